@@ -0,0 +1,29 @@
+// Package apiv1 — типы ответов API v1 (/api/v1). Поля и теги json — контракт с клиентами:
+// в v1 их можно только добавлять. Переименование, удаление и смена типа поля — это новая
+// версия: пакет apiv2 и подроутер /api/v2 рядом с v1.
+//
+// Обработчики не отдают типы домена напрямую, а переводят их в типы этого пакета
+// функциями New*: так изменения в домене и БД не попадают в ответы незаметно.
+package apiv1
+
+// OK — ответ операций без данных
+type OK struct {
+	OK bool `json:"ok"`
+}
+
+// ID — ответ создания: идентификатор новой записи
+type ID struct {
+	ID uint64 `json:"id"`
+}
+
+// mapAll переводит срез типов домена в срез DTO; nil остаётся nil (в JSON — null, как раньше)
+func mapAll[D, T any](items []D, conv func(D) T) []T {
+	if items == nil {
+		return nil
+	}
+	out := make([]T, len(items))
+	for i, item := range items {
+		out[i] = conv(item)
+	}
+	return out
+}
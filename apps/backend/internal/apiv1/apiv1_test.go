@@ -0,0 +1,107 @@
+package apiv1
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"bookinghub-backend/internal/domain"
+)
+
+// Ответы v1 совпадают по JSON с типами домена, которые обработчики отдавали раньше:
+// устаревшие адреса /api и старые клиенты получают то же, что и до появления v1
+func sameJSON(t *testing.T, name string, old, dto any) {
+	t.Helper()
+	a, err := json.Marshal(old)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	b, err := json.Marshal(dto)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	var x, y any
+	_ = json.Unmarshal(a, &x)
+	_ = json.Unmarshal(b, &y)
+	if ja, jb := mustJSON(x), mustJSON(y); ja != jb {
+		t.Errorf("%s:\nдомен: %s\nv1:    %s", name, ja, jb)
+	}
+}
+
+func mustJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func TestDTOs_MatchDomainJSON(t *testing.T) {
+	now := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	id := uint64(3)
+	text := "текст"
+	hours := 4
+	lat, lng, dist := 55.75, 37.62, 1.5
+	status := domain.BookingPending
+
+	img := domain.ResourceImage{ID: 1, ResourceID: 2, StorageKey: "k", ThumbKey: "t", URL: "/u/k", ThumbnailURL: "/u/t",
+		ContentType: "image/jpeg", SizeBytes: 10, Width: 4, Height: 3, Position: 1, IsCover: true, CreatedAt: now}
+	res := domain.Resource{
+		ID: 2, OwnerUserID: 5, OrganizationID: &id, CategoryID: 7, Title: "Зал", Description: &text, Location: &text,
+		PricePerHour: 100, Timezone: "Europe/Moscow", IsActive: true, CreatedAt: now,
+		BookingRules:  domain.BookingRules{Capacity: 2, BufferBeforeMin: 10, BufferAfterMin: 15},
+		ApprovalRules: domain.ApprovalRules{Mode: domain.ApprovalConditional, IfVerified: true, MaxHours: &hours},
+		Address:       domain.Address{City: &text, Latitude: &lat, Longitude: &lng},
+		DistanceKm:    &dist,
+		Images:        []domain.ResourceImage{img},
+		Attributes:    map[string]any{"capacity": 12},
+	}
+	sameJSON(t, "Resource", res, NewResource(res))
+	sameJSON(t, "Resource без фото", domain.Resource{}, NewResource(domain.Resource{}))
+
+	b := domain.Booking{ID: 1, ResourceID: 2, UserID: 3, StartAt: now, EndAt: now.Add(time.Hour), Quantity: 2,
+		Status: domain.BookingApproved, ManagerComment: &text, CreatedAt: now, UpdatedAt: &now, GroupID: &id,
+		BufferStartAt: &now, BufferEndAt: &now}
+	sameJSON(t, "Booking", b, NewBooking(b))
+	g := domain.BookingGroup{ID: 1, UserID: 3, Status: domain.BookingPending, CreatedAt: now, Bookings: []domain.Booking{b}}
+	sameJSON(t, "BookingGroup", g, NewBookingGroup(g))
+	hist := []domain.BookingStatusChange{{ID: 1, BookingID: 2, FromStatus: &status, ToStatus: domain.BookingApproved, ChangedBy: &id, Comment: &text, CreatedAt: now}}
+	sameJSON(t, "BookingStatusChange", hist, NewBookingHistory(hist))
+	hold := domain.BookingHold{ID: 1, Token: "tok", ResourceID: 2, UserID: 3, StartAt: now, EndAt: now, Quantity: 1, ExpiresAt: now, CreatedAt: now}
+	sameJSON(t, "Hold", hold, NewHold(hold))
+	wl := []domain.WaitlistEntry{{ID: 1, ResourceID: 2, UserID: 3, StartAt: now, EndAt: now, Quantity: 1, AutoBook: true,
+		Status: domain.WaitlistOffered, ClaimExpiresAt: &now, BookingID: &id, CreatedAt: now}}
+	sameJSON(t, "WaitlistEntry", wl, NewWaitlist(wl))
+
+	cat := domain.Category{ID: 1, ParentID: &id, Name: "Залы", ArchivedAt: &now, CreatedAt: now}
+	tree := []domain.CategoryNode{{Category: cat, Children: []domain.CategoryNode{{Category: cat}}}}
+	sameJSON(t, "CategoryNode", tree, NewCategoryTree(tree))
+	attr := domain.CategoryAttribute{ID: 1, CategoryID: 2, Code: "mount", Name: "Байонет", Type: domain.AttrEnum,
+		Required: true, Options: domain.StringList{"EF", "RF"}, Position: 1, CreatedAt: now}
+	sameJSON(t, "CategoryAttribute", attr, NewCategoryAttribute(attr))
+	merge := domain.CategoryMergeResult{MovedResources: 3, MovedChildren: 1, RemappedAttributes: 2}
+	sameJSON(t, "CategoryMerge", merge, NewCategoryMerge(merge))
+
+	bundle := domain.ResourceBundle{ID: 1, OwnerUserID: 2, Title: "Набор", Description: &text, IsActive: true, CreatedAt: now,
+		Items: []domain.BundleItem{{BundleID: 1, ResourceID: 2, Quantity: 3}}}
+	sameJSON(t, "Bundle", bundle, NewBundle(bundle))
+	hoursList := []domain.OpeningHours{{Weekday: 1, OpensAt: "09:00", ClosesAt: "24:00"}}
+	sameJSON(t, "OpeningHours", hoursList, NewOpeningHours(hoursList))
+
+	orgs := []domain.OrganizationWithRole{{Organization: domain.Organization{ID: 1, Name: "ООО", CreatedAt: now}, MyRole: domain.OrgRoleOwner}}
+	sameJSON(t, "Organization", orgs, NewOrganizations(orgs))
+	members := []domain.OrgMember{{OrganizationID: 1, UserID: 2, Email: "a@b.c", Name: "A", Role: domain.OrgRoleViewer, CreatedAt: now}}
+	sameJSON(t, "OrgMember", members, NewOrgMembers(members))
+	invites := []domain.OrgInvite{{ID: 1, OrganizationID: 2, Email: "a@b.c", Role: domain.OrgRoleManager, Token: "t",
+		InvitedBy: 3, Status: domain.InvitePending, ExpiresAt: now, CreatedAt: now}}
+	sameJSON(t, "OrgInvite", invites, NewOrgInvites(invites))
+
+	admins := []domain.AdminUser{{ID: 1, Email: "a@b.c", Name: "A", Role: domain.RoleAdmin, CreatedAt: now, SuspendedAt: &now, SuspendReason: &text, VerifiedAt: &now}}
+	sameJSON(t, "AdminUser", admins, mapAll(admins, NewAdminUser))
+}
+
+func TestMapAll_KeepsNil(t *testing.T) {
+	if NewBookings(nil) != nil {
+		t.Fatal("nil slice must stay nil")
+	}
+	if got := NewBookings([]domain.Booking{}); got == nil || len(got) != 0 {
+		t.Fatalf("empty slice must stay empty, got %#v", got)
+	}
+}
@@ -0,0 +1,137 @@
+package apiv1
+
+import (
+	"time"
+
+	"bookinghub-backend/internal/domain"
+)
+
+// Booking — бронь; BufferStartAt/BufferEndAt — границы с буферами, только в календаре ресурса
+type Booking struct {
+	ID             uint64               `json:"id"`
+	ResourceID     uint64               `json:"resourceId"`
+	UserID         uint64               `json:"userId"`
+	StartAt        time.Time            `json:"startAt"`
+	EndAt          time.Time            `json:"endAt"`
+	Quantity       int                  `json:"quantity"`
+	Status         domain.BookingStatus `json:"status"`
+	ManagerComment *string              `json:"managerComment"`
+	CreatedAt      time.Time            `json:"createdAt"`
+	UpdatedAt      *time.Time           `json:"updatedAt"`
+	GroupID        *uint64              `json:"groupId,omitempty"`
+	BundleID       *uint64              `json:"bundleId,omitempty"`
+	BufferStartAt  *time.Time           `json:"bufferStartAt,omitempty"`
+	BufferEndAt    *time.Time           `json:"bufferEndAt,omitempty"`
+}
+
+func NewBooking(b domain.Booking) Booking {
+	return Booking{
+		ID: b.ID, ResourceID: b.ResourceID, UserID: b.UserID, StartAt: b.StartAt, EndAt: b.EndAt,
+		Quantity: b.Quantity, Status: b.Status, ManagerComment: b.ManagerComment,
+		CreatedAt: b.CreatedAt, UpdatedAt: b.UpdatedAt, GroupID: b.GroupID, BundleID: b.BundleID,
+		BufferStartAt: b.BufferStartAt, BufferEndAt: b.BufferEndAt,
+	}
+}
+
+func NewBookings(items []domain.Booking) []Booking {
+	return mapAll(items, NewBooking)
+}
+
+// BookingCreated — созданная бронь: PENDING или сразу APPROVED по режиму ресурса
+type BookingCreated struct {
+	ID     uint64               `json:"id"`
+	Status domain.BookingStatus `json:"status"`
+}
+
+// BookingStatusChange — смена статуса брони
+type BookingStatusChange struct {
+	ID              uint64                `json:"id"`
+	BookingID       uint64                `json:"bookingId"`
+	FromStatus      *domain.BookingStatus `json:"fromStatus"`
+	ToStatus        domain.BookingStatus  `json:"toStatus"`
+	ChangedByUserID *uint64               `json:"changedByUserId"`
+	Comment         *string               `json:"comment"`
+	CreatedAt       time.Time             `json:"createdAt"`
+}
+
+func NewBookingHistory(items []domain.BookingStatusChange) []BookingStatusChange {
+	return mapAll(items, func(c domain.BookingStatusChange) BookingStatusChange {
+		return BookingStatusChange{
+			ID: c.ID, BookingID: c.BookingID, FromStatus: c.FromStatus, ToStatus: c.ToStatus,
+			ChangedByUserID: c.ChangedBy, Comment: c.Comment, CreatedAt: c.CreatedAt,
+		}
+	})
+}
+
+// BookingGroup — групповая бронь и её части
+type BookingGroup struct {
+	ID        uint64               `json:"id"`
+	UserID    uint64               `json:"userId"`
+	Status    domain.BookingStatus `json:"status"`
+	CreatedAt time.Time            `json:"createdAt"`
+	UpdatedAt *time.Time           `json:"updatedAt"`
+	Bookings  []Booking            `json:"bookings"`
+}
+
+func NewBookingGroup(g domain.BookingGroup) BookingGroup {
+	return BookingGroup{ID: g.ID, UserID: g.UserID, Status: g.Status, CreatedAt: g.CreatedAt, UpdatedAt: g.UpdatedAt, Bookings: NewBookings(g.Bookings)}
+}
+
+// BookingGroupCreated — созданная групповая бронь, ID её частей и статус группы
+// (APPROVED, если автоподтверждение прошли все части)
+type BookingGroupCreated struct {
+	ID         uint64               `json:"id"`
+	BookingIDs []uint64             `json:"bookingIds"`
+	Status     domain.BookingStatus `json:"status"`
+}
+
+// Hold — удержание интервала; по Token оформляется бронь до ExpiresAt
+type Hold struct {
+	ID         uint64    `json:"id"`
+	Token      string    `json:"token"`
+	ResourceID uint64    `json:"resourceId"`
+	UserID     uint64    `json:"userId"`
+	StartAt    time.Time `json:"startAt"`
+	EndAt      time.Time `json:"endAt"`
+	Quantity   int       `json:"quantity"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func NewHold(h domain.BookingHold) Hold {
+	return Hold{
+		ID: h.ID, Token: h.Token, ResourceID: h.ResourceID, UserID: h.UserID, StartAt: h.StartAt,
+		EndAt: h.EndAt, Quantity: h.Quantity, ExpiresAt: h.ExpiresAt, CreatedAt: h.CreatedAt,
+	}
+}
+
+// WaitlistEntry — заявка в очереди на занятое время
+type WaitlistEntry struct {
+	ID             uint64                `json:"id"`
+	ResourceID     uint64                `json:"resourceId"`
+	UserID         uint64                `json:"userId"`
+	StartAt        time.Time             `json:"startAt"`
+	EndAt          time.Time             `json:"endAt"`
+	Quantity       int                   `json:"quantity"`
+	AutoBook       bool                  `json:"autoBook"`
+	Status         domain.WaitlistStatus `json:"status"`
+	ClaimExpiresAt *time.Time            `json:"claimExpiresAt"`
+	BookingID      *uint64               `json:"bookingId"`
+	CreatedAt      time.Time             `json:"createdAt"`
+	UpdatedAt      *time.Time            `json:"updatedAt"`
+}
+
+func NewWaitlist(items []domain.WaitlistEntry) []WaitlistEntry {
+	return mapAll(items, func(e domain.WaitlistEntry) WaitlistEntry {
+		return WaitlistEntry{
+			ID: e.ID, ResourceID: e.ResourceID, UserID: e.UserID, StartAt: e.StartAt, EndAt: e.EndAt,
+			Quantity: e.Quantity, AutoBook: e.AutoBook, Status: e.Status, ClaimExpiresAt: e.ClaimExpiresAt,
+			BookingID: e.BookingID, CreatedAt: e.CreatedAt, UpdatedAt: e.UpdatedAt,
+		}
+	})
+}
+
+// WaitlistClaimed — бронь, созданная из предложения очереди
+type WaitlistClaimed struct {
+	BookingID uint64 `json:"bookingId"`
+}
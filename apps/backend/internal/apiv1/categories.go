@@ -0,0 +1,94 @@
+package apiv1
+
+import (
+	"time"
+
+	"bookinghub-backend/internal/domain"
+)
+
+// Category — категория каталога; ArchivedAt — скрыта из каталога
+type Category struct {
+	ID         uint64     `json:"id"`
+	ParentID   *uint64    `json:"parentId"`
+	Name       string     `json:"name"`
+	ArchivedAt *time.Time `json:"archivedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+func NewCategory(c domain.Category) Category {
+	return Category{ID: c.ID, ParentID: c.ParentID, Name: c.Name, ArchivedAt: c.ArchivedAt, CreatedAt: c.CreatedAt}
+}
+
+func NewCategories(items []domain.Category) []Category {
+	return mapAll(items, NewCategory)
+}
+
+// CategoryNode — категория в дереве вместе с подкатегориями
+type CategoryNode struct {
+	Category
+	Children []CategoryNode `json:"children"`
+}
+
+func NewCategoryNode(n domain.CategoryNode) CategoryNode {
+	return CategoryNode{Category: NewCategory(n.Category), Children: NewCategoryTree(n.Children)}
+}
+
+func NewCategoryTree(items []domain.CategoryNode) []CategoryNode {
+	return mapAll(items, NewCategoryNode)
+}
+
+// CategoryAttribute — типизированное поле объявлений категории
+type CategoryAttribute struct {
+	ID         uint64               `json:"id"`
+	CategoryID uint64               `json:"categoryId"`
+	Code       string               `json:"code"`
+	Name       string               `json:"name"`
+	Type       domain.AttributeType `json:"type"`
+	Required   bool                 `json:"required"`
+	Options    []string             `json:"options"`
+	Position   int                  `json:"position"`
+	CreatedAt  time.Time            `json:"createdAt"`
+}
+
+func NewCategoryAttribute(a domain.CategoryAttribute) CategoryAttribute {
+	return CategoryAttribute{
+		ID: a.ID, CategoryID: a.CategoryID, Code: a.Code, Name: a.Name, Type: a.Type,
+		Required: a.Required, Options: a.Options, Position: a.Position, CreatedAt: a.CreatedAt,
+	}
+}
+
+func NewCategoryAttributes(items []domain.CategoryAttribute) []CategoryAttribute {
+	return mapAll(items, NewCategoryAttribute)
+}
+
+// CategoryDetails — категория с хлебными крошками, подкатегориями и атрибутами
+type CategoryDetails struct {
+	Category    Category            `json:"category"`
+	Breadcrumbs []Category          `json:"breadcrumbs"`
+	Children    []Category          `json:"children"`
+	Attributes  []CategoryAttribute `json:"attributes"`
+}
+
+// CategoryArchive — ответ архивации; ArchivedAt nil — категория возвращена из архива
+type CategoryArchive struct {
+	OK         bool       `json:"ok"`
+	ArchivedAt *time.Time `json:"archivedAt"`
+}
+
+// CategoryMerge — что перенесено при слиянии категории в другую
+type CategoryMerge struct {
+	MovedResources     int64 `json:"movedResources"`
+	MovedChildren      int64 `json:"movedChildren"`
+	RemappedAttributes int   `json:"remappedAttributes"`
+}
+
+func NewCategoryMerge(m domain.CategoryMergeResult) CategoryMerge {
+	return CategoryMerge{MovedResources: m.MovedResources, MovedChildren: m.MovedChildren, RemappedAttributes: m.RemappedAttributes}
+}
+
+// CategoryNotEmpty — 409 при удалении непустой категории; формат старше общего формата ошибок
+type CategoryNotEmpty struct {
+	Error         string `json:"error"`
+	ResourceCount int    `json:"resourceCount"`
+	ChildCount    int    `json:"childCount"`
+}
@@ -0,0 +1,72 @@
+package apiv1
+
+import (
+	"time"
+
+	"bookinghub-backend/internal/domain"
+)
+
+// Organization — организация и роль текущего пользователя в ней
+type Organization struct {
+	ID        uint64         `json:"id"`
+	Name      string         `json:"name"`
+	CreatedAt time.Time      `json:"createdAt"`
+	MyRole    domain.OrgRole `json:"myRole"`
+}
+
+func NewOrganizations(items []domain.OrganizationWithRole) []Organization {
+	return mapAll(items, func(o domain.OrganizationWithRole) Organization {
+		return Organization{ID: o.ID, Name: o.Name, CreatedAt: o.CreatedAt, MyRole: o.MyRole}
+	})
+}
+
+// OrgMember — участник организации
+type OrgMember struct {
+	OrganizationID uint64         `json:"organizationId"`
+	UserID         uint64         `json:"userId"`
+	Email          string         `json:"email"`
+	Name           string         `json:"name"`
+	Role           domain.OrgRole `json:"role"`
+	CreatedAt      time.Time      `json:"createdAt"`
+}
+
+func NewOrgMembers(items []domain.OrgMember) []OrgMember {
+	return mapAll(items, func(m domain.OrgMember) OrgMember {
+		return OrgMember{OrganizationID: m.OrganizationID, UserID: m.UserID, Email: m.Email, Name: m.Name, Role: m.Role, CreatedAt: m.CreatedAt}
+	})
+}
+
+// OrgInvite — приглашение в организацию
+type OrgInvite struct {
+	ID             uint64              `json:"id"`
+	OrganizationID uint64              `json:"organizationId"`
+	Email          string              `json:"email"`
+	Role           domain.OrgRole      `json:"role"`
+	Token          string              `json:"token"`
+	InvitedBy      uint64              `json:"invitedBy"`
+	Status         domain.InviteStatus `json:"status"`
+	ExpiresAt      time.Time           `json:"expiresAt"`
+	CreatedAt      time.Time           `json:"createdAt"`
+}
+
+func NewOrgInvites(items []domain.OrgInvite) []OrgInvite {
+	return mapAll(items, func(i domain.OrgInvite) OrgInvite {
+		return OrgInvite{
+			ID: i.ID, OrganizationID: i.OrganizationID, Email: i.Email, Role: i.Role, Token: i.Token,
+			InvitedBy: i.InvitedBy, Status: i.Status, ExpiresAt: i.ExpiresAt, CreatedAt: i.CreatedAt,
+		}
+	})
+}
+
+// InviteCreated — новое приглашение: токен для ссылки и срок действия
+type InviteCreated struct {
+	ID        uint64    `json:"id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// InviteAccepted — приглашение принято, пользователь вступил в организацию
+type InviteAccepted struct {
+	OK             bool   `json:"ok"`
+	OrganizationID uint64 `json:"organizationId"`
+}
@@ -0,0 +1,167 @@
+package apiv1
+
+import (
+	"time"
+
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/service"
+)
+
+// Resource — объявление
+type Resource struct {
+	ID                  uint64         `json:"id"`
+	OwnerUserID         uint64         `json:"ownerUserId"`
+	OrganizationID      *uint64        `json:"organizationId"`
+	CategoryID          uint64         `json:"categoryId"`
+	Title               string         `json:"title"`
+	Description         *string        `json:"description"`
+	Location            *string        `json:"location"`
+	PricePerHour        int            `json:"pricePerHour"`
+	Timezone            string         `json:"timezone"`
+	IsActive            bool           `json:"isActive"`
+	CreatedAt           time.Time      `json:"createdAt"`
+	Capacity            int            `json:"capacity"`
+	BufferBeforeMinutes int            `json:"bufferBeforeMinutes"`
+	BufferAfterMinutes  int            `json:"bufferAfterMinutes"`
+	Approval            Approval       `json:"approval"`
+	Address             Address        `json:"address"`
+	DistanceKm          *float64       `json:"distanceKm,omitempty"` // только при поиске по радиусу
+	Images              []Image        `json:"images"`
+	Attributes          map[string]any `json:"attributes"`
+}
+
+func NewResource(r domain.Resource) Resource {
+	return Resource{
+		ID: r.ID, OwnerUserID: r.OwnerUserID, OrganizationID: r.OrganizationID, CategoryID: r.CategoryID,
+		Title: r.Title, Description: r.Description, Location: r.Location, PricePerHour: r.PricePerHour,
+		Timezone: r.Timezone, IsActive: r.IsActive, CreatedAt: r.CreatedAt,
+		Capacity: r.Capacity, BufferBeforeMinutes: r.BufferBeforeMin, BufferAfterMinutes: r.BufferAfterMin,
+		Approval:   NewApproval(r.ApprovalRules),
+		Address:    NewAddress(r.Address),
+		DistanceKm: r.DistanceKm,
+		Images:     mapAll(r.Images, NewImage),
+		Attributes: r.Attributes,
+	}
+}
+
+func NewResources(items []domain.Resource) []Resource {
+	return mapAll(items, NewResource)
+}
+
+// Approval — режим подтверждения броней ресурса
+type Approval struct {
+	Mode            domain.ApprovalMode `json:"mode"`
+	IfVerified      bool                `json:"ifVerified"`
+	IfReturning     bool                `json:"ifReturning"`
+	MaxHours        *int                `json:"maxHours"`
+	InBusinessHours bool                `json:"inBusinessHours"`
+}
+
+func NewApproval(a domain.ApprovalRules) Approval {
+	return Approval{Mode: a.Mode, IfVerified: a.IfVerified, IfReturning: a.IfReturning, MaxHours: a.MaxHours, InBusinessHours: a.InBusinessHours}
+}
+
+// Address — адрес и координаты объявления
+type Address struct {
+	Line       *string  `json:"line"`
+	City       *string  `json:"city"`
+	PostalCode *string  `json:"postalCode"`
+	Country    *string  `json:"country"`
+	Latitude   *float64 `json:"latitude"`
+	Longitude  *float64 `json:"longitude"`
+}
+
+func NewAddress(a domain.Address) Address {
+	return Address{Line: a.Line, City: a.City, PostalCode: a.PostalCode, Country: a.Country, Latitude: a.Latitude, Longitude: a.Longitude}
+}
+
+// Image — фото объявления
+type Image struct {
+	ID           uint64    `json:"id"`
+	ResourceID   uint64    `json:"resourceId"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnailUrl"`
+	ContentType  string    `json:"contentType"`
+	SizeBytes    int64     `json:"sizeBytes"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	Position     int       `json:"position"`
+	IsCover      bool      `json:"isCover"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+func NewImage(img domain.ResourceImage) Image {
+	return Image{
+		ID: img.ID, ResourceID: img.ResourceID, URL: img.URL, ThumbnailURL: img.ThumbnailURL,
+		ContentType: img.ContentType, SizeBytes: img.SizeBytes, Width: img.Width, Height: img.Height,
+		Position: img.Position, IsCover: img.IsCover, CreatedAt: img.CreatedAt,
+	}
+}
+
+func NewImages(items []domain.ResourceImage) []Image {
+	return mapAll(items, NewImage)
+}
+
+// OpeningHours — интервал работы в день недели (1 = понедельник ... 7 = воскресенье)
+type OpeningHours struct {
+	Weekday  int    `json:"weekday"`
+	OpensAt  string `json:"opensAt"`
+	ClosesAt string `json:"closesAt"`
+}
+
+func NewOpeningHours(items []domain.OpeningHours) []OpeningHours {
+	return mapAll(items, func(h domain.OpeningHours) OpeningHours {
+		return OpeningHours{Weekday: h.Weekday, OpensAt: h.OpensAt, ClosesAt: h.ClosesAt}
+	})
+}
+
+// Availability — свободные единицы ресурса на интервал
+type Availability struct {
+	ResourceID          uint64    `json:"resourceId"`
+	StartAt             time.Time `json:"startAt"`
+	EndAt               time.Time `json:"endAt"`
+	Capacity            int       `json:"capacity"`
+	BufferBeforeMinutes int       `json:"bufferBeforeMinutes"`
+	BufferAfterMinutes  int       `json:"bufferAfterMinutes"`
+	BookedUnits         int       `json:"bookedUnits"`
+	RemainingUnits      int       `json:"remainingUnits"`
+}
+
+// Occupancy — загрузка ресурса за период
+type Occupancy struct {
+	ResourceID     uint64             `json:"resourceId"`
+	IncludePending bool               `json:"includePending"`
+	Occupancy      *service.Occupancy `json:"occupancy"`
+}
+
+// Bundle — набор ресурсов, который бронируется как одна позиция
+type Bundle struct {
+	ID             uint64       `json:"id"`
+	OwnerUserID    uint64       `json:"ownerUserId"`
+	OrganizationID *uint64      `json:"organizationId"`
+	Title          string       `json:"title"`
+	Description    *string      `json:"description"`
+	IsActive       bool         `json:"isActive"`
+	CreatedAt      time.Time    `json:"createdAt"`
+	Items          []BundleItem `json:"items"`
+}
+
+// BundleItem — ресурс в наборе и сколько его единиц занимает бронь набора
+type BundleItem struct {
+	ResourceID uint64 `json:"resourceId"`
+	Quantity   int    `json:"quantity"`
+}
+
+func NewBundle(b domain.ResourceBundle) Bundle {
+	return Bundle{
+		ID: b.ID, OwnerUserID: b.OwnerUserID, OrganizationID: b.OrganizationID, Title: b.Title,
+		Description: b.Description, IsActive: b.IsActive, CreatedAt: b.CreatedAt,
+		Items: mapAll(b.Items, func(it domain.BundleItem) BundleItem {
+			return BundleItem{ResourceID: it.ResourceID, Quantity: it.Quantity}
+		}),
+	}
+}
+
+func NewBundles(items []domain.ResourceBundle) []Bundle {
+	return mapAll(items, NewBundle)
+}
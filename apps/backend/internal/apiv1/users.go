@@ -0,0 +1,142 @@
+package apiv1
+
+import (
+	"time"
+
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/service"
+)
+
+// AuthUser — пользователь в ответе входа и регистрации
+type AuthUser struct {
+	ID    uint64          `json:"id"`
+	Email string          `json:"email"`
+	Name  string          `json:"name"`
+	Role  domain.UserRole `json:"role"`
+}
+
+// Auth — ответ входа и регистрации. RecoveryCodes — только после входа, которым завершено
+// обязательное подключение 2FA: коды восстановления показываются один раз.
+type Auth struct {
+	AccessToken   string   `json:"accessToken"`
+	User          AuthUser `json:"user"`
+	RecoveryCodes []string `json:"recoveryCodes,omitempty"`
+}
+
+// LoginChallenge — ответ входа, когда нужен второй фактор: токен доступа выдаст
+// POST /auth/2fa/verify в обмен на ChallengeToken и код. EnrollmentRequired — 2FA для роли
+// обязательна, но не подключена: Enrollment — секрет для аутентификатора, код подтверждает подключение.
+type LoginChallenge struct {
+	ChallengeToken     string               `json:"challengeToken"`
+	ExpiresAt          time.Time            `json:"expiresAt"`
+	EnrollmentRequired bool                 `json:"enrollmentRequired"`
+	Enrollment         *TwoFactorEnrollment `json:"enrollment,omitempty"`
+}
+
+// SSOAuthorization — начало входа через корпоративного провайдера: клиент запоминает State
+// и отправляет пользователя на AuthorizationURL; провайдер вернёт его на адрес возврата с code и тем же state
+type SSOAuthorization struct {
+	AuthorizationURL string    `json:"authorizationUrl"`
+	State            string    `json:"state"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+}
+
+// TwoFactorEnrollment — секрет TOTP и ссылка otpauth:// для QR-кода
+type TwoFactorEnrollment struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauthUri"`
+}
+
+func NewTwoFactorEnrollment(e service.TwoFactorEnrollment) TwoFactorEnrollment {
+	return TwoFactorEnrollment{Secret: e.Secret, OtpauthURI: e.URI}
+}
+
+// TwoFactorStatus — состояние 2FA текущего пользователя
+type TwoFactorStatus struct {
+	Enabled           bool `json:"enabled"`
+	Required          bool `json:"required"`
+	RecoveryCodesLeft int  `json:"recoveryCodesLeft"`
+}
+
+func NewTwoFactorStatus(s service.TwoFactorStatus) TwoFactorStatus {
+	return TwoFactorStatus{Enabled: s.Enabled, Required: s.Required, RecoveryCodesLeft: s.RecoveryCodesLeft}
+}
+
+// RecoveryCodes — новые коды восстановления; показываются один раз
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// Profile — профиль текущего пользователя; Locale nil — язык по Accept-Language
+type Profile struct {
+	ID     uint64          `json:"id"`
+	Email  string          `json:"email"`
+	Name   string          `json:"name"`
+	Role   domain.UserRole `json:"role"`
+	Locale *string         `json:"locale"`
+}
+
+func NewProfile(u domain.User) Profile {
+	return Profile{ID: u.ID, Email: u.Email, Name: u.Name, Role: u.Role, Locale: u.Locale}
+}
+
+// PublicUser — публичный профиль пользователя
+type PublicUser struct {
+	ID        uint64          `json:"id"`
+	Name      string          `json:"name"`
+	Role      domain.UserRole `json:"role"`
+	Email     string          `json:"email"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+func NewPublicUser(u domain.User) PublicUser {
+	return PublicUser{ID: u.ID, Name: u.Name, Role: u.Role, Email: u.Email, CreatedAt: u.CreatedAt}
+}
+
+// AdminUser — пользователь в списке админки
+type AdminUser struct {
+	ID            uint64          `json:"id"`
+	Email         string          `json:"email"`
+	Name          string          `json:"name"`
+	Role          domain.UserRole `json:"role"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	SuspendedAt   *time.Time      `json:"suspendedAt"`
+	SuspendReason *string         `json:"suspendReason"`
+	VerifiedAt    *time.Time      `json:"verifiedAt"`
+}
+
+func NewAdminUser(u domain.AdminUser) AdminUser {
+	return AdminUser{
+		ID: u.ID, Email: u.Email, Name: u.Name, Role: u.Role, CreatedAt: u.CreatedAt,
+		SuspendedAt: u.SuspendedAt, SuspendReason: u.SuspendReason, VerifiedAt: u.VerifiedAt,
+	}
+}
+
+// AdminUsersPage — страница списка пользователей
+type AdminUsersPage struct {
+	Items    []AdminUser `json:"items"`
+	Total    int         `json:"total"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"pageSize"`
+}
+
+func NewAdminUsersPage(items []domain.AdminUser, total, page, pageSize int) AdminUsersPage {
+	return AdminUsersPage{Items: mapAll(items, NewAdminUser), Total: total, Page: page, PageSize: pageSize}
+}
+
+// TemporaryPassword — временный пароль, выданный администратором
+type TemporaryPassword struct {
+	TemporaryPassword string `json:"temporaryPassword"`
+}
+
+// Permissions — известные права и матрица ролей
+type Permissions struct {
+	Permissions []domain.Permission                     `json:"permissions"`
+	Roles       map[domain.UserRole][]domain.Permission `json:"roles"`
+}
+
+// RolePermissions — права одной роли
+type RolePermissions struct {
+	Role        domain.UserRole     `json:"role"`
+	Permissions []domain.Permission `json:"permissions"`
+}
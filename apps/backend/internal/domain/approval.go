@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// ApprovalMode — как подтверждаются новые брони ресурса
+type ApprovalMode string
+
+const (
+	ApprovalManual      ApprovalMode = "MANUAL"      // владелец подтверждает каждую бронь сам
+	ApprovalAuto        ApprovalMode = "AUTO"        // все брони подтверждаются сразу
+	ApprovalConditional ApprovalMode = "CONDITIONAL" // сразу — если выполнено хотя бы одно условие
+)
+
+// MaxAutoApproveHours — верхняя граница условия «бронь короче N часов»
+const MaxAutoApproveHours = 168
+
+// ApprovalRules — режим подтверждения и условия автоподтверждения ресурса
+type ApprovalRules struct {
+	Mode ApprovalMode `json:"mode" db:"approval_mode"`
+	// условия режима CONDITIONAL, достаточно любого из включённых
+	IfVerified      bool `json:"ifVerified" db:"auto_approve_verified"`            // арендатор проверен администратором
+	IfReturning     bool `json:"ifReturning" db:"auto_approve_returning"`          // у арендатора есть завершённые брони
+	MaxHours        *int `json:"maxHours" db:"auto_approve_max_hours"`             // бронь не длиннее MaxHours часов
+	InBusinessHours bool `json:"inBusinessHours" db:"auto_approve_business_hours"` // бронь целиком в часах работы ресурса
+}
+
+// Valid — известный режим и разумный MaxHours
+func (r ApprovalRules) Valid() bool {
+	switch r.Mode {
+	case ApprovalManual, ApprovalAuto, ApprovalConditional:
+	default:
+		return false
+	}
+	return r.MaxHours == nil || (*r.MaxHours >= 1 && *r.MaxHours <= MaxAutoApproveHours)
+}
+
+// RenterStats — то, что известно об арендаторе для условий автоподтверждения
+type RenterStats struct {
+	Verified          bool `db:"verified"`
+	CompletedBookings int  `db:"completed_bookings"`
+}
+
+// BookingStatusChange — запись истории статусов брони.
+// ChangedBy = nil — статус изменила система (например, автоподтверждение).
+type BookingStatusChange struct {
+	ID         uint64         `json:"id" db:"id"`
+	BookingID  uint64         `json:"bookingId" db:"booking_id"`
+	FromStatus *BookingStatus `json:"fromStatus" db:"from_status"`
+	ToStatus   BookingStatus  `json:"toStatus" db:"to_status"`
+	ChangedBy  *uint64        `json:"changedByUserId" db:"changed_by_user_id"`
+	Comment    *string        `json:"comment" db:"comment"`
+	CreatedAt  time.Time      `json:"createdAt" db:"created_at"`
+}
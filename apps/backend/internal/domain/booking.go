@@ -17,8 +17,73 @@ type Booking struct {
 	UserID         uint64        `json:"userId" db:"user_id"`
 	StartAt        time.Time     `json:"startAt" db:"start_at"`
 	EndAt          time.Time     `json:"endAt" db:"end_at"`
+	Quantity       int           `json:"quantity" db:"quantity"` // сколько единиц ресурса занимает бронь
 	Status         BookingStatus `json:"status" db:"status"`
 	ManagerComment *string       `json:"managerComment" db:"manager_comment"`
 	CreatedAt      time.Time     `json:"createdAt" db:"created_at"`
 	UpdatedAt      *time.Time    `json:"updatedAt" db:"updated_at"`
+	// GroupID — групповая бронь, частью которой является эта; BundleID — набор, из которого она развёрнута
+	GroupID  *uint64 `json:"groupId,omitempty" db:"group_id"`
+	BundleID *uint64 `json:"bundleId,omitempty" db:"bundle_id"`
+
+	// BufferStartAt/BufferEndAt — границы брони вместе с буферами ресурса;
+	// заполняются только в календаре ресурса и только если буферы заданы
+	BufferStartAt *time.Time `json:"bufferStartAt,omitempty" db:"-"`
+	BufferEndAt   *time.Time `json:"bufferEndAt,omitempty" db:"-"`
+}
+
+// Units — занятые бронью единицы ресурса; старые записи без quantity занимают одну
+func (b Booking) Units() int {
+	if b.Quantity < 1 {
+		return 1
+	}
+	return b.Quantity
+}
+
+// BookingGroup — несколько броней, созданных одним запросом: либо все, либо ни одной.
+// Каждую часть подтверждает владелец её ресурса; группа подтверждена, когда подтверждены все части.
+type BookingGroup struct {
+	ID        uint64        `json:"id" db:"id"`
+	UserID    uint64        `json:"userId" db:"user_id"`
+	Status    BookingStatus `json:"status" db:"status"`
+	CreatedAt time.Time     `json:"createdAt" db:"created_at"`
+	UpdatedAt *time.Time    `json:"updatedAt" db:"updated_at"`
+
+	Bookings []Booking `json:"bookings" db:"-"`
+}
+
+// BookingItem — одна часть групповой брони до сохранения
+type BookingItem struct {
+	ResourceID uint64
+	BundleID   *uint64
+	StartAt    time.Time
+	EndAt      time.Time
+	Quantity   int
+}
+
+// GroupStatus — статус группы по статусам частей: отклонённая часть отклоняет всю группу,
+// подтверждена группа только целиком
+func GroupStatus(parts []BookingStatus) BookingStatus {
+	if len(parts) == 0 {
+		return BookingCanceled
+	}
+	approved, canceled := 0, 0
+	for _, st := range parts {
+		switch st {
+		case BookingRejected:
+			return BookingRejected
+		case BookingApproved:
+			approved++
+		case BookingCanceled:
+			canceled++
+		}
+	}
+	switch {
+	case canceled > 0:
+		return BookingCanceled
+	case approved == len(parts):
+		return BookingApproved
+	default:
+		return BookingPending
+	}
 }
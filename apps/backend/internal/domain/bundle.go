@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// ResourceBundle — набор ресурсов одного владельца (зал + проектор + звук),
+// который бронируется как одна позиция
+type ResourceBundle struct {
+	ID             uint64    `json:"id" db:"id"`
+	OwnerUserID    uint64    `json:"ownerUserId" db:"owner_user_id"`
+	OrganizationID *uint64   `json:"organizationId" db:"organization_id"`
+	Title          string    `json:"title" db:"title"`
+	Description    *string   `json:"description" db:"description"`
+	IsActive       bool      `json:"isActive" db:"is_active"`
+	CreatedAt      time.Time `json:"createdAt" db:"created_at"`
+
+	Items []BundleItem `json:"items" db:"-"`
+}
+
+// BundleItem — ресурс в наборе и сколько его единиц занимает одна бронь набора
+type BundleItem struct {
+	BundleID   uint64 `json:"-" db:"bundle_id"`
+	ResourceID uint64 `json:"resourceId" db:"resource_id"`
+	Quantity   int    `json:"quantity" db:"quantity"`
+}
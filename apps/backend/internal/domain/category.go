@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type Category struct {
+	ID       uint64  `json:"id" db:"id"`
+	ParentID *uint64 `json:"parentId" db:"parent_id"`
+	Name     string  `json:"name" db:"name"`
+	// ArchivedAt — категория скрыта из каталога, но объявления в ней продолжают работать
+	ArchivedAt *time.Time `json:"archivedAt" db:"archived_at"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+}
+
+// CategoryMergeResult — что перенесено при слиянии категории в другую
+type CategoryMergeResult struct {
+	MovedResources     int64 `json:"movedResources"`
+	MovedChildren      int64 `json:"movedChildren"`
+	RemappedAttributes int   `json:"remappedAttributes"`
+}
+
+// CategoryNode — категория в дереве вместе с подкатегориями
+type CategoryNode struct {
+	Category
+	Children []CategoryNode `json:"children"`
+}
+
+type AttributeType string
+
+const (
+	AttrInt    AttributeType = "int"
+	AttrNumber AttributeType = "number"
+	AttrString AttributeType = "string"
+	AttrBool   AttributeType = "bool"
+	AttrEnum   AttributeType = "enum" // одно значение из Options
+)
+
+func (t AttributeType) Valid() bool {
+	switch t {
+	case AttrInt, AttrNumber, AttrString, AttrBool, AttrEnum:
+		return true
+	}
+	return false
+}
+
+// Numeric — значения хранятся ещё и в value_num, по ним работают фильтры min/max
+func (t AttributeType) Numeric() bool {
+	return t == AttrInt || t == AttrNumber
+}
+
+// Decode превращает сохранённое строковое значение обратно в значение нужного типа для JSON
+func (t AttributeType) Decode(value string) any {
+	switch t {
+	case AttrInt:
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case AttrNumber:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case AttrBool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+// CategoryAttribute — типизированное поле объявлений категории, например "capacity: int".
+// Подкатегории наследуют атрибуты всех родителей.
+type CategoryAttribute struct {
+	ID         uint64        `json:"id" db:"id"`
+	CategoryID uint64        `json:"categoryId" db:"category_id"`
+	Code       string        `json:"code" db:"code"`
+	Name       string        `json:"name" db:"name"`
+	Type       AttributeType `json:"type" db:"type"`
+	Required   bool          `json:"required" db:"required"`
+	Options    StringList    `json:"options" db:"options"`
+	Position   int           `json:"position" db:"position"`
+	CreatedAt  time.Time     `json:"createdAt" db:"created_at"`
+}
+
+// AttributeValue — проверенное значение атрибута объявления, готовое к записи в БД
+type AttributeValue struct {
+	AttributeID uint64
+	Value       string
+	ValueNum    *float64
+}
+
+// StringList — список строк, который хранится в JSON-колонке
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal([]string(l))
+	return string(b), err
+}
+
+func (l *StringList) Scan(src any) error {
+	var raw []byte
+	switch v := src.(type) {
+	case nil:
+		*l = StringList{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return fmt.Errorf("StringList: неподдерживаемый тип %T", src)
+	}
+	var items []string
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return err
+	}
+	if items == nil {
+		items = []string{}
+	}
+	*l = items
+	return nil
+}
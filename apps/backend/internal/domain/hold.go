@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// BookingHold — временное удержание интервала, пока пользователь оформляет бронь.
+// До ExpiresAt занимает место так же, как бронь; по Token бронь создаётся из удержания.
+type BookingHold struct {
+	ID         uint64    `json:"id" db:"id"`
+	Token      string    `json:"token" db:"token"`
+	ResourceID uint64    `json:"resourceId" db:"resource_id"`
+	UserID     uint64    `json:"userId" db:"user_id"`
+	StartAt    time.Time `json:"startAt" db:"start_at"`
+	EndAt      time.Time `json:"endAt" db:"end_at"`
+	Quantity   int       `json:"quantity" db:"quantity"`
+	ExpiresAt  time.Time `json:"expiresAt" db:"expires_at"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+}
+
+// AsBooking — удержание в виде брони для расчёта занятости
+func (h BookingHold) AsBooking() Booking {
+	return Booking{ResourceID: h.ResourceID, UserID: h.UserID, StartAt: h.StartAt, EndAt: h.EndAt, Quantity: h.Quantity}
+}
@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+type OrgRole string
+
+const (
+	OrgRoleOwner   OrgRole = "OWNER"   // полный доступ, управление участниками
+	OrgRoleManager OrgRole = "MANAGER" // управление объявлениями и бронями
+	OrgRoleViewer  OrgRole = "VIEWER"  // только просмотр
+)
+
+// CanManage — может ли участник с этой ролью управлять объявлениями и бронями организации
+func (r OrgRole) CanManage() bool {
+	return r == OrgRoleOwner || r == OrgRoleManager
+}
+
+func (r OrgRole) Valid() bool {
+	return r == OrgRoleOwner || r == OrgRoleManager || r == OrgRoleViewer
+}
+
+type Organization struct {
+	ID        uint64    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// OrganizationWithRole — организация вместе с ролью текущего пользователя в ней
+type OrganizationWithRole struct {
+	Organization
+	MyRole OrgRole `json:"myRole" db:"my_role"`
+}
+
+type OrgMember struct {
+	OrganizationID uint64    `json:"organizationId" db:"organization_id"`
+	UserID         uint64    `json:"userId" db:"user_id"`
+	Email          string    `json:"email" db:"email"`
+	Name           string    `json:"name" db:"name"`
+	Role           OrgRole   `json:"role" db:"role"`
+	CreatedAt      time.Time `json:"createdAt" db:"created_at"`
+}
+
+type InviteStatus string
+
+const (
+	InvitePending  InviteStatus = "PENDING"
+	InviteAccepted InviteStatus = "ACCEPTED"
+	InviteDeclined InviteStatus = "DECLINED"
+)
+
+type OrgInvite struct {
+	ID             uint64       `json:"id" db:"id"`
+	OrganizationID uint64       `json:"organizationId" db:"organization_id"`
+	Email          string       `json:"email" db:"email"`
+	Role           OrgRole      `json:"role" db:"role"`
+	Token          string       `json:"token" db:"token"`
+	InvitedBy      uint64       `json:"invitedBy" db:"invited_by"`
+	Status         InviteStatus `json:"status" db:"status"`
+	ExpiresAt      time.Time    `json:"expiresAt" db:"expires_at"`
+	CreatedAt      time.Time    `json:"createdAt" db:"created_at"`
+}
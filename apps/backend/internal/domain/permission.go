@@ -0,0 +1,51 @@
+package domain
+
+// Permission — именованное право, например "booking:approve"
+type Permission string
+
+const (
+	PermBookingApprove   Permission = "booking:approve"  // подтверждать/отклонять брони
+	PermBookingViewAll   Permission = "booking:view_all" // видеть все ожидающие брони платформы
+	PermBookingCancel    Permission = "booking:cancel"   // отменять брони
+	PermResourceEdit     Permission = "resource:edit"    // редактировать объявления
+	PermCategoryManage   Permission = "category:manage"  // создавать/менять/удалять категории
+	PermOrgCreate        Permission = "organization:create"
+	PermPermissionManage Permission = "permission:manage" // менять матрицу ролей и прав
+	PermUserManage       Permission = "user:manage"       // роли, блокировки и сессии пользователей
+	PermReportViewAll    Permission = "report:view_all"   // отчёты по всей платформе
+)
+
+// AllPermissions — все известные права (для админки и валидации)
+var AllPermissions = []Permission{
+	PermBookingApprove,
+	PermBookingViewAll,
+	PermBookingCancel,
+	PermResourceEdit,
+	PermCategoryManage,
+	PermOrgCreate,
+	PermPermissionManage,
+	PermUserManage,
+	PermReportViewAll,
+}
+
+func (p Permission) Valid() bool {
+	for _, known := range AllPermissions {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+// AllRoles — роли пользователей платформы
+var AllRoles = []UserRole{RoleIndividual, RoleCompany, RoleAdmin}
+
+func (r UserRole) Valid() bool {
+	return r == RoleIndividual || r == RoleCompany || r == RoleAdmin
+}
+
+// RolePermission — строка таблицы role_permissions
+type RolePermission struct {
+	Role       UserRole   `json:"role" db:"role"`
+	Permission Permission `json:"permission" db:"permission"`
+}
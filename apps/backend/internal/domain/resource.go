@@ -1,21 +1,138 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+
+	"bookinghub-backend/internal/geo"
+)
 
 type Resource struct {
+	ID             uint64  `json:"id" db:"id"`
+	OwnerUserID    uint64  `json:"ownerUserId" db:"owner_user_id"`
+	OrganizationID *uint64 `json:"organizationId" db:"organization_id"`
+	CategoryID     uint64  `json:"categoryId" db:"category_id"`
+	Title          string  `json:"title" db:"title"`
+	Description    *string `json:"description" db:"description"`
+	Location       *string `json:"location" db:"location"`
+	PricePerHour   int     `json:"pricePerHour" db:"price_per_hour"`
+	// Timezone — часовой пояс IANA, в котором ресурс живёт: в нём трактуется время без смещения,
+	// считаются дни и часы работы
+	Timezone  string    `json:"timezone" db:"timezone"`
+	IsActive  bool      `json:"isActive" db:"is_active"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+
+	// BookingRules — вместимость и буферы, по ним проверяются пересечения броней
+	BookingRules
+	// ApprovalRules — как подтверждаются новые брони (колонки approval_mode, auto_approve_*)
+	ApprovalRules `json:"approval"`
+	// Address — структурированный адрес и координаты (колонки address_line, city, ..., longitude)
+	Address `json:"address"`
+	// DistanceKm — расстояние до точки near; заполняется только при поиске по радиусу
+	DistanceKm *float64 `json:"distanceKm,omitempty" db:"distance_km"`
+
+	// Images — фото по порядку показа; заполняется репозиторием отдельным запросом
+	Images []ResourceImage `json:"images" db:"-"`
+	// Attributes — значения атрибутов категории по коду атрибута (int/number/bool/string)
+	Attributes map[string]any `json:"attributes" db:"-"`
+}
+
+// BookingRules — настройки ресурса, от которых зависит проверка пересечений броней
+type BookingRules struct {
+	Capacity        int `json:"capacity" db:"capacity"` // сколько одинаковых единиц можно забронировать одновременно
+	BufferBeforeMin int `json:"bufferBeforeMinutes" db:"buffer_before_min"`
+	BufferAfterMin  int `json:"bufferAfterMinutes" db:"buffer_after_min"`
+}
+
+func (r BookingRules) BufferBefore() time.Duration {
+	return time.Duration(r.BufferBeforeMin) * time.Minute
+}
+
+func (r BookingRules) BufferAfter() time.Duration {
+	return time.Duration(r.BufferAfterMin) * time.Minute
+}
+
+// Blocked — сколько времени бронь [start, end) занимает вместе с буферами.
+// Арендатор видит и оплачивает только [start, end).
+func (r BookingRules) Blocked(start, end time.Time) (time.Time, time.Time) {
+	return start.Add(-r.BufferBefore()), end.Add(r.BufferAfter())
+}
+
+// Address — структурированный адрес и координаты объявления. Location остаётся свободным текстом
+// для старых клиентов.
+type Address struct {
+	Line       *string  `json:"line" db:"address_line"`
+	City       *string  `json:"city" db:"city"`
+	PostalCode *string  `json:"postalCode" db:"postal_code"`
+	Country    *string  `json:"country" db:"country"`
+	Latitude   *float64 `json:"latitude" db:"latitude"`
+	Longitude  *float64 `json:"longitude" db:"longitude"`
+}
+
+// HasCoordinates — заданы обе координаты
+func (a Address) HasCoordinates() bool {
+	return a.Latitude != nil && a.Longitude != nil
+}
+
+// Text — адрес одной строкой для геокодера
+func (a Address) Text() string {
+	var parts []string
+	for _, p := range []*string{a.Line, a.City, a.PostalCode, a.Country} {
+		if p != nil && *p != "" {
+			parts = append(parts, *p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ResourceImage — фото объявления. Ключи хранилища наружу не отдаются, только URL.
+type ResourceImage struct {
 	ID           uint64    `json:"id" db:"id"`
-	OwnerUserID  uint64    `json:"ownerUserId" db:"owner_user_id"`
-	CategoryID   uint64    `json:"categoryId" db:"category_id"`
-	Title        string    `json:"title" db:"title"`
-	Description  *string   `json:"description" db:"description"`
-	Location     *string   `json:"location" db:"location"`
-	PricePerHour int       `json:"pricePerHour" db:"price_per_hour"`
-	IsActive     bool      `json:"isActive" db:"is_active"`
+	ResourceID   uint64    `json:"resourceId" db:"resource_id"`
+	StorageKey   string    `json:"-" db:"storage_key"`
+	ThumbKey     string    `json:"-" db:"thumb_key"`
+	URL          string    `json:"url" db:"-"`
+	ThumbnailURL string    `json:"thumbnailUrl" db:"-"`
+	ContentType  string    `json:"contentType" db:"content_type"`
+	SizeBytes    int64     `json:"sizeBytes" db:"size_bytes"`
+	Width        int       `json:"width" db:"width"`
+	Height       int       `json:"height" db:"height"`
+	Position     int       `json:"position" db:"position"`
+	IsCover      bool      `json:"isCover" db:"is_cover"`
 	CreatedAt    time.Time `json:"createdAt" db:"created_at"`
 }
 
-type Category struct {
-	ID        uint64    `json:"id" db:"id"`
-	Name      string    `json:"name" db:"name"`
-	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+// OpeningHours — интервал работы ресурса в день недели (1 = понедельник ... 7 = воскресенье).
+// Время в формате "HH:MM", closesAt может быть "24:00". Нет интервалов — ресурс доступен круглосуточно.
+type OpeningHours struct {
+	Weekday  int    `json:"weekday" db:"weekday"`
+	OpensAt  string `json:"opensAt" db:"opens_at"`
+	ClosesAt string `json:"closesAt" db:"closes_at"`
+}
+
+// ResourceFilter — условия выборки для GET /api/resources; пустой фильтр — все объявления
+type ResourceFilter struct {
+	// CategoryIDs — категория вместе с подкатегориями
+	CategoryIDs []uint64
+	Attributes  []AttributeFilter
+	// Near и RadiusKm — объявления в радиусе от точки; DistanceKm заполняется у каждого
+	Near     *geo.Point
+	RadiusKm float64
+	// SortByDistance — ближайшие первыми (только вместе с Near), иначе новые первыми
+	SortByDistance bool
+	// BBox — объявления в видимой области карты
+	BBox *geo.Box
+}
+
+// AttributeFilter — условие на значение атрибута по его коду.
+// Values — совпадение с любым из значений, Min/Max — диапазон для числовых атрибутов.
+type AttributeFilter struct {
+	Code   string
+	Values []string
+	Min    *float64
+	Max    *float64
+}
+
+func (f ResourceFilter) Empty() bool {
+	return len(f.CategoryIDs) == 0 && len(f.Attributes) == 0 && f.Near == nil && f.BBox == nil
 }
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// UserTOTP — секрет TOTP пользователя. EnabledAt == nil — подключение начато, но ещё не подтверждено кодом.
+type UserTOTP struct {
+	UserID    uint64     `db:"user_id"`
+	Secret    string     `db:"secret"`
+	EnabledAt *time.Time `db:"enabled_at"`
+	LastStep  *int64     `db:"last_step"` // шаг последнего принятого кода
+}
+
+// Enabled — двухфакторная аутентификация включена
+func (t *UserTOTP) Enabled() bool {
+	return t != nil && t.EnabledAt != nil
+}
@@ -15,6 +15,28 @@ type User struct {
 	Email        string    `json:"email" db:"email"`
 	Name         string    `json:"name" db:"name"`
 	Role         UserRole  `json:"role" db:"role"`
+	Locale       *string   `json:"locale" db:"locale"` // ru, en; nil — по Accept-Language
 	PasswordHash string    `json:"-" db:"password_hash"`
 	CreatedAt    time.Time `json:"createdAt" db:"created_at"`
 }
+
+// UserAuthState — то, что AuthMiddleware сверяет с БД на каждом запросе
+type UserAuthState struct {
+	Role              UserRole   `db:"role"`
+	SuspendedAt       *time.Time `db:"suspended_at"`
+	SuspendReason     *string    `db:"suspend_reason"`
+	SessionsRevokedAt *time.Time `db:"sessions_revoked_at"`
+	Locale            *string    `db:"locale"`
+}
+
+// AdminUser — пользователь в списке админки
+type AdminUser struct {
+	ID            uint64     `json:"id" db:"id"`
+	Email         string     `json:"email" db:"email"`
+	Name          string     `json:"name" db:"name"`
+	Role          UserRole   `json:"role" db:"role"`
+	CreatedAt     time.Time  `json:"createdAt" db:"created_at"`
+	SuspendedAt   *time.Time `json:"suspendedAt" db:"suspended_at"`
+	SuspendReason *string    `json:"suspendReason" db:"suspend_reason"`
+	VerifiedAt    *time.Time `json:"verifiedAt" db:"verified_at"`
+}
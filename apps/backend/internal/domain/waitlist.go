@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+type WaitlistStatus string
+
+const (
+	WaitlistWaiting   WaitlistStatus = "WAITING"   // ждёт, пока освободится место
+	WaitlistOffered   WaitlistStatus = "OFFERED"   // место освободилось, нужно подтвердить до ClaimExpiresAt
+	WaitlistFulfilled WaitlistStatus = "FULFILLED" // по заявке создана бронь BookingID
+	WaitlistLeft      WaitlistStatus = "LEFT"      // пользователь вышел из очереди
+	WaitlistExpired   WaitlistStatus = "EXPIRED"   // интервал начался или предложение не подтверждено вовремя
+)
+
+// WaitlistEntry — заявка в очереди на занятое время ресурса
+type WaitlistEntry struct {
+	ID         uint64    `json:"id" db:"id"`
+	ResourceID uint64    `json:"resourceId" db:"resource_id"`
+	UserID     uint64    `json:"userId" db:"user_id"`
+	StartAt    time.Time `json:"startAt" db:"start_at"`
+	EndAt      time.Time `json:"endAt" db:"end_at"`
+	Quantity   int       `json:"quantity" db:"quantity"`
+	// AutoBook — сразу создать PENDING-бронь, когда место освободится; иначе — предложение с ограниченным сроком
+	AutoBook       bool           `json:"autoBook" db:"auto_book"`
+	Status         WaitlistStatus `json:"status" db:"status"`
+	ClaimExpiresAt *time.Time     `json:"claimExpiresAt" db:"claim_expires_at"`
+	BookingID      *uint64        `json:"bookingId" db:"booking_id"`
+	CreatedAt      time.Time      `json:"createdAt" db:"created_at"`
+	UpdatedAt      *time.Time     `json:"updatedAt" db:"updated_at"`
+}
+
+// AsBooking — заявка в виде брони для расчёта занятости (действующее предложение держит место)
+func (e WaitlistEntry) AsBooking() Booking {
+	return Booking{ResourceID: e.ResourceID, UserID: e.UserID, StartAt: e.StartAt, EndAt: e.EndAt, Quantity: e.Quantity}
+}
+
+// Active — заявка ещё в очереди
+func (e WaitlistEntry) Active() bool {
+	return e.Status == WaitlistWaiting || e.Status == WaitlistOffered
+}
@@ -0,0 +1,125 @@
+// Package geo — координаты объявлений: расстояния, ограничивающие прямоугольники, geohash
+// и геокодирование адресов. Только стандартная библиотека.
+package geo
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const earthRadiusKm = 6371.0
+
+var (
+	ErrInvalidPoint = errors.New("координаты должны быть в формате lat,lng (широта -90..90, долгота -180..180)")
+	ErrInvalidBox   = errors.New("bbox должен быть в формате west,south,east,north")
+)
+
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180 &&
+		!math.IsNaN(p.Lat) && !math.IsNaN(p.Lng)
+}
+
+// Box — прямоугольник на карте. MinLng > MaxLng означает, что он пересекает 180-й меридиан.
+type Box struct {
+	MinLat float64
+	MinLng float64
+	MaxLat float64
+	MaxLng float64
+}
+
+// CrossesAntimeridian — прямоугольник переходит через ±180° долготы
+func (b Box) CrossesAntimeridian() bool {
+	return b.MinLng > b.MaxLng
+}
+
+// DistanceKm — расстояние по большому кругу (формула гаверсинусов)
+func DistanceKm(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat := lat2 - lat1
+	dLng := radians(b.Lng - a.Lng)
+
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// BoundingBox — прямоугольник, внутри которого лежит круг радиуса radiusKm вокруг center.
+// Используется как грубый фильтр по индексу перед точной проверкой расстояния.
+func BoundingBox(center Point, radiusKm float64) Box {
+	dLat := degrees(radiusKm / earthRadiusKm)
+	box := Box{
+		MinLat: math.Max(-90, center.Lat-dLat),
+		MaxLat: math.Min(90, center.Lat+dLat),
+	}
+
+	// у полюса круг захватывает все долготы
+	if box.MinLat == -90 || box.MaxLat == 90 {
+		box.MinLng, box.MaxLng = -180, 180
+		return box
+	}
+	dLng := degrees(math.Asin(math.Min(1, math.Sin(radiusKm/earthRadiusKm)/math.Cos(radians(center.Lat)))))
+	if dLng >= 180 {
+		box.MinLng, box.MaxLng = -180, 180
+		return box
+	}
+	box.MinLng = normalizeLng(center.Lng - dLng)
+	box.MaxLng = normalizeLng(center.Lng + dLng)
+	return box
+}
+
+// ParsePoint разбирает "lat,lng"
+func ParsePoint(s string) (Point, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return Point{}, ErrInvalidPoint
+	}
+	lat, err1 := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	lng, err2 := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	p := Point{Lat: lat, Lng: lng}
+	if err1 != nil || err2 != nil || !p.Valid() {
+		return Point{}, ErrInvalidPoint
+	}
+	return p, nil
+}
+
+// ParseBox разбирает "west,south,east,north" — порядок GeoJSON и Leaflet (LatLngBounds.toBBoxString)
+func ParseBox(s string) (Box, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return Box{}, ErrInvalidBox
+	}
+	var v [4]float64
+	for i, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(f) {
+			return Box{}, ErrInvalidBox
+		}
+		v[i] = f
+	}
+	box := Box{MinLng: v[0], MinLat: v[1], MaxLng: v[2], MaxLat: v[3]}
+	sw := Point{Lat: box.MinLat, Lng: box.MinLng}
+	ne := Point{Lat: box.MaxLat, Lng: box.MaxLng}
+	if !sw.Valid() || !ne.Valid() || box.MinLat > box.MaxLat {
+		return Box{}, ErrInvalidBox
+	}
+	return box, nil
+}
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
+func degrees(rad float64) float64 { return rad * 180 / math.Pi }
+
+func normalizeLng(lng float64) float64 {
+	for lng > 180 {
+		lng -= 360
+	}
+	for lng < -180 {
+		lng += 360
+	}
+	return lng
+}
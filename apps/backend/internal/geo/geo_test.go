@@ -0,0 +1,134 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var (
+	moscow = Point{Lat: 55.755826, Lng: 37.617300}
+	spb    = Point{Lat: 59.934280, Lng: 30.335099}
+)
+
+func TestDistanceKm(t *testing.T) {
+	d := DistanceKm(moscow, spb)
+	if math.Abs(d-634) > 5 {
+		t.Fatalf("Moscow-SPb expected ~634 km, got %.1f", d)
+	}
+	if DistanceKm(moscow, moscow) != 0 {
+		t.Fatalf("distance to itself must be 0")
+	}
+}
+
+func TestBoundingBox_ContainsCircle(t *testing.T) {
+	box := BoundingBox(moscow, 10)
+	for _, bearing := range []Point{
+		{Lat: moscow.Lat + 0.089, Lng: moscow.Lng}, // ~9.9 км на север
+		{Lat: moscow.Lat, Lng: moscow.Lng + 0.158}, // ~9.9 км на восток
+	} {
+		if bearing.Lat > box.MaxLat || bearing.Lng > box.MaxLng {
+			t.Fatalf("point %+v must be inside %+v", bearing, box)
+		}
+	}
+
+	wrap := BoundingBox(Point{Lat: 0, Lng: 179.95}, 20)
+	if !wrap.CrossesAntimeridian() {
+		t.Fatalf("box near 180° must cross antimeridian: %+v", wrap)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	// эталон из описания geohash
+	if got := Encode(Point{Lat: 42.6, Lng: -5.6}, 5); got != "ezs42" {
+		t.Fatalf("expected ezs42, got %s", got)
+	}
+	if got := Encode(moscow, GeohashPrecision); len(got) != GeohashPrecision || !strings.HasPrefix(got, "ucfv") {
+		t.Fatalf("unexpected Moscow geohash %s", got)
+	}
+}
+
+func TestCover_IncludesPointsInBox(t *testing.T) {
+	box := BoundingBox(moscow, 5)
+	cells := Cover(box, 16)
+	if len(cells) == 0 || len(cells) > 16 {
+		t.Fatalf("unexpected cover size %d", len(cells))
+	}
+
+	for _, p := range []Point{moscow, {Lat: box.MinLat, Lng: box.MinLng}, {Lat: box.MaxLat, Lng: box.MaxLng}} {
+		h := Encode(p, GeohashPrecision)
+		covered := false
+		for _, c := range cells {
+			if strings.HasPrefix(h, c) {
+				covered = true
+			}
+		}
+		if !covered {
+			t.Fatalf("point %+v (%s) not covered by %v", p, h, cells)
+		}
+	}
+
+	if Cover(Box{MinLat: -1, MaxLat: 1, MinLng: 179, MaxLng: -179}, 16) != nil {
+		t.Fatalf("antimeridian box must not be covered by prefixes")
+	}
+}
+
+func TestParsePointAndBox(t *testing.T) {
+	if p, err := ParsePoint("55.75, 37.61"); err != nil || p.Lat != 55.75 || p.Lng != 37.61 {
+		t.Fatalf("unexpected %+v %v", p, err)
+	}
+	for _, bad := range []string{"", "55.75", "91,0", "0,181", "a,b"} {
+		if _, err := ParsePoint(bad); !errors.Is(err, ErrInvalidPoint) {
+			t.Fatalf("%q: expected ErrInvalidPoint, got %v", bad, err)
+		}
+	}
+
+	box, err := ParseBox("37.5,55.7,37.7,55.8")
+	if err != nil || box.MinLng != 37.5 || box.MinLat != 55.7 || box.MaxLng != 37.7 || box.MaxLat != 55.8 {
+		t.Fatalf("unexpected %+v %v", box, err)
+	}
+	if _, err := ParseBox("37.5,55.8,37.7,55.7"); !errors.Is(err, ErrInvalidBox) {
+		t.Fatalf("south > north must fail, got %v", err)
+	}
+}
+
+func TestOfflineGeocoder(t *testing.T) {
+	g := NewOfflineGeocoder(map[string]Point{"Москва, Тверская 1": {Lat: 55.757, Lng: 37.615}})
+
+	if p, err := g.Geocode(context.Background(), "  москва,  тверская 1 "); err != nil || p.Lat != 55.757 {
+		t.Fatalf("exact address: %+v %v", p, err)
+	}
+	if p, err := g.Geocode(context.Background(), "г. Санкт-Петербург, Невский пр., 1"); err != nil || p != spb {
+		t.Fatalf("city fallback: %+v %v", p, err)
+	}
+	if _, err := g.Geocode(context.Background(), "Атлантида"); !errors.Is(err, ErrAddressNotFound) {
+		t.Fatalf("expected ErrAddressNotFound, got %v", err)
+	}
+}
+
+func TestNominatimGeocoder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" || r.Header.Get("User-Agent") != "bookinghub-test" {
+			t.Errorf("unexpected request %s ua=%q", r.URL, r.Header.Get("User-Agent"))
+		}
+		if r.URL.Query().Get("q") == "nowhere" {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"lat": "55.7558", "lon": "37.6173", "display_name": "Москва"}]`))
+	}))
+	defer srv.Close()
+
+	g := NewNominatimGeocoder(srv.URL, "bookinghub-test")
+	p, err := g.Geocode(context.Background(), "Москва")
+	if err != nil || p.Lat != 55.7558 || p.Lng != 37.6173 {
+		t.Fatalf("unexpected %+v %v", p, err)
+	}
+	if _, err := g.Geocode(context.Background(), "nowhere"); !errors.Is(err, ErrAddressNotFound) {
+		t.Fatalf("expected ErrAddressNotFound, got %v", err)
+	}
+}
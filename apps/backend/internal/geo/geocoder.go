@@ -0,0 +1,121 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var ErrAddressNotFound = errors.New("адрес не найден")
+
+// Geocoder превращает адрес в координаты
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (Point, error)
+}
+
+// OfflineGeocoder — геокодер без сети: точные адреса из таблицы, иначе центр известного города
+// из адреса. Подходит для тестов и локальной разработки.
+type OfflineGeocoder struct {
+	addresses map[string]Point
+}
+
+// defaultCities — центры крупных городов, чтобы офлайн-режим давал хоть какие-то координаты
+var defaultCities = map[string]Point{
+	"москва":          {Lat: 55.755826, Lng: 37.617300},
+	"санкт-петербург": {Lat: 59.934280, Lng: 30.335099},
+	"новосибирск":     {Lat: 55.008353, Lng: 82.935733},
+	"екатеринбург":    {Lat: 56.838926, Lng: 60.605703},
+	"казань":          {Lat: 55.796127, Lng: 49.106414},
+	"нижний новгород": {Lat: 56.296504, Lng: 43.936059},
+	"краснодар":       {Lat: 45.035470, Lng: 38.975313},
+	"самара":          {Lat: 53.195878, Lng: 50.100202},
+}
+
+func NewOfflineGeocoder(addresses map[string]Point) *OfflineGeocoder {
+	g := &OfflineGeocoder{addresses: make(map[string]Point, len(addresses))}
+	for addr, p := range addresses {
+		g.addresses[normalizeAddress(addr)] = p
+	}
+	return g
+}
+
+func (g *OfflineGeocoder) Geocode(_ context.Context, address string) (Point, error) {
+	norm := normalizeAddress(address)
+	if norm == "" {
+		return Point{}, ErrAddressNotFound
+	}
+	if p, ok := g.addresses[norm]; ok {
+		return p, nil
+	}
+	for _, part := range strings.Split(norm, ",") {
+		part = strings.TrimPrefix(strings.TrimSpace(part), "г. ")
+		if p, ok := defaultCities[part]; ok {
+			return p, nil
+		}
+	}
+	return Point{}, ErrAddressNotFound
+}
+
+func normalizeAddress(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(s, "ё", "е"))), " ")
+}
+
+// NominatimGeocoder — геокодер OpenStreetMap Nominatim (или совместимый сервер).
+// Политика публичного сервера требует осмысленный User-Agent и не больше 1 запроса в секунду.
+type NominatimGeocoder struct {
+	baseURL   string
+	userAgent string
+	client    *http.Client
+}
+
+func NewNominatimGeocoder(baseURL, userAgent string) *NominatimGeocoder {
+	if baseURL == "" {
+		baseURL = "https://nominatim.openstreetmap.org"
+	}
+	return &NominatimGeocoder{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		userAgent: userAgent,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (g *NominatimGeocoder) Geocode(ctx context.Context, address string) (Point, error) {
+	q := url.Values{"q": {address}, "format": {"jsonv2"}, "limit": {"1"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+"/search?"+q.Encode(), nil)
+	if err != nil {
+		return Point{}, err
+	}
+	req.Header.Set("User-Agent", g.userAgent)
+	req.Header.Set("Accept-Language", "ru")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return Point{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Point{}, fmt.Errorf("nominatim: %s", resp.Status)
+	}
+
+	// координаты приходят строками: [{"lat": "55.75", "lon": "37.61"}]
+	var found []struct {
+		Lat float64 `json:"lat,string"`
+		Lon float64 `json:"lon,string"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&found); err != nil {
+		return Point{}, fmt.Errorf("nominatim: %w", err)
+	}
+	if len(found) == 0 {
+		return Point{}, ErrAddressNotFound
+	}
+	p := Point{Lat: found[0].Lat, Lng: found[0].Lon}
+	if !p.Valid() {
+		return Point{}, ErrAddressNotFound
+	}
+	return p, nil
+}
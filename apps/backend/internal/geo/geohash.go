@@ -0,0 +1,104 @@
+package geo
+
+import "strings"
+
+// GeohashPrecision — длина geohash, который хранится у объявления (ячейка ~4 см)
+const GeohashPrecision = 12
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Encode кодирует точку в geohash заданной длины. Соседние точки имеют общий префикс,
+// поэтому поиск по области сводится к нескольким LIKE 'префикс%' по обычному индексу.
+func Encode(p Point, precision int) string {
+	latLo, latHi := -90.0, 90.0
+	lngLo, lngHi := -180.0, 180.0
+
+	var b strings.Builder
+	b.Grow(precision)
+	even := true // биты чередуются: долгота, широта, долгота...
+	bit, ch := 0, 0
+	for b.Len() < precision {
+		if even {
+			mid := (lngLo + lngHi) / 2
+			if p.Lng >= mid {
+				ch = ch<<1 | 1
+				lngLo = mid
+			} else {
+				ch <<= 1
+				lngHi = mid
+			}
+		} else {
+			mid := (latLo + latHi) / 2
+			if p.Lat >= mid {
+				ch = ch<<1 | 1
+				latLo = mid
+			} else {
+				ch <<= 1
+				latHi = mid
+			}
+		}
+		even = !even
+		bit++
+		if bit == 5 {
+			b.WriteByte(geohashAlphabet[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return b.String()
+}
+
+// cellSize — размеры ячейки geohash длины precision в градусах
+func cellSize(precision int) (latDeg, lngDeg float64) {
+	bits := 5 * precision
+	lngBits := (bits + 1) / 2
+	latBits := bits / 2
+	return 180 / float64(uint64(1)<<latBits), 360 / float64(uint64(1)<<lngBits)
+}
+
+// Cover — префиксы geohash, ячейки которых покрывают прямоугольник; не больше maxCells штук.
+// Берётся самая длинная точность, при которой ячеек достаточно мало. nil — покрытие не нужно
+// (прямоугольник пересекает 180-й меридиан или больше ячейки первого уровня).
+func Cover(box Box, maxCells int) []string {
+	if box.CrossesAntimeridian() {
+		return nil
+	}
+	for precision := GeohashPrecision; precision >= 1; precision-- {
+		hLat, wLng := cellSize(precision)
+		rows := int((box.MaxLat-box.MinLat)/hLat) + 2
+		cols := int((box.MaxLng-box.MinLng)/wLng) + 2
+		if rows*cols > maxCells*4 {
+			continue
+		}
+
+		seen := make(map[string]bool)
+		var out []string
+		for lat := box.MinLat; ; lat += hLat {
+			lat = minFloat(lat, box.MaxLat)
+			for lng := box.MinLng; ; lng += wLng {
+				lng = minFloat(lng, box.MaxLng)
+				h := Encode(Point{Lat: lat, Lng: lng}, precision)
+				if !seen[h] {
+					seen[h] = true
+					out = append(out, h)
+				}
+				if lng >= box.MaxLng {
+					break
+				}
+			}
+			if lat >= box.MaxLat {
+				break
+			}
+		}
+		if len(out) <= maxCells {
+			return out
+		}
+	}
+	return nil
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
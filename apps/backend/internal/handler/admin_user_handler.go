@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"crypto/rand"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/apiv1"
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/repo"
+	"bookinghub-backend/internal/service"
+)
+
+const (
+	adminUsersDefaultPageSize = 20
+	adminUsersMaxPageSize     = 100
+	tempPasswordLength        = 12
+)
+
+type AdminUserHandler struct {
+	users *repo.UserRepo
+	auth  *service.AuthService
+}
+
+func NewAdminUserHandler(users *repo.UserRepo, auth *service.AuthService) *AdminUserHandler {
+	return &AdminUserHandler{users: users, auth: auth}
+}
+
+// GET /api/admin/users?q=&page=1&pageSize=20
+func (h *AdminUserHandler) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	page := 1
+	if s := strings.TrimSpace(q.Get("page")); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			writeFieldError(w, "page", "query.invalid_page")
+			return
+		}
+		page = n
+	}
+
+	pageSize := adminUsersDefaultPageSize
+	if s := strings.TrimSpace(q.Get("pageSize")); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > adminUsersMaxPageSize {
+			writeFieldError(w, "pageSize", "query.invalid_page_size")
+			return
+		}
+		pageSize = n
+	}
+
+	items, total, err := h.users.AdminList(r.Context(), q.Get("q"), pageSize, (page-1)*pageSize)
+	if err != nil {
+		internalError(w, "admin.list_users_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.NewAdminUsersPage(items, total, page, pageSize))
+}
+
+type updateUserRoleReq struct {
+	Role domain.UserRole `json:"role" validate:"trim,required,oneof=INDIVIDUAL COMPANY ADMIN"`
+}
+
+// PATCH /api/admin/users/{id}/role
+func (h *AdminUserHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.targetUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req updateUserRoleReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	req.Role = domain.UserRole(strings.ToUpper(string(req.Role)))
+
+	if err := h.users.UpdateRole(r.Context(), id, req.Role); err != nil {
+		internalError(w, "admin.change_role_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+type suspendUserReq struct {
+	Reason string `json:"reason" validate:"trim,required,max=255"`
+}
+
+// POST /api/admin/users/{id}/suspend
+func (h *AdminUserHandler) Suspend(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.targetUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req suspendUserReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if err := h.users.Suspend(r.Context(), id, req.Reason, time.Now()); err != nil {
+		internalError(w, "admin.suspend_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+// POST /api/admin/users/{id}/unsuspend
+func (h *AdminUserHandler) Unsuspend(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.targetUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.users.Unsuspend(r.Context(), id); err != nil {
+		internalError(w, "admin.unsuspend_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+// POST /api/admin/users/{id}/verify — отметка «проверенный арендатор» для автоподтверждения броней
+func (h *AdminUserHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.targetUser(w, r)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	if err := h.users.SetVerified(r.Context(), id, &now); err != nil {
+		internalError(w, "admin.verify_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+// POST /api/admin/users/{id}/unverify
+func (h *AdminUserHandler) Unverify(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.targetUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.users.SetVerified(r.Context(), id, nil); err != nil {
+		internalError(w, "admin.unverify_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+// POST /api/admin/users/{id}/logout — принудительный выход со всех устройств
+func (h *AdminUserHandler) ForceLogout(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.targetUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.users.RevokeSessions(r.Context(), id, sessionsRevokedAt(time.Now())); err != nil {
+		internalError(w, "admin.revoke_sessions_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+// POST /api/admin/users/{id}/password-reset — выдаёт временный пароль и завершает сессии
+func (h *AdminUserHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.targetUser(w, r)
+	if !ok {
+		return
+	}
+
+	password, err := newTempPassword()
+	if err != nil {
+		internalError(w, "admin.password_failed", err)
+		return
+	}
+	hash, err := h.auth.HashPassword(password)
+	if err != nil {
+		internalError(w, "auth.password_failed", err)
+		return
+	}
+
+	if err := h.users.UpdatePasswordHashByID(r.Context(), id, hash); err != nil {
+		internalError(w, "auth.update_password_failed", err)
+		return
+	}
+	if err := h.users.RevokeSessions(r.Context(), id, sessionsRevokedAt(time.Now())); err != nil {
+		internalError(w, "admin.revoke_sessions_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.TemporaryPassword{TemporaryPassword: password})
+}
+
+// targetUser разбирает {id}, проверяет что пользователь есть и что админ не действует над собой.
+func (h *AdminUserHandler) targetUser(w http.ResponseWriter, r *http.Request) (uint64, bool) {
+	id, err := strconv.ParseUint(strings.TrimSpace(chi.URLParam(r, "id")), 10, 64)
+	if err != nil || id == 0 {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "request.invalid_id")
+		return 0, false
+	}
+	if id == GetUserID(r) {
+		writeError(w, http.StatusConflict, CodeConflict, "admin.self_action")
+		return 0, false
+	}
+
+	st, err := h.users.GetAuthState(r.Context(), id)
+	if err != nil {
+		internalError(w, "db.error", err)
+		return 0, false
+	}
+	if st == nil {
+		writeError(w, http.StatusNotFound, CodeNotFound, "user.not_found")
+		return 0, false
+	}
+	return id, true
+}
+
+// sessionsRevokedAt отбрасывает доли секунды: iat в JWT хранится в секундах, и вход сразу после
+// принудительного выхода, в ту же секунду, должен работать. Токены, выпущенные в эту секунду
+// до выхода, тоже останутся действительными — это цена секундной точности iat.
+func sessionsRevokedAt(now time.Time) time.Time {
+	return now.Truncate(time.Second)
+}
+
+const tempPasswordAlphabet = "abcdefghjkmnpqrstuvwxyzABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+func newTempPassword() (string, error) {
+	b := make([]byte, tempPasswordLength)
+	max := big.NewInt(int64(len(tempPasswordAlphabet)))
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = tempPasswordAlphabet[n.Int64()]
+	}
+	return string(b), nil
+}
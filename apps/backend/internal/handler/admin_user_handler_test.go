@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/repo"
+	"bookinghub-backend/internal/service"
+)
+
+func newAdminUsersRouter(h *AdminUserHandler, uid uint64) *chi.Mux {
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(w, withUID(req, uid))
+		})
+	})
+	r.Get("/api/admin/users", h.List)
+	r.Patch("/api/admin/users/{id}/role", h.UpdateRole)
+	r.Post("/api/admin/users/{id}/suspend", h.Suspend)
+	r.Post("/api/admin/users/{id}/verify", h.Verify)
+	r.Post("/api/admin/users/{id}/logout", h.ForceLogout)
+	r.Post("/api/admin/users/{id}/password-reset", h.ResetPassword)
+	return r
+}
+
+func expectAuthState(mock sqlmock.Sqlmock, id uint64) {
+	mock.ExpectQuery("SELECT role, suspended_at, suspend_reason, sessions_revoked_at, locale FROM users WHERE id = \\?").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"role", "suspended_at", "suspend_reason", "sessions_revoked_at"}).
+			AddRow("INDIVIDUAL", nil, nil, nil))
+}
+
+func TestAdminUserHandler_List_SearchAndPaging(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	h := NewAdminUserHandler(repo.NewUserRepo(db), service.NewAuthService("secret", 60))
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE email LIKE \\? OR name LIKE \\?").
+		WithArgs("%ivan%", "%ivan%").
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(11))
+	mock.ExpectQuery("SELECT id, email, name, role, created_at, suspended_at, suspend_reason, verified_at FROM users").
+		WithArgs("%ivan%", "%ivan%", 5, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "created_at", "suspended_at", "suspend_reason"}).
+			AddRow(uint64(11), "ivan@test.local", "Ivan", "INDIVIDUAL", time.Now(), nil, nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/users?q=ivan&page=3&pageSize=5", nil)
+	rr := httptest.NewRecorder()
+
+	newAdminUsersRouter(h, 1).ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Items []map[string]any `json:"items"`
+		Total int              `json:"total"`
+		Page  int              `json:"page"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+	if resp.Total != 11 || resp.Page != 3 || len(resp.Items) != 1 {
+		t.Fatalf("unexpected response: %s", rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestAdminUserHandler_List_BadPageSize(t *testing.T) {
+	db, _, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	h := NewAdminUserHandler(repo.NewUserRepo(db), service.NewAuthService("secret", 60))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/users?pageSize=1000", nil)
+	rr := httptest.NewRecorder()
+
+	newAdminUsersRouter(h, 1).ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 got %d", rr.Code)
+	}
+}
+
+func TestAdminUserHandler_UpdateRole_Self_409(t *testing.T) {
+	db, _, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	h := NewAdminUserHandler(repo.NewUserRepo(db), service.NewAuthService("secret", 60))
+
+	body, _ := json.Marshal(map[string]any{"role": "INDIVIDUAL"})
+	req := httptest.NewRequest(http.MethodPatch, "/api/admin/users/1/role", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	newAdminUsersRouter(h, 1).ServeHTTP(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409 got %d", rr.Code)
+	}
+}
+
+func TestAdminUserHandler_UpdateRole_OK(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	h := NewAdminUserHandler(repo.NewUserRepo(db), service.NewAuthService("secret", 60))
+
+	expectAuthState(mock, 7)
+	mock.ExpectExec("UPDATE users SET role = \\? WHERE id = \\?").
+		WithArgs("COMPANY", uint64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	body, _ := json.Marshal(map[string]any{"role": "company"})
+	req := httptest.NewRequest(http.MethodPatch, "/api/admin/users/7/role", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	newAdminUsersRouter(h, 1).ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestAdminUserHandler_Suspend_RequiresReason(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	h := NewAdminUserHandler(repo.NewUserRepo(db), service.NewAuthService("secret", 60))
+	expectAuthState(mock, 7)
+
+	body, _ := json.Marshal(map[string]any{"reason": "  "})
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/7/suspend", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	newAdminUsersRouter(h, 1).ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 got %d", rr.Code)
+	}
+}
+
+func TestAdminUserHandler_Suspend_UserNotFound(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	h := NewAdminUserHandler(repo.NewUserRepo(db), service.NewAuthService("secret", 60))
+	mock.ExpectQuery("SELECT role, suspended_at, suspend_reason, sessions_revoked_at, locale FROM users").
+		WithArgs(uint64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"role", "suspended_at", "suspend_reason", "sessions_revoked_at"}))
+
+	body, _ := json.Marshal(map[string]any{"reason": "spam"})
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/7/suspend", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	newAdminUsersRouter(h, 1).ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 got %d", rr.Code)
+	}
+}
+
+func TestAdminUserHandler_Suspend_OK(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	h := NewAdminUserHandler(repo.NewUserRepo(db), service.NewAuthService("secret", 60))
+	expectAuthState(mock, 7)
+	mock.ExpectExec("UPDATE users SET suspended_at = \\?, suspend_reason = \\? WHERE id = \\?").
+		WithArgs(sqlmock.AnyArg(), "spam", uint64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	body, _ := json.Marshal(map[string]any{"reason": " spam "})
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/7/suspend", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	newAdminUsersRouter(h, 1).ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestAdminUserHandler_ForceLogout_OK(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	h := NewAdminUserHandler(repo.NewUserRepo(db), service.NewAuthService("secret", 60))
+	expectAuthState(mock, 7)
+	mock.ExpectExec("UPDATE users SET sessions_revoked_at = \\? WHERE id = \\?").
+		WithArgs(sqlmock.AnyArg(), uint64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/7/logout", nil)
+	rr := httptest.NewRecorder()
+
+	newAdminUsersRouter(h, 1).ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestAdminUserHandler_ResetPassword_OK(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	auth := service.NewAuthService("secret", 60)
+	h := NewAdminUserHandler(repo.NewUserRepo(db), auth)
+	expectAuthState(mock, 7)
+	mock.ExpectExec("UPDATE users SET password_hash = \\? WHERE id = \\?").
+		WithArgs(sqlmock.AnyArg(), uint64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE users SET sessions_revoked_at = \\? WHERE id = \\?").
+		WithArgs(sqlmock.AnyArg(), uint64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/7/password-reset", nil)
+	rr := httptest.NewRecorder()
+
+	newAdminUsersRouter(h, 1).ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+
+	var resp map[string]string
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+	if len(resp["temporaryPassword"]) != tempPasswordLength {
+		t.Fatalf("unexpected temporary password: %q", resp["temporaryPassword"])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSessionsRevokedAt_TruncatesToSecond(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 0, 0, 500, time.UTC)
+	got := sessionsRevokedAt(now)
+	if !got.Equal(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected %v", got)
+	}
+}
+
+func TestAdminUserHandler_Verify_OK(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	h := NewAdminUserHandler(repo.NewUserRepo(db), service.NewAuthService("secret", 60))
+	expectAuthState(mock, 7)
+	mock.ExpectExec("UPDATE users SET verified_at = \\? WHERE id = \\?").
+		WithArgs(sqlmock.AnyArg(), uint64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/7/verify", nil)
+	rr := httptest.NewRecorder()
+
+	newAdminUsersRouter(h, 1).ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
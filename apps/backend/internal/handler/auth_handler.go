@@ -2,11 +2,16 @@ package handler
 
 import (
 	"database/sql"
-	"encoding/json"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
+	"bookinghub-backend/internal/apiv1"
 	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/i18n"
+	"bookinghub-backend/internal/oidc"
+	"bookinghub-backend/internal/ratelimit"
 	"bookinghub-backend/internal/repo"
 	"bookinghub-backend/internal/service"
 )
@@ -14,114 +19,102 @@ import (
 type AuthHandler struct {
 	users *repo.UserRepo
 	auth  *service.AuthService
+
+	limits   ratelimit.Store
+	perEmail ratelimit.Limit
+	lockout  *ratelimit.Lockout
+
+	twoFactor *service.TwoFactorService
+
+	oidcProvider *oidc.Provider
+	sso          *service.SSOService
 }
 
 func NewAuthHandler(users *repo.UserRepo, auth *service.AuthService) *AuthHandler {
 	return &AuthHandler{users: users, auth: auth}
 }
 
+// WithLoginLimits защищает вход от перебора паролей: не больше perEmail попыток на email
+// и прогрессивная блокировка email после серии неудач. Неудачи по несуществующим email
+// учитываются так же, чтобы по ответам нельзя было узнать, есть ли аккаунт.
+func (h *AuthHandler) WithLoginLimits(store ratelimit.Store, perEmail ratelimit.Limit, lockout *ratelimit.Lockout) *AuthHandler {
+	h.limits, h.perEmail, h.lockout = store, perEmail, lockout
+	return h
+}
+
 type registerReq struct {
-	Email       string `json:"email"`
-	Name        string `json:"name"`
-	Password    string `json:"password"`
-	AccountType string `json:"accountType"`
+	Email       string `json:"email" validate:"trim,required,max=190,email"`
+	Name        string `json:"name" validate:"trim,required,max=120"`
+	Password    string `json:"password" validate:"required,min=6,max=72"` // bcrypt учитывает только 72 байта
+	AccountType string `json:"accountType" validate:"trim,oneof=INDIVIDUAL COMPANY"`
 }
 
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	var req registerReq
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Некорректный JSON", http.StatusBadRequest)
-		return
-	}
-
-	req.Email = strings.TrimSpace(strings.ToLower(req.Email))
-	req.Name = strings.TrimSpace(req.Name)
-
-	if req.Email == "" || !strings.Contains(req.Email, "@") {
-		http.Error(w, "Введите корректный email", http.StatusBadRequest)
-		return
-	}
-	if req.Name == "" {
-		http.Error(w, "Имя обязательно", http.StatusBadRequest)
-		return
-	}
-	if len(req.Password) < 6 {
-		http.Error(w, "Пароль должен быть не короче 6 символов", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
+	req.Email = strings.ToLower(req.Email)
 
 	// проверим, что email не занят
 	existing, err := h.users.GetByEmail(r.Context(), req.Email)
 	if err == nil && existing != nil {
-		http.Error(w, "Пользователь с таким email уже существует", http.StatusConflict)
+		writeError(w, http.StatusConflict, CodeEmailTaken, "auth.user_exists")
 		return
 	}
 	if err != nil && err != sql.ErrNoRows {
-		http.Error(w, "Ошибка базы данных: "+err.Error(), http.StatusInternalServerError)
+		internalError(w, "db.error", err)
 		return
 	}
 
 	hash, err := h.auth.HashPassword(req.Password)
 	if err != nil {
-		http.Error(w, "Не удалось обработать пароль", http.StatusInternalServerError)
+		internalError(w, "auth.password_failed", err)
 		return
 	}
 
 	role := domain.RoleIndividual
-	switch strings.ToUpper(strings.TrimSpace(req.AccountType)) {
-	case "INDIVIDUAL", "":
-		role = domain.RoleIndividual
-	case "COMPANY":
+	if strings.EqualFold(req.AccountType, "COMPANY") {
 		role = domain.RoleCompany
-	default:
-		http.Error(w, "Некорректный тип аккаунта (accountType)", http.StatusBadRequest)
-		return
 	}
 
 	id, err := h.users.Create(r.Context(), req.Email, req.Name, role, hash)
 	if err != nil {
-		http.Error(w, "Не удалось создать пользователя: "+err.Error(), http.StatusInternalServerError)
+		internalError(w, "auth.create_user_failed", err)
 		return
 	}
 
 	token, err := h.auth.CreateAccessToken(id, role)
 	if err != nil {
-		http.Error(w, "Не удалось создать токен", http.StatusInternalServerError)
+		internalError(w, "auth.token_failed", err)
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, map[string]any{
-		"accessToken": token,
-		"user": map[string]any{
-			"id":    id,
-			"email": req.Email,
-			"name":  req.Name,
-			"role":  role,
-		},
+	writeJSON(w, http.StatusCreated, apiv1.Auth{
+		AccessToken: token,
+		User:        apiv1.AuthUser{ID: id, Email: req.Email, Name: req.Name, Role: role},
 	})
 }
 
 type loginReq struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"trim,required"`
+	Password string `json:"password" validate:"required"`
 }
 
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req loginReq
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Некорректный JSON", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
-
-	req.Email = strings.TrimSpace(strings.ToLower(req.Email))
-	if req.Email == "" || req.Password == "" {
-		http.Error(w, "Email и пароль обязательны", http.StatusBadRequest)
+	req.Email = strings.ToLower(req.Email)
+	if !h.allowLogin(w, r, req.Email) {
 		return
 	}
 
 	u, err := h.users.GetByEmail(r.Context(), req.Email)
 	if err != nil {
-		http.Error(w, "Неверный email или пароль", http.StatusUnauthorized)
+		h.loginFailed(w, r, req.Email)
+		writeError(w, http.StatusUnauthorized, CodeInvalidCredentials, "auth.invalid_credentials")
 		return
 	}
 
@@ -134,82 +127,146 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.auth.CheckPassword(u.PasswordHash, req.Password); err != nil {
-		http.Error(w, "Неверный email или пароль", http.StatusUnauthorized)
+		h.loginFailed(w, r, req.Email)
+		writeError(w, http.StatusUnauthorized, CodeInvalidCredentials, "auth.invalid_credentials")
+		return
+	}
+
+	h.finishLogin(w, r, u)
+}
+
+// finishLogin завершает вход пользователя, личность которого уже проверена (паролем или
+// провайдером SSO): заблокированному — 403, с 2FA — второй шаг (202), иначе — токен доступа
+func (h *AuthHandler) finishLogin(w http.ResponseWriter, r *http.Request, u *domain.User) {
+	st, err := h.users.GetAuthState(r.Context(), u.ID)
+	if err != nil {
+		internalError(w, "db.error", err)
+		return
+	}
+	if st != nil && st.SuspendedAt != nil {
+		msg := i18n.T(responseLang(w), "auth.suspended")
+		if st.SuspendReason != nil && *st.SuspendReason != "" {
+			msg += ": " + *st.SuspendReason
+		}
+		writeAPIError(w, http.StatusForbidden, APIError{Code: CodeAccountSuspended, Message: msg})
+		return
+	}
+
+	// со второй ступенью счётчик неудач сбросит только верный код
+	if h.challenge(w, r, u) {
 		return
 	}
 
 	token, err := h.auth.CreateAccessToken(u.ID, u.Role)
 	if err != nil {
-		http.Error(w, "Не удалось создать токен", http.StatusInternalServerError)
+		internalError(w, "auth.token_failed", err)
 		return
 	}
+	h.loginSucceeded(w, r, u.Email)
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"accessToken": token,
-		"user": map[string]any{
-			"id":    u.ID,
-			"email": u.Email,
-			"name":  u.Name,
-			"role":  u.Role,
-		},
+	writeJSON(w, http.StatusOK, apiv1.Auth{
+		AccessToken: token,
+		User:        apiv1.AuthUser{ID: u.ID, Email: u.Email, Name: u.Name, Role: u.Role},
 	})
 }
 
+// allowLogin проверяет блокировку и лимит попыток входа для email; при отказе уже ответил 429.
+// Ошибки хранилища лимитов вход не блокируют.
+func (h *AuthHandler) allowLogin(w http.ResponseWriter, r *http.Request, email string) bool {
+	if h.limits == nil {
+		return true
+	}
+	now := time.Now()
+	if h.lockout != nil {
+		wait, err := h.lockout.Check(r.Context(), loginKey(email), now)
+		if err != nil {
+			log.Printf("[%s] login lockout: %v", w.Header().Get(RequestIDHeader), err)
+		} else if wait > 0 {
+			writeTooManyRequests(w, CodeLoginLocked, wait)
+			return false
+		}
+	}
+	ok, wait, err := h.limits.Take(r.Context(), "login-email:"+email, h.perEmail, now)
+	if err != nil {
+		log.Printf("[%s] login rate limit: %v", w.Header().Get(RequestIDHeader), err)
+		return true
+	}
+	if !ok {
+		writeTooManyRequests(w, CodeTooManyRequests, wait)
+	}
+	return ok
+}
+
+func (h *AuthHandler) loginFailed(w http.ResponseWriter, r *http.Request, email string) {
+	if h.lockout == nil {
+		return
+	}
+	if _, err := h.lockout.Fail(r.Context(), loginKey(email), time.Now()); err != nil {
+		log.Printf("[%s] login lockout: %v", w.Header().Get(RequestIDHeader), err)
+	}
+}
+
+func (h *AuthHandler) loginSucceeded(w http.ResponseWriter, r *http.Request, email string) {
+	if h.lockout == nil {
+		return
+	}
+	if err := h.lockout.Succeed(r.Context(), loginKey(email)); err != nil {
+		log.Printf("[%s] login lockout: %v", w.Header().Get(RequestIDHeader), err)
+	}
+}
+
+func loginKey(email string) string { return "login:" + strings.ToLower(email) }
+
 func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
 	uid := GetUserID(r)
 	if uid == 0 {
-		http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
 		return
 	}
 
 	u, err := h.users.GetByID(r.Context(), uid)
 	if err != nil {
-		http.Error(w, "Пользователь не найден", http.StatusUnauthorized)
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "user.not_found")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"id":    u.ID,
-		"email": u.Email,
-		"name":  u.Name,
-		"role":  u.Role,
-	})
+	writeJSON(w, http.StatusOK, apiv1.NewProfile(*u))
 }
 
 type updateMeReq struct {
-	Email string `json:"email"`
-	Name  string `json:"name"`
+	Email  string  `json:"email" validate:"trim,required,max=190,email"`
+	Name   string  `json:"name" validate:"trim,required,max=120"`
+	Locale *string `json:"locale"` // ru, en; "" — снова по Accept-Language; без поля — не меняется
 }
 
 func (h *AuthHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
 	uid := GetUserID(r)
 	if uid == 0 {
-		http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
 		return
 	}
 
 	var req updateMeReq
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Некорректный JSON", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
+	email := strings.ToLower(req.Email)
+	name := req.Name
 
-	email := strings.TrimSpace(strings.ToLower(req.Email))
-	name := strings.TrimSpace(req.Name)
-
-	if email == "" || !strings.Contains(email, "@") {
-		http.Error(w, "Введите корректный email", http.StatusBadRequest)
-		return
-	}
-	if name == "" {
-		http.Error(w, "Имя обязательно", http.StatusBadRequest)
-		return
+	var locale *string
+	if req.Locale != nil && *req.Locale != "" {
+		lang, ok := i18n.Normalize(*req.Locale)
+		if !ok {
+			writeFieldError(w, "locale", "auth.invalid_locale")
+			return
+		}
+		locale = &lang
 	}
 
 	// текущий пользователь
 	u, err := h.users.GetByID(r.Context(), uid)
 	if err != nil {
-		http.Error(w, "Пользователь не найден", http.StatusUnauthorized)
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "user.not_found")
 		return
 	}
 
@@ -217,89 +274,81 @@ func (h *AuthHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
 	if email != u.Email {
 		existing, err := h.users.GetByEmail(r.Context(), email)
 		if err == nil && existing != nil {
-			http.Error(w, "Email уже занят", http.StatusConflict)
+			writeError(w, http.StatusConflict, CodeEmailTaken, "auth.email_taken")
 			return
 		}
 		if err != nil && err != sql.ErrNoRows {
-			http.Error(w, "Ошибка базы данных: "+err.Error(), http.StatusInternalServerError)
+			internalError(w, "db.error", err)
 			return
 		}
 	}
 
-	if err := h.users.UpdateProfile(r.Context(), uid, email, name); err != nil {
-		http.Error(w, "Не удалось обновить профиль: "+err.Error(), http.StatusInternalServerError)
+	if req.Locale == nil {
+		locale = u.Locale
+	}
+	if err := h.users.UpdateProfile(r.Context(), uid, email, name, locale); err != nil {
+		internalError(w, "auth.update_profile_failed", err)
 		return
 	}
 
 	// отдадим обновлённого пользователя
 	u2, _ := h.users.GetByID(r.Context(), uid)
-	writeJSON(w, http.StatusOK, map[string]any{
-		"id":    u2.ID,
-		"email": u2.Email,
-		"name":  u2.Name,
-		"role":  u2.Role,
-	})
+	writeJSON(w, http.StatusOK, apiv1.NewProfile(*u2))
 }
 
 type changePasswordReq struct {
-	CurrentPassword string `json:"currentPassword"`
-	NewPassword     string `json:"newPassword"`
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required,min=6,max=72"`
 }
 
 func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 	uid := GetUserID(r)
 	if uid == 0 {
-		http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
 		return
 	}
 
 	var req changePasswordReq
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Некорректный JSON", http.StatusBadRequest)
-		return
-	}
-
-	if len(req.NewPassword) < 6 {
-		http.Error(w, "Новый пароль должен быть минимум 6 символов", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	u, err := h.users.GetByID(r.Context(), uid)
 	if err != nil {
-		http.Error(w, "Пользователь не найден", http.StatusUnauthorized)
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "user.not_found")
 		return
 	}
 
 	if err := h.auth.CheckPassword(u.PasswordHash, req.CurrentPassword); err != nil {
-		http.Error(w, "Текущий пароль неверный", http.StatusUnauthorized)
+		writeError(w, http.StatusUnauthorized, CodeInvalidCredentials, "auth.wrong_password")
 		return
 	}
 
 	hash, err := h.auth.HashPassword(req.NewPassword)
 	if err != nil {
-		http.Error(w, "Не удалось обработать пароль", http.StatusInternalServerError)
+		internalError(w, "auth.password_failed", err)
 		return
 	}
 
 	if err := h.users.UpdatePasswordHashByID(r.Context(), uid, hash); err != nil {
-		http.Error(w, "Не удалось обновить пароль: "+err.Error(), http.StatusInternalServerError)
+		internalError(w, "auth.update_password_failed", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{"ok": true})
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
 }
 
 func (h *AuthHandler) DeleteMe(w http.ResponseWriter, r *http.Request) {
 	uid := GetUserID(r)
 	if uid == 0 {
-		http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
 		return
 	}
 
 	if err := h.users.DeleteAccount(r.Context(), uid); err != nil {
-		http.Error(w, "Не удалось удалить аккаунт: "+err.Error(), http.StatusInternalServerError)
+		internalError(w, "auth.delete_account_failed", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{"ok": true})
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
 }
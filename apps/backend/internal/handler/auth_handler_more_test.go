@@ -103,7 +103,7 @@ func TestAuthHandler_Register_ConflictEmail(t *testing.T) {
 	h := NewAuthHandler(users, auth)
 
 	// users.GetByEmail -> returns existing row
-	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at FROM users WHERE email = \\?").
+	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE email = \\?").
 		WithArgs("a@b.c").
 		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at"}).
 			AddRow(uint64(1), "a@b.c", "Alex", "INDIVIDUAL", "hash", time.Now()))
@@ -168,7 +168,7 @@ func TestAuthHandler_Login_WrongPassword(t *testing.T) {
 
 	hash, _ := auth.HashPassword("correct123")
 
-	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at FROM users WHERE email = \\?").
+	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE email = \\?").
 		WithArgs("a@b.c").
 		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at"}).
 			AddRow(uint64(1), "a@b.c", "Alex", "INDIVIDUAL", hash, time.Now()))
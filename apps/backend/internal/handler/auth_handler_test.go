@@ -63,7 +63,7 @@ func TestAuthHandler_Register_ExistingEmail_409(t *testing.T) {
 	created := time.Now()
 
 	// GetByEmail -> found
-	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at FROM users WHERE email = \\? LIMIT 1").
+	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE email = \\? LIMIT 1").
 		WithArgs("x@test.local").
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at"}).
@@ -98,7 +98,7 @@ func TestAuthHandler_Register_OK_201(t *testing.T) {
 	h := NewAuthHandler(users, auth)
 
 	// GetByEmail -> sql.ErrNoRows
-	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at FROM users WHERE email = \\? LIMIT 1").
+	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE email = \\? LIMIT 1").
 		WithArgs("new@test.local").
 		WillReturnError(sql.ErrNoRows)
 
@@ -138,11 +138,16 @@ func TestAuthHandler_Login_OK_200(t *testing.T) {
 	hash, _ := auth.HashPassword("123456")
 	created := time.Now()
 
-	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at FROM users WHERE email = \\? LIMIT 1").
+	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE email = \\? LIMIT 1").
 		WithArgs("a@test.local").
 		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at"}).
 			AddRow(uint64(10), "a@test.local", "A", string(domain.RoleIndividual), hash, created))
 
+	mock.ExpectQuery("SELECT role, suspended_at, suspend_reason, sessions_revoked_at, locale FROM users WHERE id = \\?").
+		WithArgs(uint64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"role", "suspended_at", "suspend_reason", "sessions_revoked_at"}).
+			AddRow(string(domain.RoleIndividual), nil, nil, nil))
+
 	body := map[string]any{"email": "a@test.local", "password": "123456"}
 	b, _ := json.Marshal(body)
 
@@ -158,6 +163,39 @@ func TestAuthHandler_Login_OK_200(t *testing.T) {
 	}
 }
 
+func TestAuthHandler_Login_Suspended_403(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock(t)
+	defer cleanup()
+
+	users := repo.NewUserRepo(dbx)
+	auth := service.NewAuthService("dev", 15)
+	h := NewAuthHandler(users, auth)
+
+	hash, _ := auth.HashPassword("123456")
+	created := time.Now()
+
+	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE email = \\? LIMIT 1").
+		WithArgs("a@test.local").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at"}).
+			AddRow(uint64(10), "a@test.local", "A", string(domain.RoleIndividual), hash, created))
+	mock.ExpectQuery("SELECT role, suspended_at, suspend_reason, sessions_revoked_at, locale FROM users WHERE id = \\?").
+		WithArgs(uint64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"role", "suspended_at", "suspend_reason", "sessions_revoked_at"}).
+			AddRow(string(domain.RoleIndividual), created, "spam", nil))
+
+	b, _ := json.Marshal(map[string]any{"email": "a@test.local", "password": "123456"})
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/login", bytes.NewReader(b))
+	rr := httptest.NewRecorder()
+
+	h.Login(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
 func TestAuthHandler_Login_TEMPUser_SetsDefaultPassword(t *testing.T) {
 	dbx, mock, cleanup := newSQLXMock(t)
 	defer cleanup()
@@ -168,7 +206,7 @@ func TestAuthHandler_Login_TEMPUser_SetsDefaultPassword(t *testing.T) {
 
 	created := time.Now()
 
-	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at FROM users WHERE email = \\? LIMIT 1").
+	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE email = \\? LIMIT 1").
 		WithArgs("temp@test.local").
 		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at"}).
 			AddRow(uint64(11), "temp@test.local", "Temp", string(domain.RoleIndividual), "TEMP", created))
@@ -178,6 +216,11 @@ func TestAuthHandler_Login_TEMPUser_SetsDefaultPassword(t *testing.T) {
 		WithArgs(sqlmock.AnyArg(), "temp@test.local").
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
+	mock.ExpectQuery("SELECT role, suspended_at, suspend_reason, sessions_revoked_at, locale FROM users WHERE id = \\?").
+		WithArgs(uint64(11)).
+		WillReturnRows(sqlmock.NewRows([]string{"role", "suspended_at", "suspend_reason", "sessions_revoked_at"}).
+			AddRow(string(domain.RoleIndividual), nil, nil, nil))
+
 	body := map[string]any{"email": "temp@test.local", "password": "123456"}
 	b, _ := json.Marshal(body)
 
@@ -218,7 +261,7 @@ func TestAuthHandler_Me_OK_200(t *testing.T) {
 
 	created := time.Now()
 
-	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at FROM users WHERE id = \\? LIMIT 1").
+	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE id = \\? LIMIT 1").
 		WithArgs(uint64(5)).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at"}).
 			AddRow(uint64(5), "me@test.local", "Me", string(domain.RoleCompany), "HASH", created))
@@ -246,28 +289,28 @@ func TestAuthHandler_UpdateMe_OK_200_EmailChanged(t *testing.T) {
 	created := time.Now()
 
 	// current user
-	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at FROM users WHERE id = \\? LIMIT 1").
+	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE id = \\? LIMIT 1").
 		WithArgs(uint64(5)).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at"}).
 			AddRow(uint64(5), "old@test.local", "Old", string(domain.RoleIndividual), "HASH", created))
 
 	// uniqueness: GetByEmail(new) -> no rows
-	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at FROM users WHERE email = \\? LIMIT 1").
+	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE email = \\? LIMIT 1").
 		WithArgs("new@test.local").
 		WillReturnError(sql.ErrNoRows)
 
 	// UpdateProfile
-	mock.ExpectExec("UPDATE users SET email = \\?, name = \\? WHERE id = \\?").
-		WithArgs("new@test.local", "NewName", uint64(5)).
+	mock.ExpectExec("UPDATE users SET email = \\?, name = \\?, locale = \\? WHERE id = \\?").
+		WithArgs("new@test.local", "NewName", "en", uint64(5)).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	// GetByID again
-	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at FROM users WHERE id = \\? LIMIT 1").
+	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE id = \\? LIMIT 1").
 		WithArgs(uint64(5)).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at"}).
 			AddRow(uint64(5), "new@test.local", "NewName", string(domain.RoleIndividual), "HASH", created))
 
-	body := map[string]any{"email": "new@test.local", "name": "NewName"}
+	body := map[string]any{"email": "new@test.local", "name": "NewName", "locale": "en-GB"}
 	b, _ := json.Marshal(body)
 
 	req := httptest.NewRequest(http.MethodPatch, "/api/auth/me", bytes.NewReader(b))
@@ -294,7 +337,7 @@ func TestAuthHandler_ChangePassword_OK_200(t *testing.T) {
 	oldHash, _ := auth.HashPassword("oldpass")
 	created := time.Now()
 
-	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at FROM users WHERE id = \\? LIMIT 1").
+	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE id = \\? LIMIT 1").
 		WithArgs(uint64(9)).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at"}).
 			AddRow(uint64(9), "p@test.local", "P", string(domain.RoleIndividual), oldHash, created))
@@ -4,8 +4,11 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/i18n"
+	"bookinghub-backend/internal/policy"
 	"bookinghub-backend/internal/service"
 )
 
@@ -16,24 +19,57 @@ const (
 	ctxRole   ctxKey = "role"
 )
 
-func AuthMiddleware(auth *service.AuthService) func(http.Handler) http.Handler {
+type authUserStore interface {
+	GetAuthState(ctx context.Context, id uint64) (*domain.UserAuthState, error)
+}
+
+// AuthMiddleware проверяет JWT и сверяет пользователя с БД: удалённые и заблокированные
+// не проходят, токены старше принудительного выхода отклоняются, роль берётся из БД.
+func AuthMiddleware(auth *service.AuthService, users authUserStore) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			h := r.Header.Get("Authorization")
 			if h == "" || !strings.HasPrefix(h, "Bearer ") {
-				http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+				writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
 				return
 			}
 			tokenStr := strings.TrimPrefix(h, "Bearer ")
 
 			claims, err := auth.ParseAccessToken(tokenStr)
 			if err != nil {
-				http.Error(w, "Неверный токен", http.StatusUnauthorized)
+				writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.invalid_token")
 				return
 			}
 
-			ctx := context.WithValue(r.Context(), ctxUserID, claims.UserID)
-			ctx = context.WithValue(ctx, ctxRole, claims.Role)
+			st, err := users.GetAuthState(r.Context(), claims.UserID)
+			if err != nil {
+				internalError(w, "db.error", err)
+				return
+			}
+			if st == nil {
+				writeError(w, http.StatusUnauthorized, CodeUnauthorized, "user.not_found")
+				return
+			}
+			// язык из профиля важнее Accept-Language
+			ctx := r.Context()
+			if st.Locale != nil {
+				if lang, ok := i18n.Normalize(*st.Locale); ok {
+					setLang(w, lang)
+					ctx = i18n.WithLang(ctx, lang)
+				}
+			}
+			if st.SuspendedAt != nil {
+				writeError(w, http.StatusForbidden, CodeAccountSuspended, "auth.suspended")
+				return
+			}
+			// iat — в секундах: токен, выпущенный в секунду выхода, действителен
+			if st.SessionsRevokedAt != nil && (claims.IssuedAt == nil || claims.IssuedAt.Time.Before(st.SessionsRevokedAt.Truncate(time.Second))) {
+				writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.session_revoked")
+				return
+			}
+
+			ctx = context.WithValue(ctx, ctxUserID, claims.UserID)
+			ctx = context.WithValue(ctx, ctxRole, st.Role)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -55,22 +91,21 @@ func GetRole(r *http.Request) domain.UserRole {
 	return v.(domain.UserRole)
 }
 
-// RequireRoles — middleware, которое пускает только нужные роли
-func RequireRoles(allowed ...domain.UserRole) func(http.Handler) http.Handler {
-	allowedSet := map[domain.UserRole]struct{}{}
-	for _, a := range allowed {
-		allowedSet[a] = struct{}{}
-	}
+func actorFromRequest(r *http.Request) policy.Actor {
+	return policy.Actor{UserID: GetUserID(r), Role: GetRole(r)}
+}
 
+// RequirePermission — middleware, которое пускает только роли с глобальным правом perm
+func RequirePermission(p *policy.Policy, perm domain.Permission) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			role := GetRole(r)
-			if role == "" {
-				http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+			actor := actorFromRequest(r)
+			if actor.UserID == 0 || actor.Role == "" {
+				writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
 				return
 			}
-			if _, ok := allowedSet[role]; !ok {
-				http.Error(w, "Недостаточно прав", http.StatusForbidden)
+			if !p.Can(actor, perm, policy.Target{}) {
+				writeError(w, http.StatusForbidden, CodeForbidden, "access.denied")
 				return
 			}
 			next.ServeHTTP(w, r)
@@ -1,18 +1,30 @@
 package handler
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/policy"
 	"bookinghub-backend/internal/service"
 )
 
+type fakeAuthUsers struct {
+	states map[uint64]*domain.UserAuthState
+}
+
+func (f *fakeAuthUsers) GetAuthState(ctx context.Context, id uint64) (*domain.UserAuthState, error) {
+	return f.states[id], nil
+}
+
 func TestAuthMiddleware_NoHeader(t *testing.T) {
 	auth := service.NewAuthService("secret", 15)
 
-	h := AuthMiddleware(auth)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	h := AuthMiddleware(auth, &fakeAuthUsers{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(200)
 	}))
 
@@ -28,7 +40,7 @@ func TestAuthMiddleware_NoHeader(t *testing.T) {
 func TestAuthMiddleware_BadToken(t *testing.T) {
 	auth := service.NewAuthService("secret", 15)
 
-	h := AuthMiddleware(auth)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	h := AuthMiddleware(auth, &fakeAuthUsers{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(200)
 	}))
 
@@ -50,8 +62,12 @@ func TestAuthMiddleware_OK_PutsClaimsToContext(t *testing.T) {
 		t.Fatalf("CreateAccessToken: %v", err)
 	}
 
+	users := &fakeAuthUsers{states: map[uint64]*domain.UserAuthState{
+		123: {Role: domain.RoleAdmin},
+	}}
+
 	called := false
-	h := AuthMiddleware(auth)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	h := AuthMiddleware(auth, users)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		called = true
 		if GetUserID(r) != 123 {
 			t.Fatalf("expected uid=123 got %d", GetUserID(r))
@@ -76,13 +92,111 @@ func TestAuthMiddleware_OK_PutsClaimsToContext(t *testing.T) {
 	}
 }
 
-func TestRequireRoles_Forbidden(t *testing.T) {
+func TestAuthMiddleware_RejectsByDBState(t *testing.T) {
+	auth := service.NewAuthService("secret", 15)
+	tok, err := auth.CreateAccessToken(7, domain.RoleAdmin)
+	if err != nil {
+		t.Fatalf("CreateAccessToken: %v", err)
+	}
+
+	suspendedAt := time.Now().Add(-time.Hour)
+	revokedAt := time.Now().Add(time.Minute)
+	reason := "spam"
+
+	cases := []struct {
+		name  string
+		state *domain.UserAuthState
+		want  int
+	}{
+		{"deleted user", nil, 401},
+		{"suspended user", &domain.UserAuthState{Role: domain.RoleAdmin, SuspendedAt: &suspendedAt, SuspendReason: &reason}, 403},
+		{"sessions revoked after token issue", &domain.UserAuthState{Role: domain.RoleAdmin, SessionsRevokedAt: &revokedAt}, 401},
+		{"active user", &domain.UserAuthState{Role: domain.RoleAdmin}, 200},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			users := &fakeAuthUsers{states: map[uint64]*domain.UserAuthState{}}
+			if tc.state != nil {
+				users.states[7] = tc.state
+			}
+
+			h := AuthMiddleware(auth, users)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(200)
+			}))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tok)
+			rr := httptest.NewRecorder()
+
+			h.ServeHTTP(rr, req)
+			if rr.Code != tc.want {
+				t.Fatalf("expected %d got %d", tc.want, rr.Code)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_LoginRightAfterRevoke(t *testing.T) {
+	auth := service.NewAuthService("secret", 15)
+	// админ завершил сессии, пользователь сразу вошёл снова — скорее всего в ту же секунду
+	revokedAt := sessionsRevokedAt(time.Now())
+	tok, err := auth.CreateAccessToken(7, domain.RoleIndividual)
+	if err != nil {
+		t.Fatalf("CreateAccessToken: %v", err)
+	}
+	users := &fakeAuthUsers{states: map[uint64]*domain.UserAuthState{
+		7: {Role: domain.RoleIndividual, SessionsRevokedAt: &revokedAt},
+	}}
+
+	h := AuthMiddleware(auth, users)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer "+tok)
+	rr := httptest.NewRecorder()
+
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d", rr.Code)
+	}
+}
+
+func TestAuthMiddleware_RoleComesFromDB(t *testing.T) {
+	auth := service.NewAuthService("secret", 15)
+	// токен выписан, когда пользователь был ADMIN, потом роль понизили
+	tok, err := auth.CreateAccessToken(8, domain.RoleAdmin)
+	if err != nil {
+		t.Fatalf("CreateAccessToken: %v", err)
+	}
+	users := &fakeAuthUsers{states: map[uint64]*domain.UserAuthState{
+		8: {Role: domain.RoleIndividual},
+	}}
+
+	h := AuthMiddleware(auth, users)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if GetRole(r) != domain.RoleIndividual {
+			t.Fatalf("expected role from DB, got %s", GetRole(r))
+		}
+		w.WriteHeader(200)
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer "+tok)
+	rr := httptest.NewRecorder()
+
+	h.ServeHTTP(rr, req)
+	if rr.Code != 200 {
+		t.Fatalf("expected 200 got %d", rr.Code)
+	}
+}
+
+func TestRequirePermission_NoRole_401(t *testing.T) {
 	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(200)
 	})
 
 	// сделаем запрос без роли в контексте -> 401
-	h := RequireRoles(domain.RoleAdmin)(next)
+	h := RequirePermission(policy.Default(), domain.PermCategoryManage)(next)
 
 	req := httptest.NewRequest("GET", "/", nil)
 	rr := httptest.NewRecorder()
@@ -92,3 +206,61 @@ func TestRequireRoles_Forbidden(t *testing.T) {
 		t.Fatalf("expected 401 got %d", rr.Code)
 	}
 }
+
+func TestRequirePermission_ByRole(t *testing.T) {
+	cases := []struct {
+		role domain.UserRole
+		perm domain.Permission
+		want int
+	}{
+		{domain.RoleAdmin, domain.PermCategoryManage, 200},
+		{domain.RoleCompany, domain.PermCategoryManage, 403},
+		{domain.RoleIndividual, domain.PermCategoryManage, 403},
+		{domain.RoleCompany, domain.PermOrgCreate, 200},
+		{domain.RoleIndividual, domain.PermOrgCreate, 403},
+		{domain.RoleAdmin, domain.PermPermissionManage, 200},
+		{domain.RoleCompany, domain.PermPermissionManage, 403},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.role)+" "+string(tc.perm), func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(200)
+			})
+			h := RequirePermission(policy.Default(), tc.perm)(next)
+
+			req := httptest.NewRequest("GET", "/", nil)
+			ctx := context.WithValue(req.Context(), ctxUserID, uint64(5))
+			ctx = context.WithValue(ctx, ctxRole, tc.role)
+			rr := httptest.NewRecorder()
+
+			h.ServeHTTP(rr, req.WithContext(ctx))
+			if rr.Code != tc.want {
+				t.Fatalf("expected %d got %d", tc.want, rr.Code)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_ProfileLocaleOverridesHeader(t *testing.T) {
+	auth := service.NewAuthService("secret", 15)
+	tok, _ := auth.CreateAccessToken(5, domain.RoleIndividual)
+	en := "en"
+	users := &fakeAuthUsers{states: map[uint64]*domain.UserAuthState{
+		5: {Role: domain.RoleIndividual, Locale: &en},
+	}}
+
+	h := Localize(AuthMiddleware(auth, users)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, CodeBookingNotFound, "booking.not_found")
+	})))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer "+tok)
+	req.Header.Set("Accept-Language", "ru-RU")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	if rr.Header().Get("Content-Language") != "en" || !strings.Contains(rr.Body.String(), "Booking not found") {
+		t.Fatalf("expected English response, got %s %s", rr.Header().Get("Content-Language"), rr.Body.String())
+	}
+}
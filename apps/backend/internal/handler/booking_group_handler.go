@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/apiv1"
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/policy"
+	"bookinghub-backend/internal/repo"
+	"bookinghub-backend/internal/service"
+)
+
+type BookingGroupHandler struct {
+	bookings *repo.BookingRepo
+	bundles  *repo.BundleRepo
+	service  *service.BookingGroupService
+	policy   *policy.Policy
+	waitlist slotReleaser
+}
+
+func NewBookingGroupHandler(bookings *repo.BookingRepo, bundles *repo.BundleRepo, service *service.BookingGroupService, policy *policy.Policy) *BookingGroupHandler {
+	return &BookingGroupHandler{bookings: bookings, bundles: bundles, service: service, policy: policy}
+}
+
+// WithWaitlist — отмена группы освобождает место для очереди ожидания
+func (h *BookingGroupHandler) WithWaitlist(wl slotReleaser) *BookingGroupHandler {
+	h.waitlist = wl
+	return h
+}
+
+type groupItemReq struct {
+	ResourceID uint64 `json:"resourceId"`
+	BundleID   uint64 `json:"bundleId"`
+	Quantity   *int   `json:"quantity" validate:"min=1"` // единиц ресурса или экземпляров набора; по умолчанию 1
+	StartAt    string `json:"startAt"`                   // если не задано — общее время группы
+	EndAt      string `json:"endAt"`
+}
+
+type createGroupReq struct {
+	StartAt string         `json:"startAt"`
+	EndAt   string         `json:"endAt"`
+	Items   []groupItemReq `json:"items" validate:"required,max=20"` // service.MaxGroupItems
+}
+
+// POST /api/booking-groups — несколько ресурсов и наборов одним запросом.
+// Создаются либо все брони, либо ни одной; набор раскладывается на брони своих ресурсов.
+func (h *BookingGroupHandler) Create(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return
+	}
+
+	var req createGroupReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	var errs fieldErrors
+	for i, it := range req.Items {
+		if (it.ResourceID == 0) == (it.BundleID == 0) {
+			errs.add(fmt.Sprintf("items[%d]", i), "validation.one_of_fields")
+		}
+	}
+	if errs.respond(w) {
+		return
+	}
+
+	// время без смещения — местное время каждого ресурса, поэтому части набора
+	// в разных поясах получают каждая своё UTC-время
+	clocks := map[uint64]*resourceClock{}
+	parseFor := func(resourceID uint64, startRaw, endRaw string) (time.Time, time.Time, bool) {
+		clock := clocks[resourceID]
+		if clock == nil {
+			clock = newResourceClock(r.Context(), h.bookings, resourceID)
+			clocks[resourceID] = clock
+		}
+		startAt, ok := parseRequestTime(w, clock, "startAt", startRaw)
+		if !ok {
+			return time.Time{}, time.Time{}, false
+		}
+		endAt, ok := parseRequestTime(w, clock, "endAt", endRaw)
+		return startAt, endAt, ok
+	}
+
+	var items []domain.BookingItem
+	for _, it := range req.Items {
+		startRaw, endRaw := req.StartAt, req.EndAt
+		if strings.TrimSpace(it.StartAt) != "" {
+			startRaw = it.StartAt
+		}
+		if strings.TrimSpace(it.EndAt) != "" {
+			endRaw = it.EndAt
+		}
+
+		quantity := 1
+		if it.Quantity != nil {
+			quantity = *it.Quantity
+		}
+
+		if it.ResourceID != 0 {
+			startAt, endAt, ok := parseFor(it.ResourceID, startRaw, endRaw)
+			if !ok {
+				return
+			}
+			items = append(items, domain.BookingItem{ResourceID: it.ResourceID, StartAt: startAt, EndAt: endAt, Quantity: quantity})
+			continue
+		}
+
+		bundle, err := h.bundles.GetByID(r.Context(), it.BundleID)
+		if err != nil {
+			internalError(w, "db.error", err)
+			return
+		}
+		if bundle == nil || !bundle.IsActive {
+			writeError(w, http.StatusNotFound, CodeNotFound, "bundle.not_found")
+			return
+		}
+		bundleID := bundle.ID
+		for _, part := range bundle.Items {
+			startAt, endAt, ok := parseFor(part.ResourceID, startRaw, endRaw)
+			if !ok {
+				return
+			}
+			items = append(items, domain.BookingItem{
+				ResourceID: part.ResourceID,
+				BundleID:   &bundleID,
+				StartAt:    startAt,
+				EndAt:      endAt,
+				Quantity:   part.Quantity * quantity,
+			})
+		}
+	}
+
+	groupID, bookingIDs, status, err := h.service.Create(r.Context(), uid, items)
+	if err != nil {
+		writeServiceError(w, err, "booking.create_group_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, apiv1.BookingGroupCreated{ID: groupID, BookingIDs: bookingIDs, Status: status})
+}
+
+// GET /api/booking-groups/{id} — автору и тем, кто видит все брони
+func (h *BookingGroupHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if GetUserID(r) == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return
+	}
+	g, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	actor := actorFromRequest(r)
+	if g.UserID != actor.UserID && !h.policy.Can(actor, domain.PermBookingViewAll, policy.Target{}) {
+		writeError(w, http.StatusForbidden, CodeForbidden, "access.denied")
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.NewBookingGroup(*g))
+}
+
+// POST /api/booking-groups/{id}/cancel — отменяет все части; правило «за 2 часа» — по самой ранней
+func (h *BookingGroupHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	if GetUserID(r) == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return
+	}
+	g, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	if !h.policy.Can(actorFromRequest(r), domain.PermBookingCancel, policy.Target{BookerUserID: g.UserID}) {
+		writeError(w, http.StatusForbidden, CodeForbidden, "access.denied")
+		return
+	}
+	if g.Status != domain.BookingPending && g.Status != domain.BookingApproved {
+		writeError(w, http.StatusBadRequest, CodeCancelNotAllowed, "booking.cancel_not_allowed")
+		return
+	}
+	for _, b := range g.Bookings {
+		if time.Until(b.StartAt) < 2*time.Hour {
+			writeError(w, http.StatusBadRequest, CodeCancelNotAllowed, "booking.cancel_too_late")
+			return
+		}
+	}
+
+	if err := h.bookings.CancelGroup(r.Context(), g.ID, GetUserID(r)); err != nil {
+		internalError(w, "booking.cancel_failed", err)
+		return
+	}
+	resourceIDs := make([]uint64, 0, len(g.Bookings))
+	for _, b := range g.Bookings {
+		resourceIDs = append(resourceIDs, b.ResourceID)
+	}
+	releaseSlots(r.Context(), h.waitlist, resourceIDs...)
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+func (h *BookingGroupHandler) load(w http.ResponseWriter, r *http.Request) (*domain.BookingGroup, bool) {
+	id64, err := strconv.ParseUint(strings.TrimSpace(chi.URLParam(r, "id")), 10, 64)
+	if err != nil || id64 == 0 {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "request.invalid_id")
+		return nil, false
+	}
+	g, err := h.bookings.GetGroup(r.Context(), id64)
+	if err != nil {
+		internalError(w, "db.error", err)
+		return nil, false
+	}
+	if g == nil {
+		writeError(w, http.StatusNotFound, CodeNotFound, "booking.group_not_found")
+		return nil, false
+	}
+	return g, true
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/apiv1"
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/policy"
+	"bookinghub-backend/internal/repo"
+	"bookinghub-backend/internal/service"
+)
+
+func bookingGroupRouter(db *repo.BookingRepo, bundles *repo.BundleRepo) http.Handler {
+	h := NewBookingGroupHandler(db, bundles, service.NewBookingGroupService(db), policy.Default())
+	r := chi.NewRouter()
+	r.Post("/api/booking-groups", h.Create)
+	return r
+}
+
+func TestBookingGroupHandler_Create_ExpandsBundle_201(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	start := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Hour)
+	end := start.Add(2 * time.Hour)
+
+	// набор #9: зал #1 и 2 микрофона #2
+	mock.ExpectQuery("FROM resource_bundles WHERE id = \\?").
+		WithArgs(uint64(9)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "owner_user_id", "organization_id", "title", "description", "is_active", "created_at"}).
+			AddRow(uint64(9), uint64(50), nil, "Зал со звуком", nil, true, time.Now()))
+	mock.ExpectQuery("FROM resource_bundle_items WHERE bundle_id IN \\(\\?\\)").
+		WithArgs(uint64(9)).
+		WillReturnRows(sqlmock.NewRows([]string{"bundle_id", "resource_id", "quantity"}).
+			AddRow(uint64(9), uint64(1), 1).
+			AddRow(uint64(9), uint64(2), 2))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM resources WHERE id IN \\(\\?, \\?, \\?\\) ORDER BY id FOR UPDATE").
+		WithArgs(uint64(1), uint64(2), uint64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint64(1)).AddRow(uint64(2)).AddRow(uint64(3)))
+	mock.ExpectExec("INSERT INTO booking_groups \\(user_id\\) VALUES \\(\\?\\)").
+		WithArgs(uint64(7)).
+		WillReturnResult(sqlmock.NewResult(40, 1))
+	for i, part := range []struct {
+		resourceID uint64
+		quantity   int
+		bundleID   any
+	}{{1, 2, uint64(9)}, {2, 4, uint64(9)}, {3, 1, nil}} {
+		expectAvailability(mock, part.resourceID, 5, start, end)
+		mock.ExpectExec("INSERT INTO bookings \\(resource_id, user_id, group_id, bundle_id, start_at, end_at, quantity, status\\)").
+			WithArgs(part.resourceID, uint64(7), uint64(40), part.bundleID, timeEq{start}, timeEq{end}, part.quantity).
+			WillReturnResult(sqlmock.NewResult(int64(100+i), 1))
+		expectCreatedHistory(mock, uint64(100+i), 7)
+	}
+	mock.ExpectCommit()
+
+	body := `{"startAt":"` + start.Format(time.RFC3339) + `","endAt":"` + end.Format(time.RFC3339) + `",
+		"items":[{"bundleId":9,"quantity":2},{"resourceId":3}]}`
+	req := withUID(httptest.NewRequest(http.MethodPost, "/api/booking-groups", bytes.NewBufferString(body)), 7)
+	rr := httptest.NewRecorder()
+
+	bookingGroupRouter(repo.NewBookingRepo(db), repo.NewBundleRepo(db)).ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	var got apiv1.BookingGroupCreated
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.ID != 40 || len(got.BookingIDs) != 3 || got.Status != domain.BookingPending {
+		t.Fatalf("unexpected response: %+v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBookingGroupHandler_Create_ConflictRollsBack_409(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	start := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Hour)
+	end := start.Add(time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM resources WHERE id IN").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint64(1)).AddRow(uint64(2)))
+	mock.ExpectExec("INSERT INTO booking_groups").WillReturnResult(sqlmock.NewResult(40, 1))
+	expectAvailability(mock, 1, 1, start, end)
+	mock.ExpectExec("INSERT INTO bookings").WillReturnResult(sqlmock.NewResult(100, 1))
+	expectCreatedHistory(mock, 100, 7)
+	// проектор #2 уже занят — вся группа откатывается
+	expectAvailability(mock, 2, 1, start, end).
+		AddRow(uint64(5), uint64(2), uint64(3), start, end, 1, "APPROVED", nil, start, nil)
+	mock.ExpectRollback()
+
+	body := `{"startAt":"` + start.Format(time.RFC3339) + `","endAt":"` + end.Format(time.RFC3339) + `",
+		"items":[{"resourceId":1},{"resourceId":2}]}`
+	req := withUID(httptest.NewRequest(http.MethodPost, "/api/booking-groups", bytes.NewBufferString(body)), 7)
+	rr := httptest.NewRecorder()
+
+	bookingGroupRouter(repo.NewBookingRepo(db), repo.NewBundleRepo(db)).ServeHTTP(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), "#2") {
+		t.Fatalf("conflict must name the resource: %s", rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), `"code":"BOOKING_CONFLICT"`) {
+		t.Fatalf("conflict must carry BOOKING_CONFLICT code: %s", rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBookingGroupHandler_Create_ItemNeedsOneTarget_400(t *testing.T) {
+	body := `{"startAt":"2030-01-01T10:00:00","endAt":"2030-01-01T12:00:00","items":[{"resourceId":1,"bundleId":2}]}`
+	req := withUID(httptest.NewRequest(http.MethodPost, "/api/booking-groups", bytes.NewBufferString(body)), 7)
+	rr := httptest.NewRecorder()
+
+	bookingGroupRouter(nil, nil).ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
@@ -2,7 +2,7 @@ package handler
 
 import (
 	"context"
-	"encoding/json"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -10,7 +10,9 @@ import (
 
 	"github.com/go-chi/chi/v5"
 
+	"bookinghub-backend/internal/apiv1"
 	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/policy"
 	// "bookinghub-backend/internal/repo"
 	"bookinghub-backend/internal/service"
 )
@@ -20,9 +22,37 @@ type bookingRepo interface {
 	ListPending(ctx context.Context) ([]domain.Booking, error)
 	ListPendingForOwner(ctx context.Context, ownerID uint64) ([]domain.Booking, error)
 	GetOwnerUserIDByBookingID(ctx context.Context, bookingID uint64) (uint64, error)
+	IsOrgManagerForBooking(ctx context.Context, bookingID, userID uint64) (bool, error)
 	GetByID(ctx context.Context, id uint64) (*domain.Booking, error)
-	UpdateStatus(ctx context.Context, id uint64, status domain.BookingStatus, managerComment *string) error
-	Cancel(ctx context.Context, id uint64) error
+	UpdateStatus(ctx context.Context, c domain.BookingStatusChange) (bool, error)
+	Cancel(ctx context.Context, id uint64, from domain.BookingStatus, by uint64) error
+	SyncGroupStatus(ctx context.Context, groupID uint64) (domain.BookingStatus, error)
+	GetGroup(ctx context.Context, id uint64) (*domain.BookingGroup, error)
+	ListStatusHistory(ctx context.Context, bookingID uint64) ([]domain.BookingStatusChange, error)
+	ResourceTimezone(ctx context.Context, resourceID uint64) (string, error)
+}
+
+// slotReleaser — очередь ожидания: ей сообщают, что на ресурсе освободилось место
+type slotReleaser interface {
+	Release(ctx context.Context, resourceID uint64) error
+}
+
+// releaseSlots передаёт освободившиеся места очереди. Ошибка очереди не отменяет
+// уже выполненную отмену брони — её только логируем.
+func releaseSlots(ctx context.Context, wl slotReleaser, resourceIDs ...uint64) {
+	if wl == nil {
+		return
+	}
+	seen := map[uint64]bool{}
+	for _, id := range resourceIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if err := wl.Release(ctx, id); err != nil {
+			log.Printf("waitlist release for resource %d failed: %v", id, err)
+		}
+	}
 }
 
 type userRepo interface {
@@ -30,24 +60,39 @@ type userRepo interface {
 }
 
 type BookingHandler struct {
-	repo    bookingRepo
-	users   userRepo
-	service *service.BookingService
+	repo     bookingRepo
+	users    userRepo
+	service  *service.BookingService
+	policy   *policy.Policy
+	waitlist slotReleaser
+	holds    *service.HoldService
+}
+
+func NewBookingHandler(repo bookingRepo, users userRepo, service *service.BookingService, policy *policy.Policy) *BookingHandler {
+	return &BookingHandler{repo: repo, users: users, service: service, policy: policy}
 }
 
-func NewBookingHandler(repo bookingRepo, users userRepo, service *service.BookingService) *BookingHandler {
-	return &BookingHandler{repo: repo, users: users, service: service}
+// WithHolds позволяет создавать бронь из временного удержания (holdToken)
+func (h *BookingHandler) WithHolds(holds *service.HoldService) *BookingHandler {
+	h.holds = holds
+	return h
+}
+
+// WithWaitlist включает очередь ожидания: отменённые и отклонённые брони освобождают место для неё
+func (h *BookingHandler) WithWaitlist(wl slotReleaser) *BookingHandler {
+	h.waitlist = wl
+	return h
 }
 
 func (h *BookingHandler) My(w http.ResponseWriter, r *http.Request) {
 	uid := GetUserID(r)
 	if uid == 0 {
-		http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
 		return
 	}
 	items, err := h.repo.ListByUser(r.Context(), uid)
 	if err != nil {
-		http.Error(w, "Не удалось получить бронирования: "+err.Error(), http.StatusInternalServerError)
+		internalError(w, "booking.list_failed", err)
 		return
 	}
 
@@ -55,84 +100,100 @@ func (h *BookingHandler) My(w http.ResponseWriter, r *http.Request) {
 		items = make([]domain.Booking, 0)
 	}
 
-	writeJSON(w, http.StatusOK, items)
+	writeJSON(w, http.StatusOK, apiv1.NewBookings(items))
 }
 
 type createBookingReq struct {
 	ResourceID uint64 `json:"resourceId"`
-	StartAt    string `json:"startAt"` // ISO-строка
-	EndAt      string `json:"endAt"`
-}
-
-// ожидаем формат RFC3339, например: 2025-12-25T10:00:00
-func parseTime(s string) (time.Time, error) {
-	s = strings.TrimSpace(s)
-	// Поддержим без timezone (локально) + с timezone
-	if t, err := time.Parse(time.RFC3339, s); err == nil {
-		return t, nil
-	}
-	return time.Parse("2006-01-02T15:04:05", s)
+	StartAt    string `json:"startAt" validate:"trim"` // RFC3339 или без смещения — тогда в поясе ресурса
+	EndAt      string `json:"endAt" validate:"trim"`
+	Quantity   *int   `json:"quantity" validate:"min=1"` // сколько единиц ресурса; по умолчанию 1
+	// HoldToken — бронь из удержания: интервал и количество берутся из него, startAt/endAt не нужны
+	HoldToken string `json:"holdToken" validate:"trim,max=64"`
 }
 
 func (h *BookingHandler) Create(w http.ResponseWriter, r *http.Request) {
 	uid := GetUserID(r)
 	if uid == 0 {
-		http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
 		return
 	}
 
 	var req createBookingReq
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Некорректный JSON", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	startAt, err := parseTime(req.StartAt)
-	if err != nil {
-		http.Error(w, "Некорректное startAt. Формат: YYYY-MM-DDTHH:MM:SS", http.StatusBadRequest)
+	if req.HoldToken != "" && h.holds != nil {
+		id, status, err := h.holds.Book(r.Context(), uid, req.ResourceID, req.HoldToken)
+		if err != nil {
+			writeBookingError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, apiv1.BookingCreated{ID: id, Status: status})
 		return
 	}
-	endAt, err := parseTime(req.EndAt)
-	if err != nil {
-		http.Error(w, "Некорректное endAt. Формат: YYYY-MM-DDTHH:MM:SS", http.StatusBadRequest)
+
+	var errs fieldErrors
+	errs.require("resourceId", req.ResourceID != 0)
+	errs.require("startAt", req.StartAt != "")
+	errs.require("endAt", req.EndAt != "")
+	if errs.respond(w) {
 		return
 	}
 
-	id, err := h.service.Create(r.Context(), uid, req.ResourceID, startAt, endAt)
+	// время без смещения — местное время ресурса
+	clock := newResourceClock(r.Context(), h.repo, req.ResourceID)
+	startAt, ok := parseRequestTime(w, clock, "startAt", req.StartAt)
+	if !ok {
+		return
+	}
+	endAt, ok := parseRequestTime(w, clock, "endAt", req.EndAt)
+	if !ok {
+		return
+	}
+
+	quantity := 1
+	if req.Quantity != nil {
+		quantity = *req.Quantity
+	}
+
+	id, status, err := h.service.Create(r.Context(), uid, req.ResourceID, startAt, endAt, quantity)
 	if err != nil {
-		if err == service.ErrConflict {
-			http.Error(w, err.Error(), http.StatusConflict)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeBookingError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, map[string]any{"id": id})
+	writeJSON(w, http.StatusCreated, apiv1.BookingCreated{ID: id, Status: status})
+}
+
+// writeBookingError — ответ на ошибку создания брони или удержания
+func writeBookingError(w http.ResponseWriter, err error) {
+	writeServiceError(w, err, "booking.save_failed")
 }
 
 // Менеджерская часть: список ожидающих
 func (h *BookingHandler) Pending(w http.ResponseWriter, r *http.Request) {
 	uid := GetUserID(r)
 	if uid == 0 {
-		http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
 		return
 	}
 
 	role, err := h.users.GetRoleByID(r.Context(), uid)
 	if err != nil {
-		http.Error(w, "Ошибка базы данных", http.StatusInternalServerError)
+		internalError(w, "db.error", err)
 		return
 	}
 
 	var items []domain.Booking
-	if role == domain.RoleAdmin {
+	if h.policy.Can(policy.Actor{UserID: uid, Role: role}, domain.PermBookingViewAll, policy.Target{}) {
 		items, err = h.repo.ListPending(r.Context())
 	} else {
 		items, err = h.repo.ListPendingForOwner(r.Context(), uid)
 	}
 	if err != nil {
-		http.Error(w, "Не удалось получить список: "+err.Error(), http.StatusInternalServerError)
+		internalError(w, "booking.list_failed", err)
 		return
 	}
 
@@ -140,131 +201,240 @@ func (h *BookingHandler) Pending(w http.ResponseWriter, r *http.Request) {
 		items = make([]domain.Booking, 0)
 	}
 
-	writeJSON(w, http.StatusOK, items)
+	writeJSON(w, http.StatusOK, apiv1.NewBookings(items))
 }
 
 type updateStatusReq struct {
-	Status         domain.BookingStatus `json:"status"` // APPROVED / REJECTED
-	ManagerComment *string              `json:"managerComment"`
+	Status         domain.BookingStatus `json:"status" validate:"required,oneof=APPROVED REJECTED"`
+	ManagerComment *string              `json:"managerComment" validate:"max=255"`
 }
 
 func (h *BookingHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	if strings.TrimSpace(idStr) == "" {
-		http.Error(w, "Нужен параметр id", http.StatusBadRequest)
+		writeFieldError(w, "id", "query.id_required")
 		return
 	}
 	id64, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		http.Error(w, "Некорректный id", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "request.invalid_id")
 		return
 	}
 
 	uid := GetUserID(r)
 	if uid == 0 {
-		http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
 		return
 	}
 
 	// owner ресурса по этой брони (ВАЖНО: передаём id64)
 	ownerID, err := h.repo.GetOwnerUserIDByBookingID(r.Context(), uint64(id64))
 	if err != nil {
-		http.Error(w, "Бронирование не найдено", http.StatusNotFound)
+		writeError(w, http.StatusNotFound, CodeBookingNotFound, "booking.not_found")
 		return
 	}
 
 	// роль текущего пользователя (из БД)
 	role, err := h.users.GetRoleByID(r.Context(), uid)
 	if err != nil {
-		http.Error(w, "Ошибка базы данных", http.StatusInternalServerError)
+		internalError(w, "db.error", err)
 		return
 	}
 
-	// admin может всё, остальные — только если владелец объявления
-	if role != domain.RoleAdmin && ownerID != uid {
-		http.Error(w, "Недостаточно прав: вы не владелец объявления", http.StatusForbidden)
+	allowed, err := h.canApprove(r.Context(), policy.Actor{UserID: uid, Role: role}, ownerID, uint64(id64))
+	if err != nil {
+		internalError(w, "db.error", err)
 		return
 	}
-
-	var req updateStatusReq
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Некорректный JSON", http.StatusBadRequest)
+	if !allowed {
+		writeError(w, http.StatusForbidden, CodeForbidden, "resource.not_owner")
 		return
 	}
 
-	if req.Status != domain.BookingApproved && req.Status != domain.BookingRejected {
-		http.Error(w, "status должен быть APPROVED или REJECTED", http.StatusBadRequest)
+	var req updateStatusReq
+	if !decodeJSON(w, r, &req) {
 		return
 	}
+	req.Status = domain.BookingStatus(strings.ToUpper(string(req.Status)))
 
 	b, err := h.repo.GetByID(r.Context(), uint64(id64))
 	if err != nil {
-		http.Error(w, "Ошибка базы: "+err.Error(), http.StatusInternalServerError)
+		internalError(w, "db.error", err)
 		return
 	}
 	if b == nil {
-		http.Error(w, "Бронирование не найдено", http.StatusNotFound)
+		writeError(w, http.StatusNotFound, CodeBookingNotFound, "booking.not_found")
 		return
 	}
 	if b.Status != domain.BookingPending {
-		http.Error(w, "Можно менять статус только у брони со статусом PENDING", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, CodeInvalidBookingStatus, "booking.not_pending")
 		return
 	}
 
-	if err := h.repo.UpdateStatus(r.Context(), uint64(id64), req.Status, req.ManagerComment); err != nil {
-		http.Error(w, "Не удалось обновить статус: "+err.Error(), http.StatusInternalServerError)
+	change := domain.BookingStatusChange{BookingID: b.ID, FromStatus: &b.Status, ToStatus: req.Status, ChangedBy: &uid, Comment: req.ManagerComment}
+	updated, err := h.repo.UpdateStatus(r.Context(), change)
+	if err != nil {
+		internalError(w, "booking.update_status_failed", err)
+		return
+	}
+	// между чтением и записью бронь успели отменить или автоподтвердить
+	if !updated {
+		writeError(w, http.StatusConflict, CodeInvalidBookingStatus, "booking.status_changed")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{"ok": true})
+	var released []uint64
+	if req.Status == domain.BookingRejected {
+		released = append(released, b.ResourceID)
+	}
+
+	// групповая бронь подтверждается, когда подтверждены все части; отказ по одной отменяет остальные
+	if b.GroupID != nil {
+		groupStatus, err := h.repo.SyncGroupStatus(r.Context(), *b.GroupID)
+		if err != nil {
+			internalError(w, "booking.update_group_failed", err)
+			return
+		}
+		if groupStatus == domain.BookingRejected && h.waitlist != nil {
+			g, err := h.repo.GetGroup(r.Context(), *b.GroupID)
+			if err != nil {
+				internalError(w, "db.error", err)
+				return
+			}
+			if g != nil {
+				for _, part := range g.Bookings {
+					released = append(released, part.ResourceID)
+				}
+			}
+		}
+	}
+	releaseSlots(r.Context(), h.waitlist, released...)
+
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
 }
 
 func (h *BookingHandler) Cancel(w http.ResponseWriter, r *http.Request) {
 	uid := GetUserID(r)
 	if uid == 0 {
-		http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
 		return
 	}
 
 	idStr := chi.URLParam(r, "id")
 	id64, err := strconv.ParseUint(strings.TrimSpace(idStr), 10, 64)
 	if err != nil {
-		http.Error(w, "Некорректный id", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "request.invalid_id")
 		return
 	}
 
 	b, err := h.repo.GetByID(r.Context(), uint64(id64))
 	if err != nil {
-		http.Error(w, "Ошибка базы: "+err.Error(), http.StatusInternalServerError)
+		internalError(w, "db.error", err)
 		return
 	}
 	if b == nil {
-		http.Error(w, "Бронирование не найдено", http.StatusNotFound)
+		writeError(w, http.StatusNotFound, CodeBookingNotFound, "booking.not_found")
 		return
 	}
 
-	// Только владелец брони
-	if b.UserID != uid {
-		http.Error(w, "Недостаточно прав", http.StatusForbidden)
+	// booking:cancel — по умолчанию только автор брони
+	if !h.policy.Can(actorFromRequest(r), domain.PermBookingCancel, policy.Target{BookerUserID: b.UserID}) {
+		writeError(w, http.StatusForbidden, CodeForbidden, "access.denied")
+		return
+	}
+
+	if b.GroupID != nil {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "booking.group_part_cancel")
 		return
 	}
 
 	// Можно отменять только PENDING/APPROVED
 	if b.Status != domain.BookingPending && b.Status != domain.BookingApproved {
-		http.Error(w, "Эту бронь нельзя отменить", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, CodeCancelNotAllowed, "booking.cancel_not_allowed")
 		return
 	}
 
 	// Правило: отмена возможна минимум за 2 часа
 	if time.Until(b.StartAt) < 2*time.Hour {
-		http.Error(w, "Отмена возможна не позднее чем за 2 часа до начала", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, CodeCancelNotAllowed, "booking.cancel_too_late")
 		return
 	}
 
-	if err := h.repo.Cancel(r.Context(), b.ID); err != nil {
-		http.Error(w, "Не удалось отменить бронь: "+err.Error(), http.StatusInternalServerError)
+	if err := h.repo.Cancel(r.Context(), b.ID, b.Status, uid); err != nil {
+		internalError(w, "booking.cancel_failed", err)
 		return
 	}
+	releaseSlots(r.Context(), h.waitlist, b.ResourceID)
+
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
 
-	writeJSON(w, http.StatusOK, map[string]any{"ok": true})
+// GET /api/bookings/{id}/history — смены статуса брони; автоподтверждение записано
+// без автора (changedByUserId = null). Видят автор брони и те, кто может её подтверждать.
+func (h *BookingHandler) History(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return
+	}
+
+	id64, err := strconv.ParseUint(strings.TrimSpace(chi.URLParam(r, "id")), 10, 64)
+	if err != nil || id64 == 0 {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "request.invalid_id")
+		return
+	}
+
+	b, err := h.repo.GetByID(r.Context(), id64)
+	if err != nil {
+		internalError(w, "db.error", err)
+		return
+	}
+	if b == nil {
+		writeError(w, http.StatusNotFound, CodeBookingNotFound, "booking.not_found")
+		return
+	}
+
+	if b.UserID != uid {
+		actor := actorFromRequest(r)
+		allowed := h.policy.Can(actor, domain.PermBookingViewAll, policy.Target{})
+		if !allowed {
+			ownerID, err := h.repo.GetOwnerUserIDByBookingID(r.Context(), b.ID)
+			if err != nil {
+				internalError(w, "db.error", err)
+				return
+			}
+			if allowed, err = h.canApprove(r.Context(), actor, ownerID, b.ID); err != nil {
+				internalError(w, "db.error", err)
+				return
+			}
+		}
+		if !allowed {
+			writeError(w, http.StatusForbidden, CodeForbidden, "access.denied")
+			return
+		}
+	}
+
+	items, err := h.repo.ListStatusHistory(r.Context(), b.ID)
+	if err != nil {
+		internalError(w, "booking.history_failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.NewBookingHistory(items))
+}
+
+// canApprove — право booking:approve: по роли, как владелец объявления
+// или как OWNER/MANAGER организации, которой принадлежит объявление
+func (h *BookingHandler) canApprove(ctx context.Context, actor policy.Actor, ownerID, bookingID uint64) (bool, error) {
+	target := policy.Target{OwnerUserID: ownerID}
+	if h.policy.Can(actor, domain.PermBookingApprove, target) {
+		return true, nil
+	}
+	isManager, err := h.repo.IsOrgManagerForBooking(ctx, bookingID, actor.UserID)
+	if err != nil {
+		return false, err
+	}
+	if isManager {
+		target.OrgRole = domain.OrgRoleManager
+	}
+	return h.policy.Can(actor, domain.PermBookingApprove, target), nil
 }
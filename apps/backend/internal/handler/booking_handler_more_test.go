@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"bookinghub-backend/internal/policy"
 	"bookinghub-backend/internal/repo"
 	"bookinghub-backend/internal/service"
 
@@ -38,7 +39,7 @@ func TestBookingHandler_My_Unauthorized(t *testing.T) {
 	bRepo := repo.NewBookingRepo(db)
 	uRepo := repo.NewUserRepo(db)
 	svc := service.NewBookingService(bRepo)
-	h := NewBookingHandler(bRepo, uRepo, svc)
+	h := NewBookingHandler(bRepo, uRepo, svc, policy.Default())
 
 	req := httptest.NewRequest(http.MethodGet, "/api/bookings/my", nil)
 	rr := httptest.NewRecorder()
@@ -57,9 +58,9 @@ func TestBookingHandler_My_OK(t *testing.T) {
 	bRepo := repo.NewBookingRepo(db)
 	uRepo := repo.NewUserRepo(db)
 	svc := service.NewBookingService(bRepo)
-	h := NewBookingHandler(bRepo, uRepo, svc)
+	h := NewBookingHandler(bRepo, uRepo, svc, policy.Default())
 
-	mock.ExpectQuery("SELECT id, resource_id, user_id, start_at, end_at, status, manager_comment, created_at, updated_at").
+	mock.ExpectQuery("SELECT id, resource_id, user_id, start_at, end_at, quantity, status, manager_comment, created_at, updated_at").
 		WithArgs(uint64(10)).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "resource_id", "user_id", "start_at", "end_at", "status", "manager_comment", "created_at", "updated_at",
@@ -90,7 +91,7 @@ func TestBookingHandler_UpdateStatus_Forbidden_NotOwner(t *testing.T) {
 	bRepo := repo.NewBookingRepo(db)
 	uRepo := repo.NewUserRepo(db)
 	svc := service.NewBookingService(bRepo)
-	h := NewBookingHandler(bRepo, uRepo, svc)
+	h := NewBookingHandler(bRepo, uRepo, svc, policy.Default())
 
 	// owner of booking -> 999, current user -> 10
 	mock.ExpectQuery("SELECT r.owner_user_id").
@@ -102,6 +103,11 @@ func TestBookingHandler_UpdateStatus_Forbidden_NotOwner(t *testing.T) {
 		WithArgs(uint64(10)).
 		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("INDIVIDUAL"))
 
+	// not a manager of the resource's organization
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM bookings b JOIN resources r").
+		WithArgs(uint64(1), uint64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+
 	body, _ := json.Marshal(map[string]any{
 		"status":         "APPROVED",
 		"managerComment": nil,
@@ -129,7 +135,7 @@ func TestBookingHandler_UpdateStatus_OK_Owner(t *testing.T) {
 	bRepo := repo.NewBookingRepo(db)
 	uRepo := repo.NewUserRepo(db)
 	svc := service.NewBookingService(bRepo)
-	h := NewBookingHandler(bRepo, uRepo, svc)
+	h := NewBookingHandler(bRepo, uRepo, svc, policy.Default())
 
 	// owner is current user
 	mock.ExpectQuery("SELECT r.owner_user_id").
@@ -142,7 +148,7 @@ func TestBookingHandler_UpdateStatus_OK_Owner(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("INDIVIDUAL"))
 
 	// booking exists and pending
-	mock.ExpectQuery("SELECT id, resource_id, user_id, start_at, end_at, status, manager_comment, created_at, updated_at").
+	mock.ExpectQuery("SELECT id, resource_id, user_id, start_at, end_at, quantity, status, manager_comment, created_at, updated_at").
 		WithArgs(uint64(7)).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "resource_id", "user_id", "start_at", "end_at", "status", "manager_comment", "created_at", "updated_at",
@@ -153,9 +159,14 @@ func TestBookingHandler_UpdateStatus_OK_Owner(t *testing.T) {
 		))
 
 	// update status
-	mock.ExpectExec("UPDATE bookings\\s+SET status = \\?, manager_comment = \\?\\s+WHERE id = \\?").
-		WithArgs("APPROVED", sqlmock.AnyArg(), uint64(7)).
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE bookings\\s+SET status = \\?, manager_comment = \\?\\s+WHERE id = \\? AND status = \\?").
+		WithArgs("APPROVED", sqlmock.AnyArg(), uint64(7), "PENDING").
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO booking_status_history \\(booking_id, from_status, to_status, changed_by_user_id, comment\\)").
+		WithArgs(uint64(7), "PENDING", "APPROVED", uint64(10), "ok").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	body, _ := json.Marshal(map[string]any{
 		"status":         "APPROVED",
@@ -180,6 +191,54 @@ func TestBookingHandler_UpdateStatus_OK_Owner(t *testing.T) {
 	}
 }
 
+func TestBookingHandler_UpdateStatus_ChangedMeanwhile_409(t *testing.T) {
+	db, mock, closeFn := newSQLXMock2Res(t)
+	defer closeFn()
+
+	bRepo := repo.NewBookingRepo(db)
+	h := NewBookingHandler(bRepo, repo.NewUserRepo(db), service.NewBookingService(bRepo), policy.Default())
+
+	mock.ExpectQuery("SELECT r.owner_user_id").
+		WithArgs(uint64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"owner_user_id"}).AddRow(uint64(10)))
+	mock.ExpectQuery("SELECT role FROM users").
+		WithArgs(uint64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("INDIVIDUAL"))
+	mock.ExpectQuery("SELECT id, resource_id, user_id, start_at, end_at, quantity, status, manager_comment, created_at, updated_at").
+		WithArgs(uint64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "resource_id", "user_id", "start_at", "end_at", "status", "manager_comment", "created_at", "updated_at",
+		}).AddRow(
+			uint64(7), uint64(2), uint64(55),
+			time.Now().Add(2*time.Hour), time.Now().Add(3*time.Hour),
+			"PENDING", nil, time.Now(), nil,
+		))
+
+	// после чтения бронь отменили: UPDATE не находит PENDING, истории нет
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE bookings\\s+SET status = \\?, manager_comment = \\?\\s+WHERE id = \\? AND status = \\?").
+		WithArgs("APPROVED", nil, uint64(7), "PENDING").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	body, _ := json.Marshal(map[string]any{"status": "APPROVED"})
+	req := httptest.NewRequest(http.MethodPatch, "/api/bookings/7/status", bytes.NewReader(body))
+	req = req.WithContext(withUIDBH(req.Context(), 10))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "7")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rr := httptest.NewRecorder()
+	h.UpdateStatus(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
 func TestBookingHandler_Cancel_OK(t *testing.T) {
 	db, mock, closeFn := newSQLXMock2Res(t)
 	defer closeFn()
@@ -187,12 +246,13 @@ func TestBookingHandler_Cancel_OK(t *testing.T) {
 	bRepo := repo.NewBookingRepo(db)
 	uRepo := repo.NewUserRepo(db)
 	svc := service.NewBookingService(bRepo)
-	h := NewBookingHandler(bRepo, uRepo, svc)
+	released := &fakeReleaser{}
+	h := NewBookingHandler(bRepo, uRepo, svc, policy.Default()).WithWaitlist(released)
 
 	start := time.Now().Add(5 * time.Hour)
 
 	// booking exists, belongs to user, status pending
-	mock.ExpectQuery("SELECT id, resource_id, user_id, start_at, end_at, status, manager_comment, created_at, updated_at").
+	mock.ExpectQuery("SELECT id, resource_id, user_id, start_at, end_at, quantity, status, manager_comment, created_at, updated_at").
 		WithArgs(uint64(3)).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "resource_id", "user_id", "start_at", "end_at", "status", "manager_comment", "created_at", "updated_at",
@@ -202,9 +262,14 @@ func TestBookingHandler_Cancel_OK(t *testing.T) {
 			"PENDING", nil, time.Now(), nil,
 		))
 
+	mock.ExpectBegin()
 	mock.ExpectExec("UPDATE bookings\\s+SET status = 'CANCELED'\\s+WHERE id = \\?").
 		WithArgs(uint64(3)).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO booking_status_history \\(booking_id, from_status, to_status, changed_by_user_id, comment\\)").
+		WithArgs(uint64(3), "PENDING", "CANCELED", uint64(10), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	req := httptest.NewRequest(http.MethodPost, "/api/bookings/3/cancel", nil)
 	req = req.WithContext(withUIDBH(req.Context(), 10))
@@ -219,6 +284,143 @@ func TestBookingHandler_Cancel_OK(t *testing.T) {
 	if rr.Code != http.StatusOK {
 		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
 	}
+	if len(released.resourceIDs) != 1 || released.resourceIDs[0] != 2 {
+		t.Fatalf("canceled booking must free its slot for the waitlist, got %v", released.resourceIDs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestBookingHandler_UpdateStatus_OK_OrgManager(t *testing.T) {
+	db, mock, closeFn := newSQLXMock2Res(t)
+	defer closeFn()
+
+	bRepo := repo.NewBookingRepo(db)
+	uRepo := repo.NewUserRepo(db)
+	svc := service.NewBookingService(bRepo)
+	h := NewBookingHandler(bRepo, uRepo, svc, policy.Default())
+
+	// resource created by another employee of the same organization
+	mock.ExpectQuery("SELECT r.owner_user_id").
+		WithArgs(uint64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"owner_user_id"}).AddRow(uint64(999)))
+
+	mock.ExpectQuery("SELECT role FROM users").
+		WithArgs(uint64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("COMPANY"))
+
+	// current user is MANAGER of the resource's organization
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM bookings b JOIN resources r").
+		WithArgs(uint64(7), uint64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
+
+	mock.ExpectQuery("SELECT id, resource_id, user_id, start_at, end_at, quantity, status, manager_comment, created_at, updated_at").
+		WithArgs(uint64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "resource_id", "user_id", "start_at", "end_at", "status", "manager_comment", "created_at", "updated_at",
+		}).AddRow(
+			uint64(7), uint64(2), uint64(55),
+			time.Now().Add(2*time.Hour), time.Now().Add(3*time.Hour),
+			"PENDING", nil, time.Now(), nil,
+		))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE bookings\\s+SET status = \\?, manager_comment = \\?\\s+WHERE id = \\? AND status = \\?").
+		WithArgs("REJECTED", sqlmock.AnyArg(), uint64(7), "PENDING").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO booking_status_history \\(booking_id, from_status, to_status, changed_by_user_id, comment\\)").
+		WithArgs(uint64(7), "PENDING", "REJECTED", uint64(10), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	body, _ := json.Marshal(map[string]any{"status": "REJECTED"})
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/bookings/7/status", bytes.NewReader(body))
+	req = req.WithContext(withUIDBH(req.Context(), 10))
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "7")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rr := httptest.NewRecorder()
+	h.UpdateStatus(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func historyRequest(uid uint64) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/bookings/7/history", nil)
+	req = req.WithContext(withUIDBH(req.Context(), uid))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "7")
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func expectBooking7(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT id, resource_id, user_id, start_at, end_at, quantity, status, manager_comment, created_at, updated_at").
+		WithArgs(uint64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "resource_id", "user_id", "start_at", "end_at", "status", "created_at"}).
+			AddRow(uint64(7), uint64(2), uint64(55), time.Now().Add(2*time.Hour), time.Now().Add(3*time.Hour), "APPROVED", time.Now()))
+}
+
+func TestBookingHandler_History_Booker_SeesSystemApproval(t *testing.T) {
+	db, mock, closeFn := newSQLXMock2Res(t)
+	defer closeFn()
+
+	bRepo := repo.NewBookingRepo(db)
+	h := NewBookingHandler(bRepo, repo.NewUserRepo(db), service.NewBookingService(bRepo), policy.Default())
+
+	expectBooking7(mock)
+	mock.ExpectQuery("SELECT id, booking_id, from_status, to_status, changed_by_user_id, comment, created_at FROM booking_status_history WHERE booking_id = \\? ORDER BY id ASC").
+		WithArgs(uint64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "booking_id", "from_status", "to_status", "changed_by_user_id", "comment", "created_at"}).
+			AddRow(uint64(1), uint64(7), "PENDING", "APPROVED", nil, "Автоподтверждение: режим ресурса", time.Now()))
+
+	rr := httptest.NewRecorder()
+	h.History(rr, historyRequest(55))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	var items []map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &items); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if len(items) != 1 || items[0]["changedByUserId"] != nil || items[0]["toStatus"] != "APPROVED" {
+		t.Fatalf("unexpected history %v", items)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestBookingHandler_History_Stranger_Forbidden(t *testing.T) {
+	db, mock, closeFn := newSQLXMock2Res(t)
+	defer closeFn()
+
+	bRepo := repo.NewBookingRepo(db)
+	h := NewBookingHandler(bRepo, repo.NewUserRepo(db), service.NewBookingService(bRepo), policy.Default())
+
+	expectBooking7(mock)
+	mock.ExpectQuery("SELECT r.owner_user_id FROM bookings b JOIN resources r").
+		WithArgs(uint64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"owner_user_id"}).AddRow(uint64(10)))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM bookings b JOIN resources r ON r.id = b.resource_id JOIN organization_members m").
+		WithArgs(uint64(7), uint64(99)).
+		WillReturnRows(sqlmock.NewRows([]string{"cnt"}).AddRow(0))
+
+	rr := httptest.NewRecorder()
+	h.History(rr, historyRequest(99))
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 got %d body=%s", rr.Code, rr.Body.String())
+	}
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Fatalf("unmet expectations: %v", err)
 	}
@@ -15,6 +15,7 @@ import (
 	"github.com/jmoiron/sqlx"
 
 	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/policy"
 	"bookinghub-backend/internal/repo"
 	"bookinghub-backend/internal/service"
 )
@@ -48,6 +49,46 @@ func (m timeEq) Match(v driver.Value) bool {
 	return t.UTC().Round(0).Equal(m.want.UTC().Round(0))
 }
 
+var bookingCols = []string{
+	"id", "resource_id", "user_id", "start_at", "end_at", "quantity", "status", "manager_comment", "created_at", "updated_at",
+}
+
+// expectAvailability ожидает проверку свободных единиц: вместимость ресурса, пересекающиеся брони и удержания.
+// Возвращает строки броней, чтобы тест мог их добавить.
+func expectAvailability(mock sqlmock.Sqlmock, resourceID uint64, capacity int, start, end time.Time) *sqlmock.Rows {
+	mock.ExpectQuery("SELECT capacity, buffer_before_min, buffer_after_min FROM resources WHERE id = \\?").
+		WithArgs(resourceID).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity", "buffer_before_min", "buffer_after_min"}).AddRow(capacity, 0, 0))
+	rows := sqlmock.NewRows(bookingCols)
+	mock.ExpectQuery("FROM bookings WHERE resource_id = \\? AND status IN \\('PENDING','APPROVED'\\) AND start_at < \\? AND end_at > \\?").
+		WithArgs(resourceID, timeEq{end}, timeEq{start}).
+		WillReturnRows(rows)
+	mock.ExpectQuery("FROM booking_holds WHERE resource_id = \\? AND expires_at > \\? AND start_at < \\? AND end_at > \\?").
+		WithArgs(resourceID, sqlmock.AnyArg(), timeEq{end}, timeEq{start}).
+		WillReturnRows(sqlmock.NewRows(holdCols))
+	mock.ExpectQuery("FROM waitlist_entries WHERE resource_id = \\? AND status = 'OFFERED' AND claim_expires_at > \\? AND start_at < \\? AND end_at > \\?").
+		WithArgs(resourceID, sqlmock.AnyArg(), timeEq{end}, timeEq{start}).
+		WillReturnRows(sqlmock.NewRows(waitlistCols))
+	return rows
+}
+
+// expectResourceLock ожидает начало транзакции брони и блокировку строки ресурса
+func expectResourceLock(mock sqlmock.Sqlmock, resourceID uint64) {
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM resources WHERE id IN \\(\\?\\) ORDER BY id FOR UPDATE").
+		WithArgs(resourceID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(resourceID))
+}
+
+// expectCreatedHistory ожидает первую запись истории новой брони: PENDING от её автора
+func expectCreatedHistory(mock sqlmock.Sqlmock, bookingID, userID uint64) {
+	mock.ExpectExec("INSERT INTO booking_status_history \\(booking_id, from_status, to_status, changed_by_user_id, comment\\)").
+		WithArgs(bookingID, nil, "PENDING", userID, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+var holdCols = []string{"id", "token", "resource_id", "user_id", "start_at", "end_at", "quantity", "expires_at", "created_at"}
+
 func TestBookingHandler_Create_BadJSON(t *testing.T) {
 	db, mock, cleanup := newMockHandlerDB(t)
 	defer cleanup()
@@ -55,7 +96,7 @@ func TestBookingHandler_Create_BadJSON(t *testing.T) {
 	bookingRepo := repo.NewBookingRepo(db)
 	userRepo := repo.NewUserRepo(db)
 	svc := service.NewBookingService(bookingRepo)
-	h := NewBookingHandler(bookingRepo, userRepo, svc)
+	h := NewBookingHandler(bookingRepo, userRepo, svc, policy.Default())
 
 	req := httptest.NewRequest("POST", "/api/bookings", bytes.NewBufferString("{bad"))
 	req = withUID(req, 1)
@@ -75,10 +116,10 @@ func TestBookingHandler_Create_Conflict(t *testing.T) {
 	bookingRepo := repo.NewBookingRepo(db)
 	userRepo := repo.NewUserRepo(db)
 	svc := service.NewBookingService(bookingRepo)
-	h := NewBookingHandler(bookingRepo, userRepo, svc)
+	h := NewBookingHandler(bookingRepo, userRepo, svc, policy.Default())
 
-	// fixed time for deterministic tests
-	start := time.Date(2025, 12, 29, 17, 47, 54, 0, time.UTC)
+	// start in the future: the service rejects bookings in the past
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Second).UTC()
 	end := start.Add(time.Hour)
 
 	body, _ := json.Marshal(map[string]any{
@@ -87,16 +128,11 @@ func TestBookingHandler_Create_Conflict(t *testing.T) {
 		"endAt":      end.Format(time.RFC3339),
 	})
 
-	// service.Create -> repo.HasConflict -> COUNT(*) = 1
-	mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT COUNT(*)
-		FROM bookings
-		WHERE resource_id = ?
-		  AND status IN ('PENDING','APPROVED')
-		  AND (? < end_at) AND (? > start_at)
-	`)).
-		WithArgs(uint64(99), timeEq{start}, timeEq{end}).
-		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
+	// service.Create -> вместимость 1 и одна пересекающаяся бронь
+	expectResourceLock(mock, 99)
+	expectAvailability(mock, 99, 1, start, end).
+		AddRow(uint64(1), uint64(99), uint64(3), start, end, 1, "APPROVED", nil, start, nil)
+	mock.ExpectRollback()
 
 	req := httptest.NewRequest("POST", "/api/bookings", bytes.NewReader(body))
 	req = withUID(req, 7)
@@ -119,10 +155,10 @@ func TestBookingHandler_Create_OK(t *testing.T) {
 	bookingRepo := repo.NewBookingRepo(db)
 	userRepo := repo.NewUserRepo(db)
 	svc := service.NewBookingService(bookingRepo)
-	h := NewBookingHandler(bookingRepo, userRepo, svc)
+	h := NewBookingHandler(bookingRepo, userRepo, svc, policy.Default())
 
-	// fixed time for deterministic tests
-	start := time.Date(2025, 12, 29, 17, 47, 54, 0, time.UTC)
+	// start in the future: the service rejects bookings in the past
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Second).UTC()
 	end := start.Add(time.Hour)
 
 	body, _ := json.Marshal(map[string]any{
@@ -132,23 +168,18 @@ func TestBookingHandler_Create_OK(t *testing.T) {
 	})
 
 	// no conflict
-	mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT COUNT(*)
-		FROM bookings
-		WHERE resource_id = ?
-		  AND status IN ('PENDING','APPROVED')
-		  AND (? < end_at) AND (? > start_at)
-	`)).
-		WithArgs(uint64(99), timeEq{start}, timeEq{end}).
-		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+	expectResourceLock(mock, 99)
+	expectAvailability(mock, 99, 1, start, end)
 
 	// insert booking
 	mock.ExpectExec(regexp.QuoteMeta(`
-		INSERT INTO bookings (resource_id, user_id, start_at, end_at, status)
-		VALUES (?, ?, ?, ?, 'PENDING')
+		INSERT INTO bookings (resource_id, user_id, start_at, end_at, quantity, status)
+		VALUES (?, ?, ?, ?, ?, 'PENDING')
 	`)).
-		WithArgs(uint64(99), uint64(7), timeEq{start}, timeEq{end}).
+		WithArgs(uint64(99), uint64(7), timeEq{start}, timeEq{end}, 1).
 		WillReturnResult(sqlmock.NewResult(555, 1))
+	expectCreatedHistory(mock, 555, 7)
+	mock.ExpectCommit()
 
 	req := httptest.NewRequest("POST", "/api/bookings", bytes.NewReader(body))
 	req = withUID(req, 7)
@@ -164,6 +195,37 @@ func TestBookingHandler_Create_OK(t *testing.T) {
 	}
 }
 
+func TestBookingHandler_Create_ResourceNotFound(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	bookingRepo := repo.NewBookingRepo(db)
+	h := NewBookingHandler(bookingRepo, repo.NewUserRepo(db), service.NewBookingService(bookingRepo), policy.Default())
+
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Second).UTC()
+	body, _ := json.Marshal(map[string]any{
+		"resourceId": 99,
+		"startAt":    start.Format(time.RFC3339),
+		"endAt":      start.Add(time.Hour).Format(time.RFC3339),
+	})
+
+	// блокировка не нашла строку ресурса — до проверки занятости дело не доходит
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM resources WHERE id IN \\(\\?\\) ORDER BY id FOR UPDATE").
+		WithArgs(uint64(99)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	rr := httptest.NewRecorder()
+	h.Create(rr, withUID(httptest.NewRequest("POST", "/api/bookings", bytes.NewReader(body)), 7))
+	if rr.Code != 404 {
+		t.Fatalf("expected 404 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
 func TestBookingHandler_Pending_Admin(t *testing.T) {
 	db, mock, cleanup := newMockHandlerDB(t)
 	defer cleanup()
@@ -171,7 +233,7 @@ func TestBookingHandler_Pending_Admin(t *testing.T) {
 	bookingRepo := repo.NewBookingRepo(db)
 	userRepo := repo.NewUserRepo(db)
 	svc := service.NewBookingService(bookingRepo)
-	h := NewBookingHandler(bookingRepo, userRepo, svc)
+	h := NewBookingHandler(bookingRepo, userRepo, svc, policy.Default())
 
 	// GetRoleByID -> ADMIN
 	mock.ExpectQuery(regexp.QuoteMeta(`
@@ -186,7 +248,7 @@ func TestBookingHandler_Pending_Admin(t *testing.T) {
 	// ListPending
 	now := time.Date(2025, 12, 29, 12, 0, 0, 0, time.UTC)
 	mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT id, resource_id, user_id, start_at, end_at, status, manager_comment, created_at, updated_at
+		SELECT id, resource_id, user_id, start_at, end_at, quantity, status, manager_comment, created_at, updated_at, group_id, bundle_id
 		FROM bookings
 		WHERE status = 'PENDING'
 		ORDER BY start_at ASC
@@ -208,3 +270,74 @@ func TestBookingHandler_Pending_Admin(t *testing.T) {
 		t.Fatalf("expectations: %v", err)
 	}
 }
+
+func TestBookingHandler_Create_QuantityFitsRemainingUnits(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	bookingRepo := repo.NewBookingRepo(db)
+	svc := service.NewBookingService(bookingRepo)
+	h := NewBookingHandler(bookingRepo, repo.NewUserRepo(db), svc, policy.Default())
+
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Second).UTC()
+	end := start.Add(time.Hour)
+
+	// 30 рабочих мест, 25 уже заняты — 5 свободно
+	expectResourceLock(mock, 99)
+	expectAvailability(mock, 99, 30, start, end).
+		AddRow(uint64(1), uint64(99), uint64(3), start, end, 20, "APPROVED", nil, start, nil).
+		AddRow(uint64(2), uint64(99), uint64(4), start, end, 5, "PENDING", nil, start, nil)
+	mock.ExpectExec("INSERT INTO bookings").
+		WithArgs(uint64(99), uint64(7), timeEq{start}, timeEq{end}, 5).
+		WillReturnResult(sqlmock.NewResult(556, 1))
+	expectCreatedHistory(mock, 556, 7)
+	mock.ExpectCommit()
+	expectResourceLock(mock, 99)
+	expectAvailability(mock, 99, 30, start, end).
+		AddRow(uint64(1), uint64(99), uint64(3), start, end, 30, "APPROVED", nil, start, nil)
+	mock.ExpectRollback()
+
+	for _, tc := range []struct {
+		quantity int
+		want     int
+	}{{5, 201}, {1, 409}, {0, 400}} {
+		body, _ := json.Marshal(map[string]any{
+			"resourceId": 99,
+			"startAt":    start.Format(time.RFC3339),
+			"endAt":      end.Format(time.RFC3339),
+			"quantity":   tc.quantity,
+		})
+		req := withUID(httptest.NewRequest("POST", "/api/bookings", bytes.NewReader(body)), 7)
+		rr := httptest.NewRecorder()
+
+		h.Create(rr, req)
+		if rr.Code != tc.want {
+			t.Fatalf("quantity=%d: expected %d got %d body=%s", tc.quantity, tc.want, rr.Code, rr.Body.String())
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBookingCreate_MissingResourceID_400(t *testing.T) {
+	db, _, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	bookings := repo.NewBookingRepo(db)
+	h := NewBookingHandler(bookings, repo.NewUserRepo(db), service.NewBookingService(bookings), nil)
+
+	body := `{"startAt":"2030-01-01T10:00:00Z","endAt":"2030-01-01T11:00:00Z"}`
+	req := withUID(httptest.NewRequest(http.MethodPost, "/api/bookings", bytes.NewBufferString(body)), 7)
+	rr := httptest.NewRecorder()
+	h.Create(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	got := decodeAPIError(t, rr)
+	if got.Code != CodeValidationFailed || len(got.Details) != 1 || got.Details[0].Field != "resourceId" {
+		t.Fatalf("unexpected error: %+v", got)
+	}
+}
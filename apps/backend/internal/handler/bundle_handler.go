@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/apiv1"
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/policy"
+	"bookinghub-backend/internal/repo"
+)
+
+type BundleHandler struct {
+	bundles   *repo.BundleRepo
+	resources *repo.ResourceRepo
+	orgs      orgMembership
+	policy    *policy.Policy
+}
+
+func NewBundleHandler(bundles *repo.BundleRepo, resources *repo.ResourceRepo, orgs orgMembership, policy *policy.Policy) *BundleHandler {
+	return &BundleHandler{bundles: bundles, resources: resources, orgs: orgs, policy: policy}
+}
+
+// GET /api/bundles — активные наборы
+func (h *BundleHandler) List(w http.ResponseWriter, r *http.Request) {
+	items, err := h.bundles.ListActive(r.Context())
+	if err != nil {
+		internalError(w, "bundle.list_failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.NewBundles(items))
+}
+
+// GET /api/bundles/{id}
+func (h *BundleHandler) Get(w http.ResponseWriter, r *http.Request) {
+	b, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.NewBundle(*b))
+}
+
+type createBundleReq struct {
+	Title       string  `json:"title" validate:"trim,required,max=255"`
+	Description *string `json:"description" validate:"max=16000"` // maxTextLen
+	Items       []struct {
+		ResourceID uint64 `json:"resourceId" validate:"required"`
+		Quantity   *int   `json:"quantity" validate:"min=1"` // по умолчанию 1
+	} `json:"items" validate:"required,max=20"` // service.MaxGroupItems
+}
+
+// POST /api/bundles — набор из ресурсов, которые текущий пользователь может редактировать.
+// Все ресурсы должны принадлежать одному владельцу (и одной организации): набор подтверждает один владелец.
+func (h *BundleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return
+	}
+
+	var req createBundleReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	var errs fieldErrors
+	seen := map[uint64]bool{}
+	for i, it := range req.Items {
+		if seen[it.ResourceID] {
+			errs.add(fmt.Sprintf("items[%d].resourceId", i), "validation.duplicate")
+		}
+		seen[it.ResourceID] = true
+	}
+	if errs.respond(w) {
+		return
+	}
+
+	bundle := domain.ResourceBundle{Title: req.Title, Description: req.Description}
+	for i, it := range req.Items {
+		res, err := h.resources.GetByID(r.Context(), it.ResourceID)
+		if err != nil {
+			internalError(w, "db.error", err)
+			return
+		}
+		if res == nil {
+			writeErrorf(w, http.StatusBadRequest, CodeValidationFailed, "bundle.resource_not_found", it.ResourceID)
+			return
+		}
+		ok, err := canEditResource(r.Context(), h.policy, h.orgs, res, actorFromRequest(r))
+		if err != nil {
+			internalError(w, "db.error", err)
+			return
+		}
+		if !ok {
+			writeErrorf(w, http.StatusForbidden, CodeForbidden, "bundle.resource_forbidden", res.ID)
+			return
+		}
+
+		if i == 0 {
+			bundle.OwnerUserID, bundle.OrganizationID = res.OwnerUserID, res.OrganizationID
+		} else if res.OwnerUserID != bundle.OwnerUserID || !sameOrg(res.OrganizationID, bundle.OrganizationID) {
+			writeError(w, http.StatusBadRequest, CodeValidationFailed, "bundle.mixed_owners")
+			return
+		}
+
+		quantity := 1
+		if it.Quantity != nil {
+			quantity = *it.Quantity
+		}
+		if quantity > res.Capacity {
+			writeErrorf(w, http.StatusBadRequest, CodeValidationFailed, "bundle.invalid_quantity", res.ID, res.Capacity)
+			return
+		}
+		bundle.Items = append(bundle.Items, domain.BundleItem{ResourceID: res.ID, Quantity: quantity})
+	}
+
+	id, err := h.bundles.Create(r.Context(), bundle)
+	if err != nil {
+		internalError(w, "bundle.create_failed", err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, apiv1.ID{ID: id})
+}
+
+// DELETE /api/bundles/{id} — брони, уже оформленные на набор, остаются
+func (h *BundleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if GetUserID(r) == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return
+	}
+	b, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	owner := &domain.Resource{OwnerUserID: b.OwnerUserID, OrganizationID: b.OrganizationID}
+	allowed, err := canEditResource(r.Context(), h.policy, h.orgs, owner, actorFromRequest(r))
+	if err != nil {
+		internalError(w, "db.error", err)
+		return
+	}
+	if !allowed {
+		writeError(w, http.StatusForbidden, CodeForbidden, "access.denied")
+		return
+	}
+
+	if err := h.bundles.Delete(r.Context(), b.ID); err != nil {
+		internalError(w, "bundle.delete_failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+func (h *BundleHandler) load(w http.ResponseWriter, r *http.Request) (*domain.ResourceBundle, bool) {
+	id64, err := strconv.ParseUint(strings.TrimSpace(chi.URLParam(r, "id")), 10, 64)
+	if err != nil || id64 == 0 {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "request.invalid_id")
+		return nil, false
+	}
+	b, err := h.bundles.GetByID(r.Context(), id64)
+	if err != nil {
+		internalError(w, "db.error", err)
+		return nil, false
+	}
+	if b == nil {
+		writeError(w, http.StatusNotFound, CodeNotFound, "bundle.not_found")
+		return nil, false
+	}
+	return b, true
+}
+
+func sameOrg(a, b *uint64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
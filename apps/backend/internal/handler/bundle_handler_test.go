@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/policy"
+	"bookinghub-backend/internal/repo"
+)
+
+func bundleRouter(h *BundleHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/api/bundles", h.Create)
+	r.Delete("/api/bundles/{id}", h.Delete)
+	return r
+}
+
+func newBundleTestHandler(t *testing.T) (*BundleHandler, sqlmock.Sqlmock) {
+	t.Helper()
+	dbx, mock, cleanup := newSQLXMock2(t)
+	t.Cleanup(cleanup)
+	return NewBundleHandler(repo.NewBundleRepo(dbx), repo.NewResourceRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default()), mock
+}
+
+// expectBundle ожидает загрузку набора owner с одним ресурсом
+func expectBundle(mock sqlmock.Sqlmock, id, owner uint64) {
+	mock.ExpectQuery("FROM resource_bundles WHERE id = \\?").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "owner_user_id", "organization_id", "title", "description", "is_active", "created_at"}).
+			AddRow(id, owner, nil, "Зал со звуком", nil, true, time.Now()))
+	mock.ExpectQuery("FROM resource_bundle_items WHERE bundle_id IN \\(\\?\\)").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"bundle_id", "resource_id", "quantity"}).AddRow(id, uint64(1), 1))
+}
+
+func serveBundle(h *BundleHandler, method, path, body string, uid uint64) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	if uid != 0 {
+		req = req.WithContext(withUIDRes(req.Context(), uid))
+	}
+	rr := httptest.NewRecorder()
+	bundleRouter(h).ServeHTTP(rr, req)
+	return rr
+}
+
+func TestBundleHandler_Create_201(t *testing.T) {
+	h, mock := newBundleTestHandler(t)
+
+	mock.ExpectQuery("FROM resources WHERE id = \\?").WithArgs(uint64(1)).WillReturnRows(resourceRow(1, 7, nil))
+	mock.ExpectQuery("FROM resources WHERE id = \\?").WithArgs(uint64(2)).WillReturnRows(resourceRow(2, 7, nil))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO resource_bundles \\(owner_user_id, organization_id, title, description\\)").
+		WithArgs(uint64(7), nil, "Зал с проектором", nil).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+	mock.ExpectExec("INSERT INTO resource_bundle_items").WithArgs(int64(9), uint64(1), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO resource_bundle_items").WithArgs(int64(9), uint64(2), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rr := serveBundle(h, http.MethodPost, "/api/bundles", `{"title":" Зал с проектором ","items":[{"resourceId":1},{"resourceId":2}]}`, 7)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBundleHandler_Create_ForeignResource_403(t *testing.T) {
+	h, mock := newBundleTestHandler(t)
+
+	mock.ExpectQuery("FROM resources WHERE id = \\?").WithArgs(uint64(1)).WillReturnRows(resourceRow(1, 7, nil))
+	mock.ExpectQuery("FROM resources WHERE id = \\?").WithArgs(uint64(2)).WillReturnRows(resourceRow(2, 8, nil))
+
+	rr := serveBundle(h, http.MethodPost, "/api/bundles", `{"title":"Чужой набор","items":[{"resourceId":1},{"resourceId":2}]}`, 7)
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBundleHandler_Create_Unauthorized_401(t *testing.T) {
+	h, _ := newBundleTestHandler(t)
+
+	rr := serveBundle(h, http.MethodPost, "/api/bundles", `{"title":"Набор","items":[{"resourceId":1}]}`, 0)
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestBundleHandler_Create_Validation_400(t *testing.T) {
+	cases := map[string]string{
+		"без названия":       `{"title":"  ","items":[{"resourceId":1}]}`,
+		"без состава":        `{"title":"Набор"}`,
+		"ресурс дважды":      `{"title":"Набор","items":[{"resourceId":1},{"resourceId":1}]}`,
+		"нулевое количество": `{"title":"Набор","items":[{"resourceId":1,"quantity":0}]}`,
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			h, mock := newBundleTestHandler(t)
+
+			rr := serveBundle(h, http.MethodPost, "/api/bundles", body, 7)
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400 got %d body=%s", rr.Code, rr.Body.String())
+			}
+			// до БД дело не доходит
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf("expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestBundleHandler_Create_QuantityOverCapacity_400(t *testing.T) {
+	h, mock := newBundleTestHandler(t)
+
+	// у resourceRow вместимость 1
+	mock.ExpectQuery("FROM resources WHERE id = \\?").WithArgs(uint64(1)).WillReturnRows(resourceRow(1, 7, nil))
+
+	rr := serveBundle(h, http.MethodPost, "/api/bundles", `{"title":"Набор","items":[{"resourceId":1,"quantity":2}]}`, 7)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBundleHandler_Create_UnknownResource_400(t *testing.T) {
+	h, mock := newBundleTestHandler(t)
+
+	mock.ExpectQuery("FROM resources WHERE id = \\?").WithArgs(uint64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	rr := serveBundle(h, http.MethodPost, "/api/bundles", `{"title":"Набор","items":[{"resourceId":5}]}`, 7)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestBundleHandler_Delete_OK(t *testing.T) {
+	h, mock := newBundleTestHandler(t)
+
+	expectBundle(mock, 9, 7)
+	mock.ExpectExec("DELETE FROM resource_bundles WHERE id = \\?").WithArgs(uint64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	rr := serveBundle(h, http.MethodDelete, "/api/bundles/9", "", 7)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBundleHandler_Delete_Foreign_403(t *testing.T) {
+	h, mock := newBundleTestHandler(t)
+
+	expectBundle(mock, 9, 8)
+
+	rr := serveBundle(h, http.MethodDelete, "/api/bundles/9", "", 7)
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBundleHandler_Delete_NotFound_404(t *testing.T) {
+	h, mock := newBundleTestHandler(t)
+
+	mock.ExpectQuery("FROM resource_bundles WHERE id = \\?").WithArgs(uint64(9)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	rr := serveBundle(h, http.MethodDelete, "/api/bundles/9", "", 7)
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
@@ -1,14 +1,22 @@
 package handler
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	"bookinghub-backend/internal/apiv1"
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/i18n"
 	"bookinghub-backend/internal/repo"
+	"bookinghub-backend/internal/service"
 )
 
 type CategoryHandler struct {
@@ -19,82 +27,484 @@ func NewCategoryHandler(repo *repo.CategoryRepo) *CategoryHandler {
 	return &CategoryHandler{repo: repo}
 }
 
+// GET /api/categories?includeArchived=true
 func (h *CategoryHandler) List(w http.ResponseWriter, r *http.Request) {
+	items, ok := h.catalogue(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.NewCategories(items))
+}
+
+// GET /api/categories/tree?includeArchived=true
+func (h *CategoryHandler) Tree(w http.ResponseWriter, r *http.Request) {
+	items, ok := h.catalogue(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.NewCategoryTree(service.BuildCategoryTree(items)))
+}
+
+// catalogue — категории для каталога: архивные (и их подкатегории) скрыты, если не попросили иначе
+func (h *CategoryHandler) catalogue(w http.ResponseWriter, r *http.Request) ([]domain.Category, bool) {
+	items, err := h.repo.List(r.Context())
+	if err != nil {
+		internalError(w, "category.list_failed", err)
+		return nil, false
+	}
+	if r.URL.Query().Get("includeArchived") == "true" {
+		return items, true
+	}
+	return service.ActiveCategories(items), true
+}
+
+// GET /api/categories/{id} — категория, хлебные крошки от корня и атрибуты с учётом унаследованных
+func (h *CategoryHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id64, ok := categoryIDParam(w, r)
+	if !ok {
+		return
+	}
+
 	items, err := h.repo.List(r.Context())
 	if err != nil {
-		http.Error(w, "failed to list categories: "+err.Error(), http.StatusInternalServerError)
+		internalError(w, "category.list_failed", err)
+		return
+	}
+	path := service.CategoryPath(items, id64)
+	if path == nil {
+		writeError(w, http.StatusNotFound, CodeNotFound, "category.not_found")
+		return
+	}
+
+	attrs, err := h.repo.ListAttributes(r.Context(), service.CategoryIDs(path))
+	if err != nil {
+		internalError(w, "attribute.list_failed", err)
 		return
 	}
-	writeJSON(w, http.StatusOK, items)
+
+	children := make([]domain.Category, 0)
+	for _, c := range items {
+		if c.ParentID != nil && *c.ParentID == id64 {
+			children = append(children, c)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.CategoryDetails{
+		Category:    apiv1.NewCategory(path[len(path)-1]),
+		Breadcrumbs: apiv1.NewCategories(path),
+		Children:    apiv1.NewCategories(children),
+		Attributes:  apiv1.NewCategoryAttributes(attrs),
+	})
 }
 
 type createCategoryReq struct {
-	Name string `json:"name"`
+	Name     string  `json:"name" validate:"trim,required,max=100"`
+	ParentID *uint64 `json:"parentId"`
 }
 
 func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req createCategoryReq
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Некорректный JSON", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	req.Name = strings.TrimSpace(req.Name)
-	if req.Name == "" {
-		http.Error(w, "name обязателен", http.StatusBadRequest)
-		return
+	if req.ParentID != nil {
+		items, err := h.repo.List(r.Context())
+		if err != nil {
+			internalError(w, "category.list_failed", err)
+			return
+		}
+		parentPath := service.CategoryPath(items, *req.ParentID)
+		if parentPath == nil {
+			writeError(w, http.StatusBadRequest, CodeValidationFailed, "category.parent_not_found")
+			return
+		}
+		if service.CategoryArchived(parentPath) {
+			writeError(w, http.StatusBadRequest, CodeValidationFailed, "category.parent_archived")
+			return
+		}
 	}
 
-	id, err := h.repo.Create(r.Context(), req.Name)
+	id, err := h.repo.Create(r.Context(), req.Name, req.ParentID)
 	if err != nil {
-		http.Error(w, "failed to create category: "+err.Error(), http.StatusInternalServerError)
+		internalError(w, "category.create_failed", err)
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, map[string]any{"id": id})
+	writeJSON(w, http.StatusCreated, apiv1.ID{ID: id})
+}
+
+type updateCategoryReq struct {
+	Name string `json:"name" validate:"trim,required,max=100"`
+	// ParentID: поле не передано — родитель не меняется, null — перенести в корень
+	ParentID json.RawMessage `json:"parentId"`
 }
 
 func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
-	idStr := chi.URLParam(r, "id")
-	id64, err := strconv.ParseUint(strings.TrimSpace(idStr), 10, 64)
-	if err != nil || id64 == 0 {
-		http.Error(w, "Некорректный id", http.StatusBadRequest)
+	id64, ok := categoryIDParam(w, r)
+	if !ok {
 		return
 	}
 
-	var req createCategoryReq
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Некорректный JSON", http.StatusBadRequest)
+	var req updateCategoryReq
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	name := strings.TrimSpace(req.Name)
-	if name == "" {
-		http.Error(w, "name обязателен", http.StatusBadRequest)
-		return
+	if len(req.ParentID) > 0 {
+		var parentID *uint64
+		if !bytes.Equal(bytes.TrimSpace(req.ParentID), []byte("null")) {
+			var pid uint64
+			if err := json.Unmarshal(req.ParentID, &pid); err != nil || pid == 0 {
+				writeFieldError(w, "parentId", "query.invalid_parent_id")
+				return
+			}
+			parentID = &pid
+		}
+
+		items, err := h.repo.List(r.Context())
+		if err != nil {
+			internalError(w, "category.list_failed", err)
+			return
+		}
+		if service.CategoryPath(items, id64) == nil {
+			writeError(w, http.StatusNotFound, CodeNotFound, "category.not_found")
+			return
+		}
+		if parentID != nil {
+			if service.CategoryPath(items, *parentID) == nil {
+				writeError(w, http.StatusBadRequest, CodeValidationFailed, "category.parent_not_found")
+				return
+			}
+			if service.WouldCreateCycle(items, id64, *parentID) {
+				writeError(w, http.StatusConflict, CodeConflict, "category.move_into_self")
+				return
+			}
+		}
+
+		if err := h.repo.SetParent(r.Context(), id64, parentID); err != nil {
+			internalError(w, "category.update_failed", err)
+			return
+		}
 	}
 
-	if err := h.repo.Update(r.Context(), uint64(id64), name); err != nil {
-		http.Error(w, "failed to update category: "+err.Error(), http.StatusInternalServerError)
+	if err := h.repo.Update(r.Context(), id64, req.Name); err != nil {
+		internalError(w, "category.update_failed", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{"ok": true})
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
 }
 
+// DELETE /api/categories/{id} — только пустая категория: без объявлений и подкатегорий.
+// Иначе 409 с количеством; такую категорию можно слить с другой (merge) или архивировать.
 func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	idStr := chi.URLParam(r, "id")
-	id64, err := strconv.ParseUint(strings.TrimSpace(idStr), 10, 64)
-	if err != nil || id64 == 0 {
-		http.Error(w, "Некорректный id", http.StatusBadRequest)
+	id64, ok := categoryIDParam(w, r)
+	if !ok {
 		return
 	}
 
-	if err := h.repo.Delete(r.Context(), uint64(id64)); err != nil {
-		// Частый кейс: на категорию есть ресурсы → FK не даст удалить
-		http.Error(w, "Не удалось удалить категорию (возможно, к ней привязаны объявления): "+err.Error(), http.StatusBadRequest)
+	items, err := h.repo.List(r.Context())
+	if err != nil {
+		internalError(w, "category.list_failed", err)
+		return
+	}
+	if service.CategoryPath(items, id64) == nil {
+		writeError(w, http.StatusNotFound, CodeNotFound, "category.not_found")
+		return
+	}
+	childCount := len(service.CategorySubtree(items, id64)) - 1
+
+	resourceCount, err := h.repo.CountResources(r.Context(), id64)
+	if err != nil {
+		internalError(w, "db.error", err)
+		return
+	}
+	if resourceCount > 0 || childCount > 0 {
+		writeJSON(w, http.StatusConflict, apiv1.CategoryNotEmpty{
+			Error:         i18n.T(responseLang(w), "category.not_empty"),
+			ResourceCount: resourceCount,
+			ChildCount:    childCount,
+		})
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), id64); err != nil {
+		log.Printf("failed to delete category %d: %v", id64, err)
+		internalError(w, "category.delete_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+// POST /api/categories/{id}/archive — скрыть из каталога вместе с подкатегориями
+func (h *CategoryHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	now := time.Now().UTC()
+	h.setArchived(w, r, &now)
+}
+
+// POST /api/categories/{id}/unarchive
+func (h *CategoryHandler) Unarchive(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, nil)
+}
+
+func (h *CategoryHandler) setArchived(w http.ResponseWriter, r *http.Request, at *time.Time) {
+	id64, ok := categoryIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	items, err := h.repo.List(r.Context())
+	if err != nil {
+		internalError(w, "category.list_failed", err)
+		return
+	}
+	if service.CategoryPath(items, id64) == nil {
+		writeError(w, http.StatusNotFound, CodeNotFound, "category.not_found")
+		return
+	}
+
+	if err := h.repo.SetArchived(r.Context(), id64, at); err != nil {
+		internalError(w, "category.update_failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.CategoryArchive{OK: true, ArchivedAt: at})
+}
+
+type mergeCategoryReq struct {
+	TargetID uint64 `json:"targetId" validate:"required"`
+}
+
+// POST /api/categories/{id}/merge — перенести объявления и подкатегории в targetId и удалить категорию
+func (h *CategoryHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	sourceID, ok := categoryIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req mergeCategoryReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	items, err := h.repo.List(r.Context())
+	if err != nil {
+		internalError(w, "category.list_failed", err)
+		return
+	}
+	if service.CategoryPath(items, sourceID) == nil {
+		writeError(w, http.StatusNotFound, CodeNotFound, "category.not_found")
+		return
+	}
+	targetPath := service.CategoryPath(items, req.TargetID)
+	if targetPath == nil {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "category.target_not_found")
+		return
+	}
+	if service.CategoryArchived(targetPath) {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "category.target_archived")
 		return
 	}
+	// сюда же попадает target == source
+	if service.WouldCreateCycle(items, sourceID, req.TargetID) {
+		writeError(w, http.StatusConflict, CodeConflict, "category.merge_into_self")
+		return
+	}
+
+	sourceAttrs, err := h.repo.ListAttributes(r.Context(), service.CategoryIDs(service.CategoryPath(items, sourceID)))
+	if err != nil {
+		internalError(w, "attribute.list_failed", err)
+		return
+	}
+	targetAttrs, err := h.repo.ListAttributes(r.Context(), service.CategoryIDs(targetPath))
+	if err != nil {
+		internalError(w, "attribute.list_failed", err)
+		return
+	}
+
+	attrMap, drop := service.MatchAttributes(sourceAttrs, targetAttrs)
+	result, err := h.repo.Merge(r.Context(), repo.CategoryMerge{
+		SourceID:            sourceID,
+		TargetID:            req.TargetID,
+		AttrMap:             attrMap,
+		DropAttrIDs:         drop,
+		AffectedCategoryIDs: append([]uint64{req.TargetID}, service.CategorySubtree(items, sourceID)[1:]...),
+	})
+	if errors.Is(err, repo.ErrCategoryNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "category.not_found")
+		return
+	}
+	if err != nil {
+		log.Printf("failed to merge category %d into %d: %v", sourceID, req.TargetID, err)
+		internalError(w, "category.merge_failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.NewCategoryMerge(result))
+}
+
+// GET /api/categories/{id}/attributes — собственные атрибуты категории (без унаследованных)
+func (h *CategoryHandler) ListAttributes(w http.ResponseWriter, r *http.Request) {
+	id64, ok := categoryIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	items, err := h.repo.ListAttributes(r.Context(), []uint64{id64})
+	if err != nil {
+		internalError(w, "attribute.list_failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.NewCategoryAttributes(items))
+}
+
+type attributeReq struct {
+	Code     string               `json:"code" validate:"trim,max=50"`
+	Name     string               `json:"name" validate:"trim,required,max=100"`
+	Type     domain.AttributeType `json:"type"`
+	Required bool                 `json:"required"`
+	Options  []string             `json:"options"`
+	Position int                  `json:"position"`
+}
+
+// POST /api/categories/{id}/attributes
+func (h *CategoryHandler) CreateAttribute(w http.ResponseWriter, r *http.Request) {
+	id64, ok := categoryIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req attributeReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	a := &domain.CategoryAttribute{
+		CategoryID: id64,
+		Code:       req.Code,
+		Name:       req.Name,
+		Type:       req.Type,
+		Required:   req.Required,
+		Options:    req.Options,
+		Position:   req.Position,
+	}
+	if err := service.ValidateAttributeDefinition(a); err != nil {
+		writeServiceError(w, err, "attribute.check_failed")
+		return
+	}
+
+	items, err := h.repo.List(r.Context())
+	if err != nil {
+		internalError(w, "category.list_failed", err)
+		return
+	}
+	path := service.CategoryPath(items, id64)
+	if path == nil {
+		writeError(w, http.StatusNotFound, CodeNotFound, "category.not_found")
+		return
+	}
+
+	// код должен быть уникален во всей ветке: у предков (наследуются сюда) и у потомков (унаследуют этот)
+	related := append(service.CategoryIDs(path), service.CategorySubtree(items, id64)[1:]...)
+	existing, err := h.repo.ListAttributes(r.Context(), related)
+	if err != nil {
+		internalError(w, "attribute.list_failed", err)
+		return
+	}
+	for _, e := range existing {
+		if e.Code == a.Code {
+			writeErrorf(w, http.StatusConflict, CodeConflict, "attribute.code_taken", a.Code)
+			return
+		}
+	}
+
+	newID, err := h.repo.CreateAttribute(r.Context(), a)
+	if err != nil {
+		internalError(w, "attribute.create_failed", err)
+		return
+	}
+	a.ID = newID
+	writeJSON(w, http.StatusCreated, apiv1.NewCategoryAttribute(*a))
+}
+
+// PATCH /api/categories/{id}/attributes/{attrId} — code и type не меняются
+func (h *CategoryHandler) UpdateAttribute(w http.ResponseWriter, r *http.Request) {
+	a, ok := h.attribute(w, r)
+	if !ok {
+		return
+	}
+
+	var req attributeReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if (req.Code != "" && req.Code != a.Code) || (req.Type != "" && req.Type != a.Type) {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "attribute.immutable")
+		return
+	}
+
+	a.Name = req.Name
+	a.Required = req.Required
+	a.Options = req.Options
+	a.Position = req.Position
+	if err := service.ValidateAttributeDefinition(a); err != nil {
+		writeServiceError(w, err, "attribute.check_failed")
+		return
+	}
+
+	if err := h.repo.UpdateAttribute(r.Context(), a); err != nil {
+		internalError(w, "attribute.update_failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.NewCategoryAttribute(*a))
+}
+
+// DELETE /api/categories/{id}/attributes/{attrId} — значения у объявлений удаляются вместе с атрибутом
+func (h *CategoryHandler) DeleteAttribute(w http.ResponseWriter, r *http.Request) {
+	a, ok := h.attribute(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteAttribute(r.Context(), a.CategoryID, a.ID); err != nil {
+		internalError(w, "attribute.delete_failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+func (h *CategoryHandler) attribute(w http.ResponseWriter, r *http.Request) (*domain.CategoryAttribute, bool) {
+	categoryID, ok := categoryIDParam(w, r)
+	if !ok {
+		return nil, false
+	}
+	attrID, err := strconv.ParseUint(strings.TrimSpace(chi.URLParam(r, "attrId")), 10, 64)
+	if err != nil || attrID == 0 {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "attribute.invalid_id")
+		return nil, false
+	}
+
+	a, err := h.repo.GetAttribute(r.Context(), categoryID, attrID)
+	if err != nil {
+		internalError(w, "db.error", err)
+		return nil, false
+	}
+	if a == nil {
+		writeError(w, http.StatusNotFound, CodeNotFound, "attribute.not_found")
+		return nil, false
+	}
+	return a, true
+}
+
+func categoryIDParam(w http.ResponseWriter, r *http.Request) (uint64, bool) {
+	id64, err := strconv.ParseUint(strings.TrimSpace(chi.URLParam(r, "id")), 10, 64)
+	if err != nil || id64 == 0 {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "request.invalid_id")
+		return 0, false
+	}
+	return id64, true
+}
 
-	writeJSON(w, http.StatusOK, map[string]any{"ok": true})
+// writeAttributeError — ошибки проверки атрибутов объявления
+func writeAttributeError(w http.ResponseWriter, err error) {
+	writeServiceError(w, err, "attribute.check_values_failed")
 }
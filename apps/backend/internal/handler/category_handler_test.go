@@ -2,6 +2,7 @@ package handler
 
 import (
 	"bytes"
+	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -10,8 +11,10 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
 	"github.com/jmoiron/sqlx"
 
+	"bookinghub-backend/internal/domain"
 	"bookinghub-backend/internal/repo"
 )
 
@@ -32,9 +35,9 @@ func TestCategoryHandler_List_OK(t *testing.T) {
 	h := NewCategoryHandler(repo.NewCategoryRepo(dbx))
 
 	now := time.Now()
-	mock.ExpectQuery("SELECT id, name, created_at FROM resource_categories ORDER BY name ASC").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at"}).
-			AddRow(uint64(1), "A", now))
+	mock.ExpectQuery("SELECT id, parent_id, name, archived_at, created_at FROM resource_categories ORDER BY name ASC").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id", "name", "archived_at", "created_at"}).
+			AddRow(uint64(1), nil, "A", nil, now))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/categories", nil)
 	rr := httptest.NewRecorder()
@@ -69,8 +72,8 @@ func TestCategoryHandler_Create_OK_201(t *testing.T) {
 
 	h := NewCategoryHandler(repo.NewCategoryRepo(dbx))
 
-	mock.ExpectExec("INSERT INTO resource_categories \\(name\\) VALUES \\(\\?\\)").
-		WithArgs("NewCat").
+	mock.ExpectExec("INSERT INTO resource_categories \\(name, parent_id\\) VALUES \\(\\?, \\?\\)").
+		WithArgs("NewCat", nil).
 		WillReturnResult(sqlmock.NewResult(9, 1))
 
 	body := map[string]any{"name": "NewCat"}
@@ -123,3 +126,332 @@ func TestCategoryHandler_Delete_FKError_400(t *testing.T) {
 	_, _ = dbx.Exec("DELETE FROM resource_categories WHERE id = ?", 1)
 	_ = mock.ExpectationsWereMet()
 }
+
+var categoryAttributeCols = []string{"id", "category_id", "code", "name", "type", "required", "options", "position", "created_at"}
+
+// expectCategories — дерево "Помещения" (1) → "Переговорные" (2), отдельная "Фототехника" (3)
+// и архивная "Старое" (4) с подкатегорией (5)
+func expectCategories(mock sqlmock.Sqlmock) {
+	now := time.Now()
+	mock.ExpectQuery("SELECT id, parent_id, name, archived_at, created_at FROM resource_categories").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id", "name", "archived_at", "created_at"}).
+			AddRow(uint64(2), uint64(1), "Переговорные", nil, now).
+			AddRow(uint64(1), nil, "Помещения", nil, now).
+			AddRow(uint64(4), nil, "Старое", now, now).
+			AddRow(uint64(5), uint64(4), "Старое/Прокат", nil, now).
+			AddRow(uint64(3), nil, "Фототехника", nil, now))
+}
+
+// expectCategoryAttributes — схема атрибутов; без аргументов у категории нет атрибутов
+func expectCategoryAttributes(mock sqlmock.Sqlmock, rows ...[]driver.Value) {
+	r := sqlmock.NewRows(categoryAttributeCols)
+	for _, row := range rows {
+		r.AddRow(row...)
+	}
+	mock.ExpectQuery("FROM category_attributes WHERE category_id IN").WillReturnRows(r)
+}
+
+func expectAttributeValues(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("FROM resource_attribute_values v JOIN category_attributes a").
+		WillReturnRows(sqlmock.NewRows([]string{"resource_id", "code", "type", "value"}))
+}
+
+func categoryRouter(h *CategoryHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/api/categories/tree", h.Tree)
+	r.Get("/api/categories/{id}", h.Get)
+	r.Patch("/api/categories/{id}", h.Update)
+	r.Post("/api/categories/{id}/attributes", h.CreateAttribute)
+	return r
+}
+
+func TestCategoryHandler_Tree_OK(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock3(t)
+	defer cleanup()
+
+	h := NewCategoryHandler(repo.NewCategoryRepo(dbx))
+	expectCategories(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/categories/tree", nil)
+	rr := httptest.NewRecorder()
+	categoryRouter(h).ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+
+	var tree []domain.CategoryNode
+	if err := json.Unmarshal(rr.Body.Bytes(), &tree); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(tree) != 2 || tree[0].ID != 1 || len(tree[0].Children) != 1 || tree[0].Children[0].ID != 2 {
+		t.Fatalf("unexpected tree: %+v", tree)
+	}
+}
+
+func TestCategoryHandler_Get_BreadcrumbsAndInheritedAttributes(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock3(t)
+	defer cleanup()
+
+	h := NewCategoryHandler(repo.NewCategoryRepo(dbx))
+	now := time.Now()
+	expectCategories(mock)
+	mock.ExpectQuery("FROM category_attributes WHERE category_id IN \\(\\?, \\?\\)").
+		WithArgs(uint64(1), uint64(2)).
+		WillReturnRows(sqlmock.NewRows(categoryAttributeCols).
+			AddRow(uint64(5), uint64(1), "capacity", "Вместимость", "int", true, nil, 0, now))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/categories/2", nil)
+	rr := httptest.NewRecorder()
+	categoryRouter(h).ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Breadcrumbs []domain.Category          `json:"breadcrumbs"`
+		Attributes  []domain.CategoryAttribute `json:"attributes"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Breadcrumbs) != 2 || resp.Breadcrumbs[0].Name != "Помещения" || resp.Breadcrumbs[1].ID != 2 {
+		t.Fatalf("unexpected breadcrumbs: %+v", resp.Breadcrumbs)
+	}
+	if len(resp.Attributes) != 1 || resp.Attributes[0].Code != "capacity" {
+		t.Fatalf("unexpected attributes: %+v", resp.Attributes)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestCategoryHandler_Update_MoveIntoOwnChild_409(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock3(t)
+	defer cleanup()
+
+	h := NewCategoryHandler(repo.NewCategoryRepo(dbx))
+	expectCategories(mock)
+
+	b, _ := json.Marshal(map[string]any{"name": "Помещения", "parentId": 2})
+	req := httptest.NewRequest(http.MethodPatch, "/api/categories/1", bytes.NewReader(b))
+	rr := httptest.NewRecorder()
+	categoryRouter(h).ServeHTTP(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestCategoryHandler_Update_MoveToRoot_OK(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock3(t)
+	defer cleanup()
+
+	h := NewCategoryHandler(repo.NewCategoryRepo(dbx))
+	expectCategories(mock)
+	mock.ExpectExec("UPDATE resource_categories SET parent_id = \\? WHERE id = \\?").
+		WithArgs(nil, uint64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE resource_categories SET name = \\? WHERE id = \\?").
+		WithArgs("Переговорные", uint64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	b, _ := json.Marshal(map[string]any{"name": "Переговорные", "parentId": nil})
+	req := httptest.NewRequest(http.MethodPatch, "/api/categories/2", bytes.NewReader(b))
+	rr := httptest.NewRecorder()
+	categoryRouter(h).ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestCategoryHandler_CreateAttribute_CodeTakenByParent_409(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock3(t)
+	defer cleanup()
+
+	h := NewCategoryHandler(repo.NewCategoryRepo(dbx))
+	expectCategories(mock)
+	expectCategoryAttributes(mock, []driver.Value{uint64(5), uint64(1), "capacity", "Вместимость", "int", false, nil, 0, time.Now()})
+
+	b, _ := json.Marshal(map[string]any{"code": "capacity", "name": "Мест", "type": "int"})
+	req := httptest.NewRequest(http.MethodPost, "/api/categories/2/attributes", bytes.NewReader(b))
+	rr := httptest.NewRecorder()
+	categoryRouter(h).ServeHTTP(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestCategoryHandler_CreateAttribute_Enum_201(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock3(t)
+	defer cleanup()
+
+	h := NewCategoryHandler(repo.NewCategoryRepo(dbx))
+	expectCategories(mock)
+	expectCategoryAttributes(mock)
+	mock.ExpectExec("INSERT INTO category_attributes \\(category_id, code, name, type, required, options, position\\)").
+		WithArgs(uint64(3), "lens_mount", "Байонет", domain.AttrEnum, true, `["EF","RF"]`, 0).
+		WillReturnResult(sqlmock.NewResult(11, 1))
+
+	b, _ := json.Marshal(map[string]any{"code": "lens_mount", "name": "Байонет", "type": "enum", "required": true, "options": []string{"EF", " RF "}})
+	req := httptest.NewRequest(http.MethodPost, "/api/categories/3/attributes", bytes.NewReader(b))
+	rr := httptest.NewRecorder()
+	categoryRouter(h).ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestCategoryHandler_Tree_HidesArchivedBranch(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock3(t)
+	defer cleanup()
+
+	h := NewCategoryHandler(repo.NewCategoryRepo(dbx))
+	expectCategories(mock)
+	expectCategories(mock)
+
+	for url, want := range map[string]int{"/api/categories": 3, "/api/categories?includeArchived=true": 5} {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		rr := httptest.NewRecorder()
+		h.List(rr, req)
+
+		var items []domain.Category
+		if err := json.Unmarshal(rr.Body.Bytes(), &items); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if len(items) != want {
+			t.Fatalf("%s: expected %d categories got %d", url, want, len(items))
+		}
+	}
+}
+
+func TestCategoryHandler_Delete_NotEmpty_409(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock3(t)
+	defer cleanup()
+
+	h := NewCategoryHandler(repo.NewCategoryRepo(dbx))
+	expectCategories(mock)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM resources WHERE category_id = \\?").
+		WithArgs(uint64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	r := chi.NewRouter()
+	r.Delete("/api/categories/{id}", h.Delete)
+	req := httptest.NewRequest(http.MethodDelete, "/api/categories/1", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409 got %d body=%s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		ResourceCount int `json:"resourceCount"`
+		ChildCount    int `json:"childCount"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("409 must be JSON: %v body=%s", err, rr.Body.String())
+	}
+	if resp.ResourceCount != 4 || resp.ChildCount != 1 {
+		t.Fatalf("unexpected counts: %+v", resp)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestCategoryHandler_Merge_OK(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock3(t)
+	defer cleanup()
+
+	h := NewCategoryHandler(repo.NewCategoryRepo(dbx))
+	now := time.Now()
+	expectCategories(mock)
+	// атрибуты ветки source (2): capacity у родителя, seats у самой категории
+	mock.ExpectQuery("FROM category_attributes WHERE category_id IN \\(\\?, \\?\\)").
+		WithArgs(uint64(1), uint64(2)).
+		WillReturnRows(sqlmock.NewRows(categoryAttributeCols).
+			AddRow(uint64(5), uint64(1), "capacity", "Вместимость", "int", false, nil, 0, now).
+			AddRow(uint64(6), uint64(2), "seats", "Мест", "int", false, nil, 1, now))
+	// у target (3) есть seats того же типа
+	mock.ExpectQuery("FROM category_attributes WHERE category_id IN \\(\\?\\)").
+		WithArgs(uint64(3)).
+		WillReturnRows(sqlmock.NewRows(categoryAttributeCols).
+			AddRow(uint64(9), uint64(3), "seats", "Мест", "int", false, nil, 0, now))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM resource_categories WHERE id IN \\(\\?, \\?\\) FOR UPDATE").
+		WithArgs(uint64(2), uint64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint64(2)).AddRow(uint64(3)))
+	mock.ExpectExec("UPDATE resources SET category_id = \\? WHERE category_id = \\?").
+		WithArgs(uint64(3), uint64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 7))
+	mock.ExpectExec("UPDATE resource_categories SET parent_id = \\? WHERE parent_id = \\?").
+		WithArgs(uint64(3), uint64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE resource_attribute_values v JOIN resources res .* SET v.attribute_id = \\? WHERE v.attribute_id = \\? AND res.category_id IN \\(\\?\\)").
+		WithArgs(uint64(9), uint64(6), uint64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 7))
+	mock.ExpectExec("DELETE v FROM resource_attribute_values v .* WHERE v.attribute_id IN \\(\\?\\) AND res.category_id IN \\(\\?\\)").
+		WithArgs(uint64(5), uint64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 7))
+	mock.ExpectExec("DELETE FROM resource_categories WHERE id = \\?").
+		WithArgs(uint64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	r := chi.NewRouter()
+	r.Post("/api/categories/{id}/merge", h.Merge)
+	b, _ := json.Marshal(map[string]any{"targetId": 3})
+	req := httptest.NewRequest(http.MethodPost, "/api/categories/2/merge", bytes.NewReader(b))
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+
+	var res domain.CategoryMergeResult
+	_ = json.Unmarshal(rr.Body.Bytes(), &res)
+	if res.MovedResources != 7 || res.RemappedAttributes != 1 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestCategoryHandler_Merge_IntoOwnChildOrArchived(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock3(t)
+	defer cleanup()
+
+	h := NewCategoryHandler(repo.NewCategoryRepo(dbx))
+	r := chi.NewRouter()
+	r.Post("/api/categories/{id}/merge", h.Merge)
+
+	cases := []struct {
+		source, target string
+		want           int
+	}{
+		{"1", "2", http.StatusConflict},   // в собственную подкатегорию
+		{"1", "1", http.StatusConflict},   // сама в себя
+		{"3", "4", http.StatusBadRequest}, // в архивную
+	}
+	for _, c := range cases {
+		expectCategories(mock)
+		req := httptest.NewRequest(http.MethodPost, "/api/categories/"+c.source+"/merge", bytes.NewBufferString(`{"targetId": `+c.target+`}`))
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		if rr.Code != c.want {
+			t.Fatalf("%s -> %s: expected %d got %d body=%s", c.source, c.target, c.want, rr.Code, rr.Body.String())
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Заголовки устаревших адресов API; фронтенд на другом домене должен их видеть (CORS)
+const (
+	DeprecationHeader = "Deprecation"
+	SunsetHeader      = "Sunset"
+)
+
+// Deprecated помечает ответы устаревшего префикса API. Deprecation (RFC 9745) — с какой даты
+// адрес устарел, Sunset (RFC 8594) — после какой даты он может перестать работать, Link с
+// rel="successor-version" — тот же путь под префиксом successor.
+// Пример: Deprecated("/api", "/api/v1", since, sunset) для запроса /api/resources/5 даст
+// Link: </api/v1/resources/5>; rel="successor-version".
+func Deprecated(prefix, successor string, since, sunset time.Time) func(http.Handler) http.Handler {
+	deprecation := "@" + strconv.FormatInt(since.Unix(), 10)
+	sunsetAt := sunset.UTC().Format(http.TimeFormat)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set(DeprecationHeader, deprecation)
+			h.Set(SunsetHeader, sunsetAt)
+			if rest, ok := strings.CutPrefix(r.URL.Path, prefix); ok {
+				h.Add("Link", "<"+successor+rest+`>; rel="successor-version"`)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"bookinghub-backend/internal/i18n"
+	"bookinghub-backend/internal/service"
+)
+
+// Коды ошибок API. Клиенты ветвятся по коду, поэтому однажды выпущенный код не переименовываем.
+const (
+	CodeValidationFailed     = "VALIDATION_FAILED"
+	CodeInvalidJSON          = "INVALID_JSON"
+	CodeUnauthorized         = "UNAUTHORIZED"
+	CodeInvalidCredentials   = "INVALID_CREDENTIALS"
+	CodeForbidden            = "FORBIDDEN"
+	CodeAccountSuspended     = "ACCOUNT_SUSPENDED"
+	CodeNotFound             = "NOT_FOUND"
+	CodeResourceNotFound     = "RESOURCE_NOT_FOUND"
+	CodeBookingNotFound      = "BOOKING_NOT_FOUND"
+	CodeMethodNotAllowed     = "METHOD_NOT_ALLOWED"
+	CodeConflict             = "CONFLICT"
+	CodeBookingConflict      = "BOOKING_CONFLICT"
+	CodeEmailTaken           = "EMAIL_TAKEN"
+	CodeHoldNotFound         = "HOLD_NOT_FOUND"
+	CodeHoldLimit            = "HOLD_LIMIT_REACHED"
+	CodeSlotAvailable        = "SLOT_AVAILABLE"
+	CodeAlreadyInQueue       = "ALREADY_IN_QUEUE"
+	CodeClaimNotOffered      = "CLAIM_NOT_OFFERED"
+	CodeInviteExpired        = "INVITE_EXPIRED"
+	CodeCancelNotAllowed     = "CANCEL_NOT_ALLOWED"
+	CodeInvalidBookingStatus = "INVALID_BOOKING_STATUS"
+	CodePayloadTooLarge      = "PAYLOAD_TOO_LARGE"
+	CodeUnsupportedMedia     = "UNSUPPORTED_MEDIA_TYPE"
+	CodeImageLimit           = "IMAGE_LIMIT_REACHED"
+	CodeInternal             = "INTERNAL_ERROR"
+	CodeTimeDoesNotExist     = "LOCAL_TIME_DOES_NOT_EXIST"
+	CodeInvalidTimezone      = "INVALID_TIMEZONE"
+	CodeInvalidTimeInterval  = "INVALID_TIME_INTERVAL"
+	CodeBookingInPast        = "BOOKING_IN_PAST"
+	CodeTooManyRequests      = "TOO_MANY_REQUESTS"
+	CodeLoginLocked          = "LOGIN_LOCKED"
+	CodeInvalidTwoFactorCode = "INVALID_TWO_FACTOR_CODE"
+	CodeTwoFactorNotEnabled  = "TWO_FACTOR_NOT_ENABLED"
+	CodeTwoFactorEnabled     = "TWO_FACTOR_ENABLED"
+	CodeTwoFactorRequired    = "TWO_FACTOR_REQUIRED"
+	CodeSSOFailed            = "SSO_FAILED"
+	CodeSSOUnavailable       = "SSO_UNAVAILABLE"
+	CodeSSOEmailNotVerified  = "SSO_EMAIL_NOT_VERIFIED"
+)
+
+// RequestIDHeader — заголовок ответа с ID запроса; тот же ID приходит в теле ошибки и в логах
+const RequestIDHeader = "X-Request-ID"
+
+// FieldError — ошибка в конкретном поле запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// APIError — тело ответа с ошибкой: {"error": {"code": ..., "message": ..., "details": [...], "requestId": ...}}
+type APIError struct {
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"requestId,omitempty"`
+}
+
+type errorBody struct {
+	Error APIError `json:"error"`
+}
+
+// ExposeRequestID отдаёт ID запроса из middleware.RequestID в заголовке ответа. Обработчики
+// берут его оттуда для тела ошибки, поэтому middleware ставится сразу после middleware.RequestID.
+func ExposeRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := middleware.GetReqID(r.Context()); id != "" {
+			w.Header().Set(RequestIDHeader, id)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeError отвечает ошибкой API с кодом и понятным человеку сообщением на языке ответа.
+// message и сообщения в details — ключи каталога i18n или русский текст.
+func writeError(w http.ResponseWriter, status int, code, message string, details ...FieldError) {
+	lang := responseLang(w)
+	for i := range details {
+		details[i].Message = localize(lang, "field.invalid", details[i].Message)
+	}
+	writeAPIError(w, status, APIError{Code: code, Message: localize(lang, code, message), Details: details})
+}
+
+// writeAPIError отвечает уже переведённой ошибкой, дописывая ID запроса
+func writeAPIError(w http.ResponseWriter, status int, e APIError) {
+	e.RequestID = w.Header().Get(RequestIDHeader)
+	writeJSON(w, status, errorBody{Error: e})
+}
+
+// writeErrorf — writeError для сообщения каталога с параметрами
+func writeErrorf(w http.ResponseWriter, status int, code, key string, args ...any) {
+	writeAPIError(w, status, APIError{Code: code, Message: i18n.T(responseLang(w), key, args...)})
+}
+
+// writeFieldError — 400 VALIDATION_FAILED с указанием поля
+func writeFieldError(w http.ResponseWriter, field, message string) {
+	writeError(w, http.StatusBadRequest, CodeValidationFailed, message, FieldError{Field: field, Message: message})
+}
+
+// writeFieldErrorf — writeFieldError для сообщения каталога с параметрами
+func writeFieldErrorf(w http.ResponseWriter, field, key string, args ...any) {
+	message := i18n.T(responseLang(w), key, args...)
+	writeAPIError(w, http.StatusBadRequest, APIError{
+		Code:    CodeValidationFailed,
+		Message: message,
+		Details: []FieldError{{Field: field, Message: message}},
+	})
+}
+
+// internalError пишет причину в лог сервера, а клиенту отдаёт только message и ID запроса,
+// по которому причину можно найти в логах. Текст ошибок БД и драйверов клиенту не уходит.
+func internalError(w http.ResponseWriter, message string, err error) {
+	text := i18n.T(i18n.Default, message)
+	if err != nil {
+		log.Printf("[%s] %s: %v", w.Header().Get(RequestIDHeader), text, err)
+	} else {
+		log.Printf("[%s] %s", w.Header().Get(RequestIDHeader), text)
+	}
+	writeError(w, http.StatusInternalServerError, CodeInternal, message)
+}
+
+// NotFound и MethodNotAllowed — ответы роутера в том же формате, что и ошибки обработчиков
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusNotFound, CodeNotFound, "route.not_found")
+}
+
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusMethodNotAllowed, CodeMethodNotAllowed, "METHOD_NOT_ALLOWED")
+}
+
+// serviceErrors — ошибки сервисов, которые можно показать клиенту: статус, код и ключ перевода.
+// Всё, чего здесь нет, считается внутренней ошибкой.
+var serviceErrors = []struct {
+	err    error
+	status int
+	code   string
+	key    string
+}{
+	{service.ErrConflict, http.StatusConflict, CodeBookingConflict, "booking.conflict"},
+	{service.ErrResourceNotFound, http.StatusNotFound, CodeResourceNotFound, "resource.not_found"},
+	{service.ErrHoldNotFound, http.StatusNotFound, CodeHoldNotFound, "hold.not_found"},
+	{service.ErrHoldLimit, http.StatusTooManyRequests, CodeHoldLimit, "hold.limit"},
+	{service.ErrSlotAvailable, http.StatusConflict, CodeSlotAvailable, "waitlist.slot_available"},
+	{service.ErrAlreadyInQueue, http.StatusConflict, CodeAlreadyInQueue, "waitlist.already_in_queue"},
+	{service.ErrClaimNotOffered, http.StatusBadRequest, CodeClaimNotOffered, "waitlist.claim_not_offered"},
+	{service.ErrNonexistentTime, http.StatusBadRequest, CodeTimeDoesNotExist, "time.nonexistent"},
+	{service.ErrInvalidTimezone, http.StatusBadRequest, CodeInvalidTimezone, "timezone.invalid"},
+	{service.ErrInvalidTime, http.StatusBadRequest, CodeInvalidTimeInterval, "booking.invalid_time"},
+	{service.ErrTooShort, http.StatusBadRequest, CodeInvalidTimeInterval, "booking.too_short"},
+	{service.ErrInPast, http.StatusBadRequest, CodeBookingInPast, "booking.in_past"},
+	{service.ErrInvalidQuantity, http.StatusBadRequest, CodeValidationFailed, "booking.invalid_quantity"},
+	{service.ErrInvalidHoldTTL, http.StatusBadRequest, CodeValidationFailed, "hold.invalid_ttl"},
+	{service.ErrInvalidGroup, http.StatusBadRequest, CodeValidationFailed, "booking.invalid_group"},
+	{service.ErrInvalidOpeningHours, http.StatusBadRequest, CodeValidationFailed, "opening_hours.invalid"},
+	{service.ErrInvalidAddress, http.StatusBadRequest, CodeValidationFailed, "address.invalid"},
+	{service.ErrInvalidAttribute, http.StatusBadRequest, CodeValidationFailed, "attribute.invalid"},
+	{service.ErrInvalidAttributeValues, http.StatusBadRequest, CodeValidationFailed, "attribute.invalid_values"},
+	{service.ErrInvalidAttributeFilter, http.StatusBadRequest, CodeValidationFailed, "attribute.invalid_filter"},
+	{service.ErrImageTooLarge, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "image.too_large"},
+	{service.ErrImageType, http.StatusUnsupportedMediaType, CodeUnsupportedMedia, "image.type"},
+	{service.ErrImageUnreadable, http.StatusBadRequest, CodeValidationFailed, "image.unreadable"},
+	{service.ErrTooManyImages, http.StatusConflict, CodeImageLimit, "image.limit"},
+	{service.ErrInvalidTwoFactorCode, http.StatusBadRequest, CodeInvalidTwoFactorCode, "two_factor.invalid_code"},
+	{service.ErrTwoFactorNotEnrolled, http.StatusConflict, CodeTwoFactorNotEnabled, "two_factor.not_enabled"},
+	{service.ErrTwoFactorEnrollNotStarted, http.StatusConflict, CodeTwoFactorNotEnabled, "two_factor.enroll_not_started"},
+	{service.ErrTwoFactorAlreadyEnabled, http.StatusConflict, CodeTwoFactorEnabled, "two_factor.already_enabled"},
+	{service.ErrTwoFactorRequiredForRole, http.StatusForbidden, CodeTwoFactorRequired, "two_factor.required"},
+	{service.ErrSSOEmailNotVerified, http.StatusForbidden, CodeSSOEmailNotVerified, "sso.email_not_verified"},
+}
+
+// writeServiceError отвечает ошибкой сервиса: известные ошибки — с их кодом и текстом
+// (на русском — с подробностями из err, на других языках — по каталогу), остальные — как внутренние.
+func writeServiceError(w http.ResponseWriter, err error, fallback string) {
+	for _, e := range serviceErrors {
+		if errors.Is(err, e.err) {
+			message := err.Error()
+			if responseLang(w) != i18n.Default {
+				message = e.key
+			}
+			writeError(w, e.status, e.code, message)
+			return
+		}
+	}
+	internalError(w, fallback, err)
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"bookinghub-backend/internal/i18n"
+	"bookinghub-backend/internal/service"
+)
+
+func decodeAPIError(t *testing.T, rr *httptest.ResponseRecorder) APIError {
+	t.Helper()
+	var body struct {
+		Error APIError `json:"error"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("error body is not JSON: %v (%s)", err, rr.Body.String())
+	}
+	return body.Error
+}
+
+func TestWriteError_CarriesRequestID(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(ExposeRequestID)
+	r.NotFound(NotFound)
+	r.Get("/field", func(w http.ResponseWriter, r *http.Request) {
+		writeFieldError(w, "title", "title is required")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/field", nil)
+	req.Header.Set("X-Request-Id", "req-42")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest || rr.Header().Get(RequestIDHeader) != "req-42" {
+		t.Fatalf("unexpected response %d headers=%v", rr.Code, rr.Header())
+	}
+	got := decodeAPIError(t, rr)
+	if got.Code != CodeValidationFailed || got.RequestID != "req-42" ||
+		len(got.Details) != 1 || got.Details[0].Field != "title" {
+		t.Fatalf("unexpected error: %+v", got)
+	}
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/nope", nil))
+	if got := decodeAPIError(t, rr); rr.Code != http.StatusNotFound || got.Code != CodeNotFound || got.RequestID == "" {
+		t.Fatalf("unexpected 404: %d %+v", rr.Code, got)
+	}
+}
+
+func TestWriteServiceError(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{fmt.Errorf("%w: свободно 0 из 1 ед.", service.ErrConflict), http.StatusConflict, CodeBookingConflict},
+		{service.ErrHoldLimit, http.StatusTooManyRequests, CodeHoldLimit},
+		{fmt.Errorf("позиция 2: %w", service.ErrInPast), http.StatusBadRequest, CodeBookingInPast},
+		{errors.New("Error 1205 (HY000): Lock wait timeout exceeded"), http.StatusInternalServerError, CodeInternal},
+	}
+	for _, c := range cases {
+		rr := httptest.NewRecorder()
+		writeServiceError(rr, c.err, "Не удалось сохранить бронь")
+		got := decodeAPIError(t, rr)
+		if rr.Code != c.status || got.Code != c.code {
+			t.Errorf("%v: expected %d %s, got %d %+v", c.err, c.status, c.code, rr.Code, got)
+		}
+	}
+}
+
+func TestInternalError_DoesNotLeakCause(t *testing.T) {
+	rr := httptest.NewRecorder()
+	internalError(rr, "booking.list_failed", errors.New("dial tcp 10.0.0.5:3306: connection refused"))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500 got %d", rr.Code)
+	}
+	if strings.Contains(rr.Body.String(), "3306") {
+		t.Fatalf("internal error leaked to client: %s", rr.Body.String())
+	}
+	if got := decodeAPIError(t, rr); got.Code != CodeInternal || got.Message != "Не удалось получить бронирования" {
+		t.Fatalf("unexpected error: %+v", got)
+	}
+}
+
+func TestWriteError_Localized(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(Localize)
+	r.Get("/conflict", func(w http.ResponseWriter, r *http.Request) {
+		writeServiceError(w, fmt.Errorf("%w: свободно 0 из 1 ед.", service.ErrConflict), "booking.save_failed")
+	})
+	r.Get("/email", func(w http.ResponseWriter, r *http.Request) {
+		writeFieldError(w, "email", "auth.invalid_email")
+	})
+	r.Get("/formatted", func(w http.ResponseWriter, r *http.Request) {
+		writeFieldErrorf(w, "startAt", "time.invalid_format", "startAt")
+	})
+	r.Get("/literal", func(w http.ResponseWriter, r *http.Request) {
+		writeFieldError(w, "title", "title обязателен (до 255 символов)")
+	})
+
+	cases := []struct {
+		path, lang, message, detail string
+	}{
+		{"/conflict", "en-US,en;q=0.9", "The selected time is already booked", ""},
+		{"/conflict", "", "Выбранное время уже занято: свободно 0 из 1 ед.", ""},
+		{"/email", "en", "Enter a valid email", "Enter a valid email"},
+		{"/email", "ru", "Введите корректный email", "Введите корректный email"},
+		{"/formatted", "en", "Invalid startAt. Format: YYYY-MM-DDTHH:MM:SS", "Invalid startAt. Format: YYYY-MM-DDTHH:MM:SS"},
+		{"/formatted", "ru", "Некорректное startAt. Формат: YYYY-MM-DDTHH:MM:SS", "Некорректное startAt. Формат: YYYY-MM-DDTHH:MM:SS"},
+		// текст без перевода: на английском — общий текст кода и поля
+		{"/literal", "en", "The request contains invalid data", "Invalid value"},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, c.path, nil)
+		if c.lang != "" {
+			req.Header.Set("Accept-Language", c.lang)
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		got := decodeAPIError(t, rr)
+		if got.Message != c.message {
+			t.Errorf("%s [%s]: message %q, want %q", c.path, c.lang, got.Message, c.message)
+		}
+		if c.detail != "" && (len(got.Details) != 1 || got.Details[0].Message != c.detail) {
+			t.Errorf("%s [%s]: details %+v, want %q", c.path, c.lang, got.Details, c.detail)
+		}
+	}
+}
+
+func TestServiceErrors_RussianCatalogMatchesServiceText(t *testing.T) {
+	for _, e := range serviceErrors {
+		if got := i18n.T(i18n.RU, e.key); got != e.err.Error() {
+			t.Errorf("%s: catalog %q, service %q", e.key, got, e.err.Error())
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/apiv1"
+	"bookinghub-backend/internal/service"
+)
+
+type HoldHandler struct {
+	service *service.HoldService
+	zones   zoneSource
+}
+
+func NewHoldHandler(service *service.HoldService, zones zoneSource) *HoldHandler {
+	return &HoldHandler{service: service, zones: zones}
+}
+
+type createHoldReq struct {
+	StartAt  string `json:"startAt" validate:"trim,required"`
+	EndAt    string `json:"endAt" validate:"trim,required"`
+	Quantity *int   `json:"quantity" validate:"min=1"` // по умолчанию 1
+	Minutes  *int   `json:"minutes" validate:"min=1"`  // срок удержания; по умолчанию 10
+}
+
+// POST /api/resources/{id}/holds — удержать интервал, пока пользователь оформляет бронь
+func (h *HoldHandler) Create(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return
+	}
+	id64, err := strconv.ParseUint(strings.TrimSpace(chi.URLParam(r, "id")), 10, 64)
+	if err != nil || id64 == 0 {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "resource.invalid_id")
+		return
+	}
+
+	var req createHoldReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	clock := newResourceClock(r.Context(), h.zones, id64)
+	startAt, ok := parseRequestTime(w, clock, "startAt", req.StartAt)
+	if !ok {
+		return
+	}
+	endAt, ok := parseRequestTime(w, clock, "endAt", req.EndAt)
+	if !ok {
+		return
+	}
+
+	quantity := 1
+	if req.Quantity != nil {
+		quantity = *req.Quantity
+	}
+	ttl := service.DefaultHoldTTL
+	if req.Minutes != nil {
+		ttl = time.Duration(*req.Minutes) * time.Minute
+	}
+
+	hold, err := h.service.Place(r.Context(), uid, id64, startAt, endAt, quantity, ttl)
+	if err != nil {
+		writeBookingError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, apiv1.NewHold(*hold))
+}
+
+// DELETE /api/holds/{token} — снять своё удержание раньше срока
+func (h *HoldHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return
+	}
+	if err := h.service.Cancel(r.Context(), uid, strings.TrimSpace(chi.URLParam(r, "token"))); err != nil {
+		writeBookingError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
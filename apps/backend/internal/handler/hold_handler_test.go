@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/repo"
+	"bookinghub-backend/internal/service"
+)
+
+func holdRouter(bookings *repo.BookingRepo) http.Handler {
+	h := NewHoldHandler(service.NewHoldService(bookings), bookings)
+	r := chi.NewRouter()
+	r.Post("/api/resources/{id}/holds", h.Create)
+	return r
+}
+
+func TestHoldHandler_Create_201(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	start := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Hour)
+	end := start.Add(time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM resources WHERE id IN \\(\\?\\) ORDER BY id FOR UPDATE").
+		WithArgs(uint64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint64(5)))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM booking_holds WHERE user_id = \\?").
+		WithArgs(uint64(7), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+	expectAvailability(mock, 5, 1, start, end)
+	mock.ExpectExec("INSERT INTO booking_holds \\(token, resource_id, user_id, start_at, end_at, quantity, expires_at\\)").
+		WithArgs(sqlmock.AnyArg(), uint64(5), uint64(7), timeEq{start}, timeEq{end}, 1, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectCommit()
+
+	body := `{"startAt":"` + start.Format(time.RFC3339) + `","endAt":"` + end.Format(time.RFC3339) + `","minutes":5}`
+	req := withUID(httptest.NewRequest(http.MethodPost, "/api/resources/5/holds", bytes.NewBufferString(body)), 7)
+	rr := httptest.NewRecorder()
+
+	holdRouter(repo.NewBookingRepo(db)).ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	var got struct {
+		ID        uint64    `json:"id"`
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expiresAt"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.ID != 3 || got.Token == "" || time.Until(got.ExpiresAt) > 5*time.Minute {
+		t.Fatalf("unexpected hold: %+v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestHoldHandler_Create_LimitReached_429(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	start := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM resources WHERE id IN").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint64(5)))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM booking_holds").
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(service.DefaultMaxHoldsPerUser))
+	mock.ExpectRollback()
+
+	body := `{"startAt":"` + start.Format(time.RFC3339) + `","endAt":"` + start.Add(time.Hour).Format(time.RFC3339) + `"}`
+	req := withUID(httptest.NewRequest(http.MethodPost, "/api/resources/5/holds", bytes.NewBufferString(body)), 7)
+	rr := httptest.NewRecorder()
+
+	holdRouter(repo.NewBookingRepo(db)).ServeHTTP(rr, req)
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"bookinghub-backend/internal/i18n"
+)
+
+// Localize выбирает язык ответа по Accept-Language и сообщает его в Content-Language.
+// Если у пользователя в профиле задан язык, AuthMiddleware заменяет выбранный им.
+// Тексты ошибок берут язык из Content-Language ответа, как и ID запроса из X-Request-ID.
+func Localize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Language")
+		lang := i18n.Negotiate(r.Header.Get("Accept-Language"))
+		setLang(w, lang)
+		next.ServeHTTP(w, r.WithContext(i18n.WithLang(r.Context(), lang)))
+	})
+}
+
+func setLang(w http.ResponseWriter, lang string) {
+	w.Header().Set("Content-Language", lang)
+}
+
+// responseLang — язык, выбранный для ответа; без Localize — язык по умолчанию
+func responseLang(w http.ResponseWriter) string {
+	if lang, ok := i18n.Normalize(w.Header().Get("Content-Language")); ok {
+		return lang
+	}
+	return i18n.Default
+}
+
+// localize — текст сообщения на языке ответа. message — ключ каталога или готовый русский текст;
+// для русского текста без перевода другим языкам отдаётся общий текст по коду fallbackKey.
+func localize(lang, fallbackKey, message string) string {
+	switch {
+	case i18n.Has(lang, message):
+		return i18n.T(lang, message)
+	case lang == i18n.Default:
+		return message
+	default:
+		return i18n.T(lang, fallbackKey)
+	}
+}
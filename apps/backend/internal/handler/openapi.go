@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"net/http"
+
+	"bookinghub-backend/internal/apiv1"
+	"bookinghub-backend/internal/openapi"
+	"bookinghub-backend/internal/reporting"
+	"bookinghub-backend/internal/service"
+)
+
+// spec — произвольный JSON-объект: так в спецификации описан сам документ OpenAPI
+type spec map[string]any
+
+var (
+	reportQuery = []openapi.Param{
+		{Name: "from", Description: "Начало периода, YYYY-MM-DD"},
+		{Name: "to", Description: "Конец периода, YYYY-MM-DD"},
+		{Name: "groupBy", Enum: []string{"day", "week", "month"}},
+		{Name: "format", Enum: []string{"json", "csv"}},
+		{Name: "section", Description: "Раздел CSV", Enum: []string{
+			reporting.SectionTotals, reporting.SectionSeries, reporting.SectionResources,
+			reporting.SectionCategories, reporting.SectionOwners,
+		}},
+	}
+	includeArchived = []openapi.Param{{Name: "includeArchived", Type: "boolean", Description: "Показать архивные категории"}}
+)
+
+// APIRoutes — операции /api/v1 для документа OpenAPI, в том же порядке, что и в роутере.
+// Новый маршрут без записи здесь роняет тест покрытия спецификации.
+func APIRoutes() []openapi.Route {
+	const (
+		tagSystem     = "Служебное"
+		tagAuth       = "Авторизация"
+		tagCategories = "Категории"
+		tagResources  = "Ресурсы"
+		tagBookings   = "Бронирования"
+		tagWaitlist   = "Очередь ожидания"
+		tagBundles    = "Наборы ресурсов"
+		tagOrgs       = "Организации"
+		tagReports    = "Отчёты"
+		tagAdmin      = "Администрирование"
+		tagUsers      = "Пользователи"
+	)
+	created := http.StatusCreated
+	tooMany := openapi.Reply{Status: http.StatusTooManyRequests, Description: "Превышен лимит запросов (TOO_MANY_REQUESTS) или вход временно заблокирован (LOGIN_LOCKED); через сколько секунд повторить — в заголовке Retry-After", Body: errorBody{}}
+
+	return []openapi.Route{
+		{ID: "health", Method: "GET", Path: "/api/v1/health", Summary: "Проверка, что сервер жив", Tag: tagSystem, Content: "text/plain"},
+		{ID: "openapiSpec", Method: "GET", Path: "/api/v1/openapi.json", Summary: "Этот документ OpenAPI", Tag: tagSystem, Response: spec{}},
+		{ID: "apiDocs", Method: "GET", Path: "/api/v1/docs", Summary: "Swagger UI", Tag: tagSystem, Content: "text/html"},
+
+		{ID: "listCategories", Method: "GET", Path: "/api/v1/categories", Summary: "Список категорий", Tag: tagCategories, Query: includeArchived, Response: []apiv1.Category{}},
+		{ID: "categoryTree", Method: "GET", Path: "/api/v1/categories/tree", Summary: "Дерево категорий", Tag: tagCategories, Query: includeArchived, Response: []apiv1.CategoryNode{}},
+		{ID: "getCategory", Method: "GET", Path: "/api/v1/categories/{id}", Summary: "Категория с хлебными крошками и атрибутами", Tag: tagCategories, Response: apiv1.CategoryDetails{}},
+		{ID: "listCategoryAttributes", Method: "GET", Path: "/api/v1/categories/{id}/attributes", Summary: "Атрибуты категории с унаследованными", Tag: tagCategories, Response: []apiv1.CategoryAttribute{}},
+
+		{ID: "listResources", Method: "GET", Path: "/api/v1/resources", Summary: "Поиск ресурсов; по атрибутам категории — attr.<code>=, attr.<code>.min=, attr.<code>.max=", Tag: tagResources,
+			Query: []openapi.Param{
+				{Name: "categoryId", Type: "integer", Description: "Категория вместе с подкатегориями"},
+				{Name: "near", Description: "Точка lat,lng для поиска по радиусу"},
+				{Name: "radiusKm", Type: "number"},
+				{Name: "bbox", Description: "Прямоугольник west,south,east,north"},
+				{Name: "sort", Enum: []string{"distance", "newest"}},
+			},
+			Response: []apiv1.Resource{}},
+		{ID: "getPublicUser", Method: "GET", Path: "/api/v1/users/{id}", Summary: "Публичный профиль пользователя", Tag: tagUsers, Response: apiv1.PublicUser{}},
+		{ID: "createResource", Method: "POST", Path: "/api/v1/resources", Summary: "Создать ресурс", Tag: tagResources, Auth: true, Body: createResourceRequest{}, Status: created, Response: apiv1.ID{}},
+
+		{ID: "register", Method: "POST", Path: "/api/v1/auth/register", Summary: "Регистрация", Tag: tagAuth, Body: registerReq{}, Status: created, Response: apiv1.Auth{},
+			Replies: []openapi.Reply{tooMany}},
+		{ID: "login", Method: "POST", Path: "/api/v1/auth/login", Summary: "Вход по email и паролю", Tag: tagAuth, Body: loginReq{}, Response: apiv1.Auth{},
+			Replies: []openapi.Reply{
+				{Status: http.StatusAccepted, Description: "Нужен второй фактор: токен доступа выдаст verifyTwoFactor", Body: apiv1.LoginChallenge{}},
+				tooMany,
+			}},
+		{ID: "verifyTwoFactor", Method: "POST", Path: "/api/v1/auth/2fa/verify", Summary: "Второй шаг входа: код 2FA или код восстановления", Tag: tagAuth, Body: twoFactorVerifyReq{}, Response: apiv1.Auth{},
+			Replies: []openapi.Reply{tooMany}},
+		{ID: "ssoAuthorize", Method: "GET", Path: "/api/v1/auth/oidc/authorize", Summary: "Начать вход через корпоративного провайдера (OIDC, код авторизации с PKCE)", Tag: tagAuth, Response: apiv1.SSOAuthorization{}},
+		{ID: "ssoCallback", Method: "POST", Path: "/api/v1/auth/oidc/callback", Summary: "Завершить вход через провайдера: code и state с адреса возврата", Tag: tagAuth, Body: ssoCallbackReq{}, Response: apiv1.Auth{},
+			Replies: []openapi.Reply{
+				{Status: http.StatusAccepted, Description: "Нужен второй фактор: токен доступа выдаст verifyTwoFactor", Body: apiv1.LoginChallenge{}},
+				tooMany,
+			}},
+		{ID: "twoFactorStatus", Method: "GET", Path: "/api/v1/auth/2fa", Summary: "Состояние двухфакторной аутентификации", Tag: tagAuth, Auth: true, Response: apiv1.TwoFactorStatus{}},
+		{ID: "enrollTwoFactor", Method: "POST", Path: "/api/v1/auth/2fa/enroll", Summary: "Начать подключение 2FA: секрет и ссылка otpauth://", Tag: tagAuth, Auth: true, Response: apiv1.TwoFactorEnrollment{}},
+		{ID: "enableTwoFactor", Method: "POST", Path: "/api/v1/auth/2fa/enable", Summary: "Включить 2FA кодом из аутентификатора", Tag: tagAuth, Auth: true, Body: twoFactorCodeReq{}, Response: apiv1.RecoveryCodes{}},
+		{ID: "regenerateRecoveryCodes", Method: "POST", Path: "/api/v1/auth/2fa/recovery-codes", Summary: "Новые коды восстановления", Tag: tagAuth, Auth: true, Body: twoFactorCodeReq{}, Response: apiv1.RecoveryCodes{}},
+		{ID: "disableTwoFactor", Method: "POST", Path: "/api/v1/auth/2fa/disable", Summary: "Выключить 2FA", Tag: tagAuth, Auth: true, Body: twoFactorDisableReq{}, Response: apiv1.OK{}},
+		{ID: "getMe", Method: "GET", Path: "/api/v1/auth/me", Summary: "Текущий пользователь", Tag: tagAuth, Auth: true, Response: apiv1.Profile{}},
+		{ID: "updateMe", Method: "PATCH", Path: "/api/v1/auth/me", Summary: "Изменить профиль", Tag: tagAuth, Auth: true, Body: updateMeReq{}, Response: apiv1.Profile{}},
+		{ID: "changePassword", Method: "POST", Path: "/api/v1/auth/password", Summary: "Сменить пароль", Tag: tagAuth, Auth: true, Body: changePasswordReq{}, Response: apiv1.OK{}},
+		{ID: "deleteMe", Method: "DELETE", Path: "/api/v1/auth/me", Summary: "Удалить аккаунт", Tag: tagAuth, Auth: true, Response: apiv1.OK{}},
+
+		{ID: "myBookings", Method: "GET", Path: "/api/v1/bookings/my", Summary: "Мои бронирования", Tag: tagBookings, Auth: true, Response: []apiv1.Booking{}},
+		{ID: "createBooking", Method: "POST", Path: "/api/v1/bookings", Summary: "Забронировать ресурс или оформить удержание", Tag: tagBookings, Auth: true, Body: createBookingReq{}, Status: created, Response: apiv1.BookingCreated{},
+			Replies: []openapi.Reply{tooMany}},
+		{ID: "pendingBookings", Method: "GET", Path: "/api/v1/bookings/pending", Summary: "Брони, ожидающие решения менеджера", Tag: tagBookings, Auth: true, Response: []apiv1.Booking{}},
+		{ID: "updateBookingStatus", Method: "PATCH", Path: "/api/v1/bookings/{id}/status", Summary: "Подтвердить или отклонить бронь", Tag: tagBookings, Auth: true, Body: updateStatusReq{}, Response: apiv1.OK{}},
+		{ID: "cancelBooking", Method: "POST", Path: "/api/v1/bookings/{id}/cancel", Summary: "Отменить бронь", Tag: tagBookings, Auth: true, Response: apiv1.OK{}},
+		{ID: "bookingHistory", Method: "GET", Path: "/api/v1/bookings/{id}/history", Summary: "История статусов брони", Tag: tagBookings, Auth: true, Response: []apiv1.BookingStatusChange{}},
+
+		{ID: "createHold", Method: "POST", Path: "/api/v1/resources/{id}/holds", Summary: "Удержать интервал на время оформления", Tag: tagBookings, Auth: true, Body: createHoldReq{}, Status: created, Response: apiv1.Hold{}},
+		{ID: "deleteHold", Method: "DELETE", Path: "/api/v1/holds/{token}", Summary: "Снять удержание", Tag: tagBookings, Auth: true, Response: apiv1.OK{}},
+
+		{ID: "joinWaitlist", Method: "POST", Path: "/api/v1/waitlist", Summary: "Встать в очередь на занятое время", Tag: tagWaitlist, Auth: true, Body: joinWaitlistReq{}, Status: created, Response: apiv1.ID{}},
+		{ID: "myWaitlist", Method: "GET", Path: "/api/v1/waitlist/my", Summary: "Мои места в очереди", Tag: tagWaitlist, Auth: true, Response: []apiv1.WaitlistEntry{}},
+		{ID: "leaveWaitlist", Method: "DELETE", Path: "/api/v1/waitlist/{id}", Summary: "Выйти из очереди", Tag: tagWaitlist, Auth: true, Response: apiv1.OK{}},
+		{ID: "claimWaitlist", Method: "POST", Path: "/api/v1/waitlist/{id}/claim", Summary: "Забрать освободившееся время", Tag: tagWaitlist, Auth: true, Status: created, Response: apiv1.WaitlistClaimed{}},
+
+		{ID: "createBookingGroup", Method: "POST", Path: "/api/v1/booking-groups", Summary: "Групповая бронь", Tag: tagBookings, Auth: true, Body: createGroupReq{}, Status: created, Response: apiv1.BookingGroupCreated{},
+			Replies: []openapi.Reply{tooMany}},
+		{ID: "getBookingGroup", Method: "GET", Path: "/api/v1/booking-groups/{id}", Summary: "Групповая бронь", Tag: tagBookings, Auth: true, Response: apiv1.BookingGroup{}},
+		{ID: "cancelBookingGroup", Method: "POST", Path: "/api/v1/booking-groups/{id}/cancel", Summary: "Отменить групповую бронь", Tag: tagBookings, Auth: true, Response: apiv1.OK{}},
+		{ID: "listBundles", Method: "GET", Path: "/api/v1/bundles", Summary: "Наборы ресурсов", Tag: tagBundles, Response: []apiv1.Bundle{}},
+		{ID: "getBundle", Method: "GET", Path: "/api/v1/bundles/{id}", Summary: "Набор ресурсов", Tag: tagBundles, Response: apiv1.Bundle{}},
+		{ID: "createBundle", Method: "POST", Path: "/api/v1/bundles", Summary: "Создать набор", Tag: tagBundles, Auth: true, Body: createBundleReq{}, Status: created, Response: apiv1.ID{}},
+		{ID: "deleteBundle", Method: "DELETE", Path: "/api/v1/bundles/{id}", Summary: "Удалить набор", Tag: tagBundles, Auth: true, Response: apiv1.OK{}},
+
+		{ID: "resourceBookings", Method: "GET", Path: "/api/v1/resources/{id}/bookings", Summary: "Брони ресурса за период", Tag: tagResources,
+			Query: []openapi.Param{
+				{Name: "from", Required: true, Description: "YYYY-MM-DD в поясе ресурса"},
+				{Name: "to", Required: true, Description: "YYYY-MM-DD в поясе ресурса, включительно"},
+			},
+			Response: []apiv1.Booking{}},
+		{ID: "resourceAvailability", Method: "GET", Path: "/api/v1/resources/{id}/availability", Summary: "Свободные единицы на интервал", Tag: tagResources,
+			Query: []openapi.Param{
+				{Name: "startAt", Required: true, Description: "RFC 3339 или местное время ресурса"},
+				{Name: "endAt", Required: true, Description: "RFC 3339 или местное время ресурса"},
+			},
+			Response: apiv1.Availability{}},
+		{ID: "resourceOccupancy", Method: "GET", Path: "/api/v1/resources/{id}/occupancy", Summary: "Загрузка ресурса", Tag: tagResources, Auth: true,
+			Query: []openapi.Param{
+				{Name: "bucket", Enum: []string{string(service.BucketHour), string(service.BucketDay)}},
+				{Name: "from", Description: "YYYY-MM-DD"},
+				{Name: "to", Description: "YYYY-MM-DD, включительно"},
+				{Name: "includePending", Type: "boolean"},
+			},
+			Response: apiv1.Occupancy{}},
+		{ID: "getOpeningHours", Method: "GET", Path: "/api/v1/resources/{id}/opening-hours", Summary: "Часы работы", Tag: tagResources, Response: []apiv1.OpeningHours{}},
+		{ID: "updateOpeningHours", Method: "PUT", Path: "/api/v1/resources/{id}/opening-hours", Summary: "Заменить часы работы", Tag: tagResources, Auth: true, Body: updateOpeningHoursRequest{}, Response: apiv1.OK{}},
+
+		{ID: "listResourceImages", Method: "GET", Path: "/api/v1/resources/{id}/images", Summary: "Фото ресурса", Tag: tagResources, Response: []apiv1.Image{}},
+		{ID: "uploadResourceImages", Method: "POST", Path: "/api/v1/resources/{id}/images", Summary: "Загрузить фото", Tag: tagResources, Auth: true, Upload: "file", Status: created, Response: []apiv1.Image{}},
+		{ID: "reorderResourceImages", Method: "PUT", Path: "/api/v1/resources/{id}/images/order", Summary: "Порядок фото", Tag: tagResources, Auth: true, Body: reorderImagesRequest{}, Response: apiv1.OK{}},
+		{ID: "setResourceCover", Method: "POST", Path: "/api/v1/resources/{id}/images/{imageId}/cover", Summary: "Сделать фото обложкой", Tag: tagResources, Auth: true, Response: apiv1.OK{}},
+		{ID: "deleteResourceImage", Method: "DELETE", Path: "/api/v1/resources/{id}/images/{imageId}", Summary: "Удалить фото", Tag: tagResources, Auth: true, Response: apiv1.OK{}},
+
+		{ID: "myResources", Method: "GET", Path: "/api/v1/resources/my", Summary: "Мои ресурсы", Tag: tagResources, Auth: true, Response: []apiv1.Resource{}},
+		{ID: "getResource", Method: "GET", Path: "/api/v1/resources/{id}", Summary: "Ресурс", Tag: tagResources, Response: apiv1.Resource{}},
+		{ID: "updateResource", Method: "PATCH", Path: "/api/v1/resources/{id}", Summary: "Изменить ресурс", Tag: tagResources, Auth: true, Body: updateResourceRequest{}, Response: apiv1.OK{}},
+
+		{ID: "createOrganization", Method: "POST", Path: "/api/v1/organizations", Summary: "Создать организацию", Tag: tagOrgs, Auth: true, Body: createOrganizationReq{}, Status: created, Response: apiv1.ID{}},
+		{ID: "myOrganizations", Method: "GET", Path: "/api/v1/organizations/my", Summary: "Мои организации", Tag: tagOrgs, Auth: true, Response: []apiv1.Organization{}},
+		{ID: "listOrgMembers", Method: "GET", Path: "/api/v1/organizations/{id}/members", Summary: "Участники", Tag: tagOrgs, Auth: true, Response: []apiv1.OrgMember{}},
+		{ID: "updateOrgMember", Method: "PATCH", Path: "/api/v1/organizations/{id}/members/{userId}", Summary: "Сменить роль участника", Tag: tagOrgs, Auth: true, Body: updateMemberReq{}, Response: apiv1.OK{}},
+		{ID: "removeOrgMember", Method: "DELETE", Path: "/api/v1/organizations/{id}/members/{userId}", Summary: "Исключить участника", Tag: tagOrgs, Auth: true, Response: apiv1.OK{}},
+		{ID: "listOrgInvites", Method: "GET", Path: "/api/v1/organizations/{id}/invites", Summary: "Ожидающие приглашения", Tag: tagOrgs, Auth: true, Response: []apiv1.OrgInvite{}},
+		{ID: "createOrgInvite", Method: "POST", Path: "/api/v1/organizations/{id}/invites", Summary: "Пригласить по email", Tag: tagOrgs, Auth: true, Body: createInviteReq{}, Status: created, Response: apiv1.InviteCreated{}},
+		{ID: "myInvites", Method: "GET", Path: "/api/v1/invites/my", Summary: "Приглашения мне", Tag: tagOrgs, Auth: true, Response: []apiv1.OrgInvite{}},
+		{ID: "acceptInvite", Method: "POST", Path: "/api/v1/invites/{token}/accept", Summary: "Принять приглашение", Tag: tagOrgs, Auth: true, Response: apiv1.InviteAccepted{}},
+		{ID: "declineInvite", Method: "POST", Path: "/api/v1/invites/{token}/decline", Summary: "Отклонить приглашение", Tag: tagOrgs, Auth: true, Response: apiv1.OK{}},
+
+		{ID: "ownerReport", Method: "GET", Path: "/api/v1/reports/owner", Summary: "Отчёт владельца", Tag: tagReports, Auth: true, Query: reportQuery, Response: reporting.Report{}, Content: "text/csv"},
+
+		{ID: "createCategory", Method: "POST", Path: "/api/v1/categories", Summary: "Создать категорию", Tag: tagCategories, Auth: true, Body: createCategoryReq{}, Status: created, Response: apiv1.ID{}},
+		{ID: "updateCategory", Method: "PATCH", Path: "/api/v1/categories/{id}", Summary: "Изменить категорию", Tag: tagCategories, Auth: true, Body: updateCategoryReq{}, Response: apiv1.OK{}},
+		{ID: "deleteCategory", Method: "DELETE", Path: "/api/v1/categories/{id}", Summary: "Удалить пустую категорию", Tag: tagCategories, Auth: true, Response: apiv1.OK{},
+			Replies: []openapi.Reply{{Status: http.StatusConflict, Description: "В категории есть ресурсы или подкатегории", Body: apiv1.CategoryNotEmpty{}}}},
+		{ID: "mergeCategory", Method: "POST", Path: "/api/v1/categories/{id}/merge", Summary: "Перенести ресурсы в другую категорию", Tag: tagCategories, Auth: true, Body: mergeCategoryReq{}, Response: apiv1.CategoryMerge{}},
+		{ID: "archiveCategory", Method: "POST", Path: "/api/v1/categories/{id}/archive", Summary: "Архивировать категорию", Tag: tagCategories, Auth: true, Response: apiv1.CategoryArchive{}},
+		{ID: "unarchiveCategory", Method: "POST", Path: "/api/v1/categories/{id}/unarchive", Summary: "Вернуть из архива", Tag: tagCategories, Auth: true, Response: apiv1.CategoryArchive{}},
+		{ID: "createCategoryAttribute", Method: "POST", Path: "/api/v1/categories/{id}/attributes", Summary: "Добавить атрибут", Tag: tagCategories, Auth: true, Body: attributeReq{}, Status: created, Response: apiv1.CategoryAttribute{}},
+		{ID: "updateCategoryAttribute", Method: "PATCH", Path: "/api/v1/categories/{id}/attributes/{attrId}", Summary: "Изменить атрибут", Tag: tagCategories, Auth: true, Body: attributeReq{}, Response: apiv1.CategoryAttribute{}},
+		{ID: "deleteCategoryAttribute", Method: "DELETE", Path: "/api/v1/categories/{id}/attributes/{attrId}", Summary: "Удалить атрибут", Tag: tagCategories, Auth: true, Response: apiv1.OK{}},
+
+		{ID: "listPermissions", Method: "GET", Path: "/api/v1/admin/permissions", Summary: "Права и матрица ролей", Tag: tagAdmin, Auth: true, Response: apiv1.Permissions{}},
+		{ID: "updateRolePermissions", Method: "PUT", Path: "/api/v1/admin/roles/{role}/permissions", Summary: "Заменить права роли", Tag: tagAdmin, Auth: true, Body: updateRolePermissionsReq{}, Response: apiv1.RolePermissions{}},
+		{ID: "adminListUsers", Method: "GET", Path: "/api/v1/admin/users", Summary: "Пользователи", Tag: tagAdmin, Auth: true,
+			Query: []openapi.Param{
+				{Name: "q", Description: "Поиск по email и имени"},
+				{Name: "page", Type: "integer"},
+				{Name: "pageSize", Type: "integer"},
+			},
+			Response: apiv1.AdminUsersPage{}},
+		{ID: "adminUpdateUserRole", Method: "PATCH", Path: "/api/v1/admin/users/{id}/role", Summary: "Сменить роль", Tag: tagAdmin, Auth: true, Body: updateUserRoleReq{}, Response: apiv1.OK{}},
+		{ID: "adminSuspendUser", Method: "POST", Path: "/api/v1/admin/users/{id}/suspend", Summary: "Заблокировать", Tag: tagAdmin, Auth: true, Body: suspendUserReq{}, Response: apiv1.OK{}},
+		{ID: "adminUnsuspendUser", Method: "POST", Path: "/api/v1/admin/users/{id}/unsuspend", Summary: "Разблокировать", Tag: tagAdmin, Auth: true, Response: apiv1.OK{}},
+		{ID: "adminVerifyUser", Method: "POST", Path: "/api/v1/admin/users/{id}/verify", Summary: "Отметить как проверенного", Tag: tagAdmin, Auth: true, Response: apiv1.OK{}},
+		{ID: "adminUnverifyUser", Method: "POST", Path: "/api/v1/admin/users/{id}/unverify", Summary: "Снять отметку о проверке", Tag: tagAdmin, Auth: true, Response: apiv1.OK{}},
+		{ID: "adminForceLogout", Method: "POST", Path: "/api/v1/admin/users/{id}/logout", Summary: "Завершить все сессии", Tag: tagAdmin, Auth: true, Response: apiv1.OK{}},
+		{ID: "adminResetPassword", Method: "POST", Path: "/api/v1/admin/users/{id}/password-reset", Summary: "Выдать временный пароль", Tag: tagAdmin, Auth: true, Response: apiv1.TemporaryPassword{}},
+		{ID: "platformReport", Method: "GET", Path: "/api/v1/admin/reports", Summary: "Отчёт по платформе", Tag: tagAdmin, Auth: true, Query: reportQuery, Response: reporting.Report{}, Content: "text/csv"},
+	}
+}
+
+// ErrorBody — значение типа тела ошибки API для openapi.Build
+func ErrorBody() any {
+	return errorBody{}
+}
@@ -0,0 +1,404 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/apiv1"
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/repo"
+)
+
+// сколько живёт приглашение в организацию
+const inviteTTL = 7 * 24 * time.Hour
+
+type OrganizationHandler struct {
+	orgs  *repo.OrganizationRepo
+	users *repo.UserRepo
+}
+
+func NewOrganizationHandler(orgs *repo.OrganizationRepo, users *repo.UserRepo) *OrganizationHandler {
+	return &OrganizationHandler{orgs: orgs, users: users}
+}
+
+type createOrganizationReq struct {
+	Name string `json:"name" validate:"trim,required,max=150"`
+}
+
+// POST /api/organizations — создатель становится OWNER
+func (h *OrganizationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return
+	}
+
+	var req createOrganizationReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	id, err := h.orgs.Create(r.Context(), req.Name, uid)
+	if err != nil {
+		internalError(w, "organization.create_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, apiv1.ID{ID: id})
+}
+
+// GET /api/organizations/my
+func (h *OrganizationHandler) My(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return
+	}
+
+	items, err := h.orgs.ListForUser(r.Context(), uid)
+	if err != nil {
+		internalError(w, "organization.list_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.NewOrganizations(items))
+}
+
+// GET /api/organizations/{id}/members — доступно любому участнику
+func (h *OrganizationHandler) Members(w http.ResponseWriter, r *http.Request) {
+	orgID, myRole, ok := h.memberContext(w, r)
+	if !ok {
+		return
+	}
+	if myRole == "" {
+		writeError(w, http.StatusForbidden, CodeForbidden, "organization.not_member")
+		return
+	}
+
+	items, err := h.orgs.ListMembers(r.Context(), orgID)
+	if err != nil {
+		internalError(w, "organization.members_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.NewOrgMembers(items))
+}
+
+type updateMemberReq struct {
+	Role domain.OrgRole `json:"role" validate:"trim,required,oneof=OWNER MANAGER VIEWER"`
+}
+
+// PATCH /api/organizations/{id}/members/{userId} — только OWNER
+func (h *OrganizationHandler) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	orgID, myRole, ok := h.memberContext(w, r)
+	if !ok {
+		return
+	}
+	if myRole != domain.OrgRoleOwner {
+		writeError(w, http.StatusForbidden, CodeForbidden, "organization.owner_only_roles")
+		return
+	}
+
+	memberID, err := strconv.ParseUint(strings.TrimSpace(chi.URLParam(r, "userId")), 10, 64)
+	if err != nil || memberID == 0 {
+		writeFieldError(w, "userId", "query.invalid_user_id")
+		return
+	}
+
+	var req updateMemberReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	req.Role = domain.OrgRole(strings.ToUpper(string(req.Role)))
+
+	current, err := h.orgs.GetMemberRole(r.Context(), orgID, memberID)
+	if err != nil {
+		internalError(w, "db.error", err)
+		return
+	}
+	if current == "" {
+		writeError(w, http.StatusNotFound, CodeNotFound, "organization.member_not_found")
+		return
+	}
+
+	if current == domain.OrgRoleOwner && req.Role != domain.OrgRoleOwner {
+		if !h.hasAnotherOwner(w, r, orgID) {
+			return
+		}
+	}
+
+	if err := h.orgs.UpdateMemberRole(r.Context(), orgID, memberID, req.Role); err != nil {
+		internalError(w, "organization.update_role_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+// DELETE /api/organizations/{id}/members/{userId} — OWNER исключает участника, либо участник выходит сам
+func (h *OrganizationHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	orgID, myRole, ok := h.memberContext(w, r)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.ParseUint(strings.TrimSpace(chi.URLParam(r, "userId")), 10, 64)
+	if err != nil || memberID == 0 {
+		writeFieldError(w, "userId", "query.invalid_user_id")
+		return
+	}
+
+	if myRole != domain.OrgRoleOwner && memberID != GetUserID(r) {
+		writeError(w, http.StatusForbidden, CodeForbidden, "organization.owner_only_remove")
+		return
+	}
+
+	current, err := h.orgs.GetMemberRole(r.Context(), orgID, memberID)
+	if err != nil {
+		internalError(w, "db.error", err)
+		return
+	}
+	if current == "" {
+		writeError(w, http.StatusNotFound, CodeNotFound, "organization.member_not_found")
+		return
+	}
+
+	if current == domain.OrgRoleOwner {
+		if !h.hasAnotherOwner(w, r, orgID) {
+			return
+		}
+	}
+
+	if err := h.orgs.RemoveMember(r.Context(), orgID, memberID); err != nil {
+		internalError(w, "organization.remove_member_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+type createInviteReq struct {
+	Email string         `json:"email" validate:"trim,required,max=190,email"`
+	Role  domain.OrgRole `json:"role" validate:"trim,oneof=OWNER MANAGER VIEWER"` // по умолчанию VIEWER
+}
+
+// POST /api/organizations/{id}/invites — OWNER или MANAGER (MANAGER не может пригласить OWNER)
+func (h *OrganizationHandler) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	orgID, myRole, ok := h.memberContext(w, r)
+	if !ok {
+		return
+	}
+	if !myRole.CanManage() {
+		writeError(w, http.StatusForbidden, CodeForbidden, "invite.forbidden")
+		return
+	}
+
+	var req createInviteReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	email := strings.ToLower(req.Email)
+	req.Role = domain.OrgRole(strings.ToUpper(string(req.Role)))
+	if req.Role == "" {
+		req.Role = domain.OrgRoleViewer
+	}
+	if req.Role == domain.OrgRoleOwner && myRole != domain.OrgRoleOwner {
+		writeError(w, http.StatusForbidden, CodeForbidden, "invite.owner_forbidden")
+		return
+	}
+
+	token, err := newInviteToken()
+	if err != nil {
+		internalError(w, "invite.create_failed", err)
+		return
+	}
+
+	expiresAt := time.Now().Add(inviteTTL)
+	id, err := h.orgs.CreateInvite(r.Context(), orgID, email, req.Role, token, GetUserID(r), expiresAt)
+	if err != nil {
+		internalError(w, "invite.create_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, apiv1.InviteCreated{ID: id, Token: token, ExpiresAt: expiresAt})
+}
+
+// GET /api/organizations/{id}/invites — ожидающие приглашения (OWNER/MANAGER)
+func (h *OrganizationHandler) Invites(w http.ResponseWriter, r *http.Request) {
+	orgID, myRole, ok := h.memberContext(w, r)
+	if !ok {
+		return
+	}
+	if !myRole.CanManage() {
+		writeError(w, http.StatusForbidden, CodeForbidden, "organization.access_denied")
+		return
+	}
+
+	items, err := h.orgs.ListInvites(r.Context(), orgID)
+	if err != nil {
+		internalError(w, "invite.list_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.NewOrgInvites(items))
+}
+
+// GET /api/invites/my — приглашения на email текущего пользователя
+func (h *OrganizationHandler) MyInvites(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return
+	}
+
+	u, err := h.users.GetByID(r.Context(), uid)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "user.not_found")
+		return
+	}
+
+	items, err := h.orgs.ListInvitesByEmail(r.Context(), u.Email, time.Now())
+	if err != nil {
+		internalError(w, "invite.list_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.NewOrgInvites(items))
+}
+
+// POST /api/invites/{token}/accept
+func (h *OrganizationHandler) AcceptInvite(w http.ResponseWriter, r *http.Request) {
+	uid, inv, ok := h.inviteForCurrentUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.orgs.AcceptInvite(r.Context(), inv.ID, inv.OrganizationID, uid, inv.Role); err != nil {
+		internalError(w, "invite.accept_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.InviteAccepted{OK: true, OrganizationID: inv.OrganizationID})
+}
+
+// POST /api/invites/{token}/decline
+func (h *OrganizationHandler) DeclineInvite(w http.ResponseWriter, r *http.Request) {
+	_, inv, ok := h.inviteForCurrentUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.orgs.DeclineInvite(r.Context(), inv.ID); err != nil {
+		internalError(w, "invite.decline_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+// memberContext разбирает {id} организации и возвращает роль текущего пользователя в ней.
+// При ошибке сам пишет ответ и возвращает ok=false.
+func (h *OrganizationHandler) memberContext(w http.ResponseWriter, r *http.Request) (uint64, domain.OrgRole, bool) {
+	uid := GetUserID(r)
+	if uid == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return 0, "", false
+	}
+
+	orgID, err := strconv.ParseUint(strings.TrimSpace(chi.URLParam(r, "id")), 10, 64)
+	if err != nil || orgID == 0 {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "request.invalid_id")
+		return 0, "", false
+	}
+
+	org, err := h.orgs.GetByID(r.Context(), orgID)
+	if err != nil {
+		internalError(w, "db.error", err)
+		return 0, "", false
+	}
+	if org == nil {
+		writeError(w, http.StatusNotFound, CodeNotFound, "organization.not_found")
+		return 0, "", false
+	}
+
+	role, err := h.orgs.GetMemberRole(r.Context(), orgID, uid)
+	if err != nil {
+		internalError(w, "db.error", err)
+		return 0, "", false
+	}
+
+	return orgID, role, true
+}
+
+// hasAnotherOwner не даёт оставить организацию без владельца
+func (h *OrganizationHandler) hasAnotherOwner(w http.ResponseWriter, r *http.Request, orgID uint64) bool {
+	owners, err := h.orgs.CountOwners(r.Context(), orgID)
+	if err != nil {
+		internalError(w, "db.error", err)
+		return false
+	}
+	if owners <= 1 {
+		writeError(w, http.StatusConflict, CodeConflict, "organization.last_owner")
+		return false
+	}
+	return true
+}
+
+func (h *OrganizationHandler) inviteForCurrentUser(w http.ResponseWriter, r *http.Request) (uint64, *domain.OrgInvite, bool) {
+	uid := GetUserID(r)
+	if uid == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return 0, nil, false
+	}
+
+	token := strings.TrimSpace(chi.URLParam(r, "token"))
+	if token == "" {
+		writeFieldError(w, "token", "invite.token_required")
+		return 0, nil, false
+	}
+
+	inv, err := h.orgs.GetInviteByToken(r.Context(), token)
+	if err != nil {
+		internalError(w, "db.error", err)
+		return 0, nil, false
+	}
+	if inv == nil {
+		writeError(w, http.StatusNotFound, CodeNotFound, "invite.not_found")
+		return 0, nil, false
+	}
+
+	u, err := h.users.GetByID(r.Context(), uid)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "user.not_found")
+		return 0, nil, false
+	}
+
+	if !strings.EqualFold(u.Email, inv.Email) {
+		writeError(w, http.StatusForbidden, CodeForbidden, "invite.wrong_email")
+		return 0, nil, false
+	}
+	if inv.Status != domain.InvitePending {
+		writeError(w, http.StatusConflict, CodeConflict, "invite.used")
+		return 0, nil, false
+	}
+	if time.Now().After(inv.ExpiresAt) {
+		writeError(w, http.StatusGone, CodeInviteExpired, "INVITE_EXPIRED")
+		return 0, nil, false
+	}
+
+	return uid, inv, true
+}
+
+func newInviteToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
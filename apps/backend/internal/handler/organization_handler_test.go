@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/repo"
+)
+
+func newOrgRouter(h *OrganizationHandler, uid uint64) *chi.Mux {
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(w, withUID(req, uid))
+		})
+	})
+	r.Post("/api/organizations", h.Create)
+	r.Patch("/api/organizations/{id}/members/{userId}", h.UpdateMember)
+	r.Post("/api/organizations/{id}/invites", h.CreateInvite)
+	r.Post("/api/invites/{token}/accept", h.AcceptInvite)
+	return r
+}
+
+func expectOrgAndRole(mock sqlmock.Sqlmock, orgID, uid uint64, role string) {
+	mock.ExpectQuery("SELECT id, name, created_at FROM organizations").
+		WithArgs(orgID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at"}).AddRow(orgID, "ACME", time.Now()))
+	rows := sqlmock.NewRows([]string{"role"})
+	if role != "" {
+		rows.AddRow(role)
+	}
+	mock.ExpectQuery("SELECT role FROM organization_members").
+		WithArgs(orgID, uid).
+		WillReturnRows(rows)
+}
+
+func TestOrganizationHandler_Create_OK(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	h := NewOrganizationHandler(repo.NewOrganizationRepo(db), repo.NewUserRepo(db))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO organizations").WithArgs("ACME").WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectExec("INSERT INTO organization_members").WithArgs(int64(4), uint64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	body, _ := json.Marshal(map[string]any{"name": " ACME "})
+	req := httptest.NewRequest(http.MethodPost, "/api/organizations", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	newOrgRouter(h, 2).ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestOrganizationHandler_CreateInvite_ViewerForbidden(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	h := NewOrganizationHandler(repo.NewOrganizationRepo(db), repo.NewUserRepo(db))
+	expectOrgAndRole(mock, 4, 2, "VIEWER")
+
+	body, _ := json.Marshal(map[string]any{"email": "new@test.local", "role": "MANAGER"})
+	req := httptest.NewRequest(http.MethodPost, "/api/organizations/4/invites", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	newOrgRouter(h, 2).ServeHTTP(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestOrganizationHandler_CreateInvite_ManagerCannotInviteOwner(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	h := NewOrganizationHandler(repo.NewOrganizationRepo(db), repo.NewUserRepo(db))
+	expectOrgAndRole(mock, 4, 2, "MANAGER")
+
+	body, _ := json.Marshal(map[string]any{"email": "new@test.local", "role": "OWNER"})
+	req := httptest.NewRequest(http.MethodPost, "/api/organizations/4/invites", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	newOrgRouter(h, 2).ServeHTTP(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestOrganizationHandler_CreateInvite_OK(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	h := NewOrganizationHandler(repo.NewOrganizationRepo(db), repo.NewUserRepo(db))
+	expectOrgAndRole(mock, 4, 2, "OWNER")
+
+	mock.ExpectExec("INSERT INTO organization_invites").
+		WithArgs(uint64(4), "new@test.local", "MANAGER", sqlmock.AnyArg(), uint64(2), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(10, 1))
+
+	body, _ := json.Marshal(map[string]any{"email": "New@Test.local", "role": "MANAGER"})
+	req := httptest.NewRequest(http.MethodPost, "/api/organizations/4/invites", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	newOrgRouter(h, 2).ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201 got %d body=%s", rr.Code, rr.Body.String())
+	}
+
+	var resp map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+	if tok, _ := resp["token"].(string); len(tok) != 64 {
+		t.Fatalf("expected 64-char token, got %q", tok)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestOrganizationHandler_UpdateMember_LastOwner_409(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	h := NewOrganizationHandler(repo.NewOrganizationRepo(db), repo.NewUserRepo(db))
+	expectOrgAndRole(mock, 4, 2, "OWNER")
+
+	// target member is the (only) owner
+	mock.ExpectQuery("SELECT role FROM organization_members").
+		WithArgs(uint64(4), uint64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("OWNER"))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM organization_members").
+		WithArgs(uint64(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
+
+	body, _ := json.Marshal(map[string]any{"role": "VIEWER"})
+	req := httptest.NewRequest(http.MethodPatch, "/api/organizations/4/members/2", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	newOrgRouter(h, 2).ServeHTTP(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestOrganizationHandler_AcceptInvite_WrongEmail_403(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	h := NewOrganizationHandler(repo.NewOrganizationRepo(db), repo.NewUserRepo(db))
+	now := time.Now()
+
+	mock.ExpectQuery("FROM organization_invites").
+		WithArgs("tok").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "organization_id", "email", "role", "token", "invited_by", "status", "expires_at", "created_at",
+		}).AddRow(uint64(1), uint64(4), "invited@test.local", "VIEWER", "tok", uint64(2), "PENDING", now.Add(time.Hour), now))
+	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE id = \\?").
+		WithArgs(uint64(9)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at"}).
+			AddRow(uint64(9), "other@test.local", "Other", "INDIVIDUAL", "HASH", now))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/invites/tok/accept", nil)
+	rr := httptest.NewRecorder()
+
+	newOrgRouter(h, 9).ServeHTTP(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestOrganizationHandler_AcceptInvite_OK(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	h := NewOrganizationHandler(repo.NewOrganizationRepo(db), repo.NewUserRepo(db))
+	now := time.Now()
+
+	mock.ExpectQuery("FROM organization_invites").
+		WithArgs("tok").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "organization_id", "email", "role", "token", "invited_by", "status", "expires_at", "created_at",
+		}).AddRow(uint64(1), uint64(4), "invited@test.local", "MANAGER", "tok", uint64(2), "PENDING", now.Add(time.Hour), now))
+	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE id = \\?").
+		WithArgs(uint64(9)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at"}).
+			AddRow(uint64(9), "invited@test.local", "Invited", "INDIVIDUAL", "HASH", now))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO organization_members").
+		WithArgs(uint64(4), uint64(9), "MANAGER").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE organization_invites").
+		WithArgs(uint64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/invites/tok/accept", nil)
+	rr := httptest.NewRecorder()
+
+	newOrgRouter(h, 9).ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
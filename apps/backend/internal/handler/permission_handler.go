@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/apiv1"
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/policy"
+)
+
+type permissionStore interface {
+	ReplaceForRole(ctx context.Context, role domain.UserRole, perms []domain.Permission) error
+}
+
+type PermissionHandler struct {
+	store  permissionStore
+	policy *policy.Policy
+}
+
+func NewPermissionHandler(store permissionStore, policy *policy.Policy) *PermissionHandler {
+	return &PermissionHandler{store: store, policy: policy}
+}
+
+// GET /api/admin/permissions — список известных прав и текущая матрица ролей
+func (h *PermissionHandler) List(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, apiv1.Permissions{
+		Permissions: domain.AllPermissions,
+		Roles:       h.policy.Grants(),
+	})
+}
+
+type updateRolePermissionsReq struct {
+	Permissions []domain.Permission `json:"permissions"`
+}
+
+// PUT /api/admin/roles/{role}/permissions — заменить набор прав роли
+func (h *PermissionHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	role := domain.UserRole(strings.ToUpper(strings.TrimSpace(chi.URLParam(r, "role"))))
+	if !role.Valid() {
+		writeFieldError(w, "role", "permission.invalid_role")
+		return
+	}
+
+	var req updateRolePermissionsReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	var errs fieldErrors
+	seen := make(map[domain.Permission]struct{}, len(req.Permissions))
+	perms := make([]domain.Permission, 0, len(req.Permissions))
+	for i, p := range req.Permissions {
+		if !p.Valid() {
+			errs.add(fmt.Sprintf("permissions[%d]", i), "permission.unknown")
+			continue
+		}
+		if _, dup := seen[p]; dup {
+			continue
+		}
+		seen[p] = struct{}{}
+		perms = append(perms, p)
+	}
+	if errs.respond(w) {
+		return
+	}
+
+	// не даём админу отобрать у себя возможность чинить матрицу
+	if role == domain.RoleAdmin {
+		if _, ok := seen[domain.PermPermissionManage]; !ok {
+			writeError(w, http.StatusConflict, CodeConflict, "permission.admin_manage")
+			return
+		}
+	}
+
+	if err := h.store.ReplaceForRole(r.Context(), role, perms); err != nil {
+		internalError(w, "permission.save_failed", err)
+		return
+	}
+	if err := h.policy.Reload(r.Context()); err != nil {
+		internalError(w, "permission.apply_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.RolePermissions{Role: role, Permissions: perms})
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/policy"
+)
+
+type fakePermissionStore struct {
+	items []domain.RolePermission
+}
+
+func (f *fakePermissionStore) ListRolePermissions(ctx context.Context) ([]domain.RolePermission, error) {
+	return f.items, nil
+}
+
+func (f *fakePermissionStore) ReplaceForRole(ctx context.Context, role domain.UserRole, perms []domain.Permission) error {
+	kept := make([]domain.RolePermission, 0, len(f.items))
+	for _, it := range f.items {
+		if it.Role != role {
+			kept = append(kept, it)
+		}
+	}
+	for _, p := range perms {
+		kept = append(kept, domain.RolePermission{Role: role, Permission: p})
+	}
+	f.items = kept
+	return nil
+}
+
+func newPermissionRouter(h *PermissionHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Get("/api/admin/permissions", h.List)
+	r.Put("/api/admin/roles/{role}/permissions", h.UpdateRole)
+	return r
+}
+
+func TestPermissionHandler_UpdateRole_AppliesImmediately(t *testing.T) {
+	store := &fakePermissionStore{items: policy.DefaultGrants()}
+	pol := policy.New(store)
+	h := NewPermissionHandler(store, pol)
+
+	company := policy.Actor{UserID: 2, Role: domain.RoleCompany}
+	if pol.Can(company, domain.PermCategoryManage, policy.Target{}) {
+		t.Fatalf("COMPANY must not manage categories by default")
+	}
+
+	b, _ := json.Marshal(map[string]any{"permissions": []string{"organization:create", "category:manage"}})
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/roles/company/permissions", bytes.NewReader(b))
+	rr := httptest.NewRecorder()
+
+	newPermissionRouter(h).ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if !pol.Can(company, domain.PermCategoryManage, policy.Target{}) {
+		t.Fatalf("expected new grant to be applied")
+	}
+}
+
+func TestPermissionHandler_UpdateRole_UnknownPermission_400(t *testing.T) {
+	store := &fakePermissionStore{}
+	h := NewPermissionHandler(store, policy.New(store))
+
+	b, _ := json.Marshal(map[string]any{"permissions": []string{"booking:explode"}})
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/roles/COMPANY/permissions", bytes.NewReader(b))
+	rr := httptest.NewRecorder()
+
+	newPermissionRouter(h).ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestPermissionHandler_UpdateRole_AdminLockout_409(t *testing.T) {
+	store := &fakePermissionStore{}
+	h := NewPermissionHandler(store, policy.New(store))
+
+	b, _ := json.Marshal(map[string]any{"permissions": []string{"category:manage"}})
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/roles/ADMIN/permissions", bytes.NewReader(b))
+	rr := httptest.NewRecorder()
+
+	newPermissionRouter(h).ServeHTTP(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409 got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestPermissionHandler_List(t *testing.T) {
+	h := NewPermissionHandler(&fakePermissionStore{}, policy.Default())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/permissions", nil)
+	rr := httptest.NewRecorder()
+
+	newPermissionRouter(h).ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d", rr.Code)
+	}
+
+	var resp struct {
+		Permissions []string            `json:"permissions"`
+		Roles       map[string][]string `json:"roles"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if len(resp.Permissions) != len(domain.AllPermissions) || len(resp.Roles["ADMIN"]) == 0 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"bookinghub-backend/internal/ratelimit"
+)
+
+// RetryAfterHeader — через сколько секунд можно повторить запрос (в ответах 429)
+const RetryAfterHeader = "Retry-After"
+
+// RateLimit ограничивает частоту запросов: у каждого значения key(r) своё ведро токенов в store
+// под префиксом scope. Пустой ключ не ограничивается. Без токенов — 429 TOO_MANY_REQUESTS
+// с Retry-After. Если store недоступен, запрос пропускается: ограничитель не должен ронять API.
+func RateLimit(store ratelimit.Store, scope string, limit ratelimit.Limit, key func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			k := key(r)
+			if k == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ok, wait, err := store.Take(r.Context(), scope+":"+k, limit, time.Now())
+			if err != nil {
+				log.Printf("[%s] rate limit %s: %v", w.Header().Get(RequestIDHeader), scope, err)
+			}
+			if err == nil && !ok {
+				writeTooManyRequests(w, CodeTooManyRequests, wait)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientIP — IP клиента для ключа RateLimit. Ставится после middleware.RealIP, который
+// подменяет RemoteAddr адресом из X-Forwarded-For / X-Real-IP.
+func ClientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// UserKey — ID пользователя для ключа RateLimit; ставится после AuthMiddleware
+func UserKey(r *http.Request) string {
+	if uid := GetUserID(r); uid != 0 {
+		return strconv.FormatUint(uid, 10)
+	}
+	return ""
+}
+
+// writeTooManyRequests — 429 с кодом code и Retry-After
+func writeTooManyRequests(w http.ResponseWriter, code string, wait time.Duration) {
+	w.Header().Set(RetryAfterHeader, strconv.Itoa(ratelimit.RetryAfterSeconds(wait)))
+	writeError(w, http.StatusTooManyRequests, code, code)
+}
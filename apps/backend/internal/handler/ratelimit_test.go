@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bookinghub-backend/internal/ratelimit"
+	"bookinghub-backend/internal/repo"
+	"bookinghub-backend/internal/service"
+)
+
+func TestRateLimit_PerIP_429WithRetryAfter(t *testing.T) {
+	mw := RateLimit(ratelimit.NewMemoryStore(), "test", ratelimit.PerMinute(2), ClientIP)
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) }))
+
+	do := func(addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+		req.RemoteAddr = addr
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	for i := 0; i < 2; i++ {
+		if rr := do("10.0.0.1:1000"); rr.Code != http.StatusNoContent {
+			t.Fatalf("request %d: got %d", i+1, rr.Code)
+		}
+	}
+	// другой порт того же адреса — тот же клиент
+	rr := do("10.0.0.1:2000")
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 got %d", rr.Code)
+	}
+	if got := rr.Header().Get(RetryAfterHeader); got != "30" {
+		t.Fatalf("Retry-After = %q, want 30", got)
+	}
+	var body errorBody
+	_ = json.Unmarshal(rr.Body.Bytes(), &body)
+	if body.Error.Code != CodeTooManyRequests {
+		t.Fatalf("code = %q body=%s", body.Error.Code, rr.Body.String())
+	}
+
+	if rr := do("10.0.0.2:1000"); rr.Code != http.StatusNoContent {
+		t.Fatalf("other IP should pass, got %d", rr.Code)
+	}
+}
+
+func TestRateLimit_NoKeyPasses(t *testing.T) {
+	mw := RateLimit(ratelimit.NewMemoryStore(), "test", ratelimit.PerMinute(1), UserKey)
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) }))
+	for i := 0; i < 3; i++ {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/bookings", nil))
+		if rr.Code != http.StatusNoContent {
+			t.Fatalf("request %d without user: got %d", i+1, rr.Code)
+		}
+	}
+}
+
+func TestAuthHandler_Login_LockedAfterFailures(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock(t)
+	defer cleanup()
+
+	limits := ratelimit.NewMemoryStore()
+	h := NewAuthHandler(repo.NewUserRepo(dbx), service.NewAuthService("dev", 15)).
+		WithLoginLimits(limits, ratelimit.PerMinute(100), ratelimit.NewLockout(limits).WithAfter(2))
+
+	login := func() *httptest.ResponseRecorder {
+		b, _ := json.Marshal(map[string]any{"email": "Ghost@Test.local", "password": "x"})
+		rr := httptest.NewRecorder()
+		h.Login(rr, httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(b)))
+		return rr
+	}
+
+	// несуществующий email считается неудачей так же, как неверный пароль
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE email = \\? LIMIT 1").
+			WithArgs("ghost@test.local").
+			WillReturnError(sql.ErrNoRows)
+		if rr := login(); rr.Code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: expected 401 got %d body=%s", i+1, rr.Code, rr.Body.String())
+		}
+	}
+
+	// заблокирован: в БД не ходим, отвечаем 429 LOGIN_LOCKED
+	rr := login()
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get(RetryAfterHeader); got != "60" {
+		t.Fatalf("Retry-After = %q, want 60", got)
+	}
+	var body errorBody
+	_ = json.Unmarshal(rr.Body.Bytes(), &body)
+	if body.Error.Code != CodeLoginLocked {
+		t.Fatalf("code = %q", body.Error.Code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuthHandler_Login_PerEmailLimit(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock(t)
+	defer cleanup()
+
+	limits := ratelimit.NewMemoryStore()
+	h := NewAuthHandler(repo.NewUserRepo(dbx), service.NewAuthService("dev", 15)).
+		WithLoginLimits(limits, ratelimit.PerMinute(1), nil)
+
+	login := func() *httptest.ResponseRecorder {
+		b, _ := json.Marshal(map[string]any{"email": "a@test.local", "password": "x"})
+		rr := httptest.NewRecorder()
+		h.Login(rr, httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(b)))
+		return rr
+	}
+
+	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE email = \\? LIMIT 1").
+		WithArgs("a@test.local").
+		WillReturnError(sql.ErrNoRows)
+	if rr := login(); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 got %d", rr.Code)
+	}
+	rr := login()
+	if rr.Code != http.StatusTooManyRequests || rr.Header().Get(RetryAfterHeader) != "60" {
+		t.Fatalf("expected 429 with Retry-After 60, got %d %q", rr.Code, rr.Header().Get(RetryAfterHeader))
+	}
+}
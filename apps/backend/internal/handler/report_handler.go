@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"bookinghub-backend/internal/i18n"
+	"bookinghub-backend/internal/reporting"
+)
+
+type reportBuilder interface {
+	Build(ctx context.Context, p reporting.Params) (*reporting.Report, error)
+}
+
+type ReportHandler struct {
+	reports reportBuilder
+}
+
+func NewReportHandler(reports reportBuilder) *ReportHandler {
+	return &ReportHandler{reports: reports}
+}
+
+// GET /api/reports/owner?from=&to=&groupBy=day|week|month&format=json|csv&section=
+// Отчёт по объявлениям текущего пользователя (личным и его организаций)
+func (h *ReportHandler) Owner(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return
+	}
+	h.serve(w, r, uid)
+}
+
+// GET /api/admin/reports — то же по всей платформе, плюс выручка по владельцам
+func (h *ReportHandler) Platform(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, 0)
+}
+
+func (h *ReportHandler) serve(w http.ResponseWriter, r *http.Request, ownerUserID uint64) {
+	q := r.URL.Query()
+
+	p, err := reporting.ParseParams(q, time.Now().UTC())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, err.Error())
+		return
+	}
+	p.OwnerUserID = ownerUserID
+
+	format := strings.ToLower(strings.TrimSpace(q.Get("format")))
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		writeFieldError(w, "format", "query.invalid_format")
+		return
+	}
+
+	section := strings.ToLower(strings.TrimSpace(q.Get("section")))
+	if section == "" {
+		section = reporting.SectionSeries
+	}
+	if format == "csv" && (!reporting.ValidSection(section) || (section == reporting.SectionOwners && ownerUserID != 0)) {
+		writeFieldError(w, "section", "query.invalid_section")
+		return
+	}
+
+	rep, err := h.reports.Build(r.Context(), p)
+	if err != nil {
+		internalError(w, "report.build_failed", err)
+		return
+	}
+
+	if format == "json" {
+		writeJSON(w, http.StatusOK, rep)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := reporting.WriteCSV(&buf, rep, section, i18n.FromContext(r.Context())); err != nil {
+		internalError(w, "report.csv_failed", err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="report-`+section+`.csv"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"bookinghub-backend/internal/reporting"
+)
+
+type fakeReportBuilder struct {
+	got reporting.Params
+	rep *reporting.Report
+}
+
+func (f *fakeReportBuilder) Build(_ context.Context, p reporting.Params) (*reporting.Report, error) {
+	f.got = p
+	return f.rep, nil
+}
+
+func TestReportHandler_Owner_Unauthorized(t *testing.T) {
+	h := NewReportHandler(&fakeReportBuilder{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/reports/owner", nil)
+	rr := httptest.NewRecorder()
+	h.Owner(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 got %d", rr.Code)
+	}
+}
+
+func TestReportHandler_Owner_ScopesToCurrentUser(t *testing.T) {
+	fake := &fakeReportBuilder{rep: &reporting.Report{}}
+	h := NewReportHandler(fake)
+
+	req := withUID(httptest.NewRequest(http.MethodGet, "/api/reports/owner?from=2026-01-01&to=2026-01-31&groupBy=month", nil), 7)
+	rr := httptest.NewRecorder()
+	h.Owner(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if fake.got.OwnerUserID != 7 || fake.got.GroupBy != reporting.GroupMonth {
+		t.Fatalf("unexpected params: %+v", fake.got)
+	}
+}
+
+func TestReportHandler_BadGroupBy(t *testing.T) {
+	h := NewReportHandler(&fakeReportBuilder{})
+
+	req := withUID(httptest.NewRequest(http.MethodGet, "/api/reports/owner?groupBy=year", nil), 7)
+	rr := httptest.NewRecorder()
+	h.Owner(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 got %d", rr.Code)
+	}
+}
+
+func TestReportHandler_OwnerCannotExportOwnersSection(t *testing.T) {
+	h := NewReportHandler(&fakeReportBuilder{rep: &reporting.Report{}})
+
+	req := withUID(httptest.NewRequest(http.MethodGet, "/api/reports/owner?format=csv&section=owners", nil), 7)
+	rr := httptest.NewRecorder()
+	h.Owner(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 got %d", rr.Code)
+	}
+}
+
+func TestReportHandler_Platform_CSV(t *testing.T) {
+	fake := &fakeReportBuilder{rep: &reporting.Report{
+		Owners: []reporting.OwnerRevenue{{OwnerUserID: 2, Name: "Owner", Bookings: 3, Revenue: 4200}},
+	}}
+	h := NewReportHandler(fake)
+
+	req := withUID(httptest.NewRequest(http.MethodGet, "/api/admin/reports?format=csv&section=owners", nil), 1)
+	rr := httptest.NewRecorder()
+	h.Platform(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if fake.got.OwnerUserID != 0 {
+		t.Fatalf("platform report must not be scoped to owner")
+	}
+	if !strings.Contains(rr.Body.String(), "2,Owner,3,4200") {
+		t.Fatalf("unexpected csv: %s", rr.Body.String())
+	}
+}
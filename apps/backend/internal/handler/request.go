@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"bookinghub-backend/internal/i18n"
+	"bookinghub-backend/internal/validate"
+)
+
+// maxJSONBody — предел тела JSON-запроса. Загрузка изображений идёт multipart и ограничена отдельно.
+const maxJSONBody = 1 << 20
+
+// maxTextLen — предел для полей TEXT (65 535 байт): даже из 4-байтовых символов поместится
+const maxTextLen = 16000
+
+// decodeJSON читает тело запроса в dst и проверяет его по тегам validate. Неизвестные поля,
+// лишние данные после объекта и тело больше maxJSONBody отклоняются. При ошибке ответ уже
+// отправлен, и обработчик просто возвращается; нарушения правил приходят все сразу в details.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxJSONBody))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(dst)
+	if err == nil {
+		// после объекта допустимы только пробелы
+		if _, tokErr := dec.Token(); tokErr != io.EOF {
+			err = tokErr
+			if err == nil {
+				err = errors.New("лишние данные после JSON")
+			}
+		}
+	}
+	if err != nil {
+		writeDecodeError(w, err)
+		return false
+	}
+
+	if err := validate.Struct(dst); err != nil {
+		writeValidationErrors(w, err.(validate.Errors))
+		return false
+	}
+	return true
+}
+
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &tooLarge):
+		lang := responseLang(w)
+		writeAPIError(w, http.StatusRequestEntityTooLarge, APIError{
+			Code:    CodePayloadTooLarge,
+			Message: i18n.T(lang, "request.too_large", tooLarge.Limit>>10),
+		})
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, CodeValidationFailed,
+			FieldError{Field: typeErr.Field, Message: "validation.type"})
+	default:
+		if field, ok := unknownField(err); ok {
+			writeError(w, http.StatusBadRequest, CodeValidationFailed, CodeValidationFailed,
+				FieldError{Field: field, Message: "validation.unknown_field"})
+			return
+		}
+		writeError(w, http.StatusBadRequest, CodeInvalidJSON, "request.invalid_json")
+	}
+}
+
+// unknownField достаёт имя поля из ошибки DisallowUnknownFields: отдельного типа для неё в encoding/json нет
+func unknownField(err error) (string, bool) {
+	rest, ok := strings.CutPrefix(err.Error(), "json: unknown field ")
+	if !ok {
+		return "", false
+	}
+	field, uerr := strconv.Unquote(rest)
+	if uerr != nil {
+		return rest, true
+	}
+	return field, true
+}
+
+// writeValidationErrors — 400 VALIDATION_FAILED со всеми нарушениями, тексты на языке ответа
+func writeValidationErrors(w http.ResponseWriter, errs validate.Errors) {
+	lang := responseLang(w)
+	details := make([]FieldError, len(errs))
+	for i, e := range errs {
+		details[i] = FieldError{Field: e.Field, Message: validationMessage(lang, e)}
+	}
+	writeAPIError(w, http.StatusBadRequest, APIError{
+		Code:    CodeValidationFailed,
+		Message: i18n.T(lang, CodeValidationFailed),
+		Details: details,
+	})
+}
+
+func validationMessage(lang string, e validate.FieldError) string {
+	switch e.Rule {
+	case validate.RuleRequired:
+		return i18n.T(lang, "validation.required")
+	case validate.RuleEmail:
+		return i18n.T(lang, "validation.email")
+	case validate.RuleOneOf:
+		return i18n.T(lang, "validation.oneof", e.Param)
+	case validate.RuleMin, validate.RuleMax:
+		key := "validation." + e.Rule
+		switch e.Kind {
+		case "string":
+			key += "_len"
+		case "slice":
+			key += "_items"
+		}
+		return i18n.T(lang, key, e.Param)
+	}
+	return i18n.T(lang, "field.invalid")
+}
+
+// fieldErrors собирает нарушения, которые не выразить тегами: поля, обязательные лишь в части
+// запросов, повторы в списках. Как и decodeJSON, отдаёт их все одним ответом.
+type fieldErrors []FieldError
+
+func (fe *fieldErrors) add(field, message string) {
+	*fe = append(*fe, FieldError{Field: field, Message: message})
+}
+
+// require добавляет ошибку validation.required, если поле не заполнено
+func (fe *fieldErrors) require(field string, filled bool) {
+	if !filled {
+		fe.add(field, "validation.required")
+	}
+}
+
+// respond отвечает 400 VALIDATION_FAILED, если есть ошибки, и сообщает, был ли отправлен ответ
+func (fe fieldErrors) respond(w http.ResponseWriter) bool {
+	if len(fe) == 0 {
+		return false
+	}
+	writeError(w, http.StatusBadRequest, CodeValidationFailed, CodeValidationFailed, fe...)
+	return true
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"bookinghub-backend/internal/i18n"
+	"bookinghub-backend/internal/service"
+)
+
+func decodeRequest(body string, lang string) (*httptest.ResponseRecorder, bool, registerReq) {
+	var req registerReq
+	rr := httptest.NewRecorder()
+	if lang != "" {
+		setLang(rr, lang)
+	}
+	ok := decodeJSON(rr, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)), &req)
+	return rr, ok, req
+}
+
+func TestDecodeJSON_OK(t *testing.T) {
+	rr, ok, req := decodeRequest(`{"email":" a@example.com ","name":" Анна ","password":"secret1"}`, "")
+	if !ok {
+		t.Fatalf("expected ok, got %d %s", rr.Code, rr.Body.String())
+	}
+	if req.Email != "a@example.com" || req.Name != "Анна" {
+		t.Fatalf("fields not trimmed: %+v", req)
+	}
+}
+
+func TestDecodeJSON_AllFieldErrorsAtOnce(t *testing.T) {
+	rr, ok, _ := decodeRequest(`{"email":"nope","name":"","password":"123","accountType":"VIP"}`, i18n.EN)
+	if ok || rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+	got := decodeAPIError(t, rr)
+	if got.Code != CodeValidationFailed {
+		t.Fatalf("unexpected code: %+v", got)
+	}
+	fields := map[string]string{}
+	for _, d := range got.Details {
+		fields[d.Field] = d.Message
+	}
+	want := map[string]string{
+		"email":       "Invalid email address",
+		"name":        "This field is required",
+		"password":    "Must be at least 6 characters",
+		"accountType": "Allowed values: INDIVIDUAL, COMPANY",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Fatalf("details mismatch:\n got  %v\n want %v", fields, want)
+	}
+}
+
+func TestDecodeJSON_Rejects(t *testing.T) {
+	cases := []struct {
+		name   string
+		body   string
+		status int
+		code   string
+		field  string
+	}{
+		{"unknown field", `{"email":"a@example.com","name":"A","password":"secret1","admin":true}`, http.StatusBadRequest, CodeValidationFailed, "admin"},
+		{"wrong type", `{"email":"a@example.com","name":42,"password":"secret1"}`, http.StatusBadRequest, CodeValidationFailed, "name"},
+		{"trailing data", `{"email":"a@example.com","name":"A","password":"secret1"} {}`, http.StatusBadRequest, CodeInvalidJSON, ""},
+		{"syntax", `{"email":`, http.StatusBadRequest, CodeInvalidJSON, ""},
+		{"empty", ``, http.StatusBadRequest, CodeInvalidJSON, ""},
+		{"too large", `{"name":"` + strings.Repeat("x", maxJSONBody) + `"}`, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, ""},
+	}
+	for _, tc := range cases {
+		rr, ok, _ := decodeRequest(tc.body, "")
+		if ok || rr.Code != tc.status {
+			t.Fatalf("%s: expected %d, got %d %s", tc.name, tc.status, rr.Code, rr.Body.String())
+		}
+		got := decodeAPIError(t, rr)
+		if got.Code != tc.code {
+			t.Fatalf("%s: expected code %s, got %+v", tc.name, tc.code, got)
+		}
+		if tc.field != "" && (len(got.Details) != 1 || got.Details[0].Field != tc.field) {
+			t.Fatalf("%s: expected detail for %s, got %+v", tc.name, tc.field, got.Details)
+		}
+	}
+}
+
+// Лимит позиций в тегах DTO записан числом — он должен совпадать с лимитом сервиса
+func TestItemsLimitMatchesService(t *testing.T) {
+	want := "max=" + strconv.Itoa(service.MaxGroupItems)
+	for _, typ := range []reflect.Type{reflect.TypeOf(createGroupReq{}), reflect.TypeOf(createBundleReq{})} {
+		f, _ := typ.FieldByName("Items")
+		if !strings.Contains(f.Tag.Get("validate"), want) {
+			t.Fatalf("%s.Items: validate tag %q, want %s", typ.Name(), f.Tag.Get("validate"), want)
+		}
+	}
+}
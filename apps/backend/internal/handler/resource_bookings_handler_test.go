@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/repo"
+)
+
+func TestResourceBookingsHandler_Availability_RemainingUnits(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	h := NewResourceBookingsHandler(repo.NewBookingRepo(db))
+	start := time.Date(2030, 3, 4, 10, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+
+	// 10 ноутбуков: 3 на первый час, 4 на второй и 2 на весь интервал — пик 6
+	expectAvailability(mock, 5, 10, start, end).
+		AddRow(uint64(1), uint64(5), uint64(3), start, start.Add(time.Hour), 3, "APPROVED", nil, start, nil).
+		AddRow(uint64(2), uint64(5), uint64(4), start.Add(time.Hour), end, 4, "PENDING", nil, start, nil).
+		AddRow(uint64(3), uint64(5), uint64(6), start, end, 2, "APPROVED", nil, start, nil)
+
+	r := chi.NewRouter()
+	r.Get("/api/resources/{id}/availability", h.Availability)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/resources/5/availability?startAt=2030-03-04T10:00:00Z&endAt=2030-03-04T12:00:00Z", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	var got struct {
+		Capacity       int `json:"capacity"`
+		BookedUnits    int `json:"bookedUnits"`
+		RemainingUnits int `json:"remainingUnits"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Capacity != 10 || got.BookedUnits != 6 || got.RemainingUnits != 4 {
+		t.Fatalf("unexpected availability: %+v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceBookingsHandler_Availability_BadInterval_400(t *testing.T) {
+	h := NewResourceBookingsHandler(nil)
+
+	r := chi.NewRouter()
+	r.Get("/api/resources/{id}/availability", h.Availability)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/resources/5/availability?startAt=2030-03-04T12:00:00Z&endAt=2030-03-04T10:00:00Z", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestResourceBookingsHandler_List_ShowsBuffers(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	h := NewResourceBookingsHandler(repo.NewBookingRepo(db))
+	start := time.Date(2030, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	// день ресурса в Москве: 2030-03-04 00:00 MSK = 2030-03-03 21:00 UTC
+	mock.ExpectQuery("SELECT timezone FROM resources WHERE id = \\?").
+		WithArgs(uint64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"timezone"}).AddRow("Europe/Moscow"))
+	mock.ExpectQuery("FROM bookings WHERE resource_id = \\? AND status IN \\('PENDING','APPROVED'\\) AND start_at >= \\?").
+		WithArgs(uint64(5), timeEq{time.Date(2030, 3, 3, 21, 0, 0, 0, time.UTC)}, timeEq{time.Date(2030, 3, 4, 21, 0, 0, 0, time.UTC)}).
+		WillReturnRows(sqlmock.NewRows(bookingCols).
+			AddRow(uint64(1), uint64(5), uint64(3), start, start.Add(time.Hour), 1, "APPROVED", nil, start, nil))
+	mock.ExpectQuery("SELECT capacity, buffer_before_min, buffer_after_min FROM resources").
+		WithArgs(uint64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity", "buffer_before_min", "buffer_after_min"}).AddRow(1, 0, 30))
+
+	r := chi.NewRouter()
+	r.Get("/api/resources/{id}/bookings", h.List)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/resources/5/bookings?from=2030-03-04&to=2030-03-04", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	var items []domain.Booking
+	if err := json.Unmarshal(rr.Body.Bytes(), &items); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	// время брони не меняется, буферы — отдельными полями
+	b := items[0]
+	if !b.EndAt.Equal(start.Add(time.Hour)) || b.BufferStartAt == nil || !b.BufferStartAt.Equal(start) ||
+		b.BufferEndAt == nil || !b.BufferEndAt.Equal(start.Add(90*time.Minute)) {
+		t.Fatalf("unexpected booking: %+v", b)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -1,97 +1,595 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/apiv1"
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/geo"
+	"bookinghub-backend/internal/policy"
 	"bookinghub-backend/internal/repo"
+	"bookinghub-backend/internal/service"
 )
 
+type orgMembership interface {
+	GetMemberRole(ctx context.Context, orgID, userID uint64) (domain.OrgRole, error)
+}
+
 type ResourceHandler struct {
-	repo *repo.ResourceRepo
+	repo       *repo.ResourceRepo
+	categories *repo.CategoryRepo
+	orgs       orgMembership
+	policy     *policy.Policy
+	geocoder   geo.Geocoder
 }
 
-func NewResourceHandler(repo *repo.ResourceRepo) *ResourceHandler {
-	return &ResourceHandler{repo: repo}
+func NewResourceHandler(repo *repo.ResourceRepo, categories *repo.CategoryRepo, orgs orgMembership, policy *policy.Policy) *ResourceHandler {
+	return &ResourceHandler{repo: repo, categories: categories, orgs: orgs, policy: policy}
 }
 
+// WithGeocoder включает поиск координат по адресу, если клиент прислал адрес без них
+func (h *ResourceHandler) WithGeocoder(g geo.Geocoder) *ResourceHandler {
+	h.geocoder = g
+	return h
+}
+
+// GET /api/resources?categoryId=&attr.<code>=&attr.<code>.min=&attr.<code>.max=
+// &near=lat,lng&radiusKm=&bbox=west,south,east,north&sort=distance|newest
+// categoryId включает подкатегории
 func (h *ResourceHandler) List(w http.ResponseWriter, r *http.Request) {
-	items, err := h.repo.List(r.Context())
+	q := r.URL.Query()
+
+	filter, ok := parseGeoFilter(w, q)
+	if !ok {
+		return
+	}
+	if raw := strings.TrimSpace(q.Get("categoryId")); raw != "" {
+		categoryID, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || categoryID == 0 {
+			writeFieldError(w, "categoryId", "query.invalid_category_id")
+			return
+		}
+		cats, err := h.categories.List(r.Context())
+		if err != nil {
+			internalError(w, "category.list_failed", err)
+			return
+		}
+		filter.CategoryIDs = service.CategorySubtree(cats, categoryID)
+	}
+
+	attrFilters, err := service.ParseAttributeFilters(q)
+	if err != nil {
+		writeAttributeError(w, err)
+		return
+	}
+	filter.Attributes = attrFilters
+
+	items, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		internalError(w, "resource.list_failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.NewResources(items))
+}
+
+// parseGeoFilter — near/radiusKm/bbox/sort из query
+func parseGeoFilter(w http.ResponseWriter, q url.Values) (domain.ResourceFilter, bool) {
+	var filter domain.ResourceFilter
+
+	if raw := strings.TrimSpace(q.Get("near")); raw != "" {
+		p, err := geo.ParsePoint(raw)
+		if err != nil {
+			writeFieldError(w, "near", "near: "+err.Error())
+			return filter, false
+		}
+		filter.Near = &p
+		filter.RadiusKm = service.DefaultSearchRadiusKm
+		filter.SortByDistance = true
+	}
+	if raw := strings.TrimSpace(q.Get("radiusKm")); raw != "" {
+		radius, err := strconv.ParseFloat(raw, 64)
+		if err != nil || radius <= 0 || radius > service.MaxSearchRadiusKm {
+			writeFieldError(w, "radiusKm", "query.invalid_radius")
+			return filter, false
+		}
+		if filter.Near == nil {
+			writeFieldError(w, "radiusKm", "query.radius_without_near")
+			return filter, false
+		}
+		filter.RadiusKm = radius
+	}
+	if raw := strings.TrimSpace(q.Get("bbox")); raw != "" {
+		box, err := geo.ParseBox(raw)
+		if err != nil {
+			writeFieldError(w, "bbox", err.Error())
+			return filter, false
+		}
+		filter.BBox = &box
+	}
+
+	switch q.Get("sort") {
+	case "":
+	case "distance":
+		if filter.Near == nil {
+			writeFieldError(w, "sort", "query.sort_requires_near")
+			return filter, false
+		}
+	case "newest":
+		filter.SortByDistance = false
+	default:
+		writeFieldError(w, "sort", "query.invalid_sort")
+		return filter, false
+	}
+	return filter, true
+}
+
+// GET /api/resources/{id}
+func (h *ResourceHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id64, err := strconv.ParseUint(strings.TrimSpace(chi.URLParam(r, "id")), 10, 64)
+	if err != nil || id64 == 0 {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "resource.invalid_id")
+		return
+	}
+
+	res, err := h.repo.GetByID(r.Context(), id64)
 	if err != nil {
-		http.Error(w, "failed to list resources: "+err.Error(), http.StatusInternalServerError)
+		internalError(w, "resource.load_failed", err)
+		return
+	}
+	if res == nil {
+		writeError(w, http.StatusNotFound, CodeResourceNotFound, "resource.not_found")
 		return
 	}
-	writeJSON(w, http.StatusOK, items)
+
+	items := []domain.Resource{*res}
+	if err := h.repo.AttachDetails(r.Context(), items); err != nil {
+		internalError(w, "failed to get resource details", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.NewResource(items[0]))
 }
 
 type createResourceRequest struct {
-	CategoryID   uint64  `json:"categoryId"`
-	Title        string  `json:"title"`
-	Description  *string `json:"description"`
-	Location     *string `json:"location"`
-	PricePerHour int     `json:"pricePerHour"`
+	CategoryID     uint64  `json:"categoryId" validate:"required"`
+	OrganizationID *uint64 `json:"organizationId"`
+	Title          string  `json:"title" validate:"trim,required,max=150"`
+	Description    *string `json:"description" validate:"max=16000"` // maxTextLen
+	Location       *string `json:"location" validate:"max=150"`
+	PricePerHour   int     `json:"pricePerHour" validate:"min=0"`
+	// Capacity — сколько единиц можно забронировать одновременно; по умолчанию 1
+	Capacity *int `json:"capacity"`
+	// BufferBeforeMinutes/BufferAfterMinutes — перерыв до и после каждой брони; по умолчанию 0
+	BufferBeforeMinutes *int `json:"bufferBeforeMinutes"`
+	BufferAfterMinutes  *int `json:"bufferAfterMinutes"`
+	// Approval — режим подтверждения броней; по умолчанию MANUAL
+	Approval *domain.ApprovalRules `json:"approval"`
+	// Timezone — часовой пояс IANA; по умолчанию Europe/Moscow
+	Timezone *string `json:"timezone" validate:"max=64"`
+	// Address — структурированный адрес; без координат они ищутся геокодером
+	Address *domain.Address `json:"address"`
+	// Attributes — значения атрибутов категории по коду, например {"capacity": 12}
+	Attributes map[string]any `json:"attributes"`
 }
 
 func (h *ResourceHandler) My(w http.ResponseWriter, r *http.Request) {
 	ownerID := GetUserID(r)
 	if ownerID == 0 {
-		http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
 		return
 	}
 
 	items, err := h.repo.ListByOwner(r.Context(), ownerID)
 	if err != nil {
-		http.Error(w, "failed to list my resources: "+err.Error(), http.StatusInternalServerError)
+		internalError(w, "failed to list my resources", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, items)
+	writeJSON(w, http.StatusOK, apiv1.NewResources(items))
 }
 
 func (h *ResourceHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req createResourceRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid json", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	req.Title = strings.TrimSpace(req.Title)
-	if req.CategoryID == 0 {
-		http.Error(w, "categoryId is required", http.StatusBadRequest)
+	rules, ok := bookingRules(w, domain.BookingRules{Capacity: 1}, req.Capacity, req.BufferBeforeMinutes, req.BufferAfterMinutes)
+	if !ok {
 		return
 	}
-	if req.Title == "" {
-		http.Error(w, "title is required", http.StatusBadRequest)
+	approval, ok := approvalRules(w, domain.ApprovalRules{Mode: domain.ApprovalManual}, req.Approval)
+	if !ok {
 		return
 	}
-
-	if req.PricePerHour < 0 {
-		http.Error(w, "pricePerHour must be >= 0", http.StatusBadRequest)
+	timezone, ok := resourceTimezone(w, service.DefaultTimezone, req.Timezone)
+	if !ok {
 		return
 	}
 
 	ownerID := GetUserID(r)
 	if ownerID == 0 {
-		http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return
+	}
+
+	// объявление от имени организации может разместить только её OWNER/MANAGER:
+	// глобальное resource:edit даёт править чужие объявления, но не вступать в чужие организации
+	if req.OrganizationID != nil {
+		role, err := h.orgs.GetMemberRole(r.Context(), *req.OrganizationID, ownerID)
+		if err != nil {
+			internalError(w, "db.error", err)
+			return
+		}
+		if !role.CanManage() {
+			writeError(w, http.StatusForbidden, CodeForbidden, "organization.access_denied")
+			return
+		}
+	}
+
+	attrs, ok := h.resolveAttributes(w, r, req.CategoryID, req.Attributes, false)
+	if !ok {
+		return
+	}
+
+	var addr domain.Address
+	if req.Address != nil {
+		addr = *req.Address
+	}
+	if !h.resolveAddress(w, r, &addr) {
 		return
 	}
 
 	id, err := h.repo.Create(
 		r.Context(),
 		ownerID,
+		req.OrganizationID,
 		req.CategoryID,
 		req.Title,
 		req.Description,
 		req.Location,
+		addr,
+		timezone,
 		req.PricePerHour,
+		rules,
+		approval,
+		attrs,
 	)
 	if err != nil {
-		http.Error(w, "failed to create resource: "+err.Error(), http.StatusInternalServerError)
+		internalError(w, "resource.create_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, apiv1.ID{ID: id})
+}
+
+// updateResourceRequest — частичное изменение: непереданное поле не меняется
+type updateResourceRequest struct {
+	CategoryID *uint64 `json:"categoryId"`
+	Title      *string `json:"title" validate:"trim,max=150"`
+	// Description и Location: "" очищает поле
+	Description  *string `json:"description" validate:"max=16000"` // maxTextLen
+	Location     *string `json:"location" validate:"max=150"`
+	PricePerHour *int    `json:"pricePerHour" validate:"min=0"`
+	IsActive     *bool   `json:"isActive"`
+	// Capacity и буферы: не передано — не меняется
+	Capacity            *int `json:"capacity"`
+	BufferBeforeMinutes *int `json:"bufferBeforeMinutes"`
+	BufferAfterMinutes  *int `json:"bufferAfterMinutes"`
+	// Approval: не передано — не меняется, передано — заменяется целиком
+	Approval *domain.ApprovalRules `json:"approval"`
+	// Timezone: не передано — не меняется
+	Timezone *string `json:"timezone" validate:"max=64"`
+	// Address: не передано — адрес и координаты не меняются
+	Address *domain.Address `json:"address"`
+	// Attributes: не передано — сохраняются текущие значения (если категория не меняется)
+	Attributes map[string]any `json:"attributes"`
+}
+
+// PATCH /api/resources/{id}
+func (h *ResourceHandler) Update(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, map[string]any{"id": id})
+	id64, err := strconv.ParseUint(strings.TrimSpace(chi.URLParam(r, "id")), 10, 64)
+	if err != nil || id64 == 0 {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "resource.invalid_id")
+		return
+	}
+
+	res, err := h.repo.GetByID(r.Context(), id64)
+	if err != nil {
+		internalError(w, "resource.load_failed", err)
+		return
+	}
+	if res == nil {
+		writeError(w, http.StatusNotFound, CodeResourceNotFound, "resource.not_found")
+		return
+	}
+
+	allowed, err := h.canEdit(r.Context(), res, actorFromRequest(r))
+	if err != nil {
+		internalError(w, "db.error", err)
+		return
+	}
+	if !allowed {
+		writeError(w, http.StatusForbidden, CodeForbidden, "resource.not_owner")
+		return
+	}
+
+	var req updateResourceRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	// поле можно не передать, но нельзя передать пустым
+	if req.CategoryID != nil && *req.CategoryID == 0 {
+		writeFieldError(w, "categoryId", "validation.required")
+		return
+	}
+	if req.Title != nil && *req.Title == "" {
+		writeFieldError(w, "title", "validation.required")
+		return
+	}
+
+	upd := repo.ResourceUpdate{
+		CategoryID:   req.CategoryID,
+		Title:        req.Title,
+		Description:  req.Description,
+		Location:     req.Location,
+		PricePerHour: req.PricePerHour,
+		IsActive:     req.IsActive,
+	}
+
+	if req.Capacity != nil || req.BufferBeforeMinutes != nil || req.BufferAfterMinutes != nil {
+		rules, ok := bookingRules(w, res.BookingRules, req.Capacity, req.BufferBeforeMinutes, req.BufferAfterMinutes)
+		if !ok {
+			return
+		}
+		upd.Rules = &rules
+	}
+	if req.Approval != nil {
+		approval, ok := approvalRules(w, res.ApprovalRules, req.Approval)
+		if !ok {
+			return
+		}
+		upd.Approval = &approval
+	}
+	if req.Timezone != nil {
+		timezone, ok := resourceTimezone(w, res.Timezone, req.Timezone)
+		if !ok {
+			return
+		}
+		upd.Timezone = &timezone
+	}
+
+	categoryChanged := req.CategoryID != nil && *req.CategoryID != res.CategoryID
+	if req.Attributes != nil || categoryChanged {
+		categoryID := res.CategoryID
+		if categoryChanged {
+			categoryID = *req.CategoryID
+		}
+		// в архивной категории можно править уже размещённое объявление, но нельзя перенести туда новое
+		attrs, ok := h.resolveAttributes(w, r, categoryID, req.Attributes, !categoryChanged)
+		if !ok {
+			return
+		}
+		upd.Attributes = &attrs
+	}
+
+	if req.Address != nil {
+		addr := *req.Address
+		if !h.resolveAddress(w, r, &addr) {
+			return
+		}
+		upd.Address = &addr
+	}
+
+	if err := h.repo.Update(r.Context(), id64, upd); err != nil {
+		internalError(w, "resource.update_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+// GET /api/resources/{id}/opening-hours
+func (h *ResourceHandler) OpeningHours(w http.ResponseWriter, r *http.Request) {
+	id64, err := strconv.ParseUint(strings.TrimSpace(chi.URLParam(r, "id")), 10, 64)
+	if err != nil || id64 == 0 {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "resource.invalid_id")
+		return
+	}
+
+	items, err := h.repo.ListOpeningHours(r.Context(), id64)
+	if err != nil {
+		internalError(w, "opening_hours.load_failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.NewOpeningHours(items))
+}
+
+type updateOpeningHoursRequest struct {
+	Hours []domain.OpeningHours `json:"hours"`
+}
+
+// PUT /api/resources/{id}/opening-hours — заменить расписание; пустой список = круглосуточно
+func (h *ResourceHandler) UpdateOpeningHours(w http.ResponseWriter, r *http.Request) {
+	if GetUserID(r) == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return
+	}
+
+	id64, err := strconv.ParseUint(strings.TrimSpace(chi.URLParam(r, "id")), 10, 64)
+	if err != nil || id64 == 0 {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "resource.invalid_id")
+		return
+	}
+
+	res, err := h.repo.GetByID(r.Context(), id64)
+	if err != nil {
+		internalError(w, "resource.load_failed", err)
+		return
+	}
+	if res == nil {
+		writeError(w, http.StatusNotFound, CodeResourceNotFound, "resource.not_found")
+		return
+	}
+
+	allowed, err := h.canEdit(r.Context(), res, actorFromRequest(r))
+	if err != nil {
+		internalError(w, "db.error", err)
+		return
+	}
+	if !allowed {
+		writeError(w, http.StatusForbidden, CodeForbidden, "resource.not_owner")
+		return
+	}
+
+	var req updateOpeningHoursRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if err := service.ValidateOpeningHours(req.Hours); err != nil {
+		writeFieldError(w, "hours", err.Error())
+		return
+	}
+
+	if err := h.repo.ReplaceOpeningHours(r.Context(), id64, req.Hours); err != nil {
+		internalError(w, "opening_hours.save_failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+// resolveAttributes проверяет, что категория существует, и сверяет значения со схемой её атрибутов
+// (включая унаследованные от родительских категорий)
+func (h *ResourceHandler) resolveAttributes(w http.ResponseWriter, r *http.Request, categoryID uint64, raw map[string]any, allowArchived bool) ([]domain.AttributeValue, bool) {
+	cats, err := h.categories.List(r.Context())
+	if err != nil {
+		internalError(w, "category.list_failed", err)
+		return nil, false
+	}
+	path := service.CategoryPath(cats, categoryID)
+	if path == nil {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "category.not_found")
+		return nil, false
+	}
+	if !allowArchived && service.CategoryArchived(path) {
+		writeFieldError(w, "categoryId", "category.archived")
+		return nil, false
+	}
+
+	defs, err := h.categories.ListAttributes(r.Context(), service.CategoryIDs(path))
+	if err != nil {
+		internalError(w, "attribute.list_failed", err)
+		return nil, false
+	}
+	attrs, err := service.ResolveAttributeValues(defs, raw)
+	if err != nil {
+		writeAttributeError(w, err)
+		return nil, false
+	}
+	return attrs, true
+}
+
+// bookingRules применяет к текущим настройкам переданные capacity и буферы (nil — не менять)
+func bookingRules(w http.ResponseWriter, rules domain.BookingRules, capacity, before, after *int) (domain.BookingRules, bool) {
+	if capacity != nil {
+		rules.Capacity = *capacity
+	}
+	if before != nil {
+		rules.BufferBeforeMin = *before
+	}
+	if after != nil {
+		rules.BufferAfterMin = *after
+	}
+
+	if rules.Capacity < 1 || rules.Capacity > service.MaxResourceCapacity {
+		writeFieldError(w, "capacity", "capacity must be between 1 and 10000")
+		return rules, false
+	}
+	if rules.BufferBeforeMin < 0 || rules.BufferBeforeMin > service.MaxBufferMinutes ||
+		rules.BufferAfterMin < 0 || rules.BufferAfterMin > service.MaxBufferMinutes {
+		writeFieldError(w, "bufferBeforeMinutes", "bufferBeforeMinutes and bufferAfterMinutes must be between 0 and 1440")
+		return rules, false
+	}
+	return rules, true
+}
+
+// approvalRules — режим подтверждения из запроса (nil — текущий) с проверкой значений
+func approvalRules(w http.ResponseWriter, current domain.ApprovalRules, req *domain.ApprovalRules) (domain.ApprovalRules, bool) {
+	if req == nil {
+		if current.Mode == "" {
+			current.Mode = domain.ApprovalManual
+		}
+		return current, true
+	}
+	rules := *req
+	if rules.Mode == "" {
+		rules.Mode = domain.ApprovalManual
+	}
+	if !rules.Valid() {
+		writeFieldError(w, "approval", "approval.mode must be MANUAL, AUTO or CONDITIONAL, approval.maxHours between 1 and 168")
+		return rules, false
+	}
+	if rules.Mode == domain.ApprovalConditional &&
+		!rules.IfVerified && !rules.IfReturning && rules.MaxHours == nil && !rules.InBusinessHours {
+		writeFieldError(w, "approval", "CONDITIONAL approval needs at least one condition")
+		return rules, false
+	}
+	return rules, true
+}
+
+// resourceTimezone — пояс из запроса (nil — текущий), проверенный по базе IANA
+func resourceTimezone(w http.ResponseWriter, current string, req *string) (string, bool) {
+	name := current
+	if req != nil {
+		name = *req
+	}
+	loc, err := service.LoadZone(name)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidTimezone, err.Error(), FieldError{Field: "timezone", Message: err.Error()})
+		return "", false
+	}
+	return loc.String(), true
+}
+
+// resolveAddress проверяет адрес и при необходимости дополняет его координатами
+func (h *ResourceHandler) resolveAddress(w http.ResponseWriter, r *http.Request, addr *domain.Address) bool {
+	if err := service.NormalizeAddress(addr); err != nil {
+		writeFieldError(w, "address", err.Error())
+		return false
+	}
+	service.FillCoordinates(r.Context(), h.geocoder, addr)
+	return true
+}
+
+func (h *ResourceHandler) canEdit(ctx context.Context, res *domain.Resource, actor policy.Actor) (bool, error) {
+	return canEditResource(ctx, h.policy, h.orgs, res, actor)
+}
+
+// canEditResource — право resource:edit; роль в организации объявления запрашиваем только если она нужна
+func canEditResource(ctx context.Context, pol *policy.Policy, orgs orgMembership, res *domain.Resource, actor policy.Actor) (bool, error) {
+	target := policy.Target{OwnerUserID: res.OwnerUserID}
+	if ok := pol.Can(actor, domain.PermResourceEdit, target); ok || res.OrganizationID == nil {
+		return ok, nil
+	}
+	orgRole, err := orgs.GetMemberRole(ctx, *res.OrganizationID, actor.UserID)
+	if err != nil {
+		return false, err
+	}
+	target.OrgRole = orgRole
+	return pol.Can(actor, domain.PermResourceEdit, target), nil
 }
 
 func writeJSON(w http.ResponseWriter, status int, v any) {
@@ -3,15 +3,21 @@ package handler
 import (
 	"bytes"
 	"context"
+	"database/sql/driver"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
 	"github.com/jmoiron/sqlx"
 
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/geo"
+	"bookinghub-backend/internal/policy"
 	"bookinghub-backend/internal/repo"
 )
 
@@ -33,13 +39,14 @@ func TestResourceHandler_List_OK(t *testing.T) {
 	dbx, mock, cleanup := newSQLXMock2(t)
 	defer cleanup()
 
-	h := NewResourceHandler(repo.NewResourceRepo(dbx))
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
 
 	now := time.Now()
-	mock.ExpectQuery("SELECT id, owner_user_id, category_id, title, description, location, price_per_hour, is_active, created_at FROM resources ORDER BY id DESC").
+	mock.ExpectQuery("SELECT .* FROM resources ORDER BY id DESC").
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "owner_user_id", "category_id", "title", "description", "location", "price_per_hour", "is_active", "created_at",
-		}).AddRow(uint64(1), uint64(2), uint64(3), "Title", nil, nil, 100, true, now))
+			"id", "owner_user_id", "organization_id", "category_id", "title", "description", "location", "price_per_hour", "is_active", "created_at",
+		}).AddRow(uint64(1), uint64(2), nil, uint64(3), "Title", nil, nil, 100, true, now))
+	expectAttributeValues(mock)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/resources", nil)
 	rr := httptest.NewRecorder()
@@ -57,7 +64,7 @@ func TestResourceHandler_My_Unauthorized_401(t *testing.T) {
 	dbx, _, cleanup := newSQLXMock2(t)
 	defer cleanup()
 
-	h := NewResourceHandler(repo.NewResourceRepo(dbx))
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
 
 	req := httptest.NewRequest(http.MethodGet, "/api/resources/my", nil)
 	rr := httptest.NewRecorder()
@@ -72,14 +79,15 @@ func TestResourceHandler_My_OK(t *testing.T) {
 	dbx, mock, cleanup := newSQLXMock2(t)
 	defer cleanup()
 
-	h := NewResourceHandler(repo.NewResourceRepo(dbx))
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
 
 	now := time.Now()
-	mock.ExpectQuery("SELECT id, owner_user_id, category_id, title, description, location, price_per_hour, is_active, created_at FROM resources WHERE owner_user_id = \\? ORDER BY id DESC").
-		WithArgs(uint64(5)).
+	mock.ExpectQuery("SELECT .* FROM resources WHERE owner_user_id = \\? OR organization_id IN \\( SELECT organization_id FROM organization_members WHERE user_id = \\? \\) ORDER BY id DESC").
+		WithArgs(uint64(5), uint64(5)).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "owner_user_id", "category_id", "title", "description", "location", "price_per_hour", "is_active", "created_at",
-		}).AddRow(uint64(10), uint64(5), uint64(1), "Mine", nil, nil, 0, true, now))
+			"id", "owner_user_id", "organization_id", "category_id", "title", "description", "location", "price_per_hour", "is_active", "created_at",
+		}).AddRow(uint64(10), uint64(5), nil, uint64(1), "Mine", nil, nil, 0, true, now))
+	expectAttributeValues(mock)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/resources/my", nil)
 	req = req.WithContext(withUIDRes(req.Context(), 5))
@@ -98,7 +106,7 @@ func TestResourceHandler_Create_Validation_400(t *testing.T) {
 	dbx, _, cleanup := newSQLXMock2(t)
 	defer cleanup()
 
-	h := NewResourceHandler(repo.NewResourceRepo(dbx))
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
 
 	body := map[string]any{"categoryId": 0, "title": ""}
 	b, _ := json.Marshal(body)
@@ -117,11 +125,15 @@ func TestResourceHandler_Create_OK_201(t *testing.T) {
 	dbx, mock, cleanup := newSQLXMock2(t)
 	defer cleanup()
 
-	h := NewResourceHandler(repo.NewResourceRepo(dbx))
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
 
-	mock.ExpectExec("INSERT INTO resources \\(owner_user_id, category_id, title, description, location, price_per_hour\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?\\)").
-		WithArgs(uint64(7), uint64(2), "Hello", nil, nil, 100).
+	expectCategories(mock)
+	expectCategoryAttributes(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO resources \\(owner_user_id, organization_id, category_id, title, description, location, address_line, .*, price_per_hour, capacity, buffer_before_min, buffer_after_min, approval_mode, .*\\)").
+		WithArgs(uint64(7), nil, uint64(2), "Hello", nil, nil, nil, nil, nil, nil, nil, nil, nil, "Europe/Moscow", 100, 1, 0, 0, "MANUAL", false, false, nil, false).
 		WillReturnResult(sqlmock.NewResult(55, 1))
+	mock.ExpectCommit()
 
 	body := map[string]any{
 		"categoryId":   2,
@@ -144,3 +156,450 @@ func TestResourceHandler_Create_OK_201(t *testing.T) {
 		t.Fatalf("expectations: %v", err)
 	}
 }
+
+func TestResourceHandler_Create_AdminNotOrgMember_403(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock2(t)
+	defer cleanup()
+
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
+
+	// у ADMIN есть глобальное resource:edit, но в организации 4 он не состоит
+	mock.ExpectQuery("SELECT role FROM organization_members").
+		WithArgs(uint64(4), uint64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}))
+
+	b, _ := json.Marshal(map[string]any{"categoryId": 2, "title": "Hello", "pricePerHour": 100, "organizationId": 4})
+	req := httptest.NewRequest(http.MethodPost, "/api/resources", bytes.NewReader(b))
+	ctx := context.WithValue(withUIDRes(req.Context(), 7), ctxRole, domain.RoleAdmin)
+	rr := httptest.NewRecorder()
+
+	h.Create(rr, req.WithContext(ctx))
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceHandler_Create_OrgManager_201(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock2(t)
+	defer cleanup()
+
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
+
+	mock.ExpectQuery("SELECT role FROM organization_members").
+		WithArgs(uint64(4), uint64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("MANAGER"))
+	expectCategories(mock)
+	expectCategoryAttributes(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO resources").
+		WillReturnResult(sqlmock.NewResult(55, 1))
+	mock.ExpectCommit()
+
+	b, _ := json.Marshal(map[string]any{"categoryId": 2, "title": "Hello", "pricePerHour": 100, "organizationId": 4})
+	req := httptest.NewRequest(http.MethodPost, "/api/resources", bytes.NewReader(b))
+	req = req.WithContext(withUIDRes(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	h.Create(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func resourceRow(id, owner uint64, orgID any) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"id", "owner_user_id", "organization_id", "category_id", "title", "description", "location", "price_per_hour", "capacity", "is_active", "created_at",
+	}).AddRow(id, owner, orgID, uint64(1), "Room", nil, nil, 100, 1, true, time.Now())
+}
+
+func TestResourceHandler_Update_OrgViewer_403(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock2(t)
+	defer cleanup()
+
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
+
+	mock.ExpectQuery("FROM resources WHERE id = \\?").
+		WithArgs(uint64(3)).
+		WillReturnRows(resourceRow(3, 100, uint64(4)))
+	mock.ExpectQuery("SELECT role FROM organization_members").
+		WithArgs(uint64(4), uint64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("VIEWER"))
+
+	r := chi.NewRouter()
+	r.Patch("/api/resources/{id}", h.Update)
+
+	b, _ := json.Marshal(map[string]any{"categoryId": 1, "title": "New", "pricePerHour": 10})
+	req := httptest.NewRequest(http.MethodPatch, "/api/resources/3", bytes.NewReader(b))
+	req = req.WithContext(withUIDRes(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceHandler_Update_OrgManager_OK(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock2(t)
+	defer cleanup()
+
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
+
+	mock.ExpectQuery("FROM resources WHERE id = \\?").
+		WithArgs(uint64(3)).
+		WillReturnRows(resourceRow(3, 100, uint64(4)))
+	mock.ExpectQuery("SELECT role FROM organization_members").
+		WithArgs(uint64(4), uint64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("MANAGER"))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE resources SET category_id = \\?, title = \\?, price_per_hour = \\?, is_active = \\? WHERE id = \\?").
+		WithArgs(uint64(1), "New", 10, false, uint64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	r := chi.NewRouter()
+	r.Patch("/api/resources/{id}", h.Update)
+
+	b, _ := json.Marshal(map[string]any{"categoryId": 1, "title": "New", "pricePerHour": 10, "isActive": false})
+	req := httptest.NewRequest(http.MethodPatch, "/api/resources/3", bytes.NewReader(b))
+	req = req.WithContext(withUIDRes(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceHandler_Update_PartialKeepsOmittedFields(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock2(t)
+	defer cleanup()
+
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
+
+	mock.ExpectQuery("FROM resources WHERE id = \\?").
+		WithArgs(uint64(3)).
+		WillReturnRows(resourceRow(3, 7, nil))
+	// ни категорий, ни атрибутов: меняется только цена
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE resources SET price_per_hour = \\? WHERE id = \\?$").
+		WithArgs(250, uint64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	r := chi.NewRouter()
+	r.Patch("/api/resources/{id}", h.Update)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/resources/3", strings.NewReader(`{"pricePerHour":250}`))
+	req = req.WithContext(withUIDRes(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceHandler_Update_EmptyTitle_400(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock2(t)
+	defer cleanup()
+
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
+
+	mock.ExpectQuery("FROM resources WHERE id = \\?").
+		WithArgs(uint64(3)).
+		WillReturnRows(resourceRow(3, 7, nil))
+
+	r := chi.NewRouter()
+	r.Patch("/api/resources/{id}", h.Update)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/resources/3", strings.NewReader(`{"title":"  "}`))
+	req = req.WithContext(withUIDRes(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceHandler_UpdateOpeningHours_Invalid_400(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock2(t)
+	defer cleanup()
+
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
+	expectResource(mock, 3, 2)
+
+	body, _ := json.Marshal(map[string]any{"hours": []map[string]any{{"weekday": 1, "opensAt": "18:00", "closesAt": "09:00"}}})
+	req := httptest.NewRequest(http.MethodPut, "/api/resources/3/opening-hours", bytes.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "3")
+	req = req.WithContext(context.WithValue(withUIDRes(req.Context(), 2), chi.RouteCtxKey, rctx))
+	rr := httptest.NewRecorder()
+
+	h.UpdateOpeningHours(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceHandler_Create_AttributeValidation_400(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock2(t)
+	defer cleanup()
+
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
+	expectCategories(mock)
+	expectCategoryAttributes(mock, []driver.Value{uint64(5), uint64(1), "capacity", "Вместимость", "int", true, nil, 0, time.Now()})
+
+	b, _ := json.Marshal(map[string]any{"categoryId": 2, "title": "Room", "attributes": map[string]any{"capacity": 2.5}})
+	req := httptest.NewRequest(http.MethodPost, "/api/resources", bytes.NewReader(b))
+	req = req.WithContext(withUIDRes(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	h.Create(rr, req)
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "capacity") {
+		t.Fatalf("expected 400 about capacity got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceHandler_Create_WithAttributes_201(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock2(t)
+	defer cleanup()
+
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
+	capacity := 12.0
+	expectCategories(mock)
+	expectCategoryAttributes(mock, []driver.Value{uint64(5), uint64(1), "capacity", "Вместимость", "int", true, nil, 0, time.Now()})
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO resources").
+		WithArgs(uint64(7), nil, uint64(2), "Room", nil, nil, nil, nil, nil, nil, nil, nil, nil, "Europe/Moscow", 0, 1, 0, 0, "MANUAL", false, false, nil, false).
+		WillReturnResult(sqlmock.NewResult(55, 1))
+	mock.ExpectExec("INSERT INTO resource_attribute_values").
+		WithArgs(uint64(55), uint64(5), "12", &capacity).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	b, _ := json.Marshal(map[string]any{"categoryId": 2, "title": "Room", "attributes": map[string]any{"capacity": 12}})
+	req := httptest.NewRequest(http.MethodPost, "/api/resources", bytes.NewReader(b))
+	req = req.WithContext(withUIDRes(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	h.Create(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceHandler_Create_UnknownCategory_400(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock2(t)
+	defer cleanup()
+
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
+	expectCategories(mock)
+
+	b, _ := json.Marshal(map[string]any{"categoryId": 99, "title": "Room"})
+	req := httptest.NewRequest(http.MethodPost, "/api/resources", bytes.NewReader(b))
+	req = req.WithContext(withUIDRes(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	h.Create(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestResourceHandler_List_FilterBySubtreeAndAttribute(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock2(t)
+	defer cleanup()
+
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
+	expectCategories(mock)
+	mock.ExpectQuery("FROM resources WHERE category_id IN \\(\\?, \\?\\) AND EXISTS").
+		WithArgs(uint64(1), uint64(2), "capacity", 10.0).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "owner_user_id", "organization_id", "category_id", "title", "description", "location", "price_per_hour", "is_active", "created_at",
+		}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/resources?categoryId=1&attr.capacity.min=10", nil)
+	rr := httptest.NewRecorder()
+
+	h.List(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceHandler_List_BadAttributeFilter_400(t *testing.T) {
+	dbx, _, cleanup := newSQLXMock2(t)
+	defer cleanup()
+
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/resources?attr.capacity.min=many", nil)
+	rr := httptest.NewRecorder()
+
+	h.List(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestResourceHandler_Create_ArchivedCategory_400(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock2(t)
+	defer cleanup()
+
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
+	expectCategories(mock)
+
+	// 5 — подкатегория архивной категории 4
+	b, _ := json.Marshal(map[string]any{"categoryId": 5, "title": "Room"})
+	req := httptest.NewRequest(http.MethodPost, "/api/resources", bytes.NewReader(b))
+	req = req.WithContext(withUIDRes(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	h.Create(rr, req)
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "архив") {
+		t.Fatalf("expected 400 about archive got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestResourceHandler_List_BadGeoParams_400(t *testing.T) {
+	h := NewResourceHandler(nil, nil, nil, policy.Default())
+
+	for _, q := range []string{
+		"near=91,10",
+		"near=55.75",
+		"near=55.75,37.61&radiusKm=0",
+		"near=55.75,37.61&radiusKm=1000",
+		"radiusKm=5",
+		"bbox=37,56,38",
+		"bbox=37,56,38,55",
+		"sort=distance",
+		"near=55.75,37.61&sort=price",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/resources?"+q, nil)
+		rr := httptest.NewRecorder()
+
+		h.List(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400 got %d body=%s", q, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestResourceHandler_List_NearWithRadius(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock2(t)
+	defer cleanup()
+
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
+
+	mock.ExpectQuery("AS distance_km FROM resources WHERE .* HAVING distance_km <= \\? ORDER BY id DESC").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "is_active", "created_at", "distance_km"}).
+			AddRow(uint64(1), "Room", true, time.Now(), 1.5))
+	expectAttributeValues(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/resources?near=55.75,37.61&radiusKm=3&sort=newest", nil)
+	rr := httptest.NewRecorder()
+
+	h.List(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), `"distanceKm":1.5`) {
+		t.Fatalf("expected distance in response: %s", rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceHandler_Create_GeocodesAddress_201(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock2(t)
+	defer cleanup()
+
+	g := geo.NewOfflineGeocoder(map[string]geo.Point{
+		"Тверская, 1, Москва": {Lat: 55.757, Lng: 37.615},
+	})
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default()).
+		WithGeocoder(g)
+
+	expectCategories(mock)
+	expectCategoryAttributes(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO resources").
+		WithArgs(uint64(7), nil, uint64(2), "Room", nil, nil,
+			"Тверская, 1", "Москва", nil, nil, 55.757, 37.615, geo.Encode(geo.Point{Lat: 55.757, Lng: 37.615}, geo.GeohashPrecision), "Europe/Moscow", 0, 1, 0, 0, "MANUAL", false, false, nil, false).
+		WillReturnResult(sqlmock.NewResult(55, 1))
+	mock.ExpectCommit()
+
+	b, _ := json.Marshal(map[string]any{
+		"categoryId": 2,
+		"title":      "Room",
+		"address":    map[string]any{"line": " Тверская, 1 ", "city": "Москва"},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/resources", bytes.NewReader(b))
+	req = req.WithContext(withUIDRes(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	h.Create(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceHandler_Create_InvalidAddress_400(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock2(t)
+	defer cleanup()
+
+	h := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
+
+	expectCategories(mock)
+	expectCategoryAttributes(mock)
+
+	b, _ := json.Marshal(map[string]any{
+		"categoryId": 2,
+		"title":      "Room",
+		"address":    map[string]any{"latitude": 55.75},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/resources", bytes.NewReader(b))
+	req = req.WithContext(withUIDRes(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	h.Create(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/apiv1"
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/policy"
+	"bookinghub-backend/internal/repo"
+	"bookinghub-backend/internal/service"
+)
+
+const maxImagesPerUpload = 10
+
+type ResourceImageHandler struct {
+	resources *repo.ResourceRepo
+	images    *repo.ResourceImageRepo
+	svc       *service.ImageService
+	orgs      orgMembership
+	policy    *policy.Policy
+}
+
+func NewResourceImageHandler(
+	resources *repo.ResourceRepo,
+	images *repo.ResourceImageRepo,
+	svc *service.ImageService,
+	orgs orgMembership,
+	policy *policy.Policy,
+) *ResourceImageHandler {
+	return &ResourceImageHandler{resources: resources, images: images, svc: svc, orgs: orgs, policy: policy}
+}
+
+// GET /api/resources/{id}/images
+func (h *ResourceImageHandler) List(w http.ResponseWriter, r *http.Request) {
+	id64, err := strconv.ParseUint(strings.TrimSpace(chi.URLParam(r, "id")), 10, 64)
+	if err != nil || id64 == 0 {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "resource.invalid_id")
+		return
+	}
+
+	items, err := h.images.ListByResource(r.Context(), id64)
+	if err != nil {
+		internalError(w, "image.list_failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.NewImages(items))
+}
+
+// POST /api/resources/{id}/images — multipart/form-data, одно или несколько полей "file"
+func (h *ResourceImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	res, ok := h.editableResource(w, r)
+	if !ok {
+		return
+	}
+
+	// запас на заголовки частей multipart сверх размеров самих файлов
+	r.Body = http.MaxBytesReader(w, r.Body, h.svc.MaxBytes()*maxImagesPerUpload+1<<20)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "PAYLOAD_TOO_LARGE")
+			return
+		}
+		writeFieldError(w, "file", "image.multipart_required")
+		return
+	}
+	defer func() { _ = r.MultipartForm.RemoveAll() }()
+
+	files := r.MultipartForm.File["file"]
+	if len(files) == 0 {
+		writeFieldError(w, "file", "image.multipart_required")
+		return
+	}
+	if len(files) > maxImagesPerUpload {
+		writeFieldError(w, "file", "image.too_many_files")
+		return
+	}
+
+	created := make([]apiv1.Image, 0, len(files))
+	for _, fh := range files {
+		if fh.Size > h.svc.MaxBytes() {
+			writeError(w, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, service.ErrImageTooLarge.Error()+": "+fh.Filename)
+			return
+		}
+		f, err := fh.Open()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, CodeValidationFailed, "image.read_failed")
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(f, h.svc.MaxBytes()+1))
+		_ = f.Close()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, CodeValidationFailed, "image.read_failed")
+			return
+		}
+
+		img, err := h.svc.Upload(r.Context(), res.ID, data)
+		if err != nil {
+			writeImageError(w, err, fh.Filename)
+			return
+		}
+		created = append(created, apiv1.NewImage(*img))
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func writeImageError(w http.ResponseWriter, err error, filename string) {
+	switch {
+	case errors.Is(err, service.ErrImageTooLarge):
+		writeError(w, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, err.Error()+": "+filename)
+	case errors.Is(err, service.ErrImageType):
+		writeError(w, http.StatusUnsupportedMediaType, CodeUnsupportedMedia, err.Error()+": "+filename)
+	case errors.Is(err, service.ErrImageUnreadable):
+		// причину декодера клиенту не показываем
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, service.ErrImageUnreadable.Error()+": "+filename)
+	default:
+		writeServiceError(w, err, "image.save_failed")
+	}
+}
+
+type reorderImagesRequest struct {
+	ImageIDs []uint64 `json:"imageIds"`
+}
+
+// PUT /api/resources/{id}/images/order — новый порядок, body: { "imageIds": [3, 1, 2] }
+func (h *ResourceImageHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	res, ok := h.editableResource(w, r)
+	if !ok {
+		return
+	}
+
+	var req reorderImagesRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	err := h.images.Reorder(r.Context(), res.ID, req.ImageIDs)
+	if errors.Is(err, repo.ErrImageOrderMismatch) {
+		writeFieldError(w, "imageIds", "image.invalid_order")
+		return
+	}
+	if err != nil {
+		internalError(w, "image.reorder_failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+// POST /api/resources/{id}/images/{imageId}/cover — сделать фото обложкой
+func (h *ResourceImageHandler) SetCover(w http.ResponseWriter, r *http.Request) {
+	res, ok := h.editableResource(w, r)
+	if !ok {
+		return
+	}
+	img, ok := h.image(w, r, res.ID)
+	if !ok {
+		return
+	}
+
+	if err := h.images.SetCover(r.Context(), res.ID, img.ID); err != nil {
+		internalError(w, "image.cover_failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+// DELETE /api/resources/{id}/images/{imageId}
+func (h *ResourceImageHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	res, ok := h.editableResource(w, r)
+	if !ok {
+		return
+	}
+	img, ok := h.image(w, r, res.ID)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), img); err != nil {
+		internalError(w, "image.delete_failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+// editableResource загружает ресурс из {id} и проверяет право resource:edit
+func (h *ResourceImageHandler) editableResource(w http.ResponseWriter, r *http.Request) (*domain.Resource, bool) {
+	if GetUserID(r) == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return nil, false
+	}
+
+	id64, err := strconv.ParseUint(strings.TrimSpace(chi.URLParam(r, "id")), 10, 64)
+	if err != nil || id64 == 0 {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "resource.invalid_id")
+		return nil, false
+	}
+
+	res, err := h.resources.GetByID(r.Context(), id64)
+	if err != nil {
+		internalError(w, "resource.load_failed", err)
+		return nil, false
+	}
+	if res == nil {
+		writeError(w, http.StatusNotFound, CodeResourceNotFound, "resource.not_found")
+		return nil, false
+	}
+
+	allowed, err := canEditResource(r.Context(), h.policy, h.orgs, res, actorFromRequest(r))
+	if err != nil {
+		internalError(w, "db.error", err)
+		return nil, false
+	}
+	if !allowed {
+		writeError(w, http.StatusForbidden, CodeForbidden, "resource.not_owner")
+		return nil, false
+	}
+	return res, true
+}
+
+func (h *ResourceImageHandler) image(w http.ResponseWriter, r *http.Request, resourceID uint64) (*domain.ResourceImage, bool) {
+	imageID, err := strconv.ParseUint(strings.TrimSpace(chi.URLParam(r, "imageId")), 10, 64)
+	if err != nil || imageID == 0 {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "image.invalid_id")
+		return nil, false
+	}
+
+	img, err := h.images.GetByID(r.Context(), resourceID, imageID)
+	if err != nil {
+		internalError(w, "db.error", err)
+		return nil, false
+	}
+	if img == nil {
+		writeError(w, http.StatusNotFound, CodeNotFound, "image.not_found")
+		return nil, false
+	}
+	return img, true
+}
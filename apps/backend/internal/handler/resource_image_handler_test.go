@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/policy"
+	"bookinghub-backend/internal/repo"
+	"bookinghub-backend/internal/service"
+	"bookinghub-backend/internal/storage"
+)
+
+func newImagesRouter(h *ResourceImageHandler, uid uint64) *chi.Mux {
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(w, withUID(req, uid))
+		})
+	})
+	r.Post("/api/resources/{id}/images", h.Upload)
+	return r
+}
+
+func multipartBody(t *testing.T, files map[string][]byte) (*bytes.Buffer, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for name, data := range files {
+		fw, err := mw.CreateFormFile("file", name)
+		if err != nil {
+			t.Fatalf("CreateFormFile: %v", err)
+		}
+		_, _ = fw.Write(data)
+	}
+	_ = mw.Close()
+	return &buf, mw.FormDataContentType()
+}
+
+func newTestImageHandler(t *testing.T) (*ResourceImageHandler, sqlmock.Sqlmock, func()) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	images := repo.NewResourceImageRepo(db, nil)
+	svc := service.NewImageService(images, storage.NewLocalStore(t.TempDir(), "/uploads"), 0, 0)
+	h := NewResourceImageHandler(repo.NewResourceRepo(db), images, svc, repo.NewOrganizationRepo(db), policy.Default())
+	return h, mock, cleanup
+}
+
+func TestResourceImageHandler_Upload_OK(t *testing.T) {
+	h, mock, cleanup := newTestImageHandler(t)
+	defer cleanup()
+
+	var pngBuf bytes.Buffer
+	_ = png.Encode(&pngBuf, image.NewRGBA(image.Rect(0, 0, 20, 10)))
+
+	expectResource(mock, 3, 2)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM resource_images WHERE resource_id = \\?").
+		WithArgs(uint64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM resource_images WHERE resource_id = \\? FOR UPDATE").
+		WithArgs(uint64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"next_position", "covers"}).AddRow(0, 0))
+	mock.ExpectExec("INSERT INTO resource_images").
+		WithArgs(uint64(3), sqlmock.AnyArg(), sqlmock.AnyArg(), "image/png", sqlmock.AnyArg(), 20, 10, 0, true).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	body, ct := multipartBody(t, map[string][]byte{"room.png": pngBuf.Bytes()})
+	req := httptest.NewRequest(http.MethodPost, "/api/resources/3/images", body)
+	req.Header.Set("Content-Type", ct)
+	rr := httptest.NewRecorder()
+
+	newImagesRouter(h, 2).ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceImageHandler_Upload_WrongType_415(t *testing.T) {
+	h, mock, cleanup := newTestImageHandler(t)
+	defer cleanup()
+
+	expectResource(mock, 3, 2)
+
+	body, ct := multipartBody(t, map[string][]byte{"doc.png": []byte("%PDF-1.4 definitely not png")})
+	req := httptest.NewRequest(http.MethodPost, "/api/resources/3/images", body)
+	req.Header.Set("Content-Type", ct)
+	rr := httptest.NewRecorder()
+
+	newImagesRouter(h, 2).ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("expected 415 got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestResourceImageHandler_Upload_NotOwner_403(t *testing.T) {
+	h, mock, cleanup := newTestImageHandler(t)
+	defer cleanup()
+
+	expectResource(mock, 3, 9)
+
+	body, ct := multipartBody(t, map[string][]byte{"a.png": []byte("x")})
+	req := httptest.NewRequest(http.MethodPost, "/api/resources/3/images", body)
+	req.Header.Set("Content-Type", ct)
+	rr := httptest.NewRecorder()
+
+	newImagesRouter(h, 2).ServeHTTP(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 got %d", rr.Code)
+	}
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/policy"
+	"bookinghub-backend/internal/repo"
+)
+
+func newOccupancyRouter(h *ResourceOccupancyHandler, uid uint64) *chi.Mux {
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if uid != 0 {
+				req = withUID(req, uid)
+			}
+			next.ServeHTTP(w, req)
+		})
+	})
+	r.Get("/api/resources/{id}/occupancy", h.Get)
+	return r
+}
+
+func newTestOccupancyHandler(t *testing.T) (*ResourceOccupancyHandler, sqlmock.Sqlmock, func()) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	h := NewResourceOccupancyHandler(repo.NewResourceRepo(db), repo.NewBookingRepo(db), repo.NewOrganizationRepo(db), policy.Default())
+	return h, mock, cleanup
+}
+
+func expectResource(mock sqlmock.Sqlmock, id, ownerID uint64) {
+	expectResourceIn(mock, id, ownerID, "UTC")
+}
+
+func expectResourceIn(mock sqlmock.Sqlmock, id, ownerID uint64, tz string) {
+	mock.ExpectQuery("FROM resources WHERE id = \\?").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "owner_user_id", "organization_id", "category_id", "title", "description", "location", "timezone", "price_per_hour", "is_active", "created_at",
+		}).AddRow(id, ownerID, nil, uint64(1), "Room", nil, nil, tz, 100, true, time.Now()))
+}
+
+func TestResourceOccupancyHandler_Unauthorized(t *testing.T) {
+	h, _, cleanup := newTestOccupancyHandler(t)
+	defer cleanup()
+
+	rr := httptest.NewRecorder()
+	newOccupancyRouter(h, 0).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/resources/3/occupancy", nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 got %d", rr.Code)
+	}
+}
+
+func TestResourceOccupancyHandler_BadParams(t *testing.T) {
+	h, _, cleanup := newTestOccupancyHandler(t)
+	defer cleanup()
+
+	for _, url := range []string{
+		"/api/resources/3/occupancy?bucket=minute",
+		"/api/resources/3/occupancy?from=2026-01-10&to=2026-01-01",
+		"/api/resources/3/occupancy?bucket=hour&from=2026-01-01&to=2026-03-01",
+	} {
+		rr := httptest.NewRecorder()
+		newOccupancyRouter(h, 2).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400 got %d", url, rr.Code)
+		}
+	}
+}
+
+func TestResourceOccupancyHandler_NotOwner_403(t *testing.T) {
+	h, mock, cleanup := newTestOccupancyHandler(t)
+	defer cleanup()
+
+	expectResource(mock, 3, 9)
+
+	rr := httptest.NewRecorder()
+	newOccupancyRouter(h, 2).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/resources/3/occupancy", nil))
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 got %d", rr.Code)
+	}
+}
+
+func TestResourceOccupancyHandler_Owner_OK(t *testing.T) {
+	h, mock, cleanup := newTestOccupancyHandler(t)
+	defer cleanup()
+
+	from := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	expectResource(mock, 3, 2)
+	mock.ExpectQuery("FROM resource_opening_hours").
+		WithArgs(uint64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"weekday", "opens_at", "closes_at"}).AddRow(1, "09:00", "18:00"))
+	mock.ExpectQuery("status IN \\('PENDING','APPROVED'\\)").
+		WithArgs(uint64(3), to, from).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "resource_id", "user_id", "start_at", "end_at", "status", "manager_comment", "created_at", "updated_at"}).
+			AddRow(uint64(1), uint64(3), uint64(5), from.Add(10*time.Hour), from.Add(11*time.Hour), "PENDING", nil, from, nil))
+
+	rr := httptest.NewRecorder()
+	newOccupancyRouter(h, 2).ServeHTTP(rr, httptest.NewRequest(http.MethodGet,
+		"/api/resources/3/occupancy?from=2026-01-05&to=2026-01-05&bucket=hour&includePending=true", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Occupancy struct {
+			Buckets []struct {
+				BookedMinutes    int `json:"bookedMinutes"`
+				AvailableMinutes int `json:"availableMinutes"`
+			} `json:"buckets"`
+			Heatmap []any `json:"heatmap"`
+		} `json:"occupancy"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+	if len(resp.Occupancy.Buckets) != 24 || len(resp.Occupancy.Heatmap) != 7*24 {
+		t.Fatalf("unexpected response size: %s", rr.Body.String())
+	}
+	if b := resp.Occupancy.Buckets[10]; b.BookedMinutes != 60 || b.AvailableMinutes != 60 {
+		t.Fatalf("unexpected bucket 10: %+v", b)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceOccupancyHandler_DSTDay_InResourceZone(t *testing.T) {
+	h, mock, cleanup := newTestOccupancyHandler(t)
+	defer cleanup()
+
+	// 2026-03-29 в Берлине часы переводятся вперёд: сутки длятся 23 часа
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	from := time.Date(2026, 3, 29, 0, 0, 0, 0, berlin)
+	to := time.Date(2026, 3, 30, 0, 0, 0, 0, berlin)
+
+	expectResourceIn(mock, 3, 2, "Europe/Berlin")
+	mock.ExpectQuery("FROM resource_opening_hours").
+		WithArgs(uint64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"weekday", "opens_at", "closes_at"}).AddRow(7, "09:00", "18:00"))
+	mock.ExpectQuery("status IN \\('APPROVED'\\)").
+		WithArgs(uint64(3), timeEq{to}, timeEq{from}).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "resource_id", "user_id", "start_at", "end_at", "status", "created_at"}))
+
+	rr := httptest.NewRecorder()
+	newOccupancyRouter(h, 2).ServeHTTP(rr, httptest.NewRequest(http.MethodGet,
+		"/api/resources/3/occupancy?from=2026-03-29&to=2026-03-29&bucket=hour", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Occupancy struct {
+			Buckets []struct {
+				Start            time.Time `json:"start"`
+				AvailableMinutes int       `json:"availableMinutes"`
+			} `json:"buckets"`
+		} `json:"occupancy"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+	buckets := resp.Occupancy.Buckets
+	if len(buckets) != 23 {
+		t.Fatalf("expected 23 hourly buckets, got %d", len(buckets))
+	}
+	// 09:00 по местному времени — восьмой час после полуночи, а не девятый
+	if b := buckets[8]; !b.Start.Equal(time.Date(2026, 3, 29, 9, 0, 0, 0, berlin)) || b.AvailableMinutes != 60 {
+		t.Fatalf("unexpected 09:00 bucket: %+v", b)
+	}
+	if _, offset := buckets[8].Start.Zone(); offset != 2*3600 {
+		t.Fatalf("expected +02:00 offset in response, got %d", offset)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
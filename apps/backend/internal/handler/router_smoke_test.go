@@ -14,6 +14,7 @@ import (
 	"github.com/jmoiron/sqlx"
 
 	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/policy"
 	"bookinghub-backend/internal/repo"
 )
 
@@ -61,14 +62,18 @@ func TestRouter_Category_Delete_OK(t *testing.T) {
 
 	catH := NewCategoryHandler(repo.NewCategoryRepo(dbx))
 
+	expectCategories(mock)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM resources WHERE category_id = \\?").
+		WithArgs(uint64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 	mock.ExpectExec("DELETE FROM resource_categories WHERE id = \\?").
-		WithArgs(uint64(12)).
+		WithArgs(uint64(3)).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	r := chi.NewRouter()
 	r.Delete("/api/categories/{id}", catH.Delete)
 
-	req := httptest.NewRequest(http.MethodDelete, "/api/categories/12", nil)
+	req := httptest.NewRequest(http.MethodDelete, "/api/categories/3", nil)
 	rr := httptest.NewRecorder()
 
 	r.ServeHTTP(rr, req)
@@ -87,7 +92,7 @@ func TestRouter_User_PublicByID_OK(t *testing.T) {
 	userH := NewUserHandler(repo.NewUserRepo(dbx))
 
 	now := time.Now()
-	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at FROM users WHERE id = \\? LIMIT 1").
+	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE id = \\? LIMIT 1").
 		WithArgs(uint64(2)).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at"}).
 			AddRow(uint64(2), "u@test.local", "User", string(domain.RoleIndividual), "HASH", now))
@@ -111,7 +116,7 @@ func TestRouter_Resource_Create_Unauthorized_401(t *testing.T) {
 	dbx, _, cleanup := newSQLXMock5(t)
 	defer cleanup()
 
-	resH := NewResourceHandler(repo.NewResourceRepo(dbx))
+	resH := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
 
 	r := chi.NewRouter()
 	r.Post("/api/resources", resH.Create)
@@ -132,11 +137,15 @@ func TestRouter_Resource_Create_OK_201_WithContextUser(t *testing.T) {
 	dbx, mock, cleanup := newSQLXMock5(t)
 	defer cleanup()
 
-	resH := NewResourceHandler(repo.NewResourceRepo(dbx))
+	resH := NewResourceHandler(repo.NewResourceRepo(dbx), repo.NewCategoryRepo(dbx), repo.NewOrganizationRepo(dbx), policy.Default())
 
-	mock.ExpectExec("INSERT INTO resources \\(owner_user_id, category_id, title, description, location, price_per_hour\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?\\)").
-		WithArgs(uint64(9), uint64(1), "X", nil, nil, 0).
+	expectCategories(mock)
+	expectCategoryAttributes(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO resources \\(owner_user_id, organization_id, category_id, title, description, location, address_line, .*, price_per_hour, capacity, buffer_before_min, buffer_after_min, approval_mode, .*\\)").
+		WithArgs(uint64(9), nil, uint64(1), "X", nil, nil, nil, nil, nil, nil, nil, nil, nil, "Europe/Moscow", 0, 1, 0, 0, "MANUAL", false, false, nil, false).
 		WillReturnResult(sqlmock.NewResult(101, 1))
+	mock.ExpectCommit()
 
 	r := chi.NewRouter()
 	// имитируем auth: просто кладём userId в context
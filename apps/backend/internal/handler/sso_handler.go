@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"bookinghub-backend/internal/apiv1"
+	"bookinghub-backend/internal/oidc"
+	"bookinghub-backend/internal/service"
+)
+
+// WithSSO включает вход через корпоративного провайдера OpenID Connect.
+// Без него маршруты SSO отвечают 404.
+func (h *AuthHandler) WithSSO(p *oidc.Provider, sso *service.SSOService) *AuthHandler {
+	h.oidcProvider, h.sso = p, sso
+	return h
+}
+
+// GET /api/v1/auth/oidc/authorize — адрес страницы входа у провайдера (код авторизации с PKCE).
+// Сервер между шагами ничего не хранит: верификатор PKCE и nonce зашифрованы в state.
+func (h *AuthHandler) SSOAuthorize(w http.ResponseWriter, r *http.Request) {
+	if h.oidcProvider == nil {
+		writeError(w, http.StatusNotFound, CodeNotFound, "sso.not_configured")
+		return
+	}
+	authURL, state, expires, err := h.oidcProvider.Start(r.Context())
+	if err != nil {
+		h.ssoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.SSOAuthorization{AuthorizationURL: authURL, State: state, ExpiresAt: expires})
+}
+
+type ssoCallbackReq struct {
+	Code  string `json:"code" validate:"trim,required,max=2048"`
+	State string `json:"state" validate:"trim,required,max=2048"`
+}
+
+// POST /api/v1/auth/oidc/callback — завершение входа: code и state с адреса возврата.
+// Клиент сначала сверяет state с тем, что получил от authorize, — так чужой код не подсунуть.
+// Ответ — как у /auth/login: токен доступа или второй шаг 2FA.
+func (h *AuthHandler) SSOCallback(w http.ResponseWriter, r *http.Request) {
+	var req ssoCallbackReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if h.oidcProvider == nil {
+		writeError(w, http.StatusNotFound, CodeNotFound, "sso.not_configured")
+		return
+	}
+
+	claims, err := h.oidcProvider.Finish(r.Context(), req.Code, req.State)
+	if err != nil {
+		h.ssoError(w, err)
+		return
+	}
+	u, err := h.sso.SignIn(r.Context(), service.SSOIdentity{
+		Issuer:        h.oidcProvider.Issuer(),
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+		Groups:        claims.Groups,
+	})
+	if err != nil {
+		writeServiceError(w, err, "sso.failed")
+		return
+	}
+	h.finishLogin(w, r, u)
+}
+
+// ssoError — ошибки провайдера: недоступность — 502, всё остальное — отказ во входе (401).
+// Подробности остаются в логе сервера.
+func (h *AuthHandler) ssoError(w http.ResponseWriter, err error) {
+	log.Printf("[%s] sso: %v", w.Header().Get(RequestIDHeader), err)
+	switch {
+	case errors.Is(err, oidc.ErrUnavailable):
+		writeError(w, http.StatusBadGateway, CodeSSOUnavailable, "sso.unavailable")
+	case errors.Is(err, oidc.ErrInvalidState):
+		writeError(w, http.StatusUnauthorized, CodeSSOFailed, "sso.invalid_state")
+	default:
+		writeError(w, http.StatusUnauthorized, CodeSSOFailed, "sso.failed")
+	}
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"bookinghub-backend/internal/apiv1"
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/oidc"
+	"bookinghub-backend/internal/oidc/oidctest"
+	"bookinghub-backend/internal/repo"
+	"bookinghub-backend/internal/service"
+)
+
+// newSSOTestHandler — AuthHandler с входом через локальный провайдер; admins -> ADMIN
+func newSSOTestHandler(t *testing.T) (*AuthHandler, *oidctest.Server, sqlmock.Sqlmock) {
+	t.Helper()
+	dbx, mock, cleanup := newSQLXMock(t)
+	t.Cleanup(cleanup)
+	idp := oidctest.NewServer("bookinghub", "s3cret")
+	t.Cleanup(idp.Close)
+
+	auth := service.NewAuthService("dev", 15)
+	users := repo.NewUserRepo(dbx)
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:       idp.Issuer(),
+		ClientID:     "bookinghub",
+		ClientSecret: "s3cret",
+		RedirectURL:  "http://app.local/sso/callback",
+		StateKey:     auth.DeriveKey("test oidc state"),
+	})
+	sso := service.NewSSOService(repo.NewIdentityRepo(dbx), users).
+		WithRoleMap(map[string]domain.UserRole{"admins": domain.RoleAdmin})
+	return NewAuthHandler(users, auth).WithSSO(provider, sso), idp, mock
+}
+
+// ssoCallback проходит authorize и страницу провайдера и отправляет code и state в callback
+func ssoCallback(t *testing.T, h *AuthHandler, idp *oidctest.Server) *httptest.ResponseRecorder {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	h.SSOAuthorize(rr, httptest.NewRequest(http.MethodGet, "/api/v1/auth/oidc/authorize", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("authorize: %d %s", rr.Code, rr.Body.String())
+	}
+	var start apiv1.SSOAuthorization
+	_ = json.Unmarshal(rr.Body.Bytes(), &start)
+
+	code, state, err := idp.Login(start.AuthorizationURL)
+	if err != nil {
+		t.Fatalf("provider login: %v", err)
+	}
+	if state != start.State {
+		t.Fatalf("state вернулся другим")
+	}
+
+	b, _ := json.Marshal(map[string]string{"code": code, "state": state})
+	rr = httptest.NewRecorder()
+	h.SSOCallback(rr, httptest.NewRequest(http.MethodPost, "/api/v1/auth/oidc/callback", bytes.NewReader(b)))
+	return rr
+}
+
+func TestSSOCallback_CreatesUserWithMappedRole(t *testing.T) {
+	h, idp, mock := newSSOTestHandler(t)
+	idp.SetUser(&oidctest.User{Subject: "u-1", Email: "Ann@Corp.example", EmailVerified: true, Name: "Анна", Groups: []string{"admins"}})
+
+	mock.ExpectQuery("SELECT user_id\\s+FROM user_identities").WithArgs(idp.Issuer(), "u-1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+	mock.ExpectQuery("FROM users\\s+WHERE email = \\?").WithArgs("ann@corp.example").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("INSERT INTO users").
+		WithArgs("ann@corp.example", "Анна", domain.RoleIndividual, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(42, 1))
+	mock.ExpectExec("UPDATE users\\s+SET role = \\?").WithArgs(domain.RoleAdmin, uint64(42)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO user_identities").
+		WithArgs(uint64(42), idp.Issuer(), "u-1", "ann@corp.example", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT role, suspended_at, suspend_reason, sessions_revoked_at, locale FROM users WHERE id = \\?").
+		WithArgs(uint64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(string(domain.RoleAdmin)))
+
+	rr := ssoCallback(t, h, idp)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	var res apiv1.Auth
+	_ = json.Unmarshal(rr.Body.Bytes(), &res)
+	if res.AccessToken == "" || res.User.ID != 42 || res.User.Role != domain.RoleAdmin {
+		t.Fatalf("unexpected auth: %s", rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSSOCallback_EmailNotVerified(t *testing.T) {
+	h, idp, mock := newSSOTestHandler(t)
+	idp.SetUser(&oidctest.User{Subject: "u-2", Email: "admin@bookinghub.local"})
+
+	mock.ExpectQuery("SELECT user_id\\s+FROM user_identities").WithArgs(idp.Issuer(), "u-2").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+	rr := ssoCallback(t, h, idp)
+	if rr.Code != http.StatusForbidden || !bytes.Contains(rr.Body.Bytes(), []byte(CodeSSOEmailNotVerified)) {
+		t.Fatalf("expected 403 %s got %d body=%s", CodeSSOEmailNotVerified, rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSSOCallback_RejectsForeignState(t *testing.T) {
+	h, _, _ := newSSOTestHandler(t)
+
+	b, _ := json.Marshal(map[string]string{"code": "c", "state": "forged"})
+	rr := httptest.NewRecorder()
+	h.SSOCallback(rr, httptest.NewRequest(http.MethodPost, "/api/v1/auth/oidc/callback", bytes.NewReader(b)))
+	if rr.Code != http.StatusUnauthorized || !bytes.Contains(rr.Body.Bytes(), []byte(CodeSSOFailed)) {
+		t.Fatalf("expected 401 %s got %d body=%s", CodeSSOFailed, rr.Code, rr.Body.String())
+	}
+}
+
+func TestSSO_NotConfigured(t *testing.T) {
+	h := NewAuthHandler(nil, service.NewAuthService("dev", 15))
+
+	rr := httptest.NewRecorder()
+	h.SSOAuthorize(rr, httptest.NewRequest(http.MethodGet, "/api/v1/auth/oidc/authorize", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
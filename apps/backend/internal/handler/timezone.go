@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"bookinghub-backend/internal/service"
+)
+
+// zoneSource — часовой пояс ресурса по id; "" — ресурса нет или пояс не задан
+type zoneSource interface {
+	ResourceTimezone(ctx context.Context, resourceID uint64) (string, error)
+}
+
+// resourceClock разбирает время из запроса в поясе ресурса. Пояс читается из БД лениво —
+// только если во входных данных встретилось время без смещения.
+type resourceClock struct {
+	ctx        context.Context
+	zones      zoneSource
+	resourceID uint64
+	loc        *time.Location
+}
+
+func newResourceClock(ctx context.Context, zones zoneSource, resourceID uint64) *resourceClock {
+	return &resourceClock{ctx: ctx, zones: zones, resourceID: resourceID}
+}
+
+// Location — пояс ресурса; для несуществующего ресурса — пояс по умолчанию,
+// ресурс проверит уже сервис
+func (c *resourceClock) Location() (*time.Location, error) {
+	if c.loc != nil {
+		return c.loc, nil
+	}
+	name := ""
+	if c.zones != nil && c.resourceID != 0 {
+		var err error
+		if name, err = c.zones.ResourceTimezone(c.ctx, c.resourceID); err != nil {
+			return nil, err
+		}
+	}
+	loc, err := service.LoadZone(name)
+	if err != nil {
+		return nil, err
+	}
+	c.loc = loc
+	return loc, nil
+}
+
+// Parse — время со смещением как есть, без смещения — местное время ресурса; результат в UTC
+func (c *resourceClock) Parse(s string) (time.Time, error) {
+	if service.HasOffset(s) {
+		return service.ParseLocalTime(s, time.UTC)
+	}
+	loc, err := c.Location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return service.ParseLocalTime(s, loc)
+}
+
+// parseRequestTime разбирает поле field и сам отвечает клиенту при ошибке:
+// неверный формат и несуществующее местное время — 400, ошибка чтения пояса — 500
+func parseRequestTime(w http.ResponseWriter, c *resourceClock, field, s string) (time.Time, bool) {
+	t, err := c.Parse(s)
+	switch {
+	case err == nil:
+		return t, true
+	case errors.Is(err, service.ErrNonexistentTime):
+		writeError(w, http.StatusBadRequest, CodeTimeDoesNotExist, field+": "+err.Error(), FieldError{Field: field, Message: err.Error()})
+	case isTimeFormatError(err):
+		writeFieldErrorf(w, field, "time.invalid_format", field)
+	default:
+		internalError(w, "timezone.load_failed", err)
+	}
+	return time.Time{}, false
+}
+
+func isTimeFormatError(err error) bool {
+	var pe *time.ParseError
+	return errors.As(err, &pe)
+}
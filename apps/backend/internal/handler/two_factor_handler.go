@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"bookinghub-backend/internal/apiv1"
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/service"
+)
+
+// WithTwoFactor включает двухфакторную аутентификацию: вход с включённой 2FA (или с обязательной
+// для роли) идёт в два шага — Login отдаёт токен второго шага, VerifyTwoFactor меняет его на токен доступа
+func (h *AuthHandler) WithTwoFactor(tf *service.TwoFactorService) *AuthHandler {
+	h.twoFactor = tf
+	return h
+}
+
+// challenge отвечает 202 с токеном второго шага, если пользователю нужна 2FA.
+// false — не нужна, вход продолжается как обычно.
+func (h *AuthHandler) challenge(w http.ResponseWriter, r *http.Request, u *domain.User) bool {
+	if h.twoFactor == nil {
+		return false
+	}
+	enabled, err := h.twoFactor.Enabled(r.Context(), u.ID)
+	if err != nil {
+		internalError(w, "db.error", err)
+		return true
+	}
+
+	var res apiv1.LoginChallenge
+	switch {
+	case enabled:
+	case h.twoFactor.Required(u.Role):
+		e, err := h.twoFactor.Enroll(r.Context(), u.ID, u.Email)
+		if err != nil {
+			internalError(w, "two_factor.failed", err)
+			return true
+		}
+		enrollment := apiv1.NewTwoFactorEnrollment(*e)
+		res.EnrollmentRequired, res.Enrollment = true, &enrollment
+	default:
+		return false
+	}
+
+	if res.ChallengeToken, res.ExpiresAt, err = h.auth.CreateChallengeToken(u.ID, res.EnrollmentRequired); err != nil {
+		internalError(w, "auth.token_failed", err)
+		return true
+	}
+	writeJSON(w, http.StatusAccepted, res)
+	return true
+}
+
+type twoFactorVerifyReq struct {
+	ChallengeToken string `json:"challengeToken" validate:"trim,required"`
+	Code           string `json:"code" validate:"trim,max=16"`
+	RecoveryCode   string `json:"recoveryCode" validate:"trim,max=32"`
+}
+
+// POST /api/v1/auth/2fa/verify — второй шаг входа: код из аутентификатора или код восстановления.
+// Для обязательного подключения код подтверждает его, и в ответе — коды восстановления.
+func (h *AuthHandler) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req twoFactorVerifyReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if h.twoFactor == nil {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.invalid_challenge")
+		return
+	}
+	claims, err := h.auth.ParseChallengeToken(req.ChallengeToken)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.invalid_challenge")
+		return
+	}
+	if req.Code == "" && (req.RecoveryCode == "" || claims.Enroll) {
+		writeFieldError(w, "code", "two_factor.code_required")
+		return
+	}
+
+	u, err := h.users.GetByID(r.Context(), claims.UserID)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.invalid_challenge")
+		return
+	}
+	// подбор кода ограничен теми же лимитами и блокировкой, что и подбор пароля
+	if !h.allowLogin(w, r, u.Email) {
+		return
+	}
+
+	var recovery []string
+	if claims.Enroll {
+		recovery, err = h.twoFactor.Enable(r.Context(), u.ID, req.Code)
+	} else {
+		err = h.twoFactor.Verify(r.Context(), u.ID, req.Code, req.RecoveryCode)
+	}
+	if errors.Is(err, service.ErrInvalidTwoFactorCode) {
+		h.loginFailed(w, r, u.Email)
+		writeError(w, http.StatusUnauthorized, CodeInvalidTwoFactorCode, "two_factor.invalid_code")
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err, "two_factor.failed")
+		return
+	}
+	h.loginSucceeded(w, r, u.Email)
+
+	token, err := h.auth.CreateAccessToken(u.ID, u.Role)
+	if err != nil {
+		internalError(w, "auth.token_failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.Auth{
+		AccessToken:   token,
+		User:          apiv1.AuthUser{ID: u.ID, Email: u.Email, Name: u.Name, Role: u.Role},
+		RecoveryCodes: recovery,
+	})
+}
+
+// GET /api/v1/auth/2fa — включена ли 2FA, обязательна ли она и сколько осталось кодов восстановления
+func (h *AuthHandler) TwoFactorStatus(w http.ResponseWriter, r *http.Request) {
+	st, err := h.twoFactor.Status(r.Context(), GetUserID(r), GetRole(r))
+	if err != nil {
+		internalError(w, "db.error", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.NewTwoFactorStatus(*st))
+}
+
+// POST /api/v1/auth/2fa/enroll — начать подключение: секрет и ссылка otpauth:// для QR-кода.
+// 2FA включится после POST /auth/2fa/enable с первым кодом.
+func (h *AuthHandler) EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	u, err := h.users.GetByID(r.Context(), GetUserID(r))
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "user.not_found")
+		return
+	}
+	e, err := h.twoFactor.Enroll(r.Context(), u.ID, u.Email)
+	if err != nil {
+		writeServiceError(w, err, "two_factor.failed")
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.NewTwoFactorEnrollment(*e))
+}
+
+type twoFactorCodeReq struct {
+	Code string `json:"code" validate:"trim,required,max=16"`
+}
+
+// POST /api/v1/auth/2fa/enable — включить 2FA кодом из аутентификатора; ответ — коды восстановления
+func (h *AuthHandler) EnableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req twoFactorCodeReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	codes, err := h.twoFactor.Enable(r.Context(), GetUserID(r), req.Code)
+	if err != nil {
+		writeServiceError(w, err, "two_factor.failed")
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.RecoveryCodes{RecoveryCodes: codes})
+}
+
+// POST /api/v1/auth/2fa/recovery-codes — новые коды восстановления взамен прежних
+func (h *AuthHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	var req twoFactorCodeReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	codes, err := h.twoFactor.RegenerateRecoveryCodes(r.Context(), GetUserID(r), req.Code)
+	if err != nil {
+		writeServiceError(w, err, "two_factor.failed")
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.RecoveryCodes{RecoveryCodes: codes})
+}
+
+type twoFactorDisableReq struct {
+	Code         string `json:"code" validate:"trim,max=16"`
+	RecoveryCode string `json:"recoveryCode" validate:"trim,max=32"`
+}
+
+// POST /api/v1/auth/2fa/disable — выключить 2FA кодом из аутентификатора или кодом восстановления
+func (h *AuthHandler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req twoFactorDisableReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Code+req.RecoveryCode) == "" {
+		writeFieldError(w, "code", "two_factor.code_required")
+		return
+	}
+	if err := h.twoFactor.Disable(r.Context(), GetUserID(r), GetRole(r), req.Code, req.RecoveryCode); err != nil {
+		writeServiceError(w, err, "two_factor.failed")
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"bookinghub-backend/internal/apiv1"
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/repo"
+	"bookinghub-backend/internal/service"
+	"bookinghub-backend/internal/totp"
+)
+
+func TestAuthHandler_Login_RequiredTwoFactorEnrollment(t *testing.T) {
+	dbx, mock, cleanup := newSQLXMock(t)
+	defer cleanup()
+
+	auth := service.NewAuthService("dev", 15)
+	tf := service.NewTwoFactorService(repo.NewTwoFactorRepo(dbx), "BookingHub").WithRequiredRoles(domain.RoleAdmin)
+	h := NewAuthHandler(repo.NewUserRepo(dbx), auth).WithTwoFactor(tf)
+
+	hash, _ := auth.HashPassword("123456")
+	userRow := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at"}).
+			AddRow(uint64(1), "admin@test.local", "Admin", string(domain.RoleAdmin), hash, time.Now())
+	}
+
+	// шаг 1: пароль верный, 2FA обязательна, но не подключена — вместо токена доступа секрет
+	mock.ExpectQuery("FROM users WHERE email = \\?").WithArgs("admin@test.local").WillReturnRows(userRow())
+	mock.ExpectQuery("SELECT role, suspended_at, suspend_reason, sessions_revoked_at, locale FROM users WHERE id = \\?").
+		WithArgs(uint64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(string(domain.RoleAdmin)))
+	mock.ExpectQuery("FROM user_totp WHERE user_id = \\?").WithArgs(uint64(1)).WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+	mock.ExpectQuery("FROM user_totp WHERE user_id = \\?").WithArgs(uint64(1)).WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+	mock.ExpectExec("INSERT INTO user_totp").WithArgs(uint64(1), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	b, _ := json.Marshal(map[string]any{"email": "admin@test.local", "password": "123456"})
+	rr := httptest.NewRecorder()
+	h.Login(rr, httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(b)))
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	var challenge apiv1.LoginChallenge
+	_ = json.Unmarshal(rr.Body.Bytes(), &challenge)
+	if !challenge.EnrollmentRequired || challenge.Enrollment == nil || challenge.ChallengeToken == "" {
+		t.Fatalf("unexpected challenge: %s", rr.Body.String())
+	}
+	if _, err := auth.ParseAccessToken(challenge.ChallengeToken); err == nil {
+		t.Fatal("challenge token must not be an access token")
+	}
+
+	// шаг 2: первый код из аутентификатора подтверждает подключение и завершает вход
+	secret := challenge.Enrollment.Secret
+	code, _ := totp.Code(secret, time.Now())
+	mock.ExpectQuery("FROM users WHERE id = \\?").WithArgs(uint64(1)).WillReturnRows(userRow())
+	mock.ExpectQuery("FROM user_totp WHERE user_id = \\?").WithArgs(uint64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "secret", "enabled_at", "last_step"}).AddRow(uint64(1), secret, nil, nil))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE user_totp\\s+SET enabled_at").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM user_recovery_codes").WithArgs(uint64(1)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO user_recovery_codes").WillReturnResult(sqlmock.NewResult(1, service.RecoveryCodeCount))
+	mock.ExpectCommit()
+
+	b, _ = json.Marshal(map[string]any{"challengeToken": challenge.ChallengeToken, "code": code})
+	rr = httptest.NewRecorder()
+	h.VerifyTwoFactor(rr, httptest.NewRequest(http.MethodPost, "/api/v1/auth/2fa/verify", bytes.NewReader(b)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	var res apiv1.Auth
+	_ = json.Unmarshal(rr.Body.Bytes(), &res)
+	if len(res.RecoveryCodes) != service.RecoveryCodeCount {
+		t.Fatalf("expected recovery codes: %s", rr.Body.String())
+	}
+	if claims, err := auth.ParseAccessToken(res.AccessToken); err != nil || claims.UserID != 1 {
+		t.Fatalf("access token: %+v, %v", claims, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestAuthHandler_VerifyTwoFactor_InvalidChallenge_401(t *testing.T) {
+	dbx, _, cleanup := newSQLXMock(t)
+	defer cleanup()
+
+	auth := service.NewAuthService("dev", 15)
+	h := NewAuthHandler(repo.NewUserRepo(dbx), auth).
+		WithTwoFactor(service.NewTwoFactorService(repo.NewTwoFactorRepo(dbx), "BookingHub"))
+
+	// токен доступа вместо токена второго шага не подходит
+	access, _ := auth.CreateAccessToken(1, domain.RoleAdmin)
+	b, _ := json.Marshal(map[string]any{"challengeToken": access, "code": "123456"})
+	rr := httptest.NewRecorder()
+	h.VerifyTwoFactor(rr, httptest.NewRequest(http.MethodPost, "/api/v1/auth/2fa/verify", bytes.NewReader(b)))
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestAuthHandler_DisableTwoFactor_RequiredRole_403(t *testing.T) {
+	dbx, _, cleanup := newSQLXMock(t)
+	defer cleanup()
+
+	tf := service.NewTwoFactorService(repo.NewTwoFactorRepo(dbx), "BookingHub").WithRequiredRoles(domain.RoleAdmin)
+	h := NewAuthHandler(repo.NewUserRepo(dbx), service.NewAuthService("dev", 15)).WithTwoFactor(tf)
+
+	b, _ := json.Marshal(map[string]any{"code": "123456"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/2fa/disable", bytes.NewReader(b))
+	req = req.WithContext(withUser(req.Context(), 1, domain.RoleAdmin))
+	rr := httptest.NewRecorder()
+	h.DisableTwoFactor(rr, req)
+	if rr.Code != http.StatusForbidden || !bytes.Contains(rr.Body.Bytes(), []byte(CodeTwoFactorRequired)) {
+		t.Fatalf("expected 403 TWO_FACTOR_REQUIRED got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
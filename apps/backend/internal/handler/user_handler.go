@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 
+	"bookinghub-backend/internal/apiv1"
 	"bookinghub-backend/internal/repo"
 )
 
@@ -22,22 +23,16 @@ func (h *UserHandler) PublicByID(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id64, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil || id64 == 0 {
-		http.Error(w, "Некорректный id", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "request.invalid_id")
 		return
 	}
 
 	u, err := h.users.GetByID(r.Context(), uint64(id64))
 	if err != nil || u == nil {
-		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+		writeError(w, http.StatusNotFound, CodeNotFound, "user.not_found")
 		return
 	}
 
 	// публичные поля (можно скрыть email если хочешь)
-	writeJSON(w, http.StatusOK, map[string]any{
-		"id":        u.ID,
-		"name":      u.Name,
-		"role":      u.Role,
-		"email":     u.Email, // если не хочешь светить — убери
-		"createdAt": u.CreatedAt,
-	})
+	writeJSON(w, http.StatusOK, apiv1.NewPublicUser(*u))
 }
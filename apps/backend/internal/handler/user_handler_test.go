@@ -30,7 +30,7 @@ func TestUserHandler_PublicByID_NotFound_404(t *testing.T) {
 
 	h := NewUserHandler(repo.NewUserRepo(dbx))
 
-	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at FROM users WHERE id = \\? LIMIT 1").
+	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE id = \\? LIMIT 1").
 		WithArgs(uint64(99)).
 		WillReturnError(sqlmock.ErrCancelled) // любой err → 404 в твоём хендлере
 
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/apiv1"
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/repo"
+	"bookinghub-backend/internal/service"
+)
+
+type WaitlistHandler struct {
+	repo    *repo.WaitlistRepo
+	service *service.WaitlistService
+	zones   zoneSource
+}
+
+func NewWaitlistHandler(repo *repo.WaitlistRepo, service *service.WaitlistService, zones zoneSource) *WaitlistHandler {
+	return &WaitlistHandler{repo: repo, service: service, zones: zones}
+}
+
+type joinWaitlistReq struct {
+	ResourceID uint64 `json:"resourceId" validate:"required"`
+	StartAt    string `json:"startAt" validate:"trim,required"`
+	EndAt      string `json:"endAt" validate:"trim,required"`
+	Quantity   *int   `json:"quantity" validate:"min=1"` // по умолчанию 1
+	AutoBook   *bool  `json:"autoBook"`                  // по умолчанию true
+}
+
+// POST /api/waitlist — встать в очередь на занятое время
+func (h *WaitlistHandler) Join(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return
+	}
+
+	var req joinWaitlistReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	clock := newResourceClock(r.Context(), h.zones, req.ResourceID)
+	startAt, ok := parseRequestTime(w, clock, "startAt", req.StartAt)
+	if !ok {
+		return
+	}
+	endAt, ok := parseRequestTime(w, clock, "endAt", req.EndAt)
+	if !ok {
+		return
+	}
+
+	e := domain.WaitlistEntry{ResourceID: req.ResourceID, UserID: uid, StartAt: startAt, EndAt: endAt, Quantity: 1, AutoBook: true}
+	if req.Quantity != nil {
+		e.Quantity = *req.Quantity
+	}
+	if req.AutoBook != nil {
+		e.AutoBook = *req.AutoBook
+	}
+
+	id, err := h.service.Join(r.Context(), e)
+	if err != nil {
+		writeServiceError(w, err, "waitlist.join_failed")
+		return
+	}
+	writeJSON(w, http.StatusCreated, apiv1.ID{ID: id})
+}
+
+// GET /api/waitlist/my — мои заявки, включая завершённые
+func (h *WaitlistHandler) My(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return
+	}
+	items, err := h.repo.ListByUser(r.Context(), uid)
+	if err != nil {
+		internalError(w, "waitlist.list_failed", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiv1.NewWaitlist(items))
+}
+
+// DELETE /api/waitlist/{id} — выйти из очереди
+func (h *WaitlistHandler) Leave(w http.ResponseWriter, r *http.Request) {
+	e, ok := h.ownEntry(w, r)
+	if !ok {
+		return
+	}
+	if !e.Active() {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "waitlist.not_waiting")
+		return
+	}
+	if err := h.repo.SetStatus(r.Context(), e.ID, domain.WaitlistLeft); err != nil {
+		internalError(w, "waitlist.leave_failed", err)
+		return
+	}
+	// отказ от предложения отдаёт место следующему
+	if e.Status == domain.WaitlistOffered {
+		releaseSlots(r.Context(), h.service, e.ResourceID)
+	}
+	writeJSON(w, http.StatusOK, apiv1.OK{OK: true})
+}
+
+// POST /api/waitlist/{id}/claim — забрать предложенное место, пока не истёк срок
+func (h *WaitlistHandler) Claim(w http.ResponseWriter, r *http.Request) {
+	e, ok := h.ownEntry(w, r)
+	if !ok {
+		return
+	}
+	bookingID, err := h.service.Claim(r.Context(), *e)
+	if err != nil {
+		if errors.Is(err, service.ErrConflict) {
+			writeError(w, http.StatusConflict, CodeBookingConflict, "waitlist.claim_conflict")
+			return
+		}
+		writeServiceError(w, err, "waitlist.claim_failed")
+		return
+	}
+	writeJSON(w, http.StatusCreated, apiv1.WaitlistClaimed{BookingID: bookingID})
+}
+
+func (h *WaitlistHandler) ownEntry(w http.ResponseWriter, r *http.Request) (*domain.WaitlistEntry, bool) {
+	uid := GetUserID(r)
+	if uid == 0 {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "auth.required")
+		return nil, false
+	}
+	id64, err := strconv.ParseUint(strings.TrimSpace(chi.URLParam(r, "id")), 10, 64)
+	if err != nil || id64 == 0 {
+		writeError(w, http.StatusBadRequest, CodeValidationFailed, "request.invalid_id")
+		return nil, false
+	}
+	e, err := h.repo.GetByID(r.Context(), id64)
+	if err != nil {
+		internalError(w, "db.error", err)
+		return nil, false
+	}
+	// чужие заявки не показываем вовсе
+	if e == nil || e.UserID != uid {
+		writeError(w, http.StatusNotFound, CodeNotFound, "waitlist.entry_not_found")
+		return nil, false
+	}
+	return e, true
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/repo"
+	"bookinghub-backend/internal/service"
+)
+
+type fakeReleaser struct{ resourceIDs []uint64 }
+
+func (f *fakeReleaser) Release(ctx context.Context, resourceID uint64) error {
+	f.resourceIDs = append(f.resourceIDs, resourceID)
+	return nil
+}
+
+var waitlistCols = []string{
+	"id", "resource_id", "user_id", "start_at", "end_at", "quantity", "auto_book", "status", "claim_expires_at", "booking_id", "created_at", "updated_at",
+}
+
+func waitlistRouter(db *repo.WaitlistRepo, bookings *repo.BookingRepo) http.Handler {
+	h := NewWaitlistHandler(db, service.NewWaitlistService(db, bookings), bookings)
+	r := chi.NewRouter()
+	r.Post("/api/waitlist", h.Join)
+	r.Delete("/api/waitlist/{id}", h.Leave)
+	return r
+}
+
+func TestWaitlistHandler_Join_201(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	start := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Hour)
+	end := start.Add(time.Hour)
+
+	expectAvailability(mock, 5, 1, start, end).
+		AddRow(uint64(1), uint64(5), uint64(3), start, end, 1, "APPROVED", nil, start, nil)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM waitlist_entries").
+		WithArgs(uint64(7), uint64(5), timeEq{start}, timeEq{end}).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+	mock.ExpectExec("INSERT INTO waitlist_entries \\(resource_id, user_id, start_at, end_at, quantity, auto_book, status\\)").
+		WithArgs(uint64(5), uint64(7), timeEq{start}, timeEq{end}, 1, false).
+		WillReturnResult(sqlmock.NewResult(12, 1))
+
+	body := `{"resourceId":5,"startAt":"` + start.Format(time.RFC3339) + `","endAt":"` + end.Format(time.RFC3339) + `","autoBook":false}`
+	req := withUID(httptest.NewRequest(http.MethodPost, "/api/waitlist", bytes.NewBufferString(body)), 7)
+	rr := httptest.NewRecorder()
+
+	waitlistRouter(repo.NewWaitlistRepo(db), repo.NewBookingRepo(db)).ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201 got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestWaitlistHandler_Join_FreeSlot_409(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	start := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Hour)
+	end := start.Add(time.Hour)
+	expectAvailability(mock, 5, 1, start, end)
+
+	body := `{"resourceId":5,"startAt":"` + start.Format(time.RFC3339) + `","endAt":"` + end.Format(time.RFC3339) + `"}`
+	req := withUID(httptest.NewRequest(http.MethodPost, "/api/waitlist", bytes.NewBufferString(body)), 7)
+	rr := httptest.NewRecorder()
+
+	waitlistRouter(repo.NewWaitlistRepo(db), repo.NewBookingRepo(db)).ServeHTTP(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409 got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestWaitlistHandler_Leave_ForeignEntry_404(t *testing.T) {
+	db, mock, cleanup := newMockHandlerDB(t)
+	defer cleanup()
+
+	start := time.Now().Add(48 * time.Hour)
+	mock.ExpectQuery("FROM waitlist_entries WHERE id = \\?").
+		WithArgs(uint64(12)).
+		WillReturnRows(sqlmock.NewRows(waitlistCols).
+			AddRow(uint64(12), uint64(5), uint64(8), start, start.Add(time.Hour), 1, true, "WAITING", nil, nil, time.Now(), nil))
+
+	req := withUID(httptest.NewRequest(http.MethodDelete, "/api/waitlist/12", nil), 7)
+	rr := httptest.NewRecorder()
+
+	waitlistRouter(repo.NewWaitlistRepo(db), repo.NewBookingRepo(db)).ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,101 @@
+// Package i18n — каталог сообщений API на поддерживаемых языках и выбор языка запроса.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Поддерживаемые языки. Русский — исходный: на нём написаны тексты в коде, и он же по умолчанию.
+const (
+	RU      = "ru"
+	EN      = "en"
+	Default = RU
+)
+
+// Supported — языки, для которых есть каталог
+var Supported = []string{RU, EN}
+
+// Normalize приводит тег языка к поддерживаемому: "en-US" → "en". ok=false — язык не поддерживается.
+func Normalize(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if _, ok := catalog[tag]; ok {
+		return tag, true
+	}
+	return "", false
+}
+
+// Negotiate выбирает язык по заголовку Accept-Language с учётом весов q.
+// Если ни один язык не поддерживается, возвращает Default.
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+	var cands []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = f
+		}
+		if q <= 0 {
+			continue
+		}
+		if lang, ok := Normalize(tag); ok {
+			cands = append(cands, candidate{lang, q})
+		}
+	}
+	if len(cands) == 0 {
+		return Default
+	}
+	// при равных весах выигрывает язык, названный раньше
+	sort.SliceStable(cands, func(a, b int) bool { return cands[a].q > cands[b].q })
+	return cands[0].lang
+}
+
+// Has — есть ли в каталоге сообщение key на языке lang
+func Has(lang, key string) bool {
+	_, ok := catalog[lang][key]
+	return ok
+}
+
+// T — сообщение key на языке lang; если перевода нет — на языке по умолчанию, если нет и его — сам key.
+// args подставляются в сообщение через fmt.Sprintf.
+func T(lang, key string, args ...any) string {
+	msg, ok := catalog[lang][key]
+	if !ok {
+		if msg, ok = catalog[Default][key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+type ctxKey struct{}
+
+// WithLang сохраняет язык запроса в контексте
+func WithLang(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, lang)
+}
+
+// FromContext — язык запроса; Default, если он не выбран
+func FromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(ctxKey{}).(string); ok && lang != "" {
+		return lang
+	}
+	return Default
+}
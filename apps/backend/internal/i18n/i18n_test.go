@@ -0,0 +1,63 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := map[string]string{
+		"":                             RU,
+		"en":                           EN,
+		"en-US,en;q=0.9":               EN,
+		"de-DE,de;q=0.9,en;q=0.8":      EN,
+		"fr, ru;q=0.5, en;q=0.7":       EN,
+		"ru-RU,ru;q=0.9,en-US;q=0.8":   RU,
+		"en;q=0, ru;q=0.1":             RU,
+		"de":                           RU,
+		"EN_gb":                        EN,
+		"en;q=abc":                     RU,
+		"en-US;q=0.5, ru-RU;q=0.5, de": EN,
+	}
+	for header, want := range cases {
+		if got := Negotiate(header); got != want {
+			t.Errorf("Negotiate(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestCatalog_AllLanguagesHaveSameKeys(t *testing.T) {
+	for _, lang := range Supported {
+		for key := range catalog[Default] {
+			if !Has(lang, key) {
+				t.Errorf("%s: no translation for %q", lang, key)
+			}
+		}
+		for key := range catalog[lang] {
+			if !Has(Default, key) {
+				t.Errorf("%s: key %q is missing in %s", lang, key, Default)
+			}
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T(EN, "BOOKING_CONFLICT"); got != "The selected time is already booked" {
+		t.Fatalf("unexpected EN text %q", got)
+	}
+	if got := T("de", "BOOKING_CONFLICT"); got != "Выбранное время уже занято" {
+		t.Fatalf("unknown language must fall back to default, got %q", got)
+	}
+	if got := T(EN, "no.such.key"); got != "no.such.key" {
+		t.Fatalf("missing key must be returned as is, got %q", got)
+	}
+	if got := T(EN, "notify.waitlist_booked", 7, "2030-01-01 10:00 UTC"); got != "A spot opened up: booking #7 for 2030-01-01 10:00 UTC was created automatically" {
+		t.Fatalf("unexpected formatted text %q", got)
+	}
+	if got := FromContext(WithLang(context.Background(), EN)); got != EN {
+		t.Fatalf("FromContext = %q", got)
+	}
+	if got := FromContext(context.Background()); got != Default {
+		t.Fatalf("FromContext without language = %q", got)
+	}
+}
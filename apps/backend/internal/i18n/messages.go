@@ -0,0 +1,579 @@
+package i18n
+
+// catalog — сообщения по языкам. Ключи — коды ошибок API (общий текст для кода)
+// и ключи отдельных сообщений вида "раздел.сообщение". У каждого ключа должен быть перевод на все языки.
+var catalog = map[string]map[string]string{
+	RU: {
+		// коды ошибок API
+		"VALIDATION_FAILED":         "Некорректные данные запроса",
+		"INVALID_JSON":              "Некорректный JSON",
+		"UNAUTHORIZED":              "Требуется авторизация",
+		"INVALID_CREDENTIALS":       "Неверный email или пароль",
+		"FORBIDDEN":                 "Недостаточно прав",
+		"ACCOUNT_SUSPENDED":         "Аккаунт заблокирован",
+		"NOT_FOUND":                 "Не найдено",
+		"RESOURCE_NOT_FOUND":        "Ресурс не найден",
+		"BOOKING_NOT_FOUND":         "Бронирование не найдено",
+		"METHOD_NOT_ALLOWED":        "Метод не поддерживается",
+		"CONFLICT":                  "Конфликт с текущим состоянием данных",
+		"BOOKING_CONFLICT":          "Выбранное время уже занято",
+		"EMAIL_TAKEN":               "Email уже занят",
+		"HOLD_NOT_FOUND":            "Удержание не найдено или истекло",
+		"HOLD_LIMIT_REACHED":        "Слишком много активных удержаний",
+		"SLOT_AVAILABLE":            "Это время свободно — забронируйте его напрямую",
+		"ALREADY_IN_QUEUE":          "Вы уже стоите в очереди на это время",
+		"CLAIM_NOT_OFFERED":         "Место по этой заявке сейчас не предлагается",
+		"INVITE_EXPIRED":            "Срок действия приглашения истёк",
+		"CANCEL_NOT_ALLOWED":        "Эту бронь нельзя отменить",
+		"INVALID_BOOKING_STATUS":    "Действие недоступно в текущем статусе брони",
+		"PAYLOAD_TOO_LARGE":         "Слишком большой запрос",
+		"UNSUPPORTED_MEDIA_TYPE":    "Неподдерживаемый тип данных",
+		"IMAGE_LIMIT_REACHED":       "Достигнут лимит фото для объявления",
+		"INTERNAL_ERROR":            "Внутренняя ошибка сервера",
+		"LOCAL_TIME_DOES_NOT_EXIST": "Такого времени нет в часовом поясе ресурса: в этот момент часы переводятся вперёд",
+		"INVALID_TIMEZONE":          "Некорректный часовой пояс: нужен идентификатор IANA, например Europe/Moscow",
+		"INVALID_TIME_INTERVAL":     "Некорректный интервал времени",
+		"BOOKING_IN_PAST":           "Нельзя бронировать время в прошлом",
+		"TOO_MANY_REQUESTS":         "Слишком много запросов, повторите позже",
+		"LOGIN_LOCKED":              "Слишком много неудачных попыток входа, повторите позже",
+		"INVALID_TWO_FACTOR_CODE":   "Неверный или уже использованный код",
+		"TWO_FACTOR_NOT_ENABLED":    "Двухфакторная аутентификация не подключена",
+		"TWO_FACTOR_ENABLED":        "Двухфакторная аутентификация уже включена",
+		"TWO_FACTOR_REQUIRED":       "Для вашей роли двухфакторная аутентификация обязательна",
+		"SSO_FAILED":                "Не удалось войти через корпоративный аккаунт",
+		"SSO_UNAVAILABLE":           "Провайдер входа недоступен, повторите позже",
+		"SSO_EMAIL_NOT_VERIFIED":    "Провайдер входа не подтвердил email",
+
+		// общие сообщения
+		"field.invalid":        "Некорректное значение",
+		"request.invalid_json": "Некорректный JSON",
+		"request.invalid_id":   "Некорректный id",
+		"db.error":             "Ошибка базы данных",
+		"access.denied":        "Недостаточно прав",
+		"resource.invalid_id":  "Некорректный id ресурса",
+		"resource.not_found":   "Ресурс не найден",
+		"resource.not_owner":   "Недостаточно прав: вы не владелец объявления",
+		"category.not_found":   "Категория не найдена",
+		"booking.not_found":    "Бронирование не найдено",
+		"user.not_found":       "Пользователь не найден",
+
+		// проверка полей запроса
+		"request.too_large":        "Тело запроса больше %d КБ",
+		"validation.required":      "Обязательное поле",
+		"validation.email":         "Некорректный email",
+		"validation.oneof":         "Допустимые значения: %s",
+		"validation.min":           "Не меньше %s",
+		"validation.max":           "Не больше %s",
+		"validation.min_len":       "Не короче %s символов",
+		"validation.max_len":       "Не длиннее %s символов",
+		"validation.min_items":     "Нужно не меньше %s элементов",
+		"validation.max_items":     "Не больше %s элементов",
+		"validation.unknown_field": "Неизвестное поле",
+		"validation.type":          "Неверный тип значения",
+		"validation.one_of_fields": "Нужно ровно одно из полей resourceId и bundleId",
+		"validation.duplicate":     "Значение повторяется",
+
+		// авторизация и профиль
+		"auth.required":               "Требуется авторизация",
+		"auth.invalid_token":          "Неверный токен",
+		"auth.session_revoked":        "Сессия завершена, войдите заново",
+		"auth.suspended":              "Аккаунт заблокирован",
+		"auth.invalid_challenge":      "Подтверждение входа истекло или недействительно, войдите заново",
+		"auth.invalid_email":          "Введите корректный email",
+		"auth.invalid_credentials":    "Неверный email или пароль",
+		"auth.wrong_password":         "Текущий пароль неверный",
+		"auth.user_exists":            "Пользователь с таким email уже существует",
+		"auth.email_taken":            "Email уже занят",
+		"auth.invalid_locale":         "locale должен быть ru или en",
+		"auth.password_failed":        "Не удалось обработать пароль",
+		"auth.token_failed":           "Не удалось создать токен",
+		"auth.create_user_failed":     "Не удалось создать пользователя",
+		"auth.update_profile_failed":  "Не удалось обновить профиль",
+		"auth.update_password_failed": "Не удалось обновить пароль",
+		"auth.delete_account_failed":  "Не удалось удалить аккаунт",
+
+		// двухфакторная аутентификация
+		"two_factor.invalid_code":       "Неверный или уже использованный код",
+		"two_factor.code_required":      "Введите код из приложения или код восстановления",
+		"two_factor.not_enabled":        "Двухфакторная аутентификация не подключена",
+		"two_factor.enroll_not_started": "Сначала начните подключение двухфакторной аутентификации",
+		"two_factor.already_enabled":    "Двухфакторная аутентификация уже включена",
+		"two_factor.required":           "Для вашей роли двухфакторная аутентификация обязательна",
+		"two_factor.failed":             "Не удалось выполнить операцию двухфакторной аутентификации",
+
+		// вход через корпоративного провайдера (OIDC)
+		"sso.not_configured":     "Вход через корпоративный аккаунт не настроен",
+		"sso.invalid_state":      "Вход через корпоративный аккаунт истёк или недействителен, начните заново",
+		"sso.failed":             "Не удалось войти через корпоративный аккаунт",
+		"sso.unavailable":        "Провайдер входа недоступен, повторите позже",
+		"sso.email_not_verified": "Провайдер входа не подтвердил email",
+
+		// ошибки сервисов броней, удержаний и очереди
+		"booking.conflict":           "Выбранное время уже занято",
+		"booking.too_short":          "Минимальная длительность бронирования: 30 минут",
+		"booking.in_past":            "Нельзя бронировать время в прошлом",
+		"booking.invalid_time":       "Некорректный интервал времени",
+		"booking.invalid_quantity":   "Некорректное количество",
+		"booking.invalid_group":      "Некорректная групповая бронь",
+		"booking.cancel_not_allowed": "Эту бронь нельзя отменить",
+		"booking.cancel_too_late":    "Отмена возможна не позднее чем за 2 часа до начала",
+		"booking.save_failed":        "Не удалось сохранить бронь",
+		"booking.status_changed":     "Статус брони уже изменился, обновите страницу",
+		"hold.not_found":             "Удержание не найдено или истекло",
+		"hold.limit":                 "Слишком много активных удержаний",
+		"hold.invalid_ttl":           "Некорректный срок удержания",
+		"waitlist.slot_available":    "Это время свободно — забронируйте его напрямую",
+		"waitlist.already_in_queue":  "Вы уже стоите в очереди на это время",
+		"waitlist.claim_not_offered": "Место по этой заявке сейчас не предлагается",
+		"time.nonexistent":           "Такого времени нет в часовом поясе ресурса: в этот момент часы переводятся вперёд",
+		"timezone.invalid":           "Некорректный часовой пояс: нужен идентификатор IANA, например Europe/Moscow",
+		"opening_hours.invalid":      "Некорректное расписание работы",
+		"address.invalid":            "Некорректный адрес",
+		"attribute.invalid":          "Некорректное описание атрибута",
+		"attribute.invalid_values":   "Некорректные значения атрибутов",
+		"attribute.invalid_filter":   "Некорректный фильтр по атрибуту",
+		"image.too_large":            "Файл слишком большой",
+		"image.type":                 "Поддерживаются только JPEG, PNG и GIF",
+		"image.unreadable":           "Не удалось прочитать изображение",
+		"image.limit":                "Достигнут лимит фото для объявления",
+
+		// параметры запроса
+		"route.not_found":                  "Маршрут не найден",
+		"query.id_required":                "Нужен параметр id",
+		"query.invalid_page":               "Некорректный page",
+		"query.invalid_page_size":          "pageSize должен быть от 1 до 100",
+		"query.invalid_user_id":            "Некорректный userId",
+		"query.invalid_category_id":        "Некорректный categoryId",
+		"query.invalid_parent_id":          "Некорректный parentId",
+		"query.invalid_from":               "Некорректный from",
+		"query.invalid_to":                 "Некорректный to",
+		"query.dates_required":             "Нужны параметры from и to в формате YYYY-MM-DD",
+		"query.from_after_to":              "from должен быть не позже to",
+		"query.start_after_end":            "startAt должно быть раньше endAt",
+		"query.period_too_long":            "Слишком длинный период",
+		"query.period_too_long_for_bucket": "Слишком длинный период для выбранного bucket",
+		"query.invalid_bucket":             "bucket должен быть hour или day",
+		"query.invalid_radius":             "radiusKm должен быть от 0 до 500",
+		"query.radius_without_near":        "radiusKm задаётся вместе с near",
+		"query.sort_requires_near":         "sort=distance требует near",
+		"query.invalid_sort":               "sort должен быть distance или newest",
+		"query.invalid_format":             "format должен быть json или csv",
+		"query.invalid_section":            "Некорректный section для CSV",
+		"time.invalid_format":              "Некорректное %s. Формат: YYYY-MM-DDTHH:MM:SS",
+		"timezone.load_failed":             "Не удалось загрузить часовой пояс ресурса",
+
+		// администрирование пользователей и прав
+		"admin.list_users_failed":      "Не удалось получить пользователей",
+		"admin.change_role_failed":     "Не удалось сменить роль",
+		"admin.suspend_failed":         "Не удалось заблокировать пользователя",
+		"admin.unsuspend_failed":       "Не удалось разблокировать пользователя",
+		"admin.verify_failed":          "Не удалось отметить пользователя",
+		"admin.unverify_failed":        "Не удалось снять отметку",
+		"admin.revoke_sessions_failed": "Не удалось завершить сессии",
+		"admin.password_failed":        "Не удалось сгенерировать пароль",
+		"admin.self_action":            "Нельзя применять это действие к своему аккаунту",
+		"permission.invalid_role":      "Некорректная роль",
+		"permission.unknown":           "Неизвестное право",
+		"permission.admin_manage":      "Нельзя отнять у ADMIN право permission:manage",
+		"permission.save_failed":       "Не удалось сохранить права",
+		"permission.apply_failed":      "Права сохранены, но не применены",
+
+		// брони, наборы и очередь
+		"booking.list_failed":          "Не удалось получить бронирования",
+		"booking.history_failed":       "Не удалось получить историю",
+		"booking.not_pending":          "Можно менять статус только у брони со статусом PENDING",
+		"booking.update_status_failed": "Не удалось обновить статус",
+		"booking.cancel_failed":        "Не удалось отменить бронь",
+		"booking.group_part_cancel":    "Часть групповой брони отменяется только вместе с группой",
+		"booking.group_not_found":      "Групповая бронь не найдена",
+		"booking.create_group_failed":  "Не удалось создать групповую бронь",
+		"booking.update_group_failed":  "Не удалось обновить групповую бронь",
+		"bundle.not_found":             "Набор не найден",
+		"bundle.list_failed":           "Не удалось получить наборы",
+		"bundle.create_failed":         "Не удалось создать набор",
+		"bundle.delete_failed":         "Не удалось удалить набор",
+		"bundle.resource_not_found":    "Ресурс #%d не найден",
+		"bundle.resource_forbidden":    "Недостаточно прав на ресурс #%d",
+		"bundle.mixed_owners":          "Все ресурсы набора должны принадлежать одному владельцу",
+		"bundle.invalid_quantity":      "Ресурс #%d: quantity должно быть от 1 до %d",
+		"waitlist.join_failed":         "Не удалось встать в очередь",
+		"waitlist.list_failed":         "Не удалось получить очередь",
+		"waitlist.entry_not_found":     "Заявка не найдена",
+		"waitlist.not_waiting":         "Заявка уже не в очереди",
+		"waitlist.leave_failed":        "Не удалось выйти из очереди",
+		"waitlist.claim_conflict":      "Выбранное время уже занято. Заявка снова в очереди",
+		"waitlist.claim_failed":        "Не удалось забрать место",
+
+		// ресурсы, расписание и фото
+		"resource.list_failed":      "Не удалось получить ресурсы",
+		"resource.load_failed":      "Не удалось получить ресурс",
+		"resource.create_failed":    "Не удалось создать ресурс",
+		"resource.update_failed":    "Не удалось изменить ресурс",
+		"opening_hours.load_failed": "Не удалось получить расписание",
+		"opening_hours.save_failed": "Не удалось сохранить расписание",
+		"image.list_failed":         "Не удалось получить фото",
+		"image.multipart_required":  "Ожидается multipart/form-data с полем file",
+		"image.too_many_files":      "Не больше 10 файлов за раз",
+		"image.read_failed":         "Не удалось прочитать файл",
+		"image.save_failed":         "Не удалось сохранить фото",
+		"image.invalid_order":       "imageIds должен содержать все фото объявления ровно по одному разу",
+		"image.reorder_failed":      "Не удалось изменить порядок",
+		"image.cover_failed":        "Не удалось выбрать обложку",
+		"image.delete_failed":       "Не удалось удалить фото",
+		"image.invalid_id":          "Некорректный id фото",
+		"image.not_found":           "Фото не найдено",
+
+		// категории и атрибуты
+		"category.list_failed":          "Не удалось получить категории",
+		"category.create_failed":        "Не удалось создать категорию",
+		"category.update_failed":        "Не удалось изменить категорию",
+		"category.delete_failed":        "Не удалось удалить категорию",
+		"category.merge_failed":         "Не удалось слить категории",
+		"category.archived":             "Категория в архиве — выберите другую",
+		"category.parent_not_found":     "Родительская категория не найдена",
+		"category.parent_archived":      "Родительская категория в архиве",
+		"category.target_not_found":     "Целевая категория не найдена",
+		"category.target_archived":      "Целевая категория в архиве",
+		"category.move_into_self":       "Нельзя перенести категорию внутрь неё самой или её подкатегории",
+		"category.merge_into_self":      "Нельзя слить категорию с ней самой или с её подкатегорией",
+		"category.not_empty":            "Категория не пуста: перенесите объявления (merge) или архивируйте категорию",
+		"attribute.list_failed":         "Не удалось получить атрибуты",
+		"attribute.check_failed":        "Не удалось проверить атрибут",
+		"attribute.check_values_failed": "Не удалось проверить атрибуты",
+		"attribute.create_failed":       "Не удалось создать атрибут",
+		"attribute.update_failed":       "Не удалось обновить атрибут",
+		"attribute.delete_failed":       "Не удалось удалить атрибут",
+		"attribute.code_taken":          "Атрибут с кодом %s уже есть в этой ветке категорий",
+		"attribute.immutable":           "code и type атрибута менять нельзя — создайте новый атрибут",
+		"attribute.invalid_id":          "Некорректный id атрибута",
+		"attribute.not_found":           "Атрибут не найден",
+		"report.build_failed":           "Не удалось построить отчёт",
+		"report.csv_failed":             "Не удалось сформировать CSV",
+
+		// организации и приглашения
+		"organization.not_found":            "Организация не найдена",
+		"organization.create_failed":        "Не удалось создать организацию",
+		"organization.list_failed":          "Не удалось получить организации",
+		"organization.not_member":           "Вы не состоите в организации",
+		"organization.access_denied":        "Недостаточно прав в организации",
+		"organization.members_failed":       "Не удалось получить участников",
+		"organization.member_not_found":     "Участник не найден",
+		"organization.owner_only_roles":     "Менять роли может только владелец организации",
+		"organization.owner_only_remove":    "Исключать участников может только владелец организации",
+		"organization.update_role_failed":   "Не удалось обновить роль",
+		"organization.remove_member_failed": "Не удалось удалить участника",
+		"organization.last_owner":           "В организации должен остаться хотя бы один владелец",
+		"invite.forbidden":                  "Приглашать может только владелец или менеджер организации",
+		"invite.owner_forbidden":            "Пригласить владельца может только владелец организации",
+		"invite.create_failed":              "Не удалось создать приглашение",
+		"invite.list_failed":                "Не удалось получить приглашения",
+		"invite.accept_failed":              "Не удалось принять приглашение",
+		"invite.decline_failed":             "Не удалось отклонить приглашение",
+		"invite.token_required":             "Нужен token приглашения",
+		"invite.not_found":                  "Приглашение не найдено",
+		"invite.wrong_email":                "Приглашение выписано на другой email",
+		"invite.used":                       "Приглашение уже использовано",
+
+		// заголовки CSV-отчёта
+		"report.bookings":          "Брони",
+		"report.pending":           "Ожидают",
+		"report.approved":          "Подтверждены",
+		"report.rejected":          "Отклонены",
+		"report.canceled":          "Отменены",
+		"report.approval_rate":     "Доля подтверждённых",
+		"report.avg_lead_time":     "Среднее время до начала, ч",
+		"report.revenue":           "Выручка",
+		"report.period":            "Период",
+		"report.resource_id":       "ID ресурса",
+		"report.title":             "Название",
+		"report.booked_hours":      "Забронировано часов",
+		"report.occupancy_percent": "Загрузка, %",
+		"report.category_id":       "ID категории",
+		"report.name":              "Название",
+		"report.owner_user_id":     "ID владельца",
+		"report.owner_name":        "Владелец",
+
+		// уведомления
+		"notify.waitlist_booked":  "Место освободилось: бронь #%d на %s создана автоматически",
+		"notify.waitlist_offered": "Место освободилось: подтвердите бронь на %s до %s",
+	},
+	EN: {
+		"VALIDATION_FAILED":         "The request contains invalid data",
+		"INVALID_JSON":              "Malformed JSON",
+		"UNAUTHORIZED":              "Authentication required",
+		"INVALID_CREDENTIALS":       "Invalid email or password",
+		"FORBIDDEN":                 "Insufficient permissions",
+		"ACCOUNT_SUSPENDED":         "Account suspended",
+		"NOT_FOUND":                 "Not found",
+		"RESOURCE_NOT_FOUND":        "Resource not found",
+		"BOOKING_NOT_FOUND":         "Booking not found",
+		"METHOD_NOT_ALLOWED":        "Method not allowed",
+		"CONFLICT":                  "The request conflicts with the current state",
+		"BOOKING_CONFLICT":          "The selected time is already booked",
+		"EMAIL_TAKEN":               "Email is already taken",
+		"HOLD_NOT_FOUND":            "Hold not found or expired",
+		"HOLD_LIMIT_REACHED":        "Too many active holds",
+		"SLOT_AVAILABLE":            "This time is free — book it directly",
+		"ALREADY_IN_QUEUE":          "You are already on the waitlist for this time",
+		"CLAIM_NOT_OFFERED":         "No spot is currently offered for this waitlist entry",
+		"INVITE_EXPIRED":            "The invitation has expired",
+		"CANCEL_NOT_ALLOWED":        "This booking cannot be canceled",
+		"INVALID_BOOKING_STATUS":    "This action is not available in the current booking status",
+		"PAYLOAD_TOO_LARGE":         "Request is too large",
+		"UNSUPPORTED_MEDIA_TYPE":    "Unsupported media type",
+		"IMAGE_LIMIT_REACHED":       "The listing has reached its photo limit",
+		"INTERNAL_ERROR":            "Internal server error",
+		"LOCAL_TIME_DOES_NOT_EXIST": "This local time does not exist in the resource's time zone: clocks move forward at that moment",
+		"INVALID_TIMEZONE":          "Invalid time zone: use an IANA name such as Europe/Moscow",
+		"INVALID_TIME_INTERVAL":     "Invalid time interval",
+		"BOOKING_IN_PAST":           "Cannot book time in the past",
+		"TOO_MANY_REQUESTS":         "Too many requests, try again later",
+		"LOGIN_LOCKED":              "Too many failed sign-in attempts, try again later",
+		"INVALID_TWO_FACTOR_CODE":   "Invalid or already used code",
+		"TWO_FACTOR_NOT_ENABLED":    "Two-factor authentication is not enabled",
+		"TWO_FACTOR_ENABLED":        "Two-factor authentication is already enabled",
+		"TWO_FACTOR_REQUIRED":       "Two-factor authentication is required for your role",
+		"SSO_FAILED":                "Could not sign in with the corporate account",
+		"SSO_UNAVAILABLE":           "The identity provider is unavailable, try again later",
+		"SSO_EMAIL_NOT_VERIFIED":    "The identity provider has not verified the email",
+
+		"field.invalid":        "Invalid value",
+		"request.invalid_json": "Malformed JSON",
+		"request.invalid_id":   "Invalid id",
+		"db.error":             "Database error",
+		"access.denied":        "Insufficient permissions",
+		"resource.invalid_id":  "Invalid resource id",
+		"resource.not_found":   "Resource not found",
+		"resource.not_owner":   "Insufficient permissions: you do not own this listing",
+		"category.not_found":   "Category not found",
+		"booking.not_found":    "Booking not found",
+		"user.not_found":       "User not found",
+
+		"request.too_large":        "Request body exceeds %d KB",
+		"validation.required":      "This field is required",
+		"validation.email":         "Invalid email address",
+		"validation.oneof":         "Allowed values: %s",
+		"validation.min":           "Must be at least %s",
+		"validation.max":           "Must be at most %s",
+		"validation.min_len":       "Must be at least %s characters",
+		"validation.max_len":       "Must be at most %s characters",
+		"validation.min_items":     "At least %s items required",
+		"validation.max_items":     "At most %s items allowed",
+		"validation.unknown_field": "Unknown field",
+		"validation.type":          "Wrong value type",
+		"validation.one_of_fields": "Exactly one of resourceId and bundleId is required",
+		"validation.duplicate":     "Duplicate value",
+
+		"auth.required":               "Authentication required",
+		"auth.invalid_token":          "Invalid token",
+		"auth.session_revoked":        "Session has ended, please sign in again",
+		"auth.suspended":              "Account suspended",
+		"auth.invalid_challenge":      "Sign-in confirmation has expired or is invalid, please sign in again",
+		"auth.invalid_email":          "Enter a valid email",
+		"auth.invalid_credentials":    "Invalid email or password",
+		"auth.wrong_password":         "Current password is incorrect",
+		"auth.user_exists":            "A user with this email already exists",
+		"auth.email_taken":            "Email is already taken",
+		"auth.invalid_locale":         "locale must be ru or en",
+		"auth.password_failed":        "Could not process the password",
+		"auth.token_failed":           "Could not create a token",
+		"auth.create_user_failed":     "Could not create the user",
+		"auth.update_profile_failed":  "Could not update the profile",
+		"auth.update_password_failed": "Could not update the password",
+		"auth.delete_account_failed":  "Could not delete the account",
+
+		"two_factor.invalid_code":       "Invalid or already used code",
+		"two_factor.code_required":      "Enter a code from the app or a recovery code",
+		"two_factor.not_enabled":        "Two-factor authentication is not enabled",
+		"two_factor.enroll_not_started": "Start two-factor authentication setup first",
+		"two_factor.already_enabled":    "Two-factor authentication is already enabled",
+		"two_factor.required":           "Two-factor authentication is required for your role",
+		"two_factor.failed":             "Could not complete the two-factor authentication request",
+
+		"sso.not_configured":     "Corporate sign-in is not configured",
+		"sso.invalid_state":      "Corporate sign-in has expired or is invalid, please start again",
+		"sso.failed":             "Could not sign in with the corporate account",
+		"sso.unavailable":        "The identity provider is unavailable, try again later",
+		"sso.email_not_verified": "The identity provider has not verified the email",
+
+		"booking.conflict":           "The selected time is already booked",
+		"booking.too_short":          "Minimum booking duration is 30 minutes",
+		"booking.in_past":            "Cannot book time in the past",
+		"booking.invalid_time":       "Invalid time interval",
+		"booking.invalid_quantity":   "Invalid quantity",
+		"booking.invalid_group":      "Invalid group booking",
+		"booking.cancel_not_allowed": "This booking cannot be canceled",
+		"booking.cancel_too_late":    "Bookings can be canceled no later than 2 hours before the start",
+		"booking.save_failed":        "Could not save the booking",
+		"booking.status_changed":     "The booking status has already changed, reload the page",
+		"hold.not_found":             "Hold not found or expired",
+		"hold.limit":                 "Too many active holds",
+		"hold.invalid_ttl":           "Invalid hold duration",
+		"waitlist.slot_available":    "This time is free — book it directly",
+		"waitlist.already_in_queue":  "You are already on the waitlist for this time",
+		"waitlist.claim_not_offered": "No spot is currently offered for this waitlist entry",
+		"time.nonexistent":           "This local time does not exist in the resource's time zone: clocks move forward at that moment",
+		"timezone.invalid":           "Invalid time zone: use an IANA name such as Europe/Moscow",
+		"opening_hours.invalid":      "Invalid opening hours",
+		"address.invalid":            "Invalid address",
+		"attribute.invalid":          "Invalid attribute definition",
+		"attribute.invalid_values":   "Invalid attribute values",
+		"attribute.invalid_filter":   "Invalid attribute filter",
+		"image.too_large":            "File is too large",
+		"image.type":                 "Only JPEG, PNG and GIF are supported",
+		"image.unreadable":           "Could not read the image",
+		"image.limit":                "The listing has reached its photo limit",
+
+		"route.not_found":                  "Route not found",
+		"query.id_required":                "The id parameter is required",
+		"query.invalid_page":               "Invalid page",
+		"query.invalid_page_size":          "pageSize must be between 1 and 100",
+		"query.invalid_user_id":            "Invalid userId",
+		"query.invalid_category_id":        "Invalid categoryId",
+		"query.invalid_parent_id":          "Invalid parentId",
+		"query.invalid_from":               "Invalid from",
+		"query.invalid_to":                 "Invalid to",
+		"query.dates_required":             "from and to are required in YYYY-MM-DD format",
+		"query.from_after_to":              "from must not be later than to",
+		"query.start_after_end":            "startAt must be earlier than endAt",
+		"query.period_too_long":            "The period is too long",
+		"query.period_too_long_for_bucket": "The period is too long for the selected bucket",
+		"query.invalid_bucket":             "bucket must be hour or day",
+		"query.invalid_radius":             "radiusKm must be between 0 and 500",
+		"query.radius_without_near":        "radiusKm requires near",
+		"query.sort_requires_near":         "sort=distance requires near",
+		"query.invalid_sort":               "sort must be distance or newest",
+		"query.invalid_format":             "format must be json or csv",
+		"query.invalid_section":            "Invalid section for CSV",
+		"time.invalid_format":              "Invalid %s. Format: YYYY-MM-DDTHH:MM:SS",
+		"timezone.load_failed":             "Could not load the resource time zone",
+
+		"admin.list_users_failed":      "Could not load users",
+		"admin.change_role_failed":     "Could not change the role",
+		"admin.suspend_failed":         "Could not suspend the user",
+		"admin.unsuspend_failed":       "Could not unsuspend the user",
+		"admin.verify_failed":          "Could not mark the user as verified",
+		"admin.unverify_failed":        "Could not remove the verified mark",
+		"admin.revoke_sessions_failed": "Could not end the sessions",
+		"admin.password_failed":        "Could not generate a password",
+		"admin.self_action":            "This action cannot be applied to your own account",
+		"permission.invalid_role":      "Invalid role",
+		"permission.unknown":           "Unknown permission",
+		"permission.admin_manage":      "ADMIN cannot lose the permission:manage permission",
+		"permission.save_failed":       "Could not save the permissions",
+		"permission.apply_failed":      "Permissions were saved but not applied",
+
+		"booking.list_failed":          "Could not load bookings",
+		"booking.history_failed":       "Could not load the status history",
+		"booking.not_pending":          "Only PENDING bookings can change status",
+		"booking.update_status_failed": "Could not update the status",
+		"booking.cancel_failed":        "Could not cancel the booking",
+		"booking.group_part_cancel":    "A part of a group booking can only be canceled with the whole group",
+		"booking.group_not_found":      "Group booking not found",
+		"booking.create_group_failed":  "Could not create the group booking",
+		"booking.update_group_failed":  "Could not update the group booking",
+		"bundle.not_found":             "Bundle not found",
+		"bundle.list_failed":           "Could not load bundles",
+		"bundle.create_failed":         "Could not create the bundle",
+		"bundle.delete_failed":         "Could not delete the bundle",
+		"bundle.resource_not_found":    "Resource #%d not found",
+		"bundle.resource_forbidden":    "Insufficient permissions for resource #%d",
+		"bundle.mixed_owners":          "All resources in a bundle must belong to the same owner",
+		"bundle.invalid_quantity":      "Resource #%d: quantity must be between 1 and %d",
+		"waitlist.join_failed":         "Could not join the waitlist",
+		"waitlist.list_failed":         "Could not load the waitlist",
+		"waitlist.entry_not_found":     "Waitlist entry not found",
+		"waitlist.not_waiting":         "The entry is no longer on the waitlist",
+		"waitlist.leave_failed":        "Could not leave the waitlist",
+		"waitlist.claim_conflict":      "The selected time is already booked. The entry is back on the waitlist",
+		"waitlist.claim_failed":        "Could not claim the spot",
+
+		"resource.list_failed":      "Could not load resources",
+		"resource.load_failed":      "Could not load the resource",
+		"resource.create_failed":    "Could not create the resource",
+		"resource.update_failed":    "Could not update the resource",
+		"opening_hours.load_failed": "Could not load the opening hours",
+		"opening_hours.save_failed": "Could not save the opening hours",
+		"image.list_failed":         "Could not load photos",
+		"image.multipart_required":  "Expected multipart/form-data with a file field",
+		"image.too_many_files":      "At most 10 files at a time",
+		"image.read_failed":         "Could not read the file",
+		"image.save_failed":         "Could not save the photo",
+		"image.invalid_order":       "imageIds must list every photo of the listing exactly once",
+		"image.reorder_failed":      "Could not change the order",
+		"image.cover_failed":        "Could not set the cover",
+		"image.delete_failed":       "Could not delete the photo",
+		"image.invalid_id":          "Invalid photo id",
+		"image.not_found":           "Photo not found",
+
+		"category.list_failed":          "Could not load categories",
+		"category.create_failed":        "Could not create the category",
+		"category.update_failed":        "Could not update the category",
+		"category.delete_failed":        "Could not delete the category",
+		"category.merge_failed":         "Could not merge the categories",
+		"category.archived":             "The category is archived — choose another one",
+		"category.parent_not_found":     "Parent category not found",
+		"category.parent_archived":      "The parent category is archived",
+		"category.target_not_found":     "Target category not found",
+		"category.target_archived":      "The target category is archived",
+		"category.move_into_self":       "A category cannot be moved into itself or its subcategory",
+		"category.merge_into_self":      "A category cannot be merged with itself or its subcategory",
+		"category.not_empty":            "The category is not empty: move the listings (merge) or archive the category",
+		"attribute.list_failed":         "Could not load attributes",
+		"attribute.check_failed":        "Could not check the attribute",
+		"attribute.check_values_failed": "Could not check the attributes",
+		"attribute.create_failed":       "Could not create the attribute",
+		"attribute.update_failed":       "Could not update the attribute",
+		"attribute.delete_failed":       "Could not delete the attribute",
+		"attribute.code_taken":          "An attribute with code %s already exists in this category branch",
+		"attribute.immutable":           "An attribute's code and type cannot be changed — create a new attribute",
+		"attribute.invalid_id":          "Invalid attribute id",
+		"attribute.not_found":           "Attribute not found",
+		"report.build_failed":           "Could not build the report",
+		"report.csv_failed":             "Could not generate the CSV",
+
+		"organization.not_found":            "Organization not found",
+		"organization.create_failed":        "Could not create the organization",
+		"organization.list_failed":          "Could not load organizations",
+		"organization.not_member":           "You are not a member of this organization",
+		"organization.access_denied":        "Insufficient permissions in the organization",
+		"organization.members_failed":       "Could not load members",
+		"organization.member_not_found":     "Member not found",
+		"organization.owner_only_roles":     "Only the organization owner can change roles",
+		"organization.owner_only_remove":    "Only the organization owner can remove members",
+		"organization.update_role_failed":   "Could not update the role",
+		"organization.remove_member_failed": "Could not remove the member",
+		"organization.last_owner":           "The organization must keep at least one owner",
+		"invite.forbidden":                  "Only an organization owner or manager can invite",
+		"invite.owner_forbidden":            "Only the organization owner can invite an owner",
+		"invite.create_failed":              "Could not create the invitation",
+		"invite.list_failed":                "Could not load invitations",
+		"invite.accept_failed":              "Could not accept the invitation",
+		"invite.decline_failed":             "Could not decline the invitation",
+		"invite.token_required":             "The invitation token is required",
+		"invite.not_found":                  "Invitation not found",
+		"invite.wrong_email":                "The invitation was issued to a different email",
+		"invite.used":                       "The invitation has already been used",
+
+		"report.bookings":          "Bookings",
+		"report.pending":           "Pending",
+		"report.approved":          "Approved",
+		"report.rejected":          "Rejected",
+		"report.canceled":          "Canceled",
+		"report.approval_rate":     "Approval rate",
+		"report.avg_lead_time":     "Average lead time, h",
+		"report.revenue":           "Revenue",
+		"report.period":            "Period",
+		"report.resource_id":       "Resource ID",
+		"report.title":             "Title",
+		"report.booked_hours":      "Booked hours",
+		"report.occupancy_percent": "Occupancy, %",
+		"report.category_id":       "Category ID",
+		"report.name":              "Name",
+		"report.owner_user_id":     "Owner ID",
+		"report.owner_name":        "Owner",
+
+		"notify.waitlist_booked":  "A spot opened up: booking #%d for %s was created automatically",
+		"notify.waitlist_offered": "A spot opened up: confirm the booking for %s before %s",
+	},
+}
@@ -0,0 +1,102 @@
+// Package imaging — декодирование загруженных изображений и уменьшенные копии на чистом Go
+// (стандартные image/jpeg, image/png, image/gif, без cgo и внешних библиотек).
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif" // регистрирует декодер GIF для image.Decode
+	"image/jpeg"
+	_ "image/png" // регистрирует декодер PNG для image.Decode
+)
+
+// MaxPixels — защита от "бомб": маленький файл с огромными размерами кадра
+const MaxPixels = 40_000_000
+
+var ErrTooManyPixels = errors.New("изображение слишком большое по размерам")
+
+// Decode читает изображение, предварительно проверив размеры кадра
+func Decode(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// Thumbnail уменьшает изображение так, чтобы большая сторона была не больше maxSide.
+// Используется усреднение по площади (box filter): для уменьшения это даёт гладкий результат
+// без муара. Маленькие изображения не увеличиваются.
+func Thumbnail(src image.Image, maxSide int) *image.RGBA {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+
+	dw, dh := sw, sh
+	if sw > maxSide || sh > maxSide {
+		if sw >= sh {
+			dw, dh = maxSide, max(1, sh*maxSide/sw)
+		} else {
+			dw, dh = max(1, sw*maxSide/sh), maxSide
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0 := b.Min.Y + y*sh/dh
+		y1 := max(y0+1, b.Min.Y+(y+1)*sh/dh)
+		for x := 0; x < dw; x++ {
+			x0 := b.Min.X + x*sw/dw
+			x1 := max(x0+1, b.Min.X+(x+1)*sw/dw)
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					bl += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(bl / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
+
+// EncodeJPEG кодирует изображение в JPEG; прозрачность заливается белым
+func EncodeJPEG(img image.Image, quality int) ([]byte, error) {
+	b := img.Bounds()
+	flat := image.NewRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, a := img.At(x, y).RGBA()
+			// premultiplied alpha: добавляем белый фон пропорционально прозрачности
+			bg := 0xffff - a
+			flat.SetRGBA(x, y, color.RGBA{
+				R: uint8((r + bg) >> 8),
+				G: uint8((g + bg) >> 8),
+				B: uint8((bl + bg) >> 8),
+				A: 0xff,
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,68 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func pngBytes(t *testing.T, w, h int, c color.Color) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestThumbnail_KeepsAspectRatio(t *testing.T) {
+	img, err := Decode(pngBytes(t, 800, 400, color.RGBA{R: 200, A: 255}))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	th := Thumbnail(img, 320)
+	if th.Bounds().Dx() != 320 || th.Bounds().Dy() != 160 {
+		t.Fatalf("unexpected size %v", th.Bounds())
+	}
+	if c := th.RGBAAt(10, 10); c.R != 200 || c.G != 0 || c.A != 255 {
+		t.Fatalf("colour must survive averaging, got %+v", c)
+	}
+}
+
+func TestThumbnail_DoesNotUpscale(t *testing.T) {
+	img, _ := Decode(pngBytes(t, 50, 80, color.White))
+	if th := Thumbnail(img, 320); th.Bounds().Dx() != 50 || th.Bounds().Dy() != 80 {
+		t.Fatalf("unexpected size %v", th.Bounds())
+	}
+}
+
+func TestEncodeJPEG_FlattensTransparency(t *testing.T) {
+	img, _ := Decode(pngBytes(t, 4, 4, color.Transparent))
+	data, err := EncodeJPEG(img, 80)
+	if err != nil {
+		t.Fatalf("EncodeJPEG: %v", err)
+	}
+	out, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("jpeg.Decode: %v", err)
+	}
+	if r, g, b, _ := out.At(1, 1).RGBA(); r>>8 < 250 || g>>8 < 250 || b>>8 < 250 {
+		t.Fatalf("transparent pixels must become white")
+	}
+}
+
+func TestDecode_RejectsGarbage(t *testing.T) {
+	if _, err := Decode([]byte("not an image")); err == nil {
+		t.Fatalf("expected error")
+	}
+}
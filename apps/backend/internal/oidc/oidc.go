@@ -0,0 +1,396 @@
+// Package oidc — вход через корпоративного провайдера OpenID Connect: код авторизации с PKCE,
+// обмен кода на токены и проверка ID-токена по ключам провайдера (JWKS).
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// FlowTTL — сколько ждём возврата пользователя от провайдера
+const FlowTTL = 10 * time.Minute
+
+var (
+	// ErrUnavailable — провайдер не ответил или ответил не так, как должен (сеть, 5xx, битый JSON)
+	ErrUnavailable = errors.New("oidc: провайдер недоступен")
+	// ErrInvalidState — state подделан, испорчен или просрочен
+	ErrInvalidState = errors.New("oidc: неверный или просроченный state")
+	// ErrInvalidIDToken — ID-токен не прошёл проверку подписи или claims
+	ErrInvalidIDToken = errors.New("oidc: неверный ID-токен")
+)
+
+// Error — отказ провайдера по протоколу OAuth 2.0 (invalid_grant и т.п.)
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *Error) Error() string {
+	if e.Description != "" {
+		return "oidc: " + e.Code + ": " + e.Description
+	}
+	return "oidc: " + e.Code
+}
+
+// Config — настройки клиента у провайдера
+type Config struct {
+	Issuer       string // https://id.example.com/realms/corp; метаданные — Issuer + /.well-known/openid-configuration
+	ClientID     string
+	ClientSecret string // пусто — публичный клиент, защищённый только PKCE
+	RedirectURL  string // куда провайдер вернёт пользователя с кодом
+	Scopes       []string
+	GroupsClaim  string // claim со списком групп; по умолчанию groups
+	StateKey     []byte // 32 байта для шифрования state (AES-256-GCM)
+}
+
+// Metadata — нужная нам часть метаданных провайдера (OpenID Connect Discovery)
+type Metadata struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	CodeChallengeMethods  []string `json:"code_challenge_methods_supported"`
+}
+
+// Claims — кто вошёл, по данным ID-токена
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Groups        []string
+}
+
+// Provider — клиент одного провайдера. Метаданные и ключи запрашиваются при первом входе
+// и кэшируются, так что недоступный провайдер не мешает запуску сервера.
+type Provider struct {
+	cfg    Config
+	client *http.Client
+	now    func() time.Time
+
+	mu          sync.Mutex
+	meta        *Metadata
+	keys        map[string]any
+	keysFetched time.Time
+}
+
+func NewProvider(cfg Config) *Provider {
+	cfg.Issuer = strings.TrimRight(cfg.Issuer, "/")
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+	return &Provider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+		now:    time.Now,
+	}
+}
+
+// WithHTTPClient задаёт HTTP-клиент для запросов к провайдеру
+func (p *Provider) WithHTTPClient(c *http.Client) *Provider {
+	p.client = c
+	return p
+}
+
+// Issuer — издатель, как он задан в настройках
+func (p *Provider) Issuer() string { return p.cfg.Issuer }
+
+// Start начинает вход: адрес страницы входа у провайдера и state, который вернётся вместе с кодом.
+// В state зашифрованы верификатор PKCE и nonce — сервер ничего не хранит между шагами.
+func (p *Provider) Start(ctx context.Context) (authURL, state string, expires time.Time, err error) {
+	meta, err := p.Discover(ctx)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	flow := flow{Verifier: randomString(32), Nonce: randomString(16)}
+	expires = p.now().Add(FlowTTL)
+	flow.Expires = expires.Unix()
+	if state, err = sealFlow(p.cfg.StateKey, flow); err != nil {
+		return "", "", time.Time{}, err
+	}
+
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(p.cfg.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {flow.Nonce},
+		"code_challenge":        {codeChallenge(flow.Verifier)},
+		"code_challenge_method": {"S256"},
+	}
+	sep := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return meta.AuthorizationEndpoint + sep + q.Encode(), state, expires, nil
+}
+
+// Finish завершает вход: расшифровывает state, меняет код на токены и проверяет ID-токен
+func (p *Provider) Finish(ctx context.Context, code, state string) (*Claims, error) {
+	f, err := openFlow(p.cfg.StateKey, state, p.now())
+	if err != nil {
+		return nil, err
+	}
+	idToken, err := p.exchange(ctx, code, f.Verifier)
+	if err != nil {
+		return nil, err
+	}
+	return p.verify(ctx, idToken, f.Nonce)
+}
+
+// Discover загружает метаданные провайдера; успешный ответ кэшируется
+func (p *Provider) Discover(ctx context.Context) (*Metadata, error) {
+	p.mu.Lock()
+	meta := p.meta
+	p.mu.Unlock()
+	if meta != nil {
+		return meta, nil
+	}
+
+	meta = &Metadata{}
+	if err := p.getJSON(ctx, p.cfg.Issuer+"/.well-known/openid-configuration", meta); err != nil {
+		return nil, err
+	}
+	// метаданные чужого издателя — признак подмены или ошибки в OIDC_ISSUER
+	if strings.TrimRight(meta.Issuer, "/") != p.cfg.Issuer {
+		return nil, fmt.Errorf("%w: issuer в метаданных %q, ожидали %q", ErrUnavailable, meta.Issuer, p.cfg.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: в метаданных нет нужных адресов", ErrUnavailable)
+	}
+
+	p.mu.Lock()
+	p.meta = meta
+	p.mu.Unlock()
+	return meta, nil
+}
+
+// exchange меняет код на токены и возвращает ID-токен
+func (p *Provider) exchange(ctx context.Context, code, verifier string) (string, error) {
+	meta, err := p.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		// client_secret_basic — способ по умолчанию в спецификации
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+		oerr := &Error{}
+		if json.Unmarshal(body, oerr) == nil && oerr.Code != "" {
+			return "", oerr
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: token endpoint: %s", ErrUnavailable, resp.Status)
+	}
+
+	var tok struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &tok); err != nil {
+		return "", fmt.Errorf("%w: token endpoint: %v", ErrUnavailable, err)
+	}
+	if tok.IDToken == "" {
+		return "", fmt.Errorf("%w: провайдер не выдал id_token", ErrInvalidIDToken)
+	}
+	return tok.IDToken, nil
+}
+
+// verify проверяет подпись ID-токена ключом провайдера, издателя, аудиторию, срок и nonce
+func (p *Provider) verify(ctx context.Context, raw, nonce string) (*Claims, error) {
+	meta, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, meta, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384"}),
+		jwt.WithIssuer(meta.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+		jwt.WithTimeFunc(p.now),
+	)
+	if errors.Is(err, ErrUnavailable) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if n, _ := claims["nonce"].(string); n == "" || n != nonce {
+		return nil, fmt.Errorf("%w: nonce не совпадает", ErrInvalidIDToken)
+	}
+	// при нескольких аудиториях токен должен быть выписан именно нам
+	if aud, _ := claims.GetAudience(); len(aud) > 1 {
+		if azp, _ := claims["azp"].(string); azp != p.cfg.ClientID {
+			return nil, fmt.Errorf("%w: azp не совпадает", ErrInvalidIDToken)
+		}
+	}
+
+	c := &Claims{
+		Email:         stringClaim(claims, "email"),
+		EmailVerified: boolClaim(claims, "email_verified"),
+		Name:          stringClaim(claims, "name"),
+		Groups:        stringsClaim(claims, p.cfg.GroupsClaim),
+	}
+	c.Subject, _ = claims.GetSubject()
+	if c.Subject == "" {
+		return nil, fmt.Errorf("%w: нет sub", ErrInvalidIDToken)
+	}
+	return c, nil
+}
+
+// key — открытый ключ провайдера по kid. Незнакомый kid — повод перечитать JWKS
+// (провайдер мог сменить ключи), но не чаще раза в минуту.
+func (p *Provider) key(ctx context.Context, meta *Metadata, kid string) (any, error) {
+	p.mu.Lock()
+	keys, fetched := p.keys, p.keysFetched
+	p.mu.Unlock()
+
+	if k := pickKey(keys, kid); k != nil {
+		return k, nil
+	}
+	if keys != nil && p.now().Sub(fetched) < time.Minute {
+		return nil, fmt.Errorf("неизвестный ключ %q", kid)
+	}
+
+	var set jwkSet
+	if err := p.getJSON(ctx, meta.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+	keys = set.publicKeys()
+
+	p.mu.Lock()
+	p.keys, p.keysFetched = keys, p.now()
+	p.mu.Unlock()
+
+	if k := pickKey(keys, kid); k != nil {
+		return k, nil
+	}
+	return nil, fmt.Errorf("неизвестный ключ %q", kid)
+}
+
+// pickKey — ключ по kid; без kid подходит только единственный ключ
+func pickKey(keys map[string]any, kid string) any {
+	if kid == "" && len(keys) == 1 {
+		for _, k := range keys {
+			return k
+		}
+	}
+	return keys[kid]
+}
+
+func (p *Provider) getJSON(ctx context.Context, u string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s: %s", ErrUnavailable, u, resp.Status)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(dst); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrUnavailable, u, err)
+	}
+	return nil
+}
+
+// codeChallenge — S256: base64url(SHA-256(verifier)) без выравнивания (RFC 7636)
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// randomString — n случайных байт в base64url; из 32 байт получается верификатор PKCE длиной 43 символа
+func randomString(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err) // crypto/rand не отказывает на поддерживаемых платформах
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func stringClaim(c jwt.MapClaims, name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+// boolClaim — некоторые провайдеры отдают email_verified строкой "true"
+func boolClaim(c jwt.MapClaims, name string) bool {
+	switch v := c[name].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
+
+// stringsClaim — список строк; одиночная строка считается списком из одного элемента
+func stringsClaim(c jwt.MapClaims, name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		return []string{v}
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"bookinghub-backend/internal/oidc/oidctest"
+)
+
+var testStateKey = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestProvider(t *testing.T, secret string) (*Provider, *oidctest.Server) {
+	t.Helper()
+	idp := oidctest.NewServer("bookinghub", secret)
+	t.Cleanup(idp.Close)
+	p := NewProvider(Config{
+		Issuer:       idp.Issuer(),
+		ClientID:     "bookinghub",
+		ClientSecret: secret,
+		RedirectURL:  "http://app.local/sso/callback",
+		StateKey:     testStateKey,
+	})
+	return p, idp
+}
+
+func TestProvider_LoginFlow(t *testing.T) {
+	ctx := context.Background()
+	p, idp := newTestProvider(t, "s3cret")
+	idp.SetUser(&oidctest.User{Subject: "u-1", Email: "Ann@corp.example", EmailVerified: true, Name: "Анна", Groups: []string{"staff", "bookinghub-admins"}})
+
+	authURL, state, expires, err := p.Start(ctx)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if time.Until(expires) <= 0 || time.Until(expires) > FlowTTL {
+		t.Errorf("expires = %v", expires)
+	}
+	u, _ := url.Parse(authURL)
+	q := u.Query()
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" || q.Get("nonce") == "" {
+		t.Errorf("в адресе входа нет PKCE или nonce: %s", authURL)
+	}
+	if q.Get("state") != state || q.Get("redirect_uri") != "http://app.local/sso/callback" {
+		t.Errorf("state/redirect_uri: %s", authURL)
+	}
+
+	code, gotState, err := idp.Login(authURL)
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	claims, err := p.Finish(ctx, code, gotState)
+	if err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	want := &Claims{Subject: "u-1", Email: "Ann@corp.example", EmailVerified: true, Name: "Анна", Groups: []string{"staff", "bookinghub-admins"}}
+	if !reflect.DeepEqual(claims, want) {
+		t.Errorf("claims = %+v, want %+v", claims, want)
+	}
+
+	// код одноразовый
+	_, err = p.Finish(ctx, code, gotState)
+	var oerr *Error
+	if !errors.As(err, &oerr) || oerr.Code != "invalid_grant" {
+		t.Errorf("повторный обмен кода: %v", err)
+	}
+}
+
+func TestProvider_PublicClient(t *testing.T) {
+	ctx := context.Background()
+	p, idp := newTestProvider(t, "")
+	idp.SetUser(&oidctest.User{Subject: "u-2", Email: "bob@corp.example"})
+
+	authURL, _, _, err := p.Start(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state, err := idp.Login(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := p.Finish(ctx, code, state)
+	if err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	if claims.EmailVerified || claims.Groups != nil {
+		t.Errorf("claims = %+v", claims)
+	}
+}
+
+func TestProvider_RejectsBadState(t *testing.T) {
+	ctx := context.Background()
+	p, idp := newTestProvider(t, "s3cret")
+	idp.SetUser(&oidctest.User{Subject: "u-1", Email: "ann@corp.example", EmailVerified: true})
+
+	authURL, _, _, err := p.Start(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state, err := idp.Login(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seal := func(key []byte, f flow) string {
+		s, err := sealFlow(key, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	later := time.Now().Add(time.Minute).Unix()
+	for name, s := range map[string]string{
+		"пустой":           "",
+		"испорченный":      state[:len(state)-2] + "AA",
+		"не base64":        "%%%",
+		"чужой ключ":       seal([]byte(strings.Repeat("k", 32)), flow{Verifier: "v", Nonce: "n", Expires: later}),
+		"просроченный":     seal(testStateKey, flow{Verifier: "v", Nonce: "n", Expires: time.Now().Add(-time.Second).Unix()}),
+		"без верификатора": seal(testStateKey, flow{Nonce: "n", Expires: later}),
+	} {
+		if _, err := p.Finish(ctx, code, s); !errors.Is(err, ErrInvalidState) {
+			t.Errorf("%s: err = %v, want ErrInvalidState", name, err)
+		}
+	}
+}
+
+func TestProvider_RejectsBadIDToken(t *testing.T) {
+	cases := map[string]func(jwt.MapClaims){
+		"чужой nonce":     func(c jwt.MapClaims) { c["nonce"] = "other" },
+		"чужая аудитория": func(c jwt.MapClaims) { c["aud"] = "someone-else" },
+		"чужой издатель":  func(c jwt.MapClaims) { c["iss"] = "https://evil.example" },
+		"просрочен":       func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"без sub":         func(c jwt.MapClaims) { delete(c, "sub") },
+		"azp не наш": func(c jwt.MapClaims) {
+			c["aud"] = []string{"bookinghub", "other"}
+			c["azp"] = "other"
+		},
+	}
+	for name, tamper := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			p, idp := newTestProvider(t, "s3cret")
+			idp.SetUser(&oidctest.User{Subject: "u-1", Email: "ann@corp.example", EmailVerified: true})
+			idp.TamperIDToken = tamper
+
+			authURL, _, _, err := p.Start(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			code, state, err := idp.Login(authURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := p.Finish(ctx, code, state); !errors.Is(err, ErrInvalidIDToken) {
+				t.Errorf("err = %v, want ErrInvalidIDToken", err)
+			}
+		})
+	}
+}
+
+func TestProvider_AccessDenied(t *testing.T) {
+	p, idp := newTestProvider(t, "s3cret")
+	idp.SetUser(nil)
+
+	authURL, _, _, err := p.Start(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := idp.Login(authURL); err == nil || !strings.Contains(err.Error(), "access_denied") {
+		t.Errorf("err = %v, want access_denied", err)
+	}
+}
+
+func TestProvider_Unavailable(t *testing.T) {
+	p, idp := newTestProvider(t, "s3cret")
+	idp.Close()
+
+	if _, _, _, err := p.Start(context.Background()); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("err = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestDiscover_IssuerMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"issuer":"https://evil.example","authorization_endpoint":"https://evil.example/a","token_endpoint":"https://evil.example/t","jwks_uri":"https://evil.example/k"}`))
+	}))
+	defer srv.Close()
+
+	p := NewProvider(Config{Issuer: srv.URL, ClientID: "bookinghub", StateKey: testStateKey})
+	if _, err := p.Discover(context.Background()); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("метаданные с чужим issuer: err = %v", err)
+	}
+}
+
+func TestClaimHelpers(t *testing.T) {
+	c := jwt.MapClaims{"ev": "true", "g": "admins", "list": []any{"a", 1, "b"}}
+	if !boolClaim(c, "ev") || boolClaim(c, "missing") {
+		t.Error("boolClaim")
+	}
+	if got := stringsClaim(c, "g"); !reflect.DeepEqual(got, []string{"admins"}) {
+		t.Errorf("одиночная группа: %v", got)
+	}
+	if got := stringsClaim(c, "list"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("список групп: %v", got)
+	}
+}
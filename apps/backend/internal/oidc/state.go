@@ -0,0 +1,70 @@
+package oidc
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// flow — то, что нужно помнить между началом входа и возвратом от провайдера.
+// Хранится в самом state зашифрованным: верификатор PKCE не должен быть виден
+// тому, кто перехватит адрес возврата с кодом.
+type flow struct {
+	Verifier string `json:"v"`
+	Nonce    string `json:"n"`
+	Expires  int64  `json:"e"`
+}
+
+func sealFlow(key []byte, f flow) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	plain, err := json.Marshal(f)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, plain, nil)), nil
+}
+
+func openFlow(key []byte, state string, now time.Time) (flow, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return flow{}, err
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(state)
+	if err != nil || len(raw) < aead.NonceSize() {
+		return flow{}, ErrInvalidState
+	}
+	plain, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], nil)
+	if err != nil {
+		return flow{}, ErrInvalidState
+	}
+	var f flow
+	if err := json.Unmarshal(plain, &f); err != nil || f.Verifier == "" {
+		return flow{}, ErrInvalidState
+	}
+	if now.Unix() > f.Expires {
+		return flow{}, ErrInvalidState
+	}
+	return f, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, errors.New("oidc: StateKey должен быть 32 байта")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
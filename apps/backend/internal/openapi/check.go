@@ -0,0 +1,315 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+
+	"bookinghub-backend/internal/validate"
+)
+
+// Validator — middleware для тестов: после обработки запроса сверяет его и ответ с документом
+// и передаёт расхождения в report; ответ клиенту не меняется. Ставится в роутер chi до маршрутов:
+// операция ищется по шаблону совпавшего маршрута.
+//
+// Тело запроса сверяется только у успешных ответов: отклонить запрос вне спецификации —
+// правильное поведение, а принять его — расхождение. Ответ сверяется всегда, ошибки — со схемой
+// ответа default. Запросы, не дошедшие ни до одного маршрута (404, 405), пропускаются.
+func Validator(doc *Document, report func(r *http.Request, err error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var reqBody []byte
+			if r.Body != nil {
+				reqBody, _ = io.ReadAll(r.Body)
+				r.Body = io.NopCloser(bytes.NewReader(reqBody))
+			}
+			rec := &recorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+
+			rctx := chi.RouteContext(r.Context())
+			if rctx == nil || rctx.RoutePattern() == "" {
+				return
+			}
+			op := doc.Operation(r.Method, rctx.RoutePattern())
+			if op == nil {
+				if rec.status() != http.StatusNotFound && rec.status() != http.StatusMethodNotAllowed {
+					report(r, fmt.Errorf("операции %s %s нет в спецификации", r.Method, Path(rctx.RoutePattern())))
+				}
+				return
+			}
+			if err := doc.checkExchange(op, r, reqBody, rec); err != nil {
+				report(r, fmt.Errorf("%s %s → %d: %w", r.Method, Path(rctx.RoutePattern()), rec.status(), err))
+			}
+		})
+	}
+}
+
+// recorder пропускает ответ клиенту и сохраняет копию для проверки
+type recorder struct {
+	http.ResponseWriter
+	code int
+	body bytes.Buffer
+}
+
+func (rec *recorder) WriteHeader(code int) {
+	if rec.code == 0 {
+		rec.code = code
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *recorder) Write(p []byte) (int, error) {
+	if rec.code == 0 {
+		rec.code = http.StatusOK
+	}
+	rec.body.Write(p)
+	return rec.ResponseWriter.Write(p)
+}
+
+func (rec *recorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+func (rec *recorder) status() int {
+	if rec.code == 0 {
+		return http.StatusOK
+	}
+	return rec.code
+}
+
+// contentType — тип тела ответа; без заголовка его определяет net/http по содержимому
+func (rec *recorder) contentType() string {
+	ct := rec.Header().Get("Content-Type")
+	if ct == "" && rec.body.Len() > 0 {
+		ct = http.DetectContentType(rec.body.Bytes())
+	}
+	mt, _, _ := mime.ParseMediaType(ct)
+	return mt
+}
+
+func (d *Document) checkExchange(op *Operation, r *http.Request, reqBody []byte, rec *recorder) error {
+	var errs []error
+	status := rec.status()
+
+	if status >= 200 && status < 300 {
+		for _, p := range op.Parameters {
+			if p.In == "query" && p.Required && !r.URL.Query().Has(p.Name) {
+				errs = append(errs, fmt.Errorf("запрос: нет обязательного параметра %s", p.Name))
+			}
+		}
+		if rb := op.RequestBody; rb != nil {
+			if mt, ok := rb.Content[jsonType]; ok {
+				if err := d.ValidateJSON(mt.Schema, reqBody); err != nil {
+					errs = append(errs, fmt.Errorf("запрос: %w", err))
+				}
+			}
+		} else if len(bytes.TrimSpace(reqBody)) > 0 {
+			errs = append(errs, errors.New("запрос: тело не описано в спецификации"))
+		}
+	}
+
+	resp := op.Responses[strconv.Itoa(status)]
+	if resp == nil && status >= 400 {
+		resp = op.Responses[defaultResponse]
+	}
+	if resp != nil && resp.Ref != "" {
+		resp = d.Components.Responses[strings.TrimPrefix(resp.Ref, "#/components/responses/")]
+	}
+	switch {
+	case resp == nil:
+		errs = append(errs, fmt.Errorf("ответ: статус %d не описан", status))
+	case len(resp.Content) == 0:
+		if rec.body.Len() > 0 {
+			errs = append(errs, errors.New("ответ: тело не описано в спецификации"))
+		}
+	default:
+		ct := rec.contentType()
+		mt, ok := mediaType(resp.Content, ct)
+		if !ok {
+			errs = append(errs, fmt.Errorf("ответ: тип %q не описан", ct))
+		} else if ct == jsonType {
+			if err := d.ValidateJSON(mt.Schema, rec.body.Bytes()); err != nil {
+				errs = append(errs, fmt.Errorf("ответ: %w", err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// mediaType ищет описание тела по типу содержимого: точно, затем image/* и */*
+func mediaType(content map[string]MediaType, ct string) (MediaType, bool) {
+	major, _, _ := strings.Cut(ct, "/")
+	for _, key := range []string{ct, major + "/*", "*/*"} {
+		if mt, ok := content[key]; ok {
+			return mt, true
+		}
+	}
+	return MediaType{}, false
+}
+
+// ValidateJSON проверяет JSON-документ по схеме и возвращает все нарушения с путями до полей
+func (d *Document) ValidateJSON(s *Schema, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf("некорректный JSON: %w", err)
+	}
+	c := &checker{doc: d}
+	c.check(s, v, "$")
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(c.errs, "; "))
+}
+
+type checker struct {
+	doc  *Document
+	errs []string
+}
+
+func (c *checker) fail(path, format string, args ...any) {
+	c.errs = append(c.errs, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (c *checker) check(s *Schema, v any, path string) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		target := c.doc.Lookup(s.Ref)
+		if target == nil {
+			c.fail(path, "схема %s не найдена", s.Ref)
+			return
+		}
+		s = target
+	}
+	if len(s.AnyOf) > 0 {
+		for _, alt := range s.AnyOf {
+			sub := &checker{doc: c.doc}
+			sub.check(alt, v, path)
+			if len(sub.errs) == 0 {
+				return
+			}
+		}
+		c.fail(path, "значение не подходит ни под один вариант")
+		return
+	}
+
+	got := typeOf(v)
+	if len(s.Type) > 0 && !slices.Contains(s.Type, got) && !(got == "integer" && slices.Contains(s.Type, "number")) {
+		c.fail(path, "ожидался %s, получен %s", strings.Join(s.Type, " | "), got)
+		return
+	}
+	if v == nil {
+		return
+	}
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, v) {
+		c.fail(path, "значение %v не из списка %v", v, s.Enum)
+	}
+
+	switch val := v.(type) {
+	case string:
+		c.checkString(s, val, path)
+	case json.Number:
+		f, _ := val.Float64()
+		if s.Minimum != nil && f < *s.Minimum {
+			c.fail(path, "%v меньше %v", val, *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			c.fail(path, "%v больше %v", val, *s.Maximum)
+		}
+	case []any:
+		if s.MinItems != nil && len(val) < *s.MinItems {
+			c.fail(path, "элементов меньше %d", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(val) > *s.MaxItems {
+			c.fail(path, "элементов больше %d", *s.MaxItems)
+		}
+		for i, item := range val {
+			c.check(s.Items, item, path+"["+strconv.Itoa(i)+"]")
+		}
+	case map[string]any:
+		c.checkObject(s, val, path)
+	}
+}
+
+func (c *checker) checkString(s *Schema, v, path string) {
+	n := utf8.RuneCountInString(v)
+	if s.MinLength != nil && n < *s.MinLength {
+		c.fail(path, "короче %d символов", *s.MinLength)
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		c.fail(path, "длиннее %d символов", *s.MaxLength)
+	}
+	switch s.Format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, v); err != nil {
+			c.fail(path, "%q — не дата-время RFC 3339", v)
+		}
+	case "email":
+		if !validate.Email(v) {
+			c.fail(path, "%q — не email", v)
+		}
+	}
+}
+
+func (c *checker) checkObject(s *Schema, v map[string]any, path string) {
+	for _, name := range s.Required {
+		if _, ok := v[name]; !ok {
+			c.fail(path, "нет обязательного поля %s", name)
+		}
+	}
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		if ps, ok := s.Properties[k]; ok {
+			c.check(ps, v[k], path+"."+k)
+			continue
+		}
+		switch extra := s.AdditionalProperties.(type) {
+		case bool:
+			if !extra {
+				c.fail(path, "поле %s не описано", k)
+			}
+		case *Schema:
+			c.check(extra, v[k], path+"."+k)
+		}
+	}
+}
+
+// typeOf — тип JSON-значения в терминах JSON Schema; целые числа — integer
+func typeOf(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if strings.ContainsAny(val.String(), ".eE") {
+			return "number"
+		}
+		return "integer"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
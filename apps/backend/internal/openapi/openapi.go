@@ -0,0 +1,290 @@
+// Package openapi собирает документ OpenAPI 3.1 из таблицы маршрутов и типов Go.
+//
+// Схемы тел строятся отражением по тегам json, ограничения берутся из тегов validate
+// (required, min, max, email, oneof) — теми же, по которым обработчики проверяют запросы,
+// поэтому спецификация не расходится с кодом. Для тестов есть Validator: middleware, которое
+// сверяет настоящие запросы и ответы с документом.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version — версия спецификации OpenAPI
+const Version = "3.1.0"
+
+// Info — заголовок документа
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Param — query-параметр операции
+type Param struct {
+	Name        string
+	Description string
+	Required    bool
+	// Type — "string" (по умолчанию), "integer", "number" или "boolean"
+	Type string
+	// Enum — допустимые значения, если их немного
+	Enum []string
+}
+
+// Reply — дополнительный ответ операции помимо успешного и ошибок в общем формате
+type Reply struct {
+	Status      int
+	Description string
+	// Body — значение типа JSON-тела; nil, если тело не JSON
+	Body any
+	// Content — тип содержимого не-JSON тела, например text/plain
+	Content string
+}
+
+// Route — операция API. Path — шаблон chi, как в роутере: /api/resources/{id}.
+type Route struct {
+	ID      string
+	Method  string
+	Path    string
+	Summary string
+	Tag     string
+	// Auth — нужен заголовок Authorization: Bearer <token>
+	Auth  bool
+	Query []Param
+	// Body — значение типа JSON-тела запроса, например createResourceRequest{}
+	Body any
+	// Upload — имя поля multipart/form-data с файлами, если запрос загружает файлы
+	Upload string
+	// Status — код успешного ответа; 0 — 200
+	Status int
+	// Response — значение типа JSON-тела успешного ответа; nil, если тело не JSON или его нет
+	Response any
+	// Content — тип не-JSON тела успешного ответа (text/plain, text/csv); может идти вместе с Response
+	Content string
+	Replies []Reply
+}
+
+// Document — документ OpenAPI; сериализуется в JSON как есть
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// PathItem — операции пути по методам в нижнем регистре
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response — ответ операции или ссылка Ref на общий ответ из components.responses
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	Responses       map[string]*Response      `json:"responses,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+const (
+	jsonType        = "application/json"
+	bearerAuth      = "bearerAuth"
+	errorResponse   = "Error"
+	defaultResponse = "default"
+)
+
+var pathParam = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// Build собирает документ. errorBody — значение типа тела ошибки: им описан ответ default
+// каждой операции. Повтор метода и пути — ошибка в таблице маршрутов, Build паникует.
+func Build(info Info, errorBody any, routes []Route) *Document {
+	g := newGenerator()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Responses: map[string]*Response{
+				errorResponse: {
+					Description: "Ошибка в общем формате API",
+					Content:     jsonContent(g.schemaOf(reflect.TypeOf(errorBody))),
+				},
+			},
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	for _, rt := range routes {
+		p := Path(rt.Path)
+		method := strings.ToLower(rt.Method)
+		item := doc.Paths[p]
+		if item == nil {
+			item = PathItem{}
+			doc.Paths[p] = item
+		}
+		if item[method] != nil {
+			panic(fmt.Sprintf("openapi: операция %s %s описана дважды", rt.Method, p))
+		}
+		item[method] = g.operation(rt, p)
+	}
+
+	doc.Components.Schemas = g.schemas
+	return doc
+}
+
+func (g *generator) operation(rt Route, p string) *Operation {
+	op := &Operation{
+		OperationID: rt.ID,
+		Summary:     rt.Summary,
+		Responses:   map[string]*Response{},
+	}
+	if rt.Tag != "" {
+		op.Tags = []string{rt.Tag}
+	}
+	if rt.Auth {
+		op.Security = []map[string][]string{{bearerAuth: {}}}
+	}
+
+	for _, m := range pathParam.FindAllStringSubmatch(p, -1) {
+		op.Parameters = append(op.Parameters, Parameter{Name: m[1], In: "path", Required: true, Schema: pathParamSchema(m[1])})
+	}
+	for _, q := range rt.Query {
+		s := &Schema{Type: []string{"string"}}
+		if q.Type != "" {
+			s.Type = []string{q.Type}
+		}
+		for _, v := range q.Enum {
+			s.Enum = append(s.Enum, v)
+		}
+		op.Parameters = append(op.Parameters, Parameter{Name: q.Name, In: "query", Description: q.Description, Required: q.Required, Schema: s})
+	}
+
+	switch {
+	case rt.Body != nil:
+		op.RequestBody = &RequestBody{Required: true, Content: jsonContent(g.schemaOf(reflect.TypeOf(rt.Body)))}
+	case rt.Upload != "":
+		files := &Schema{Type: []string{"array"}, Items: &Schema{Type: []string{"string"}, ContentMediaType: "application/octet-stream"}}
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+			"multipart/form-data": {Schema: &Schema{
+				Type:       []string{"object"},
+				Properties: map[string]*Schema{rt.Upload: files},
+				Required:   []string{rt.Upload},
+			}},
+		}}
+	}
+
+	status := rt.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	op.Responses[strconv.Itoa(status)] = g.response(http.StatusText(status), rt.Response, rt.Content)
+	for _, rep := range rt.Replies {
+		desc := rep.Description
+		if desc == "" {
+			desc = http.StatusText(rep.Status)
+		}
+		op.Responses[strconv.Itoa(rep.Status)] = g.response(desc, rep.Body, rep.Content)
+	}
+	op.Responses[defaultResponse] = &Response{Ref: "#/components/responses/" + errorResponse}
+	return op
+}
+
+func (g *generator) response(desc string, body any, content string) *Response {
+	resp := &Response{Description: desc}
+	if body != nil {
+		resp.Content = jsonContent(g.schemaOf(reflect.TypeOf(body)))
+	}
+	if content != "" {
+		if resp.Content == nil {
+			resp.Content = map[string]MediaType{}
+		}
+		resp.Content[content] = MediaType{Schema: &Schema{Type: []string{"string"}}}
+	}
+	return resp
+}
+
+func jsonContent(s *Schema) map[string]MediaType {
+	return map[string]MediaType{jsonType: {Schema: s}}
+}
+
+// pathParamSchema — идентификаторы ({id}, {userId}) числовые, остальные параметры пути — строки
+func pathParamSchema(name string) *Schema {
+	if name == "id" || strings.HasSuffix(name, "Id") {
+		return &Schema{Type: []string{"integer"}, Minimum: ptr(1.0)}
+	}
+	return &Schema{Type: []string{"string"}}
+}
+
+// Path переводит шаблон chi в путь OpenAPI: регулярные выражения параметров отбрасываются,
+// "*" в конце становится параметром {path}, завершающий "/" убирается, как в chi.RoutePattern
+func Path(pattern string) string {
+	p := pathParam.ReplaceAllString(pattern, "{$1}")
+	if strings.HasSuffix(p, "/*") {
+		p = strings.TrimSuffix(p, "*") + "{path}"
+	}
+	if p != "/" {
+		p = strings.TrimSuffix(p, "/")
+	}
+	return p
+}
+
+// Operation находит операцию по методу и шаблону chi; nil, если её нет в документе
+func (d *Document) Operation(method, pattern string) *Operation {
+	return d.Paths[Path(pattern)][strings.ToLower(method)]
+}
+
+// Lookup ищет схему по ссылке #/components/schemas/<имя>
+func (d *Document) Lookup(ref string) *Schema {
+	return d.Components.Schemas[strings.TrimPrefix(ref, refPrefix)]
+}
+
+// ServeHTTP отдаёт документ в JSON
+func (d *Document) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(d)
+}
@@ -0,0 +1,197 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type limits struct {
+	Capacity int `json:"capacity"`
+}
+
+type sampleReq struct {
+	Email   string            `json:"email" validate:"trim,required,max=190,email"`
+	Role    *string           `json:"role" validate:"oneof=OWNER VIEWER"`
+	Count   *int              `json:"count" validate:"min=1"`
+	Items   []string          `json:"items" validate:"required,max=3"`
+	When    time.Time         `json:"when"`
+	Meta    map[string]any    `json:"meta"`
+	Nested  *sampleReq        `json:"nested"`
+	Hidden  string            `json:"-"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	limits                    // встроенная без тега — поля раскрываются
+	Limits2 limits            `json:"limits"`
+}
+
+type errBody struct {
+	Error struct {
+		Code string `json:"code"`
+	} `json:"error"`
+}
+
+func TestPath(t *testing.T) {
+	cases := map[string]string{
+		"/api/resources/{id}":          "/api/resources/{id}",
+		"/api/items/{id:[0-9]+}/x":     "/api/items/{id}/x",
+		"/uploads/*":                   "/uploads/{path}",
+		"/api/organizations/":          "/api/organizations",
+		"/":                            "/",
+		"/api/admin/roles/{role}/perm": "/api/admin/roles/{role}/perm",
+	}
+	for in, want := range cases {
+		if got := Path(in); got != want {
+			t.Errorf("Path(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSchema_FromTags(t *testing.T) {
+	g := newGenerator()
+	ref := g.schemaOf(reflect.TypeOf(sampleReq{}))
+	if ref.Ref != refPrefix+"sampleReq" {
+		t.Fatalf("expected $ref, got %+v", ref)
+	}
+	s := g.schemas["sampleReq"]
+
+	if !reflect.DeepEqual(s.Required, []string{"email", "items"}) {
+		t.Fatalf("required = %v", s.Required)
+	}
+	if _, ok := s.Properties["Hidden"]; ok {
+		t.Fatal("json:\"-\" field must be skipped")
+	}
+	if _, ok := s.Properties["capacity"]; !ok {
+		t.Fatal("embedded struct fields must be flattened")
+	}
+	if s.Properties["limits"].Ref != refPrefix+"limits" {
+		t.Fatalf("named field must reference component: %+v", s.Properties["limits"])
+	}
+
+	email := s.Properties["email"]
+	if email.Format != "email" || *email.MinLength != 1 || *email.MaxLength != 190 {
+		t.Fatalf("email schema: %+v", email)
+	}
+	role := s.Properties["role"]
+	if !reflect.DeepEqual(role.Type, []string{"string", "null"}) || !reflect.DeepEqual(role.Enum, []any{"OWNER", "VIEWER", nil}) {
+		t.Fatalf("role schema: %+v", role)
+	}
+	if count := s.Properties["count"]; *count.Minimum != 1 {
+		t.Fatalf("count schema: %+v", count)
+	}
+	if items := s.Properties["items"]; *items.MinItems != 1 || *items.MaxItems != 3 {
+		t.Fatalf("items schema: %+v", items)
+	}
+	if when := s.Properties["when"]; when.Format != "date-time" {
+		t.Fatalf("time schema: %+v", when)
+	}
+	if nested := s.Properties["nested"]; len(nested.AnyOf) != 2 || nested.AnyOf[0].Ref != refPrefix+"sampleReq" {
+		t.Fatalf("recursive pointer must be nullable $ref: %+v", nested)
+	}
+
+	// type пишется строкой, если тип один
+	raw, _ := json.Marshal(g.schemas["limits"])
+	if !strings.Contains(string(raw), `"type":"object"`) || !strings.Contains(string(raw), `"additionalProperties":false`) {
+		t.Fatalf("unexpected JSON: %s", raw)
+	}
+}
+
+func testDoc() *Document {
+	return Build(Info{Title: "test", Version: "1"}, errBody{}, []Route{
+		{ID: "create", Method: http.MethodPost, Path: "/items", Body: sampleReq{}, Status: http.StatusCreated, Response: limits{}},
+		{ID: "get", Method: http.MethodGet, Path: "/items/{id}", Response: limits{}},
+		{ID: "file", Method: http.MethodGet, Path: "/files/*", Content: "*/*"},
+	})
+}
+
+func TestValidateJSON(t *testing.T) {
+	doc := testDoc()
+	s := doc.Paths["/items"]["post"].RequestBody.Content[jsonType].Schema
+
+	valid := `{"email":"a@example.com","items":["x"],"when":"2026-05-01T10:00:00Z","role":null,"meta":{"a":1},"limits":{"capacity":2}}`
+	if err := doc.ValidateJSON(s, []byte(valid)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	invalid := `{"email":"nope","items":[],"when":"вчера","role":"ADMIN","count":0,"extra":true,"limits":{"capacity":"2"}}`
+	err := doc.ValidateJSON(s, []byte(invalid))
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	for _, want := range []string{"$.email", "$.items", "$.when", "$.role", "$.count", "поле extra", "$.limits.capacity"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
+
+func TestValidator_ReportsMismatches(t *testing.T) {
+	var reports []string
+	r := chi.NewRouter()
+	r.Use(Validator(testDoc(), func(_ *http.Request, err error) { reports = append(reports, err.Error()) }))
+	r.Post("/items", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"capacity":1}`))
+	})
+	r.Get("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if chi.URLParam(r, "id") == "404" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"NOT_FOUND"}}`))
+			return
+		}
+		w.Write([]byte(`{"capacity":"many"}`))
+	})
+	r.Get("/files/*", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte{0x89, 'P', 'N', 'G'})
+	})
+	r.Get("/undocumented", func(w http.ResponseWriter, r *http.Request) {})
+
+	send := func(method, path, body string) []string {
+		reports = nil
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, strings.NewReader(body)))
+		return reports
+	}
+
+	if got := send(http.MethodPost, "/items", `{"email":"a@example.com","items":["x"],"when":"2026-05-01T10:00:00Z"}`); len(got) != 0 {
+		t.Fatalf("valid exchange reported: %v", got)
+	}
+	if got := send(http.MethodGet, "/items/404", ""); len(got) != 0 {
+		t.Fatalf("documented error reported: %v", got)
+	}
+	if got := send(http.MethodGet, "/files/a/b.png", ""); len(got) != 0 {
+		t.Fatalf("wildcard content reported: %v", got)
+	}
+	if got := send(http.MethodGet, "/nope", ""); len(got) != 0 {
+		t.Fatalf("unrouted request reported: %v", got)
+	}
+
+	if got := send(http.MethodPost, "/items", `{"email":"a@example.com"}`); len(got) != 1 || !strings.Contains(got[0], "запрос:") {
+		t.Fatalf("accepted invalid request not reported: %v", got)
+	}
+	if got := send(http.MethodGet, "/items/1", ""); len(got) != 1 || !strings.Contains(got[0], "$.capacity") {
+		t.Fatalf("invalid response not reported: %v", got)
+	}
+	if got := send(http.MethodGet, "/undocumented", ""); len(got) != 1 || !strings.Contains(got[0], "нет в спецификации") {
+		t.Fatalf("undocumented route not reported: %v", got)
+	}
+}
+
+func TestBuild_DuplicateOperationPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	Build(Info{}, errBody{}, []Route{
+		{ID: "a", Method: http.MethodGet, Path: "/x/"},
+		{ID: "b", Method: http.MethodGet, Path: "/x"},
+	})
+}
@@ -0,0 +1,256 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema — подмножество JSON Schema 2020-12, которым OpenAPI 3.1 описывает тела запросов и ответов
+type Schema struct {
+	Ref string `json:"$ref,omitempty"`
+	// Type — один тип или несколько, например ["string", "null"] для необязательного значения
+	Type        []string           `json:"-"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Enum        []any              `json:"enum,omitempty"`
+	AnyOf       []*Schema          `json:"anyOf,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	// AdditionalProperties — false (других полей быть не может) или *Schema значений словаря
+	AdditionalProperties any      `json:"additionalProperties,omitempty"`
+	Items                *Schema  `json:"items,omitempty"`
+	MinLength            *int     `json:"minLength,omitempty"`
+	MaxLength            *int     `json:"maxLength,omitempty"`
+	Minimum              *float64 `json:"minimum,omitempty"`
+	Maximum              *float64 `json:"maximum,omitempty"`
+	MinItems             *int     `json:"minItems,omitempty"`
+	MaxItems             *int     `json:"maxItems,omitempty"`
+	ContentMediaType     string   `json:"contentMediaType,omitempty"`
+}
+
+// MarshalJSON пишет type строкой, если тип один, и массивом, если их несколько
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema
+	out := struct {
+		Type any `json:"type,omitempty"`
+		*plain
+	}{plain: (*plain)(s)}
+	switch len(s.Type) {
+	case 0:
+	case 1:
+		out.Type = s.Type[0]
+	default:
+		out.Type = s.Type
+	}
+	return json.Marshal(out)
+}
+
+const refPrefix = "#/components/schemas/"
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+// generator строит схемы по типам Go. Именованные структуры попадают в components.schemas
+// и подключаются через $ref, поэтому рекурсивные типы (дерево категорий) не зацикливаются.
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newGenerator() *generator {
+	return &generator{schemas: map[string]*Schema{}, names: map[reflect.Type]string{}}
+}
+
+// schemaOf — схема значения типа t в том виде, в каком его пишет encoding/json
+func (g *generator) schemaOf(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: []string{"string"}, Format: "date-time"}
+	case rawType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(g.schemaOf(t.Elem()))
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Bool:
+		return &Schema{Type: []string{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: []string{"integer"}}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := 0.0
+		return &Schema{Type: []string{"integer"}, Minimum: &zero}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: []string{"number"}}
+	case reflect.String:
+		return &Schema{Type: []string{"string"}}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: []string{"string"}, Format: "byte"}
+		}
+		// nil-срез encoding/json пишет как null
+		return &Schema{Type: []string{"array", "null"}, Items: g.schemaOf(t.Elem())}
+	case reflect.Array:
+		return &Schema{Type: []string{"array"}, Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: []string{"object", "null"}, AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return &Schema{Ref: refPrefix + g.define(t)}
+	}
+	panic(fmt.Sprintf("openapi: тип %s не поддерживается", t))
+}
+
+// define регистрирует именованную структуру в components и возвращает имя схемы. При совпадении
+// имён из разных пакетов второе получает префикс пакета: reporting.Report.
+func (g *generator) define(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if _, taken := g.schemas[name]; taken {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+	g.names[t] = name
+	g.schemas[name] = nil // заглушка на время обхода полей: рекурсивная ссылка вернёт имя
+	g.schemas[name] = g.structSchema(t)
+	return name
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: []string{"object"}, Properties: map[string]*Schema{}, AdditionalProperties: false}
+	g.addFields(s, t)
+	return s
+}
+
+// addFields переносит поля структуры в схему по правилам encoding/json: встроенные структуры
+// без имени в теге раскрываются, поля с json:"-" пропускаются
+func (g *generator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(s, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fs := g.schemaOf(f.Type)
+		if slices.Contains(strings.Split(opts, ","), "string") {
+			fs = &Schema{Type: []string{"string"}}
+		}
+		if tag, ok := f.Tag.Lookup("validate"); ok && applyRules(fs, f.Type, tag) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = fs
+	}
+}
+
+// applyRules переносит правила тега validate в ограничения схемы и сообщает, обязательно ли поле.
+// Схемы полей создаются заново для каждого поля, поэтому менять их можно.
+func applyRules(s *Schema, t reflect.Type, tag string) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	required := false
+	for _, part := range strings.Split(tag, ",") {
+		rule, param, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch rule {
+		case "required":
+			required = true
+			switch t.Kind() {
+			case reflect.String:
+				s.MinLength = maxInt(s.MinLength, 1)
+			case reflect.Slice, reflect.Array:
+				s.MinItems = maxInt(s.MinItems, 1)
+			}
+		case "min", "max":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				panic(fmt.Sprintf("openapi: некорректный параметр %s=%q", rule, param))
+			}
+			setLimit(s, t, rule, n)
+		case "email":
+			s.Format = "email"
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				s.Enum = append(s.Enum, v)
+			}
+			if slices.Contains(s.Type, "null") {
+				s.Enum = append(s.Enum, nil)
+			}
+		}
+	}
+	return required
+}
+
+func setLimit(s *Schema, t reflect.Type, rule string, n float64) {
+	switch t.Kind() {
+	case reflect.String:
+		if rule == "min" {
+			s.MinLength = maxInt(s.MinLength, int(n))
+		} else {
+			s.MaxLength = ptr(int(n))
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if rule == "min" {
+			s.MinItems = maxInt(s.MinItems, int(n))
+		} else {
+			s.MaxItems = ptr(int(n))
+		}
+	default:
+		if rule == "min" {
+			s.Minimum = ptr(n)
+		} else {
+			s.Maximum = ptr(n)
+		}
+	}
+}
+
+// nullable разрешает значению быть null: указатели и пустые необязательные поля
+func nullable(s *Schema) *Schema {
+	if s.Ref != "" {
+		return &Schema{AnyOf: []*Schema{s, {Type: []string{"null"}}}}
+	}
+	if len(s.Type) > 0 && !slices.Contains(s.Type, "null") {
+		s.Type = append(s.Type, "null")
+	}
+	return s
+}
+
+func maxInt(cur *int, n int) *int {
+	if cur != nil && *cur >= n {
+		return cur
+	}
+	return &n
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,41 @@
+package openapi
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// DefaultSwaggerAssets — сборка swagger-ui-dist с CDN; версия закреплена, чтобы страница не менялась сама
+const DefaultSwaggerAssets = "https://cdn.jsdelivr.net/npm/swagger-ui-dist@5.17.14"
+
+var swaggerPage = template.Must(template.New("swagger").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>{{.Title}}</title>
+  <link rel="stylesheet" href="{{.Assets}}/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="{{.Assets}}/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: {{.SpecURL}}, dom_id: "#swagger-ui", deepLinking: true });
+  </script>
+</body>
+</html>
+`))
+
+// SwaggerUI — страница Swagger UI для документа по адресу specURL. assets — каталог с файлами
+// swagger-ui-dist (swagger-ui.css, swagger-ui-bundle.js); пусто — DefaultSwaggerAssets.
+func SwaggerUI(title, specURL, assets string) http.Handler {
+	if assets == "" {
+		assets = DefaultSwaggerAssets
+	}
+	data := struct{ Title, SpecURL, Assets string }{title, specURL, strings.TrimSuffix(assets, "/")}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = swaggerPage.Execute(w, data)
+	})
+}
@@ -0,0 +1,139 @@
+// Package policy — единая точка проверки прав: Can(actor, action, target).
+//
+// Права складываются из двух источников:
+//   - глобальные права роли (таблица role_permissions, например ADMIN -> booking:approve);
+//   - права "по отношению" к объекту: владелец объявления, OWNER/MANAGER его организации,
+//     автор брони. Эти правила зашиты в код и от матрицы ролей не зависят.
+package policy
+
+import (
+	"context"
+	"sync"
+
+	"bookinghub-backend/internal/domain"
+)
+
+// Actor — кто выполняет действие
+type Actor struct {
+	UserID uint64
+	Role   domain.UserRole
+}
+
+// Target — факты об объекте действия, которые нужны для проверки.
+// Незаполненные поля просто не дают прав "по отношению".
+type Target struct {
+	OwnerUserID  uint64         // владелец объявления
+	OrgRole      domain.OrgRole // роль actor'а в организации объявления
+	BookerUserID uint64         // автор брони
+}
+
+// Store — источник матрицы ролей и прав
+type Store interface {
+	ListRolePermissions(ctx context.Context) ([]domain.RolePermission, error)
+}
+
+type Policy struct {
+	store Store
+
+	mu     sync.RWMutex
+	grants map[domain.UserRole]map[domain.Permission]struct{}
+}
+
+// DefaultGrants — матрица по умолчанию, совпадает с сидами миграций 0010–0012
+func DefaultGrants() []domain.RolePermission {
+	return []domain.RolePermission{
+		{Role: domain.RoleAdmin, Permission: domain.PermBookingApprove},
+		{Role: domain.RoleAdmin, Permission: domain.PermBookingViewAll},
+		{Role: domain.RoleAdmin, Permission: domain.PermResourceEdit},
+		{Role: domain.RoleAdmin, Permission: domain.PermCategoryManage},
+		{Role: domain.RoleAdmin, Permission: domain.PermOrgCreate},
+		{Role: domain.RoleAdmin, Permission: domain.PermPermissionManage},
+		{Role: domain.RoleAdmin, Permission: domain.PermUserManage},
+		{Role: domain.RoleAdmin, Permission: domain.PermReportViewAll},
+		{Role: domain.RoleCompany, Permission: domain.PermOrgCreate},
+	}
+}
+
+// New создаёт политику с матрицей по умолчанию; актуальную матрицу из store подтягивает Reload.
+// store может быть nil — тогда политика всегда работает на матрице по умолчанию.
+func New(store Store) *Policy {
+	p := &Policy{store: store}
+	p.set(DefaultGrants())
+	return p
+}
+
+// Default — политика без хранилища (тесты, fallback)
+func Default() *Policy {
+	return New(nil)
+}
+
+// Reload перечитывает матрицу из хранилища
+func (p *Policy) Reload(ctx context.Context) error {
+	if p.store == nil {
+		return nil
+	}
+	items, err := p.store.ListRolePermissions(ctx)
+	if err != nil {
+		return err
+	}
+	p.set(items)
+	return nil
+}
+
+func (p *Policy) set(items []domain.RolePermission) {
+	grants := make(map[domain.UserRole]map[domain.Permission]struct{})
+	for _, it := range items {
+		if grants[it.Role] == nil {
+			grants[it.Role] = make(map[domain.Permission]struct{})
+		}
+		grants[it.Role][it.Permission] = struct{}{}
+	}
+
+	p.mu.Lock()
+	p.grants = grants
+	p.mu.Unlock()
+}
+
+// HasRolePermission — есть ли у роли глобальное право
+func (p *Policy) HasRolePermission(role domain.UserRole, perm domain.Permission) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, ok := p.grants[role][perm]
+	return ok
+}
+
+// Grants — текущая матрица в виде role -> []permission
+func (p *Policy) Grants() map[domain.UserRole][]domain.Permission {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	out := make(map[domain.UserRole][]domain.Permission, len(domain.AllRoles))
+	for _, role := range domain.AllRoles {
+		perms := make([]domain.Permission, 0)
+		for _, perm := range domain.AllPermissions {
+			if _, ok := p.grants[role][perm]; ok {
+				perms = append(perms, perm)
+			}
+		}
+		out[role] = perms
+	}
+	return out
+}
+
+// Can — может ли actor выполнить action над target
+func (p *Policy) Can(actor Actor, action domain.Permission, t Target) bool {
+	if actor.UserID == 0 {
+		return false
+	}
+	if p.HasRolePermission(actor.Role, action) {
+		return true
+	}
+
+	switch action {
+	case domain.PermBookingApprove, domain.PermResourceEdit:
+		return (t.OwnerUserID != 0 && t.OwnerUserID == actor.UserID) || t.OrgRole.CanManage()
+	case domain.PermBookingCancel:
+		return t.BookerUserID != 0 && t.BookerUserID == actor.UserID
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"bookinghub-backend/internal/domain"
+)
+
+type fakeStore struct {
+	items []domain.RolePermission
+	err   error
+}
+
+func (f *fakeStore) ListRolePermissions(ctx context.Context) ([]domain.RolePermission, error) {
+	return f.items, f.err
+}
+
+func TestPolicy_Can_DefaultMatrix(t *testing.T) {
+	p := Default()
+
+	admin := Actor{UserID: 1, Role: domain.RoleAdmin}
+	company := Actor{UserID: 2, Role: domain.RoleCompany}
+	user := Actor{UserID: 3, Role: domain.RoleIndividual}
+	anon := Actor{}
+
+	cases := []struct {
+		name   string
+		actor  Actor
+		action domain.Permission
+		target Target
+		want   bool
+	}{
+		// категории — только админ
+		{"admin manages categories", admin, domain.PermCategoryManage, Target{}, true},
+		{"company cannot manage categories", company, domain.PermCategoryManage, Target{}, false},
+		{"user cannot manage categories", user, domain.PermCategoryManage, Target{}, false},
+
+		// подтверждение брони: админ, владелец объявления, OWNER/MANAGER организации
+		{"admin approves any booking", admin, domain.PermBookingApprove, Target{OwnerUserID: 99}, true},
+		{"owner approves own resource", user, domain.PermBookingApprove, Target{OwnerUserID: 3}, true},
+		{"stranger cannot approve", user, domain.PermBookingApprove, Target{OwnerUserID: 99}, false},
+		{"org manager approves", company, domain.PermBookingApprove, Target{OwnerUserID: 99, OrgRole: domain.OrgRoleManager}, true},
+		{"org owner approves", company, domain.PermBookingApprove, Target{OwnerUserID: 99, OrgRole: domain.OrgRoleOwner}, true},
+		{"org viewer cannot approve", company, domain.PermBookingApprove, Target{OwnerUserID: 99, OrgRole: domain.OrgRoleViewer}, false},
+
+		// редактирование объявления — те же правила
+		{"admin edits any resource", admin, domain.PermResourceEdit, Target{OwnerUserID: 99}, true},
+		{"owner edits resource", company, domain.PermResourceEdit, Target{OwnerUserID: 2}, true},
+		{"stranger cannot edit", user, domain.PermResourceEdit, Target{OwnerUserID: 99}, false},
+
+		// отмена брони — только автор брони (админ тоже нет, как было)
+		{"booker cancels", user, domain.PermBookingCancel, Target{BookerUserID: 3}, true},
+		{"admin cannot cancel others", admin, domain.PermBookingCancel, Target{BookerUserID: 3}, false},
+		{"owner cannot cancel renter booking", company, domain.PermBookingCancel, Target{OwnerUserID: 2, BookerUserID: 3}, false},
+
+		// список всех ожидающих броней — только админ
+		{"admin views all pending", admin, domain.PermBookingViewAll, Target{}, true},
+		{"company views only own", company, domain.PermBookingViewAll, Target{}, false},
+
+		// организации создают компании и админ
+		{"company creates org", company, domain.PermOrgCreate, Target{}, true},
+		{"admin creates org", admin, domain.PermOrgCreate, Target{}, true},
+		{"individual cannot create org", user, domain.PermOrgCreate, Target{}, false},
+
+		{"admin manages permissions", admin, domain.PermPermissionManage, Target{}, true},
+		{"company cannot manage permissions", company, domain.PermPermissionManage, Target{}, false},
+
+		// без пользователя — ничего
+		{"anonymous cannot approve even with zero owner", anon, domain.PermBookingApprove, Target{}, false},
+		{"zero owner does not match", user, domain.PermBookingApprove, Target{}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := p.Can(tc.actor, tc.action, tc.target); got != tc.want {
+				t.Fatalf("Can(%+v, %s, %+v) = %v, want %v", tc.actor, tc.action, tc.target, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPolicy_Reload_FromStore(t *testing.T) {
+	store := &fakeStore{items: []domain.RolePermission{
+		{Role: domain.RoleCompany, Permission: domain.PermCategoryManage},
+	}}
+	p := New(store)
+
+	company := Actor{UserID: 2, Role: domain.RoleCompany}
+	if p.Can(company, domain.PermCategoryManage, Target{}) {
+		t.Fatalf("default matrix must not grant category:manage to COMPANY")
+	}
+
+	if err := p.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if !p.Can(company, domain.PermCategoryManage, Target{}) {
+		t.Fatalf("expected grant from store")
+	}
+	if p.Can(Actor{UserID: 1, Role: domain.RoleAdmin}, domain.PermCategoryManage, Target{}) {
+		t.Fatalf("store matrix replaces defaults entirely")
+	}
+}
+
+func TestPolicy_Reload_ErrorKeepsPrevious(t *testing.T) {
+	p := New(&fakeStore{err: errors.New("db down")})
+
+	if err := p.Reload(context.Background()); err == nil {
+		t.Fatalf("expected error")
+	}
+	if !p.Can(Actor{UserID: 1, Role: domain.RoleAdmin}, domain.PermCategoryManage, Target{}) {
+		t.Fatalf("defaults must survive failed reload")
+	}
+}
+
+func TestPolicy_Grants(t *testing.T) {
+	g := Default().Grants()
+	if len(g[domain.RoleIndividual]) != 0 {
+		t.Fatalf("INDIVIDUAL has no global grants by default, got %v", g[domain.RoleIndividual])
+	}
+	if len(g[domain.RoleCompany]) != 1 || g[domain.RoleCompany][0] != domain.PermOrgCreate {
+		t.Fatalf("unexpected COMPANY grants: %v", g[domain.RoleCompany])
+	}
+}
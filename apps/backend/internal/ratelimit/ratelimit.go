@@ -0,0 +1,111 @@
+// Package ratelimit — ограничение частоты запросов (token bucket) и прогрессивная блокировка
+// входа после неудачных попыток.
+//
+// Состояние лежит за интерфейсом Store. MemoryStore держит его в памяти процесса — этого
+// достаточно для одного экземпляра сервера. Несколько реплик должны делить состояние: Store
+// поверх Redis укладывается в те же операции (скрипт для Take, INCR/PEXPIRE для счётчика
+// неудач, SET PX для блокировки, DEL для сброса).
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit — ведро токенов: Rate токенов в секунду, не больше Burst сразу
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute — n запросов в минуту, все n можно сделать подряд
+func PerMinute(n int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: n}
+}
+
+// Store — состояние ограничителей. Каждая операция атомарна для своего ключа.
+type Store interface {
+	// Take забирает токен из ведра key. Токенов нет — ok=false и через сколько появится следующий.
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (ok bool, retryAfter time.Duration, err error)
+	// AddFailure увеличивает счётчик неудач key и возвращает его. Счётчик забывается,
+	// если ttl не было новых неудач.
+	AddFailure(ctx context.Context, key string, ttl time.Duration, now time.Time) (int, error)
+	// Lock блокирует key до until
+	Lock(ctx context.Context, key string, until time.Time) error
+	// LockedUntil — до какого момента key заблокирован; нулевое время — не заблокирован
+	LockedUntil(ctx context.Context, key string, now time.Time) (time.Time, error)
+	// Reset сбрасывает счётчик неудач и блокировку key
+	Reset(ctx context.Context, key string) error
+}
+
+// Значения Lockout по умолчанию
+const (
+	DefaultLockoutAfter  = 5
+	DefaultLockoutBase   = time.Minute
+	DefaultLockoutMax    = time.Hour
+	DefaultLockoutWindow = 24 * time.Hour
+)
+
+// Lockout — прогрессивная блокировка после неудачных попыток: с After-й неудачи подряд ключ
+// блокируется на Base, каждая следующая неудача удваивает срок, но не больше Max. Неудачи
+// забываются через Window без новых неудач или после успешной попытки.
+type Lockout struct {
+	store  Store
+	After  int
+	Base   time.Duration
+	Max    time.Duration
+	Window time.Duration
+}
+
+func NewLockout(store Store) *Lockout {
+	return &Lockout{store: store, After: DefaultLockoutAfter, Base: DefaultLockoutBase, Max: DefaultLockoutMax, Window: DefaultLockoutWindow}
+}
+
+// WithAfter задаёт, с какой неудачи подряд начинается блокировка
+func (l *Lockout) WithAfter(n int) *Lockout {
+	l.After = n
+	return l
+}
+
+// Check — на сколько ещё заблокирован key; 0 — не заблокирован
+func (l *Lockout) Check(ctx context.Context, key string, now time.Time) (time.Duration, error) {
+	until, err := l.store.LockedUntil(ctx, key, now)
+	if err != nil || until.IsZero() || !until.After(now) {
+		return 0, err
+	}
+	return until.Sub(now), nil
+}
+
+// Fail учитывает неудачную попытку и возвращает срок блокировки, если она началась; 0 — ещё нет
+func (l *Lockout) Fail(ctx context.Context, key string, now time.Time) (time.Duration, error) {
+	n, err := l.store.AddFailure(ctx, key, l.Window, now)
+	if err != nil || n < l.After {
+		return 0, err
+	}
+	d := l.duration(n)
+	return d, l.store.Lock(ctx, key, now.Add(d))
+}
+
+// Succeed — успешная попытка: неудачи и блокировка key сбрасываются
+func (l *Lockout) Succeed(ctx context.Context, key string) error {
+	return l.store.Reset(ctx, key)
+}
+
+// duration — срок блокировки после n-й неудачи подряд: Base, 2·Base, 4·Base, ... до Max
+func (l *Lockout) duration(n int) time.Duration {
+	shift := n - l.After
+	if shift > 30 {
+		return l.Max
+	}
+	d := l.Base << shift
+	if d <= 0 || d > l.Max {
+		return l.Max
+	}
+	return d
+}
+
+// RetryAfterSeconds — значение заголовка Retry-After: целые секунды с округлением вверх, не меньше 1
+func RetryAfterSeconds(d time.Duration) int {
+	return max(1, int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,120 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTake_BurstThenRefill(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	limit := PerMinute(3)
+
+	for i := 0; i < 3; i++ {
+		if ok, _, _ := s.Take(ctx, "k", limit, now); !ok {
+			t.Fatalf("request %d should pass", i+1)
+		}
+	}
+	ok, wait, _ := s.Take(ctx, "k", limit, now)
+	if ok || wait != 20*time.Second {
+		t.Fatalf("4th request: ok=%v wait=%v, want rejected with 20s", ok, wait)
+	}
+	if ok, _, _ := s.Take(ctx, "other", limit, now); !ok {
+		t.Fatal("other key has its own bucket")
+	}
+
+	// через 20 секунд появился ровно один токен
+	now = now.Add(20 * time.Second)
+	if ok, _, _ := s.Take(ctx, "k", limit, now); !ok {
+		t.Fatal("token should be refilled after 20s")
+	}
+	if ok, _, _ := s.Take(ctx, "k", limit, now); ok {
+		t.Fatal("only one token should be refilled")
+	}
+}
+
+func TestLockout_Progressive(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	l := NewLockout(s).WithAfter(3)
+
+	for i := 0; i < 2; i++ {
+		if d, _ := l.Fail(ctx, "a", now); d != 0 {
+			t.Fatalf("failure %d should not lock, got %v", i+1, d)
+		}
+	}
+	want := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute}
+	for i, w := range want {
+		d, _ := l.Fail(ctx, "a", now)
+		if d != w {
+			t.Fatalf("lock %d = %v, want %v", i+1, d, w)
+		}
+		if left, _ := l.Check(ctx, "a", now); left != w {
+			t.Fatalf("check after lock %d = %v, want %v", i+1, left, w)
+		}
+	}
+	if left, _ := l.Check(ctx, "a", now.Add(5*time.Minute)); left != 0 {
+		t.Fatalf("lock should expire, left %v", left)
+	}
+
+	if err := l.Succeed(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if left, _ := l.Check(ctx, "a", now); left != 0 {
+		t.Fatal("success should reset the lock")
+	}
+	if d, _ := l.Fail(ctx, "a", now); d != 0 {
+		t.Fatal("success should reset failures")
+	}
+}
+
+func TestLockout_CappedAndForgotten(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	l := NewLockout(s).WithAfter(1)
+
+	var d time.Duration
+	for i := 0; i < 40; i++ {
+		d, _ = l.Fail(ctx, "a", now)
+	}
+	if d != DefaultLockoutMax {
+		t.Fatalf("lock = %v, want cap %v", d, DefaultLockoutMax)
+	}
+
+	// после окна без неудач счёт начинается заново
+	later := now.Add(DefaultLockoutWindow + time.Second)
+	if d, _ := l.Fail(ctx, "a", later); d != DefaultLockoutBase {
+		t.Fatalf("lock after window = %v, want %v", d, DefaultLockoutBase)
+	}
+}
+
+func TestSweep_DropsIdleState(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	s.Take(ctx, "k", PerMinute(2), now)
+	s.AddFailure(ctx, "f", time.Minute, now)
+	s.Lock(ctx, "f", now.Add(time.Minute))
+
+	s.Sweep(now.Add(10 * time.Second))
+	if len(s.buckets) != 1 || len(s.failures) != 1 || len(s.locks) != 1 {
+		t.Fatalf("state swept too early: %d buckets, %d failures, %d locks", len(s.buckets), len(s.failures), len(s.locks))
+	}
+	s.Sweep(now.Add(time.Minute))
+	if len(s.buckets) != 0 || len(s.failures) != 0 || len(s.locks) != 0 {
+		t.Fatalf("idle state left: %d buckets, %d failures, %d locks", len(s.buckets), len(s.failures), len(s.locks))
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	for d, want := range map[time.Duration]int{0: 1, 300 * time.Millisecond: 1, 20 * time.Second: 20, 20*time.Second + time.Millisecond: 21} {
+		if got := RetryAfterSeconds(d); got != want {
+			t.Errorf("RetryAfterSeconds(%v) = %d, want %d", d, got, want)
+		}
+	}
+}
@@ -18,10 +18,51 @@ func NewBookingRepo(db *sqlx.DB) *BookingRepo {
 	return &BookingRepo{db: db}
 }
 
+// dbtx — общее у *sqlx.DB и *sqlx.Tx
+type dbtx interface {
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+type bookingTxKey struct{}
+
+// conn — транзакция из InTx, если метод вызван внутри неё, иначе пул соединений
+func (r *BookingRepo) conn(ctx context.Context) dbtx {
+	return bookingConn(ctx, r.db)
+}
+
+// bookingConn — транзакция BookingRepo.InTx из ctx, иначе db. Через неё в транзакцию брони
+// попадают записи других репозиториев (например, очереди ожидания).
+func bookingConn(ctx context.Context, db *sqlx.DB) dbtx {
+	if tx, ok := ctx.Value(bookingTxKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// InTx выполняет fn в одной транзакции: методы репозитория, вызванные с переданным в fn ctx,
+// работают внутри неё. Ошибка fn откатывает всё.
+func (r *BookingRepo) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(bookingTxKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(context.WithValue(ctx, bookingTxKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 func (r *BookingRepo) ListByUser(ctx context.Context, userID uint64) ([]domain.Booking, error) {
 	var items []domain.Booking
-	err := r.db.SelectContext(ctx, &items, `
-		SELECT id, resource_id, user_id, start_at, end_at, status, manager_comment, created_at, updated_at
+	err := r.conn(ctx).SelectContext(ctx, &items, `
+		SELECT id, resource_id, user_id, start_at, end_at, quantity, status, manager_comment, created_at, updated_at, group_id, bundle_id
 		FROM bookings
 		WHERE user_id = ?
 		ORDER BY start_at DESC
@@ -31,8 +72,8 @@ func (r *BookingRepo) ListByUser(ctx context.Context, userID uint64) ([]domain.B
 
 func (r *BookingRepo) ListPending(ctx context.Context) ([]domain.Booking, error) {
 	var items []domain.Booking
-	err := r.db.SelectContext(ctx, &items, `
-		SELECT id, resource_id, user_id, start_at, end_at, status, manager_comment, created_at, updated_at
+	err := r.conn(ctx).SelectContext(ctx, &items, `
+		SELECT id, resource_id, user_id, start_at, end_at, quantity, status, manager_comment, created_at, updated_at, group_id, bundle_id
 		FROM bookings
 		WHERE status = 'PENDING'
 		ORDER BY start_at ASC
@@ -40,46 +81,95 @@ func (r *BookingRepo) ListPending(ctx context.Context) ([]domain.Booking, error)
 	return items, err
 }
 
-func (r *BookingRepo) Create(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time) (uint64, error) {
-	res, err := r.db.ExecContext(ctx, `
-		INSERT INTO bookings (resource_id, user_id, start_at, end_at, status)
-		VALUES (?, ?, ?, ?, 'PENDING')
-	`, resourceID, userID, startAt, endAt)
-	if err != nil {
-		return 0, err
-	}
+// Create добавляет PENDING-бронь и первую запись её истории статусов (автор — userID)
+func (r *BookingRepo) Create(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time, quantity int) (uint64, error) {
+	var id uint64
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		res, err := r.conn(ctx).ExecContext(ctx, `
+			INSERT INTO bookings (resource_id, user_id, start_at, end_at, quantity, status)
+			VALUES (?, ?, ?, ?, ?, 'PENDING')
+		`, resourceID, userID, startAt, endAt, quantity)
+		if err != nil {
+			return err
+		}
+		if id, err = insertedID(res); err != nil {
+			return err
+		}
+		return r.addCreated(ctx, id, userID)
+	})
+	return id, err
+}
+
+// addCreated — первая запись истории: бронь появилась в статусе PENDING
+func (r *BookingRepo) addCreated(ctx context.Context, bookingID, userID uint64) error {
+	return r.AddStatusHistory(ctx, domain.BookingStatusChange{
+		BookingID: bookingID,
+		ToStatus:  domain.BookingPending,
+		ChangedBy: &userID,
+	})
+}
+
+func insertedID(res sql.Result) (uint64, error) {
 	id, err := res.LastInsertId()
 	return uint64(id), err
 }
 
-func (r *BookingRepo) HasConflict(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error) {
-	// Пересечение: start < existing_end AND end > existing_start
-	// Считаем конфликтами PENDING и APPROVED
-	var cnt int
-	err := r.db.GetContext(ctx, &cnt, `
-		SELECT COUNT(*)
-		FROM bookings
-		WHERE resource_id = ?
-		  AND status IN ('PENDING','APPROVED')
-		  AND (? < end_at) AND (? > start_at)
-	`, resourceID, startAt, endAt)
+// BookingRules — вместимость и буферы ресурса; nil, если ресурса нет
+func (r *BookingRepo) BookingRules(ctx context.Context, resourceID uint64) (*domain.BookingRules, error) {
+	var rules domain.BookingRules
+	err := r.conn(ctx).GetContext(ctx, &rules, `
+		SELECT capacity, buffer_before_min, buffer_after_min
+		FROM resources
+		WHERE id = ?
+		LIMIT 1
+	`, resourceID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
 
-	return cnt > 0, err
+// ResourceTimezone — часовой пояс ресурса; "", если ресурса нет
+func (r *BookingRepo) ResourceTimezone(ctx context.Context, resourceID uint64) (string, error) {
+	var tz string
+	err := r.conn(ctx).GetContext(ctx, &tz, `SELECT timezone FROM resources WHERE id = ? LIMIT 1`, resourceID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return tz, err
 }
 
-func (r *BookingRepo) UpdateStatus(ctx context.Context, id uint64, status domain.BookingStatus, managerComment *string) error {
-	_, err := r.db.ExecContext(ctx, `
-		UPDATE bookings
-		SET status = ?, manager_comment = ?
-		WHERE id = ?
-	`, status, managerComment, id)
-	return err
+// UpdateStatus — решение по брони: новый статус с комментарием и запись в историю
+// в одной транзакции. c.Comment сохраняется и как комментарий менеджера.
+// Статус меняется, только если бронь всё ещё в c.FromStatus; false — её успели отменить
+// или обработать, и ни статус, ни история не тронуты.
+func (r *BookingRepo) UpdateStatus(ctx context.Context, c domain.BookingStatusChange) (bool, error) {
+	updated := false
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		res, err := r.conn(ctx).ExecContext(ctx, `
+			UPDATE bookings
+			SET status = ?, manager_comment = ?
+			WHERE id = ? AND status = ?
+		`, c.ToStatus, c.Comment, c.BookingID, c.FromStatus)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+		updated = true
+		return r.AddStatusHistory(ctx, c)
+	})
+	return updated && err == nil, err
 }
 
 func (r *BookingRepo) GetByID(ctx context.Context, id uint64) (*domain.Booking, error) {
 	var b domain.Booking
-	err := r.db.GetContext(ctx, &b, `
-		SELECT id, resource_id, user_id, start_at, end_at, status, manager_comment, created_at, updated_at
+	err := r.conn(ctx).GetContext(ctx, &b, `
+		SELECT id, resource_id, user_id, start_at, end_at, quantity, status, manager_comment, created_at, updated_at, group_id, bundle_id
 		FROM bookings
 		WHERE id = ?
 		LIMIT 1
@@ -93,19 +183,29 @@ func (r *BookingRepo) GetByID(ctx context.Context, id uint64) (*domain.Booking,
 	return &b, nil
 }
 
-func (r *BookingRepo) Cancel(ctx context.Context, id uint64) error {
-	_, err := r.db.ExecContext(ctx, `
-		UPDATE bookings
-		SET status = 'CANCELED'
-		WHERE id = ?
-	`, id)
-	return err
+// Cancel отменяет бронь и пишет отмену в историю (from — статус до отмены, by — кто отменил)
+func (r *BookingRepo) Cancel(ctx context.Context, id uint64, from domain.BookingStatus, by uint64) error {
+	return r.InTx(ctx, func(ctx context.Context) error {
+		if _, err := r.conn(ctx).ExecContext(ctx, `
+			UPDATE bookings
+			SET status = 'CANCELED'
+			WHERE id = ?
+		`, id); err != nil {
+			return err
+		}
+		return r.AddStatusHistory(ctx, domain.BookingStatusChange{
+			BookingID:  id,
+			FromStatus: &from,
+			ToStatus:   domain.BookingCanceled,
+			ChangedBy:  &by,
+		})
+	})
 }
 
 func (r *BookingRepo) ListByResourceBetween(ctx context.Context, resourceID uint64, from, to time.Time) ([]domain.Booking, error) {
 	items := make([]domain.Booking, 0)
-	err := r.db.SelectContext(ctx, &items, `
-		SELECT id, resource_id, user_id, start_at, end_at, status, manager_comment, created_at, updated_at
+	err := r.conn(ctx).SelectContext(ctx, &items, `
+		SELECT id, resource_id, user_id, start_at, end_at, quantity, status, manager_comment, created_at, updated_at, group_id, bundle_id
 		FROM bookings
 		WHERE resource_id = ?
 		  AND status IN ('PENDING','APPROVED')
@@ -116,9 +216,29 @@ func (r *BookingRepo) ListByResourceBetween(ctx context.Context, resourceID uint
 	return items, err
 }
 
+// ListOverlapping — подтверждённые (и при includePending — ожидающие) брони ресурса,
+// пересекающиеся с [from, to)
+func (r *BookingRepo) ListOverlapping(ctx context.Context, resourceID uint64, from, to time.Time, includePending bool) ([]domain.Booking, error) {
+	statuses := "'APPROVED'"
+	if includePending {
+		statuses = "'PENDING','APPROVED'"
+	}
+
+	items := make([]domain.Booking, 0)
+	err := r.conn(ctx).SelectContext(ctx, &items, `
+		SELECT id, resource_id, user_id, start_at, end_at, quantity, status, manager_comment, created_at, updated_at, group_id, bundle_id
+		FROM bookings
+		WHERE resource_id = ?
+		  AND status IN (`+statuses+`)
+		  AND start_at < ? AND end_at > ?
+		ORDER BY start_at ASC
+	`, resourceID, to, from)
+	return items, err
+}
+
 func (r *BookingRepo) GetOwnerUserIDByBookingID(ctx context.Context, bookingID uint64) (uint64, error) {
 	var owner uint64
-	err := r.db.GetContext(ctx, &owner, `
+	err := r.conn(ctx).GetContext(ctx, &owner, `
 		SELECT r.owner_user_id
 		FROM bookings b
 		JOIN resources r ON r.id = b.resource_id
@@ -127,15 +247,345 @@ func (r *BookingRepo) GetOwnerUserIDByBookingID(ctx context.Context, bookingID u
 	return owner, err
 }
 
+// IsOrgManagerForBooking — является ли пользователь OWNER/MANAGER организации,
+// которой принадлежит ресурс брони
+func (r *BookingRepo) IsOrgManagerForBooking(ctx context.Context, bookingID, userID uint64) (bool, error) {
+	var cnt int
+	err := r.conn(ctx).GetContext(ctx, &cnt, `
+		SELECT COUNT(*)
+		FROM bookings b
+		JOIN resources r ON r.id = b.resource_id
+		JOIN organization_members m ON m.organization_id = r.organization_id
+		WHERE b.id = ?
+		  AND m.user_id = ?
+		  AND m.role IN ('OWNER','MANAGER')
+	`, bookingID, userID)
+	return cnt > 0, err
+}
+
+// ListPendingForOwner — ожидающие брони по личным ресурсам пользователя
+// и по ресурсам организаций, в которых он состоит
 func (r *BookingRepo) ListPendingForOwner(ctx context.Context, ownerUserID uint64) ([]domain.Booking, error) {
 	var items []domain.Booking
-	err := r.db.SelectContext(ctx, &items, `
-		SELECT b.id, b.resource_id, b.user_id, b.start_at, b.end_at, b.status, b.manager_comment, b.created_at, b.updated_at
+	err := r.conn(ctx).SelectContext(ctx, &items, `
+		SELECT b.id, b.resource_id, b.user_id, b.start_at, b.end_at, b.quantity, b.status, b.manager_comment, b.created_at, b.updated_at, b.group_id, b.bundle_id
 		FROM bookings b
 		JOIN resources r ON r.id = b.resource_id
 		WHERE b.status = 'PENDING'
-		  AND r.owner_user_id = ?
+		  AND (
+		    r.owner_user_id = ?
+		    OR r.organization_id IN (
+		      SELECT organization_id FROM organization_members WHERE user_id = ?
+		    )
+		  )
 		ORDER BY b.start_at ASC
-	`, ownerUserID)
+	`, ownerUserID, ownerUserID)
+	return items, err
+}
+
+// LockResources блокирует строки ресурсов до конца транзакции (по возрастанию id, чтобы
+// параллельные групповые брони не ждали друг друга по кругу). Возвращает найденные id.
+func (r *BookingRepo) LockResources(ctx context.Context, resourceIDs []uint64) ([]uint64, error) {
+	query, args, err := sqlx.In(`SELECT id FROM resources WHERE id IN (?) ORDER BY id FOR UPDATE`, resourceIDs)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint64
+	err = r.conn(ctx).SelectContext(ctx, &ids, r.db.Rebind(query), args...)
+	return ids, err
+}
+
+func (r *BookingRepo) CreateGroup(ctx context.Context, userID uint64) (uint64, error) {
+	res, err := r.conn(ctx).ExecContext(ctx, `INSERT INTO booking_groups (user_id) VALUES (?)`, userID)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	return uint64(id), err
+}
+
+// CreateGroupPart добавляет часть групповой брони, как Create — вместе с первой записью истории
+func (r *BookingRepo) CreateGroupPart(ctx context.Context, groupID, userID uint64, it domain.BookingItem) (uint64, error) {
+	var id uint64
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		res, err := r.conn(ctx).ExecContext(ctx, `
+			INSERT INTO bookings (resource_id, user_id, group_id, bundle_id, start_at, end_at, quantity, status)
+			VALUES (?, ?, ?, ?, ?, ?, ?, 'PENDING')
+		`, it.ResourceID, userID, groupID, it.BundleID, it.StartAt, it.EndAt, it.Quantity)
+		if err != nil {
+			return err
+		}
+		if id, err = insertedID(res); err != nil {
+			return err
+		}
+		return r.addCreated(ctx, id, userID)
+	})
+	return id, err
+}
+
+// GetGroup — групповая бронь со всеми частями; nil, если её нет
+func (r *BookingRepo) GetGroup(ctx context.Context, id uint64) (*domain.BookingGroup, error) {
+	var g domain.BookingGroup
+	err := r.conn(ctx).GetContext(ctx, &g, `
+		SELECT id, user_id, status, created_at, updated_at
+		FROM booking_groups
+		WHERE id = ?
+		LIMIT 1
+	`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	g.Bookings = make([]domain.Booking, 0)
+	err = r.conn(ctx).SelectContext(ctx, &g.Bookings, `
+		SELECT id, resource_id, user_id, start_at, end_at, quantity, status, manager_comment, created_at, updated_at, group_id, bundle_id
+		FROM bookings
+		WHERE group_id = ?
+		ORDER BY start_at ASC, id ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// SyncGroupStatus пересчитывает статус группы после решения по одной из частей.
+// Если часть отклонена, остальные активные части отменяются — группа бронируется только целиком;
+// отмена пишется в их историю как решение системы.
+func (r *BookingRepo) SyncGroupStatus(ctx context.Context, groupID uint64) (domain.BookingStatus, error) {
+	var status domain.BookingStatus
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		if err := r.conn(ctx).GetContext(ctx, &status, `
+			SELECT status FROM booking_groups WHERE id = ? FOR UPDATE
+		`, groupID); err != nil {
+			return err
+		}
+
+		var parts []domain.BookingStatus
+		if err := r.conn(ctx).SelectContext(ctx, &parts, `
+			SELECT status FROM bookings WHERE group_id = ? FOR UPDATE
+		`, groupID); err != nil {
+			return err
+		}
+
+		status = domain.GroupStatus(parts)
+		if status == domain.BookingRejected {
+			if err := r.cancelGroupParts(ctx, groupID, nil, "Отклонена другая часть групповой брони"); err != nil {
+				return err
+			}
+		}
+		_, err := r.conn(ctx).ExecContext(ctx, `UPDATE booking_groups SET status = ? WHERE id = ?`, status, groupID)
+		return err
+	})
+	return status, err
+}
+
+// CancelGroup отменяет все активные части группы и саму группу; by — кто отменил
+func (r *BookingRepo) CancelGroup(ctx context.Context, groupID, by uint64) error {
+	return r.InTx(ctx, func(ctx context.Context) error {
+		if err := r.cancelGroupParts(ctx, groupID, &by, "Отменена вместе с групповой бронью"); err != nil {
+			return err
+		}
+		_, err := r.conn(ctx).ExecContext(ctx, `UPDATE booking_groups SET status = 'CANCELED' WHERE id = ?`, groupID)
+		return err
+	})
+}
+
+// cancelGroupParts отменяет активные части группы. История пишется до UPDATE, пока
+// у частей ещё видны прежние статусы; by = nil — отменила система.
+func (r *BookingRepo) cancelGroupParts(ctx context.Context, groupID uint64, by *uint64, comment string) error {
+	if _, err := r.conn(ctx).ExecContext(ctx, `
+		INSERT INTO booking_status_history (booking_id, from_status, to_status, changed_by_user_id, comment)
+		SELECT id, status, 'CANCELED', ?, ?
+		FROM bookings
+		WHERE group_id = ? AND status IN ('PENDING','APPROVED')
+	`, by, comment, groupID); err != nil {
+		return err
+	}
+	_, err := r.conn(ctx).ExecContext(ctx, `
+		UPDATE bookings
+		SET status = 'CANCELED'
+		WHERE group_id = ? AND status IN ('PENDING','APPROVED')
+	`, groupID)
+	return err
+}
+
+// ApprovalRules — режим подтверждения броней ресурса; nil, если ресурса нет
+func (r *BookingRepo) ApprovalRules(ctx context.Context, resourceID uint64) (*domain.ApprovalRules, error) {
+	var rules domain.ApprovalRules
+	err := r.conn(ctx).GetContext(ctx, &rules, `
+		SELECT approval_mode, auto_approve_verified, auto_approve_returning, auto_approve_max_hours, auto_approve_business_hours
+		FROM resources
+		WHERE id = ?
+		LIMIT 1
+	`, resourceID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// RenterStats — проверен ли арендатор и сколько у него подтверждённых броней, закончившихся к now
+func (r *BookingRepo) RenterStats(ctx context.Context, userID uint64, now time.Time) (*domain.RenterStats, error) {
+	var st domain.RenterStats
+	err := r.conn(ctx).GetContext(ctx, &st, `
+		SELECT u.verified_at IS NOT NULL AS verified,
+		       (SELECT COUNT(*) FROM bookings b WHERE b.user_id = u.id AND b.status = 'APPROVED' AND b.end_at <= ?) AS completed_bookings
+		FROM users u
+		WHERE u.id = ?
+		LIMIT 1
+	`, now, userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// AutoApprove подтверждает ожидающую бронь от имени системы и пишет это в историю.
+// false — бронь уже не в PENDING (её успели отменить или обработать).
+func (r *BookingRepo) AutoApprove(ctx context.Context, bookingID uint64, comment string) (bool, error) {
+	approved := false
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		res, err := r.conn(ctx).ExecContext(ctx, `
+			UPDATE bookings
+			SET status = 'APPROVED'
+			WHERE id = ? AND status = 'PENDING'
+		`, bookingID)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+		approved = true
+		from := domain.BookingPending
+		return r.AddStatusHistory(ctx, domain.BookingStatusChange{
+			BookingID:  bookingID,
+			FromStatus: &from,
+			ToStatus:   domain.BookingApproved,
+			Comment:    &comment,
+		})
+	})
+	return approved && err == nil, err
+}
+
+// AddStatusHistory добавляет запись в историю статусов брони
+func (r *BookingRepo) AddStatusHistory(ctx context.Context, c domain.BookingStatusChange) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `
+		INSERT INTO booking_status_history (booking_id, from_status, to_status, changed_by_user_id, comment)
+		VALUES (?, ?, ?, ?, ?)
+	`, c.BookingID, c.FromStatus, c.ToStatus, c.ChangedBy, c.Comment)
+	return err
+}
+
+// ListStatusHistory — история статусов брони от старых записей к новым
+func (r *BookingRepo) ListStatusHistory(ctx context.Context, bookingID uint64) ([]domain.BookingStatusChange, error) {
+	items := make([]domain.BookingStatusChange, 0)
+	err := r.conn(ctx).SelectContext(ctx, &items, `
+		SELECT id, booking_id, from_status, to_status, changed_by_user_id, comment, created_at
+		FROM booking_status_history
+		WHERE booking_id = ?
+		ORDER BY id ASC
+	`, bookingID)
 	return items, err
 }
+
+const holdColumns = `id, token, resource_id, user_id, start_at, end_at, quantity, expires_at, created_at`
+
+// ListActiveHolds — неистёкшие удержания ресурса, пересекающиеся с [from, to)
+func (r *BookingRepo) ListActiveHolds(ctx context.Context, resourceID uint64, from, to, now time.Time) ([]domain.BookingHold, error) {
+	var items []domain.BookingHold
+	err := r.conn(ctx).SelectContext(ctx, &items, `
+		SELECT `+holdColumns+`
+		FROM booking_holds
+		WHERE resource_id = ? AND expires_at > ?
+		  AND start_at < ? AND end_at > ?
+		ORDER BY start_at ASC
+	`, resourceID, now, to, from)
+	return items, err
+}
+
+// ListActiveOffers — неистёкшие предложения из очереди на ресурс, пересекающиеся с [from, to)
+func (r *BookingRepo) ListActiveOffers(ctx context.Context, resourceID uint64, from, to, now time.Time) ([]domain.WaitlistEntry, error) {
+	var items []domain.WaitlistEntry
+	err := r.conn(ctx).SelectContext(ctx, &items, `
+		SELECT `+waitlistColumns+`
+		FROM waitlist_entries
+		WHERE resource_id = ? AND status = 'OFFERED' AND claim_expires_at > ?
+		  AND start_at < ? AND end_at > ?
+		ORDER BY start_at ASC
+	`, resourceID, now, to, from)
+	return items, err
+}
+
+// CountActiveHolds — сколько неистёкших удержаний у пользователя
+func (r *BookingRepo) CountActiveHolds(ctx context.Context, userID uint64, now time.Time) (int, error) {
+	var n int
+	err := r.conn(ctx).GetContext(ctx, &n, `
+		SELECT COUNT(*) FROM booking_holds WHERE user_id = ? AND expires_at > ?
+	`, userID, now)
+	return n, err
+}
+
+func (r *BookingRepo) CreateHold(ctx context.Context, h domain.BookingHold) (uint64, error) {
+	res, err := r.conn(ctx).ExecContext(ctx, `
+		INSERT INTO booking_holds (token, resource_id, user_id, start_at, end_at, quantity, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, h.Token, h.ResourceID, h.UserID, h.StartAt, h.EndAt, h.Quantity, h.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	return uint64(id), err
+}
+
+// GetHoldByToken — удержание по токену (внутри транзакции — с блокировкой строки); nil, если его нет
+func (r *BookingRepo) GetHoldByToken(ctx context.Context, token string) (*domain.BookingHold, error) {
+	var h domain.BookingHold
+	err := r.conn(ctx).GetContext(ctx, &h, `
+		SELECT `+holdColumns+`
+		FROM booking_holds
+		WHERE token = ?
+		LIMIT 1
+		FOR UPDATE
+	`, token)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+func (r *BookingRepo) DeleteHold(ctx context.Context, id uint64) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM booking_holds WHERE id = ?`, id)
+	return err
+}
+
+// PurgeExpiredHolds удаляет истёкшие удержания и возвращает ресурсы, на которых освободилось место
+func (r *BookingRepo) PurgeExpiredHolds(ctx context.Context, now time.Time) ([]uint64, error) {
+	var resourceIDs []uint64
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		if err := r.conn(ctx).SelectContext(ctx, &resourceIDs, `
+			SELECT DISTINCT resource_id FROM booking_holds WHERE expires_at <= ? FOR UPDATE
+		`, now); err != nil {
+			return err
+		}
+		if len(resourceIDs) == 0 {
+			return nil
+		}
+		_, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM booking_holds WHERE expires_at <= ?`, now)
+		return err
+	})
+	return resourceIDs, err
+}
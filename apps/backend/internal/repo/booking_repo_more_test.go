@@ -21,19 +21,50 @@ func newSQLXMockRepo3(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock, func()) {
 	return sqlxDB, mock, func() { _ = db.Close() }
 }
 
-func TestBookingRepo_UpdateStatus_OK(t *testing.T) {
+func TestBookingRepo_UpdateStatus_WritesHistory(t *testing.T) {
 	db, mock, closeFn := newSQLXMockRepo3(t)
 	defer closeFn()
 
 	r := NewBookingRepo(db)
 
 	comment := "ok"
-	mock.ExpectExec("UPDATE bookings\\s+SET status = \\?, manager_comment = \\?\\s+WHERE id = \\?").
-		WithArgs("APPROVED", &comment, uint64(10)).
+	by := uint64(3)
+	from := domain.BookingPending
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE bookings\\s+SET status = \\?, manager_comment = \\?\\s+WHERE id = \\? AND status = \\?").
+		WithArgs("APPROVED", &comment, uint64(10), "PENDING").
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO booking_status_history \\(booking_id, from_status, to_status, changed_by_user_id, comment\\)").
+		WithArgs(uint64(10), "PENDING", "APPROVED", uint64(3), "ok").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
-	if err := r.UpdateStatus(context.Background(), 10, domain.BookingApproved, &comment); err != nil {
-		t.Fatalf("unexpected err: %v", err)
+	c := domain.BookingStatusChange{BookingID: 10, FromStatus: &from, ToStatus: domain.BookingApproved, ChangedBy: &by, Comment: &comment}
+	if ok, err := r.UpdateStatus(context.Background(), c); err != nil || !ok {
+		t.Fatalf("expected update, got %v (%v)", ok, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestBookingRepo_UpdateStatus_StatusChangedMeanwhile(t *testing.T) {
+	db, mock, closeFn := newSQLXMockRepo3(t)
+	defer closeFn()
+
+	// бронь успели отменить: UPDATE не нашёл PENDING, истории нет
+	by := uint64(3)
+	from := domain.BookingPending
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE bookings\\s+SET status = \\?, manager_comment = \\?\\s+WHERE id = \\? AND status = \\?").
+		WithArgs("APPROVED", nil, uint64(10), "PENDING").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	c := domain.BookingStatusChange{BookingID: 10, FromStatus: &from, ToStatus: domain.BookingApproved, ChangedBy: &by}
+	ok, err := NewBookingRepo(db).UpdateStatus(context.Background(), c)
+	if err != nil || ok {
+		t.Fatalf("expected no update, got %v (%v)", ok, err)
 	}
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Fatalf("unmet expectations: %v", err)
@@ -73,3 +104,49 @@ func TestBookingRepo_ListByResourceBetween_OK(t *testing.T) {
 		t.Fatalf("unmet expectations: %v", err)
 	}
 }
+
+func TestBookingRepo_AutoApprove_WritesSystemHistory(t *testing.T) {
+	db, mock, closeFn := newSQLXMockRepo3(t)
+	defer closeFn()
+
+	r := NewBookingRepo(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE bookings\\s+SET status = 'APPROVED'\\s+WHERE id = \\? AND status = 'PENDING'").
+		WithArgs(uint64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO booking_status_history \\(booking_id, from_status, to_status, changed_by_user_id, comment\\)").
+		WithArgs(uint64(10), "PENDING", "APPROVED", nil, "Автоподтверждение: режим ресурса").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	ok, err := r.AutoApprove(context.Background(), 10, "Автоподтверждение: режим ресурса")
+	if err != nil || !ok {
+		t.Fatalf("expected approved, got ok=%v err=%v", ok, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestBookingRepo_AutoApprove_AlreadyHandled(t *testing.T) {
+	db, mock, closeFn := newSQLXMockRepo3(t)
+	defer closeFn()
+
+	r := NewBookingRepo(db)
+
+	// бронь успели отменить — история не пишется
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE bookings\\s+SET status = 'APPROVED'").
+		WithArgs(uint64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	ok, err := r.AutoApprove(context.Background(), 10, "x")
+	if err != nil || ok {
+		t.Fatalf("expected not approved, got ok=%v err=%v", ok, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
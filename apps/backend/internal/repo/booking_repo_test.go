@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+
+	"bookinghub-backend/internal/domain"
 )
 
 func TestBookingRepo_ListByUser(t *testing.T) {
@@ -27,7 +29,7 @@ func TestBookingRepo_ListByUser(t *testing.T) {
 	)
 
 	q := regexp.QuoteMeta(`
-		SELECT id, resource_id, user_id, start_at, end_at, status, manager_comment, created_at, updated_at
+		SELECT id, resource_id, user_id, start_at, end_at, quantity, status, manager_comment, created_at, updated_at, group_id, bundle_id
 		FROM bookings
 		WHERE user_id = ?
 		ORDER BY start_at DESC
@@ -63,7 +65,7 @@ func TestBookingRepo_ListPending(t *testing.T) {
 	}).AddRow(uint64(2), uint64(11), uint64(6), now, now.Add(time.Hour), "PENDING", nil, now, nil)
 
 	q := regexp.QuoteMeta(`
-		SELECT id, resource_id, user_id, start_at, end_at, status, manager_comment, created_at, updated_at
+		SELECT id, resource_id, user_id, start_at, end_at, quantity, status, manager_comment, created_at, updated_at, group_id, bundle_id
 		FROM bookings
 		WHERE status = 'PENDING'
 		ORDER BY start_at ASC
@@ -92,15 +94,21 @@ func TestBookingRepo_Create(t *testing.T) {
 	end := start.Add(time.Hour)
 
 	q := regexp.QuoteMeta(`
-		INSERT INTO bookings (resource_id, user_id, start_at, end_at, status)
-		VALUES (?, ?, ?, ?, 'PENDING')
+		INSERT INTO bookings (resource_id, user_id, start_at, end_at, quantity, status)
+		VALUES (?, ?, ?, ?, ?, 'PENDING')
 	`)
 
+	mock.ExpectBegin()
 	mock.ExpectExec(q).
-		WithArgs(uint64(7), uint64(9), start, end).
+		WithArgs(uint64(7), uint64(9), start, end, 2).
 		WillReturnResult(sqlmock.NewResult(123, 1))
+	// первая запись истории: бронь создана автором в PENDING
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO booking_status_history (booking_id, from_status, to_status, changed_by_user_id, comment)`)).
+		WithArgs(uint64(123), nil, "PENDING", uint64(9), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
-	id, err := r.Create(context.Background(), 7, 9, start, end)
+	id, err := r.Create(context.Background(), 7, 9, start, end, 2)
 	if err != nil {
 		t.Fatalf("Create err: %v", err)
 	}
@@ -113,32 +121,30 @@ func TestBookingRepo_Create(t *testing.T) {
 	}
 }
 
-func TestBookingRepo_HasConflict(t *testing.T) {
+func TestBookingRepo_BookingRules(t *testing.T) {
 	db, mock, cleanup := newMockDB(t)
 	defer cleanup()
 
 	r := NewBookingRepo(db)
-	start := time.Date(2025, 12, 29, 12, 0, 0, 0, time.UTC)
-	end := start.Add(time.Hour)
 
 	q := regexp.QuoteMeta(`
-		SELECT COUNT(*)
-		FROM bookings
-		WHERE resource_id = ?
-		  AND status IN ('PENDING','APPROVED')
-		  AND (? < end_at) AND (? > start_at)
+		SELECT capacity, buffer_before_min, buffer_after_min
+		FROM resources
+		WHERE id = ?
+		LIMIT 1
 	`)
+	mock.ExpectQuery(q).WithArgs(uint64(99)).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity", "buffer_before_min", "buffer_after_min"}).AddRow(10, 0, 30))
+	mock.ExpectQuery(q).WithArgs(uint64(100)).WillReturnError(sql.ErrNoRows)
 
-	// конфликт есть
-	rows := sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1)
-	mock.ExpectQuery(q).WithArgs(uint64(99), start, end).WillReturnRows(rows)
-
-	ok, err := r.HasConflict(context.Background(), 99, start, end)
-	if err != nil {
-		t.Fatalf("HasConflict err: %v", err)
+	rules, err := r.BookingRules(context.Background(), 99)
+	if err != nil || rules == nil || rules.Capacity != 10 || rules.BufferAfterMin != 30 {
+		t.Fatalf("unexpected rules: %+v err=%v", rules, err)
 	}
-	if !ok {
-		t.Fatalf("expected conflict=true")
+	// ресурса нет — nil без ошибки
+	rules, err = r.BookingRules(context.Background(), 100)
+	if err != nil || rules != nil {
+		t.Fatalf("expected nil, got %+v err=%v", rules, err)
 	}
 
 	if err := mock.ExpectationsWereMet(); err != nil {
@@ -153,7 +159,7 @@ func TestBookingRepo_GetByID_NotFound(t *testing.T) {
 	r := NewBookingRepo(db)
 
 	q := regexp.QuoteMeta(`
-		SELECT id, resource_id, user_id, start_at, end_at, status, manager_comment, created_at, updated_at
+		SELECT id, resource_id, user_id, start_at, end_at, quantity, status, manager_comment, created_at, updated_at, group_id, bundle_id
 		FROM bookings
 		WHERE id = ?
 		LIMIT 1
@@ -173,6 +179,28 @@ func TestBookingRepo_GetByID_NotFound(t *testing.T) {
 	}
 }
 
+func TestBookingRepo_ResourceTimezone(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewBookingRepo(db)
+
+	q := regexp.QuoteMeta(`SELECT timezone FROM resources WHERE id = ? LIMIT 1`)
+	mock.ExpectQuery(q).WithArgs(uint64(3)).WillReturnRows(sqlmock.NewRows([]string{"timezone"}).AddRow("Asia/Vladivostok"))
+	mock.ExpectQuery(q).WithArgs(uint64(4)).WillReturnError(sql.ErrNoRows)
+
+	if tz, err := r.ResourceTimezone(context.Background(), 3); err != nil || tz != "Asia/Vladivostok" {
+		t.Fatalf("ResourceTimezone = %q, %v", tz, err)
+	}
+	if tz, err := r.ResourceTimezone(context.Background(), 4); err != nil || tz != "" {
+		t.Fatalf("missing resource: expected empty zone, got %q, %v", tz, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
 func TestBookingRepo_Cancel(t *testing.T) {
 	db, mock, cleanup := newMockDB(t)
 	defer cleanup()
@@ -184,9 +212,14 @@ func TestBookingRepo_Cancel(t *testing.T) {
 		SET status = 'CANCELED'
 		WHERE id = ?
 	`)
+	mock.ExpectBegin()
 	mock.ExpectExec(q).WithArgs(uint64(55)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO booking_status_history`)).
+		WithArgs(uint64(55), "APPROVED", "CANCELED", uint64(7), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
-	err := r.Cancel(context.Background(), 55)
+	err := r.Cancel(context.Background(), 55, domain.BookingApproved, 7)
 	if err != nil {
 		t.Fatalf("Cancel err: %v", err)
 	}
@@ -232,11 +265,16 @@ func TestBookingRepo_ListPendingForOwner(t *testing.T) {
 	now := time.Date(2025, 12, 29, 12, 0, 0, 0, time.UTC)
 
 	q := regexp.QuoteMeta(`
-		SELECT b.id, b.resource_id, b.user_id, b.start_at, b.end_at, b.status, b.manager_comment, b.created_at, b.updated_at
+		SELECT b.id, b.resource_id, b.user_id, b.start_at, b.end_at, b.quantity, b.status, b.manager_comment, b.created_at, b.updated_at, b.group_id, b.bundle_id
 		FROM bookings b
 		JOIN resources r ON r.id = b.resource_id
 		WHERE b.status = 'PENDING'
-		  AND r.owner_user_id = ?
+		  AND (
+		    r.owner_user_id = ?
+		    OR r.organization_id IN (
+		      SELECT organization_id FROM organization_members WHERE user_id = ?
+		    )
+		  )
 		ORDER BY b.start_at ASC
 	`)
 
@@ -245,7 +283,7 @@ func TestBookingRepo_ListPendingForOwner(t *testing.T) {
 		"manager_comment", "created_at", "updated_at",
 	}).AddRow(uint64(1), uint64(10), uint64(3), now, now.Add(time.Hour), "PENDING", nil, now, nil)
 
-	mock.ExpectQuery(q).WithArgs(uint64(42)).WillReturnRows(rows)
+	mock.ExpectQuery(q).WithArgs(uint64(42), uint64(42)).WillReturnRows(rows)
 
 	items, err := r.ListPendingForOwner(context.Background(), 42)
 	if err != nil {
@@ -259,3 +297,205 @@ func TestBookingRepo_ListPendingForOwner(t *testing.T) {
 		t.Fatalf("expectations: %v", err)
 	}
 }
+
+func TestBookingRepo_IsOrgManagerForBooking(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewBookingRepo(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT COUNT(*)
+		FROM bookings b
+		JOIN resources r ON r.id = b.resource_id
+		JOIN organization_members m ON m.organization_id = r.organization_id
+		WHERE b.id = ?
+		  AND m.user_id = ?
+		  AND m.role IN ('OWNER','MANAGER')
+	`)).WithArgs(uint64(7), uint64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
+
+	ok, err := r.IsOrgManagerForBooking(context.Background(), 7, 3)
+	if err != nil {
+		t.Fatalf("IsOrgManagerForBooking err: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected manager access")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBookingRepo_ListOverlapping_Statuses(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewBookingRepo(db)
+	from := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	cols := []string{"id", "resource_id", "user_id", "start_at", "end_at", "status", "manager_comment", "created_at", "updated_at"}
+
+	mock.ExpectQuery("status IN \\('APPROVED'\\) AND start_at < \\? AND end_at > \\?").
+		WithArgs(uint64(3), to, from).
+		WillReturnRows(sqlmock.NewRows(cols))
+	if _, err := r.ListOverlapping(context.Background(), 3, from, to, false); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	mock.ExpectQuery("status IN \\('PENDING','APPROVED'\\) AND start_at < \\? AND end_at > \\?").
+		WithArgs(uint64(3), to, from).
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(uint64(1), uint64(3), uint64(2), from, from.Add(time.Hour), "PENDING", nil, from, nil))
+	items, err := r.ListOverlapping(context.Background(), 3, from, to, true)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item got %d", len(items))
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBookingRepo_SyncGroupStatus_RejectCancelsOtherParts(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewBookingRepo(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT status FROM booking_groups WHERE id = \? FOR UPDATE`).
+		WithArgs(uint64(40)).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("PENDING"))
+	mock.ExpectQuery(`SELECT status FROM bookings WHERE group_id = \? FOR UPDATE`).
+		WithArgs(uint64(40)).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("APPROVED").AddRow("REJECTED").AddRow("PENDING"))
+	// отмена остальных частей попадает в их историю как решение системы
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO booking_status_history (booking_id, from_status, to_status, changed_by_user_id, comment) SELECT id, status, 'CANCELED', ?, ? FROM bookings WHERE group_id = ? AND status IN ('PENDING','APPROVED')`)).
+		WithArgs(nil, "Отклонена другая часть групповой брони", uint64(40)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE bookings SET status = 'CANCELED' WHERE group_id = ? AND status IN ('PENDING','APPROVED')`)).
+		WithArgs(uint64(40)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE booking_groups SET status = ? WHERE id = ?`)).
+		WithArgs("REJECTED", uint64(40)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	status, err := r.SyncGroupStatus(context.Background(), 40)
+	if err != nil {
+		t.Fatalf("SyncGroupStatus err: %v", err)
+	}
+	if status != "REJECTED" {
+		t.Fatalf("expected REJECTED, got %s", status)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBookingRepo_SyncGroupStatus_AllApproved(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewBookingRepo(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT status FROM booking_groups`).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("PENDING"))
+	mock.ExpectQuery(`SELECT status FROM bookings WHERE group_id`).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("APPROVED").AddRow("APPROVED"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE booking_groups SET status = ? WHERE id = ?`)).
+		WithArgs("APPROVED", uint64(40)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if status, err := r.SyncGroupStatus(context.Background(), 40); err != nil || status != "APPROVED" {
+		t.Fatalf("expected APPROVED, got %s (%v)", status, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBookingRepo_CancelGroup_WritesHistory(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewBookingRepo(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO booking_status_history (booking_id, from_status, to_status, changed_by_user_id, comment) SELECT id, status, 'CANCELED', ?, ?`)).
+		WithArgs(uint64(7), "Отменена вместе с групповой бронью", uint64(40)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE bookings SET status = 'CANCELED' WHERE group_id = ? AND status IN ('PENDING','APPROVED')`)).
+		WithArgs(uint64(40)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE booking_groups SET status = 'CANCELED' WHERE id = ?`)).
+		WithArgs(uint64(40)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := r.CancelGroup(context.Background(), 40, 7); err != nil {
+		t.Fatalf("CancelGroup err: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBookingRepo_CreateGroupPart_WritesHistory(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewBookingRepo(db)
+	start := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+	bundleID := uint64(3)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO bookings (resource_id, user_id, group_id, bundle_id, start_at, end_at, quantity, status)`)).
+		WithArgs(uint64(5), uint64(9), uint64(40), &bundleID, start, start.Add(time.Hour), 2).
+		WillReturnResult(sqlmock.NewResult(101, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO booking_status_history`)).
+		WithArgs(uint64(101), nil, "PENDING", uint64(9), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	id, err := r.CreateGroupPart(context.Background(), 40, 9, domain.BookingItem{ResourceID: 5, BundleID: &bundleID, StartAt: start, EndAt: start.Add(time.Hour), Quantity: 2})
+	if err != nil || id != 101 {
+		t.Fatalf("CreateGroupPart: id=%d err=%v", id, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBookingRepo_PurgeExpiredHolds(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewBookingRepo(db)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT resource_id FROM booking_holds WHERE expires_at <= ? FOR UPDATE`)).
+		WithArgs(now).
+		WillReturnRows(sqlmock.NewRows([]string{"resource_id"}).AddRow(uint64(5)))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM booking_holds WHERE expires_at <= ?`)).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	ids, err := r.PurgeExpiredHolds(context.Background(), now)
+	if err != nil {
+		t.Fatalf("PurgeExpiredHolds err: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != 5 {
+		t.Fatalf("unexpected resources: %v", ids)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
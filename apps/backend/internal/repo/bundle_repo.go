@@ -0,0 +1,124 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"bookinghub-backend/internal/domain"
+)
+
+type BundleRepo struct {
+	db *sqlx.DB
+}
+
+func NewBundleRepo(db *sqlx.DB) *BundleRepo {
+	return &BundleRepo{db: db}
+}
+
+const bundleColumns = `id, owner_user_id, organization_id, title, description, is_active, created_at`
+
+// Create сохраняет набор вместе с составом
+func (r *BundleRepo) Create(ctx context.Context, b domain.ResourceBundle) (uint64, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO resource_bundles (owner_user_id, organization_id, title, description)
+		VALUES (?, ?, ?, ?)
+	`, b.OwnerUserID, b.OrganizationID, b.Title, b.Description)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, it := range b.Items {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO resource_bundle_items (bundle_id, resource_id, quantity)
+			VALUES (?, ?, ?)
+		`, id, it.ResourceID, it.Quantity); err != nil {
+			return 0, err
+		}
+	}
+	return uint64(id), tx.Commit()
+}
+
+// GetByID — набор с составом; nil, если его нет
+func (r *BundleRepo) GetByID(ctx context.Context, id uint64) (*domain.ResourceBundle, error) {
+	var b domain.ResourceBundle
+	err := r.db.GetContext(ctx, &b, `SELECT `+bundleColumns+` FROM resource_bundles WHERE id = ? LIMIT 1`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	items := []domain.ResourceBundle{b}
+	if err := r.attachItems(ctx, items); err != nil {
+		return nil, err
+	}
+	return &items[0], nil
+}
+
+// ListActive — активные наборы, новые сверху
+func (r *BundleRepo) ListActive(ctx context.Context) ([]domain.ResourceBundle, error) {
+	items := make([]domain.ResourceBundle, 0)
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT `+bundleColumns+`
+		FROM resource_bundles
+		WHERE is_active = TRUE
+		ORDER BY created_at DESC, id DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return items, r.attachItems(ctx, items)
+}
+
+func (r *BundleRepo) attachItems(ctx context.Context, bundles []domain.ResourceBundle) error {
+	if len(bundles) == 0 {
+		return nil
+	}
+	ids := make([]uint64, 0, len(bundles))
+	for _, b := range bundles {
+		ids = append(ids, b.ID)
+	}
+	query, args, err := sqlx.In(`
+		SELECT bundle_id, resource_id, quantity
+		FROM resource_bundle_items
+		WHERE bundle_id IN (?)
+		ORDER BY bundle_id, resource_id
+	`, ids)
+	if err != nil {
+		return err
+	}
+	var rows []domain.BundleItem
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return err
+	}
+
+	byBundle := map[uint64][]domain.BundleItem{}
+	for _, it := range rows {
+		byBundle[it.BundleID] = append(byBundle[it.BundleID], it)
+	}
+	for i := range bundles {
+		bundles[i].Items = byBundle[bundles[i].ID]
+		if bundles[i].Items == nil {
+			bundles[i].Items = make([]domain.BundleItem, 0)
+		}
+	}
+	return nil
+}
+
+func (r *BundleRepo) Delete(ctx context.Context, id uint64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM resource_bundles WHERE id = ?`, id)
+	return err
+}
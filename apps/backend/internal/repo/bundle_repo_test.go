@@ -0,0 +1,152 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"bookinghub-backend/internal/domain"
+)
+
+func bundleRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "owner_user_id", "organization_id", "title", "description", "is_active", "created_at"})
+}
+
+func TestBundleRepo_Create(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	orgID := uint64(3)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO resource_bundles (owner_user_id, organization_id, title, description) VALUES (?, ?, ?, ?)`)).
+		WithArgs(uint64(7), &orgID, "Зал со звуком", nil).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO resource_bundle_items (bundle_id, resource_id, quantity) VALUES (?, ?, ?)`)).
+		WithArgs(int64(9), uint64(1), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO resource_bundle_items (bundle_id, resource_id, quantity) VALUES (?, ?, ?)`)).
+		WithArgs(int64(9), uint64(2), 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	id, err := NewBundleRepo(db).Create(context.Background(), domain.ResourceBundle{
+		OwnerUserID:    7,
+		OrganizationID: &orgID,
+		Title:          "Зал со звуком",
+		Items:          []domain.BundleItem{{ResourceID: 1, Quantity: 1}, {ResourceID: 2, Quantity: 2}},
+	})
+	if err != nil {
+		t.Fatalf("Create err: %v", err)
+	}
+	if id != 9 {
+		t.Fatalf("expected id 9, got %d", id)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBundleRepo_Create_ItemFailsRollsBack(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO resource_bundles`).WillReturnResult(sqlmock.NewResult(9, 1))
+	mock.ExpectExec(`INSERT INTO resource_bundle_items`).WillReturnError(errors.New("fk"))
+	mock.ExpectRollback()
+
+	_, err := NewBundleRepo(db).Create(context.Background(), domain.ResourceBundle{
+		OwnerUserID: 7,
+		Title:       "Зал со звуком",
+		Items:       []domain.BundleItem{{ResourceID: 1, Quantity: 1}},
+	})
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBundleRepo_ListActive_AttachesItems(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM resource_bundles WHERE is_active = TRUE ORDER BY created_at DESC, id DESC`)).
+		WillReturnRows(bundleRows().
+			AddRow(uint64(2), uint64(7), nil, "Второй", nil, true, now).
+			AddRow(uint64(1), uint64(7), nil, "Первый", nil, true, now))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM resource_bundle_items WHERE bundle_id IN (?, ?)`)).
+		WithArgs(uint64(2), uint64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"bundle_id", "resource_id", "quantity"}).
+			AddRow(uint64(2), uint64(5), 1).
+			AddRow(uint64(2), uint64(6), 3))
+
+	items, err := NewBundleRepo(db).ListActive(context.Background())
+	if err != nil {
+		t.Fatalf("ListActive err: %v", err)
+	}
+	if len(items) != 2 || len(items[0].Items) != 2 || items[0].Items[1].Quantity != 3 {
+		t.Fatalf("unexpected bundles: %+v", items)
+	}
+	// набор без состава отдаётся с пустым, а не nil списком
+	if items[1].Items == nil || len(items[1].Items) != 0 {
+		t.Fatalf("expected empty items, got %+v", items[1].Items)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBundleRepo_ListActive_EmptySkipsItems(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`FROM resource_bundles`).WillReturnRows(bundleRows())
+
+	items, err := NewBundleRepo(db).ListActive(context.Background())
+	if err != nil || items == nil || len(items) != 0 {
+		t.Fatalf("expected empty list, got %v (%v)", items, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBundleRepo_GetByID_NotFound(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM resource_bundles WHERE id = ? LIMIT 1`)).
+		WithArgs(uint64(9)).
+		WillReturnRows(bundleRows())
+
+	b, err := NewBundleRepo(db).GetByID(context.Background(), 9)
+	if err != nil || b != nil {
+		t.Fatalf("expected nil bundle, got %+v (%v)", b, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBundleRepo_Delete(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM resource_bundles WHERE id = ?`)).
+		WithArgs(uint64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := NewBundleRepo(db).Delete(context.Background(), 9); err != nil {
+		t.Fatalf("Delete err: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -2,12 +2,19 @@ package repo
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
 	"bookinghub-backend/internal/domain"
 )
 
+var ErrCategoryNotFound = errors.New("категория не найдена")
+
+const categoryAttributeColumns = `id, category_id, code, name, type, required, options, position, created_at`
+
 type CategoryRepo struct {
 	db *sqlx.DB
 }
@@ -16,20 +23,21 @@ func NewCategoryRepo(db *sqlx.DB) *CategoryRepo {
 	return &CategoryRepo{db: db}
 }
 
+// List — все категории плоским списком; дерево и цепочки родителей строятся из него в service
 func (r *CategoryRepo) List(ctx context.Context) ([]domain.Category, error) {
 	var items []domain.Category
 	err := r.db.SelectContext(ctx, &items, `
-		SELECT id, name, created_at
+		SELECT id, parent_id, name, archived_at, created_at
 		FROM resource_categories
 		ORDER BY name ASC
 	`)
 	return items, err
 }
 
-func (r *CategoryRepo) Create(ctx context.Context, name string) (uint64, error) {
+func (r *CategoryRepo) Create(ctx context.Context, name string, parentID *uint64) (uint64, error) {
 	res, err := r.db.ExecContext(ctx, `
-		INSERT INTO resource_categories (name) VALUES (?)
-	`, name)
+		INSERT INTO resource_categories (name, parent_id) VALUES (?, ?)
+	`, name, parentID)
 	if err != nil {
 		return 0, err
 	}
@@ -46,7 +54,189 @@ func (r *CategoryRepo) Update(ctx context.Context, id uint64, name string) error
 	return err
 }
 
+// SetParent переносит категорию; nil — в корень. Проверка на циклы — на стороне вызывающего.
+func (r *CategoryRepo) SetParent(ctx context.Context, id uint64, parentID *uint64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE resource_categories
+		SET parent_id = ?
+		WHERE id = ?
+	`, parentID, id)
+	return err
+}
+
+// SetArchived архивирует категорию (at != nil) или возвращает её в каталог (nil)
+func (r *CategoryRepo) SetArchived(ctx context.Context, id uint64, at *time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE resource_categories
+		SET archived_at = ?
+		WHERE id = ?
+	`, at, id)
+	return err
+}
+
+// CountResources — сколько объявлений напрямую привязано к категории
+func (r *CategoryRepo) CountResources(ctx context.Context, id uint64) (int, error) {
+	var cnt int
+	err := r.db.GetContext(ctx, &cnt, `SELECT COUNT(*) FROM resources WHERE category_id = ?`, id)
+	return cnt, err
+}
+
+// CategoryMerge — параметры слияния категории SourceID в TargetID
+type CategoryMerge struct {
+	SourceID uint64
+	TargetID uint64
+	// AttrMap: атрибут ветки source -> атрибут ветки target с тем же кодом и типом, значения переезжают
+	AttrMap map[uint64]uint64
+	// DropAttrIDs — атрибуты ветки source, которым нет места в target: их значения удаляются
+	DropAttrIDs []uint64
+	// AffectedCategoryIDs — target и подкатегории source: где окажутся перенесённые объявления
+	AffectedCategoryIDs []uint64
+}
+
+// Merge в одной транзакции переносит объявления и подкатегории source в target, приводит значения
+// атрибутов перенесённых объявлений к схеме target и удаляет source
+func (r *CategoryRepo) Merge(ctx context.Context, m CategoryMerge) (domain.CategoryMergeResult, error) {
+	var out domain.CategoryMergeResult
+
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return out, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var locked []uint64
+	if err := tx.SelectContext(ctx, &locked, `
+		SELECT id FROM resource_categories WHERE id IN (?, ?) FOR UPDATE
+	`, m.SourceID, m.TargetID); err != nil {
+		return out, err
+	}
+	if len(locked) != 2 {
+		return out, ErrCategoryNotFound
+	}
+
+	res, err := tx.ExecContext(ctx, `UPDATE resources SET category_id = ? WHERE category_id = ?`, m.TargetID, m.SourceID)
+	if err != nil {
+		return out, err
+	}
+	if out.MovedResources, err = res.RowsAffected(); err != nil {
+		return out, err
+	}
+
+	res, err = tx.ExecContext(ctx, `UPDATE resource_categories SET parent_id = ? WHERE parent_id = ?`, m.TargetID, m.SourceID)
+	if err != nil {
+		return out, err
+	}
+	if out.MovedChildren, err = res.RowsAffected(); err != nil {
+		return out, err
+	}
+
+	for from, to := range m.AttrMap {
+		query, args, err := sqlx.In(`
+			UPDATE resource_attribute_values v
+			JOIN resources res ON res.id = v.resource_id
+			SET v.attribute_id = ?
+			WHERE v.attribute_id = ? AND res.category_id IN (?)
+		`, to, from, m.AffectedCategoryIDs)
+		if err != nil {
+			return out, err
+		}
+		if _, err := tx.ExecContext(ctx, tx.Rebind(query), args...); err != nil {
+			return out, err
+		}
+		out.RemappedAttributes++
+	}
+
+	if len(m.DropAttrIDs) > 0 {
+		query, args, err := sqlx.In(`
+			DELETE v FROM resource_attribute_values v
+			JOIN resources res ON res.id = v.resource_id
+			WHERE v.attribute_id IN (?) AND res.category_id IN (?)
+		`, m.DropAttrIDs, m.AffectedCategoryIDs)
+		if err != nil {
+			return out, err
+		}
+		if _, err := tx.ExecContext(ctx, tx.Rebind(query), args...); err != nil {
+			return out, err
+		}
+	}
+
+	// собственные атрибуты source удаляются каскадом
+	if _, err := tx.ExecContext(ctx, `DELETE FROM resource_categories WHERE id = ?`, m.SourceID); err != nil {
+		return out, err
+	}
+	return out, tx.Commit()
+}
+
 func (r *CategoryRepo) Delete(ctx context.Context, id uint64) error {
 	_, err := r.db.ExecContext(ctx, `DELETE FROM resource_categories WHERE id = ?`, id)
 	return err
 }
+
+// ListAttributes — атрибуты перечисленных категорий (обычно категория и её предки)
+func (r *CategoryRepo) ListAttributes(ctx context.Context, categoryIDs []uint64) ([]domain.CategoryAttribute, error) {
+	items := make([]domain.CategoryAttribute, 0)
+	if len(categoryIDs) == 0 {
+		return items, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT `+categoryAttributeColumns+`
+		FROM category_attributes
+		WHERE category_id IN (?)
+		ORDER BY position ASC, id ASC
+	`, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.db.SelectContext(ctx, &items, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *CategoryRepo) GetAttribute(ctx context.Context, categoryID, attributeID uint64) (*domain.CategoryAttribute, error) {
+	var a domain.CategoryAttribute
+	err := r.db.GetContext(ctx, &a, `
+		SELECT `+categoryAttributeColumns+`
+		FROM category_attributes
+		WHERE id = ? AND category_id = ?
+		LIMIT 1
+	`, attributeID, categoryID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *CategoryRepo) CreateAttribute(ctx context.Context, a *domain.CategoryAttribute) (uint64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO category_attributes (category_id, code, name, type, required, options, position)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, a.CategoryID, a.Code, a.Name, a.Type, a.Required, a.Options, a.Position)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	return uint64(id), err
+}
+
+// UpdateAttribute меняет всё, кроме кода и типа: от них зависят уже сохранённые значения
+func (r *CategoryRepo) UpdateAttribute(ctx context.Context, a *domain.CategoryAttribute) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE category_attributes
+		SET name = ?, required = ?, options = ?, position = ?
+		WHERE id = ? AND category_id = ?
+	`, a.Name, a.Required, a.Options, a.Position, a.ID, a.CategoryID)
+	return err
+}
+
+// DeleteAttribute удаляет атрибут вместе со значениями у объявлений (ON DELETE CASCADE)
+func (r *CategoryRepo) DeleteAttribute(ctx context.Context, categoryID, attributeID uint64) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM category_attributes WHERE id = ? AND category_id = ?
+	`, attributeID, categoryID)
+	return err
+}
@@ -26,9 +26,9 @@ func TestCategoryRepo_List_OK(t *testing.T) {
 	r := NewCategoryRepo(dbx)
 	now := time.Now()
 
-	mock.ExpectQuery("SELECT id, name, created_at FROM resource_categories ORDER BY name ASC").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at"}).
-			AddRow(uint64(1), "A", now))
+	mock.ExpectQuery("SELECT id, parent_id, name, archived_at, created_at FROM resource_categories ORDER BY name ASC").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id", "name", "archived_at", "created_at"}).
+			AddRow(uint64(1), nil, "A", nil, now))
 
 	_, err := r.List(context.Background())
 	if err != nil {
@@ -45,11 +45,11 @@ func TestCategoryRepo_Create_OK(t *testing.T) {
 
 	r := NewCategoryRepo(dbx)
 
-	mock.ExpectExec("INSERT INTO resource_categories \\(name\\) VALUES \\(\\?\\)").
-		WithArgs("X").
+	mock.ExpectExec("INSERT INTO resource_categories \\(name, parent_id\\) VALUES \\(\\?, \\?\\)").
+		WithArgs("X", nil).
 		WillReturnResult(sqlmock.NewResult(7, 1))
 
-	id, err := r.Create(context.Background(), "X")
+	id, err := r.Create(context.Background(), "X", nil)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -96,3 +96,28 @@ func TestCategoryRepo_Delete_Error(t *testing.T) {
 		t.Fatalf("expectations: %v", err)
 	}
 }
+
+func TestCategoryRepo_ListAttributes_ParsesOptions(t *testing.T) {
+	dbx, mock, cleanup := newRepoMock2(t)
+	defer cleanup()
+
+	r := NewCategoryRepo(dbx)
+	now := time.Now()
+
+	mock.ExpectQuery("FROM category_attributes WHERE category_id IN \\(\\?, \\?\\) ORDER BY position ASC, id ASC").
+		WithArgs(uint64(1), uint64(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "category_id", "code", "name", "type", "required", "options", "position", "created_at"}).
+			AddRow(uint64(1), uint64(1), "capacity", "Вместимость", "int", true, nil, 0, now).
+			AddRow(uint64(2), uint64(4), "mount", "Байонет", "enum", false, []byte(`["EF","RF"]`), 1, now))
+
+	items, err := r.ListAttributes(context.Background(), []uint64{1, 4})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(items) != 2 || len(items[0].Options) != 0 || len(items[1].Options) != 2 || items[1].Options[1] != "RF" {
+		t.Fatalf("unexpected attributes: %+v", items)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// IdentityRepo — привязки пользователей к учётным записям у внешних провайдеров входа
+type IdentityRepo struct {
+	db *sqlx.DB
+}
+
+func NewIdentityRepo(db *sqlx.DB) *IdentityRepo {
+	return &IdentityRepo{db: db}
+}
+
+// FindUserID — пользователь, привязанный к учётной записи subject у провайдера issuer; 0, если привязки нет
+func (r *IdentityRepo) FindUserID(ctx context.Context, issuer, subject string) (uint64, error) {
+	var id uint64
+	err := r.db.GetContext(ctx, &id, `
+		SELECT user_id
+		FROM user_identities
+		WHERE issuer = ? AND subject = ?
+	`, issuer, subject)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return id, err
+}
+
+// Link привязывает учётную запись к пользователю или, если она уже привязана,
+// обновляет email и время входа. Привязку к другому пользователю не переносит.
+func (r *IdentityRepo) Link(ctx context.Context, userID uint64, issuer, subject, email string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_identities (user_id, issuer, subject, email, last_login_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE email = VALUES(email), last_login_at = VALUES(last_login_at)
+	`, userID, issuer, subject, email, at)
+	return err
+}
@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestIdentityRepo_FindUserID(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	q := `SELECT user_id\s+FROM user_identities\s+WHERE issuer = \? AND subject = \?`
+	mock.ExpectQuery(q).WithArgs("https://id.corp", "u-1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(uint64(7)))
+	mock.ExpectQuery(q).WithArgs("https://id.corp", "u-2").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+	r := NewIdentityRepo(db)
+	if id, err := r.FindUserID(context.Background(), "https://id.corp", "u-1"); err != nil || id != 7 {
+		t.Fatalf("linked: %d, %v", id, err)
+	}
+	if id, err := r.FindUserID(context.Background(), "https://id.corp", "u-2"); err != nil || id != 0 {
+		t.Fatalf("not linked: %d, %v", id, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestIdentityRepo_Link(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	mock.ExpectExec(`INSERT INTO user_identities \(user_id, issuer, subject, email, last_login_at\)\s+VALUES \(\?, \?, \?, \?, \?\)\s+ON DUPLICATE KEY UPDATE email = VALUES\(email\), last_login_at = VALUES\(last_login_at\)`).
+		WithArgs(uint64(7), "https://id.corp", "u-1", "ann@corp.example", at).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	if err := NewIdentityRepo(db).Link(context.Background(), 7, "https://id.corp", "u-1", "ann@corp.example", at); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"bookinghub-backend/internal/domain"
+)
+
+type OrganizationRepo struct {
+	db *sqlx.DB
+}
+
+func NewOrganizationRepo(db *sqlx.DB) *OrganizationRepo {
+	return &OrganizationRepo{db: db}
+}
+
+// Create создаёт организацию и сразу делает создателя её владельцем (OWNER)
+func (r *OrganizationRepo) Create(ctx context.Context, name string, ownerUserID uint64) (uint64, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, `INSERT INTO organizations (name) VALUES (?)`, name)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO organization_members (organization_id, user_id, role)
+		VALUES (?, ?, 'OWNER')
+	`, id, ownerUserID); err != nil {
+		return 0, err
+	}
+
+	return uint64(id), tx.Commit()
+}
+
+func (r *OrganizationRepo) GetByID(ctx context.Context, id uint64) (*domain.Organization, error) {
+	var o domain.Organization
+	err := r.db.GetContext(ctx, &o, `
+		SELECT id, name, created_at
+		FROM organizations
+		WHERE id = ?
+		LIMIT 1
+	`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *OrganizationRepo) ListForUser(ctx context.Context, userID uint64) ([]domain.OrganizationWithRole, error) {
+	items := make([]domain.OrganizationWithRole, 0)
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT o.id, o.name, o.created_at, m.role AS my_role
+		FROM organizations o
+		JOIN organization_members m ON m.organization_id = o.id
+		WHERE m.user_id = ?
+		ORDER BY o.name ASC
+	`, userID)
+	return items, err
+}
+
+// GetMemberRole возвращает роль пользователя в организации или "" если он не участник
+func (r *OrganizationRepo) GetMemberRole(ctx context.Context, orgID, userID uint64) (domain.OrgRole, error) {
+	var role domain.OrgRole
+	err := r.db.GetContext(ctx, &role, `
+		SELECT role
+		FROM organization_members
+		WHERE organization_id = ? AND user_id = ?
+		LIMIT 1
+	`, orgID, userID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return role, err
+}
+
+func (r *OrganizationRepo) ListMembers(ctx context.Context, orgID uint64) ([]domain.OrgMember, error) {
+	items := make([]domain.OrgMember, 0)
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT m.organization_id, m.user_id, u.email, u.name, m.role, m.created_at
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.organization_id = ?
+		ORDER BY m.created_at ASC
+	`, orgID)
+	return items, err
+}
+
+func (r *OrganizationRepo) CountOwners(ctx context.Context, orgID uint64) (int, error) {
+	var cnt int
+	err := r.db.GetContext(ctx, &cnt, `
+		SELECT COUNT(*)
+		FROM organization_members
+		WHERE organization_id = ? AND role = 'OWNER'
+	`, orgID)
+	return cnt, err
+}
+
+func (r *OrganizationRepo) UpdateMemberRole(ctx context.Context, orgID, userID uint64, role domain.OrgRole) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE organization_members
+		SET role = ?
+		WHERE organization_id = ? AND user_id = ?
+	`, role, orgID, userID)
+	return err
+}
+
+func (r *OrganizationRepo) RemoveMember(ctx context.Context, orgID, userID uint64) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM organization_members
+		WHERE organization_id = ? AND user_id = ?
+	`, orgID, userID)
+	return err
+}
+
+func (r *OrganizationRepo) CreateInvite(ctx context.Context, orgID uint64, email string, role domain.OrgRole, token string, invitedBy uint64, expiresAt time.Time) (uint64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO organization_invites (organization_id, email, role, token, invited_by, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, orgID, email, role, token, invitedBy, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	return uint64(id), err
+}
+
+func (r *OrganizationRepo) ListInvites(ctx context.Context, orgID uint64) ([]domain.OrgInvite, error) {
+	items := make([]domain.OrgInvite, 0)
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT id, organization_id, email, role, token, invited_by, status, expires_at, created_at
+		FROM organization_invites
+		WHERE organization_id = ? AND status = 'PENDING'
+		ORDER BY created_at DESC
+	`, orgID)
+	return items, err
+}
+
+// ListInvitesByEmail — активные (не просроченные) приглашения на email пользователя
+func (r *OrganizationRepo) ListInvitesByEmail(ctx context.Context, email string, now time.Time) ([]domain.OrgInvite, error) {
+	items := make([]domain.OrgInvite, 0)
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT id, organization_id, email, role, token, invited_by, status, expires_at, created_at
+		FROM organization_invites
+		WHERE email = ? AND status = 'PENDING' AND expires_at > ?
+		ORDER BY created_at DESC
+	`, email, now)
+	return items, err
+}
+
+func (r *OrganizationRepo) GetInviteByToken(ctx context.Context, token string) (*domain.OrgInvite, error) {
+	var inv domain.OrgInvite
+	err := r.db.GetContext(ctx, &inv, `
+		SELECT id, organization_id, email, role, token, invited_by, status, expires_at, created_at
+		FROM organization_invites
+		WHERE token = ?
+		LIMIT 1
+	`, token)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// AcceptInvite добавляет пользователя в организацию и закрывает приглашение одной транзакцией.
+// Если пользователь уже участник — его роль обновляется на роль из приглашения.
+func (r *OrganizationRepo) AcceptInvite(ctx context.Context, inviteID, orgID, userID uint64, role domain.OrgRole) error {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO organization_members (organization_id, user_id, role)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE role = VALUES(role)
+	`, orgID, userID, role); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE organization_invites
+		SET status = 'ACCEPTED'
+		WHERE id = ?
+	`, inviteID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *OrganizationRepo) DeclineInvite(ctx context.Context, inviteID uint64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE organization_invites
+		SET status = 'DECLINED'
+		WHERE id = ?
+	`, inviteID)
+	return err
+}
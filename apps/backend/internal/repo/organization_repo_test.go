@@ -0,0 +1,117 @@
+package repo
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"bookinghub-backend/internal/domain"
+)
+
+func TestOrganizationRepo_Create_AddsOwner(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewOrganizationRepo(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO organizations (name) VALUES (?)`)).
+		WithArgs("ACME").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`
+		INSERT INTO organization_members (organization_id, user_id, role)
+		VALUES (?, ?, 'OWNER')
+	`)).
+		WithArgs(int64(3), uint64(8)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	id, err := r.Create(context.Background(), "ACME", 8)
+	if err != nil {
+		t.Fatalf("Create err: %v", err)
+	}
+	if id != 3 {
+		t.Fatalf("expected id=3 got %d", id)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestOrganizationRepo_GetMemberRole_NotMember(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewOrganizationRepo(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT role
+		FROM organization_members
+		WHERE organization_id = ? AND user_id = ?
+		LIMIT 1
+	`)).
+		WithArgs(uint64(1), uint64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}))
+
+	role, err := r.GetMemberRole(context.Background(), 1, 2)
+	if err != nil {
+		t.Fatalf("GetMemberRole err: %v", err)
+	}
+	if role != "" {
+		t.Fatalf("expected empty role, got %q", role)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestOrganizationRepo_AcceptInvite(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewOrganizationRepo(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO organization_members").
+		WithArgs(uint64(4), uint64(9), domain.OrgRoleManager).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE organization_invites\\s+SET status = 'ACCEPTED'").
+		WithArgs(uint64(12)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := r.AcceptInvite(context.Background(), 12, 4, 9, domain.OrgRoleManager); err != nil {
+		t.Fatalf("AcceptInvite err: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestOrganizationRepo_GetInviteByToken(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewOrganizationRepo(db)
+	now := time.Date(2025, 12, 29, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("FROM organization_invites\\s+WHERE token = \\?").
+		WithArgs("abc").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "organization_id", "email", "role", "token", "invited_by", "status", "expires_at", "created_at",
+		}).AddRow(uint64(1), uint64(2), "a@b.c", "VIEWER", "abc", uint64(5), "PENDING", now.Add(time.Hour), now))
+
+	inv, err := r.GetInviteByToken(context.Background(), "abc")
+	if err != nil {
+		t.Fatalf("GetInviteByToken err: %v", err)
+	}
+	if inv == nil || inv.OrganizationID != 2 || inv.Role != domain.OrgRoleViewer {
+		t.Fatalf("unexpected invite: %+v", inv)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"bookinghub-backend/internal/domain"
+)
+
+type PermissionRepo struct {
+	db *sqlx.DB
+}
+
+func NewPermissionRepo(db *sqlx.DB) *PermissionRepo {
+	return &PermissionRepo{db: db}
+}
+
+func (r *PermissionRepo) ListRolePermissions(ctx context.Context) ([]domain.RolePermission, error) {
+	items := make([]domain.RolePermission, 0)
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT role, permission
+		FROM role_permissions
+		ORDER BY role ASC, permission ASC
+	`)
+	return items, err
+}
+
+// ReplaceForRole полностью заменяет набор прав роли одной транзакцией
+func (r *PermissionRepo) ReplaceForRole(ctx context.Context, role domain.UserRole, perms []domain.Permission) error {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM role_permissions WHERE role = ?`, role); err != nil {
+		return err
+	}
+
+	for _, p := range perms {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO role_permissions (role, permission) VALUES (?, ?)
+		`, role, p); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
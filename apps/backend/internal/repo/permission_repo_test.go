@@ -0,0 +1,66 @@
+package repo
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"bookinghub-backend/internal/domain"
+)
+
+func TestPermissionRepo_ListRolePermissions(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewPermissionRepo(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT role, permission
+		FROM role_permissions
+		ORDER BY role ASC, permission ASC
+	`)).WillReturnRows(sqlmock.NewRows([]string{"role", "permission"}).
+		AddRow("ADMIN", "category:manage").
+		AddRow("COMPANY", "organization:create"))
+
+	items, err := r.ListRolePermissions(context.Background())
+	if err != nil {
+		t.Fatalf("ListRolePermissions err: %v", err)
+	}
+	if len(items) != 2 || items[0].Role != domain.RoleAdmin || items[1].Permission != domain.PermOrgCreate {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestPermissionRepo_ReplaceForRole(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewPermissionRepo(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM role_permissions WHERE role = ?`)).
+		WithArgs(domain.RoleCompany).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO role_permissions").
+		WithArgs(domain.RoleCompany, domain.PermOrgCreate).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO role_permissions").
+		WithArgs(domain.RoleCompany, domain.PermCategoryManage).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := r.ReplaceForRole(context.Background(), domain.RoleCompany, []domain.Permission{
+		domain.PermOrgCreate, domain.PermCategoryManage,
+	})
+	if err != nil {
+		t.Fatalf("ReplaceForRole err: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+
+	"bookinghub-backend/internal/domain"
+)
+
+const resourceImageColumns = `id, resource_id, storage_key, thumb_key, content_type, size_bytes, width, height, position, is_cover, created_at`
+
+var ErrImageOrderMismatch = errors.New("список фото не совпадает с фото объявления")
+
+type ResourceImageRepo struct {
+	db *sqlx.DB
+	// urlFor превращает ключ хранилища в публичный URL
+	urlFor func(key string) string
+}
+
+func NewResourceImageRepo(db *sqlx.DB, urlFor func(key string) string) *ResourceImageRepo {
+	if urlFor == nil {
+		urlFor = func(key string) string { return key }
+	}
+	return &ResourceImageRepo{db: db, urlFor: urlFor}
+}
+
+func (r *ResourceImageRepo) withURLs(items []domain.ResourceImage) {
+	for i := range items {
+		items[i].URL = r.urlFor(items[i].StorageKey)
+		items[i].ThumbnailURL = r.urlFor(items[i].ThumbKey)
+	}
+}
+
+func (r *ResourceImageRepo) ListByResource(ctx context.Context, resourceID uint64) ([]domain.ResourceImage, error) {
+	items := make([]domain.ResourceImage, 0)
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT `+resourceImageColumns+`
+		FROM resource_images
+		WHERE resource_id = ?
+		ORDER BY position ASC, id ASC
+	`, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	r.withURLs(items)
+	return items, nil
+}
+
+// ListByResources — фото нескольких объявлений одним запросом, сгруппированные по resource_id
+func (r *ResourceImageRepo) ListByResources(ctx context.Context, resourceIDs []uint64) (map[uint64][]domain.ResourceImage, error) {
+	out := make(map[uint64][]domain.ResourceImage, len(resourceIDs))
+	if len(resourceIDs) == 0 {
+		return out, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT `+resourceImageColumns+`
+		FROM resource_images
+		WHERE resource_id IN (?)
+		ORDER BY resource_id ASC, position ASC, id ASC
+	`, resourceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.ResourceImage, 0)
+	if err := r.db.SelectContext(ctx, &items, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	r.withURLs(items)
+	for _, img := range items {
+		out[img.ResourceID] = append(out[img.ResourceID], img)
+	}
+	return out, nil
+}
+
+func (r *ResourceImageRepo) GetByID(ctx context.Context, resourceID, imageID uint64) (*domain.ResourceImage, error) {
+	var img domain.ResourceImage
+	err := r.db.GetContext(ctx, &img, `
+		SELECT `+resourceImageColumns+`
+		FROM resource_images
+		WHERE id = ? AND resource_id = ?
+		LIMIT 1
+	`, imageID, resourceID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	items := []domain.ResourceImage{img}
+	r.withURLs(items)
+	return &items[0], nil
+}
+
+func (r *ResourceImageRepo) CountByResource(ctx context.Context, resourceID uint64) (int, error) {
+	var cnt int
+	err := r.db.GetContext(ctx, &cnt, `SELECT COUNT(*) FROM resource_images WHERE resource_id = ?`, resourceID)
+	return cnt, err
+}
+
+// Create добавляет фото в конец списка; первое фото объявления становится обложкой
+func (r *ResourceImageRepo) Create(ctx context.Context, img *domain.ResourceImage) (uint64, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var next struct {
+		Position int `db:"next_position"`
+		Covers   int `db:"covers"`
+	}
+	// FOR UPDATE: параллельные загрузки в одно объявление не получат одинаковую позицию
+	if err := tx.GetContext(ctx, &next, `
+		SELECT COALESCE(MAX(position) + 1, 0) AS next_position, COALESCE(SUM(is_cover), 0) AS covers
+		FROM resource_images
+		WHERE resource_id = ?
+		FOR UPDATE
+	`, img.ResourceID); err != nil {
+		return 0, err
+	}
+
+	img.Position = next.Position
+	img.IsCover = next.Covers == 0
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO resource_images (resource_id, storage_key, thumb_key, content_type, size_bytes, width, height, position, is_cover)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, img.ResourceID, img.StorageKey, img.ThumbKey, img.ContentType, img.SizeBytes, img.Width, img.Height, img.Position, img.IsCover)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	img.ID = uint64(id)
+	img.URL = r.urlFor(img.StorageKey)
+	img.ThumbnailURL = r.urlFor(img.ThumbKey)
+	return img.ID, nil
+}
+
+// Delete удаляет фото; если это была обложка, обложкой становится первое оставшееся фото
+func (r *ResourceImageRepo) Delete(ctx context.Context, resourceID, imageID uint64, wasCover bool) error {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM resource_images WHERE id = ? AND resource_id = ?`, imageID, resourceID); err != nil {
+		return err
+	}
+	if wasCover {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE resource_images
+			SET is_cover = TRUE
+			WHERE resource_id = ?
+			ORDER BY position ASC, id ASC
+			LIMIT 1
+		`, resourceID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *ResourceImageRepo) SetCover(ctx context.Context, resourceID, imageID uint64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE resource_images
+		SET is_cover = (id = ?)
+		WHERE resource_id = ?
+	`, imageID, resourceID)
+	return err
+}
+
+// Reorder задаёт порядок фото; imageIDs должен содержать ровно все фото объявления
+func (r *ResourceImageRepo) Reorder(ctx context.Context, resourceID uint64, imageIDs []uint64) error {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var current []uint64
+	if err := tx.SelectContext(ctx, &current, `
+		SELECT id FROM resource_images WHERE resource_id = ? FOR UPDATE
+	`, resourceID); err != nil {
+		return err
+	}
+	if !sameIDs(current, imageIDs) {
+		return ErrImageOrderMismatch
+	}
+
+	for pos, id := range imageIDs {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE resource_images SET position = ? WHERE id = ? AND resource_id = ?
+		`, pos, id, resourceID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func sameIDs(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[uint64]int, len(a))
+	for _, id := range a {
+		seen[id]++
+	}
+	for _, id := range b {
+		if seen[id] == 0 {
+			return false
+		}
+		seen[id]--
+	}
+	return true
+}
@@ -0,0 +1,150 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"bookinghub-backend/internal/domain"
+)
+
+var resourceImageCols = []string{"id", "resource_id", "storage_key", "thumb_key", "content_type", "size_bytes", "width", "height", "position", "is_cover", "created_at"}
+
+func TestResourceImageRepo_Create_FirstImageBecomesCover(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewResourceImageRepo(db, func(key string) string { return "/uploads/" + key })
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT COALESCE\\(MAX\\(position\\) \\+ 1, 0\\) AS next_position, COALESCE\\(SUM\\(is_cover\\), 0\\) AS covers FROM resource_images WHERE resource_id = \\? FOR UPDATE").
+		WithArgs(uint64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"next_position", "covers"}).AddRow(0, 0))
+	mock.ExpectExec("INSERT INTO resource_images").
+		WithArgs(uint64(3), "resources/3/a.jpg", "resources/3/a_thumb.jpg", "image/jpeg", int64(10), 4, 3, 0, true).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectCommit()
+
+	img := &domain.ResourceImage{
+		ResourceID: 3, StorageKey: "resources/3/a.jpg", ThumbKey: "resources/3/a_thumb.jpg",
+		ContentType: "image/jpeg", SizeBytes: 10, Width: 4, Height: 3,
+	}
+	id, err := r.Create(context.Background(), img)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if id != 7 || !img.IsCover || img.URL != "/uploads/resources/3/a.jpg" || img.ThumbnailURL != "/uploads/resources/3/a_thumb.jpg" {
+		t.Fatalf("unexpected image: %+v", img)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceImageRepo_Reorder_Mismatch(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewResourceImageRepo(db, nil)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM resource_images WHERE resource_id = \\? FOR UPDATE").
+		WithArgs(uint64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint64(1)).AddRow(uint64(2)))
+	mock.ExpectRollback()
+
+	err := r.Reorder(context.Background(), 3, []uint64{1, 1})
+	if !errors.Is(err, ErrImageOrderMismatch) {
+		t.Fatalf("expected ErrImageOrderMismatch, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceImageRepo_Reorder_OK(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewResourceImageRepo(db, nil)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM resource_images").
+		WithArgs(uint64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint64(1)).AddRow(uint64(2)))
+	mock.ExpectExec("UPDATE resource_images SET position = \\? WHERE id = \\? AND resource_id = \\?").
+		WithArgs(0, uint64(2), uint64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE resource_images SET position = \\?").
+		WithArgs(1, uint64(1), uint64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := r.Reorder(context.Background(), 3, []uint64{2, 1}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceImageRepo_Delete_CoverMovesToFirst(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewResourceImageRepo(db, nil)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM resource_images WHERE id = \\? AND resource_id = \\?").
+		WithArgs(uint64(5), uint64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE resource_images SET is_cover = TRUE WHERE resource_id = \\? ORDER BY position ASC, id ASC LIMIT 1").
+		WithArgs(uint64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := r.Delete(context.Background(), 3, 5, true); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceRepo_List_WithImages(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	images := NewResourceImageRepo(db, func(key string) string { return "https://cdn.test/" + key })
+	r := NewResourceRepo(db).WithImages(images)
+	now := time.Now()
+
+	mock.ExpectQuery("FROM resources ORDER BY id DESC").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "owner_user_id", "organization_id", "category_id", "title", "description", "location", "price_per_hour", "is_active", "created_at",
+		}).
+			AddRow(uint64(2), uint64(1), nil, uint64(1), "B", nil, nil, 0, true, now).
+			AddRow(uint64(1), uint64(1), nil, uint64(1), "A", nil, nil, 0, true, now))
+	expectNoAttributeValues(mock)
+	mock.ExpectQuery("FROM resource_images WHERE resource_id IN \\(\\?, \\?\\)").
+		WithArgs(uint64(2), uint64(1)).
+		WillReturnRows(sqlmock.NewRows(resourceImageCols).
+			AddRow(uint64(9), uint64(1), "resources/1/x.png", "resources/1/x_thumb.jpg", "image/png", 100, 10, 10, 0, true, now))
+
+	items, err := r.List(context.Background(), domain.ResourceFilter{})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(items[0].Images) != 0 || items[0].Images == nil {
+		t.Fatalf("resource without photos must have empty images list: %+v", items[0].Images)
+	}
+	if len(items[1].Images) != 1 || items[1].Images[0].URL != "https://cdn.test/resources/1/x.png" {
+		t.Fatalf("unexpected images: %+v", items[1].Images)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
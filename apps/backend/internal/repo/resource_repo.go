@@ -2,56 +2,444 @@ package repo
 
 import (
 	"context"
+	"database/sql"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
 
 	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/geo"
 )
 
+const resourceColumns = `id, owner_user_id, organization_id, category_id, title, description, location,
+	address_line, city, postal_code, country, latitude, longitude, timezone, price_per_hour, capacity, buffer_before_min, buffer_after_min,
+	approval_mode, auto_approve_verified, auto_approve_returning, auto_approve_max_hours, auto_approve_business_hours, is_active, created_at`
+
+// maxGeohashCells — сколько префиксов geohash допускаем в одном запросе, дальше хватает индекса по широте
+const maxGeohashCells = 16
+
 type ResourceRepo struct {
-	db *sqlx.DB
+	db     *sqlx.DB
+	images *ResourceImageRepo
 }
 
 func NewResourceRepo(db *sqlx.DB) *ResourceRepo {
 	return &ResourceRepo{db: db}
 }
 
-func (r *ResourceRepo) List(ctx context.Context) ([]domain.Resource, error) {
+// WithImages включает подгрузку фото в List, ListByOwner и AttachDetails
+func (r *ResourceRepo) WithImages(images *ResourceImageRepo) *ResourceRepo {
+	r.images = images
+	return r
+}
+
+// AttachDetails заполняет у объявлений атрибуты и фото — по одному запросу на всю пачку
+func (r *ResourceRepo) AttachDetails(ctx context.Context, items []domain.Resource) error {
+	if len(items) == 0 {
+		return nil
+	}
+	if err := r.AttachAttributes(ctx, items); err != nil {
+		return err
+	}
+	return r.attachImages(ctx, items)
+}
+
+func (r *ResourceRepo) attachImages(ctx context.Context, items []domain.Resource) error {
+	if r.images == nil {
+		return nil
+	}
+	byResource, err := r.images.ListByResources(ctx, resourceIDs(items))
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		items[i].Images = byResource[items[i].ID]
+		if items[i].Images == nil {
+			items[i].Images = make([]domain.ResourceImage, 0)
+		}
+	}
+	return nil
+}
+
+// AttachAttributes заполняет Attributes значениями из resource_attribute_values
+func (r *ResourceRepo) AttachAttributes(ctx context.Context, items []domain.Resource) error {
+	if len(items) == 0 {
+		return nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT v.resource_id, a.code, a.type, v.value
+		FROM resource_attribute_values v
+		JOIN category_attributes a ON a.id = v.attribute_id
+		WHERE v.resource_id IN (?)
+	`, resourceIDs(items))
+	if err != nil {
+		return err
+	}
+
+	var rows []struct {
+		ResourceID uint64               `db:"resource_id"`
+		Code       string               `db:"code"`
+		Type       domain.AttributeType `db:"type"`
+		Value      string               `db:"value"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return err
+	}
+
+	byResource := make(map[uint64]map[string]any, len(items))
+	for _, row := range rows {
+		if byResource[row.ResourceID] == nil {
+			byResource[row.ResourceID] = make(map[string]any)
+		}
+		byResource[row.ResourceID][row.Code] = row.Type.Decode(row.Value)
+	}
+	for i := range items {
+		items[i].Attributes = byResource[items[i].ID]
+		if items[i].Attributes == nil {
+			items[i].Attributes = make(map[string]any)
+		}
+	}
+	return nil
+}
+
+func resourceIDs(items []domain.Resource) []uint64 {
+	ids := make([]uint64, len(items))
+	for i := range items {
+		ids[i] = items[i].ID
+	}
+	return ids
+}
+
+func (r *ResourceRepo) List(ctx context.Context, f domain.ResourceFilter) ([]domain.Resource, error) {
+	var args []any
+	query := `SELECT ` + resourceColumns
+	if f.Near != nil {
+		// расстояние по формуле гаверсинусов, радиус Земли 6371 км
+		query += `, 6371 * 2 * ASIN(LEAST(1, SQRT(
+			POWER(SIN(RADIANS(latitude - ?) / 2), 2) +
+			COS(RADIANS(?)) * COS(RADIANS(latitude)) * POWER(SIN(RADIANS(longitude - ?) / 2), 2)
+		))) AS distance_km`
+		args = append(args, f.Near.Lat, f.Near.Lat, f.Near.Lng)
+	}
+	query += " FROM resources"
+
+	if !f.Empty() {
+		where, whereArgs := resourceFilterSQL(f)
+		query += " WHERE " + where
+		args = append(args, whereArgs...)
+	}
+	if f.Near != nil {
+		query += " HAVING distance_km <= ?"
+		args = append(args, f.RadiusKm)
+	}
+	if f.Near != nil && f.SortByDistance {
+		query += " ORDER BY distance_km ASC, id DESC"
+	} else {
+		query += " ORDER BY id DESC"
+	}
+
+	query, args, err := sqlx.In(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
 	items := make([]domain.Resource, 0)
-	err := r.db.SelectContext(ctx, &items, `
-		SELECT id, owner_user_id, category_id, title, description, location, price_per_hour, is_active, created_at
+	if err := r.db.SelectContext(ctx, &items, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	return items, r.AttachDetails(ctx, items)
+}
+
+// resourceFilterSQL собирает условие WHERE; списки раскрываются потом через sqlx.In
+func resourceFilterSQL(f domain.ResourceFilter) (string, []any) {
+	var conds []string
+	var args []any
+
+	if len(f.CategoryIDs) > 0 {
+		conds = append(conds, "category_id IN (?)")
+		args = append(args, f.CategoryIDs)
+	}
+	for _, af := range f.Attributes {
+		cond := `EXISTS (
+			SELECT 1 FROM resource_attribute_values v
+			JOIN category_attributes a ON a.id = v.attribute_id
+			WHERE v.resource_id = resources.id AND a.code = ?`
+		args = append(args, af.Code)
+		if len(af.Values) > 0 {
+			cond += " AND v.value IN (?)"
+			args = append(args, af.Values)
+		}
+		if af.Min != nil {
+			cond += " AND v.value_num >= ?"
+			args = append(args, *af.Min)
+		}
+		if af.Max != nil {
+			cond += " AND v.value_num <= ?"
+			args = append(args, *af.Max)
+		}
+		conds = append(conds, cond+")")
+	}
+	if f.Near != nil {
+		cond, boxArgs := geoBoxSQL(geo.BoundingBox(*f.Near, f.RadiusKm))
+		conds = append(conds, cond)
+		args = append(args, boxArgs...)
+	}
+	if f.BBox != nil {
+		cond, boxArgs := geoBoxSQL(*f.BBox)
+		conds = append(conds, cond)
+		args = append(args, boxArgs...)
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// geoBoxSQL — условие "точка внутри прямоугольника": префиксы geohash сужают выборку по индексу,
+// сравнение широты и долготы отсекает края ячеек
+func geoBoxSQL(box geo.Box) (string, []any) {
+	var parts []string
+	var args []any
+
+	if cells := geo.Cover(box, maxGeohashCells); len(cells) > 0 {
+		likes := make([]string, len(cells))
+		for i, c := range cells {
+			likes[i] = "geohash LIKE ?"
+			args = append(args, c+"%")
+		}
+		parts = append(parts, "("+strings.Join(likes, " OR ")+")")
+	}
+
+	parts = append(parts, "latitude BETWEEN ? AND ?")
+	args = append(args, box.MinLat, box.MaxLat)
+	if box.CrossesAntimeridian() {
+		parts = append(parts, "(longitude >= ? OR longitude <= ?)")
+	} else {
+		parts = append(parts, "longitude BETWEEN ? AND ?")
+	}
+	args = append(args, box.MinLng, box.MaxLng)
+
+	return strings.Join(parts, " AND "), args
+}
+
+func (r *ResourceRepo) GetByID(ctx context.Context, id uint64) (*domain.Resource, error) {
+	var res domain.Resource
+	err := r.db.GetContext(ctx, &res, `
+		SELECT `+resourceColumns+`
 		FROM resources
-		ORDER BY id DESC
-	`)
-	return items, err
+		WHERE id = ?
+		LIMIT 1
+	`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
 }
 
 func (r *ResourceRepo) Create(
 	ctx context.Context,
 	ownerUserID uint64,
+	organizationID *uint64,
 	categoryID uint64,
 	title string,
 	description, location *string,
+	addr domain.Address,
+	timezone string,
 	pricePerHour int,
+	rules domain.BookingRules,
+	approval domain.ApprovalRules,
+	attrs []domain.AttributeValue,
 ) (uint64, error) {
-	res, err := r.db.ExecContext(ctx, `
-		INSERT INTO resources (owner_user_id, category_id, title, description, location, price_per_hour)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`, ownerUserID, categoryID, title, description, location, pricePerHour)
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO resources (owner_user_id, organization_id, category_id, title, description, location,
+			address_line, city, postal_code, country, latitude, longitude, geohash, timezone, price_per_hour,
+			capacity, buffer_before_min, buffer_after_min,
+			approval_mode, auto_approve_verified, auto_approve_returning, auto_approve_max_hours, auto_approve_business_hours)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, ownerUserID, organizationID, categoryID, title, description, location,
+		addr.Line, addr.City, addr.PostalCode, addr.Country, addr.Latitude, addr.Longitude, geohashOf(addr), timezone, pricePerHour,
+		rules.Capacity, rules.BufferBeforeMin, rules.BufferAfterMin,
+		approval.Mode, approval.IfVerified, approval.IfReturning, approval.MaxHours, approval.InBusinessHours)
 	if err != nil {
 		return 0, err
 	}
 	id, err := res.LastInsertId()
-	return uint64(id), err
+	if err != nil {
+		return 0, err
+	}
+	if err := insertAttributeValues(ctx, tx, uint64(id), attrs); err != nil {
+		return 0, err
+	}
+	return uint64(id), tx.Commit()
+}
+
+// ResourceUpdate — изменения объявления для Update: nil — столбец не меняется
+type ResourceUpdate struct {
+	CategoryID *uint64
+	Title      *string
+	// Description и Location: пустая строка очищает поле
+	Description  *string
+	Location     *string
+	Address      *domain.Address
+	Timezone     *string
+	PricePerHour *int
+	Rules        *domain.BookingRules
+	Approval     *domain.ApprovalRules
+	IsActive     *bool
+	// Attributes заменяют значения атрибутов целиком
+	Attributes *[]domain.AttributeValue
+}
+
+// Update меняет только переданные поля; атрибуты, если переданы, заменяются в той же транзакции
+func (r *ResourceRepo) Update(ctx context.Context, id uint64, u ResourceUpdate) error {
+	var sets []string
+	var args []any
+	set := func(column string, v any) {
+		sets = append(sets, column+" = ?")
+		args = append(args, v)
+	}
+	if u.CategoryID != nil {
+		set("category_id", *u.CategoryID)
+	}
+	if u.Title != nil {
+		set("title", *u.Title)
+	}
+	if u.Description != nil {
+		set("description", sql.NullString{String: *u.Description, Valid: *u.Description != ""})
+	}
+	if u.Location != nil {
+		set("location", sql.NullString{String: *u.Location, Valid: *u.Location != ""})
+	}
+	if a := u.Address; a != nil {
+		set("address_line", a.Line)
+		set("city", a.City)
+		set("postal_code", a.PostalCode)
+		set("country", a.Country)
+		set("latitude", a.Latitude)
+		set("longitude", a.Longitude)
+		set("geohash", geohashOf(*a))
+	}
+	if u.Timezone != nil {
+		set("timezone", *u.Timezone)
+	}
+	if u.PricePerHour != nil {
+		set("price_per_hour", *u.PricePerHour)
+	}
+	if rl := u.Rules; rl != nil {
+		set("capacity", rl.Capacity)
+		set("buffer_before_min", rl.BufferBeforeMin)
+		set("buffer_after_min", rl.BufferAfterMin)
+	}
+	if ap := u.Approval; ap != nil {
+		set("approval_mode", ap.Mode)
+		set("auto_approve_verified", ap.IfVerified)
+		set("auto_approve_returning", ap.IfReturning)
+		set("auto_approve_max_hours", ap.MaxHours)
+		set("auto_approve_business_hours", ap.InBusinessHours)
+	}
+	if u.IsActive != nil {
+		set("is_active", *u.IsActive)
+	}
+	if len(sets) == 0 && u.Attributes == nil {
+		return nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if len(sets) > 0 {
+		if _, err := tx.ExecContext(ctx, `UPDATE resources SET `+strings.Join(sets, ", ")+` WHERE id = ?`, append(args, id)...); err != nil {
+			return err
+		}
+	}
+	if u.Attributes != nil {
+		// значения заменяются целиком: при смене категории старые атрибуты теряют смысл
+		if _, err := tx.ExecContext(ctx, `DELETE FROM resource_attribute_values WHERE resource_id = ?`, id); err != nil {
+			return err
+		}
+		if err := insertAttributeValues(ctx, tx, id, *u.Attributes); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
+func geohashOf(addr domain.Address) *string {
+	if !addr.HasCoordinates() {
+		return nil
+	}
+	h := geo.Encode(geo.Point{Lat: *addr.Latitude, Lng: *addr.Longitude}, geo.GeohashPrecision)
+	return &h
+}
+
+func insertAttributeValues(ctx context.Context, tx *sqlx.Tx, resourceID uint64, attrs []domain.AttributeValue) error {
+	for _, v := range attrs {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO resource_attribute_values (resource_id, attribute_id, value, value_num)
+			VALUES (?, ?, ?, ?)
+		`, resourceID, v.AttributeID, v.Value, v.ValueNum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListByOwner — объявления пользователя: личные и объявления организаций, в которых он состоит
 func (r *ResourceRepo) ListByOwner(ctx context.Context, ownerID uint64) ([]domain.Resource, error) {
 	items := make([]domain.Resource, 0)
 	err := r.db.SelectContext(ctx, &items, `
-		SELECT id, owner_user_id, category_id, title, description, location, price_per_hour, is_active, created_at
+		SELECT `+resourceColumns+`
 		FROM resources
 		WHERE owner_user_id = ?
+		   OR organization_id IN (
+		     SELECT organization_id FROM organization_members WHERE user_id = ?
+		   )
 		ORDER BY id DESC
-	`, ownerID)
+	`, ownerID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return items, r.AttachDetails(ctx, items)
+}
+
+func (r *ResourceRepo) ListOpeningHours(ctx context.Context, resourceID uint64) ([]domain.OpeningHours, error) {
+	items := make([]domain.OpeningHours, 0)
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT weekday, TIME_FORMAT(opens_at, '%H:%i') AS opens_at, TIME_FORMAT(closes_at, '%H:%i') AS closes_at
+		FROM resource_opening_hours
+		WHERE resource_id = ?
+		ORDER BY weekday ASC, opens_at ASC
+	`, resourceID)
 	return items, err
 }
+
+// ReplaceOpeningHours заменяет расписание ресурса целиком
+func (r *ResourceRepo) ReplaceOpeningHours(ctx context.Context, resourceID uint64, hours []domain.OpeningHours) error {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM resource_opening_hours WHERE resource_id = ?`, resourceID); err != nil {
+		return err
+	}
+	for _, h := range hours {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO resource_opening_hours (resource_id, weekday, opens_at, closes_at)
+			VALUES (?, ?, ?, ?)
+		`, resourceID, h.Weekday, h.OpensAt, h.ClosesAt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -7,6 +7,9 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
+
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/geo"
 )
 
 func newRepoMock(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock, func()) {
@@ -25,12 +28,14 @@ func TestResourceRepo_List_OK(t *testing.T) {
 	r := NewResourceRepo(dbx)
 	now := time.Now()
 
-	mock.ExpectQuery("SELECT id, owner_user_id, category_id, title, description, location, price_per_hour, is_active, created_at FROM resources ORDER BY id DESC").
+	mock.ExpectQuery("SELECT id, owner_user_id, organization_id, category_id, title, description, location, address_line, city, postal_code, country, latitude, longitude, timezone, price_per_hour, capacity, buffer_before_min, buffer_after_min, approval_mode, auto_approve_verified, auto_approve_returning, auto_approve_max_hours, auto_approve_business_hours, is_active, created_at FROM resources ORDER BY id DESC").
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "owner_user_id", "category_id", "title", "description", "location", "price_per_hour", "is_active", "created_at",
-		}).AddRow(uint64(1), uint64(2), uint64(3), "T", nil, nil, 10, true, now))
+			"id", "owner_user_id", "organization_id", "category_id", "title", "description", "location", "price_per_hour", "is_active", "created_at",
+		}).AddRow(uint64(1), uint64(2), nil, uint64(3), "T", nil, nil, 10, true, now))
+
+	expectNoAttributeValues(mock)
 
-	_, err := r.List(context.Background())
+	_, err := r.List(context.Background(), domain.ResourceFilter{})
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -45,11 +50,16 @@ func TestResourceRepo_Create_OK(t *testing.T) {
 
 	r := NewResourceRepo(dbx)
 
-	mock.ExpectExec("INSERT INTO resources \\(owner_user_id, category_id, title, description, location, price_per_hour\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?\\)").
-		WithArgs(uint64(2), uint64(3), "T", nil, nil, 10).
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO resources \\(owner_user_id, organization_id, category_id, title, description, location, "+
+		"address_line, city, postal_code, country, latitude, longitude, geohash, timezone, price_per_hour, "+
+		"capacity, buffer_before_min, buffer_after_min, approval_mode, .*\\)").
+		WithArgs(uint64(2), nil, uint64(3), "T", nil, nil, nil, nil, nil, nil, nil, nil, nil, "Europe/Moscow", 10, 1, 0, 0,
+			"AUTO", false, false, nil, false).
 		WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectCommit()
 
-	id, err := r.Create(context.Background(), 2, 3, "T", nil, nil, 10)
+	id, err := r.Create(context.Background(), 2, nil, 3, "T", nil, nil, domain.Address{}, "Europe/Moscow", 10, domain.BookingRules{Capacity: 1}, domain.ApprovalRules{Mode: domain.ApprovalAuto}, nil)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -68,11 +78,13 @@ func TestResourceRepo_ListByOwner_OK(t *testing.T) {
 	r := NewResourceRepo(dbx)
 	now := time.Now()
 
-	mock.ExpectQuery("SELECT id, owner_user_id, category_id, title, description, location, price_per_hour, is_active, created_at FROM resources WHERE owner_user_id = \\? ORDER BY id DESC").
-		WithArgs(uint64(9)).
+	mock.ExpectQuery("SELECT .* FROM resources WHERE owner_user_id = \\? OR organization_id IN \\( SELECT organization_id FROM organization_members WHERE user_id = \\? \\) ORDER BY id DESC").
+		WithArgs(uint64(9), uint64(9)).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "owner_user_id", "category_id", "title", "description", "location", "price_per_hour", "is_active", "created_at",
-		}).AddRow(uint64(1), uint64(9), uint64(1), "Mine", nil, nil, 0, true, now))
+			"id", "owner_user_id", "organization_id", "category_id", "title", "description", "location", "price_per_hour", "is_active", "created_at",
+		}).AddRow(uint64(1), uint64(9), nil, uint64(1), "Mine", nil, nil, 0, true, now))
+
+	expectNoAttributeValues(mock)
 
 	_, err := r.ListByOwner(context.Background(), 9)
 	if err != nil {
@@ -82,3 +94,196 @@ func TestResourceRepo_ListByOwner_OK(t *testing.T) {
 		t.Fatalf("expectations: %v", err)
 	}
 }
+
+func TestResourceRepo_ListOpeningHours_OK(t *testing.T) {
+	dbx, mock, cleanup := newRepoMock(t)
+	defer cleanup()
+
+	r := NewResourceRepo(dbx)
+
+	mock.ExpectQuery("SELECT weekday, TIME_FORMAT\\(opens_at, '%H:%i'\\) AS opens_at, TIME_FORMAT\\(closes_at, '%H:%i'\\) AS closes_at FROM resource_opening_hours WHERE resource_id = \\?").
+		WithArgs(uint64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"weekday", "opens_at", "closes_at"}).AddRow(1, "09:00", "18:00"))
+
+	items, err := r.ListOpeningHours(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(items) != 1 || items[0].OpensAt != "09:00" {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceRepo_ReplaceOpeningHours_OK(t *testing.T) {
+	dbx, mock, cleanup := newRepoMock(t)
+	defer cleanup()
+
+	r := NewResourceRepo(dbx)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM resource_opening_hours WHERE resource_id = \\?").
+		WithArgs(uint64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO resource_opening_hours \\(resource_id, weekday, opens_at, closes_at\\)").
+		WithArgs(uint64(3), 1, "09:00", "18:00").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := r.ReplaceOpeningHours(context.Background(), 3, []domain.OpeningHours{{Weekday: 1, OpensAt: "09:00", ClosesAt: "18:00"}})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func expectNoAttributeValues(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("FROM resource_attribute_values v JOIN category_attributes a").
+		WillReturnRows(sqlmock.NewRows([]string{"resource_id", "code", "type", "value"}))
+}
+
+func TestResourceRepo_List_FilterByCategoryAndAttributes(t *testing.T) {
+	dbx, mock, cleanup := newRepoMock(t)
+	defer cleanup()
+
+	r := NewResourceRepo(dbx)
+	now := time.Now()
+	min := 10.0
+
+	mock.ExpectQuery("FROM resources WHERE category_id IN \\(\\?, \\?\\) "+
+		"AND EXISTS \\( SELECT 1 FROM resource_attribute_values v JOIN category_attributes a ON a.id = v.attribute_id "+
+		"WHERE v.resource_id = resources.id AND a.code = \\? AND v.value_num >= \\?\\) "+
+		"AND EXISTS \\( .* AND a.code = \\? AND v.value IN \\(\\?, \\?\\)\\) ORDER BY id DESC").
+		WithArgs(uint64(1), uint64(4), "capacity", min, "mount", "EF", "RF").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "owner_user_id", "organization_id", "category_id", "title", "description", "location", "price_per_hour", "is_active", "created_at",
+		}).AddRow(uint64(7), uint64(2), nil, uint64(4), "Room", nil, nil, 10, true, now))
+	mock.ExpectQuery("FROM resource_attribute_values v JOIN category_attributes a").
+		WithArgs(uint64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"resource_id", "code", "type", "value"}).
+			AddRow(uint64(7), "capacity", "int", "12").
+			AddRow(uint64(7), "projector", "bool", "true"))
+
+	items, err := r.List(context.Background(), domain.ResourceFilter{
+		CategoryIDs: []uint64{1, 4},
+		Attributes: []domain.AttributeFilter{
+			{Code: "capacity", Min: &min},
+			{Code: "mount", Values: []string{"EF", "RF"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if items[0].Attributes["capacity"] != int64(12) || items[0].Attributes["projector"] != true {
+		t.Fatalf("unexpected attributes: %+v", items[0].Attributes)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceRepo_Update_ReplacesAttributeValues(t *testing.T) {
+	dbx, mock, cleanup := newRepoMock(t)
+	defer cleanup()
+
+	r := NewResourceRepo(dbx)
+	capacity := 12.0
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE resources SET category_id = \\?, title = \\?, timezone = \\?, price_per_hour = \\?, "+
+		"capacity = \\?, buffer_before_min = \\?, buffer_after_min = \\?, approval_mode = \\?, .*, is_active = \\? WHERE id = \\?").
+		WithArgs(uint64(4), "Room", "Asia/Yekaterinburg", 10, 3, 0, 30,
+			"CONDITIONAL", true, false, nil, false, true, uint64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM resource_attribute_values WHERE resource_id = \\?").
+		WithArgs(uint64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO resource_attribute_values \\(resource_id, attribute_id, value, value_num\\)").
+		WithArgs(uint64(7), uint64(3), "12", &capacity).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	categoryID, title, timezone, price, active := uint64(4), "Room", "Asia/Yekaterinburg", 10, true
+	attrs := []domain.AttributeValue{{AttributeID: 3, Value: "12", ValueNum: &capacity}}
+	err := r.Update(context.Background(), 7, ResourceUpdate{
+		CategoryID:   &categoryID,
+		Title:        &title,
+		Timezone:     &timezone,
+		PricePerHour: &price,
+		Rules:        &domain.BookingRules{Capacity: 3, BufferAfterMin: 30},
+		Approval:     &domain.ApprovalRules{Mode: domain.ApprovalConditional, IfVerified: true},
+		IsActive:     &active,
+		Attributes:   &attrs,
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceRepo_Update_OnlyGivenColumns(t *testing.T) {
+	dbx, mock, cleanup := newRepoMock(t)
+	defer cleanup()
+
+	// остальные столбцы и атрибуты не трогаются; пустое описание очищает поле
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE resources SET description = \\?, price_per_hour = \\? WHERE id = \\?$").
+		WithArgs(nil, 250, uint64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	description, price := "", 250
+	if err := NewResourceRepo(dbx).Update(context.Background(), 7, ResourceUpdate{Description: &description, PricePerHour: &price}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceRepo_Update_NothingToChange(t *testing.T) {
+	dbx, mock, cleanup := newRepoMock(t)
+	defer cleanup()
+
+	if err := NewResourceRepo(dbx).Update(context.Background(), 7, ResourceUpdate{}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestResourceRepo_List_NearSortedByDistance(t *testing.T) {
+	dbx, mock, cleanup := newRepoMock(t)
+	defer cleanup()
+
+	r := NewResourceRepo(dbx)
+	now := time.Now()
+	near := geo.Point{Lat: 55.7558, Lng: 37.6173}
+
+	mock.ExpectQuery("AS distance_km FROM resources WHERE \\(geohash LIKE \\?.*\\) " +
+		"AND latitude BETWEEN \\? AND \\? AND longitude BETWEEN \\? AND \\? " +
+		"HAVING distance_km <= \\? ORDER BY distance_km ASC, id DESC").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "owner_user_id", "organization_id", "category_id", "title", "description", "location",
+			"address_line", "city", "postal_code", "country", "latitude", "longitude", "price_per_hour", "is_active", "created_at", "distance_km",
+		}).AddRow(uint64(1), uint64(2), nil, uint64(3), "T", nil, nil, "Тверская, 1", "Москва", nil, nil, 55.757, 37.615, 10, true, now, 0.17))
+	expectNoAttributeValues(mock)
+
+	items, err := r.List(context.Background(), domain.ResourceFilter{Near: &near, RadiusKm: 5, SortByDistance: true})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if items[0].DistanceKm == nil || *items[0].DistanceKm != 0.17 || items[0].Address.City == nil || *items[0].Address.City != "Москва" {
+		t.Fatalf("unexpected item: %+v", items[0])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
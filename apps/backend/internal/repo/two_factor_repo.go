@@ -0,0 +1,162 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"bookinghub-backend/internal/domain"
+)
+
+// TwoFactorRepo — секреты TOTP и коды восстановления пользователей
+type TwoFactorRepo struct {
+	db *sqlx.DB
+}
+
+func NewTwoFactorRepo(db *sqlx.DB) *TwoFactorRepo {
+	return &TwoFactorRepo{db: db}
+}
+
+// GetTOTP — секрет пользователя; nil, если 2FA не подключалась
+func (r *TwoFactorRepo) GetTOTP(ctx context.Context, userID uint64) (*domain.UserTOTP, error) {
+	var t domain.UserTOTP
+	err := r.db.GetContext(ctx, &t, `
+		SELECT user_id, secret, enabled_at, last_step
+		FROM user_totp
+		WHERE user_id = ?
+	`, userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// SavePendingTOTP сохраняет новый, ещё не подтверждённый секрет вместо прежнего
+func (r *TwoFactorRepo) SavePendingTOTP(ctx context.Context, userID uint64, secret string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_totp (user_id, secret)
+		VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE secret = VALUES(secret), enabled_at = NULL, last_step = NULL
+	`, userID, secret)
+	return err
+}
+
+// EnableTOTP подтверждает подключение: step — шаг кода, которым оно подтверждено.
+// Вместе с этим заменяет коды восстановления. false — подключение не начато или уже подтверждено.
+func (r *TwoFactorRepo) EnableTOTP(ctx context.Context, userID uint64, step int64, at time.Time, recoveryHashes []string) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE user_totp
+		SET enabled_at = ?, last_step = ?
+		WHERE user_id = ? AND enabled_at IS NULL
+	`, at, step, userID)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	if err := replaceRecoveryCodes(ctx, tx, userID, recoveryHashes); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// UseTOTPStep отмечает шаг принятого кода. false — код этого или более позднего шага уже
+// использовался (или 2FA выключена): повторно тот же код не принимается.
+func (r *TwoFactorRepo) UseTOTPStep(ctx context.Context, userID uint64, step int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE user_totp
+		SET last_step = ?
+		WHERE user_id = ? AND enabled_at IS NOT NULL AND (last_step IS NULL OR last_step < ?)
+	`, step, userID, step)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// ReplaceRecoveryCodes заменяет все коды восстановления пользователя новыми
+func (r *TwoFactorRepo) ReplaceRecoveryCodes(ctx context.Context, userID uint64, hashes []string) error {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, hashes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx *sqlx.Tx, userID uint64, hashes []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return err
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+	values := make([]string, len(hashes))
+	args := make([]any, 0, 2*len(hashes))
+	for i, h := range hashes {
+		values[i] = "(?, ?)"
+		args = append(args, userID, h)
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO user_recovery_codes (user_id, code_hash) VALUES `+strings.Join(values, ", "), args...)
+	return err
+}
+
+// CountRecoveryCodes — сколько кодов восстановления ещё не использовано
+func (r *TwoFactorRepo) CountRecoveryCodes(ctx context.Context, userID uint64) (int, error) {
+	var n int
+	err := r.db.GetContext(ctx, &n, `
+		SELECT COUNT(*)
+		FROM user_recovery_codes
+		WHERE user_id = ? AND used_at IS NULL
+	`, userID)
+	return n, err
+}
+
+// UseRecoveryCode гасит код восстановления с хешем hash. false — такого неиспользованного кода нет.
+func (r *TwoFactorRepo) UseRecoveryCode(ctx context.Context, userID uint64, hash string, at time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE user_recovery_codes
+		SET used_at = ?
+		WHERE user_id = ? AND code_hash = ? AND used_at IS NULL
+	`, at, userID, hash)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// DeleteTOTP выключает 2FA: удаляет секрет и коды восстановления
+func (r *TwoFactorRepo) DeleteTOTP(ctx context.Context, userID uint64) error {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_totp WHERE user_id = ?`, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
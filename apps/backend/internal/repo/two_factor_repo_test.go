@@ -0,0 +1,84 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestTwoFactorRepo_GetTOTP_NotFound(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`FROM user_totp\s+WHERE user_id = \?`).WithArgs(uint64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "secret", "enabled_at", "last_step"}))
+
+	got, err := NewTwoFactorRepo(db).GetTOTP(context.Background(), 5)
+	if err != nil || got != nil {
+		t.Fatalf("expected nil, nil got %+v, %v", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestTwoFactorRepo_UseTOTPStep_RejectsReplay(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	q := `UPDATE user_totp\s+SET last_step = \?\s+WHERE user_id = \? AND enabled_at IS NOT NULL AND \(last_step IS NULL OR last_step < \?\)`
+	mock.ExpectExec(q).WithArgs(int64(100), uint64(5), int64(100)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(q).WithArgs(int64(100), uint64(5), int64(100)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	r := NewTwoFactorRepo(db)
+	if ok, err := r.UseTOTPStep(context.Background(), 5, 100); err != nil || !ok {
+		t.Fatalf("first use: %v, %v", ok, err)
+	}
+	if ok, err := r.UseTOTPStep(context.Background(), 5, 100); err != nil || ok {
+		t.Fatalf("replay: %v, %v", ok, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestTwoFactorRepo_EnableTOTP_ReplacesRecoveryCodes(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	at := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE user_totp\s+SET enabled_at = \?, last_step = \?\s+WHERE user_id = \? AND enabled_at IS NULL`).
+		WithArgs(at, int64(100), uint64(5)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM user_recovery_codes WHERE user_id = \?`).WithArgs(uint64(5)).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`INSERT INTO user_recovery_codes \(user_id, code_hash\) VALUES \(\?, \?\), \(\?, \?\)`).
+		WithArgs(uint64(5), "h1", uint64(5), "h2").WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectCommit()
+
+	ok, err := NewTwoFactorRepo(db).EnableTOTP(context.Background(), 5, 100, at, []string{"h1", "h2"})
+	if err != nil || !ok {
+		t.Fatalf("EnableTOTP: %v, %v", ok, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestTwoFactorRepo_EnableTOTP_NotPending(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE user_totp`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	ok, err := NewTwoFactorRepo(db).EnableTOTP(context.Background(), 5, 100, time.Now(), []string{"h1"})
+	if err != nil || ok {
+		t.Fatalf("expected false, nil got %v, %v", ok, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -2,6 +2,8 @@ package repo
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
@@ -21,7 +23,7 @@ func NewUserRepo(db *sqlx.DB) *UserRepo {
 func (r *UserRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var u domain.User
 	err := r.db.GetContext(ctx, &u, `
-		SELECT id, email, name, role, password_hash, created_at
+		SELECT id, email, name, role, password_hash, created_at, locale
 		FROM users
 		WHERE email = ?
 		LIMIT 1
@@ -55,7 +57,7 @@ func (r *UserRepo) UpdatePasswordHash(ctx context.Context, email, hash string) e
 func (r *UserRepo) GetByID(ctx context.Context, id uint64) (*domain.User, error) {
 	var u domain.User
 	err := r.db.GetContext(ctx, &u, `
-		SELECT id, email, name, role, password_hash, created_at
+		SELECT id, email, name, role, password_hash, created_at, locale
 		FROM users
 		WHERE id = ?
 		LIMIT 1
@@ -66,12 +68,13 @@ func (r *UserRepo) GetByID(ctx context.Context, id uint64) (*domain.User, error)
 	return &u, nil
 }
 
-func (r *UserRepo) UpdateProfile(ctx context.Context, id uint64, email, name string) error {
+// UpdateProfile сохраняет email, имя и язык (nil — выбирать по Accept-Language)
+func (r *UserRepo) UpdateProfile(ctx context.Context, id uint64, email, name string, locale *string) error {
 	_, err := r.db.ExecContext(ctx, `
 		UPDATE users
-		SET email = ?, name = ?
+		SET email = ?, name = ?, locale = ?
 		WHERE id = ?
-	`, email, name, id)
+	`, email, name, locale, id)
 	return err
 }
 
@@ -128,3 +131,94 @@ func (r *UserRepo) GetRoleByID(ctx context.Context, id uint64) (domain.UserRole,
 	`, id)
 	return role, err
 }
+
+// GetAuthState — роль, блокировка и отзыв сессий; nil если пользователя нет
+func (r *UserRepo) GetAuthState(ctx context.Context, id uint64) (*domain.UserAuthState, error) {
+	var st domain.UserAuthState
+	err := r.db.GetContext(ctx, &st, `
+		SELECT role, suspended_at, suspend_reason, sessions_revoked_at, locale
+		FROM users
+		WHERE id = ?
+		LIMIT 1
+	`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// AdminList — постраничный список пользователей с поиском по email/имени
+func (r *UserRepo) AdminList(ctx context.Context, query string, limit, offset int) ([]domain.AdminUser, int, error) {
+	where := ""
+	args := []any{}
+	if q := strings.TrimSpace(query); q != "" {
+		where = "WHERE email LIKE ? OR name LIKE ?"
+		like := "%" + q + "%"
+		args = append(args, like, like)
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM users `+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]domain.AdminUser, 0)
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT id, email, name, role, created_at, suspended_at, suspend_reason, verified_at
+		FROM users
+		`+where+`
+		ORDER BY id ASC
+		LIMIT ? OFFSET ?
+	`, append(args, limit, offset)...)
+	return items, total, err
+}
+
+func (r *UserRepo) UpdateRole(ctx context.Context, id uint64, role domain.UserRole) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE users
+		SET role = ?
+		WHERE id = ?
+	`, role, id)
+	return err
+}
+
+func (r *UserRepo) Suspend(ctx context.Context, id uint64, reason string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE users
+		SET suspended_at = ?, suspend_reason = ?
+		WHERE id = ?
+	`, at, reason, id)
+	return err
+}
+
+func (r *UserRepo) Unsuspend(ctx context.Context, id uint64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE users
+		SET suspended_at = NULL, suspend_reason = NULL
+		WHERE id = ?
+	`, id)
+	return err
+}
+
+// SetVerified отмечает пользователя проверенным (at) или снимает отметку (nil)
+func (r *UserRepo) SetVerified(ctx context.Context, id uint64, at *time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE users
+		SET verified_at = ?
+		WHERE id = ?
+	`, at, id)
+	return err
+}
+
+// RevokeSessions делает недействительными все токены, выпущенные раньше at
+func (r *UserRepo) RevokeSessions(ctx context.Context, id uint64, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE users
+		SET sessions_revoked_at = ?
+		WHERE id = ?
+	`, at, id)
+	return err
+}
@@ -27,7 +27,7 @@ func TestUserRepo_GetByID_OK(t *testing.T) {
 
 	r := NewUserRepo(db)
 
-	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at FROM users WHERE id = \\?").
+	mock.ExpectQuery("SELECT id, email, name, role, password_hash, created_at, locale FROM users WHERE id = \\?").
 		WithArgs(uint64(5)).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at"}).
 			AddRow(uint64(5), "a@b.c", "Alex", "INDIVIDUAL", "hash", time.Now()))
@@ -47,11 +47,12 @@ func TestUserRepo_UpdateProfile_OK(t *testing.T) {
 
 	r := NewUserRepo(db)
 
-	mock.ExpectExec("UPDATE users\\s+SET email = \\?, name = \\?\\s+WHERE id = \\?").
-		WithArgs("new@b.c", "NewName", uint64(7)).
+	en := "en"
+	mock.ExpectExec("UPDATE users\\s+SET email = \\?, name = \\?, locale = \\?\\s+WHERE id = \\?").
+		WithArgs("new@b.c", "NewName", &en, uint64(7)).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	if err := r.UpdateProfile(context.Background(), 7, "new@b.c", "NewName"); err != nil {
+	if err := r.UpdateProfile(context.Background(), 7, "new@b.c", "NewName", &en); err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
 }
@@ -85,3 +86,67 @@ func TestUserRepo_UpdatePasswordHashByID_OK(t *testing.T) {
 		t.Fatalf("unexpected err: %v", err)
 	}
 }
+
+func TestUserRepo_GetAuthState_NotFound(t *testing.T) {
+	db, mock, closeFn := newSQLXMockRepo2(t)
+	defer closeFn()
+
+	r := NewUserRepo(db)
+
+	mock.ExpectQuery("SELECT role, suspended_at, suspend_reason, sessions_revoked_at, locale FROM users WHERE id = \\?").
+		WithArgs(uint64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"role", "suspended_at", "suspend_reason", "sessions_revoked_at"}))
+
+	st, err := r.GetAuthState(context.Background(), 5)
+	if err != nil || st != nil {
+		t.Fatalf("expected nil state, got err=%v st=%+v", err, st)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestUserRepo_AdminList_WithSearch(t *testing.T) {
+	db, mock, closeFn := newSQLXMockRepo2(t)
+	defer closeFn()
+
+	r := NewUserRepo(db)
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE email LIKE \\? OR name LIKE \\?").
+		WithArgs("%ann%", "%ann%").
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(31))
+	mock.ExpectQuery("SELECT id, email, name, role, created_at, suspended_at, suspend_reason, verified_at FROM users WHERE email LIKE \\? OR name LIKE \\? ORDER BY id ASC LIMIT \\? OFFSET \\?").
+		WithArgs("%ann%", "%ann%", 10, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "created_at", "suspended_at", "suspend_reason"}).
+			AddRow(uint64(21), "ann@b.c", "Ann", "INDIVIDUAL", time.Now(), nil, nil))
+
+	items, total, err := r.AdminList(context.Background(), " ann ", 10, 20)
+	if err != nil {
+		t.Fatalf("AdminList err: %v", err)
+	}
+	if total != 31 || len(items) != 1 || items[0].ID != 21 {
+		t.Fatalf("unexpected result: total=%d items=%+v", total, items)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestUserRepo_Suspend(t *testing.T) {
+	db, mock, closeFn := newSQLXMockRepo2(t)
+	defer closeFn()
+
+	r := NewUserRepo(db)
+	at := time.Date(2025, 12, 29, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("UPDATE users SET suspended_at = \\?, suspend_reason = \\? WHERE id = \\?").
+		WithArgs(at, "spam", uint64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := r.Suspend(context.Background(), 4, "spam", at); err != nil {
+		t.Fatalf("Suspend err: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -48,7 +48,7 @@ func TestUserRepo_GetByEmail_OK(t *testing.T) {
 	now := time.Date(2025, 12, 29, 12, 0, 0, 0, time.UTC)
 
 	q := regexp.QuoteMeta(`
-		SELECT id, email, name, role, password_hash, created_at
+		SELECT id, email, name, role, password_hash, created_at, locale
 		FROM users
 		WHERE email = ?
 		LIMIT 1
@@ -145,7 +145,7 @@ func TestUserRepo_GetByEmail_NotFound(t *testing.T) {
 	r := NewUserRepo(db)
 
 	q := regexp.QuoteMeta(`
-		SELECT id, email, name, role, password_hash, created_at
+		SELECT id, email, name, role, password_hash, created_at, locale
 		FROM users
 		WHERE email = ?
 		LIMIT 1
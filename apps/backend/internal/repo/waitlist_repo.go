@@ -0,0 +1,151 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"bookinghub-backend/internal/domain"
+)
+
+type WaitlistRepo struct {
+	db *sqlx.DB
+}
+
+func NewWaitlistRepo(db *sqlx.DB) *WaitlistRepo {
+	return &WaitlistRepo{db: db}
+}
+
+// conn — транзакция брони, если метод вызван внутри BookingRepo.InTx, иначе пул соединений
+func (r *WaitlistRepo) conn(ctx context.Context) dbtx {
+	return bookingConn(ctx, r.db)
+}
+
+const waitlistColumns = `id, resource_id, user_id, start_at, end_at, quantity, auto_book, status, claim_expires_at, booking_id, created_at, updated_at`
+
+func (r *WaitlistRepo) Create(ctx context.Context, e domain.WaitlistEntry) (uint64, error) {
+	res, err := r.conn(ctx).ExecContext(ctx, `
+		INSERT INTO waitlist_entries (resource_id, user_id, start_at, end_at, quantity, auto_book, status)
+		VALUES (?, ?, ?, ?, ?, ?, 'WAITING')
+	`, e.ResourceID, e.UserID, e.StartAt, e.EndAt, e.Quantity, e.AutoBook)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	return uint64(id), err
+}
+
+// GetByID — заявка (внутри транзакции — с блокировкой строки); nil, если её нет
+func (r *WaitlistRepo) GetByID(ctx context.Context, id uint64) (*domain.WaitlistEntry, error) {
+	var e domain.WaitlistEntry
+	err := r.conn(ctx).GetContext(ctx, &e, `SELECT `+waitlistColumns+` FROM waitlist_entries WHERE id = ? LIMIT 1 FOR UPDATE`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *WaitlistRepo) ListByUser(ctx context.Context, userID uint64) ([]domain.WaitlistEntry, error) {
+	items := make([]domain.WaitlistEntry, 0)
+	err := r.conn(ctx).SelectContext(ctx, &items, `
+		SELECT `+waitlistColumns+`
+		FROM waitlist_entries
+		WHERE user_id = ?
+		ORDER BY created_at DESC, id DESC
+	`, userID)
+	return items, err
+}
+
+// ListActiveForResource — очередь ресурса на будущее время в порядке записи.
+// Внутри транзакции строки блокируются: выход из очереди дождётся раздачи места.
+func (r *WaitlistRepo) ListActiveForResource(ctx context.Context, resourceID uint64, now time.Time) ([]domain.WaitlistEntry, error) {
+	var items []domain.WaitlistEntry
+	err := r.conn(ctx).SelectContext(ctx, &items, `
+		SELECT `+waitlistColumns+`
+		FROM waitlist_entries
+		WHERE resource_id = ? AND status IN ('WAITING','OFFERED') AND start_at > ?
+		ORDER BY created_at ASC, id ASC
+		FOR UPDATE
+	`, resourceID, now)
+	return items, err
+}
+
+// HasActive — есть ли у пользователя активная заявка на тот же ресурс и интервал
+func (r *WaitlistRepo) HasActive(ctx context.Context, userID, resourceID uint64, startAt, endAt time.Time) (bool, error) {
+	var n int
+	err := r.conn(ctx).GetContext(ctx, &n, `
+		SELECT COUNT(*)
+		FROM waitlist_entries
+		WHERE user_id = ? AND resource_id = ? AND start_at = ? AND end_at = ? AND status IN ('WAITING','OFFERED')
+	`, userID, resourceID, startAt, endAt)
+	return n > 0, err
+}
+
+func (r *WaitlistRepo) MarkOffered(ctx context.Context, id uint64, expiresAt time.Time) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `
+		UPDATE waitlist_entries
+		SET status = 'OFFERED', claim_expires_at = ?
+		WHERE id = ? AND status = 'WAITING'
+	`, expiresAt, id)
+	return err
+}
+
+// MarkFulfilled закрывает заявку бронью, если она всё ещё в статусе from.
+// false — заявку успели закрыть, отозвать или выполнить, ничего не изменено.
+func (r *WaitlistRepo) MarkFulfilled(ctx context.Context, id, bookingID uint64, from domain.WaitlistStatus) (bool, error) {
+	res, err := r.conn(ctx).ExecContext(ctx, `
+		UPDATE waitlist_entries
+		SET status = 'FULFILLED', booking_id = ?, claim_expires_at = NULL
+		WHERE id = ? AND status = ?
+	`, bookingID, id, from)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// SetStatus переводит активную заявку в status (WAITING снимает срок предложения)
+func (r *WaitlistRepo) SetStatus(ctx context.Context, id uint64, status domain.WaitlistStatus) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `
+		UPDATE waitlist_entries
+		SET status = ?, claim_expires_at = NULL
+		WHERE id = ? AND status IN ('WAITING','OFFERED')
+	`, status, id)
+	return err
+}
+
+// ExpireOffers закрывает неподтверждённые вовремя предложения и возвращает их ресурсы:
+// освободившееся место нужно предложить следующим в очереди
+func (r *WaitlistRepo) ExpireOffers(ctx context.Context, now time.Time) ([]uint64, error) {
+	var resourceIDs []uint64
+	err := r.conn(ctx).SelectContext(ctx, &resourceIDs, `
+		SELECT DISTINCT resource_id
+		FROM waitlist_entries
+		WHERE status = 'OFFERED' AND claim_expires_at <= ?
+	`, now)
+	if err != nil || len(resourceIDs) == 0 {
+		return nil, err
+	}
+	_, err = r.conn(ctx).ExecContext(ctx, `
+		UPDATE waitlist_entries
+		SET status = 'EXPIRED'
+		WHERE status = 'OFFERED' AND claim_expires_at <= ?
+	`, now)
+	return resourceIDs, err
+}
+
+// ExpireStarted закрывает заявки, интервал которых уже начался
+func (r *WaitlistRepo) ExpireStarted(ctx context.Context, now time.Time) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `
+		UPDATE waitlist_entries
+		SET status = 'EXPIRED', claim_expires_at = NULL
+		WHERE status IN ('WAITING','OFFERED') AND start_at <= ?
+	`, now)
+	return err
+}
@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"bookinghub-backend/internal/domain"
+)
+
+func TestWaitlistRepo_ExpireOffers(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewWaitlistRepo(db)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT resource_id FROM waitlist_entries WHERE status = 'OFFERED' AND claim_expires_at <= ?`)).
+		WithArgs(now).
+		WillReturnRows(sqlmock.NewRows([]string{"resource_id"}).AddRow(uint64(5)).AddRow(uint64(6)))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE waitlist_entries SET status = 'EXPIRED' WHERE status = 'OFFERED' AND claim_expires_at <= ?`)).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	ids, err := r.ExpireOffers(context.Background(), now)
+	if err != nil {
+		t.Fatalf("ExpireOffers err: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != 5 || ids[1] != 6 {
+		t.Fatalf("unexpected resources: %v", ids)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestWaitlistRepo_ExpireOffers_NothingToDo(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT DISTINCT resource_id FROM waitlist_entries`).
+		WillReturnRows(sqlmock.NewRows([]string{"resource_id"}))
+
+	ids, err := NewWaitlistRepo(db).ExpireOffers(context.Background(), time.Now())
+	if err != nil || len(ids) != 0 {
+		t.Fatalf("expected no resources, got %v (%v)", ids, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestWaitlistRepo_MarkFulfilled_OnlyFromExpectedStatus(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	r := NewWaitlistRepo(db)
+	query := regexp.QuoteMeta(`UPDATE waitlist_entries SET status = 'FULFILLED', booking_id = ?, claim_expires_at = NULL WHERE id = ? AND status = ?`)
+
+	mock.ExpectExec(query).WithArgs(uint64(30), uint64(3), "OFFERED").WillReturnResult(sqlmock.NewResult(0, 1))
+	if ok, err := r.MarkFulfilled(context.Background(), 3, 30, domain.WaitlistOffered); err != nil || !ok {
+		t.Fatalf("expected fulfilled, got %v (%v)", ok, err)
+	}
+
+	// предложение уже забрали параллельным запросом
+	mock.ExpectExec(query).WithArgs(uint64(31), uint64(3), "OFFERED").WillReturnResult(sqlmock.NewResult(0, 0))
+	if ok, err := r.MarkFulfilled(context.Background(), 3, 31, domain.WaitlistOffered); err != nil || ok {
+		t.Fatalf("expected no change, got %v (%v)", ok, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package reporting
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"bookinghub-backend/internal/i18n"
+)
+
+// Разделы отчёта, доступные в CSV (одна таблица на файл)
+const (
+	SectionTotals     = "totals"
+	SectionSeries     = "series"
+	SectionResources  = "resources"
+	SectionCategories = "categories"
+	SectionOwners     = "owners"
+)
+
+// WriteCSV выгружает раздел отчёта в CSV; заголовки столбцов — на языке lang
+func WriteCSV(w io.Writer, rep *Report, section, lang string) error {
+	var rows [][]string
+	header := func(keys ...string) []string {
+		row := make([]string, len(keys))
+		for i, k := range keys {
+			row[i] = i18n.T(lang, "report."+k)
+		}
+		return row
+	}
+
+	switch section {
+	case SectionTotals:
+		t := rep.Totals
+		rows = [][]string{
+			header("bookings", "pending", "approved", "rejected", "canceled", "approval_rate", "avg_lead_time", "revenue"),
+			{itoa(t.Bookings), itoa(t.Pending), itoa(t.Approved), itoa(t.Rejected), itoa(t.Canceled),
+				ftoa(t.ApprovalRate), ftoa(t.AvgLeadTimeHours), i64toa(t.Revenue)},
+		}
+	case SectionSeries:
+		rows = append(rows, header("period", "bookings", "approved", "revenue"))
+		for _, p := range rep.Series {
+			rows = append(rows, []string{p.Period, itoa(p.Bookings), itoa(p.Approved), i64toa(p.Revenue)})
+		}
+	case SectionResources:
+		rows = append(rows, header("resource_id", "title", "booked_hours", "occupancy_percent", "revenue"))
+		for _, r := range rep.Resources {
+			rows = append(rows, []string{u64toa(r.ResourceID), r.Title, ftoa(r.BookedHours), ftoa(r.OccupancyPercent), i64toa(r.Revenue)})
+		}
+	case SectionCategories:
+		rows = append(rows, header("category_id", "name", "bookings", "revenue"))
+		for _, c := range rep.TopCategories {
+			rows = append(rows, []string{u64toa(c.CategoryID), c.Name, itoa(c.Bookings), i64toa(c.Revenue)})
+		}
+	case SectionOwners:
+		rows = append(rows, header("owner_user_id", "owner_name", "bookings", "revenue"))
+		for _, o := range rep.Owners {
+			rows = append(rows, []string{u64toa(o.OwnerUserID), o.Name, itoa(o.Bookings), i64toa(o.Revenue)})
+		}
+	default:
+		return fmt.Errorf("неизвестный раздел отчёта: %s", section)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// ValidSection — есть ли раздел с таким именем
+func ValidSection(section string) bool {
+	switch section {
+	case SectionTotals, SectionSeries, SectionResources, SectionCategories, SectionOwners:
+		return true
+	}
+	return false
+}
+
+func itoa(v int) string      { return strconv.Itoa(v) }
+func i64toa(v int64) string  { return strconv.FormatInt(v, 10) }
+func u64toa(v uint64) string { return strconv.FormatUint(v, 10) }
+func ftoa(v float64) string  { return strconv.FormatFloat(v, 'f', 2, 64) }
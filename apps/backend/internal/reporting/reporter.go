@@ -0,0 +1,203 @@
+package reporting
+
+import (
+	"context"
+	"math"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const topCategoriesLimit = 10
+
+// revenueExpr — выручка брони b по цене ресурса r (только APPROVED); цена — за единицу ресурса,
+// поэтому бронь на quantity единиц приносит quantity цен
+const revenueExpr = `CAST(COALESCE(SUM(CASE WHEN b.status = 'APPROVED'
+	THEN ROUND(TIMESTAMPDIFF(MINUTE, b.start_at, b.end_at) * r.price_per_hour * b.quantity / 60)
+	ELSE 0 END), 0) AS SIGNED)`
+
+// ownerScope — объявления пользователя: личные и организаций, где он состоит
+const ownerScope = `
+	AND (
+	  r.owner_user_id = ?
+	  OR r.organization_id IN (
+	    SELECT organization_id FROM organization_members WHERE user_id = ?
+	  )
+	)`
+
+type Reporter struct {
+	db *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Reporter {
+	return &Reporter{db: db}
+}
+
+// scope возвращает условие по владельцу и его аргументы
+func scope(p Params) (string, []any) {
+	if p.OwnerUserID == 0 {
+		return "", nil
+	}
+	return ownerScope, []any{p.OwnerUserID, p.OwnerUserID}
+}
+
+// Build собирает отчёт за период
+func (rp *Reporter) Build(ctx context.Context, p Params) (*Report, error) {
+	rep := &Report{From: p.From, To: p.To, GroupBy: p.GroupBy}
+
+	totals, err := rp.totals(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	rep.Totals = totals
+
+	series, err := rp.series(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	rep.Series = fillSeries(p.GroupBy, p.From, p.To, series)
+
+	if rep.Resources, err = rp.resources(ctx, p); err != nil {
+		return nil, err
+	}
+	if rep.TopCategories, err = rp.topCategories(ctx, p); err != nil {
+		return nil, err
+	}
+	if p.OwnerUserID == 0 {
+		if rep.Owners, err = rp.owners(ctx, p); err != nil {
+			return nil, err
+		}
+	}
+	return rep, nil
+}
+
+func (rp *Reporter) totals(ctx context.Context, p Params) (Totals, error) {
+	where, args := scope(p)
+
+	var t Totals
+	err := rp.db.GetContext(ctx, &t, `
+		SELECT
+		  COUNT(*) AS bookings,
+		  COALESCE(SUM(b.status = 'PENDING'), 0) AS pending,
+		  COALESCE(SUM(b.status = 'APPROVED'), 0) AS approved,
+		  COALESCE(SUM(b.status = 'REJECTED'), 0) AS rejected,
+		  COALESCE(SUM(b.status = 'CANCELED'), 0) AS canceled,
+		  COALESCE(AVG(TIMESTAMPDIFF(MINUTE, b.created_at, b.start_at)) / 60, 0) AS avg_lead_hours,
+		  `+revenueExpr+` AS revenue
+		FROM bookings b
+		JOIN resources r ON r.id = b.resource_id
+		WHERE b.start_at >= ? AND b.start_at < ?
+		`+where, append([]any{p.From, p.To}, args...)...)
+	if err != nil {
+		return Totals{}, err
+	}
+
+	if decided := t.Approved + t.Rejected; decided > 0 {
+		t.ApprovalRate = round2(float64(t.Approved) / float64(decided))
+	}
+	t.AvgLeadTimeHours = round2(t.AvgLeadTimeHours)
+	return t, nil
+}
+
+func (rp *Reporter) series(ctx context.Context, p Params) ([]SeriesPoint, error) {
+	where, args := scope(p)
+
+	items := make([]SeriesPoint, 0)
+	err := rp.db.SelectContext(ctx, &items, `
+		SELECT
+		  DATE_FORMAT(b.start_at, ?) AS period,
+		  COUNT(*) AS bookings,
+		  COALESCE(SUM(b.status = 'APPROVED'), 0) AS approved,
+		  `+revenueExpr+` AS revenue
+		FROM bookings b
+		JOIN resources r ON r.id = b.resource_id
+		WHERE b.start_at >= ? AND b.start_at < ?
+		`+where+`
+		GROUP BY period
+		ORDER BY period ASC
+	`, append([]any{p.GroupBy.sqlFormat(), p.From, p.To}, args...)...)
+	return items, err
+}
+
+// resources — загрузка и выручка по каждому ресурсу, включая ресурсы без броней.
+// Занятость считается в единице-минутах: бронь на 2 единицы из 4 на весь период — это 50%.
+func (rp *Reporter) resources(ctx context.Context, p Params) ([]ResourceStat, error) {
+	where, args := scope(p)
+
+	items := make([]ResourceStat, 0)
+	err := rp.db.SelectContext(ctx, &items, `
+		SELECT
+		  r.id AS resource_id,
+		  r.title,
+		  r.capacity,
+		  COALESCE(SUM(TIMESTAMPDIFF(MINUTE, GREATEST(b.start_at, ?), LEAST(b.end_at, ?)) * b.quantity), 0) AS booked_minutes,
+		  CAST(COALESCE(SUM(CASE WHEN b.start_at >= ?
+		    THEN ROUND(TIMESTAMPDIFF(MINUTE, b.start_at, b.end_at) * r.price_per_hour * b.quantity / 60)
+		    ELSE 0 END), 0) AS SIGNED) AS revenue
+		FROM resources r
+		LEFT JOIN bookings b
+		  ON b.resource_id = r.id
+		 AND b.status = 'APPROVED'
+		 AND b.start_at < ? AND b.end_at > ?
+		WHERE 1 = 1
+		`+where+`
+		GROUP BY r.id, r.title, r.capacity
+		ORDER BY booked_minutes DESC, r.id ASC
+	`, append([]any{p.From, p.To, p.From, p.To, p.From}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	rangeMinutes := p.To.Sub(p.From).Minutes()
+	for i := range items {
+		items[i].BookedHours = round2(float64(items[i].BookedMinutes) / 60)
+		if available := float64(max(items[i].Capacity, 1)) * rangeMinutes; available > 0 {
+			items[i].OccupancyPercent = round2(float64(items[i].BookedMinutes) / available * 100)
+		}
+	}
+	return items, nil
+}
+
+func (rp *Reporter) topCategories(ctx context.Context, p Params) ([]CategoryStat, error) {
+	where, args := scope(p)
+
+	items := make([]CategoryStat, 0)
+	err := rp.db.SelectContext(ctx, &items, `
+		SELECT
+		  c.id AS category_id,
+		  c.name,
+		  COUNT(*) AS bookings,
+		  `+revenueExpr+` AS revenue
+		FROM bookings b
+		JOIN resources r ON r.id = b.resource_id
+		JOIN resource_categories c ON c.id = r.category_id
+		WHERE b.start_at >= ? AND b.start_at < ?
+		`+where+`
+		GROUP BY c.id, c.name
+		ORDER BY bookings DESC, c.id ASC
+		LIMIT ?
+	`, append(append([]any{p.From, p.To}, args...), topCategoriesLimit)...)
+	return items, err
+}
+
+// owners — выручка по владельцам объявлений (отчёт по платформе)
+func (rp *Reporter) owners(ctx context.Context, p Params) ([]OwnerRevenue, error) {
+	items := make([]OwnerRevenue, 0)
+	err := rp.db.SelectContext(ctx, &items, `
+		SELECT
+		  u.id AS owner_user_id,
+		  u.name,
+		  COUNT(*) AS bookings,
+		  `+revenueExpr+` AS revenue
+		FROM bookings b
+		JOIN resources r ON r.id = b.resource_id
+		JOIN users u ON u.id = r.owner_user_id
+		WHERE b.start_at >= ? AND b.start_at < ?
+		GROUP BY u.id, u.name
+		ORDER BY revenue DESC, u.id ASC
+	`, p.From, p.To)
+	return items, err
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,192 @@
+package reporting
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+)
+
+func newMockDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock, func()) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	return sqlx.NewDb(db, "mysql"), mock, func() { _ = db.Close() }
+}
+
+// reportRows — ответы БД на запросы отчёта в порядке Build; owners = nil — запроса по владельцам нет
+type reportRows struct {
+	totals, series, resources, categories, owners *sqlmock.Rows
+}
+
+// ownerScopeSQL — условие отчёта владельца: без него отчёт показал бы чужие объявления
+const ownerScopeSQL = `AND \( r\.owner_user_id = \? OR r\.organization_id IN \( ` +
+	`SELECT organization_id FROM organization_members WHERE user_id = \? \) \)`
+
+// expectReport ждёт запросы отчёта в порядке Build. Текст сверяется только по опорным фрагментам,
+// а аргументы — полностью: отчёт владельца обязан передать его ID в каждое условие ownerScope.
+// Значения отчёта тесты проверяют по тому, что он посчитал из строк БД.
+func expectReport(mock sqlmock.Sqlmock, p Params, r reportRows) {
+	scope, owner := "", []driver.Value(nil)
+	if p.OwnerUserID != 0 {
+		scope, owner = " "+ownerScopeSQL, []driver.Value{p.OwnerUserID, p.OwnerUserID}
+	}
+	args := func(head []driver.Value, tail ...driver.Value) []driver.Value {
+		return append(append(head, owner...), tail...)
+	}
+
+	mock.ExpectQuery(`FROM bookings b JOIN resources r ON r\.id = b\.resource_id WHERE b\.start_at >= \? AND b\.start_at < \?` + scope + `$`).
+		WithArgs(args([]driver.Value{p.From, p.To})...).
+		WillReturnRows(r.totals)
+	mock.ExpectQuery(`DATE_FORMAT\(b\.start_at, \?\) AS period,.*b\.start_at < \?` + scope + ` GROUP BY period`).
+		WithArgs(args([]driver.Value{p.GroupBy.sqlFormat(), p.From, p.To})...).
+		WillReturnRows(r.series)
+	mock.ExpectQuery(`FROM resources r LEFT JOIN bookings b .* WHERE 1 = 1` + scope + ` GROUP BY r\.id`).
+		WithArgs(args([]driver.Value{p.From, p.To, p.From, p.To, p.From})...).
+		WillReturnRows(r.resources)
+	mock.ExpectQuery(`JOIN resource_categories c ON c\.id = r\.category_id WHERE b\.start_at >= \? AND b\.start_at < \?` + scope + ` GROUP BY c\.id`).
+		WithArgs(args([]driver.Value{p.From, p.To}, topCategoriesLimit)...).
+		WillReturnRows(r.categories)
+	if r.owners != nil {
+		mock.ExpectQuery(`JOIN users u ON u\.id = r\.owner_user_id WHERE b\.start_at >= \? AND b\.start_at < \? GROUP BY u\.id`).
+			WithArgs(p.From, p.To).
+			WillReturnRows(r.owners)
+	}
+}
+
+func totalsRows(bookings, pending, approved, rejected, canceled int, leadHours float64, revenue int64) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"bookings", "pending", "approved", "rejected", "canceled", "avg_lead_hours", "revenue"}).
+		AddRow(bookings, pending, approved, rejected, canceled, leadHours, revenue)
+}
+
+func seriesRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"period", "bookings", "approved", "revenue"})
+}
+
+func resourceRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"resource_id", "title", "capacity", "booked_minutes", "revenue"})
+}
+
+func categoryRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"category_id", "name", "bookings", "revenue"})
+}
+
+func TestReporter_Build_OwnerReport(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	p := Params{From: from, To: to, GroupBy: GroupDay, OwnerUserID: 7}
+	expectReport(mock, p, reportRows{
+		totals:     totalsRows(4, 1, 2, 1, 0, 26.666, 1500),
+		series:     seriesRows().AddRow("2026-01-02", 4, 2, 1500),
+		resources:  resourceRows().AddRow(uint64(3), "Переговорная", 1, 720, 1500),
+		categories: categoryRows().AddRow(uint64(1), "Офисы", 4, 1500),
+	})
+
+	rep, err := New(db).Build(context.Background(), p)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	// 2 подтверждены, 1 отклонена
+	if rep.Totals.ApprovalRate != 0.67 || rep.Totals.AvgLeadTimeHours != 26.67 || rep.Totals.Revenue != 1500 {
+		t.Fatalf("unexpected totals: %+v", rep.Totals)
+	}
+	// пустые дни периода добавляются нулями
+	if len(rep.Series) != 2 || rep.Series[0].Period != "2026-01-01" || rep.Series[0].Bookings != 0 || rep.Series[1].Bookings != 4 {
+		t.Fatalf("unexpected series: %+v", rep.Series)
+	}
+	// 12 часов из 48 — 25%
+	if rep.Resources[0].BookedHours != 12 || rep.Resources[0].OccupancyPercent != 25 {
+		t.Fatalf("unexpected resources: %+v", rep.Resources)
+	}
+	if rep.Owners != nil {
+		t.Fatalf("owner report must not include owners breakdown")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestReporter_Build_PlatformIncludesOwners(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	p := Params{From: from, To: to, GroupBy: GroupMonth}
+	expectReport(mock, p, reportRows{
+		totals:     totalsRows(0, 0, 0, 0, 0, 0, 0),
+		series:     seriesRows(),
+		resources:  resourceRows(),
+		categories: categoryRows(),
+		owners: sqlmock.NewRows([]string{"owner_user_id", "name", "bookings", "revenue"}).
+			AddRow(uint64(2), "Owner", 3, 4200),
+	})
+
+	rep, err := New(db).Build(context.Background(), p)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if rep.Totals.ApprovalRate != 0 {
+		t.Fatalf("approval rate without decisions must be 0")
+	}
+	if len(rep.Series) != 1 || rep.Series[0].Period != "2026-01" {
+		t.Fatalf("unexpected series: %+v", rep.Series)
+	}
+	if len(rep.Owners) != 1 || rep.Owners[0].Revenue != 4200 {
+		t.Fatalf("unexpected owners: %+v", rep.Owners)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestReporter_Build_CountsUnits(t *testing.T) {
+	// цена ресурса — за единицу: любая выручка в запросах отчёта должна умножаться на quantity брони
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherFunc(func(_, actual string) error {
+		if strings.Contains(actual, "price_per_hour") && !strings.Contains(actual, "b.quantity") {
+			return fmt.Errorf("revenue ignores booking quantity: %s", actual)
+		}
+		return nil
+	})))
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	// коворкинг на 4 места: 2 места на все 48 часов — 5760 единице-минут
+	p := Params{From: from, To: to, GroupBy: GroupDay, OwnerUserID: 7}
+	expectReport(mock, p, reportRows{
+		totals:     totalsRows(1, 0, 1, 0, 0, 24, 9600),
+		series:     seriesRows(),
+		resources:  resourceRows().AddRow(uint64(5), "Коворкинг", 4, 5760, 9600),
+		categories: categoryRows(),
+	})
+
+	rep, err := New(sqlx.NewDb(db, "mysql")).Build(context.Background(), p)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	// загрузка — от всех мест ресурса: 2 из 4 на весь период — 50%
+	if rep.Resources[0].BookedHours != 96 || rep.Resources[0].OccupancyPercent != 50 {
+		t.Fatalf("unexpected resources: %+v", rep.Resources)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+// Package reporting — аналитика по броням и объявлениям: агрегаты считаются SQL-запросами
+// поверх bookings/resources, а пакет собирает из них отчёт за период.
+//
+// Договорённости:
+//   - бронь относится к периоду по start_at, диапазон [From, To);
+//   - выручка — только APPROVED брони: длительность * price_per_hour * quantity;
+//   - approval rate — APPROVED / (APPROVED + REJECTED);
+//   - загрузка ресурса — единице-часы APPROVED броней (часы * quantity), обрезанные по диапазону,
+//     к capacity * длина диапазона.
+package reporting
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type GroupBy string
+
+const (
+	GroupDay   GroupBy = "day"
+	GroupWeek  GroupBy = "week"
+	GroupMonth GroupBy = "month"
+)
+
+const (
+	dateLayout   = "2006-01-02"
+	defaultDays  = 30
+	maxRangeDays = 366
+)
+
+func (g GroupBy) Valid() bool {
+	return g == GroupDay || g == GroupWeek || g == GroupMonth
+}
+
+// sqlFormat — формат DATE_FORMAT, совпадающий с label
+func (g GroupBy) sqlFormat() string {
+	switch g {
+	case GroupWeek:
+		return "%x-W%v"
+	case GroupMonth:
+		return "%Y-%m"
+	default:
+		return "%Y-%m-%d"
+	}
+}
+
+// label — ключ периода для момента t (как его вернёт MySQL)
+func (g GroupBy) label(t time.Time) string {
+	switch g {
+	case GroupWeek:
+		y, w := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", y, w)
+	case GroupMonth:
+		return t.Format("2006-01")
+	default:
+		return t.Format(dateLayout)
+	}
+}
+
+// periodStart — начало периода, в который попадает t
+func (g GroupBy) periodStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch g {
+	case GroupWeek:
+		shift := (int(day.Weekday()) + 6) % 7 // понедельник — начало недели
+		return day.AddDate(0, 0, -shift)
+	case GroupMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+func (g GroupBy) next(t time.Time) time.Time {
+	switch g {
+	case GroupWeek:
+		return t.AddDate(0, 0, 7)
+	case GroupMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// Params — параметры отчёта
+type Params struct {
+	From    time.Time // включительно
+	To      time.Time // не включительно
+	GroupBy GroupBy
+	// OwnerUserID — отчёт по объявлениям пользователя (личным и его организаций); 0 — вся платформа
+	OwnerUserID uint64
+}
+
+// ParseParams разбирает ?from=YYYY-MM-DD&to=YYYY-MM-DD&groupBy=day|week|month.
+// to включительно; по умолчанию — последние 30 дней по день now.
+func ParseParams(q url.Values, now time.Time) (Params, error) {
+	p := Params{GroupBy: GroupDay}
+
+	if s := strings.TrimSpace(q.Get("groupBy")); s != "" {
+		p.GroupBy = GroupBy(strings.ToLower(s))
+		if !p.GroupBy.Valid() {
+			return Params{}, errors.New("groupBy должен быть day, week или month")
+		}
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	to := today
+	if s := strings.TrimSpace(q.Get("to")); s != "" {
+		t, err := time.Parse(dateLayout, s)
+		if err != nil {
+			return Params{}, errors.New("Некорректный to, ожидается YYYY-MM-DD")
+		}
+		to = t
+	}
+
+	from := to.AddDate(0, 0, -(defaultDays - 1))
+	if s := strings.TrimSpace(q.Get("from")); s != "" {
+		t, err := time.Parse(dateLayout, s)
+		if err != nil {
+			return Params{}, errors.New("Некорректный from, ожидается YYYY-MM-DD")
+		}
+		from = t
+	}
+
+	if from.After(to) {
+		return Params{}, errors.New("from должен быть не позже to")
+	}
+	p.From = from
+	p.To = to.AddDate(0, 0, 1)
+	if p.To.Sub(p.From) > maxRangeDays*24*time.Hour {
+		return Params{}, fmt.Errorf("Период отчёта не длиннее %d дней", maxRangeDays)
+	}
+	return p, nil
+}
+
+// Totals — сводные показатели за период
+type Totals struct {
+	Bookings         int     `json:"bookings" db:"bookings"`
+	Pending          int     `json:"pending" db:"pending"`
+	Approved         int     `json:"approved" db:"approved"`
+	Rejected         int     `json:"rejected" db:"rejected"`
+	Canceled         int     `json:"canceled" db:"canceled"`
+	ApprovalRate     float64 `json:"approvalRate" db:"-"`
+	AvgLeadTimeHours float64 `json:"avgLeadTimeHours" db:"avg_lead_hours"`
+	Revenue          int64   `json:"revenue" db:"revenue"`
+}
+
+// SeriesPoint — показатели одного периода группировки
+type SeriesPoint struct {
+	Period   string `json:"period" db:"period"`
+	Bookings int    `json:"bookings" db:"bookings"`
+	Approved int    `json:"approved" db:"approved"`
+	Revenue  int64  `json:"revenue" db:"revenue"`
+}
+
+type ResourceStat struct {
+	ResourceID       uint64  `json:"resourceId" db:"resource_id"`
+	Title            string  `json:"title" db:"title"`
+	Capacity         int     `json:"-" db:"capacity"`
+	BookedMinutes    int64   `json:"-" db:"booked_minutes"` // единице-минуты: длительность × quantity
+	BookedHours      float64 `json:"bookedHours" db:"-"`
+	OccupancyPercent float64 `json:"occupancyPercent" db:"-"`
+	Revenue          int64   `json:"revenue" db:"revenue"`
+}
+
+type OwnerRevenue struct {
+	OwnerUserID uint64 `json:"ownerUserId" db:"owner_user_id"`
+	Name        string `json:"name" db:"name"`
+	Bookings    int    `json:"bookings" db:"bookings"`
+	Revenue     int64  `json:"revenue" db:"revenue"`
+}
+
+type CategoryStat struct {
+	CategoryID uint64 `json:"categoryId" db:"category_id"`
+	Name       string `json:"name" db:"name"`
+	Bookings   int    `json:"bookings" db:"bookings"`
+	Revenue    int64  `json:"revenue" db:"revenue"`
+}
+
+type Report struct {
+	From          time.Time      `json:"from"`
+	To            time.Time      `json:"to"`
+	GroupBy       GroupBy        `json:"groupBy"`
+	Totals        Totals         `json:"totals"`
+	Series        []SeriesPoint  `json:"series"`
+	Resources     []ResourceStat `json:"resources"`
+	TopCategories []CategoryStat `json:"topCategories"`
+	Owners        []OwnerRevenue `json:"owners,omitempty"` // только в отчёте по платформе
+}
+
+// fillSeries дополняет ряд пустыми периодами, чтобы на графике не было дыр
+func fillSeries(g GroupBy, from, to time.Time, points []SeriesPoint) []SeriesPoint {
+	byPeriod := make(map[string]SeriesPoint, len(points))
+	for _, p := range points {
+		byPeriod[p.Period] = p
+	}
+
+	out := make([]SeriesPoint, 0)
+	for t := g.periodStart(from); t.Before(to); t = g.next(t) {
+		label := g.label(t)
+		if p, ok := byPeriod[label]; ok {
+			out = append(out, p)
+			continue
+		}
+		out = append(out, SeriesPoint{Period: label})
+	}
+	return out
+}
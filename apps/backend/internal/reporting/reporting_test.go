@@ -0,0 +1,107 @@
+package reporting
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"bookinghub-backend/internal/i18n"
+)
+
+func TestParseParams(t *testing.T) {
+	now := time.Date(2026, 3, 15, 13, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		query    string
+		wantErr  bool
+		wantFrom string
+		wantTo   string
+		wantBy   GroupBy
+	}{
+		{name: "defaults", query: "", wantFrom: "2026-02-14", wantTo: "2026-03-16", wantBy: GroupDay},
+		{name: "explicit range, to inclusive", query: "from=2026-01-01&to=2026-01-31&groupBy=WEEK", wantFrom: "2026-01-01", wantTo: "2026-02-01", wantBy: GroupWeek},
+		{name: "bad groupBy", query: "groupBy=year", wantErr: true},
+		{name: "bad date", query: "from=01.01.2026", wantErr: true},
+		{name: "from after to", query: "from=2026-02-01&to=2026-01-01", wantErr: true},
+		{name: "too long", query: "from=2024-01-01&to=2026-01-01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, _ := url.ParseQuery(tt.query)
+			p, err := ParseParams(q, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := p.From.Format(dateLayout); got != tt.wantFrom {
+				t.Fatalf("from: want %s got %s", tt.wantFrom, got)
+			}
+			if got := p.To.Format(dateLayout); got != tt.wantTo {
+				t.Fatalf("to: want %s got %s", tt.wantTo, got)
+			}
+			if p.GroupBy != tt.wantBy {
+				t.Fatalf("groupBy: want %s got %s", tt.wantBy, p.GroupBy)
+			}
+		})
+	}
+}
+
+func TestFillSeries_FillsGaps(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	got := fillSeries(GroupDay, from, to, []SeriesPoint{{Period: "2026-01-02", Bookings: 3}})
+	if len(got) != 3 {
+		t.Fatalf("expected 3 points got %d", len(got))
+	}
+	if got[0].Period != "2026-01-01" || got[1].Bookings != 3 || got[2].Period != "2026-01-03" {
+		t.Fatalf("unexpected series: %+v", got)
+	}
+}
+
+func TestGroupBy_WeekLabelMatchesMySQL(t *testing.T) {
+	// 2026-01-01 — четверг, ISO-неделя 2026-W01 начинается 2025-12-29
+	d := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := GroupWeek.label(d); got != "2026-W01" {
+		t.Fatalf("unexpected label %s", got)
+	}
+	if got := GroupWeek.periodStart(d).Format(dateLayout); got != "2025-12-29" {
+		t.Fatalf("unexpected week start %s", got)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	rep := &Report{
+		Resources: []ResourceStat{{ResourceID: 5, Title: "Зал, большой", BookedHours: 1.5, OccupancyPercent: 12.345, Revenue: 900}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, rep, SectionResources, i18n.EN); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	want := "Resource ID,Title,Booked hours,\"Occupancy, %\",Revenue\n5,\"Зал, большой\",1.50,12.35,900\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected csv:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteCSV(&buf, rep, SectionResources, i18n.RU); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "ID ресурса,Название,Забронировано часов,") {
+		t.Fatalf("expected Russian header:\n%s", buf.String())
+	}
+
+	if err := WriteCSV(&buf, rep, "nope", i18n.RU); err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Fatalf("expected unknown section error, got %v", err)
+	}
+}
@@ -0,0 +1,476 @@
+// Package server собирает HTTP-сервер BookingHub: репозитории, сервисы и роутер chi.
+// Отдельно от main, чтобы тесты (в том числе клиента pkg/client) работали с настоящим роутером.
+package server
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jmoiron/sqlx"
+
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/geo"
+	"bookinghub-backend/internal/handler"
+	"bookinghub-backend/internal/oidc"
+	"bookinghub-backend/internal/openapi"
+	"bookinghub-backend/internal/policy"
+	"bookinghub-backend/internal/ratelimit"
+	"bookinghub-backend/internal/repo"
+	"bookinghub-backend/internal/reporting"
+	"bookinghub-backend/internal/service"
+	"bookinghub-backend/internal/storage"
+)
+
+// App — общие зависимости служебных обработчиков
+type App struct {
+	DB *sqlx.DB
+}
+
+// Server — собранное приложение: роутер и сервисы с фоновыми задачами
+type Server struct {
+	Router   chi.Router
+	waitlist *service.WaitlistService
+	holds    *service.HoldService
+	limits   *ratelimit.MemoryStore
+}
+
+// RunBackground запускает фоновые задачи: раздачу освободившегося времени из очереди, очистку удержаний
+// и состояния ограничителей частоты запросов
+func (s *Server) RunBackground(ctx context.Context) {
+	go s.waitlist.Run(ctx, time.Duration(getEnvInt("WAITLIST_SWEEP_INTERVAL_SEC", 60))*time.Second)
+	go s.holds.Run(ctx, time.Duration(getEnvInt("HOLDS_PURGE_INTERVAL_SEC", 30))*time.Second)
+	go s.limits.Run(ctx, time.Minute)
+}
+
+// New собирает репозитории, сервисы и роутер. middlewares встают после стандартных,
+// перед маршрутами: так тесты подключают openapi.Validator.
+func New(app *App, authSvc *service.AuthService, middlewares ...func(http.Handler) http.Handler) *Server {
+	dbx := app.DB
+
+	permissionRepo := repo.NewPermissionRepo(dbx)
+	pol := policy.New(permissionRepo)
+	if err := pol.Reload(context.Background()); err != nil {
+		log.Printf("failed to load role permissions, using defaults: %v", err)
+	}
+	permissionHandler := handler.NewPermissionHandler(permissionRepo, pol)
+
+	blobs, localUploads := newBlobStore()
+	imageRepo := repo.NewResourceImageRepo(dbx, blobs.URL)
+	imageSvc := service.NewImageService(imageRepo, blobs, int64(getEnvInt("IMAGE_MAX_BYTES", service.DefaultMaxImageBytes)), 0)
+
+	resourceRepo := repo.NewResourceRepo(dbx).WithImages(imageRepo)
+	orgRepo := repo.NewOrganizationRepo(dbx)
+	categoryRepo := repo.NewCategoryRepo(dbx)
+	resourceHandler := handler.NewResourceHandler(resourceRepo, categoryRepo, orgRepo, pol).WithGeocoder(newGeocoder())
+	categoryHandler := handler.NewCategoryHandler(categoryRepo)
+	userRepo := repo.NewUserRepo(dbx)
+	// ограничители частоты: одно хранилище на процесс; для нескольких реплик нужен общий ratelimit.Store
+	limits := ratelimit.NewMemoryStore()
+	lockout := ratelimit.NewLockout(limits).WithAfter(getEnvInt("LOGIN_LOCKOUT_AFTER", ratelimit.DefaultLockoutAfter))
+	twoFactorSvc := service.NewTwoFactorService(repo.NewTwoFactorRepo(dbx), getEnv("TOTP_ISSUER", "BookingHub")).
+		WithRequiredRoles(twoFactorRequiredRoles()...)
+	authHandler := handler.NewAuthHandler(userRepo, authSvc).
+		WithLoginLimits(limits, ratelimit.PerMinute(getEnvInt("LOGIN_RATE_PER_EMAIL_MIN", 10)), lockout).
+		WithTwoFactor(twoFactorSvc)
+	if provider := newOIDCProvider(authSvc); provider != nil {
+		sso := service.NewSSOService(repo.NewIdentityRepo(dbx), userRepo).WithRoleMap(oidcRoleMap())
+		authHandler.WithSSO(provider, sso)
+	}
+	authIPLimit := handler.RateLimit(limits, "auth-ip", ratelimit.PerMinute(getEnvInt("AUTH_RATE_PER_IP_MIN", 20)), handler.ClientIP)
+	bookingLimit := handler.RateLimit(limits, "booking-user", ratelimit.PerMinute(getEnvInt("BOOKING_RATE_PER_USER_MIN", 30)), handler.UserKey)
+	bookingRepo := repo.NewBookingRepo(dbx)
+	// автоподтверждение по режиму ресурса: для обычных броней, броней из удержаний и из очереди
+	approver := service.NewApprover(bookingRepo, resourceRepo)
+	bookingSvc := service.NewBookingService(bookingRepo).WithApprover(approver)
+	waitlistRepo := repo.NewWaitlistRepo(dbx)
+	waitlistSvc := service.NewWaitlistService(waitlistRepo, bookingRepo).
+		WithApprover(approver).
+		WithClaimTTL(time.Duration(getEnvInt("WAITLIST_CLAIM_TTL_MIN", int(service.DefaultClaimTTL/time.Minute))) * time.Minute)
+	waitlistHandler := handler.NewWaitlistHandler(waitlistRepo, waitlistSvc, bookingRepo)
+	holdSvc := service.NewHoldService(bookingRepo).
+		WithMaxPerUser(getEnvInt("HOLDS_MAX_PER_USER", service.DefaultMaxHoldsPerUser)).
+		WithWaitlist(waitlistSvc).
+		WithApprover(approver)
+	holdHandler := handler.NewHoldHandler(holdSvc, bookingRepo)
+	bookingHandler := handler.NewBookingHandler(bookingRepo, userRepo, bookingSvc, pol).WithWaitlist(waitlistSvc).WithHolds(holdSvc)
+	resourceBookingsHandler := handler.NewResourceBookingsHandler(bookingRepo)
+	bundleRepo := repo.NewBundleRepo(dbx)
+	bundleHandler := handler.NewBundleHandler(bundleRepo, resourceRepo, orgRepo, pol)
+	bookingGroupHandler := handler.NewBookingGroupHandler(bookingRepo, bundleRepo, service.NewBookingGroupService(bookingRepo).WithApprover(approver), pol).WithWaitlist(waitlistSvc)
+	occupancyHandler := handler.NewResourceOccupancyHandler(resourceRepo, bookingRepo, orgRepo, pol)
+	imageHandler := handler.NewResourceImageHandler(resourceRepo, imageRepo, imageSvc, orgRepo, pol)
+	userHandler := handler.NewUserHandler(userRepo)
+	orgHandler := handler.NewOrganizationHandler(orgRepo, userRepo)
+
+	adminUserHandler := handler.NewAdminUserHandler(userRepo, authSvc)
+	reportHandler := handler.NewReportHandler(reporting.New(dbx))
+	authMW := handler.AuthMiddleware(authSvc, userRepo)
+
+	doc := APIDoc(localUploads != nil)
+
+	r := chi.NewRouter()
+
+	// --- НАЧАЛО ВСТАВКИ CORS ---
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Разрешаем запросы с любого домена (для тестов это ок)
+			w.Header().Set("Access-Control-Allow-Origin", "https://bookinghub-wheat.vercel.app")
+			// Разрешаем стандартные методы
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+			// Разрешаем заголовки, которые важны для JSON и авторизации
+			w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-CSRF-Token")
+			// ID запроса из ошибок API, пометки устаревших адресов и Retry-After должны быть видны фронтенду
+			w.Header().Set("Access-Control-Expose-Headers", strings.Join([]string{
+				handler.RequestIDHeader, handler.DeprecationHeader, handler.SunsetHeader, "Link", handler.RetryAfterHeader,
+			}, ", "))
+
+			// Если это предварительный запрос (OPTIONS), сразу отвечаем 200
+			if r.Method == "OPTIONS" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	// --- КОНЕЦ ВСТАВКИ CORS ---
+
+	// Логи + базовая защита от паники
+	r.Use(middleware.RequestID)
+	r.Use(handler.ExposeRequestID)
+	r.Use(handler.Localize)
+	r.Use(middleware.RealIP)
+	r.Use(middleware.Logger)
+	r.Use(middleware.Recoverer)
+	r.Use(middlewares...)
+
+	// неизвестные маршруты и методы — в том же JSON-формате, что и ошибки обработчиков
+	r.NotFound(handler.NotFound)
+	r.MethodNotAllowed(handler.MethodNotAllowed)
+
+	// API v1. Несовместимые изменения ответов — новая версия рядом: свой роутер v2 с типами
+	// ответов из пакета apiv2 и r.Mount("/api/v2", v2); v1 продолжает работать как есть.
+	v1 := chi.NewRouter()
+	v1.NotFound(handler.NotFound)
+	v1.MethodNotAllowed(handler.MethodNotAllowed)
+	v1.Group(func(r chi.Router) {
+		r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("ok"))
+		})
+
+		// спецификация OpenAPI и Swagger UI поверх неё
+		r.Method(http.MethodGet, "/openapi.json", doc)
+		r.Method(http.MethodGet, "/docs", openapi.SwaggerUI(doc.Info.Title, "/api/v1/openapi.json", getEnv("SWAGGER_UI_ASSETS", "")))
+
+		r.Get("/categories", categoryHandler.List)
+		r.Get("/categories/tree", categoryHandler.Tree)
+		r.Get("/categories/{id}", categoryHandler.Get)
+		r.Get("/categories/{id}/attributes", categoryHandler.ListAttributes)
+
+		r.Get("/resources", resourceHandler.List)
+
+		r.Get("/users/{id}", userHandler.PublicByID)
+
+		// Создание ресурса — только для авторизованных
+		r.With(authMW).Post("/resources", resourceHandler.Create)
+
+		r.Route("/auth", func(r chi.Router) {
+			// вход и регистрация — с лимитом попыток на IP
+			r.With(authIPLimit).Post("/register", authHandler.Register)
+			r.With(authIPLimit).Post("/login", authHandler.Login)
+			r.With(authIPLimit).Post("/2fa/verify", authHandler.VerifyTwoFactor)
+			// вход через корпоративного провайдера
+			r.Get("/oidc/authorize", authHandler.SSOAuthorize)
+			r.With(authIPLimit).Post("/oidc/callback", authHandler.SSOCallback)
+
+			// защищённый роут
+			r.With(authMW).Get("/me", authHandler.Me)
+			r.With(authMW).Patch("/me", authHandler.UpdateMe)
+			r.With(authMW).Post("/password", authHandler.ChangePassword)
+			r.With(authMW).Delete("/me", authHandler.DeleteMe)
+
+			// двухфакторная аутентификация
+			r.With(authMW).Get("/2fa", authHandler.TwoFactorStatus)
+			r.With(authMW).Post("/2fa/enroll", authHandler.EnrollTwoFactor)
+			r.With(authMW).Post("/2fa/enable", authHandler.EnableTwoFactor)
+			r.With(authMW).Post("/2fa/recovery-codes", authHandler.RegenerateRecoveryCodes)
+			r.With(authMW).Post("/2fa/disable", authHandler.DisableTwoFactor)
+		})
+
+		// Бронирования: только авторизованные
+		r.With(authMW).Get("/bookings/my", bookingHandler.My)
+		r.With(authMW, bookingLimit).Post("/bookings", bookingHandler.Create)
+
+		// Менеджер: смотреть ожидающие и менять статус
+		r.With(authMW).Get("/bookings/pending", bookingHandler.Pending)
+
+		r.With(authMW).Patch("/bookings/{id}/status", bookingHandler.UpdateStatus)
+
+		r.With(authMW).Post("/bookings/{id}/cancel", bookingHandler.Cancel)
+		r.With(authMW).Get("/bookings/{id}/history", bookingHandler.History)
+
+		// удержание интервала на время оформления брони
+		r.With(authMW).Post("/resources/{id}/holds", holdHandler.Create)
+		r.With(authMW).Delete("/holds/{token}", holdHandler.Delete)
+
+		// очередь ожидания на занятое время
+		r.With(authMW).Post("/waitlist", waitlistHandler.Join)
+		r.With(authMW).Get("/waitlist/my", waitlistHandler.My)
+		r.With(authMW).Delete("/waitlist/{id}", waitlistHandler.Leave)
+		r.With(authMW).Post("/waitlist/{id}/claim", waitlistHandler.Claim)
+
+		// групповые брони и наборы ресурсов
+		r.With(authMW, bookingLimit).Post("/booking-groups", bookingGroupHandler.Create)
+		r.With(authMW).Get("/booking-groups/{id}", bookingGroupHandler.Get)
+		r.With(authMW).Post("/booking-groups/{id}/cancel", bookingGroupHandler.Cancel)
+		r.Get("/bundles", bundleHandler.List)
+		r.Get("/bundles/{id}", bundleHandler.Get)
+		r.With(authMW).Post("/bundles", bundleHandler.Create)
+		r.With(authMW).Delete("/bundles/{id}", bundleHandler.Delete)
+
+		r.Get("/resources/{id}/bookings", resourceBookingsHandler.List)
+		r.Get("/resources/{id}/availability", resourceBookingsHandler.Availability)
+		r.With(authMW).Get("/resources/{id}/occupancy", occupancyHandler.Get)
+		r.Get("/resources/{id}/opening-hours", resourceHandler.OpeningHours)
+		r.With(authMW).Put("/resources/{id}/opening-hours", resourceHandler.UpdateOpeningHours)
+
+		// Фото объявлений
+		r.Get("/resources/{id}/images", imageHandler.List)
+		r.With(authMW).Post("/resources/{id}/images", imageHandler.Upload)
+		r.With(authMW).Put("/resources/{id}/images/order", imageHandler.Reorder)
+		r.With(authMW).Post("/resources/{id}/images/{imageId}/cover", imageHandler.SetCover)
+		r.With(authMW).Delete("/resources/{id}/images/{imageId}", imageHandler.Delete)
+
+		r.With(authMW).Get("/resources/my", resourceHandler.My)
+		r.Get("/resources/{id}", resourceHandler.Get)
+		r.With(authMW).Patch("/resources/{id}", resourceHandler.Update)
+
+		// Организации: создавать могут компании (и админ), управлять — участники по ролям
+		r.Route("/organizations", func(r chi.Router) {
+			r.Use(authMW)
+			r.With(handler.RequirePermission(pol, domain.PermOrgCreate)).Post("/", orgHandler.Create)
+			r.Get("/my", orgHandler.My)
+			r.Get("/{id}/members", orgHandler.Members)
+			r.Patch("/{id}/members/{userId}", orgHandler.UpdateMember)
+			r.Delete("/{id}/members/{userId}", orgHandler.RemoveMember)
+			r.Get("/{id}/invites", orgHandler.Invites)
+			r.Post("/{id}/invites", orgHandler.CreateInvite)
+		})
+
+		r.Route("/invites", func(r chi.Router) {
+			r.Use(authMW)
+			r.Get("/my", orgHandler.MyInvites)
+			r.Post("/{token}/accept", orgHandler.AcceptInvite)
+			r.Post("/{token}/decline", orgHandler.DeclineInvite)
+		})
+
+		// Отчёты владельца по своим объявлениям
+		r.With(authMW).Get("/reports/owner", reportHandler.Owner)
+
+		r.With(
+			authMW,
+			handler.RequirePermission(pol, domain.PermCategoryManage),
+		).Post("/categories", categoryHandler.Create)
+
+		r.With(
+			authMW,
+			handler.RequirePermission(pol, domain.PermCategoryManage),
+		).Patch("/categories/{id}", categoryHandler.Update)
+
+		r.With(
+			authMW,
+			handler.RequirePermission(pol, domain.PermCategoryManage),
+		).Delete("/categories/{id}", categoryHandler.Delete)
+
+		// Жизненный цикл и схема атрибутов категории
+		r.Group(func(r chi.Router) {
+			r.Use(authMW, handler.RequirePermission(pol, domain.PermCategoryManage))
+			r.Post("/categories/{id}/merge", categoryHandler.Merge)
+			r.Post("/categories/{id}/archive", categoryHandler.Archive)
+			r.Post("/categories/{id}/unarchive", categoryHandler.Unarchive)
+			r.Post("/categories/{id}/attributes", categoryHandler.CreateAttribute)
+			r.Patch("/categories/{id}/attributes/{attrId}", categoryHandler.UpdateAttribute)
+			r.Delete("/categories/{id}/attributes/{attrId}", categoryHandler.DeleteAttribute)
+		})
+
+		// Админка: матрица ролей и прав, управление пользователями, отчёты
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(authMW)
+
+			r.Group(func(r chi.Router) {
+				r.Use(handler.RequirePermission(pol, domain.PermPermissionManage))
+				r.Get("/permissions", permissionHandler.List)
+				r.Put("/roles/{role}/permissions", permissionHandler.UpdateRole)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(handler.RequirePermission(pol, domain.PermUserManage))
+				r.Get("/users", adminUserHandler.List)
+				r.Patch("/users/{id}/role", adminUserHandler.UpdateRole)
+				r.Post("/users/{id}/suspend", adminUserHandler.Suspend)
+				r.Post("/users/{id}/unsuspend", adminUserHandler.Unsuspend)
+				r.Post("/users/{id}/verify", adminUserHandler.Verify)
+				r.Post("/users/{id}/unverify", adminUserHandler.Unverify)
+				r.Post("/users/{id}/logout", adminUserHandler.ForceLogout)
+				r.Post("/users/{id}/password-reset", adminUserHandler.ResetPassword)
+			})
+
+			r.With(handler.RequirePermission(pol, domain.PermReportViewAll)).Get("/reports", reportHandler.Platform)
+		})
+	})
+	r.Mount("/api/v1", v1)
+
+	// старые адреса без версии — те же маршруты v1, но с заголовками Deprecation и Sunset
+	r.Mount("/api", handler.Deprecated("/api", "/api/v1", legacyAPISince, legacyAPISunset())(v1))
+
+	r.Get("/db/ping", app.handleDBPing)
+
+	// файлы локального хранилища раздаёт сам backend
+	if localUploads != nil {
+		r.Handle("/uploads/*", http.StripPrefix("/uploads/", http.FileServer(http.Dir(localUploads.Root()))))
+	}
+
+	return &Server{Router: r, waitlist: waitlistSvc, holds: holdSvc, limits: limits}
+}
+
+// legacyAPISince — с этой даты адреса /api без версии считаются устаревшими
+var legacyAPISince = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+// legacyAPISunset — после этой даты адреса /api без версии могут быть отключены:
+// API_LEGACY_SUNSET в формате YYYY-MM-DD, по умолчанию — через полгода после legacyAPISince
+func legacyAPISunset() time.Time {
+	if v := os.Getenv("API_LEGACY_SUNSET"); v != "" {
+		if t, err := time.Parse(time.DateOnly, v); err == nil {
+			return t
+		}
+		log.Printf("invalid API_LEGACY_SUNSET %q, using default", v)
+	}
+	return legacyAPISince.AddDate(0, 6, 0)
+}
+
+func (a *App) handleDBPing(w http.ResponseWriter, r *http.Request) {
+	if err := a.DB.Ping(); err != nil {
+		log.Printf("[%s] db ping: %v", middleware.GetReqID(r.Context()), err)
+		http.Error(w, "db not reachable", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("db ok"))
+}
+
+// APIDoc — спецификация OpenAPI всех маршрутов роутера: /api/v1 из пакета handler и служебные
+// маршруты сервера. Устаревшие адреса /api без версии в документ не входят. localUploads — раздаются ли файлы локального хранилища.
+func APIDoc(localUploads bool) *openapi.Document {
+	routes := append(handler.APIRoutes(), openapi.Route{
+		ID: "dbPing", Method: http.MethodGet, Path: "/db/ping", Summary: "Проверка соединения с БД", Tag: "Служебное",
+		Content: "text/plain",
+		Replies: []openapi.Reply{{Status: http.StatusInternalServerError, Description: "БД недоступна", Content: "text/plain"}},
+	})
+	if localUploads {
+		routes = append(routes, openapi.Route{
+			ID: "uploadedFile", Method: http.MethodGet, Path: "/uploads/*", Summary: "Файл из локального хранилища", Tag: "Служебное",
+			Content: "*/*",
+			Replies: []openapi.Reply{{Status: http.StatusNotFound, Description: "Файла нет", Content: "text/plain"}},
+		})
+	}
+	return openapi.Build(openapi.Info{
+		Title:       "BookingHub API",
+		Version:     "1.0.0",
+		Description: "Ошибки приходят в общем формате: {\"error\": {\"code\", \"message\", \"details\", \"requestId\"}}.",
+	}, handler.ErrorBody(), routes)
+}
+
+// newBlobStore выбирает хранилище файлов по STORAGE_DRIVER: local (по умолчанию) или s3.
+// Второе значение — локальное хранилище, если файлы нужно раздавать самим.
+func newBlobStore() (storage.BlobStore, *storage.LocalStore) {
+	if getEnv("STORAGE_DRIVER", "local") == "s3" {
+		return storage.NewS3Store(storage.S3Config{
+			Endpoint:  getEnv("S3_ENDPOINT", "http://127.0.0.1:9000"),
+			Bucket:    getEnv("S3_BUCKET", "bookinghub"),
+			Region:    getEnv("S3_REGION", "us-east-1"),
+			AccessKey: getEnv("S3_ACCESS_KEY", ""),
+			SecretKey: getEnv("S3_SECRET_KEY", ""),
+			PublicURL: getEnv("S3_PUBLIC_URL", ""),
+		}), nil
+	}
+	local := storage.NewLocalStore(getEnv("UPLOADS_DIR", "./uploads"), getEnv("UPLOADS_PUBLIC_URL", "/uploads"))
+	return local, local
+}
+
+// twoFactorRequiredRoles — роли, для которых 2FA обязательна: TWO_FACTOR_REQUIRED_ROLES=ADMIN,COMPANY
+func twoFactorRequiredRoles() []domain.UserRole {
+	var roles []domain.UserRole
+	for _, r := range strings.Split(os.Getenv("TWO_FACTOR_REQUIRED_ROLES"), ",") {
+		if r = strings.ToUpper(strings.TrimSpace(r)); r != "" {
+			roles = append(roles, domain.UserRole(r))
+		}
+	}
+	return roles
+}
+
+// newOIDCProvider — корпоративный провайдер входа из OIDC_*; nil, если OIDC_ISSUER не задан
+func newOIDCProvider(authSvc *service.AuthService) *oidc.Provider {
+	issuer := os.Getenv("OIDC_ISSUER")
+	if issuer == "" {
+		return nil
+	}
+	return oidc.NewProvider(oidc.Config{
+		Issuer:       issuer,
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  getEnv("OIDC_REDIRECT_URL", "http://localhost:5173/"),
+		Scopes:       strings.Fields(getEnv("OIDC_SCOPES", "openid email profile")),
+		GroupsClaim:  getEnv("OIDC_GROUPS_CLAIM", "groups"),
+		StateKey:     authSvc.DeriveKey("bookinghub oidc state"),
+	})
+}
+
+// oidcRoleMap — роли по группам провайдера: OIDC_ROLE_MAP=bookinghub-admins=ADMIN,partners=COMPANY
+func oidcRoleMap() map[string]domain.UserRole {
+	roles := map[string]domain.UserRole{}
+	for _, pair := range strings.Split(os.Getenv("OIDC_ROLE_MAP"), ",") {
+		group, role, ok := strings.Cut(pair, "=")
+		group, role = strings.TrimSpace(group), strings.ToUpper(strings.TrimSpace(role))
+		if !ok || group == "" {
+			continue
+		}
+		switch r := domain.UserRole(role); r {
+		case domain.RoleAdmin, domain.RoleCompany, domain.RoleIndividual:
+			roles[group] = r
+		default:
+			log.Printf("OIDC_ROLE_MAP: неизвестная роль %q для группы %q, пропускаем", role, group)
+		}
+	}
+	return roles
+}
+
+// newGeocoder выбирает геокодер по GEOCODER: offline (по умолчанию, без сети) или nominatim
+func newGeocoder() geo.Geocoder {
+	if getEnv("GEOCODER", "offline") == "nominatim" {
+		return geo.NewNominatimGeocoder(getEnv("NOMINATIM_URL", ""), getEnv("NOMINATIM_USER_AGENT", "bookinghub-backend"))
+	}
+	return geo.NewOfflineGeocoder(nil)
+}
+
+func getEnvInt(key string, fallback int) int {
+	n, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || n <= 0 {
+		return fallback
+	}
+	return n
+}
+
+func getEnv(key, fallback string) string {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback
+	}
+	return val
+}
@@ -0,0 +1,206 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+
+	"bookinghub-backend/internal/openapi"
+	"bookinghub-backend/internal/service"
+)
+
+func TestHandleDBPing_OK(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectPing()
+
+	app := &App{DB: sqlx.NewDb(db, "sqlmock")}
+
+	req := httptest.NewRequest(http.MethodGet, "/db/ping", nil)
+	rr := httptest.NewRecorder()
+
+	app.handleDBPing(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations: %v", err)
+	}
+}
+
+func newTestServer(t *testing.T, middlewares ...func(http.Handler) http.Handler) (*Server, sqlmock.Sqlmock) {
+	t.Helper()
+	t.Setenv("STORAGE_DRIVER", "")
+	t.Setenv("UPLOADS_DIR", t.TempDir())
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	// матрица прав не загрузится из пустого мока — политика возьмёт права по умолчанию
+	app := &App{DB: sqlx.NewDb(db, "sqlmock")}
+	return New(app, service.NewAuthService("test-secret", 15), middlewares...), mock
+}
+
+// Каждый маршрут роутера должен быть описан в спецификации, и наоборот
+func TestOpenAPI_CoversAllRoutes(t *testing.T) {
+	srv, _ := newTestServer(t)
+	doc := APIDoc(true)
+
+	registered := map[string]bool{}
+	err := chi.Walk(srv.Router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		// r.Handle регистрирует все методы, файлы раздаются только по GET
+		if strings.HasSuffix(route, "/*") && method != http.MethodGet {
+			return nil
+		}
+		// устаревший /api без версии — тот же роутер v1, в спецификацию не входит
+		if route == "/api/*" {
+			return nil
+		}
+		key := method + " " + openapi.Path(route)
+		registered[key] = true
+		if doc.Operation(method, route) == nil {
+			t.Errorf("%s: маршрут без описания в спецификации (handler.APIRoutes)", key)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("chi.Walk: %v", err)
+	}
+
+	ids := map[string]string{}
+	for path, item := range doc.Paths {
+		for method, op := range item {
+			key := strings.ToUpper(method) + " " + path
+			if !registered[key] {
+				t.Errorf("%s: описан в спецификации, но не зарегистрирован в роутере", key)
+			}
+			if op.OperationID == "" {
+				t.Errorf("%s: нет operationId", key)
+			} else if prev, dup := ids[op.OperationID]; dup {
+				t.Errorf("operationId %q повторяется: %s и %s", op.OperationID, prev, key)
+			}
+			ids[op.OperationID] = key
+		}
+	}
+}
+
+// Настоящие ответы роутера проходят проверку по спецификации
+func TestOpenAPI_ValidatorOnRouter(t *testing.T) {
+	var problems []string
+	validator := openapi.Validator(APIDoc(true), func(r *http.Request, err error) {
+		problems = append(problems, err.Error())
+	})
+	srv, mock := newTestServer(t, validator)
+
+	now := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`FROM resource_categories`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id", "name", "archived_at", "created_at"}).
+			AddRow(1, nil, "Залы", nil, now).
+			AddRow(2, 1, "Переговорные", now, now))
+
+	cases := []struct {
+		method, path, body string
+		status             int
+	}{
+		{http.MethodGet, "/api/v1/health", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/openapi.json", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/docs", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/categories?includeArchived=true", "", http.StatusOK},
+		{http.MethodPost, "/api/v1/auth/register", `{"email":"bad"}`, http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/auth/me", "", http.StatusUnauthorized},
+		{http.MethodGet, "/api/v1/nope", "", http.StatusNotFound},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, c.path, strings.NewReader(c.body))
+		rr := httptest.NewRecorder()
+		srv.Router.ServeHTTP(rr, req)
+		if rr.Code != c.status {
+			t.Errorf("%s %s: expected %d, got %d %s", c.method, c.path, c.status, rr.Code, rr.Body.String())
+		}
+	}
+	if len(problems) > 0 {
+		t.Fatalf("responses do not match the spec:\n%s", strings.Join(problems, "\n"))
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations: %v", err)
+	}
+}
+
+// /api без версии отвечает как /api/v1, но с пометками устаревшего адреса
+func TestLegacyAPI_Deprecated(t *testing.T) {
+	t.Setenv("API_LEGACY_SUNSET", "2027-01-31")
+	srv, mock := newTestServer(t)
+	mock.ExpectQuery(`FROM resource_categories`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id", "name", "archived_at", "created_at"}))
+
+	send := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		srv.Router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+
+	rr := send("/api/categories")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("legacy route: expected 200, got %d %s", rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get("Deprecation"); got != "@1792368000" {
+		t.Errorf("Deprecation = %q", got)
+	}
+	if got := rr.Header().Get("Sunset"); got != "Sun, 31 Jan 2027 00:00:00 GMT" {
+		t.Errorf("Sunset = %q", got)
+	}
+	if got := rr.Header().Get("Link"); got != `</api/v1/categories>; rel="successor-version"` {
+		t.Errorf("Link = %q", got)
+	}
+
+	// ошибки по старым адресам — в общем формате и тоже с пометкой
+	rr = send("/api/nope")
+	if rr.Code != http.StatusNotFound || !strings.Contains(rr.Body.String(), `"NOT_FOUND"`) || rr.Header().Get("Deprecation") == "" {
+		t.Fatalf("legacy 404: %d %v %s", rr.Code, rr.Header(), rr.Body.String())
+	}
+
+	rr = send("/api/v1/health")
+	if rr.Code != http.StatusOK || rr.Header().Get("Deprecation") != "" || rr.Header().Get("Sunset") != "" {
+		t.Fatalf("v1 route must not be deprecated: %d %v", rr.Code, rr.Header())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations: %v", err)
+	}
+}
+
+func TestAuthRoutes_RateLimitedPerIP(t *testing.T) {
+	t.Setenv("AUTH_RATE_PER_IP_MIN", "2")
+	srv, _ := newTestServer(t)
+
+	send := func(path, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader("{bad"))
+		req.Header.Set("X-Forwarded-For", ip)
+		rr := httptest.NewRecorder()
+		srv.Router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// лимит общий для входа и регистрации, в том числе по старым адресам /api
+	send("/api/v1/auth/login", "203.0.113.5")
+	send("/api/auth/register", "203.0.113.5")
+	rr := send("/api/v1/auth/login", "203.0.113.5")
+	if rr.Code != http.StatusTooManyRequests || rr.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected 429 with Retry-After, got %d %v", rr.Code, rr.Header())
+	}
+	if rr := send("/api/v1/auth/login", "203.0.113.6"); rr.Code != http.StatusBadRequest {
+		t.Fatalf("other client: expected 400, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/geo"
+)
+
+const (
+	DefaultSearchRadiusKm = 10
+	MaxSearchRadiusKm     = 500
+)
+
+var ErrInvalidAddress = errors.New("Некорректный адрес")
+
+// NormalizeAddress обрезает пробелы, пустые строки превращает в nil и проверяет координаты
+func NormalizeAddress(a *domain.Address) error {
+	fields := []struct {
+		name  string
+		value **string
+		max   int
+	}{
+		{"line", &a.Line, 255},
+		{"city", &a.City, 100},
+		{"postalCode", &a.PostalCode, 20},
+		{"country", &a.Country, 100},
+	}
+	for _, f := range fields {
+		if *f.value == nil {
+			continue
+		}
+		v := strings.TrimSpace(**f.value)
+		if v == "" {
+			*f.value = nil
+			continue
+		}
+		if len([]rune(v)) > f.max {
+			return fmt.Errorf("%w: address.%s — не больше %d символов", ErrInvalidAddress, f.name, f.max)
+		}
+		*f.value = &v
+	}
+
+	if (a.Latitude == nil) != (a.Longitude == nil) {
+		return fmt.Errorf("%w: latitude и longitude задаются вместе", ErrInvalidAddress)
+	}
+	if a.HasCoordinates() && !(geo.Point{Lat: *a.Latitude, Lng: *a.Longitude}).Valid() {
+		return fmt.Errorf("%w: %s", ErrInvalidAddress, geo.ErrInvalidPoint.Error())
+	}
+	return nil
+}
+
+// FillCoordinates находит координаты по адресу, если клиент их не прислал.
+// Геокодер — вспомогательный: если адрес не найден или сервис недоступен, объявление
+// сохраняется без координат (и просто не попадёт в поиск по карте).
+func FillCoordinates(ctx context.Context, g geo.Geocoder, a *domain.Address) {
+	if g == nil || a.HasCoordinates() {
+		return
+	}
+	text := a.Text()
+	if text == "" {
+		return
+	}
+
+	p, err := g.Geocode(ctx, text)
+	if err != nil {
+		if !errors.Is(err, geo.ErrAddressNotFound) {
+			log.Printf("geocoding %q failed: %v", text, err)
+		}
+		return
+	}
+	a.Latitude, a.Longitude = &p.Lat, &p.Lng
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/geo"
+)
+
+func strPtr(s string) *string { return &s }
+
+func floatPtr(f float64) *float64 { return &f }
+
+func TestNormalizeAddress(t *testing.T) {
+	a := domain.Address{Line: strPtr("  Тверская, 1 "), City: strPtr("   ")}
+	if err := NormalizeAddress(&a); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if *a.Line != "Тверская, 1" || a.City != nil {
+		t.Fatalf("unexpected address: %+v", a)
+	}
+
+	for name, bad := range map[string]domain.Address{
+		"lat without lng":  {Latitude: floatPtr(55)},
+		"lat out of range": {Latitude: floatPtr(95), Longitude: floatPtr(37)},
+		"postal too long":  {PostalCode: strPtr("123456789012345678901")},
+	} {
+		if err := NormalizeAddress(&bad); !errors.Is(err, ErrInvalidAddress) {
+			t.Fatalf("%s: expected ErrInvalidAddress, got %v", name, err)
+		}
+	}
+}
+
+func TestFillCoordinates(t *testing.T) {
+	g := geo.NewOfflineGeocoder(nil)
+
+	a := domain.Address{Line: strPtr("ул. Неизвестная, 5"), City: strPtr("Казань")}
+	FillCoordinates(context.Background(), g, &a)
+	if !a.HasCoordinates() || *a.Latitude < 55 || *a.Latitude > 56 {
+		t.Fatalf("expected city center coordinates: %+v", a)
+	}
+
+	// координаты клиента не перезаписываются
+	b := domain.Address{City: strPtr("Казань"), Latitude: floatPtr(1), Longitude: floatPtr(2)}
+	FillCoordinates(context.Background(), g, &b)
+	if *b.Latitude != 1 || *b.Longitude != 2 {
+		t.Fatalf("client coordinates must be kept: %+v", b)
+	}
+
+	c := domain.Address{City: strPtr("Атлантида")}
+	FillCoordinates(context.Background(), g, &c)
+	if c.HasCoordinates() {
+		t.Fatalf("unknown address must stay without coordinates: %+v", c)
+	}
+}
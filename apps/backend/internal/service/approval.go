@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"bookinghub-backend/internal/domain"
+)
+
+type approvalRepo interface {
+	ApprovalRules(ctx context.Context, resourceID uint64) (*domain.ApprovalRules, error)
+	RenterStats(ctx context.Context, userID uint64, now time.Time) (*domain.RenterStats, error)
+	AutoApprove(ctx context.Context, bookingID uint64, comment string) (bool, error)
+	ResourceTimezone(ctx context.Context, resourceID uint64) (string, error)
+}
+
+type openingHoursRepo interface {
+	ListOpeningHours(ctx context.Context, resourceID uint64) ([]domain.OpeningHours, error)
+}
+
+// Approver решает, подтверждать ли новую бронь сразу, по режиму подтверждения ресурса
+type Approver struct {
+	repo  approvalRepo
+	hours openingHoursRepo
+	now   func() time.Time
+}
+
+func NewApprover(repo approvalRepo, hours openingHoursRepo) *Approver {
+	return &Approver{repo: repo, hours: hours, now: time.Now}
+}
+
+// Apply подтверждает только что созданную бронь, если это разрешают правила ресурса,
+// и возвращает её итоговый статус. Ошибка автоподтверждения не отменяет бронь:
+// она остаётся в PENDING и ждёт владельца. Без Approver (nil) все брони ждут владельца.
+func (a *Approver) Apply(ctx context.Context, bookingID, userID, resourceID uint64, startAt, endAt time.Time) domain.BookingStatus {
+	if a == nil {
+		return domain.BookingPending
+	}
+	reason, err := a.reason(ctx, userID, resourceID, startAt, endAt)
+	if err != nil {
+		log.Printf("автоподтверждение брони #%d: %v", bookingID, err)
+		return domain.BookingPending
+	}
+	if reason == "" {
+		return domain.BookingPending
+	}
+	ok, err := a.repo.AutoApprove(ctx, bookingID, "Автоподтверждение: "+reason)
+	if err != nil {
+		log.Printf("автоподтверждение брони #%d: %v", bookingID, err)
+		return domain.BookingPending
+	}
+	if !ok {
+		return domain.BookingPending
+	}
+	return domain.BookingApproved
+}
+
+// reason — почему бронь можно подтвердить без владельца; "" — нужно ручное подтверждение.
+// Данные об арендаторе и расписание запрашиваются, только если от них что-то зависит.
+func (a *Approver) reason(ctx context.Context, userID, resourceID uint64, startAt, endAt time.Time) (string, error) {
+	rules, err := a.repo.ApprovalRules(ctx, resourceID)
+	if err != nil || rules == nil {
+		return "", err
+	}
+
+	var stats domain.RenterStats
+	if rules.Mode == domain.ApprovalConditional && (rules.IfVerified || rules.IfReturning) {
+		st, err := a.repo.RenterStats(ctx, userID, a.now())
+		if err != nil {
+			return "", err
+		}
+		if st != nil {
+			stats = *st
+		}
+	}
+	var hours []domain.OpeningHours
+	if rules.Mode == domain.ApprovalConditional && rules.InBusinessHours {
+		if hours, err = a.hours.ListOpeningHours(ctx, resourceID); err != nil {
+			return "", err
+		}
+		// часы работы заданы по местному времени ресурса
+		tz, err := a.repo.ResourceTimezone(ctx, resourceID)
+		if err != nil {
+			return "", err
+		}
+		loc, err := LoadZone(tz)
+		if err != nil {
+			return "", err
+		}
+		startAt, endAt = startAt.In(loc), endAt.In(loc)
+	}
+	return AutoApproveReason(*rules, stats, hours, startAt, endAt), nil
+}
+
+// AutoApproveReason — первое выполненное условие автоподтверждения или "", если бронь
+// должен подтвердить владелец. В режиме CONDITIONAL достаточно любого включённого условия.
+func AutoApproveReason(rules domain.ApprovalRules, stats domain.RenterStats, hours []domain.OpeningHours, startAt, endAt time.Time) string {
+	switch rules.Mode {
+	case domain.ApprovalAuto:
+		return "режим ресурса"
+	case domain.ApprovalConditional:
+	default:
+		return ""
+	}
+
+	if rules.IfVerified && stats.Verified {
+		return "проверенный арендатор"
+	}
+	if rules.IfReturning && stats.CompletedBookings > 0 {
+		return "у арендатора есть завершённые брони"
+	}
+	if rules.MaxHours != nil && endAt.Sub(startAt) <= time.Duration(*rules.MaxHours)*time.Hour {
+		return fmt.Sprintf("бронь не длиннее %d ч", *rules.MaxHours)
+	}
+	if rules.InBusinessHours && withinOpeningHours(startAt, endAt, hours) {
+		return "бронь в часы работы"
+	}
+	return ""
+}
+
+// withinOpeningHours — интервал целиком попадает в часы работы. Без расписания
+// рабочие часы не определены, и условие не выполняется.
+func withinOpeningHours(startAt, endAt time.Time, hours []domain.OpeningHours) bool {
+	if len(hours) == 0 {
+		return false
+	}
+	var open time.Duration
+	for _, iv := range openIntervals(startAt, endAt, hours) {
+		open += iv.end.Sub(iv.start)
+	}
+	return open >= endAt.Sub(startAt)
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bookinghub-backend/internal/domain"
+)
+
+type fakeApprovalRepo struct {
+	rules    domain.ApprovalRules
+	stats    domain.RenterStats
+	timezone string
+	approved []uint64
+	comments []string
+}
+
+func (f *fakeApprovalRepo) ApprovalRules(ctx context.Context, resourceID uint64) (*domain.ApprovalRules, error) {
+	rules := f.rules
+	return &rules, nil
+}
+
+func (f *fakeApprovalRepo) RenterStats(ctx context.Context, userID uint64, now time.Time) (*domain.RenterStats, error) {
+	stats := f.stats
+	return &stats, nil
+}
+
+func (f *fakeApprovalRepo) AutoApprove(ctx context.Context, bookingID uint64, comment string) (bool, error) {
+	f.approved = append(f.approved, bookingID)
+	f.comments = append(f.comments, comment)
+	return true, nil
+}
+
+func (f *fakeApprovalRepo) ResourceTimezone(ctx context.Context, resourceID uint64) (string, error) {
+	return f.timezone, nil
+}
+
+type fakeHours []domain.OpeningHours
+
+func (f fakeHours) ListOpeningHours(ctx context.Context, resourceID uint64) ([]domain.OpeningHours, error) {
+	return f, nil
+}
+
+func TestAutoApproveReason(t *testing.T) {
+	// 2030-01-07 — понедельник
+	start := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+	weekdays := []domain.OpeningHours{{Weekday: 1, OpensAt: "09:00", ClosesAt: "18:00"}}
+	three := 3
+
+	cases := []struct {
+		name  string
+		rules domain.ApprovalRules
+		stats domain.RenterStats
+		hours []domain.OpeningHours
+		end   time.Time
+		want  bool
+	}{
+		{"manual", domain.ApprovalRules{Mode: domain.ApprovalManual, IfVerified: true}, domain.RenterStats{Verified: true}, nil, start.Add(time.Hour), false},
+		{"empty mode is manual", domain.ApprovalRules{}, domain.RenterStats{}, nil, start.Add(time.Hour), false},
+		{"auto", domain.ApprovalRules{Mode: domain.ApprovalAuto}, domain.RenterStats{}, nil, start.Add(time.Hour), true},
+		{"verified", domain.ApprovalRules{Mode: domain.ApprovalConditional, IfVerified: true}, domain.RenterStats{Verified: true}, nil, start.Add(time.Hour), true},
+		{"not verified", domain.ApprovalRules{Mode: domain.ApprovalConditional, IfVerified: true}, domain.RenterStats{CompletedBookings: 2}, nil, start.Add(time.Hour), false},
+		{"returning", domain.ApprovalRules{Mode: domain.ApprovalConditional, IfReturning: true}, domain.RenterStats{CompletedBookings: 1}, nil, start.Add(time.Hour), true},
+		{"short", domain.ApprovalRules{Mode: domain.ApprovalConditional, MaxHours: &three}, domain.RenterStats{}, nil, start.Add(3 * time.Hour), true},
+		{"too long", domain.ApprovalRules{Mode: domain.ApprovalConditional, MaxHours: &three}, domain.RenterStats{}, nil, start.Add(4 * time.Hour), false},
+		{"business hours", domain.ApprovalRules{Mode: domain.ApprovalConditional, InBusinessHours: true}, domain.RenterStats{}, weekdays, start.Add(8 * time.Hour), true},
+		{"after closing", domain.ApprovalRules{Mode: domain.ApprovalConditional, InBusinessHours: true}, domain.RenterStats{}, weekdays, start.Add(9 * time.Hour), false},
+		{"no schedule", domain.ApprovalRules{Mode: domain.ApprovalConditional, InBusinessHours: true}, domain.RenterStats{}, nil, start.Add(time.Hour), false},
+	}
+	for _, c := range cases {
+		if got := AutoApproveReason(c.rules, c.stats, c.hours, start, c.end) != ""; got != c.want {
+			t.Errorf("%s: expected %v got %v", c.name, c.want, got)
+		}
+	}
+}
+
+func TestBookingService_Create_AutoApproved(t *testing.T) {
+	repo := &fakeBookingRepo{
+		busyFn: func(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error) {
+			return false, nil
+		},
+		createFn: func(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time) (uint64, error) {
+			return 42, nil
+		},
+	}
+	approvals := &fakeApprovalRepo{rules: domain.ApprovalRules{Mode: domain.ApprovalConditional, IfReturning: true}}
+	s := NewBookingService(repo).WithApprover(NewApprover(approvals, fakeHours(nil)))
+
+	start := time.Now().Add(24 * time.Hour)
+	_, status, err := s.Create(context.Background(), 1, 1, start, start.Add(time.Hour), 1)
+	if err != nil || status != domain.BookingPending {
+		t.Fatalf("first-time renter: expected PENDING, got %q err=%v", status, err)
+	}
+	if len(approvals.approved) != 0 {
+		t.Fatalf("nothing should be approved, got %v", approvals.approved)
+	}
+
+	approvals.stats.CompletedBookings = 1
+	id, status, err := s.Create(context.Background(), 1, 1, start, start.Add(time.Hour), 1)
+	if err != nil || id != 42 || status != domain.BookingApproved {
+		t.Fatalf("returning renter: expected APPROVED #42, got %q #%d err=%v", status, id, err)
+	}
+	if len(approvals.comments) != 1 || approvals.comments[0] != "Автоподтверждение: у арендатора есть завершённые брони" {
+		t.Fatalf("unexpected history comment %v", approvals.comments)
+	}
+}
+
+func TestApprover_BusinessHoursInResourceZone(t *testing.T) {
+	approvals := &fakeApprovalRepo{
+		rules:    domain.ApprovalRules{Mode: domain.ApprovalConditional, InBusinessHours: true},
+		timezone: "Europe/Moscow",
+	}
+	a := NewApprover(approvals, fakeHours{{Weekday: 1, OpensAt: "09:00", ClosesAt: "18:00"}})
+
+	// 06:00–07:00 UTC в понедельник — это 09:00–10:00 по Москве
+	start := time.Date(2030, 1, 7, 6, 0, 0, 0, time.UTC)
+	if got := a.Apply(context.Background(), 1, 1, 1, start, start.Add(time.Hour)); got != domain.BookingApproved {
+		t.Fatalf("expected APPROVED in Moscow business hours, got %q", got)
+	}
+
+	approvals.timezone = "UTC"
+	if got := a.Apply(context.Background(), 2, 1, 1, start, start.Add(time.Hour)); got != domain.BookingPending {
+		t.Fatalf("expected PENDING before opening in UTC, got %q", got)
+	}
+}
+
+func TestBookingGroupService_Create_AutoApprovesParts(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+	repo := &fakeGroupRepo{}
+	approvals := &fakeApprovalRepo{rules: domain.ApprovalRules{Mode: domain.ApprovalAuto}}
+	s := NewBookingGroupService(repo).WithApprover(NewApprover(approvals, fakeHours(nil)))
+
+	groupID, ids, status, err := s.Create(context.Background(), 1, []domain.BookingItem{
+		{ResourceID: 1, StartAt: start, EndAt: start.Add(time.Hour), Quantity: 1},
+		{ResourceID: 2, StartAt: start, EndAt: start.Add(time.Hour), Quantity: 1},
+	})
+	if err != nil || status != domain.BookingApproved {
+		t.Fatalf("expected APPROVED group, got %q err=%v", status, err)
+	}
+	if len(approvals.approved) != 2 || approvals.approved[0] != ids[0] || approvals.approved[1] != ids[1] {
+		t.Fatalf("every part must pass the approver, approved=%v ids=%v", approvals.approved, ids)
+	}
+	if len(repo.synced) != 1 || repo.synced[0] != groupID {
+		t.Fatalf("group status must be synced after approval, synced=%v", repo.synced)
+	}
+}
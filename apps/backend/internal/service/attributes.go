@@ -0,0 +1,258 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"bookinghub-backend/internal/domain"
+)
+
+const (
+	maxAttributeOptions     = 100
+	maxAttributeValueLength = 255
+	attributeQueryPrefix    = "attr."
+)
+
+var (
+	ErrInvalidAttribute       = errors.New("Некорректное описание атрибута")
+	ErrInvalidAttributeValues = errors.New("Некорректные значения атрибутов")
+	ErrInvalidAttributeFilter = errors.New("Некорректный фильтр по атрибуту")
+)
+
+// код попадает в query-параметры фильтров (attr.<code>.min), поэтому без точек и пробелов
+var attributeCodeRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// ValidateAttributeDefinition нормализует и проверяет описание атрибута категории
+func ValidateAttributeDefinition(a *domain.CategoryAttribute) error {
+	a.Code = strings.TrimSpace(a.Code)
+	a.Name = strings.TrimSpace(a.Name)
+
+	if !attributeCodeRe.MatchString(a.Code) {
+		return fmt.Errorf("%w: code — латиница в нижнем регистре, цифры и _, до 50 символов", ErrInvalidAttribute)
+	}
+	if a.Name == "" || len([]rune(a.Name)) > 100 {
+		return fmt.Errorf("%w: name обязателен, до 100 символов", ErrInvalidAttribute)
+	}
+	if !a.Type.Valid() {
+		return fmt.Errorf("%w: type должен быть int, number, string, bool или enum", ErrInvalidAttribute)
+	}
+	if a.Position < 0 {
+		return fmt.Errorf("%w: position должен быть >= 0", ErrInvalidAttribute)
+	}
+
+	if a.Type != domain.AttrEnum {
+		if len(a.Options) > 0 {
+			return fmt.Errorf("%w: options задаются только для type=enum", ErrInvalidAttribute)
+		}
+		a.Options = domain.StringList{}
+		return nil
+	}
+
+	if len(a.Options) == 0 || len(a.Options) > maxAttributeOptions {
+		return fmt.Errorf("%w: для enum нужен список options (от 1 до %d значений)", ErrInvalidAttribute, maxAttributeOptions)
+	}
+	seen := make(map[string]bool, len(a.Options))
+	for i, opt := range a.Options {
+		opt = strings.TrimSpace(opt)
+		if opt == "" || len(opt) > maxAttributeValueLength {
+			return fmt.Errorf("%w: пустое или слишком длинное значение в options", ErrInvalidAttribute)
+		}
+		if seen[opt] {
+			return fmt.Errorf("%w: значение %q повторяется в options", ErrInvalidAttribute, opt)
+		}
+		seen[opt] = true
+		a.Options[i] = opt
+	}
+	return nil
+}
+
+// ResolveAttributeValues проверяет значения из запроса по схеме категории (с учётом унаследованных
+// атрибутов) и переводит их в строки для хранения. null равносилен отсутствию значения.
+func ResolveAttributeValues(defs []domain.CategoryAttribute, raw map[string]any) ([]domain.AttributeValue, error) {
+	byCode := make(map[string]domain.CategoryAttribute, len(defs))
+	for _, d := range defs {
+		byCode[d.Code] = d
+	}
+
+	codes := make([]string, 0, len(raw))
+	for code := range raw {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if _, ok := byCode[code]; !ok {
+			return nil, fmt.Errorf("%w: у категории нет атрибута %q", ErrInvalidAttributeValues, code)
+		}
+	}
+
+	out := make([]domain.AttributeValue, 0, len(raw))
+	for _, d := range defs {
+		v, present := raw[d.Code]
+		if !present || v == nil {
+			if d.Required {
+				return nil, fmt.Errorf("%w: атрибут %q обязателен", ErrInvalidAttributeValues, d.Code)
+			}
+			continue
+		}
+		val, err := canonicalAttributeValue(d, v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: атрибут %q: %s", ErrInvalidAttributeValues, d.Code, err.Error())
+		}
+		out = append(out, val)
+	}
+	return out, nil
+}
+
+func canonicalAttributeValue(d domain.CategoryAttribute, v any) (domain.AttributeValue, error) {
+	res := domain.AttributeValue{AttributeID: d.ID}
+
+	switch d.Type {
+	case domain.AttrInt, domain.AttrNumber:
+		f, ok := attributeNumber(v)
+		if !ok || math.IsNaN(f) || math.IsInf(f, 0) {
+			return res, errors.New("ожидается число")
+		}
+		if d.Type == domain.AttrInt {
+			if f != math.Trunc(f) || math.Abs(f) > 1<<53 {
+				return res, errors.New("ожидается целое число")
+			}
+			res.Value = strconv.FormatInt(int64(f), 10)
+		} else {
+			res.Value = strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		res.ValueNum = &f
+	case domain.AttrBool:
+		b, ok := v.(bool)
+		if !ok {
+			return res, errors.New("ожидается true или false")
+		}
+		res.Value = strconv.FormatBool(b)
+	case domain.AttrString, domain.AttrEnum:
+		s, ok := v.(string)
+		if !ok {
+			return res, errors.New("ожидается строка")
+		}
+		s = strings.TrimSpace(s)
+		if s == "" || len(s) > maxAttributeValueLength {
+			return res, fmt.Errorf("строка от 1 до %d символов", maxAttributeValueLength)
+		}
+		if d.Type == domain.AttrEnum && !containsString(d.Options, s) {
+			return res, fmt.Errorf("допустимые значения: %s", strings.Join(d.Options, ", "))
+		}
+		res.Value = s
+	default:
+		return res, errors.New("неизвестный тип атрибута")
+	}
+	return res, nil
+}
+
+// attributeNumber: из JSON числа приходят как float64, из уже сохранённых значений — как int64
+func attributeNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAttributeFilters разбирает query-параметры вида
+// attr.<code>=v (можно повторять — любое из значений), attr.<code>.min=n и attr.<code>.max=n
+func ParseAttributeFilters(q url.Values) ([]domain.AttributeFilter, error) {
+	byCode := make(map[string]*domain.AttributeFilter)
+	var order []string
+
+	keys := make([]string, 0, len(q))
+	for key := range q {
+		if strings.HasPrefix(key, attributeQueryPrefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		code, bound, _ := strings.Cut(strings.TrimPrefix(key, attributeQueryPrefix), ".")
+		if !attributeCodeRe.MatchString(code) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidAttributeFilter, key)
+		}
+		f := byCode[code]
+		if f == nil {
+			f = &domain.AttributeFilter{Code: code}
+			byCode[code] = f
+			order = append(order, code)
+		}
+
+		switch bound {
+		case "":
+			for _, v := range q[key] {
+				if v = strings.TrimSpace(v); v != "" {
+					f.Values = append(f.Values, v)
+				}
+			}
+		case "min", "max":
+			n, err := strconv.ParseFloat(strings.TrimSpace(q.Get(key)), 64)
+			if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+				return nil, fmt.Errorf("%w: %s должен быть числом", ErrInvalidAttributeFilter, key)
+			}
+			if bound == "min" {
+				f.Min = &n
+			} else {
+				f.Max = &n
+			}
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrInvalidAttributeFilter, key)
+		}
+	}
+
+	out := make([]domain.AttributeFilter, 0, len(order))
+	for _, code := range order {
+		f := byCode[code]
+		if len(f.Values) == 0 && f.Min == nil && f.Max == nil {
+			continue
+		}
+		out = append(out, *f)
+	}
+	return out, nil
+}
+
+// MatchAttributes сопоставляет атрибуты при слиянии категорий. Атрибут ветки source с тем же кодом
+// и типом, что у атрибута ветки target, переезжает на него; общие атрибуты (у общих предков) остаются
+// как есть; остальные попадают в drop — их значения у перенесённых объявлений теряют смысл.
+func MatchAttributes(source, target []domain.CategoryAttribute) (remap map[uint64]uint64, drop []uint64) {
+	byCode := make(map[string]domain.CategoryAttribute, len(target))
+	for _, t := range target {
+		byCode[t.Code] = t
+	}
+
+	remap = make(map[uint64]uint64)
+	for _, s := range source {
+		t, ok := byCode[s.Code]
+		switch {
+		case ok && t.ID == s.ID:
+			// атрибут общего предка
+		case ok && t.Type == s.Type:
+			remap[s.ID] = t.ID
+		default:
+			drop = append(drop, s.ID)
+		}
+	}
+	return remap, drop
+}
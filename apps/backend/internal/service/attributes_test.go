@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"bookinghub-backend/internal/domain"
+)
+
+func TestValidateAttributeDefinition(t *testing.T) {
+	tests := []struct {
+		name    string
+		attr    domain.CategoryAttribute
+		wantErr bool
+	}{
+		{name: "int", attr: domain.CategoryAttribute{Code: "capacity", Name: "Вместимость", Type: domain.AttrInt}},
+		{name: "enum", attr: domain.CategoryAttribute{Code: "lens_mount", Name: "Байонет", Type: domain.AttrEnum, Options: domain.StringList{"EF", "RF"}}},
+		{name: "bad code", attr: domain.CategoryAttribute{Code: "Lens.Mount", Name: "Байонет", Type: domain.AttrString}, wantErr: true},
+		{name: "unknown type", attr: domain.CategoryAttribute{Code: "x", Name: "X", Type: "date"}, wantErr: true},
+		{name: "enum without options", attr: domain.CategoryAttribute{Code: "x", Name: "X", Type: domain.AttrEnum}, wantErr: true},
+		{name: "duplicate options", attr: domain.CategoryAttribute{Code: "x", Name: "X", Type: domain.AttrEnum, Options: domain.StringList{"A", " A"}}, wantErr: true},
+		{name: "options for int", attr: domain.CategoryAttribute{Code: "x", Name: "X", Type: domain.AttrInt, Options: domain.StringList{"1"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAttributeDefinition(&tt.attr)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("wantErr=%v got %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidAttribute) {
+				t.Fatalf("expected ErrInvalidAttribute, got %v", err)
+			}
+		})
+	}
+}
+
+func TestResolveAttributeValues(t *testing.T) {
+	defs := []domain.CategoryAttribute{
+		{ID: 1, Code: "capacity", Type: domain.AttrInt, Required: true},
+		{ID: 2, Code: "area", Type: domain.AttrNumber},
+		{ID: 3, Code: "projector", Type: domain.AttrBool},
+		{ID: 4, Code: "lens_mount", Type: domain.AttrEnum, Options: domain.StringList{"EF", "RF"}},
+	}
+
+	got, err := ResolveAttributeValues(defs, map[string]any{
+		"capacity":   float64(12),
+		"area":       35.5,
+		"projector":  true,
+		"lens_mount": nil,
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 values, got %+v", got)
+	}
+	if got[0].Value != "12" || *got[0].ValueNum != 12 || got[1].Value != "35.5" || got[2].Value != "true" || got[2].ValueNum != nil {
+		t.Fatalf("unexpected values: %+v", got)
+	}
+
+	bad := []map[string]any{
+		{},                                   // обязательный атрибут не передан
+		{"capacity": 2.5},                    // не целое
+		{"capacity": "12"},                   // строка вместо числа
+		{"capacity": 1.0, "projector": "да"}, // не bool
+		{"capacity": 1.0, "lens_mount": "Z"}, // вне списка
+		{"capacity": 1.0, "color": "red"},    // нет такого атрибута
+	}
+	for _, raw := range bad {
+		if _, err := ResolveAttributeValues(defs, raw); !errors.Is(err, ErrInvalidAttributeValues) {
+			t.Fatalf("expected ErrInvalidAttributeValues for %v, got %v", raw, err)
+		}
+	}
+}
+
+func TestParseAttributeFilters(t *testing.T) {
+	q := url.Values{
+		"attr.capacity.min": {"10"},
+		"attr.capacity.max": {"20"},
+		"attr.lens_mount":   {"EF", "RF"},
+		"page":              {"2"},
+	}
+	got, err := ParseAttributeFilters(q)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 filters, got %+v", got)
+	}
+	if got[0].Code != "capacity" || *got[0].Min != 10 || *got[0].Max != 20 {
+		t.Fatalf("unexpected capacity filter: %+v", got[0])
+	}
+	if got[1].Code != "lens_mount" || len(got[1].Values) != 2 {
+		t.Fatalf("unexpected lens_mount filter: %+v", got[1])
+	}
+
+	for _, bad := range []url.Values{
+		{"attr.capacity.min": {"many"}},
+		{"attr.capacity.avg": {"1"}},
+		{"attr.Bad Code": {"1"}},
+	} {
+		if _, err := ParseAttributeFilters(bad); !errors.Is(err, ErrInvalidAttributeFilter) {
+			t.Fatalf("expected ErrInvalidAttributeFilter for %v, got %v", bad, err)
+		}
+	}
+}
+
+func TestMatchAttributes(t *testing.T) {
+	source := []domain.CategoryAttribute{
+		{ID: 1, Code: "capacity", Type: domain.AttrInt}, // общий предок
+		{ID: 2, Code: "seats", Type: domain.AttrInt},
+		{ID: 3, Code: "color", Type: domain.AttrString},
+		{ID: 4, Code: "area", Type: domain.AttrNumber},
+	}
+	target := []domain.CategoryAttribute{
+		{ID: 1, Code: "capacity", Type: domain.AttrInt},
+		{ID: 7, Code: "seats", Type: domain.AttrInt},
+		{ID: 8, Code: "area", Type: domain.AttrInt}, // другой тип — не переносим
+	}
+
+	remap, drop := MatchAttributes(source, target)
+	if len(remap) != 1 || remap[2] != 7 {
+		t.Fatalf("unexpected remap: %v", remap)
+	}
+	if len(drop) != 2 || drop[0] != 3 || drop[1] != 4 {
+		t.Fatalf("unexpected drop: %v", drop)
+	}
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"errors"
 	"strconv"
 	"time"
@@ -11,16 +13,32 @@ import (
 	"bookinghub-backend/internal/domain"
 )
 
+// ChallengeTTL — сколько действует токен второго шага входа
+const ChallengeTTL = 5 * time.Minute
+
 type AuthService struct {
-	jwtSecret []byte
-	accessTTL time.Duration
+	jwtSecret    []byte
+	challengeKey []byte
+	accessTTL    time.Duration
 }
 
 func NewAuthService(jwtSecret string, accessTTLMinutes int) *AuthService {
-	return &AuthService{
+	s := &AuthService{
 		jwtSecret: []byte(jwtSecret),
 		accessTTL: time.Duration(accessTTLMinutes) * time.Minute,
 	}
+	// токены второго шага подписываются отдельным ключом, производным от JWT_SECRET:
+	// как токен доступа такой токен не пройдёт проверку подписи
+	s.challengeKey = s.DeriveKey("bookinghub 2fa challenge")
+	return s
+}
+
+// DeriveKey — 32-байтный ключ для отдельной цели (purpose), производный от JWT_SECRET.
+// Ключи разных целей независимы: утечка одного не раскрывает ни секрет, ни другие ключи.
+func (s *AuthService) DeriveKey(purpose string) []byte {
+	mac := hmac.New(sha256.New, s.jwtSecret)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
 }
 
 func (s *AuthService) HashPassword(password string) (string, error) {
@@ -66,3 +84,42 @@ func (s *AuthService) ParseAccessToken(tokenStr string) (*AccessClaims, error) {
 	}
 	return claims, nil
 }
+
+// ChallengeClaims — токен второго шага входа: пароль проверен, осталось подтвердить код 2FA.
+// Enroll — 2FA обязательна, но ещё не подключена: код подтверждает подключение.
+type ChallengeClaims struct {
+	UserID uint64 `json:"userId"`
+	Enroll bool   `json:"enroll,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// CreateChallengeToken выпускает токен второго шага на ChallengeTTL
+func (s *AuthService) CreateChallengeToken(userID uint64, enroll bool) (string, time.Time, error) {
+	now := time.Now()
+	expires := now.Add(ChallengeTTL)
+	claims := ChallengeClaims{
+		UserID: userID,
+		Enroll: enroll,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expires),
+			Subject:   strconv.FormatUint(userID, 10),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.challengeKey)
+	return token, expires, err
+}
+
+func (s *AuthService) ParseChallengeToken(tokenStr string) (*ChallengeClaims, error) {
+	t, err := jwt.ParseWithClaims(tokenStr, &ChallengeClaims{}, func(token *jwt.Token) (any, error) {
+		return s.challengeKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := t.Claims.(*ChallengeClaims)
+	if !ok || !t.Valid || claims.UserID == 0 {
+		return nil, errors.New("invalid challenge token")
+	}
+	return claims, nil
+}
@@ -72,3 +72,24 @@ func TestAuthService_ParseToken_WrongSecret(t *testing.T) {
 		t.Fatalf("expected error due to wrong secret")
 	}
 }
+
+func TestAuthService_ChallengeToken_NotInterchangeable(t *testing.T) {
+	s := NewAuthService("test-secret", 15)
+
+	challenge, _, err := s.CreateChallengeToken(7, true)
+	if err != nil {
+		t.Fatalf("create challenge err: %v", err)
+	}
+	claims, err := s.ParseChallengeToken(challenge)
+	if err != nil || claims.UserID != 7 || !claims.Enroll {
+		t.Fatalf("challenge roundtrip: %+v, %v", claims, err)
+	}
+	if _, err := s.ParseAccessToken(challenge); err == nil {
+		t.Fatal("challenge token must not work as an access token")
+	}
+
+	access, _ := s.CreateAccessToken(7, domain.RoleAdmin)
+	if _, err := s.ParseChallengeToken(access); err == nil {
+		t.Fatal("access token must not work as a challenge token")
+	}
+}
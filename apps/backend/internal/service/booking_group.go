@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"bookinghub-backend/internal/domain"
+)
+
+// MaxGroupItems — сколько броней можно оформить одним групповым запросом
+const MaxGroupItems = 20
+
+var ErrInvalidGroup = errors.New("Некорректная групповая бронь")
+
+type bookingGroupRepo interface {
+	bookingRepo
+	CreateGroup(ctx context.Context, userID uint64) (uint64, error)
+	CreateGroupPart(ctx context.Context, groupID, userID uint64, item domain.BookingItem) (uint64, error)
+	SyncGroupStatus(ctx context.Context, groupID uint64) (domain.BookingStatus, error)
+}
+
+type BookingGroupService struct {
+	repo     bookingGroupRepo
+	approver *Approver
+}
+
+func NewBookingGroupService(repo bookingGroupRepo) *BookingGroupService {
+	return &BookingGroupService{repo: repo}
+}
+
+// WithApprover включает автоподтверждение частей по режиму подтверждения их ресурсов
+func (s *BookingGroupService) WithApprover(a *Approver) *BookingGroupService {
+	s.approver = a
+	return s
+}
+
+// Create бронирует несколько ресурсов одной транзакцией: либо создаются все части, либо ни одной.
+// Строки ресурсов блокируются на время проверки. Одиночные брони, брони из удержаний
+// и из очереди берут ту же блокировку, поэтому параллельная бронь не проскочит
+// между проверкой и вставкой. Части, созданные раньше в той же транзакции, учитываются
+// при проверке следующих — два пункта на один ресурс не займут одно и то же время.
+// После фиксации каждая часть проходит автоподтверждение, как одиночная бронь.
+// Возвращает id группы, id броней в порядке items и статус группы.
+func (s *BookingGroupService) Create(ctx context.Context, userID uint64, items []domain.BookingItem) (uint64, []uint64, domain.BookingStatus, error) {
+	if len(items) == 0 || len(items) > MaxGroupItems {
+		return 0, nil, "", fmt.Errorf("%w: от 1 до %d позиций", ErrInvalidGroup, MaxGroupItems)
+	}
+	ids := make([]uint64, 0, len(items))
+	seen := map[uint64]bool{}
+	for i, it := range items {
+		if err := validateBooking(userID, it.ResourceID, it.StartAt, it.EndAt, it.Quantity); err != nil {
+			return 0, nil, "", fmt.Errorf("позиция %d: %w", i+1, err)
+		}
+		if !seen[it.ResourceID] {
+			seen[it.ResourceID] = true
+			ids = append(ids, it.ResourceID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var (
+		groupID    uint64
+		bookingIDs []uint64
+	)
+	err := s.repo.InTx(ctx, func(ctx context.Context) error {
+		locked, err := s.repo.LockResources(ctx, ids)
+		if err != nil {
+			return err
+		}
+		if len(locked) != len(ids) {
+			return ErrResourceNotFound
+		}
+
+		groupID, err = s.repo.CreateGroup(ctx, userID)
+		if err != nil {
+			return err
+		}
+		bookingIDs = make([]uint64, 0, len(items))
+		for _, it := range items {
+			if err := checkFree(ctx, s.repo, it.ResourceID, it.StartAt, it.EndAt, it.Quantity, reservation{}); err != nil {
+				return fmt.Errorf("ресурс #%d: %w", it.ResourceID, err)
+			}
+			id, err := s.repo.CreateGroupPart(ctx, groupID, userID, it)
+			if err != nil {
+				return err
+			}
+			bookingIDs = append(bookingIDs, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, nil, "", err
+	}
+
+	statuses := make([]domain.BookingStatus, len(items))
+	for i, it := range items {
+		statuses[i] = s.approver.Apply(ctx, bookingIDs[i], userID, it.ResourceID, it.StartAt, it.EndAt)
+	}
+	status := domain.GroupStatus(statuses)
+	if status != domain.BookingPending {
+		// как и у Approver, ошибка не отменяет брони: группа остаётся в PENDING
+		if status, err = s.repo.SyncGroupStatus(ctx, groupID); err != nil {
+			log.Printf("статус групповой брони #%d: %v", groupID, err)
+			status = domain.BookingPending
+		}
+	}
+	return groupID, bookingIDs, status, nil
+}
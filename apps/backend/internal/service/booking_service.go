@@ -3,51 +3,152 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
+
+	"bookinghub-backend/internal/domain"
 	// "bookinghub-backend/internal/repo"
 )
 
 var (
-	ErrInvalidTime = errors.New("Некорректный интервал времени")
-	ErrConflict    = errors.New("Выбранное время уже занято")
+	ErrInvalidTime      = errors.New("Некорректный интервал времени")
+	ErrConflict         = errors.New("Выбранное время уже занято")
+	ErrInvalidQuantity  = errors.New("Некорректное количество")
+	ErrResourceNotFound = errors.New("Ресурс не найден")
+	ErrTooShort         = errors.New("Минимальная длительность бронирования: 30 минут")
+	ErrInPast           = errors.New("Нельзя бронировать время в прошлом")
 )
 
 type bookingRepo interface {
-	HasConflict(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error)
-	Create(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time) (uint64, error)
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+	LockResources(ctx context.Context, resourceIDs []uint64) ([]uint64, error)
+	BookingRules(ctx context.Context, resourceID uint64) (*domain.BookingRules, error)
+	ListOverlapping(ctx context.Context, resourceID uint64, from, to time.Time, includePending bool) ([]domain.Booking, error)
+	Create(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time, quantity int) (uint64, error)
+	ListActiveHolds(ctx context.Context, resourceID uint64, from, to, now time.Time) ([]domain.BookingHold, error)
+	ListActiveOffers(ctx context.Context, resourceID uint64, from, to, now time.Time) ([]domain.WaitlistEntry, error)
 }
 
 type BookingService struct {
-	repo bookingRepo
+	repo     bookingRepo
+	approver *Approver
 }
 
 func NewBookingService(repo bookingRepo) *BookingService {
 	return &BookingService{repo: repo}
 }
 
-func (s *BookingService) Create(ctx context.Context, userID, resourceID uint64, startAt, endAt time.Time) (uint64, error) {
+// WithApprover включает автоподтверждение броней по режиму подтверждения ресурса
+func (s *BookingService) WithApprover(a *Approver) *BookingService {
+	s.approver = a
+	return s
+}
+
+// Create бронирует quantity единиц ресурса и возвращает id и статус брони.
+// Новая бронь ждёт владельца (PENDING), если режим подтверждения ресурса
+// не подтвердил её сразу.
+// Конфликт — если в какой-то момент интервала занятые PENDING/APPROVED бронями
+// единицы вместе с новыми превысят вместимость ресурса.
+// Брони занимают ресурс вместе с буферами до и после. Поэтому новая бронь не может
+// начаться внутри чужого буфера, а её буферы — задеть чужую бронь.
+// Строка ресурса блокируется от проверки до вставки, как у групповой брони.
+func (s *BookingService) Create(ctx context.Context, userID, resourceID uint64, startAt, endAt time.Time, quantity int) (uint64, domain.BookingStatus, error) {
+	if err := validateBooking(userID, resourceID, startAt, endAt, quantity); err != nil {
+		return 0, "", err
+	}
+	var id uint64
+	err := s.repo.InTx(ctx, func(ctx context.Context) error {
+		locked, err := s.repo.LockResources(ctx, []uint64{resourceID})
+		if err != nil {
+			return err
+		}
+		if len(locked) == 0 {
+			return ErrResourceNotFound
+		}
+		if err := checkFree(ctx, s.repo, resourceID, startAt, endAt, quantity, reservation{}); err != nil {
+			return err
+		}
+		id, err = s.repo.Create(ctx, resourceID, userID, startAt, endAt, quantity)
+		return err
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	return id, s.approver.Apply(ctx, id, userID, resourceID, startAt, endAt), nil
+}
+
+// validateBooking — проверки брони, не требующие обращения к БД
+func validateBooking(userID, resourceID uint64, startAt, endAt time.Time, quantity int) error {
 	if userID == 0 || resourceID == 0 {
-		return 0, ErrInvalidTime
+		return ErrInvalidTime
+	}
+	if quantity < 1 {
+		return fmt.Errorf("%w: quantity должно быть не меньше 1", ErrInvalidQuantity)
 	}
 	if !endAt.After(startAt) {
-		return 0, ErrInvalidTime
+		return ErrInvalidTime
 	}
 
 	if endAt.Sub(startAt) < 30*time.Minute {
-		return 0, errors.New("Минимальная длительность бронирования: 30 минут")
+		return ErrTooShort
 	}
 
 	if startAt.Before(time.Now().Add(-1 * time.Minute)) {
-		return 0, errors.New("Нельзя бронировать время в прошлом")
+		return ErrInPast
 	}
+	return nil
+}
+
+// reservation — место, из которого создаётся бронь: удержание или предложение из очереди.
+// Оно уже занимает ресурс и не должно мешать самой брони; нулевое значение — брони не из чего.
+type reservation struct {
+	holdID     uint64
+	waitlistID uint64
+}
 
-	conflict, err := s.repo.HasConflict(ctx, resourceID, startAt, endAt)
+// checkFree проверяет, что у ресурса хватает свободных единиц на интервал с учётом буферов.
+// Активные удержания и действующие предложения из очереди занимают место наравне с бронями,
+// кроме own — места, из которого создаётся эта бронь.
+func checkFree(ctx context.Context, repo bookingRepo, resourceID uint64, startAt, endAt time.Time, quantity int, own reservation) error {
+	rules, err := repo.BookingRules(ctx, resourceID)
 	if err != nil {
-		return 0, err
+		return err
+	}
+	if rules == nil {
+		return ErrResourceNotFound
 	}
-	if conflict {
-		return 0, ErrConflict
+	if quantity > rules.Capacity {
+		return fmt.Errorf("%w: у ресурса всего %d ед.", ErrInvalidQuantity, rules.Capacity)
 	}
 
-	return s.repo.Create(ctx, resourceID, userID, startAt, endAt)
+	from, to := AvailabilityWindow(*rules, startAt, endAt)
+	overlapping, err := repo.ListOverlapping(ctx, resourceID, from, to, true)
+	if err != nil {
+		return err
+	}
+	holds, err := repo.ListActiveHolds(ctx, resourceID, from, to, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, h := range holds {
+		if h.ID != own.holdID {
+			overlapping = append(overlapping, h.AsBooking())
+		}
+	}
+	offers, err := repo.ListActiveOffers(ctx, resourceID, from, to, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, e := range offers {
+		if e.ID != own.waitlistID {
+			overlapping = append(overlapping, e.AsBooking())
+		}
+	}
+	if _, free := FreeUnits(*rules, overlapping, startAt, endAt); free < quantity {
+		if rules.Capacity == 1 {
+			return ErrConflict
+		}
+		return fmt.Errorf("%w: свободно %d из %d ед.", ErrConflict, free, rules.Capacity)
+	}
+	return nil
 }
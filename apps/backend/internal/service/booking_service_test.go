@@ -5,25 +5,83 @@ import (
 	"errors"
 	"testing"
 	"time"
+
+	"bookinghub-backend/internal/domain"
 )
 
 type fakeBookingRepo struct {
-	hasConflictFn func(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error)
-	createFn      func(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time) (uint64, error)
+	rules       domain.BookingRules
+	busyFn      func(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error)
+	overlapping []domain.Booking
+	createFn    func(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time) (uint64, error)
+	holds       []domain.BookingHold
+	offers      []domain.WaitlistEntry
+	locked      []uint64
+}
+
+func (f *fakeBookingRepo) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (f *fakeBookingRepo) LockResources(ctx context.Context, resourceIDs []uint64) ([]uint64, error) {
+	f.locked = append(f.locked, resourceIDs...)
+	return resourceIDs, nil
+}
+
+func (f *fakeBookingRepo) BookingRules(ctx context.Context, resourceID uint64) (*domain.BookingRules, error) {
+	rules := f.rules
+	if rules.Capacity == 0 {
+		rules.Capacity = 1
+	}
+	return &rules, nil
+}
+
+// ListOverlapping: без заданных броней busyFn=true означает одну бронь на весь интервал
+func (f *fakeBookingRepo) ListOverlapping(ctx context.Context, resourceID uint64, from, to time.Time, includePending bool) ([]domain.Booking, error) {
+	if f.overlapping != nil {
+		return f.overlapping, nil
+	}
+	conflict, err := f.busyFn(ctx, resourceID, from, to)
+	if err != nil || !conflict {
+		return nil, err
+	}
+	return []domain.Booking{{StartAt: from, EndAt: to, Quantity: 1}}, nil
+}
+
+func (f *fakeBookingRepo) ListActiveHolds(ctx context.Context, resourceID uint64, from, to, now time.Time) ([]domain.BookingHold, error) {
+	var out []domain.BookingHold
+	for _, h := range f.holds {
+		if h.ResourceID == resourceID && h.ExpiresAt.After(now) && h.StartAt.Before(to) && h.EndAt.After(from) {
+			out = append(out, h)
+		}
+	}
+	return out, nil
 }
 
-func (f *fakeBookingRepo) HasConflict(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error) {
-	return f.hasConflictFn(ctx, resourceID, startAt, endAt)
+func (f *fakeBookingRepo) ListActiveOffers(ctx context.Context, resourceID uint64, from, to, now time.Time) ([]domain.WaitlistEntry, error) {
+	return activeOffers(f.offers, resourceID, from, to, now), nil
 }
 
-func (f *fakeBookingRepo) Create(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time) (uint64, error) {
+// activeOffers — действующие предложения ресурса, пересекающиеся с [from, to)
+func activeOffers(entries []domain.WaitlistEntry, resourceID uint64, from, to, now time.Time) []domain.WaitlistEntry {
+	var out []domain.WaitlistEntry
+	for _, e := range entries {
+		if e.ResourceID == resourceID && e.Status == domain.WaitlistOffered && e.ClaimExpiresAt != nil && e.ClaimExpiresAt.After(now) &&
+			e.StartAt.Before(to) && e.EndAt.After(from) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func (f *fakeBookingRepo) Create(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time, quantity int) (uint64, error) {
 	return f.createFn(ctx, resourceID, userID, startAt, endAt)
 }
 
 func TestBookingService_Create_InvalidIDs(t *testing.T) {
 	repo := &fakeBookingRepo{
-		hasConflictFn: func(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error) {
-			t.Fatal("should not call HasConflict")
+		busyFn: func(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error) {
+			t.Fatal("should not check availability")
 			return false, nil
 		},
 		createFn: func(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time) (uint64, error) {
@@ -34,7 +92,7 @@ func TestBookingService_Create_InvalidIDs(t *testing.T) {
 	s := NewBookingService(repo)
 
 	now := time.Now().Add(1 * time.Hour)
-	_, err := s.Create(context.Background(), 0, 1, now, now.Add(time.Hour))
+	_, _, err := s.Create(context.Background(), 0, 1, now, now.Add(time.Hour), 1)
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -42,8 +100,8 @@ func TestBookingService_Create_InvalidIDs(t *testing.T) {
 
 func TestBookingService_Create_InvalidTime_EndNotAfterStart(t *testing.T) {
 	repo := &fakeBookingRepo{
-		hasConflictFn: func(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error) {
-			t.Fatal("should not call HasConflict")
+		busyFn: func(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error) {
+			t.Fatal("should not check availability")
 			return false, nil
 		},
 		createFn: func(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time) (uint64, error) {
@@ -54,7 +112,7 @@ func TestBookingService_Create_InvalidTime_EndNotAfterStart(t *testing.T) {
 	s := NewBookingService(repo)
 
 	now := time.Now().Add(1 * time.Hour)
-	_, err := s.Create(context.Background(), 1, 1, now, now)
+	_, _, err := s.Create(context.Background(), 1, 1, now, now, 1)
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -62,8 +120,8 @@ func TestBookingService_Create_InvalidTime_EndNotAfterStart(t *testing.T) {
 
 func TestBookingService_Create_MinDuration(t *testing.T) {
 	repo := &fakeBookingRepo{
-		hasConflictFn: func(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error) {
-			t.Fatal("should not call HasConflict")
+		busyFn: func(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error) {
+			t.Fatal("should not check availability")
 			return false, nil
 		},
 		createFn: func(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time) (uint64, error) {
@@ -75,7 +133,7 @@ func TestBookingService_Create_MinDuration(t *testing.T) {
 
 	start := time.Now().Add(2 * time.Hour)
 	end := start.Add(10 * time.Minute)
-	_, err := s.Create(context.Background(), 1, 1, start, end)
+	_, _, err := s.Create(context.Background(), 1, 1, start, end, 1)
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -83,8 +141,8 @@ func TestBookingService_Create_MinDuration(t *testing.T) {
 
 func TestBookingService_Create_PastStart(t *testing.T) {
 	repo := &fakeBookingRepo{
-		hasConflictFn: func(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error) {
-			t.Fatal("should not call HasConflict")
+		busyFn: func(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error) {
+			t.Fatal("should not check availability")
 			return false, nil
 		},
 		createFn: func(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time) (uint64, error) {
@@ -96,7 +154,7 @@ func TestBookingService_Create_PastStart(t *testing.T) {
 
 	start := time.Now().Add(-10 * time.Minute)
 	end := time.Now().Add(1 * time.Hour)
-	_, err := s.Create(context.Background(), 1, 1, start, end)
+	_, _, err := s.Create(context.Background(), 1, 1, start, end, 1)
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -104,7 +162,7 @@ func TestBookingService_Create_PastStart(t *testing.T) {
 
 func TestBookingService_Create_Conflict(t *testing.T) {
 	repo := &fakeBookingRepo{
-		hasConflictFn: func(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error) {
+		busyFn: func(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error) {
 			return true, nil
 		},
 		createFn: func(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time) (uint64, error) {
@@ -116,7 +174,7 @@ func TestBookingService_Create_Conflict(t *testing.T) {
 
 	start := time.Now().Add(2 * time.Hour)
 	end := start.Add(1 * time.Hour)
-	_, err := s.Create(context.Background(), 10, 20, start, end)
+	_, _, err := s.Create(context.Background(), 10, 20, start, end, 1)
 	if !errors.Is(err, ErrConflict) {
 		t.Fatalf("expected ErrConflict, got: %v", err)
 	}
@@ -124,11 +182,11 @@ func TestBookingService_Create_Conflict(t *testing.T) {
 
 func TestBookingService_Create_RepoError(t *testing.T) {
 	repo := &fakeBookingRepo{
-		hasConflictFn: func(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error) {
+		busyFn: func(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error) {
 			return false, errors.New("db down")
 		},
 		createFn: func(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time) (uint64, error) {
-			t.Fatal("should not call Create when hasConflict returns error")
+			t.Fatal("should not call Create when availability check fails")
 			return 0, nil
 		},
 	}
@@ -136,7 +194,7 @@ func TestBookingService_Create_RepoError(t *testing.T) {
 
 	start := time.Now().Add(2 * time.Hour)
 	end := start.Add(1 * time.Hour)
-	_, err := s.Create(context.Background(), 1, 1, start, end)
+	_, _, err := s.Create(context.Background(), 1, 1, start, end, 1)
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -144,7 +202,7 @@ func TestBookingService_Create_RepoError(t *testing.T) {
 
 func TestBookingService_Create_OK(t *testing.T) {
 	repo := &fakeBookingRepo{
-		hasConflictFn: func(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error) {
+		busyFn: func(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error) {
 			return false, nil
 		},
 		createFn: func(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time) (uint64, error) {
@@ -158,11 +216,182 @@ func TestBookingService_Create_OK(t *testing.T) {
 
 	start := time.Now().Add(2 * time.Hour)
 	end := start.Add(1 * time.Hour)
-	id, err := s.Create(context.Background(), 22, 11, start, end)
+	id, _, err := s.Create(context.Background(), 22, 11, start, end, 1)
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
 	if id != 777 {
 		t.Fatalf("expected id=777, got %d", id)
 	}
+	if len(repo.locked) != 1 || repo.locked[0] != 11 {
+		t.Fatalf("resource row must be locked before the check, locked=%v", repo.locked)
+	}
+}
+
+func TestBookingService_Create_QuantityWithinCapacity(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour).Truncate(time.Minute)
+	end := start.Add(2 * time.Hour)
+
+	// 10 ноутбуков: 4 заняты в первый час, 3 — во второй; брони не пересекаются, пик — 4
+	repo := &fakeBookingRepo{
+		rules: domain.BookingRules{Capacity: 10},
+		overlapping: []domain.Booking{
+			{StartAt: start, EndAt: start.Add(time.Hour), Quantity: 4},
+			{StartAt: start.Add(time.Hour), EndAt: end, Quantity: 3},
+		},
+		createFn: func(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time) (uint64, error) {
+			return 1, nil
+		},
+	}
+	s := NewBookingService(repo)
+
+	if _, _, err := s.Create(context.Background(), 1, 1, start, end, 6); err != nil {
+		t.Fatalf("6 of 10 units must fit: %v", err)
+	}
+	if _, _, err := s.Create(context.Background(), 1, 1, start, end, 7); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if _, _, err := s.Create(context.Background(), 1, 1, start, end, 11); !errors.Is(err, ErrInvalidQuantity) {
+		t.Fatalf("expected ErrInvalidQuantity, got %v", err)
+	}
+	if _, _, err := s.Create(context.Background(), 1, 1, start, end, 0); !errors.Is(err, ErrInvalidQuantity) {
+		t.Fatalf("expected ErrInvalidQuantity, got %v", err)
+	}
+}
+
+func TestBookingService_Create_OfferedSlotIsBusy(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour).Truncate(time.Minute)
+	end := start.Add(time.Hour)
+	expires := time.Now().Add(10 * time.Minute)
+
+	// слот предложен первому в очереди — прямая бронь не должна его перехватить
+	repo := &fakeBookingRepo{
+		rules:       domain.BookingRules{Capacity: 1},
+		overlapping: []domain.Booking{},
+		offers: []domain.WaitlistEntry{
+			{ID: 4, ResourceID: 1, StartAt: start, EndAt: end, Quantity: 1, Status: domain.WaitlistOffered, ClaimExpiresAt: &expires},
+		},
+		createFn: func(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time) (uint64, error) {
+			t.Fatalf("Create must not be called")
+			return 0, nil
+		},
+	}
+	s := NewBookingService(repo)
+
+	if _, _, err := s.Create(context.Background(), 2, 1, start, end, 1); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+
+	// истёкшее предложение место уже не держит
+	expired := time.Now().Add(-time.Minute)
+	repo.offers[0].ClaimExpiresAt = &expired
+	repo.createFn = func(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time) (uint64, error) {
+		return 1, nil
+	}
+	if _, _, err := s.Create(context.Background(), 2, 1, start, end, 1); err != nil {
+		t.Fatalf("expired offer must not block booking: %v", err)
+	}
+}
+
+func TestBookingService_Create_CannotStartInsideBuffer(t *testing.T) {
+	prev := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+	repo := &fakeBookingRepo{
+		rules:       domain.BookingRules{Capacity: 1, BufferAfterMin: 30},
+		overlapping: []domain.Booking{{StartAt: prev, EndAt: prev.Add(time.Hour), Quantity: 1}},
+		createFn: func(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time) (uint64, error) {
+			return 1, nil
+		},
+	}
+	s := NewBookingService(repo)
+
+	// уборка после предыдущей брони идёт до prev+1:30
+	start := prev.Add(80 * time.Minute)
+	if _, _, err := s.Create(context.Background(), 1, 1, start, start.Add(time.Hour), 1); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	start = prev.Add(90 * time.Minute)
+	if _, _, err := s.Create(context.Background(), 1, 1, start, start.Add(time.Hour), 1); err != nil {
+		t.Fatalf("booking right after cleanup must succeed: %v", err)
+	}
+}
+
+// fakeGroupRepo запоминает созданные части, чтобы следующие позиции группы их видели
+type fakeGroupRepo struct {
+	fakeBookingRepo
+	parts  []domain.Booking
+	synced []uint64
+}
+
+func (f *fakeGroupRepo) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		f.parts = nil
+		return err
+	}
+	return nil
+}
+
+func (f *fakeGroupRepo) CreateGroup(ctx context.Context, userID uint64) (uint64, error) {
+	return 40, nil
+}
+
+func (f *fakeGroupRepo) ListOverlapping(ctx context.Context, resourceID uint64, from, to time.Time, includePending bool) ([]domain.Booking, error) {
+	var out []domain.Booking
+	for _, b := range f.parts {
+		if b.ResourceID == resourceID {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeGroupRepo) CreateGroupPart(ctx context.Context, groupID, userID uint64, it domain.BookingItem) (uint64, error) {
+	f.parts = append(f.parts, domain.Booking{ResourceID: it.ResourceID, StartAt: it.StartAt, EndAt: it.EndAt, Quantity: it.Quantity})
+	return uint64(len(f.parts)), nil
+}
+
+func (f *fakeGroupRepo) SyncGroupStatus(ctx context.Context, groupID uint64) (domain.BookingStatus, error) {
+	f.synced = append(f.synced, groupID)
+	return domain.BookingApproved, nil
+}
+
+func TestBookingGroupService_Create_AllOrNothing(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+	end := start.Add(2 * time.Hour)
+	repo := &fakeGroupRepo{}
+	s := NewBookingGroupService(repo)
+
+	groupID, ids, status, err := s.Create(context.Background(), 1, []domain.BookingItem{
+		{ResourceID: 1, StartAt: start, EndAt: end, Quantity: 1},
+		{ResourceID: 2, StartAt: start, EndAt: end, Quantity: 1},
+	})
+	if err != nil || groupID != 40 || len(ids) != 2 || status != domain.BookingPending {
+		t.Fatalf("unexpected result: group=%d ids=%v status=%q err=%v", groupID, ids, status, err)
+	}
+	if len(repo.synced) != 0 {
+		t.Fatalf("pending group needs no status sync: %v", repo.synced)
+	}
+
+	// второй пункт на тот же зал пересекается с первым из этой же группы
+	repo.parts = nil
+	_, _, _, err = s.Create(context.Background(), 1, []domain.BookingItem{
+		{ResourceID: 1, StartAt: start, EndAt: end, Quantity: 1},
+		{ResourceID: 1, StartAt: start.Add(time.Hour), EndAt: end.Add(time.Hour), Quantity: 1},
+	})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if len(repo.parts) != 0 {
+		t.Fatalf("failed group must not leave parts: %v", repo.parts)
+	}
+}
+
+func TestBookingGroupService_Create_TooManyItems(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour)
+	items := make([]domain.BookingItem, MaxGroupItems+1)
+	for i := range items {
+		items[i] = domain.BookingItem{ResourceID: uint64(i + 1), StartAt: start, EndAt: start.Add(time.Hour), Quantity: 1}
+	}
+	if _, _, _, err := NewBookingGroupService(&fakeGroupRepo{}).Create(context.Background(), 1, items); !errors.Is(err, ErrInvalidGroup) {
+		t.Fatalf("expected ErrInvalidGroup, got %v", err)
+	}
 }
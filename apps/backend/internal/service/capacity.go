@@ -0,0 +1,86 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"bookinghub-backend/internal/domain"
+)
+
+const (
+	// MaxResourceCapacity — верхняя граница единиц под одним объявлением
+	MaxResourceCapacity = 10000
+	// MaxBufferMinutes — буфер до или после брони не длиннее суток
+	MaxBufferMinutes = 24 * 60
+)
+
+// usage — отрезок [start, end), на котором занято постоянное число единиц ресурса
+type usage struct {
+	interval
+	units int
+}
+
+// loadProfile раскладывает брони в непересекающиеся отрезки с суммарной загрузкой.
+// Отрезки без броней пропускаются.
+func loadProfile(bookings []domain.Booking) []usage {
+	type event struct {
+		at    time.Time
+		delta int
+	}
+	events := make([]event, 0, 2*len(bookings))
+	for _, b := range bookings {
+		if b.EndAt.After(b.StartAt) {
+			events = append(events, event{b.StartAt, b.Units()}, event{b.EndAt, -b.Units()})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].at.Before(events[j].at) })
+
+	var out []usage
+	units := 0
+	for i := 0; i < len(events); {
+		at := events[i].at
+		for ; i < len(events) && events[i].at.Equal(at); i++ {
+			units += events[i].delta
+		}
+		if units > 0 && i < len(events) {
+			out = append(out, usage{interval{at, events[i].at}, units})
+		}
+	}
+	return out
+}
+
+// PeakUnits — наибольшее число единиц, занятых одновременно где-либо внутри [from, to).
+// Брони, которые не пересекаются между собой, не складываются.
+func PeakUnits(bookings []domain.Booking, from, to time.Time) int {
+	return peakUnits(loadProfile(bookings), from, to)
+}
+
+func peakUnits(profile []usage, from, to time.Time) int {
+	peak := 0
+	for _, u := range profile {
+		if u.start.Before(to) && u.end.After(from) && u.units > peak {
+			peak = u.units
+		}
+	}
+	return peak
+}
+
+// AvailabilityWindow — интервал, брони из которого могут помешать новой брони [start, end):
+// буферы есть и у новой брони, и у существующих
+func AvailabilityWindow(rules domain.BookingRules, start, end time.Time) (time.Time, time.Time) {
+	gap := rules.BufferBefore() + rules.BufferAfter()
+	return start.Add(-gap), end.Add(gap)
+}
+
+// FreeUnits — сколько единиц занято в пике и сколько свободно для брони [start, end)
+// с учётом буферов: и существующие брони, и новая расширяются на буферы ресурса
+func FreeUnits(rules domain.BookingRules, bookings []domain.Booking, start, end time.Time) (busy, free int) {
+	blocked := make([]domain.Booking, len(bookings))
+	for i, b := range bookings {
+		b.StartAt, b.EndAt = rules.Blocked(b.StartAt, b.EndAt)
+		blocked[i] = b
+	}
+	from, to := rules.Blocked(start, end)
+	busy = PeakUnits(blocked, from, to)
+	return busy, max(rules.Capacity-busy, 0)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"bookinghub-backend/internal/domain"
+)
+
+// maxCategoryDepth — защита от циклов в данных, заведённых в БД в обход API
+const maxCategoryDepth = 32
+
+// BuildCategoryTree собирает дерево из плоского списка; порядок детей — как в списке (по имени)
+func BuildCategoryTree(items []domain.Category) []domain.CategoryNode {
+	children := make(map[uint64][]domain.Category)
+	known := make(map[uint64]bool, len(items))
+	for _, c := range items {
+		known[c.ID] = true
+	}
+
+	var roots []domain.Category
+	for _, c := range items {
+		// категория с несуществующим родителем показывается в корне, а не пропадает
+		if c.ParentID == nil || !known[*c.ParentID] {
+			roots = append(roots, c)
+			continue
+		}
+		children[*c.ParentID] = append(children[*c.ParentID], c)
+	}
+
+	var build func(list []domain.Category, depth int) []domain.CategoryNode
+	build = func(list []domain.Category, depth int) []domain.CategoryNode {
+		nodes := make([]domain.CategoryNode, 0, len(list))
+		for _, c := range list {
+			node := domain.CategoryNode{Category: c, Children: []domain.CategoryNode{}}
+			if depth < maxCategoryDepth {
+				node.Children = build(children[c.ID], depth+1)
+			}
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+	return build(roots, 0)
+}
+
+// CategoryPath — цепочка от корня до категории включительно (хлебные крошки); nil, если категории нет
+func CategoryPath(items []domain.Category, id uint64) []domain.Category {
+	byID := make(map[uint64]domain.Category, len(items))
+	for _, c := range items {
+		byID[c.ID] = c
+	}
+
+	var path []domain.Category
+	cur, ok := byID[id]
+	for ok && len(path) < maxCategoryDepth {
+		path = append(path, cur)
+		if cur.ParentID == nil {
+			break
+		}
+		cur, ok = byID[*cur.ParentID]
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// CategorySubtree — id категории и всех её потомков
+func CategorySubtree(items []domain.Category, id uint64) []uint64 {
+	children := make(map[uint64][]uint64)
+	for _, c := range items {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c.ID)
+		}
+	}
+
+	out := []uint64{id}
+	seen := map[uint64]bool{id: true}
+	for i := 0; i < len(out); i++ {
+		for _, child := range children[out[i]] {
+			if !seen[child] {
+				seen[child] = true
+				out = append(out, child)
+			}
+		}
+	}
+	return out
+}
+
+// CategoryIDs — id категорий из списка (для запроса атрибутов по цепочке)
+func CategoryIDs(items []domain.Category) []uint64 {
+	ids := make([]uint64, len(items))
+	for i, c := range items {
+		ids[i] = c.ID
+	}
+	return ids
+}
+
+// WouldCreateCycle — станет ли категория своим же предком, если перенести её под parentID
+func WouldCreateCycle(items []domain.Category, id, parentID uint64) bool {
+	for _, sub := range CategorySubtree(items, id) {
+		if sub == parentID {
+			return true
+		}
+	}
+	return false
+}
+
+// CategoryArchived — архивирована ли категория или кто-то из её предков
+func CategoryArchived(path []domain.Category) bool {
+	for _, c := range path {
+		if c.ArchivedAt != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveCategories — каталог без архивных категорий и всех их подкатегорий
+func ActiveCategories(items []domain.Category) []domain.Category {
+	hidden := make(map[uint64]bool)
+	for _, c := range items {
+		if c.ArchivedAt == nil || hidden[c.ID] {
+			continue
+		}
+		for _, id := range CategorySubtree(items, c.ID) {
+			hidden[id] = true
+		}
+	}
+
+	out := make([]domain.Category, 0, len(items))
+	for _, c := range items {
+		if !hidden[c.ID] {
+			out = append(out, c)
+		}
+	}
+	return out
+}
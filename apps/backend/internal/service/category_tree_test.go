@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"bookinghub-backend/internal/domain"
+)
+
+func categoryList() []domain.Category {
+	id := func(v uint64) *uint64 { return &v }
+	return []domain.Category{
+		{ID: 1, Name: "Помещения"},
+		{ID: 2, ParentID: id(1), Name: "Переговорные"},
+		{ID: 3, ParentID: id(2), Name: "Большие"},
+		{ID: 4, Name: "Фототехника"},
+	}
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	tree := BuildCategoryTree(categoryList())
+	if len(tree) != 2 {
+		t.Fatalf("expected 2 roots, got %d", len(tree))
+	}
+	if len(tree[0].Children) != 1 || len(tree[0].Children[0].Children) != 1 || tree[0].Children[0].Children[0].ID != 3 {
+		t.Fatalf("unexpected tree: %+v", tree)
+	}
+	if tree[1].Children == nil {
+		t.Fatalf("leaf must have empty children list")
+	}
+}
+
+func TestCategoryPathAndSubtree(t *testing.T) {
+	items := categoryList()
+
+	path := CategoryPath(items, 3)
+	if len(path) != 3 || path[0].ID != 1 || path[2].ID != 3 {
+		t.Fatalf("unexpected path: %+v", path)
+	}
+	if CategoryPath(items, 99) != nil {
+		t.Fatalf("unknown category must give nil path")
+	}
+
+	sub := CategorySubtree(items, 1)
+	if len(sub) != 3 || sub[0] != 1 {
+		t.Fatalf("unexpected subtree: %v", sub)
+	}
+
+	if !WouldCreateCycle(items, 1, 3) || !WouldCreateCycle(items, 2, 2) || WouldCreateCycle(items, 3, 4) {
+		t.Fatalf("cycle detection is wrong")
+	}
+}
+
+func TestActiveCategories_HidesArchivedSubtree(t *testing.T) {
+	items := categoryList()
+	now := time.Now()
+	items[1].ArchivedAt = &now // "Переговорные" в архиве — вместе с "Большие"
+
+	active := ActiveCategories(items)
+	if len(active) != 2 || active[0].ID != 1 || active[1].ID != 4 {
+		t.Fatalf("unexpected active categories: %+v", active)
+	}
+	if !CategoryArchived(CategoryPath(items, 3)) || CategoryArchived(CategoryPath(items, 1)) {
+		t.Fatalf("archived flag must be inherited from ancestors only")
+	}
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"bookinghub-backend/internal/domain"
+)
+
+const (
+	DefaultHoldTTL = 10 * time.Minute
+	MaxHoldTTL     = 30 * time.Minute
+	// DefaultMaxHoldsPerUser — сколько интервалов пользователь может держать одновременно
+	DefaultMaxHoldsPerUser = 3
+)
+
+var (
+	ErrInvalidHoldTTL = errors.New("Некорректный срок удержания")
+	ErrHoldLimit      = errors.New("Слишком много активных удержаний")
+	ErrHoldNotFound   = errors.New("Удержание не найдено или истекло")
+)
+
+type holdRepo interface {
+	bookingRepo
+	CountActiveHolds(ctx context.Context, userID uint64, now time.Time) (int, error)
+	CreateHold(ctx context.Context, h domain.BookingHold) (uint64, error)
+	GetHoldByToken(ctx context.Context, token string) (*domain.BookingHold, error)
+	DeleteHold(ctx context.Context, id uint64) error
+	PurgeExpiredHolds(ctx context.Context, now time.Time) ([]uint64, error)
+}
+
+// slotReleaser — очередь ожидания, которой передаётся освободившееся место
+type slotReleaser interface {
+	Release(ctx context.Context, resourceID uint64) error
+}
+
+type HoldService struct {
+	repo       holdRepo
+	maxPerUser int
+	waitlist   slotReleaser
+	approver   *Approver
+	now        func() time.Time
+}
+
+func NewHoldService(repo holdRepo) *HoldService {
+	return &HoldService{repo: repo, maxPerUser: DefaultMaxHoldsPerUser, now: time.Now}
+}
+
+// WithMaxPerUser задаёт лимит одновременных удержаний на пользователя
+func (s *HoldService) WithMaxPerUser(n int) *HoldService {
+	s.maxPerUser = n
+	return s
+}
+
+// WithWaitlist — истёкшие и снятые удержания освобождают место для очереди ожидания
+func (s *HoldService) WithWaitlist(wl slotReleaser) *HoldService {
+	s.waitlist = wl
+	return s
+}
+
+// WithApprover — бронь из удержания подтверждается по тем же правилам, что и обычная
+func (s *HoldService) WithApprover(a *Approver) *HoldService {
+	s.approver = a
+	return s
+}
+
+// Place удерживает интервал на ttl. Проверки те же, что при бронировании: занятое бронями
+// и чужими удержаниями время удержать нельзя. Лимит на пользователя не даёт занять
+// слоты впрок без намерения бронировать.
+func (s *HoldService) Place(ctx context.Context, userID, resourceID uint64, startAt, endAt time.Time, quantity int, ttl time.Duration) (*domain.BookingHold, error) {
+	if err := validateBooking(userID, resourceID, startAt, endAt, quantity); err != nil {
+		return nil, err
+	}
+	if ttl <= 0 || ttl > MaxHoldTTL {
+		return nil, fmt.Errorf("%w: от 1 до %d минут", ErrInvalidHoldTTL, int(MaxHoldTTL/time.Minute))
+	}
+	token, err := holdToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	hold := domain.BookingHold{
+		Token:      token,
+		ResourceID: resourceID,
+		UserID:     userID,
+		StartAt:    startAt,
+		EndAt:      endAt,
+		Quantity:   quantity,
+		ExpiresAt:  now.Add(ttl),
+		CreatedAt:  now,
+	}
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		if locked, err := s.repo.LockResources(ctx, []uint64{resourceID}); err != nil {
+			return err
+		} else if len(locked) == 0 {
+			return ErrResourceNotFound
+		}
+
+		n, err := s.repo.CountActiveHolds(ctx, userID, now)
+		if err != nil {
+			return err
+		}
+		if n >= s.maxPerUser {
+			return fmt.Errorf("%w: не больше %d одновременно", ErrHoldLimit, s.maxPerUser)
+		}
+
+		if err := checkFree(ctx, s.repo, resourceID, startAt, endAt, quantity, reservation{}); err != nil {
+			return err
+		}
+		hold.ID, err = s.repo.CreateHold(ctx, hold)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// Book превращает удержание в бронь на тот же интервал и количество и возвращает её id и статус.
+// Удержание пользователя не мешает его же брони; после бронирования оно удаляется.
+// resourceID, если задан, должен совпадать с ресурсом удержания.
+func (s *HoldService) Book(ctx context.Context, userID, resourceID uint64, token string) (uint64, domain.BookingStatus, error) {
+	var bookingID uint64
+	var hold *domain.BookingHold
+	err := s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if hold, err = s.activeHold(ctx, userID, token); err != nil {
+			return err
+		}
+		if resourceID != 0 && hold.ResourceID != resourceID {
+			return ErrHoldNotFound
+		}
+		if _, err := s.repo.LockResources(ctx, []uint64{hold.ResourceID}); err != nil {
+			return err
+		}
+		if err := checkFree(ctx, s.repo, hold.ResourceID, hold.StartAt, hold.EndAt, hold.Quantity, reservation{holdID: hold.ID}); err != nil {
+			return err
+		}
+		bookingID, err = s.repo.Create(ctx, hold.ResourceID, userID, hold.StartAt, hold.EndAt, hold.Quantity)
+		if err != nil {
+			return err
+		}
+		return s.repo.DeleteHold(ctx, hold.ID)
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	return bookingID, s.approver.Apply(ctx, bookingID, userID, hold.ResourceID, hold.StartAt, hold.EndAt), nil
+}
+
+// Cancel снимает своё удержание раньше срока
+func (s *HoldService) Cancel(ctx context.Context, userID uint64, token string) error {
+	var hold *domain.BookingHold
+	err := s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if hold, err = s.activeHold(ctx, userID, token); err != nil {
+			return err
+		}
+		return s.repo.DeleteHold(ctx, hold.ID)
+	})
+	if err != nil {
+		return err
+	}
+	s.release(ctx, hold.ResourceID)
+	return nil
+}
+
+func (s *HoldService) activeHold(ctx context.Context, userID uint64, token string) (*domain.BookingHold, error) {
+	if token == "" {
+		return nil, ErrHoldNotFound
+	}
+	hold, err := s.repo.GetHoldByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	// чужое удержание не отличаем от отсутствующего
+	if hold == nil || hold.UserID != userID || !hold.ExpiresAt.After(s.now()) {
+		return nil, ErrHoldNotFound
+	}
+	return hold, nil
+}
+
+// Purge удаляет истёкшие удержания
+func (s *HoldService) Purge(ctx context.Context) error {
+	resourceIDs, err := s.repo.PurgeExpiredHolds(ctx, s.now())
+	if err != nil {
+		return err
+	}
+	for _, id := range resourceIDs {
+		s.release(ctx, id)
+	}
+	return nil
+}
+
+// Run вызывает Purge каждые every, пока не отменён ctx
+func (s *HoldService) Run(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := s.Purge(ctx); err != nil {
+				log.Printf("holds purge failed: %v", err)
+			}
+		}
+	}
+}
+
+func (s *HoldService) release(ctx context.Context, resourceID uint64) {
+	if s.waitlist == nil {
+		return
+	}
+	if err := s.waitlist.Release(ctx, resourceID); err != nil {
+		log.Printf("waitlist release for resource %d failed: %v", resourceID, err)
+	}
+}
+
+func holdToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"bookinghub-backend/internal/domain"
+)
+
+// fakeHoldRepo хранит удержания в памяти поверх fakeBookingRepo
+type fakeHoldRepo struct {
+	fakeBookingRepo
+	created []uint64
+}
+
+func (f *fakeHoldRepo) CountActiveHolds(ctx context.Context, userID uint64, now time.Time) (int, error) {
+	n := 0
+	for _, h := range f.holds {
+		if h.UserID == userID && h.ExpiresAt.After(now) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeHoldRepo) CreateHold(ctx context.Context, h domain.BookingHold) (uint64, error) {
+	h.ID = uint64(len(f.holds) + 1)
+	f.holds = append(f.holds, h)
+	return h.ID, nil
+}
+
+func (f *fakeHoldRepo) GetHoldByToken(ctx context.Context, token string) (*domain.BookingHold, error) {
+	for _, h := range f.holds {
+		if h.Token == token {
+			return &h, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeHoldRepo) DeleteHold(ctx context.Context, id uint64) error {
+	for i, h := range f.holds {
+		if h.ID == id {
+			f.holds = append(f.holds[:i], f.holds[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (f *fakeHoldRepo) PurgeExpiredHolds(ctx context.Context, now time.Time) ([]uint64, error) {
+	return nil, nil
+}
+
+func newFakeHoldRepo() *fakeHoldRepo {
+	f := &fakeHoldRepo{}
+	f.busyFn = func(ctx context.Context, resourceID uint64, startAt, endAt time.Time) (bool, error) {
+		return false, nil
+	}
+	f.createFn = func(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time) (uint64, error) {
+		f.created = append(f.created, resourceID)
+		return uint64(len(f.created)), nil
+	}
+	return f
+}
+
+func TestHoldService_HoldBlocksOthersButNotOwner(t *testing.T) {
+	repo := newFakeHoldRepo()
+	s := NewHoldService(repo)
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+	end := start.Add(time.Hour)
+
+	hold, err := s.Place(context.Background(), 1, 5, start, end, 1, DefaultHoldTTL)
+	if err != nil {
+		t.Fatalf("place: %v", err)
+	}
+	if len(hold.Token) != 64 {
+		t.Fatalf("unexpected token %q", hold.Token)
+	}
+
+	// пока удержание действует, время занято для других
+	if _, _, err := NewBookingService(repo).Create(context.Background(), 2, 5, start, end, 1); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if _, err := s.Place(context.Background(), 2, 5, start, end, 1, DefaultHoldTTL); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict for second hold, got %v", err)
+	}
+
+	if _, _, err := s.Book(context.Background(), 2, 0, hold.Token); !errors.Is(err, ErrHoldNotFound) {
+		t.Fatalf("foreign hold must not be usable, got %v", err)
+	}
+	if _, _, err := s.Book(context.Background(), 1, 6, hold.Token); !errors.Is(err, ErrHoldNotFound) {
+		t.Fatalf("hold for another resource must not be usable, got %v", err)
+	}
+	if _, _, err := s.Book(context.Background(), 1, 5, hold.Token); err != nil {
+		t.Fatalf("owner must book from own hold: %v", err)
+	}
+	if len(repo.holds) != 0 || len(repo.created) != 1 {
+		t.Fatalf("hold must turn into one booking: holds=%v created=%v", repo.holds, repo.created)
+	}
+}
+
+func TestHoldService_PerUserLimit(t *testing.T) {
+	repo := newFakeHoldRepo()
+	s := NewHoldService(repo).WithMaxPerUser(2)
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+
+	for i := 0; i < 2; i++ {
+		from := start.Add(time.Duration(i) * 2 * time.Hour)
+		if _, err := s.Place(context.Background(), 1, 5, from, from.Add(time.Hour), 1, DefaultHoldTTL); err != nil {
+			t.Fatalf("place %d: %v", i, err)
+		}
+	}
+	from := start.Add(10 * time.Hour)
+	if _, err := s.Place(context.Background(), 1, 5, from, from.Add(time.Hour), 1, DefaultHoldTTL); !errors.Is(err, ErrHoldLimit) {
+		t.Fatalf("expected ErrHoldLimit, got %v", err)
+	}
+	if _, err := s.Place(context.Background(), 1, 5, from, from.Add(time.Hour), 1, MaxHoldTTL+time.Minute); !errors.Is(err, ErrInvalidHoldTTL) {
+		t.Fatalf("expected ErrInvalidHoldTTL, got %v", err)
+	}
+}
+
+func TestHoldService_ExpiredHoldIsFree(t *testing.T) {
+	repo := newFakeHoldRepo()
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+	repo.holds = []domain.BookingHold{{ID: 1, Token: "t", ResourceID: 5, UserID: 1, StartAt: start, EndAt: start.Add(time.Hour), Quantity: 1, ExpiresAt: time.Now().Add(-time.Minute)}}
+
+	if _, _, err := NewBookingService(repo).Create(context.Background(), 2, 5, start, start.Add(time.Hour), 1); err != nil {
+		t.Fatalf("expired hold must not block: %v", err)
+	}
+	if _, _, err := NewHoldService(repo).Book(context.Background(), 1, 5, "t"); !errors.Is(err, ErrHoldNotFound) {
+		t.Fatalf("expected ErrHoldNotFound, got %v", err)
+	}
+}
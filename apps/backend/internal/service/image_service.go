@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/imaging"
+	"bookinghub-backend/internal/storage"
+)
+
+const (
+	DefaultMaxImageBytes    = 10 << 20 // 10 МБ на файл
+	DefaultMaxImagesPerItem = 20
+	thumbnailMaxSide        = 320
+	thumbnailJPEGQuality    = 80
+)
+
+var (
+	ErrImageTooLarge   = errors.New("Файл слишком большой")
+	ErrImageType       = errors.New("Поддерживаются только JPEG, PNG и GIF")
+	ErrTooManyImages   = errors.New("Достигнут лимит фото для объявления")
+	ErrImageUnreadable = errors.New("Не удалось прочитать изображение")
+)
+
+// allowedImageTypes — тип по сигнатуре файла (http.DetectContentType) -> расширение
+var allowedImageTypes = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+type resourceImageRepo interface {
+	CountByResource(ctx context.Context, resourceID uint64) (int, error)
+	Create(ctx context.Context, img *domain.ResourceImage) (uint64, error)
+	Delete(ctx context.Context, resourceID, imageID uint64, wasCover bool) error
+}
+
+type ImageService struct {
+	repo       resourceImageRepo
+	blobs      storage.BlobStore
+	maxBytes   int64
+	maxPerItem int
+}
+
+func NewImageService(repo resourceImageRepo, blobs storage.BlobStore, maxBytes int64, maxPerItem int) *ImageService {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxImageBytes
+	}
+	if maxPerItem <= 0 {
+		maxPerItem = DefaultMaxImagesPerItem
+	}
+	return &ImageService{repo: repo, blobs: blobs, maxBytes: maxBytes, maxPerItem: maxPerItem}
+}
+
+func (s *ImageService) MaxBytes() int64 {
+	return s.maxBytes
+}
+
+// Upload проверяет файл, кладёт оригинал и превью в хранилище и сохраняет запись о фото
+func (s *ImageService) Upload(ctx context.Context, resourceID uint64, data []byte) (*domain.ResourceImage, error) {
+	if int64(len(data)) > s.maxBytes {
+		return nil, ErrImageTooLarge
+	}
+
+	// тип определяем по содержимому, а не по имени файла и заголовку клиента
+	contentType := http.DetectContentType(data)
+	ext, ok := allowedImageTypes[contentType]
+	if !ok {
+		return nil, ErrImageType
+	}
+
+	cnt, err := s.repo.CountByResource(ctx, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	if cnt >= s.maxPerItem {
+		return nil, ErrTooManyImages
+	}
+
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrImageUnreadable, err)
+	}
+	thumb, err := imaging.EncodeJPEG(imaging.Thumbnail(img, thumbnailMaxSide), thumbnailJPEGQuality)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+	rec := &domain.ResourceImage{
+		ResourceID:  resourceID,
+		StorageKey:  fmt.Sprintf("resources/%d/%s.%s", resourceID, name, ext),
+		ThumbKey:    fmt.Sprintf("resources/%d/%s_thumb.jpg", resourceID, name),
+		ContentType: contentType,
+		SizeBytes:   int64(len(data)),
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	}
+
+	if err := s.blobs.Put(ctx, rec.StorageKey, data, contentType); err != nil {
+		return nil, err
+	}
+	if err := s.blobs.Put(ctx, rec.ThumbKey, thumb, "image/jpeg"); err != nil {
+		s.cleanup(ctx, rec.StorageKey)
+		return nil, err
+	}
+	if _, err := s.repo.Create(ctx, rec); err != nil {
+		s.cleanup(ctx, rec.StorageKey, rec.ThumbKey)
+		return nil, err
+	}
+	return rec, nil
+}
+
+// Delete удаляет запись о фото и файлы; ошибка удаления файла не откатывает удаление записи
+func (s *ImageService) Delete(ctx context.Context, img *domain.ResourceImage) error {
+	if err := s.repo.Delete(ctx, img.ResourceID, img.ID, img.IsCover); err != nil {
+		return err
+	}
+	s.cleanup(ctx, img.StorageKey, img.ThumbKey)
+	return nil
+}
+
+func (s *ImageService) cleanup(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			log.Printf("failed to delete blob %s: %v", key, err)
+		}
+	}
+}
+
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/storage"
+)
+
+type fakeImageRepo struct {
+	count     int
+	createErr error
+	created   []*domain.ResourceImage
+}
+
+func (f *fakeImageRepo) CountByResource(ctx context.Context, resourceID uint64) (int, error) {
+	return f.count, nil
+}
+
+func (f *fakeImageRepo) Create(ctx context.Context, img *domain.ResourceImage) (uint64, error) {
+	if f.createErr != nil {
+		return 0, f.createErr
+	}
+	f.created = append(f.created, img)
+	img.ID = uint64(len(f.created))
+	return img.ID, nil
+}
+
+func (f *fakeImageRepo) Delete(ctx context.Context, resourceID, imageID uint64, wasCover bool) error {
+	return nil
+}
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	img.Set(0, 0, color.White)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func filesIn(t *testing.T, dir string) []string {
+	t.Helper()
+	var out []string
+	_ = filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			out = append(out, p)
+		}
+		return nil
+	})
+	return out
+}
+
+func TestImageService_Upload_StoresOriginalAndThumbnail(t *testing.T) {
+	dir := t.TempDir()
+	repo := &fakeImageRepo{}
+	s := NewImageService(repo, storage.NewLocalStore(dir, "/uploads"), 0, 0)
+
+	img, err := s.Upload(context.Background(), 3, testPNG(t, 640, 480))
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if img.ContentType != "image/png" || img.Width != 640 || img.Height != 480 {
+		t.Fatalf("unexpected image: %+v", img)
+	}
+	if filepath.Ext(img.StorageKey) != ".png" || filepath.Ext(img.ThumbKey) != ".jpg" {
+		t.Fatalf("unexpected keys: %s %s", img.StorageKey, img.ThumbKey)
+	}
+	if got := len(filesIn(t, dir)); got != 2 {
+		t.Fatalf("expected original and thumbnail on disk, got %d files", got)
+	}
+}
+
+func TestImageService_Upload_Validation(t *testing.T) {
+	s := NewImageService(&fakeImageRepo{}, storage.NewLocalStore(t.TempDir(), "/uploads"), 1024, 0)
+	if _, err := s.Upload(context.Background(), 3, []byte("%PDF-1.4 not an image")); !errors.Is(err, ErrImageType) {
+		t.Fatalf("expected ErrImageType, got %v", err)
+	}
+	if _, err := s.Upload(context.Background(), 3, make([]byte, 2048)); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("expected ErrImageTooLarge, got %v", err)
+	}
+
+	full := NewImageService(&fakeImageRepo{count: 2}, storage.NewLocalStore(t.TempDir(), "/uploads"), 0, 2)
+	if _, err := full.Upload(context.Background(), 3, testPNG(t, 2, 2)); !errors.Is(err, ErrTooManyImages) {
+		t.Fatalf("expected ErrTooManyImages, got %v", err)
+	}
+}
+
+func TestImageService_Upload_CleansUpBlobsOnDBError(t *testing.T) {
+	dir := t.TempDir()
+	s := NewImageService(&fakeImageRepo{createErr: errors.New("db down")}, storage.NewLocalStore(dir, "/uploads"), 0, 0)
+
+	if _, err := s.Upload(context.Background(), 3, testPNG(t, 10, 10)); err == nil {
+		t.Fatalf("expected error")
+	}
+	if files := filesIn(t, dir); len(files) != 0 {
+		t.Fatalf("blobs must be removed, left: %v", files)
+	}
+}
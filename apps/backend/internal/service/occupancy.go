@@ -0,0 +1,262 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"bookinghub-backend/internal/domain"
+)
+
+type OccupancyBucket string
+
+const (
+	BucketHour OccupancyBucket = "hour"
+	BucketDay  OccupancyBucket = "day"
+)
+
+// Максимальная длина периода, чтобы ответ оставался разумного размера
+const (
+	maxHourBucketsDays = 31
+	maxDayBucketsDays  = 366
+)
+
+var ErrInvalidOpeningHours = errors.New("Некорректное расписание работы")
+
+// OccupancyCell — занятость одного интервала: сколько минут забронировано из доступных.
+// Для ресурса из нескольких единиц минуты считаются по каждой единице.
+type OccupancyCell struct {
+	Start            time.Time `json:"start"`
+	End              time.Time `json:"end"`
+	Weekday          int       `json:"weekday"` // 1 = понедельник ... 7 = воскресенье
+	BookedMinutes    int       `json:"bookedMinutes"`
+	AvailableMinutes int       `json:"availableMinutes"`
+	OccupancyPercent float64   `json:"occupancyPercent"`
+	PeakUnits        int       `json:"peakUnits"`      // максимум единиц, занятых одновременно
+	RemainingUnits   int       `json:"remainingUnits"` // сколько единиц свободно на весь интервал
+}
+
+// HeatmapCell — сумма по всем неделям периода для пары (день недели, час)
+type HeatmapCell struct {
+	Weekday          int     `json:"weekday"`
+	Hour             int     `json:"hour"`
+	BookedMinutes    int     `json:"bookedMinutes"`
+	AvailableMinutes int     `json:"availableMinutes"`
+	OccupancyPercent float64 `json:"occupancyPercent"`
+}
+
+type Occupancy struct {
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+	Bucket   OccupancyBucket `json:"bucket"`
+	Capacity int             `json:"capacity"`
+	Buckets  []OccupancyCell `json:"buckets"`
+	Heatmap  []HeatmapCell   `json:"heatmap,omitempty"` // 7×24, только для bucket=hour
+}
+
+type interval struct {
+	start, end time.Time
+}
+
+// ParseClock разбирает "HH:MM" в минуты от начала дня; "24:00" допустимо
+func ParseClock(s string) (int, error) {
+	hh, mm, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok || len(hh) != 2 || len(mm) != 2 {
+		return 0, fmt.Errorf("время %q должно быть в формате HH:MM", s)
+	}
+	h, err1 := strconv.Atoi(hh)
+	m, err2 := strconv.Atoi(mm)
+	if err1 != nil || err2 != nil || h < 0 || h > 24 || m < 0 || m > 59 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("время %q должно быть в формате HH:MM", s)
+	}
+	return h*60 + m, nil
+}
+
+// ValidateOpeningHours проверяет дни недели, формат времени и пересечения интервалов внутри дня
+func ValidateOpeningHours(hours []domain.OpeningHours) error {
+	byDay := make(map[int][][2]int)
+	for _, h := range hours {
+		if h.Weekday < 1 || h.Weekday > 7 {
+			return fmt.Errorf("%w: weekday должен быть от 1 до 7", ErrInvalidOpeningHours)
+		}
+		open, err := ParseClock(h.OpensAt)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidOpeningHours, err)
+		}
+		closeAt, err := ParseClock(h.ClosesAt)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidOpeningHours, err)
+		}
+		if open >= closeAt {
+			return fmt.Errorf("%w: opensAt должно быть раньше closesAt", ErrInvalidOpeningHours)
+		}
+		byDay[h.Weekday] = append(byDay[h.Weekday], [2]int{open, closeAt})
+	}
+
+	for _, spans := range byDay {
+		sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+		for i := 1; i < len(spans); i++ {
+			if spans[i][0] < spans[i-1][1] {
+				return fmt.Errorf("%w: интервалы одного дня пересекаются", ErrInvalidOpeningHours)
+			}
+		}
+	}
+	return nil
+}
+
+// MaxOccupancyRange — допустимая длина периода для размера интервала
+func MaxOccupancyRange(bucket OccupancyBucket) time.Duration {
+	if bucket == BucketHour {
+		return maxHourBucketsDays * 24 * time.Hour
+	}
+	return maxDayBucketsDays * 24 * time.Hour
+}
+
+// ComputeOccupancy раскладывает брони по интервалам [from, to) и сравнивает их с часами работы.
+// Забронированные минуты считаются только внутри часов работы и умножаются на занятые единицы,
+// но не больше capacity — пересечения броней сверх вместимости не удваиваются.
+// Расписание должно быть провалидировано; пустое расписание — ресурс открыт круглосуточно.
+func ComputeOccupancy(from, to time.Time, bucket OccupancyBucket, hours []domain.OpeningHours, capacity int, bookings []domain.Booking) *Occupancy {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	profile := loadProfile(bookings)
+	out := &Occupancy{From: from, To: to, Bucket: bucket, Capacity: capacity, Buckets: make([]OccupancyCell, 0)}
+
+	var heat map[[2]int]*HeatmapCell
+	if bucket == BucketHour {
+		heat = make(map[[2]int]*HeatmapCell, 7*24)
+	}
+
+	for start := from; start.Before(to); start = nextBucket(start, bucket) {
+		end := nextBucket(start, bucket)
+		if end.After(to) {
+			end = to
+		}
+
+		open := openIntervals(start, end, hours)
+		available, used := 0, 0
+		for _, o := range open {
+			available += minutes(o.start, o.end) * capacity
+			for _, u := range profile {
+				used += overlapMinutes(o, u.interval) * min(u.units, capacity)
+			}
+		}
+		peak := peakUnits(profile, start, end)
+
+		cell := OccupancyCell{
+			Start:            start,
+			End:              end,
+			Weekday:          isoWeekday(start),
+			BookedMinutes:    used,
+			AvailableMinutes: available,
+			OccupancyPercent: percent(used, available),
+			PeakUnits:        peak,
+			RemainingUnits:   max(capacity-peak, 0),
+		}
+		out.Buckets = append(out.Buckets, cell)
+
+		if heat != nil {
+			key := [2]int{cell.Weekday, start.Hour()}
+			hc := heat[key]
+			if hc == nil {
+				hc = &HeatmapCell{Weekday: key[0], Hour: key[1]}
+				heat[key] = hc
+			}
+			hc.BookedMinutes += used
+			hc.AvailableMinutes += available
+		}
+	}
+
+	if heat != nil {
+		out.Heatmap = make([]HeatmapCell, 0, 7*24)
+		for wd := 1; wd <= 7; wd++ {
+			for h := 0; h < 24; h++ {
+				hc := HeatmapCell{Weekday: wd, Hour: h}
+				if v := heat[[2]int{wd, h}]; v != nil {
+					hc = *v
+				}
+				hc.OccupancyPercent = percent(hc.BookedMinutes, hc.AvailableMinutes)
+				out.Heatmap = append(out.Heatmap, hc)
+			}
+		}
+	}
+	return out
+}
+
+// nextBucket — начало следующего интервала. Дневной интервал — календарный день пояса from,
+// поэтому в дни перевода часов он длится 23 или 25 часов.
+func nextBucket(start time.Time, bucket OccupancyBucket) time.Time {
+	if bucket == BucketHour {
+		return start.Add(time.Hour)
+	}
+	return NextDay(start)
+}
+
+// openIntervals — часы работы, попадающие в [start, end); дни и часы считаются в поясе start
+func openIntervals(start, end time.Time, hours []domain.OpeningHours) []interval {
+	if len(hours) == 0 {
+		return []interval{{start, end}}
+	}
+
+	var out []interval
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	for ; day.Before(end); day = NextDay(day) {
+		wd := isoWeekday(day)
+		for _, h := range hours {
+			if h.Weekday != wd {
+				continue
+			}
+			openMin, _ := ParseClock(h.OpensAt)
+			closeMin, _ := ParseClock(h.ClosesAt)
+			// часы работы — местное время: в день перевода часов от полуночи до открытия
+			// проходит не openMin минут, поэтому считаем через time.Date
+			iv := clip(interval{
+				start: time.Date(day.Year(), day.Month(), day.Day(), 0, openMin, 0, 0, day.Location()),
+				end:   time.Date(day.Year(), day.Month(), day.Day(), 0, closeMin, 0, 0, day.Location()),
+			}, start, end)
+			if iv.end.After(iv.start) {
+				out = append(out, iv)
+			}
+		}
+	}
+	return out
+}
+
+func clip(iv interval, start, end time.Time) interval {
+	if iv.start.Before(start) {
+		iv.start = start
+	}
+	if iv.end.After(end) {
+		iv.end = end
+	}
+	return iv
+}
+
+func overlapMinutes(a, b interval) int {
+	iv := clip(b, a.start, a.end)
+	if !iv.end.After(iv.start) {
+		return 0
+	}
+	return minutes(iv.start, iv.end)
+}
+
+func minutes(start, end time.Time) int {
+	return int(end.Sub(start) / time.Minute)
+}
+
+func isoWeekday(t time.Time) int {
+	return (int(t.Weekday())+6)%7 + 1
+}
+
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
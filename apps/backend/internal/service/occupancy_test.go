@@ -0,0 +1,173 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"bookinghub-backend/internal/domain"
+)
+
+func TestValidateOpeningHours(t *testing.T) {
+	tests := []struct {
+		name    string
+		hours   []domain.OpeningHours
+		wantErr bool
+	}{
+		{name: "empty is 24/7", hours: nil},
+		{name: "ok with lunch break", hours: []domain.OpeningHours{
+			{Weekday: 1, OpensAt: "09:00", ClosesAt: "13:00"},
+			{Weekday: 1, OpensAt: "14:00", ClosesAt: "24:00"},
+		}},
+		{name: "bad weekday", hours: []domain.OpeningHours{{Weekday: 0, OpensAt: "09:00", ClosesAt: "18:00"}}, wantErr: true},
+		{name: "bad clock", hours: []domain.OpeningHours{{Weekday: 1, OpensAt: "9:00", ClosesAt: "18:00"}}, wantErr: true},
+		{name: "24:30", hours: []domain.OpeningHours{{Weekday: 1, OpensAt: "09:00", ClosesAt: "24:30"}}, wantErr: true},
+		{name: "opens after closes", hours: []domain.OpeningHours{{Weekday: 1, OpensAt: "18:00", ClosesAt: "09:00"}}, wantErr: true},
+		{name: "overlap", hours: []domain.OpeningHours{
+			{Weekday: 2, OpensAt: "09:00", ClosesAt: "13:00"},
+			{Weekday: 2, OpensAt: "12:00", ClosesAt: "15:00"},
+		}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOpeningHours(tt.hours)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("wantErr=%v got %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidOpeningHours) {
+				t.Fatalf("expected ErrInvalidOpeningHours, got %v", err)
+			}
+		})
+	}
+}
+
+func TestComputeOccupancy_HourBucketsWithOpeningHours(t *testing.T) {
+	// 2026-01-05 — понедельник
+	from := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	hours := []domain.OpeningHours{{Weekday: 1, OpensAt: "09:00", ClosesAt: "12:30"}}
+	bookings := []domain.Booking{
+		// частично до открытия: считается только время в часы работы
+		{StartAt: from.Add(8 * time.Hour), EndAt: from.Add(9*time.Hour + 30*time.Minute)},
+		// пересекается с предыдущей — не удваиваем
+		{StartAt: from.Add(9 * time.Hour), EndAt: from.Add(10 * time.Hour)},
+		{StartAt: from.Add(12 * time.Hour), EndAt: from.Add(13 * time.Hour)},
+	}
+
+	occ := ComputeOccupancy(from, to, BucketHour, hours, 1, bookings)
+	if len(occ.Buckets) != 24 {
+		t.Fatalf("expected 24 buckets got %d", len(occ.Buckets))
+	}
+
+	check := func(hour, booked, available int) {
+		t.Helper()
+		c := occ.Buckets[hour]
+		if c.BookedMinutes != booked || c.AvailableMinutes != available {
+			t.Fatalf("hour %d: want %d/%d got %d/%d", hour, booked, available, c.BookedMinutes, c.AvailableMinutes)
+		}
+	}
+	check(8, 0, 0)
+	check(9, 60, 60)
+	check(10, 0, 60)
+	check(12, 30, 30)
+	check(13, 0, 0)
+
+	if occ.Buckets[12].OccupancyPercent != 100 || occ.Buckets[10].OccupancyPercent != 0 {
+		t.Fatalf("unexpected percents: %+v %+v", occ.Buckets[12], occ.Buckets[10])
+	}
+
+	if len(occ.Heatmap) != 7*24 {
+		t.Fatalf("expected 7x24 heatmap got %d", len(occ.Heatmap))
+	}
+	if hc := occ.Heatmap[9]; hc.Weekday != 1 || hc.Hour != 9 || hc.BookedMinutes != 60 {
+		t.Fatalf("unexpected heatmap cell: %+v", hc)
+	}
+}
+
+func TestComputeOccupancy_DayBucketsAroundTheClock(t *testing.T) {
+	from := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 2)
+	bookings := []domain.Booking{
+		{StartAt: from.Add(22 * time.Hour), EndAt: from.Add(26 * time.Hour)},
+	}
+
+	occ := ComputeOccupancy(from, to, BucketDay, nil, 1, bookings)
+	if len(occ.Buckets) != 2 || occ.Heatmap != nil {
+		t.Fatalf("unexpected result: %+v", occ)
+	}
+	if occ.Buckets[0].BookedMinutes != 120 || occ.Buckets[1].BookedMinutes != 120 {
+		t.Fatalf("booking must be split across days: %+v", occ.Buckets)
+	}
+	if occ.Buckets[0].AvailableMinutes != 24*60 || occ.Buckets[1].Weekday != 2 {
+		t.Fatalf("unexpected day bucket: %+v", occ.Buckets[0])
+	}
+}
+
+func TestComputeOccupancy_CapacityUnits(t *testing.T) {
+	from := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	to := from.Add(2 * time.Hour)
+	bookings := []domain.Booking{
+		{StartAt: from, EndAt: from.Add(time.Hour), Quantity: 3},
+		{StartAt: from.Add(30 * time.Minute), EndAt: to, Quantity: 2},
+	}
+
+	occ := ComputeOccupancy(from, to, BucketHour, nil, 4, bookings)
+	first, second := occ.Buckets[0], occ.Buckets[1]
+	// 9:00–9:30 — 3 ед., 9:30–10:00 — 5 ед., но не больше вместимости 4
+	if first.BookedMinutes != 30*3+30*4 || first.AvailableMinutes != 60*4 {
+		t.Fatalf("unexpected first bucket: %+v", first)
+	}
+	if first.PeakUnits != 5 || first.RemainingUnits != 0 {
+		t.Fatalf("overbooked hour must have no remaining units: %+v", first)
+	}
+	if second.BookedMinutes != 60*2 || second.PeakUnits != 2 || second.RemainingUnits != 2 {
+		t.Fatalf("unexpected second bucket: %+v", second)
+	}
+}
+
+func TestPeakUnits_SequentialBookingsDoNotAdd(t *testing.T) {
+	from := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	bookings := []domain.Booking{
+		{StartAt: from, EndAt: from.Add(time.Hour), Quantity: 4},
+		{StartAt: from.Add(time.Hour), EndAt: from.Add(2 * time.Hour), Quantity: 3},
+		{StartAt: from.Add(30 * time.Minute), EndAt: from.Add(90 * time.Minute)}, // без quantity — 1 ед.
+	}
+
+	if got := PeakUnits(bookings, from, from.Add(2*time.Hour)); got != 5 {
+		t.Fatalf("expected peak 5 got %d", got)
+	}
+	if _, free := FreeUnits(domain.BookingRules{Capacity: 10}, bookings, from.Add(90*time.Minute), from.Add(2*time.Hour)); free != 7 {
+		t.Fatalf("expected 7 free got %d", free)
+	}
+}
+
+func TestFreeUnits_Buffers(t *testing.T) {
+	from := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	rules := domain.BookingRules{Capacity: 1, BufferBeforeMin: 15, BufferAfterMin: 30}
+	existing := []domain.Booking{{StartAt: from, EndAt: from.Add(time.Hour)}}
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		free       int
+	}{
+		{"starts inside cleanup", from.Add(80 * time.Minute), from.Add(2 * time.Hour), 0},
+		{"own prep overlaps cleanup", from.Add(100 * time.Minute), from.Add(3 * time.Hour), 0},
+		{"enough gap after", from.Add(105 * time.Minute), from.Add(3 * time.Hour), 1},
+		{"own cleanup overlaps prep", from.Add(-2 * time.Hour), from.Add(-30 * time.Minute), 0},
+		{"enough gap before", from.Add(-2 * time.Hour), from.Add(-45 * time.Minute), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, free := FreeUnits(rules, existing, tt.start, tt.end); free != tt.free {
+				t.Fatalf("expected %d free got %d", tt.free, free)
+			}
+		})
+	}
+
+	wFrom, wTo := AvailabilityWindow(rules, from, from.Add(time.Hour))
+	if !wFrom.Equal(from.Add(-45*time.Minute)) || !wTo.Equal(from.Add(105*time.Minute)) {
+		t.Fatalf("unexpected window %v – %v", wFrom, wTo)
+	}
+}
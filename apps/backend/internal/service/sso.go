@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"bookinghub-backend/internal/domain"
+)
+
+// ssoPasswordHash — пароль пользователей, созданных входом через провайдера: это не bcrypt-хеш,
+// поэтому вход по паролю для них невозможен, пока пользователь не задаст пароль сам
+const ssoPasswordHash = "!sso"
+
+var ErrSSOEmailNotVerified = errors.New("Провайдер входа не подтвердил email")
+
+type ssoIdentityRepo interface {
+	FindUserID(ctx context.Context, issuer, subject string) (uint64, error)
+	Link(ctx context.Context, userID uint64, issuer, subject, email string, at time.Time) error
+}
+
+type ssoUserRepo interface {
+	GetByID(ctx context.Context, id uint64) (*domain.User, error)
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+	Create(ctx context.Context, email, name string, role domain.UserRole, passwordHash string) (uint64, error)
+	UpdateRole(ctx context.Context, id uint64, role domain.UserRole) error
+}
+
+// SSOIdentity — кто вошёл через провайдера, по данным его ID-токена
+type SSOIdentity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Groups        []string
+}
+
+// SSOService находит или заводит пользователя для входа через корпоративного провайдера.
+// Учётная запись провайдера (издатель + sub) привязывается к пользователю при первом входе:
+// к существующему — по подтверждённому провайдером email, иначе к новому.
+type SSOService struct {
+	identities ssoIdentityRepo
+	users      ssoUserRepo
+	roles      map[string]domain.UserRole
+	now        func() time.Time
+}
+
+func NewSSOService(identities ssoIdentityRepo, users ssoUserRepo) *SSOService {
+	return &SSOService{identities: identities, users: users, roles: map[string]domain.UserRole{}, now: time.Now}
+}
+
+// WithRoleMap задаёт роли по группам провайдера. С непустой картой роль определяет провайдер:
+// при каждом входе пользователь получает старшую из ролей своих групп, а без подходящих групп —
+// INDIVIDUAL. Без карты роли не трогаются, новые пользователи — INDIVIDUAL.
+func (s *SSOService) WithRoleMap(roles map[string]domain.UserRole) *SSOService {
+	for g, r := range roles {
+		s.roles[g] = r
+	}
+	return s
+}
+
+// SignIn — пользователь для входа через провайдера; роль уже приведена к группам
+func (s *SSOService) SignIn(ctx context.Context, id SSOIdentity) (*domain.User, error) {
+	email := strings.ToLower(strings.TrimSpace(id.Email))
+
+	u, err := s.linkedUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		// без подтверждённого email нельзя ни привязать чужой аккаунт, ни завести новый
+		if email == "" || !id.EmailVerified {
+			return nil, ErrSSOEmailNotVerified
+		}
+		if u, err = s.userByEmail(ctx, email, id.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	if role, ok := s.role(id.Groups); ok && role != u.Role {
+		if err := s.users.UpdateRole(ctx, u.ID, role); err != nil {
+			return nil, err
+		}
+		u.Role = role
+	}
+	if email == "" {
+		email = u.Email
+	}
+	if err := s.identities.Link(ctx, u.ID, id.Issuer, id.Subject, email, s.now()); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// linkedUser — пользователь, уже привязанный к учётной записи провайдера; nil, если привязки нет
+func (s *SSOService) linkedUser(ctx context.Context, id SSOIdentity) (*domain.User, error) {
+	uid, err := s.identities.FindUserID(ctx, id.Issuer, id.Subject)
+	if err != nil || uid == 0 {
+		return nil, err
+	}
+	return s.users.GetByID(ctx, uid)
+}
+
+// userByEmail — существующий пользователь с этим email или новый
+func (s *SSOService) userByEmail(ctx context.Context, email, name string) (*domain.User, error) {
+	u, err := s.users.GetByEmail(ctx, email)
+	if err == nil {
+		return u, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+	if utf8.RuneCountInString(name) > 120 {
+		name = string([]rune(name)[:120])
+	}
+	id, err := s.users.Create(ctx, email, name, domain.RoleIndividual, ssoPasswordHash)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.User{ID: id, Email: email, Name: name, Role: domain.RoleIndividual, CreatedAt: s.now()}, nil
+}
+
+// role — старшая роль из групп пользователя; false — карта ролей не задана
+func (s *SSOService) role(groups []string) (domain.UserRole, bool) {
+	if len(s.roles) == 0 {
+		return "", false
+	}
+	best := domain.RoleIndividual
+	for _, g := range groups {
+		if r, ok := s.roles[g]; ok && roleRank(r) > roleRank(best) {
+			best = r
+		}
+	}
+	return best, true
+}
+
+func roleRank(r domain.UserRole) int {
+	switch r {
+	case domain.RoleAdmin:
+		return 3
+	case domain.RoleCompany:
+		return 2
+	case domain.RoleIndividual:
+		return 1
+	}
+	return 0
+}
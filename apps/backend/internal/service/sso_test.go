@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"bookinghub-backend/internal/domain"
+)
+
+// fakeSSORepo — пользователи и привязки к провайдеру в памяти
+type fakeSSORepo struct {
+	users  map[uint64]*domain.User
+	links  map[string]uint64 // issuer + " " + subject -> user_id
+	nextID uint64
+}
+
+func newFakeSSORepo(users ...domain.User) *fakeSSORepo {
+	f := &fakeSSORepo{users: map[uint64]*domain.User{}, links: map[string]uint64{}, nextID: 100}
+	for _, u := range users {
+		f.users[u.ID] = &u
+	}
+	return f
+}
+
+func (f *fakeSSORepo) FindUserID(ctx context.Context, issuer, subject string) (uint64, error) {
+	return f.links[issuer+" "+subject], nil
+}
+
+func (f *fakeSSORepo) Link(ctx context.Context, userID uint64, issuer, subject, email string, at time.Time) error {
+	if _, ok := f.links[issuer+" "+subject]; !ok {
+		f.links[issuer+" "+subject] = userID
+	}
+	return nil
+}
+
+func (f *fakeSSORepo) GetByID(ctx context.Context, id uint64) (*domain.User, error) {
+	if u, ok := f.users[id]; ok {
+		c := *u
+		return &c, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (f *fakeSSORepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, u := range f.users {
+		if u.Email == email {
+			c := *u
+			return &c, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (f *fakeSSORepo) Create(ctx context.Context, email, name string, role domain.UserRole, hash string) (uint64, error) {
+	f.nextID++
+	f.users[f.nextID] = &domain.User{ID: f.nextID, Email: email, Name: name, Role: role, PasswordHash: hash}
+	return f.nextID, nil
+}
+
+func (f *fakeSSORepo) UpdateRole(ctx context.Context, id uint64, role domain.UserRole) error {
+	f.users[id].Role = role
+	return nil
+}
+
+const testIssuer = "https://id.corp"
+
+func TestSSO_CreatesUserByVerifiedEmail(t *testing.T) {
+	repo := newFakeSSORepo()
+	s := NewSSOService(repo, repo)
+
+	u, err := s.SignIn(context.Background(), SSOIdentity{Issuer: testIssuer, Subject: "u-1", Email: "Ann@Corp.example", EmailVerified: true, Name: "Анна"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Email != "ann@corp.example" || u.Name != "Анна" || u.Role != domain.RoleIndividual {
+		t.Errorf("user = %+v", u)
+	}
+	if repo.users[u.ID].PasswordHash != ssoPasswordHash {
+		t.Error("у пользователя SSO не должно быть пароля")
+	}
+	if repo.links[testIssuer+" u-1"] != u.ID {
+		t.Error("учётная запись провайдера не привязана")
+	}
+
+	// повторный вход — тот же пользователь, даже если email у провайдера сменился
+	again, err := s.SignIn(context.Background(), SSOIdentity{Issuer: testIssuer, Subject: "u-1", Email: "anna@corp.example"})
+	if err != nil || again.ID != u.ID {
+		t.Fatalf("повторный вход: %+v, %v", again, err)
+	}
+	if len(repo.users) != 1 {
+		t.Errorf("заведено пользователей: %d", len(repo.users))
+	}
+}
+
+func TestSSO_LinksExistingUserByEmail(t *testing.T) {
+	repo := newFakeSSORepo(domain.User{ID: 7, Email: "bob@corp.example", Name: "Боб", Role: domain.RoleCompany})
+	s := NewSSOService(repo, repo)
+
+	u, err := s.SignIn(context.Background(), SSOIdentity{Issuer: testIssuer, Subject: "u-2", Email: "bob@corp.example", EmailVerified: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// без карты ролей роль не меняется
+	if u.ID != 7 || u.Role != domain.RoleCompany {
+		t.Errorf("user = %+v", u)
+	}
+	if repo.links[testIssuer+" u-2"] != 7 {
+		t.Error("учётная запись провайдера не привязана")
+	}
+}
+
+func TestSSO_RequiresVerifiedEmail(t *testing.T) {
+	repo := newFakeSSORepo(domain.User{ID: 7, Email: "bob@corp.example", Role: domain.RoleAdmin})
+	s := NewSSOService(repo, repo)
+
+	for _, id := range []SSOIdentity{
+		{Issuer: testIssuer, Subject: "u-3", Email: "bob@corp.example"},
+		{Issuer: testIssuer, Subject: "u-3", EmailVerified: true},
+	} {
+		if _, err := s.SignIn(context.Background(), id); !errors.Is(err, ErrSSOEmailNotVerified) {
+			t.Errorf("%+v: err = %v, want ErrSSOEmailNotVerified", id, err)
+		}
+	}
+	if len(repo.links) != 0 {
+		t.Errorf("привязки без подтверждённого email: %v", repo.links)
+	}
+}
+
+func TestSSO_MapsGroupsToRole(t *testing.T) {
+	repo := newFakeSSORepo(domain.User{ID: 7, Email: "bob@corp.example", Role: domain.RoleAdmin})
+	s := NewSSOService(repo, repo).WithRoleMap(map[string]domain.UserRole{
+		"admins":   domain.RoleAdmin,
+		"partners": domain.RoleCompany,
+	})
+	ctx := context.Background()
+
+	u, err := s.SignIn(ctx, SSOIdentity{Issuer: testIssuer, Subject: "u-4", Email: "eve@corp.example", EmailVerified: true, Groups: []string{"staff", "partners", "admins"}})
+	if err != nil || u.Role != domain.RoleAdmin {
+		t.Fatalf("старшая из ролей групп: %+v, %v", u, err)
+	}
+
+	// роль определяет провайдер: без подходящих групп администратор становится INDIVIDUAL
+	u, err = s.SignIn(ctx, SSOIdentity{Issuer: testIssuer, Subject: "u-5", Email: "bob@corp.example", EmailVerified: true, Groups: []string{"staff"}})
+	if err != nil || u.Role != domain.RoleIndividual || repo.users[7].Role != domain.RoleIndividual {
+		t.Fatalf("без групп: %+v, %v", u, err)
+	}
+
+	u, err = s.SignIn(ctx, SSOIdentity{Issuer: testIssuer, Subject: "u-5", Groups: []string{"partners"}})
+	if err != nil || u.Role != domain.RoleCompany {
+		t.Fatalf("смена групп: %+v, %v", u, err)
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultTimezone — пояс ресурсов, для которых он не задан (и всех ресурсов до появления поясов)
+const DefaultTimezone = "Europe/Moscow"
+
+var (
+	ErrInvalidTimezone = errors.New("Некорректный часовой пояс: нужен идентификатор IANA, например Europe/Moscow")
+	// ErrNonexistentTime — местного времени нет: часы переводятся вперёд и пропускают его
+	ErrNonexistentTime = errors.New("Такого времени нет в часовом поясе ресурса: в этот момент часы переводятся вперёд")
+)
+
+// LoadZone загружает пояс ресурса по имени IANA; пустое имя — DefaultTimezone.
+// "Local" не принимаем: результат зависел бы от настроек сервера.
+func LoadZone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = DefaultTimezone
+	}
+	if name == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return loc, nil
+}
+
+// localLayouts — форматы времени без смещения, которые трактуются как местное время ресурса
+var localLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04"}
+
+// HasOffset — во времени явно указано смещение (Z или ±hh:mm), пояс ресурса для него не нужен
+func HasOffset(s string) bool {
+	_, err := time.Parse(time.RFC3339, strings.TrimSpace(s))
+	return err == nil
+}
+
+// ParseLocalTime разбирает время запроса и возвращает его в UTC. Время со смещением берётся как есть,
+// время без смещения — это часы на месте ресурса (loc). Если при переходе на летнее время
+// такого местного времени не было, возвращается ErrNonexistentTime; при переходе назад
+// местное время повторяется дважды, и берётся первый из двух моментов.
+func ParseLocalTime(s string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	var lastErr error
+	for _, layout := range localLayouts {
+		t, err := time.ParseInLocation(layout, s, loc)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if t.Format(layout) != s {
+			return time.Time{}, ErrNonexistentTime
+		}
+		return earliestInstant(t).UTC(), nil
+	}
+	return time.Time{}, lastErr
+}
+
+// earliestInstant — для повторяющегося местного времени (переход назад) ранний из двух
+// моментов с теми же часами; time.Date не гарантирует, какой из них вернёт.
+func earliestInstant(t time.Time) time.Time {
+	_, offset := t.Zone()
+	_, before := t.Add(-12 * time.Hour).Zone()
+	if before <= offset {
+		return t
+	}
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	if earlier := wall.Add(-time.Duration(before) * time.Second).In(t.Location()); earlier.Hour() == t.Hour() && earlier.Minute() == t.Minute() {
+		return earlier
+	}
+	return t
+}
+
+// ParseLocalDate — начало дня YYYY-MM-DD в поясе loc
+func ParseLocalDate(s string, loc *time.Location) (time.Time, error) {
+	d, err := time.Parse("2006-01-02", strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("ожидается дата YYYY-MM-DD: %w", err)
+	}
+	return StartOfDay(d, loc), nil
+}
+
+// StartOfDay — полночь календарного дня t в поясе loc
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// NextDay — полночь следующего дня в том же поясе. В дни перевода часов сутки длятся
+// 23 или 25 часов, поэтому к полуночи нельзя просто прибавить 24 часа.
+func NextDay(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, day.Location())
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseLocalTime(t *testing.T) {
+	moscow, err := LoadZone("")
+	if err != nil || moscow.String() != DefaultTimezone {
+		t.Fatalf("default zone: %v %v", moscow, err)
+	}
+
+	got, err := ParseLocalTime("2030-01-07T10:00", moscow)
+	if err != nil || !got.Equal(time.Date(2030, 1, 7, 7, 0, 0, 0, time.UTC)) {
+		t.Fatalf("naive time must be read in resource zone: %v %v", got, err)
+	}
+	if got.Location() != time.UTC {
+		t.Fatalf("expected UTC result, got %v", got.Location())
+	}
+
+	got, err = ParseLocalTime("2030-01-07T10:00:00+05:00", moscow)
+	if err != nil || !got.Equal(time.Date(2030, 1, 7, 5, 0, 0, 0, time.UTC)) {
+		t.Fatalf("explicit offset must win over resource zone: %v %v", got, err)
+	}
+
+	if _, err := ParseLocalTime("07.01.2030 10:00", moscow); err == nil {
+		t.Fatal("expected format error")
+	}
+	if _, err := LoadZone("Mars/Olympus"); !errors.Is(err, ErrInvalidTimezone) {
+		t.Fatalf("expected ErrInvalidTimezone, got %v", err)
+	}
+}
+
+func TestParseLocalTime_DST(t *testing.T) {
+	berlin, err := LoadZone("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2030-03-31 02:00 → 03:00: 02:30 по Берлину не существует
+	if _, err := ParseLocalTime("2030-03-31T02:30", berlin); !errors.Is(err, ErrNonexistentTime) {
+		t.Fatalf("expected ErrNonexistentTime, got %v", err)
+	}
+
+	// 2030-10-27 03:00 → 02:00: неоднозначное 02:30 читается как первое (летнее)
+	got, err := ParseLocalTime("2030-10-27T02:30", berlin)
+	if err != nil || !got.Equal(time.Date(2030, 10, 27, 0, 30, 0, 0, time.UTC)) {
+		t.Fatalf("ambiguous time: %v %v", got, err)
+	}
+}
+
+func TestNextDay_DST(t *testing.T) {
+	berlin, _ := LoadZone("Europe/Berlin")
+
+	cases := []struct {
+		day  time.Time
+		want time.Duration
+	}{
+		{time.Date(2030, 3, 31, 0, 0, 0, 0, berlin), 23 * time.Hour},
+		{time.Date(2030, 10, 27, 0, 0, 0, 0, berlin), 25 * time.Hour},
+		{time.Date(2030, 6, 1, 0, 0, 0, 0, berlin), 24 * time.Hour},
+	}
+	for _, c := range cases {
+		start := StartOfDay(c.day.Add(5*time.Hour).UTC(), berlin)
+		if !start.Equal(c.day) {
+			t.Fatalf("StartOfDay(%v) = %v", c.day, start)
+		}
+		if got := NextDay(start).Sub(start); got != c.want {
+			t.Errorf("%s: expected %v day, got %v", c.day.Format("2006-01-02"), c.want, got)
+		}
+	}
+}
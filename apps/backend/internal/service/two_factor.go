@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/totp"
+)
+
+const (
+	// RecoveryCodeCount — сколько кодов восстановления выдаётся за раз
+	RecoveryCodeCount = 10
+	// totpSkew — сколько соседних 30-секундных шагов принимается из-за расхождения часов
+	totpSkew = 1
+)
+
+var (
+	ErrTwoFactorNotEnrolled      = errors.New("Двухфакторная аутентификация не подключена")
+	ErrTwoFactorAlreadyEnabled   = errors.New("Двухфакторная аутентификация уже включена")
+	ErrTwoFactorRequiredForRole  = errors.New("Для вашей роли двухфакторная аутентификация обязательна")
+	ErrInvalidTwoFactorCode      = errors.New("Неверный или уже использованный код")
+	ErrTwoFactorEnrollNotStarted = errors.New("Сначала начните подключение двухфакторной аутентификации")
+)
+
+type twoFactorRepo interface {
+	GetTOTP(ctx context.Context, userID uint64) (*domain.UserTOTP, error)
+	SavePendingTOTP(ctx context.Context, userID uint64, secret string) error
+	EnableTOTP(ctx context.Context, userID uint64, step int64, at time.Time, recoveryHashes []string) (bool, error)
+	UseTOTPStep(ctx context.Context, userID uint64, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID uint64, hashes []string) error
+	CountRecoveryCodes(ctx context.Context, userID uint64) (int, error)
+	UseRecoveryCode(ctx context.Context, userID uint64, hash string, at time.Time) (bool, error)
+	DeleteTOTP(ctx context.Context, userID uint64) error
+}
+
+// TwoFactorEnrollment — данные для приложения-аутентификатора: секрет и ссылка otpauth:// для QR-кода
+type TwoFactorEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// TwoFactorStatus — состояние 2FA пользователя
+type TwoFactorStatus struct {
+	Enabled           bool
+	Required          bool
+	RecoveryCodesLeft int
+}
+
+// TwoFactorService — двухфакторная аутентификация по TOTP (RFC 6238) с кодами восстановления.
+// Подключение в два шага: Enroll выдаёт секрет, Enable включает 2FA после первого верного кода.
+type TwoFactorService struct {
+	repo     twoFactorRepo
+	issuer   string
+	required map[domain.UserRole]bool
+	now      func() time.Time
+}
+
+// NewTwoFactorService; issuer — название сервиса в приложении-аутентификаторе
+func NewTwoFactorService(repo twoFactorRepo, issuer string) *TwoFactorService {
+	return &TwoFactorService{repo: repo, issuer: issuer, required: map[domain.UserRole]bool{}, now: time.Now}
+}
+
+// WithRequiredRoles делает 2FA обязательной для ролей: без неё пользователь не получит токен доступа
+// и не сможет её выключить
+func (s *TwoFactorService) WithRequiredRoles(roles ...domain.UserRole) *TwoFactorService {
+	for _, r := range roles {
+		s.required[r] = true
+	}
+	return s
+}
+
+// Required — обязательна ли 2FA для роли
+func (s *TwoFactorService) Required(role domain.UserRole) bool {
+	return s.required[role]
+}
+
+// Enabled — включена ли 2FA у пользователя
+func (s *TwoFactorService) Enabled(ctx context.Context, userID uint64) (bool, error) {
+	t, err := s.repo.GetTOTP(ctx, userID)
+	return t.Enabled(), err
+}
+
+func (s *TwoFactorService) Status(ctx context.Context, userID uint64, role domain.UserRole) (*TwoFactorStatus, error) {
+	t, err := s.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	st := &TwoFactorStatus{Enabled: t.Enabled(), Required: s.Required(role)}
+	if st.Enabled {
+		if st.RecoveryCodesLeft, err = s.repo.CountRecoveryCodes(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	return st, nil
+}
+
+// Enroll начинает подключение: новый секрет заменяет прежний неподтверждённый.
+// account — подпись аккаунта в аутентификаторе (email).
+func (s *TwoFactorService) Enroll(ctx context.Context, userID uint64, account string) (*TwoFactorEnrollment, error) {
+	t, err := s.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Enabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SavePendingTOTP(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+	return &TwoFactorEnrollment{Secret: secret, URI: totp.URI(s.issuer, account, secret)}, nil
+}
+
+// Enable включает 2FA по первому верному коду из аутентификатора и возвращает коды восстановления.
+// Коды показываются один раз: в БД остаются только их хеши.
+func (s *TwoFactorService) Enable(ctx context.Context, userID uint64, code string) ([]string, error) {
+	t, err := s.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTwoFactorEnrollNotStarted
+	}
+	if t.Enabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	now := s.now()
+	step, ok := totp.Verify(t.Secret, normalizeTOTP(code), now, totpSkew)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	enabled, err := s.repo.EnableTOTP(ctx, userID, step, now, hashes)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, ErrTwoFactorEnrollNotStarted
+	}
+	return codes, nil
+}
+
+// Verify проверяет второй фактор: код из аутентификатора или, если его нет, код восстановления.
+// Каждый код принимается один раз.
+func (s *TwoFactorService) Verify(ctx context.Context, userID uint64, code, recoveryCode string) error {
+	t, err := s.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !t.Enabled() {
+		return ErrTwoFactorNotEnrolled
+	}
+	if strings.TrimSpace(code) == "" {
+		return s.useRecoveryCode(ctx, userID, recoveryCode)
+	}
+	return s.useCode(ctx, t, code)
+}
+
+// RegenerateRecoveryCodes выдаёт новые коды восстановления взамен всех прежних; нужен код из аутентификатора
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID uint64, code string) ([]string, error) {
+	t, err := s.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !t.Enabled() {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	if err := s.useCode(ctx, t, code); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable выключает 2FA после проверки кода; для ролей, где она обязательна, — нельзя
+func (s *TwoFactorService) Disable(ctx context.Context, userID uint64, role domain.UserRole, code, recoveryCode string) error {
+	if s.Required(role) {
+		return ErrTwoFactorRequiredForRole
+	}
+	if err := s.Verify(ctx, userID, code, recoveryCode); err != nil {
+		return err
+	}
+	return s.repo.DeleteTOTP(ctx, userID)
+}
+
+func (s *TwoFactorService) useCode(ctx context.Context, t *domain.UserTOTP, code string) error {
+	step, ok := totp.Verify(t.Secret, normalizeTOTP(code), s.now(), totpSkew)
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+	fresh, err := s.repo.UseTOTPStep(ctx, t.UserID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+func (s *TwoFactorService) useRecoveryCode(ctx context.Context, userID uint64, code string) error {
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return ErrInvalidTwoFactorCode
+	}
+	used, err := s.repo.UseRecoveryCode(ctx, userID, hashRecoveryCode(normalized), s.now())
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newRecoveryCodes — коды восстановления вида XXXX-XXXX-XXXX-XXXX (80 случайных бит) и их хеши.
+// При такой энтропии медленный хеш не нужен: SHA-256 не перебрать.
+func newRecoveryCodes() (codes, hashes []string, err error) {
+	for i := 0; i < RecoveryCodeCount; i++ {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := recoveryEncoding.EncodeToString(b)
+		codes = append(codes, raw[0:4]+"-"+raw[4:8]+"-"+raw[8:12]+"-"+raw[12:16])
+		hashes = append(hashes, hashRecoveryCode(raw))
+	}
+	return codes, hashes, nil
+}
+
+func hashRecoveryCode(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeRecoveryCode убирает дефисы и пробелы и приводит к верхнему регистру: код можно вводить как угодно
+func normalizeRecoveryCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// normalizeTOTP убирает пробелы: аутентификаторы показывают код как «123 456»
+func normalizeTOTP(code string) string {
+	return strings.ReplaceAll(code, " ", "")
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/totp"
+)
+
+// fakeTwoFactorRepo — секреты и коды восстановления одного пользователя в памяти
+type fakeTwoFactorRepo struct {
+	t        *domain.UserTOTP
+	recovery map[string]bool // хеш -> использован
+}
+
+func (f *fakeTwoFactorRepo) GetTOTP(ctx context.Context, userID uint64) (*domain.UserTOTP, error) {
+	if f.t == nil {
+		return nil, nil
+	}
+	c := *f.t
+	return &c, nil
+}
+
+func (f *fakeTwoFactorRepo) SavePendingTOTP(ctx context.Context, userID uint64, secret string) error {
+	f.t = &domain.UserTOTP{UserID: userID, Secret: secret}
+	return nil
+}
+
+func (f *fakeTwoFactorRepo) EnableTOTP(ctx context.Context, userID uint64, step int64, at time.Time, hashes []string) (bool, error) {
+	if f.t == nil || f.t.EnabledAt != nil {
+		return false, nil
+	}
+	f.t.EnabledAt, f.t.LastStep = &at, &step
+	return true, f.ReplaceRecoveryCodes(ctx, userID, hashes)
+}
+
+func (f *fakeTwoFactorRepo) UseTOTPStep(ctx context.Context, userID uint64, step int64) (bool, error) {
+	if !f.t.Enabled() || (f.t.LastStep != nil && *f.t.LastStep >= step) {
+		return false, nil
+	}
+	f.t.LastStep = &step
+	return true, nil
+}
+
+func (f *fakeTwoFactorRepo) ReplaceRecoveryCodes(ctx context.Context, userID uint64, hashes []string) error {
+	f.recovery = map[string]bool{}
+	for _, h := range hashes {
+		f.recovery[h] = false
+	}
+	return nil
+}
+
+func (f *fakeTwoFactorRepo) CountRecoveryCodes(ctx context.Context, userID uint64) (int, error) {
+	n := 0
+	for _, used := range f.recovery {
+		if !used {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeTwoFactorRepo) UseRecoveryCode(ctx context.Context, userID uint64, hash string, at time.Time) (bool, error) {
+	used, ok := f.recovery[hash]
+	if !ok || used {
+		return false, nil
+	}
+	f.recovery[hash] = true
+	return true, nil
+}
+
+func (f *fakeTwoFactorRepo) DeleteTOTP(ctx context.Context, userID uint64) error {
+	f.t, f.recovery = nil, nil
+	return nil
+}
+
+func newTestTwoFactor(now *time.Time) (*TwoFactorService, *fakeTwoFactorRepo) {
+	repo := &fakeTwoFactorRepo{}
+	s := NewTwoFactorService(repo, "BookingHub").WithRequiredRoles(domain.RoleAdmin)
+	s.now = func() time.Time { return *now }
+	return s, repo
+}
+
+func TestTwoFactor_EnrollEnableVerify(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	s, _ := newTestTwoFactor(&now)
+
+	if _, err := s.Enable(ctx, 7, "123456"); !errors.Is(err, ErrTwoFactorEnrollNotStarted) {
+		t.Fatalf("enable before enroll: %v", err)
+	}
+	e, err := s.Enroll(ctx, 7, "user@test.local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.URI != totp.URI("BookingHub", "user@test.local", e.Secret) {
+		t.Fatalf("URI = %s", e.URI)
+	}
+	if _, err := s.Enable(ctx, 7, "000000"); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("enable with wrong code: %v", err)
+	}
+
+	code, _ := totp.Code(e.Secret, now)
+	codes, err := s.Enable(ctx, 7, code[:3]+" "+code[3:])
+	if err != nil || len(codes) != RecoveryCodeCount {
+		t.Fatalf("enable: %d codes, %v", len(codes), err)
+	}
+	if _, err := s.Enroll(ctx, 7, "user@test.local"); !errors.Is(err, ErrTwoFactorAlreadyEnabled) {
+		t.Fatalf("enroll when enabled: %v", err)
+	}
+
+	// код, которым подтвердили подключение, второй раз не принимается
+	if err := s.Verify(ctx, 7, code, ""); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("replayed code: %v", err)
+	}
+	now = now.Add(totp.Period)
+	next, _ := totp.Code(e.Secret, now)
+	if err := s.Verify(ctx, 7, next, ""); err != nil {
+		t.Fatalf("next code: %v", err)
+	}
+
+	st, _ := s.Status(ctx, 7, domain.RoleIndividual)
+	if !st.Enabled || st.Required || st.RecoveryCodesLeft != RecoveryCodeCount {
+		t.Fatalf("status = %+v", st)
+	}
+}
+
+func TestTwoFactor_RecoveryCodes(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	s, _ := newTestTwoFactor(&now)
+
+	e, _ := s.Enroll(ctx, 7, "user@test.local")
+	code, _ := totp.Code(e.Secret, now)
+	codes, _ := s.Enable(ctx, 7, code)
+
+	// регистр и дефисы не важны, но каждый код — один раз
+	if err := s.Verify(ctx, 7, "", " "+codes[0][:9]+codes[0][10:]+" "); err != nil {
+		t.Fatalf("recovery code: %v", err)
+	}
+	if err := s.Verify(ctx, 7, "", codes[0]); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("reused recovery code: %v", err)
+	}
+	if err := s.Verify(ctx, 7, "", ""); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("empty codes: %v", err)
+	}
+
+	now = now.Add(totp.Period)
+	code, _ = totp.Code(e.Secret, now)
+	fresh, err := s.RegenerateRecoveryCodes(ctx, 7, code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Verify(ctx, 7, "", codes[1]); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("old recovery code after regenerate: %v", err)
+	}
+	if err := s.Verify(ctx, 7, "", fresh[1]); err != nil {
+		t.Fatalf("new recovery code: %v", err)
+	}
+}
+
+func TestTwoFactor_Disable(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	s, repo := newTestTwoFactor(&now)
+
+	e, _ := s.Enroll(ctx, 7, "admin@test.local")
+	code, _ := totp.Code(e.Secret, now)
+	codes, _ := s.Enable(ctx, 7, code)
+
+	if err := s.Disable(ctx, 7, domain.RoleAdmin, "", codes[0]); !errors.Is(err, ErrTwoFactorRequiredForRole) {
+		t.Fatalf("admin disable: %v", err)
+	}
+	if err := s.Disable(ctx, 7, domain.RoleIndividual, "", "nope"); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("disable with wrong code: %v", err)
+	}
+	if err := s.Disable(ctx, 7, domain.RoleIndividual, "", codes[0]); err != nil || repo.t != nil {
+		t.Fatalf("disable: %v, left %+v", err, repo.t)
+	}
+	if err := s.Verify(ctx, 7, code, ""); !errors.Is(err, ErrTwoFactorNotEnrolled) {
+		t.Fatalf("verify after disable: %v", err)
+	}
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/i18n"
+)
+
+// DefaultClaimTTL — сколько действует предложение освободившегося места
+const DefaultClaimTTL = 30 * time.Minute
+
+var (
+	ErrSlotAvailable   = errors.New("Это время свободно — забронируйте его напрямую")
+	ErrAlreadyInQueue  = errors.New("Вы уже стоите в очереди на это время")
+	ErrClaimNotOffered = errors.New("Место по этой заявке сейчас не предлагается")
+)
+
+type waitlistRepo interface {
+	Create(ctx context.Context, e domain.WaitlistEntry) (uint64, error)
+	GetByID(ctx context.Context, id uint64) (*domain.WaitlistEntry, error)
+	HasActive(ctx context.Context, userID, resourceID uint64, startAt, endAt time.Time) (bool, error)
+	ListActiveForResource(ctx context.Context, resourceID uint64, now time.Time) ([]domain.WaitlistEntry, error)
+	MarkOffered(ctx context.Context, id uint64, expiresAt time.Time) error
+	MarkFulfilled(ctx context.Context, id, bookingID uint64, from domain.WaitlistStatus) (bool, error)
+	SetStatus(ctx context.Context, id uint64, status domain.WaitlistStatus) error
+	ExpireOffers(ctx context.Context, now time.Time) ([]uint64, error)
+	ExpireStarted(ctx context.Context, now time.Time) error
+}
+
+// WaitlistNotifier сообщает пользователю, что по его заявке создана бронь или предложено место
+type WaitlistNotifier interface {
+	NotifyWaitlist(ctx context.Context, e domain.WaitlistEntry)
+}
+
+// LogNotifier пишет уведомления в лог — пока в проекте нет почты и push
+type LogNotifier struct{}
+
+func (LogNotifier) NotifyWaitlist(ctx context.Context, e domain.WaitlistEntry) {
+	if text := WaitlistNotice(i18n.Default, e); text != "" {
+		log.Printf("waitlist #%d: user %d: %s", e.ID, e.UserID, text)
+	}
+}
+
+// WaitlistNotice — текст уведомления по заявке на языке lang (для почты, push и лога);
+// "" — уведомлять не о чем
+func WaitlistNotice(lang string, e domain.WaitlistEntry) string {
+	const layout = "2006-01-02 15:04 MST"
+	switch {
+	case e.Status == domain.WaitlistFulfilled && e.BookingID != nil:
+		return i18n.T(lang, "notify.waitlist_booked", *e.BookingID, e.StartAt.UTC().Format(layout))
+	case e.Status == domain.WaitlistOffered && e.ClaimExpiresAt != nil:
+		return i18n.T(lang, "notify.waitlist_offered", e.StartAt.UTC().Format(layout), e.ClaimExpiresAt.UTC().Format(layout))
+	}
+	return ""
+}
+
+type WaitlistService struct {
+	repo     waitlistRepo
+	bookings bookingRepo
+	notifier WaitlistNotifier
+	approver *Approver
+	claimTTL time.Duration
+	now      func() time.Time
+}
+
+func NewWaitlistService(repo waitlistRepo, bookings bookingRepo) *WaitlistService {
+	return &WaitlistService{repo: repo, bookings: bookings, notifier: LogNotifier{}, claimTTL: DefaultClaimTTL, now: time.Now}
+}
+
+// WithNotifier подменяет способ уведомления пользователей
+func (s *WaitlistService) WithNotifier(n WaitlistNotifier) *WaitlistService {
+	s.notifier = n
+	return s
+}
+
+// WithClaimTTL задаёт срок предложения освободившегося места
+func (s *WaitlistService) WithClaimTTL(ttl time.Duration) *WaitlistService {
+	s.claimTTL = ttl
+	return s
+}
+
+// WithApprover — брони из очереди подтверждаются по режиму подтверждения ресурса
+func (s *WaitlistService) WithApprover(a *Approver) *WaitlistService {
+	s.approver = a
+	return s
+}
+
+// Join ставит пользователя в очередь. Встать можно только на действительно занятое время.
+func (s *WaitlistService) Join(ctx context.Context, e domain.WaitlistEntry) (uint64, error) {
+	if err := validateBooking(e.UserID, e.ResourceID, e.StartAt, e.EndAt, e.Quantity); err != nil {
+		return 0, err
+	}
+	err := checkFree(ctx, s.bookings, e.ResourceID, e.StartAt, e.EndAt, e.Quantity, reservation{})
+	if err == nil {
+		return 0, ErrSlotAvailable
+	}
+	if !errors.Is(err, ErrConflict) {
+		return 0, err
+	}
+
+	exists, err := s.repo.HasActive(ctx, e.UserID, e.ResourceID, e.StartAt, e.EndAt)
+	if err != nil {
+		return 0, err
+	}
+	if exists {
+		return 0, ErrAlreadyInQueue
+	}
+	return s.repo.Create(ctx, e)
+}
+
+// Release вызывается, когда на ресурсе освободилось место (бронь отменена или отклонена).
+// Заявки просматриваются в порядке очереди; каждая, которой теперь хватает места,
+// становится PENDING-бронью или получает предложение. Непоместившиеся заявки ждут дальше —
+// следующая подходящая может пройти раньше них.
+// Всё это идёт в одной транзакции под блокировкой строки ресурса, как обычная бронь,
+// поэтому параллельная бронь не займёт место между проверкой и вставкой.
+// Автоподтверждение и уведомления — после фиксации.
+func (s *WaitlistService) Release(ctx context.Context, resourceID uint64) error {
+	var changed []domain.WaitlistEntry
+	err := s.bookings.InTx(ctx, func(ctx context.Context) error {
+		if _, err := s.bookings.LockResources(ctx, []uint64{resourceID}); err != nil {
+			return err
+		}
+		var err error
+		changed, err = s.release(ctx, resourceID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, e := range changed {
+		if e.Status == domain.WaitlistFulfilled {
+			s.approver.Apply(ctx, *e.BookingID, e.UserID, e.ResourceID, e.StartAt, e.EndAt)
+		}
+		s.notifier.NotifyWaitlist(ctx, e)
+	}
+	return nil
+}
+
+// release раздаёт освободившееся место внутри транзакции Release и возвращает изменённые заявки
+func (s *WaitlistService) release(ctx context.Context, resourceID uint64) ([]domain.WaitlistEntry, error) {
+	now := s.now()
+	entries, err := s.repo.ListActiveForResource(ctx, resourceID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []domain.WaitlistEntry
+	for _, e := range entries {
+		if e.Status != domain.WaitlistWaiting {
+			continue
+		}
+		// действующие предложения, в том числе сделанные выше в этом цикле, checkFree читает из БД:
+		// одно окно не предложат двоим
+		err := checkFree(ctx, s.bookings, e.ResourceID, e.StartAt, e.EndAt, e.Quantity, reservation{})
+		if errors.Is(err, ErrConflict) || errors.Is(err, ErrInvalidQuantity) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if e.AutoBook {
+			bookingID, err := s.bookings.Create(ctx, e.ResourceID, e.UserID, e.StartAt, e.EndAt, e.Quantity)
+			if err != nil {
+				return nil, err
+			}
+			// строки очереди заблокированы ListActiveForResource, заявка не могла уйти из WAITING
+			ok, err := s.repo.MarkFulfilled(ctx, e.ID, bookingID, domain.WaitlistWaiting)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, fmt.Errorf("waitlist entry %d left WAITING during release", e.ID)
+			}
+			e.Status, e.BookingID = domain.WaitlistFulfilled, &bookingID
+		} else {
+			expiresAt := now.Add(s.claimTTL)
+			if err := s.repo.MarkOffered(ctx, e.ID, expiresAt); err != nil {
+				return nil, err
+			}
+			e.Status, e.ClaimExpiresAt = domain.WaitlistOffered, &expiresAt
+		}
+		changed = append(changed, e)
+	}
+	return changed, nil
+}
+
+// Claim превращает предложенное место в бронь под блокировкой строки ресурса.
+// Заявка перечитывается с блокировкой внутри транзакции: два параллельных Claim
+// или Claim вместе с Release не создадут две брони по одному предложению.
+// Если место за это время заняли напрямую, заявка возвращается в очередь.
+func (s *WaitlistService) Claim(ctx context.Context, e domain.WaitlistEntry) (uint64, error) {
+	var bookingID uint64
+	err := s.bookings.InTx(ctx, func(ctx context.Context) error {
+		if _, err := s.bookings.LockResources(ctx, []uint64{e.ResourceID}); err != nil {
+			return err
+		}
+		cur, err := s.repo.GetByID(ctx, e.ID)
+		if err != nil {
+			return err
+		}
+		if cur == nil || cur.Status != domain.WaitlistOffered || cur.ClaimExpiresAt == nil || !cur.ClaimExpiresAt.After(s.now()) {
+			return ErrClaimNotOffered
+		}
+		e = *cur
+		// своё предложение место уже держит, остальные — мешают
+		if err := checkFree(ctx, s.bookings, e.ResourceID, e.StartAt, e.EndAt, e.Quantity, reservation{waitlistID: e.ID}); err != nil {
+			return err
+		}
+		if bookingID, err = s.bookings.Create(ctx, e.ResourceID, e.UserID, e.StartAt, e.EndAt, e.Quantity); err != nil {
+			return err
+		}
+		ok, err := s.repo.MarkFulfilled(ctx, e.ID, bookingID, domain.WaitlistOffered)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrClaimNotOffered
+		}
+		return nil
+	})
+	if errors.Is(err, ErrConflict) {
+		if err := s.repo.SetStatus(ctx, e.ID, domain.WaitlistWaiting); err != nil {
+			return 0, err
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	s.approver.Apply(ctx, bookingID, e.UserID, e.ResourceID, e.StartAt, e.EndAt)
+	return bookingID, nil
+}
+
+// Sweep закрывает заявки на начавшееся время и просроченные предложения;
+// места из просроченных предложений переходят следующим в очереди
+func (s *WaitlistService) Sweep(ctx context.Context) error {
+	now := s.now()
+	if err := s.repo.ExpireStarted(ctx, now); err != nil {
+		return err
+	}
+	resourceIDs, err := s.repo.ExpireOffers(ctx, now)
+	if err != nil {
+		return err
+	}
+	for _, id := range resourceIDs {
+		if err := s.Release(ctx, id); err != nil {
+			return fmt.Errorf("resource %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// Run вызывает Sweep каждые every, пока не отменён ctx
+func (s *WaitlistService) Run(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := s.Sweep(ctx); err != nil {
+				log.Printf("waitlist sweep failed: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"bookinghub-backend/internal/domain"
+	"bookinghub-backend/internal/i18n"
+)
+
+// memBookings — брони одного ресурса в памяти; предложения берутся из waitlist
+type memBookings struct {
+	capacity int
+	items    []domain.Booking
+	locked   []uint64
+	waitlist *fakeWaitlist
+}
+
+func (m *memBookings) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (m *memBookings) LockResources(ctx context.Context, resourceIDs []uint64) ([]uint64, error) {
+	m.locked = append(m.locked, resourceIDs...)
+	return resourceIDs, nil
+}
+
+func (m *memBookings) BookingRules(ctx context.Context, resourceID uint64) (*domain.BookingRules, error) {
+	return &domain.BookingRules{Capacity: m.capacity}, nil
+}
+
+func (m *memBookings) ListOverlapping(ctx context.Context, resourceID uint64, from, to time.Time, includePending bool) ([]domain.Booking, error) {
+	var out []domain.Booking
+	for _, b := range m.items {
+		if b.StartAt.Before(to) && b.EndAt.After(from) {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+func (m *memBookings) Create(ctx context.Context, resourceID, userID uint64, startAt, endAt time.Time, quantity int) (uint64, error) {
+	m.items = append(m.items, domain.Booking{ID: uint64(len(m.items) + 1), ResourceID: resourceID, UserID: userID, StartAt: startAt, EndAt: endAt, Quantity: quantity})
+	return uint64(len(m.items)), nil
+}
+
+func (m *memBookings) ListActiveHolds(ctx context.Context, resourceID uint64, from, to, now time.Time) ([]domain.BookingHold, error) {
+	return nil, nil
+}
+
+func (m *memBookings) ListActiveOffers(ctx context.Context, resourceID uint64, from, to, now time.Time) ([]domain.WaitlistEntry, error) {
+	if m.waitlist == nil {
+		return nil, nil
+	}
+	return activeOffers(m.waitlist.entries, resourceID, from, to, now), nil
+}
+
+type fakeWaitlist struct {
+	entries []domain.WaitlistEntry
+}
+
+func (f *fakeWaitlist) find(id uint64) *domain.WaitlistEntry {
+	for i := range f.entries {
+		if f.entries[i].ID == id {
+			return &f.entries[i]
+		}
+	}
+	return nil
+}
+
+func (f *fakeWaitlist) Create(ctx context.Context, e domain.WaitlistEntry) (uint64, error) {
+	e.ID, e.Status = uint64(len(f.entries)+1), domain.WaitlistWaiting
+	f.entries = append(f.entries, e)
+	return e.ID, nil
+}
+
+func (f *fakeWaitlist) HasActive(ctx context.Context, userID, resourceID uint64, startAt, endAt time.Time) (bool, error) {
+	for _, e := range f.entries {
+		if e.UserID == userID && e.ResourceID == resourceID && e.StartAt.Equal(startAt) && e.EndAt.Equal(endAt) && e.Active() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeWaitlist) ListActiveForResource(ctx context.Context, resourceID uint64, now time.Time) ([]domain.WaitlistEntry, error) {
+	var out []domain.WaitlistEntry
+	for _, e := range f.entries {
+		if e.ResourceID == resourceID && e.Active() && e.StartAt.After(now) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeWaitlist) MarkOffered(ctx context.Context, id uint64, expiresAt time.Time) error {
+	e := f.find(id)
+	e.Status, e.ClaimExpiresAt = domain.WaitlistOffered, &expiresAt
+	return nil
+}
+
+func (f *fakeWaitlist) GetByID(ctx context.Context, id uint64) (*domain.WaitlistEntry, error) {
+	if e := f.find(id); e != nil {
+		cp := *e
+		return &cp, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeWaitlist) MarkFulfilled(ctx context.Context, id, bookingID uint64, from domain.WaitlistStatus) (bool, error) {
+	e := f.find(id)
+	if e.Status != from {
+		return false, nil
+	}
+	e.Status, e.BookingID = domain.WaitlistFulfilled, &bookingID
+	return true, nil
+}
+
+func (f *fakeWaitlist) SetStatus(ctx context.Context, id uint64, status domain.WaitlistStatus) error {
+	f.find(id).Status = status
+	return nil
+}
+
+func (f *fakeWaitlist) ExpireOffers(ctx context.Context, now time.Time) ([]uint64, error) {
+	var ids []uint64
+	for i := range f.entries {
+		if e := &f.entries[i]; e.Status == domain.WaitlistOffered && !e.ClaimExpiresAt.After(now) {
+			e.Status = domain.WaitlistExpired
+			ids = append(ids, e.ResourceID)
+		}
+	}
+	return ids, nil
+}
+
+func (f *fakeWaitlist) ExpireStarted(ctx context.Context, now time.Time) error { return nil }
+
+type silentNotifier struct{ got []domain.WaitlistEntry }
+
+func (n *silentNotifier) NotifyWaitlist(ctx context.Context, e domain.WaitlistEntry) {
+	n.got = append(n.got, e)
+}
+
+func TestWaitlistService_Release_NextInQueueGetsBooking(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+	end := start.Add(time.Hour)
+	bookings := &memBookings{capacity: 1, items: []domain.Booking{{ResourceID: 1, UserID: 9, StartAt: start, EndAt: end, Quantity: 1}}}
+	wl := &fakeWaitlist{}
+	notes := &silentNotifier{}
+	bookings.waitlist = wl
+	s := NewWaitlistService(wl, bookings).WithNotifier(notes)
+
+	for _, uid := range []uint64{2, 3} {
+		if _, err := s.Join(context.Background(), domain.WaitlistEntry{ResourceID: 1, UserID: uid, StartAt: start, EndAt: end, Quantity: 1, AutoBook: true}); err != nil {
+			t.Fatalf("join: %v", err)
+		}
+	}
+
+	// мешающую бронь отменили
+	bookings.items = nil
+	if err := s.Release(context.Background(), 1); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if wl.entries[0].Status != domain.WaitlistFulfilled || wl.entries[1].Status != domain.WaitlistWaiting {
+		t.Fatalf("only the first in queue must get the slot: %+v", wl.entries)
+	}
+	if len(bookings.items) != 1 || bookings.items[0].UserID != 2 {
+		t.Fatalf("expected booking for user 2, got %+v", bookings.items)
+	}
+	if len(notes.got) != 1 {
+		t.Fatalf("expected one notification, got %d", len(notes.got))
+	}
+	if len(bookings.locked) != 1 || bookings.locked[0] != 1 {
+		t.Fatalf("release must lock the resource row, locked=%v", bookings.locked)
+	}
+}
+
+func TestWaitlistService_Claim_LocksResource(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+	end := start.Add(time.Hour)
+	expires := time.Now().Add(time.Hour)
+	// второе предложение — на время, которое уже занято бронью
+	bookings := &memBookings{capacity: 1, items: []domain.Booking{{ResourceID: 4, UserID: 9, StartAt: end, EndAt: end.Add(time.Hour), Quantity: 1}}}
+	wl := &fakeWaitlist{entries: []domain.WaitlistEntry{
+		{ID: 1, ResourceID: 4, UserID: 2, StartAt: start, EndAt: end, Quantity: 1, Status: domain.WaitlistOffered, ClaimExpiresAt: &expires},
+		{ID: 2, ResourceID: 4, UserID: 3, StartAt: end, EndAt: end.Add(time.Hour), Quantity: 1, Status: domain.WaitlistOffered, ClaimExpiresAt: &expires},
+	}}
+	bookings.waitlist = wl
+	s := NewWaitlistService(wl, bookings).WithNotifier(&silentNotifier{})
+
+	id, err := s.Claim(context.Background(), wl.entries[0])
+	if err != nil || id != 2 || wl.entries[0].Status != domain.WaitlistFulfilled {
+		t.Fatalf("claim: id=%d err=%v entry=%+v", id, err, wl.entries[0])
+	}
+	if len(bookings.locked) != 1 || bookings.locked[0] != 4 {
+		t.Fatalf("claim must lock the resource row, locked=%v", bookings.locked)
+	}
+
+	// место уже занято — заявка возвращается в очередь
+	if _, err := s.Claim(context.Background(), wl.entries[1]); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if wl.entries[1].Status != domain.WaitlistWaiting || len(bookings.items) != 2 {
+		t.Fatalf("conflicting claim must return to queue: %+v", wl.entries[1])
+	}
+}
+
+func TestWaitlistService_Claim_StaleCopy(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+	end := start.Add(time.Hour)
+	expires := time.Now().Add(time.Hour)
+	bookings := &memBookings{capacity: 2}
+	wl := &fakeWaitlist{entries: []domain.WaitlistEntry{
+		{ID: 1, ResourceID: 4, UserID: 2, StartAt: start, EndAt: end, Quantity: 1, Status: domain.WaitlistOffered, ClaimExpiresAt: &expires},
+	}}
+	bookings.waitlist = wl
+	s := NewWaitlistService(wl, bookings).WithNotifier(&silentNotifier{})
+
+	// оба запроса прочитали заявку до того, как первый её выполнил
+	stale := wl.entries[0]
+	if _, err := s.Claim(context.Background(), stale); err != nil {
+		t.Fatalf("first claim: %v", err)
+	}
+	if _, err := s.Claim(context.Background(), stale); !errors.Is(err, ErrClaimNotOffered) {
+		t.Fatalf("expected ErrClaimNotOffered, got %v", err)
+	}
+	if len(bookings.items) != 1 {
+		t.Fatalf("one offer must give one booking, got %+v", bookings.items)
+	}
+}
+
+func TestWaitlistService_OfferHoldsSlotUntilExpired(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+	end := start.Add(time.Hour)
+	bookings := &memBookings{capacity: 1}
+	now := time.Now()
+	wl := &fakeWaitlist{entries: []domain.WaitlistEntry{
+		{ID: 1, ResourceID: 1, UserID: 2, StartAt: start, EndAt: end, Quantity: 1, Status: domain.WaitlistWaiting},
+		{ID: 2, ResourceID: 1, UserID: 3, StartAt: start, EndAt: end, Quantity: 1, AutoBook: true, Status: domain.WaitlistWaiting},
+	}}
+	bookings.waitlist = wl
+	s := NewWaitlistService(wl, bookings).WithNotifier(&silentNotifier{})
+	s.now = func() time.Time { return now }
+
+	if err := s.Release(context.Background(), 1); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if wl.entries[0].Status != domain.WaitlistOffered || wl.entries[1].Status != domain.WaitlistWaiting {
+		t.Fatalf("offer must hold the slot: %+v", wl.entries)
+	}
+
+	// предложение не подтвердили — место уходит следующему
+	now = now.Add(DefaultClaimTTL + time.Minute)
+	if err := s.Sweep(context.Background()); err != nil {
+		t.Fatalf("sweep: %v", err)
+	}
+	if wl.entries[0].Status != domain.WaitlistExpired || wl.entries[1].Status != domain.WaitlistFulfilled {
+		t.Fatalf("expired offer must pass the slot on: %+v", wl.entries)
+	}
+	if _, err := s.Claim(context.Background(), wl.entries[0]); !errors.Is(err, ErrClaimNotOffered) {
+		t.Fatalf("expected ErrClaimNotOffered, got %v", err)
+	}
+}
+
+func TestWaitlistService_Join_FreeSlot(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour)
+	s := NewWaitlistService(&fakeWaitlist{}, &memBookings{capacity: 1})
+
+	_, err := s.Join(context.Background(), domain.WaitlistEntry{ResourceID: 1, UserID: 2, StartAt: start, EndAt: start.Add(time.Hour), Quantity: 1})
+	if !errors.Is(err, ErrSlotAvailable) {
+		t.Fatalf("expected ErrSlotAvailable, got %v", err)
+	}
+}
+
+func TestWaitlistNotice(t *testing.T) {
+	start := time.Date(2030, 1, 7, 7, 0, 0, 0, time.UTC)
+	bookingID := uint64(12)
+	booked := domain.WaitlistEntry{Status: domain.WaitlistFulfilled, BookingID: &bookingID, StartAt: start}
+
+	if got := WaitlistNotice(i18n.EN, booked); got != "A spot opened up: booking #12 for 2030-01-07 07:00 UTC was created automatically" {
+		t.Fatalf("unexpected EN notice %q", got)
+	}
+	if got := WaitlistNotice(i18n.RU, booked); got != "Место освободилось: бронь #12 на 2030-01-07 07:00 UTC создана автоматически" {
+		t.Fatalf("unexpected RU notice %q", got)
+	}
+	if got := WaitlistNotice(i18n.EN, domain.WaitlistEntry{Status: domain.WaitlistWaiting}); got != "" {
+		t.Fatalf("waiting entry needs no notice, got %q", got)
+	}
+}
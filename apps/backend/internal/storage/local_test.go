@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestLocalStore_PutGetDelete(t *testing.T) {
+	s := NewLocalStore(t.TempDir(), "/uploads/")
+	ctx := context.Background()
+
+	if err := s.Put(ctx, "resources/1/a.jpg", []byte("data"), "image/jpeg"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	rc, err := s.Get(ctx, "resources/1/a.jpg")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if string(got) != "data" {
+		t.Fatalf("unexpected content %q", got)
+	}
+
+	if u := s.URL("resources/1/a.jpg"); u != "/uploads/resources/1/a.jpg" {
+		t.Fatalf("unexpected url %q", u)
+	}
+
+	if err := s.Delete(ctx, "resources/1/a.jpg"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.Delete(ctx, "resources/1/a.jpg"); err != nil {
+		t.Fatalf("second Delete must be a no-op, got %v", err)
+	}
+	if _, err := s.Get(ctx, "resources/1/a.jpg"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestLocalStore_RejectsEscapingKeys(t *testing.T) {
+	s := NewLocalStore(t.TempDir(), "/uploads")
+	for _, key := range []string{"", "/etc/passwd", "../x", "a/../../x", "a//b", `a\b`} {
+		if err := s.Put(context.Background(), key, []byte("x"), ""); !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("key %q: expected ErrInvalidKey, got %v", key, err)
+		}
+	}
+}
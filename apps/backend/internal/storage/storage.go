@@ -0,0 +1,39 @@
+// Package storage — хранилище файлов (фото объявлений и т.п.) за интерфейсом BlobStore.
+// Реализации: LocalStore (файлы на диске) и S3Store (любой S3-совместимый сервис: AWS, MinIO, ...).
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("объект не найден")
+	ErrInvalidKey = errors.New("некорректный ключ объекта")
+)
+
+// BlobStore — минимальный набор операций над объектами по ключу вида "resources/1/abc.jpg"
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get возвращает содержимое объекта; вызывающий закрывает reader
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete не считает ошибкой отсутствие объекта
+	Delete(ctx context.Context, key string) error
+	// URL — публичный адрес объекта для клиента
+	URL(key string) string
+}
+
+// validKey запрещает пустые, абсолютные ключи и выход за пределы хранилища
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
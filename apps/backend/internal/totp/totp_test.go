@@ -0,0 +1,90 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// секрет из приложений к RFC 4226 и RFC 6238
+const rfcKey = "12345678901234567890"
+
+func TestHOTP_RFC4226(t *testing.T) {
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for c, w := range want {
+		if got := hotp([]byte(rfcKey), uint64(c), 6); got != w {
+			t.Errorf("HOTP(%d) = %s, want %s", c, got, w)
+		}
+	}
+}
+
+func TestTOTP_RFC6238_SHA1(t *testing.T) {
+	cases := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, c := range cases {
+		if got := hotp([]byte(rfcKey), uint64(Step(time.Unix(c.unix, 0))), 8); got != c.want {
+			t.Errorf("TOTP(%d) = %s, want %s", c.unix, got, c.want)
+		}
+	}
+}
+
+func TestCodeAndVerify(t *testing.T) {
+	secret := encoding.EncodeToString([]byte(rfcKey))
+	at := time.Unix(1111111111, 0)
+
+	code, err := Code(secret, at)
+	if err != nil || code != "050471" {
+		t.Fatalf("Code = %q, %v", code, err)
+	}
+	step, ok := Verify(secret, code, at, 1)
+	if !ok || step != Step(at) {
+		t.Fatalf("Verify = %d, %v", step, ok)
+	}
+	// предыдущий шаг укладывается в окно, шаг за его пределами — нет
+	if _, ok := Verify(secret, code, at.Add(Period), 1); !ok {
+		t.Fatal("code from the previous step should pass with skew 1")
+	}
+	if _, ok := Verify(secret, code, at.Add(2*Period), 1); ok {
+		t.Fatal("code two steps old should be rejected")
+	}
+	if _, ok := Verify(secret, "000000", at, 1); ok {
+		t.Fatal("wrong code accepted")
+	}
+	if _, ok := Verify(secret, "50471", at, 1); ok {
+		t.Fatal("short code accepted")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := GenerateSecret()
+	if a == b || len(a) != 32 || strings.Contains(a, "=") {
+		t.Fatalf("secrets %q, %q", a, b)
+	}
+	if _, err := Code(a, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Code("not base32!", time.Now()); err != ErrInvalidSecret {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestURI(t *testing.T) {
+	got := URI("BookingHub", "admin@test.local", "ABC")
+	want := "otpauth://totp/BookingHub:admin@test.local?algorithm=SHA1&digits=6&issuer=BookingHub&period=30&secret=ABC"
+	if got != want {
+		t.Fatalf("URI = %s", got)
+	}
+}
@@ -0,0 +1,274 @@
+// Package validate — декларативная проверка DTO запросов по тегам `validate:"..."`.
+//
+// Правила перечисляются через запятую:
+//
+//	required   строка не пустая, срез не пустой, указатель не nil, число не 0
+//	trim       обрезать пробелы по краям строки перед остальными проверками (меняет значение)
+//	min=N      строка — не короче N символов, число — не меньше N, срез — не меньше N элементов
+//	max=N      то же с верхней границей; длина строк считается в символах, как VARCHAR в MySQL
+//	email      адрес в виде local@domain без имени и угловых скобок
+//	oneof=a b  значение из списка (для строк — без учёта регистра)
+//
+// Пустые необязательные значения (nil, "") пропускаются. Вложенные структуры и срезы структур
+// проверяются рекурсивно, имя поля берётся из тега json: items[1].resourceId.
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Правила, которые могут попасть в FieldError.Rule
+const (
+	RuleRequired = "required"
+	RuleMin      = "min"
+	RuleMax      = "max"
+	RuleEmail    = "email"
+	RuleOneOf    = "oneof"
+)
+
+// FieldError — нарушенное правило: поле (путь в JSON), правило и его параметр
+type FieldError struct {
+	Field string
+	Rule  string
+	Param string
+	// Kind — что проверялось: "string", "number" или "slice"; от этого зависит текст для min/max
+	Kind string
+}
+
+func (e FieldError) Error() string {
+	if e.Param == "" {
+		return e.Field + ": " + e.Rule
+	}
+	return e.Field + ": " + e.Rule + "=" + e.Param
+}
+
+// Errors — все нарушения в запросе, в порядке полей
+type Errors []FieldError
+
+func (es Errors) Error() string {
+	parts := make([]string, len(es))
+	for i, e := range es {
+		parts[i] = e.Error()
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// Struct проверяет структуру по указателю и возвращает Errors со всеми нарушениями или nil.
+// Правило trim меняет поля, поэтому нужен указатель.
+func Struct(ptr any) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		panic("validate: Struct ожидает указатель на структуру")
+	}
+	var errs Errors
+	walkStruct(v.Elem(), "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Email — корректен ли адрес: ровно local@domain, без имени, комментариев и угловых скобок
+func Email(s string) bool {
+	if s == "" || strings.ContainsAny(s, "<> ") {
+		return false
+	}
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Name != "" || addr.Address != s {
+		return false
+	}
+	at := strings.LastIndexByte(s, '@')
+	domain := s[at+1:]
+	return strings.Contains(domain, ".") && !strings.HasPrefix(domain, ".") && !strings.HasSuffix(domain, ".")
+}
+
+func walkStruct(v reflect.Value, prefix string, errs *Errors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := jsonName(sf)
+		if name == "-" {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		fv := v.Field(i)
+		if tag, ok := sf.Tag.Lookup("validate"); ok && tag != "-" {
+			checkField(fv, path, tag, errs)
+		}
+		walkNested(fv, path, errs)
+	}
+}
+
+// walkNested спускается во вложенные структуры и срезы структур
+func walkNested(v reflect.Value, path string, errs *Errors) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			walkNested(v.Elem(), path, errs)
+		}
+	case reflect.Struct:
+		walkStruct(v, path, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			el := v.Index(i)
+			if el.Kind() == reflect.Struct || (el.Kind() == reflect.Pointer && el.Type().Elem().Kind() == reflect.Struct) {
+				walkNested(el, path+"["+strconv.Itoa(i)+"]", errs)
+			}
+		}
+	}
+}
+
+func jsonName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" {
+		return sf.Name
+	}
+	return name
+}
+
+type rule struct {
+	name  string
+	param string
+}
+
+func parseRules(tag string) []rule {
+	var rules []rule
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, param, _ := strings.Cut(part, "=")
+		rules = append(rules, rule{name: name, param: param})
+	}
+	return rules
+}
+
+func checkField(v reflect.Value, path, tag string, errs *Errors) {
+	rules := parseRules(tag)
+
+	// указатель: nil допустим, если поле не required; иначе проверяется значение
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			for _, r := range rules {
+				if r.name == RuleRequired {
+					*errs = append(*errs, FieldError{Field: path, Rule: RuleRequired, Kind: kindOf(v.Type().Elem())})
+				}
+			}
+			return
+		}
+		v = v.Elem()
+	}
+
+	for _, r := range rules {
+		if r.name == "trim" && v.Kind() == reflect.String && v.CanSet() {
+			v.SetString(strings.TrimSpace(v.String()))
+		}
+	}
+
+	kind := kindOf(v.Type())
+	empty := v.IsZero()
+	if kind == "slice" {
+		empty = v.Len() == 0
+	}
+	for _, r := range rules {
+		switch r.name {
+		case "trim":
+		case RuleRequired:
+			if empty {
+				*errs = append(*errs, FieldError{Field: path, Rule: RuleRequired, Kind: kind})
+				return
+			}
+		case RuleMin, RuleMax:
+			if empty && kind != "number" {
+				continue
+			}
+			limit, err := strconv.ParseFloat(r.param, 64)
+			if err != nil {
+				panic(fmt.Sprintf("validate: %s: некорректный параметр %q", path, r.param))
+			}
+			n, ok := measure(v)
+			if !ok {
+				panic(fmt.Sprintf("validate: %s: %s не применимо к %s", path, r.name, v.Type()))
+			}
+			if (r.name == RuleMin && n < limit) || (r.name == RuleMax && n > limit) {
+				*errs = append(*errs, FieldError{Field: path, Rule: r.name, Param: r.param, Kind: kind})
+				return
+			}
+		case RuleEmail:
+			if !empty && !Email(v.String()) {
+				*errs = append(*errs, FieldError{Field: path, Rule: RuleEmail, Kind: kind})
+				return
+			}
+		case RuleOneOf:
+			if empty {
+				continue
+			}
+			if !oneOf(v, strings.Fields(r.param)) {
+				*errs = append(*errs, FieldError{Field: path, Rule: RuleOneOf, Param: strings.Join(strings.Fields(r.param), ", "), Kind: kind})
+				return
+			}
+		default:
+			panic(fmt.Sprintf("validate: %s: неизвестное правило %q", path, r.name))
+		}
+	}
+}
+
+func kindOf(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "slice"
+	default:
+		return "number"
+	}
+}
+
+// measure — длина строки в символах, число элементов или само число
+func measure(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+func oneOf(v reflect.Value, allowed []string) bool {
+	var s string
+	switch v.Kind() {
+	case reflect.String:
+		s = v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s = strconv.FormatUint(v.Uint(), 10)
+	default:
+		return false
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(s, a) {
+			return true
+		}
+	}
+	return false
+}
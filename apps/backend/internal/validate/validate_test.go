@@ -0,0 +1,126 @@
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type itemReq struct {
+	ResourceID uint64 `json:"resourceId" validate:"required"`
+	Quantity   *int   `json:"quantity" validate:"min=1"`
+}
+
+type sampleReq struct {
+	Email    string    `json:"email" validate:"trim,required,max=190,email"`
+	Title    string    `json:"title" validate:"trim,required,max=5"`
+	Note     *string   `json:"note" validate:"max=3"`
+	Price    int       `json:"price" validate:"min=0"`
+	Kind     string    `json:"kind" validate:"oneof=A B"`
+	Items    []itemReq `json:"items" validate:"required,max=2"`
+	Untagged string    `json:"untagged"`
+}
+
+func intPtr(v int) *int       { return &v }
+func strPtr(v string) *string { return &v }
+
+func TestStruct_Valid_TrimsStrings(t *testing.T) {
+	req := sampleReq{
+		Email: "  user@example.com ",
+		Title: " Зал ",
+		Kind:  "b",
+		Items: []itemReq{{ResourceID: 1}},
+	}
+	if err := Struct(&req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Email != "user@example.com" || req.Title != "Зал" {
+		t.Fatalf("trim not applied: %+v", req)
+	}
+}
+
+func TestStruct_ReportsAllErrors(t *testing.T) {
+	req := sampleReq{
+		Email: "not-an-email",
+		Title: "Слишком длинно",
+		Note:  strPtr("abcd"),
+		Price: -1,
+		Kind:  "C",
+		Items: []itemReq{{ResourceID: 1, Quantity: intPtr(0)}, {}, {ResourceID: 3}},
+	}
+	err := Struct(&req)
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected Errors, got %v", err)
+	}
+	got := map[string]string{}
+	for _, e := range errs {
+		got[e.Field] = e.Rule
+	}
+	want := map[string]string{
+		"email":               RuleEmail,
+		"title":               RuleMax,
+		"note":                RuleMax,
+		"price":               RuleMin,
+		"kind":                RuleOneOf,
+		"items":               RuleMax,
+		"items[0].quantity":   RuleMin,
+		"items[1].resourceId": RuleRequired,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("errors mismatch:\n got  %v\n want %v", got, want)
+	}
+}
+
+func TestStruct_Required(t *testing.T) {
+	req := sampleReq{Email: "   ", Items: []itemReq{}}
+	err := Struct(&req)
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("expected 3 errors, got %v", err)
+	}
+	for _, e := range errs {
+		if e.Rule != RuleRequired {
+			t.Fatalf("expected only required errors, got %v", errs)
+		}
+	}
+}
+
+func TestStruct_MaxCountsRunes(t *testing.T) {
+	// VARCHAR считает символы, а не байты: 5 кириллических букв — это 10 байт, но укладываются в max=5
+	req := sampleReq{Email: "a@b.ru", Title: "ёжики", Items: []itemReq{{ResourceID: 1}}}
+	if err := Struct(&req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEmail(t *testing.T) {
+	valid := []string{"user@example.com", "first.last+tag@sub.example.org", "a@b.co"}
+	invalid := []string{
+		"", "user", "user@", "@example.com", "user@example", "user@.com", "user@example.",
+		"User <user@example.com>", "<user@example.com>", "user @example.com", "user@example.com (comment)",
+	}
+	for _, s := range valid {
+		if !Email(s) {
+			t.Errorf("%q should be valid", s)
+		}
+	}
+	for _, s := range invalid {
+		if Email(s) {
+			t.Errorf("%q should be invalid", s)
+		}
+	}
+}
+
+func TestStruct_UnknownRulePanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "неизвестное правило") {
+			t.Fatalf("expected panic for unknown rule, got %v", r)
+		}
+	}()
+	var req struct {
+		Name string `json:"name" validate:"requierd"`
+	}
+	_ = Struct(&req)
+}
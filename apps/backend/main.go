@@ -1,30 +1,24 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"strconv"
 	"time"
+	_ "time/tzdata" // база поясов IANA внутри бинарника: ресурсы хранят пояс по имени
 
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
 
 	"bookinghub-backend/internal/db"
-	"bookinghub-backend/internal/domain"
-	"bookinghub-backend/internal/handler"
-	"bookinghub-backend/internal/repo"
+	"bookinghub-backend/internal/server"
 	"bookinghub-backend/internal/service"
 )
 
-type App struct {
-	DB *sqlx.DB
-}
-
 func main() {
 	_ = godotenv.Load()
 	jwtSecret := getEnv("JWT_SECRET", "dev-secret")
@@ -41,7 +35,10 @@ func main() {
 	dbName := getEnv("DB_NAME", "bookinghub")
 
 	// Добавлен параметр &tls=skip-verify в конце строки
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&charset=utf8mb4&collation=utf8mb4_unicode_ci&tls=skip-verify",
+	// multiStatements нужен миграциям, в которых несколько SQL-запросов
+	// loc=UTC и time_zone='+00:00': DATETIME и TIMESTAMP хранятся и читаются в UTC
+	// независимо от пояса сервера приложения и MySQL
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&loc=UTC&time_zone=%%27%%2B00%%3A00%%27&charset=utf8mb4&collation=utf8mb4_unicode_ci&multiStatements=true&tls=skip-verify",
 		dbUser, dbPass, dbHost, dbPort, dbName,
 	)
 
@@ -57,122 +54,15 @@ func main() {
 		log.Fatalf("failed to apply migrations: %v", err)
 	}
 
-	app := &App{DB: dbx}
-
-	resourceRepo := repo.NewResourceRepo(dbx)
-	resourceHandler := handler.NewResourceHandler(resourceRepo)
-	categoryRepo := repo.NewCategoryRepo(dbx)
-	categoryHandler := handler.NewCategoryHandler(categoryRepo)
-	userRepo := repo.NewUserRepo(dbx)
-	authHandler := handler.NewAuthHandler(userRepo, authSvc)
-	bookingRepo := repo.NewBookingRepo(dbx)
-	bookingSvc := service.NewBookingService(bookingRepo)
-	bookingHandler := handler.NewBookingHandler(bookingRepo, userRepo, bookingSvc)
-	resourceBookingsHandler := handler.NewResourceBookingsHandler(bookingRepo)
-	userHandler := handler.NewUserHandler(userRepo)
-
-	r := chi.NewRouter()
-
-	// --- НАЧАЛО ВСТАВКИ CORS ---
-	r.Use(func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Разрешаем запросы с любого домена (для тестов это ок)
-			w.Header().Set("Access-Control-Allow-Origin", "https://bookinghub-wheat.vercel.app")
-			// Разрешаем стандартные методы
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-			// Разрешаем заголовки, которые важны для JSON и авторизации
-			w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-CSRF-Token")
-
-			// Если это предварительный запрос (OPTIONS), сразу отвечаем 200
-			if r.Method == "OPTIONS" {
-				w.WriteHeader(http.StatusOK)
-				return
-			}
-			next.ServeHTTP(w, r)
-		})
-	})
-	// --- КОНЕЦ ВСТАВКИ CORS ---
-
-	// Логи + базовая защита от паники
-	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
-
-	r.Route("/api", func(r chi.Router) {
-		r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("ok"))
-		})
-
-		r.Get("/categories", categoryHandler.List)
-
-		r.Get("/resources", resourceHandler.List)
-
-		r.Get("/users/{id}", userHandler.PublicByID)
-
-		// Создание ресурса — только для авторизованных
-		r.With(handler.AuthMiddleware(authSvc)).Post("/resources", resourceHandler.Create)
-
-		r.Route("/auth", func(r chi.Router) {
-			r.Post("/register", authHandler.Register)
-			r.Post("/login", authHandler.Login)
-
-			// защищённый роут
-			r.With(handler.AuthMiddleware(authSvc)).Get("/me", authHandler.Me)
-			r.With(handler.AuthMiddleware(authSvc)).Patch("/me", authHandler.UpdateMe)
-			r.With(handler.AuthMiddleware(authSvc)).Post("/password", authHandler.ChangePassword)
-			r.With(handler.AuthMiddleware(authSvc)).Delete("/me", authHandler.DeleteMe)
-		})
-
-		// Бронирования: только авторизованные
-		r.With(handler.AuthMiddleware(authSvc)).Get("/bookings/my", bookingHandler.My)
-		r.With(handler.AuthMiddleware(authSvc)).Post("/bookings", bookingHandler.Create)
-
-		// Менеджер: смотреть ожидающие и менять статус
-		r.With(handler.AuthMiddleware(authSvc)).Get("/bookings/pending", bookingHandler.Pending)
-
-		r.With(handler.AuthMiddleware(authSvc)).Patch("/bookings/{id}/status", bookingHandler.UpdateStatus)
-
-		r.With(handler.AuthMiddleware(authSvc)).Post("/bookings/{id}/cancel", bookingHandler.Cancel)
-
-		r.Get("/resources/{id}/bookings", resourceBookingsHandler.List)
-
-		r.With(handler.AuthMiddleware(authSvc)).Get("/resources/my", resourceHandler.My)
-
-		r.With(
-			handler.AuthMiddleware(authSvc),
-			handler.RequireRoles(domain.RoleAdmin),
-		).Post("/categories", categoryHandler.Create)
-
-		r.With(
-			handler.AuthMiddleware(authSvc),
-			handler.RequireRoles(domain.RoleAdmin),
-		).Patch("/categories/{id}", categoryHandler.Update)
-
-		r.With(
-			handler.AuthMiddleware(authSvc),
-			handler.RequireRoles(domain.RoleAdmin),
-		).Delete("/categories/{id}", categoryHandler.Delete)
-	})
-
-	r.Get("/db/ping", app.handleDBPing)
+	srv := server.New(&server.App{DB: dbx}, authSvc)
+	srv.RunBackground(context.Background())
 
 	log.Printf("Backend started on http://localhost:%s", port)
-	if err := http.ListenAndServe(":"+port, r); err != nil {
+	if err := http.ListenAndServe(":"+port, srv.Router); err != nil {
 		log.Fatal(err)
 	}
 }
 
-func (a *App) handleDBPing(w http.ResponseWriter, r *http.Request) {
-	if err := a.DB.Ping(); err != nil {
-		http.Error(w, "db not reachable: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("db ok"))
-}
-
 func getEnv(key, fallback string) string {
 	val := os.Getenv(key)
 	if val == "" {
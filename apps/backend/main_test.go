@@ -1,13 +1,8 @@
 package main
 
 import (
-	"net/http"
-	"net/http/httptest"
 	"os"
 	"testing"
-
-	"github.com/DATA-DOG/go-sqlmock"
-	"github.com/jmoiron/sqlx"
 )
 
 func TestGetEnv(t *testing.T) {
@@ -21,27 +16,3 @@ func TestGetEnv(t *testing.T) {
 		t.Fatalf("expected abc, got %q", got)
 	}
 }
-
-func TestHandleDBPing_OK(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("sqlmock.New: %v", err)
-	}
-	defer db.Close()
-
-	mock.ExpectPing()
-
-	app := &App{DB: sqlx.NewDb(db, "sqlmock")}
-
-	req := httptest.NewRequest(http.MethodGet, "/db/ping", nil)
-	rr := httptest.NewRecorder()
-
-	app.handleDBPing(rr, req)
-
-	if rr.Code != http.StatusOK {
-		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
-	}
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Fatalf("sql expectations: %v", err)
-	}
-}
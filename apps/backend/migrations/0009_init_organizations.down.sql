@@ -0,0 +1,8 @@
+ALTER TABLE resources
+  DROP FOREIGN KEY fk_resources_organization,
+  DROP KEY idx_resources_organization,
+  DROP COLUMN organization_id;
+
+DROP TABLE IF EXISTS organization_invites;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
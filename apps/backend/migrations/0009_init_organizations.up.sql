@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS organizations (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  name VARCHAR(150) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS organization_members (
+  organization_id BIGINT UNSIGNED NOT NULL,
+  user_id BIGINT UNSIGNED NOT NULL,
+  role ENUM('OWNER','MANAGER','VIEWER') NOT NULL DEFAULT 'VIEWER',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (organization_id, user_id),
+  KEY idx_organization_members_user (user_id),
+  CONSTRAINT fk_organization_members_org
+    FOREIGN KEY (organization_id) REFERENCES organizations(id)
+    ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT fk_organization_members_user
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS organization_invites (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  organization_id BIGINT UNSIGNED NOT NULL,
+  email VARCHAR(190) NOT NULL,
+  role ENUM('OWNER','MANAGER','VIEWER') NOT NULL DEFAULT 'VIEWER',
+  token CHAR(64) NOT NULL,
+  invited_by BIGINT UNSIGNED NOT NULL,
+  status ENUM('PENDING','ACCEPTED','DECLINED') NOT NULL DEFAULT 'PENDING',
+  expires_at DATETIME NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_organization_invites_token (token),
+  KEY idx_organization_invites_email (email),
+  CONSTRAINT fk_organization_invites_org
+    FOREIGN KEY (organization_id) REFERENCES organizations(id)
+    ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE resources
+  ADD COLUMN organization_id BIGINT UNSIGNED NULL AFTER owner_user_id,
+  ADD KEY idx_resources_organization (organization_id),
+  ADD CONSTRAINT fk_resources_organization
+    FOREIGN KEY (organization_id) REFERENCES organizations(id)
+    ON DELETE SET NULL ON UPDATE CASCADE;
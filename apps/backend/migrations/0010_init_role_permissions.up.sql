@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS role_permissions (
+  role ENUM('INDIVIDUAL','COMPANY','ADMIN') NOT NULL,
+  permission VARCHAR(64) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (role, permission)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO role_permissions (role, permission) VALUES
+  ('ADMIN',   'booking:approve'),
+  ('ADMIN',   'booking:view_all'),
+  ('ADMIN',   'resource:edit'),
+  ('ADMIN',   'category:manage'),
+  ('ADMIN',   'organization:create'),
+  ('ADMIN',   'permission:manage'),
+  ('COMPANY', 'organization:create')
+ON DUPLICATE KEY UPDATE permission = VALUES(permission);
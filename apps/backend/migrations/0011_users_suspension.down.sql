@@ -0,0 +1,6 @@
+DELETE FROM role_permissions WHERE permission = 'user:manage';
+
+ALTER TABLE users
+  DROP COLUMN sessions_revoked_at,
+  DROP COLUMN suspend_reason,
+  DROP COLUMN suspended_at;
@@ -0,0 +1,8 @@
+ALTER TABLE users
+  ADD COLUMN suspended_at DATETIME NULL,
+  ADD COLUMN suspend_reason VARCHAR(255) NULL,
+  ADD COLUMN sessions_revoked_at DATETIME NULL;
+
+INSERT INTO role_permissions (role, permission) VALUES
+  ('ADMIN', 'user:manage')
+ON DUPLICATE KEY UPDATE permission = VALUES(permission);
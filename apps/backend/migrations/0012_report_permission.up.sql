@@ -0,0 +1,3 @@
+INSERT INTO role_permissions (role, permission) VALUES
+  ('ADMIN', 'report:view_all')
+ON DUPLICATE KEY UPDATE permission = VALUES(permission);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS resource_opening_hours (
+  resource_id BIGINT UNSIGNED NOT NULL,
+  weekday TINYINT UNSIGNED NOT NULL, -- 1 = понедельник ... 7 = воскресенье
+  opens_at TIME NOT NULL,
+  closes_at TIME NOT NULL,
+  PRIMARY KEY (resource_id, weekday, opens_at),
+  CONSTRAINT fk_opening_hours_resource
+    FOREIGN KEY (resource_id) REFERENCES resources(id)
+    ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
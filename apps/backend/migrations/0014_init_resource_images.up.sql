@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS resource_images (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  resource_id BIGINT UNSIGNED NOT NULL,
+  storage_key VARCHAR(255) NOT NULL,
+  thumb_key VARCHAR(255) NOT NULL,
+  content_type VARCHAR(50) NOT NULL,
+  size_bytes INT UNSIGNED NOT NULL,
+  width INT UNSIGNED NOT NULL,
+  height INT UNSIGNED NOT NULL,
+  position INT UNSIGNED NOT NULL DEFAULT 0,
+  is_cover BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY idx_resource_images_resource (resource_id, position),
+  CONSTRAINT fk_resource_images_resource
+    FOREIGN KEY (resource_id) REFERENCES resources(id)
+    ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
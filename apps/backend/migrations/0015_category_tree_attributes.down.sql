@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS resource_attribute_values;
+DROP TABLE IF EXISTS category_attributes;
+
+ALTER TABLE resource_categories
+  DROP FOREIGN KEY fk_resource_categories_parent,
+  DROP KEY idx_resource_categories_parent,
+  DROP COLUMN parent_id;
@@ -0,0 +1,39 @@
+ALTER TABLE resource_categories
+  ADD COLUMN parent_id BIGINT UNSIGNED NULL AFTER id,
+  ADD KEY idx_resource_categories_parent (parent_id),
+  ADD CONSTRAINT fk_resource_categories_parent
+    FOREIGN KEY (parent_id) REFERENCES resource_categories(id)
+    ON DELETE RESTRICT ON UPDATE CASCADE;
+
+CREATE TABLE IF NOT EXISTS category_attributes (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  category_id BIGINT UNSIGNED NOT NULL,
+  code VARCHAR(50) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  type ENUM('int','number','string','bool','enum') NOT NULL,
+  required BOOLEAN NOT NULL DEFAULT FALSE,
+  options JSON NULL, -- допустимые значения для type = 'enum'
+  position INT UNSIGNED NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_category_attributes_code (category_id, code),
+  CONSTRAINT fk_category_attributes_category
+    FOREIGN KEY (category_id) REFERENCES resource_categories(id)
+    ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS resource_attribute_values (
+  resource_id BIGINT UNSIGNED NOT NULL,
+  attribute_id BIGINT UNSIGNED NOT NULL,
+  value VARCHAR(255) NOT NULL,      -- каноническое строковое представление
+  value_num DECIMAL(20,6) NULL,     -- для int/number: фильтры по диапазону
+  PRIMARY KEY (resource_id, attribute_id),
+  KEY idx_attribute_values_attr (attribute_id, value),
+  KEY idx_attribute_values_num (attribute_id, value_num),
+  CONSTRAINT fk_attribute_values_resource
+    FOREIGN KEY (resource_id) REFERENCES resources(id)
+    ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT fk_attribute_values_attribute
+    FOREIGN KEY (attribute_id) REFERENCES category_attributes(id)
+    ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
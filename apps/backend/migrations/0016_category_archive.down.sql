@@ -0,0 +1,2 @@
+ALTER TABLE resource_categories
+  DROP COLUMN archived_at;
@@ -0,0 +1,2 @@
+ALTER TABLE resource_categories
+  ADD COLUMN archived_at TIMESTAMP NULL DEFAULT NULL AFTER name;
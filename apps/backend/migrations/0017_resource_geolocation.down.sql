@@ -0,0 +1,10 @@
+ALTER TABLE resources
+  DROP KEY idx_resources_lat_lng,
+  DROP KEY idx_resources_geohash,
+  DROP COLUMN geohash,
+  DROP COLUMN longitude,
+  DROP COLUMN latitude,
+  DROP COLUMN country,
+  DROP COLUMN postal_code,
+  DROP COLUMN city,
+  DROP COLUMN address_line;
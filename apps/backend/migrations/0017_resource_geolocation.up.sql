@@ -0,0 +1,11 @@
+ALTER TABLE resources
+  ADD COLUMN address_line VARCHAR(255) NULL AFTER location,
+  ADD COLUMN city VARCHAR(100) NULL AFTER address_line,
+  ADD COLUMN postal_code VARCHAR(20) NULL AFTER city,
+  ADD COLUMN country VARCHAR(100) NULL AFTER postal_code,
+  ADD COLUMN latitude DECIMAL(9,6) NULL AFTER country,
+  ADD COLUMN longitude DECIMAL(9,6) NULL AFTER latitude,
+  -- geohash точки: поиск по области через LIKE 'префикс%' по индексу
+  ADD COLUMN geohash CHAR(12) NULL AFTER longitude,
+  ADD KEY idx_resources_geohash (geohash),
+  ADD KEY idx_resources_lat_lng (latitude, longitude);
@@ -0,0 +1,6 @@
+ALTER TABLE bookings
+  DROP CHECK chk_booking_quantity,
+  DROP COLUMN quantity;
+
+ALTER TABLE resources
+  DROP COLUMN capacity;
@@ -0,0 +1,8 @@
+-- сколько одинаковых единиц сдаётся под одним объявлением (10 ноутбуков, 30 рабочих мест)
+ALTER TABLE resources
+  ADD COLUMN capacity INT UNSIGNED NOT NULL DEFAULT 1 AFTER price_per_hour;
+
+-- сколько единиц ресурса занимает бронь
+ALTER TABLE bookings
+  ADD COLUMN quantity INT UNSIGNED NOT NULL DEFAULT 1 AFTER end_at,
+  ADD CONSTRAINT chk_booking_quantity CHECK (quantity >= 1);
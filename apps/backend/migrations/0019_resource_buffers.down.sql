@@ -0,0 +1,3 @@
+ALTER TABLE resources
+  DROP COLUMN buffer_after_min,
+  DROP COLUMN buffer_before_min;
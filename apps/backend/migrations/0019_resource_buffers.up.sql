@@ -0,0 +1,4 @@
+-- технические перерывы вокруг каждой брони (уборка, подготовка), в минутах
+ALTER TABLE resources
+  ADD COLUMN buffer_before_min INT UNSIGNED NOT NULL DEFAULT 0 AFTER capacity,
+  ADD COLUMN buffer_after_min INT UNSIGNED NOT NULL DEFAULT 0 AFTER buffer_before_min;
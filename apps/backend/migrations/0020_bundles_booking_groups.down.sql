@@ -0,0 +1,10 @@
+ALTER TABLE bookings
+  DROP FOREIGN KEY fk_bookings_bundle,
+  DROP FOREIGN KEY fk_bookings_group,
+  DROP KEY idx_bookings_group,
+  DROP COLUMN bundle_id,
+  DROP COLUMN group_id;
+
+DROP TABLE IF EXISTS booking_groups;
+DROP TABLE IF EXISTS resource_bundle_items;
+DROP TABLE IF EXISTS resource_bundles;
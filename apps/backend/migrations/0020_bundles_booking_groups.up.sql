@@ -0,0 +1,58 @@
+-- набор ресурсов одного владельца, который бронируется как одна позиция
+CREATE TABLE IF NOT EXISTS resource_bundles (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  owner_user_id BIGINT UNSIGNED NOT NULL,
+  organization_id BIGINT UNSIGNED NULL,
+  title VARCHAR(255) NOT NULL,
+  description TEXT NULL,
+  is_active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY idx_resource_bundles_owner (owner_user_id),
+  CONSTRAINT fk_resource_bundles_owner
+    FOREIGN KEY (owner_user_id) REFERENCES users(id)
+    ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT fk_resource_bundles_organization
+    FOREIGN KEY (organization_id) REFERENCES organizations(id)
+    ON DELETE SET NULL ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS resource_bundle_items (
+  bundle_id BIGINT UNSIGNED NOT NULL,
+  resource_id BIGINT UNSIGNED NOT NULL,
+  quantity INT UNSIGNED NOT NULL DEFAULT 1,
+  PRIMARY KEY (bundle_id, resource_id),
+  KEY idx_resource_bundle_items_resource (resource_id),
+  CONSTRAINT fk_resource_bundle_items_bundle
+    FOREIGN KEY (bundle_id) REFERENCES resource_bundles(id)
+    ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT fk_resource_bundle_items_resource
+    FOREIGN KEY (resource_id) REFERENCES resources(id)
+    ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- групповая бронь: несколько броней, созданных одним запросом;
+-- подтверждается, только когда подтверждены все части
+CREATE TABLE IF NOT EXISTS booking_groups (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id BIGINT UNSIGNED NOT NULL,
+  status ENUM('PENDING','APPROVED','REJECTED','CANCELED') NOT NULL DEFAULT 'PENDING',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NULL DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY idx_booking_groups_user (user_id),
+  CONSTRAINT fk_booking_groups_user
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE bookings
+  ADD COLUMN group_id BIGINT UNSIGNED NULL AFTER user_id,
+  ADD COLUMN bundle_id BIGINT UNSIGNED NULL AFTER group_id,
+  ADD KEY idx_bookings_group (group_id),
+  ADD CONSTRAINT fk_bookings_group
+    FOREIGN KEY (group_id) REFERENCES booking_groups(id)
+    ON DELETE CASCADE ON UPDATE CASCADE,
+  ADD CONSTRAINT fk_bookings_bundle
+    FOREIGN KEY (bundle_id) REFERENCES resource_bundles(id)
+    ON DELETE SET NULL ON UPDATE CASCADE;
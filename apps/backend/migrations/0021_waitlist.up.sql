@@ -0,0 +1,28 @@
+-- очередь на занятое время: когда мешающая бронь освобождает место,
+-- заявка превращается в бронь (auto_book) или получает ограниченное по времени предложение
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  resource_id BIGINT UNSIGNED NOT NULL,
+  user_id BIGINT UNSIGNED NOT NULL,
+  start_at DATETIME NOT NULL,
+  end_at DATETIME NOT NULL,
+  quantity INT UNSIGNED NOT NULL DEFAULT 1,
+  auto_book BOOLEAN NOT NULL DEFAULT TRUE,
+  status ENUM('WAITING','OFFERED','FULFILLED','LEFT','EXPIRED') NOT NULL DEFAULT 'WAITING',
+  claim_expires_at DATETIME NULL,
+  booking_id BIGINT UNSIGNED NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NULL DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY idx_waitlist_resource_status (resource_id, status, start_at),
+  KEY idx_waitlist_user (user_id),
+  CONSTRAINT fk_waitlist_resource
+    FOREIGN KEY (resource_id) REFERENCES resources(id)
+    ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT fk_waitlist_user
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT fk_waitlist_booking
+    FOREIGN KEY (booking_id) REFERENCES bookings(id)
+    ON DELETE SET NULL ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
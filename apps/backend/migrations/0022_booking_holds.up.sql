@@ -0,0 +1,23 @@
+-- короткие удержания интервала на время оформления брони;
+-- пока удержание не истекло, время считается занятым
+CREATE TABLE IF NOT EXISTS booking_holds (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  token CHAR(64) NOT NULL,
+  resource_id BIGINT UNSIGNED NOT NULL,
+  user_id BIGINT UNSIGNED NOT NULL,
+  start_at DATETIME NOT NULL,
+  end_at DATETIME NOT NULL,
+  quantity INT UNSIGNED NOT NULL DEFAULT 1,
+  expires_at DATETIME NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_booking_holds_token (token),
+  KEY idx_booking_holds_resource (resource_id, expires_at),
+  KEY idx_booking_holds_user (user_id, expires_at),
+  CONSTRAINT fk_booking_holds_resource
+    FOREIGN KEY (resource_id) REFERENCES resources(id)
+    ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT fk_booking_holds_user
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS booking_status_history;
+ALTER TABLE users
+  DROP COLUMN verified_at;
+ALTER TABLE resources
+  DROP COLUMN auto_approve_business_hours,
+  DROP COLUMN auto_approve_max_hours,
+  DROP COLUMN auto_approve_returning,
+  DROP COLUMN auto_approve_verified,
+  DROP COLUMN approval_mode;
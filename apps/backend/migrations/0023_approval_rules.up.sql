@@ -0,0 +1,30 @@
+-- режим подтверждения броней ресурса: вручную, всегда автоматически или по условиям
+ALTER TABLE resources
+  ADD COLUMN approval_mode ENUM('MANUAL','AUTO','CONDITIONAL') NOT NULL DEFAULT 'MANUAL' AFTER buffer_after_min,
+  ADD COLUMN auto_approve_verified TINYINT(1) NOT NULL DEFAULT 0 AFTER approval_mode,
+  ADD COLUMN auto_approve_returning TINYINT(1) NOT NULL DEFAULT 0 AFTER auto_approve_verified,
+  ADD COLUMN auto_approve_max_hours INT UNSIGNED NULL AFTER auto_approve_returning,
+  ADD COLUMN auto_approve_business_hours TINYINT(1) NOT NULL DEFAULT 0 AFTER auto_approve_max_hours;
+
+-- отметка администратора о проверке арендатора
+ALTER TABLE users
+  ADD COLUMN verified_at DATETIME NULL;
+
+-- история смены статусов брони; changed_by_user_id = NULL — изменение сделала система
+CREATE TABLE IF NOT EXISTS booking_status_history (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  booking_id BIGINT UNSIGNED NOT NULL,
+  from_status ENUM('PENDING','APPROVED','REJECTED','CANCELED') NULL,
+  to_status ENUM('PENDING','APPROVED','REJECTED','CANCELED') NOT NULL,
+  changed_by_user_id BIGINT UNSIGNED NULL,
+  comment VARCHAR(500) NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY idx_booking_status_history_booking (booking_id, id),
+  CONSTRAINT fk_booking_status_history_booking
+    FOREIGN KEY (booking_id) REFERENCES bookings(id)
+    ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT fk_booking_status_history_user
+    FOREIGN KEY (changed_by_user_id) REFERENCES users(id)
+    ON DELETE SET NULL ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
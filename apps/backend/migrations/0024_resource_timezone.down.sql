@@ -0,0 +1,12 @@
+UPDATE booking_holds
+SET start_at = CONVERT_TZ(start_at, '+00:00', '+03:00'),
+    end_at = CONVERT_TZ(end_at, '+00:00', '+03:00');
+UPDATE waitlist_entries
+SET start_at = CONVERT_TZ(start_at, '+00:00', '+03:00'),
+    end_at = CONVERT_TZ(end_at, '+00:00', '+03:00');
+UPDATE bookings
+SET start_at = CONVERT_TZ(start_at, '+00:00', '+03:00'),
+    end_at = CONVERT_TZ(end_at, '+00:00', '+03:00');
+
+ALTER TABLE resources
+  DROP COLUMN timezone;
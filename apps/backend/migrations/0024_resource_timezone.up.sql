@@ -0,0 +1,15 @@
+-- часовой пояс ресурса (IANA); время броней дальше хранится в UTC
+ALTER TABLE resources
+  ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'Europe/Moscow' AFTER longitude;
+
+-- до этой миграции время без смещения сохранялось как есть — это московское время
+-- (UTC+3 без перехода на летнее с 2014 года); переводим его в UTC
+UPDATE bookings
+SET start_at = CONVERT_TZ(start_at, '+03:00', '+00:00'),
+    end_at = CONVERT_TZ(end_at, '+03:00', '+00:00');
+UPDATE waitlist_entries
+SET start_at = CONVERT_TZ(start_at, '+03:00', '+00:00'),
+    end_at = CONVERT_TZ(end_at, '+03:00', '+00:00');
+UPDATE booking_holds
+SET start_at = CONVERT_TZ(start_at, '+03:00', '+00:00'),
+    end_at = CONVERT_TZ(end_at, '+03:00', '+00:00');
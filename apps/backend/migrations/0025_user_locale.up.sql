@@ -0,0 +1,3 @@
+-- язык интерфейса и сообщений API, выбранный пользователем; NULL — по Accept-Language
+ALTER TABLE users
+  ADD COLUMN locale VARCHAR(8) NULL AFTER name;
@@ -0,0 +1,28 @@
+-- двухфакторная аутентификация (TOTP): секрет пользователя и одноразовые коды восстановления.
+-- enabled_at NULL — подключение начато, но ещё не подтверждено кодом;
+-- last_step — шаг последнего принятого кода, чтобы один код нельзя было использовать дважды
+CREATE TABLE IF NOT EXISTS user_totp (
+  user_id BIGINT UNSIGNED NOT NULL,
+  secret VARCHAR(64) NOT NULL,
+  enabled_at DATETIME NULL,
+  last_step BIGINT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_id),
+  CONSTRAINT fk_user_totp_user
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- коды восстановления хранятся только как SHA-256: сами коды показываются пользователю один раз
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id BIGINT UNSIGNED NOT NULL,
+  code_hash CHAR(64) NOT NULL,
+  used_at DATETIME NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_user_recovery_codes (user_id, code_hash),
+  CONSTRAINT fk_user_recovery_codes_user
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
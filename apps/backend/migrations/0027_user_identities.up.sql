@@ -0,0 +1,17 @@
+-- учётные записи у внешних провайдеров входа (OIDC): пользователь узнаётся по паре издатель + sub,
+-- даже если email у провайдера потом поменяется. email — каким он был при последнем входе
+CREATE TABLE IF NOT EXISTS user_identities (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id BIGINT UNSIGNED NOT NULL,
+  issuer VARCHAR(255) NOT NULL,
+  subject VARCHAR(255) NOT NULL,
+  email VARCHAR(190) NOT NULL,
+  last_login_at DATETIME NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_user_identities (issuer, subject),
+  KEY idx_user_identities_user (user_id),
+  CONSTRAINT fk_user_identities_user
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
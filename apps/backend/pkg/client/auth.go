@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Register регистрирует пользователя; клиент запоминает выданный токен
+func (c *Client) Register(ctx context.Context, in RegisterRequest) (*AuthResult, error) {
+	var out AuthResult
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/v1/auth/register", body: in}, &out); err != nil {
+		return nil, err
+	}
+	c.setToken(out.AccessToken)
+	return &out, nil
+}
+
+// Login входит по email и паролю. Клиент запоминает токен и учётные данные, чтобы самому
+// входить заново, когда токен истечёт. Если у аккаунта двухфакторная аутентификация, вернётся
+// *ChallengeError: вход завершает VerifyTwoFactor, а сам заново клиент входить не сможет.
+func (c *Client) Login(ctx context.Context, email, password string) (*AuthResult, error) {
+	res, err := c.login(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.email, c.password = email, password
+	c.mu.Unlock()
+	return res, nil
+}
+
+func (c *Client) login(ctx context.Context, email, password string) (*AuthResult, error) {
+	body := map[string]string{"email": email, "password": password}
+	return c.signIn(ctx, "/api/v1/auth/login", body)
+}
+
+// signIn — запрос входа: 200 — токен доступа, 202 — токен второго шага (*ChallengeError)
+func (c *Client) signIn(ctx context.Context, path string, body any) (*AuthResult, error) {
+	var out struct {
+		AuthResult
+		LoginChallenge
+	}
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body}, &out); err != nil {
+		return nil, err
+	}
+	if out.ChallengeToken != "" {
+		return nil, &ChallengeError{Challenge: out.LoginChallenge}
+	}
+	c.setToken(out.AccessToken)
+	return &out.AuthResult, nil
+}
+
+// Me — профиль текущего пользователя
+func (c *Client) Me(ctx context.Context) (*Profile, error) {
+	var out Profile
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/auth/me", auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateProfile меняет email, имя и язык и возвращает обновлённый профиль
+func (c *Client) UpdateProfile(ctx context.Context, in UpdateProfileRequest) (*Profile, error) {
+	var out Profile
+	if err := c.do(ctx, request{method: http.MethodPatch, path: "/api/v1/auth/me", body: in, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ChangePassword меняет пароль. Сохранённые учётные данные клиента обновляются.
+func (c *Client) ChangePassword(ctx context.Context, current, next string) error {
+	body := map[string]string{"currentPassword": current, "newPassword": next}
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/v1/auth/password", body: body, auth: true}, nil); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	if c.email != "" {
+		c.password = next
+	}
+	c.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// CreateBooking бронирует ресурс или оформляет бронь из удержания
+func (c *Client) CreateBooking(ctx context.Context, in BookingRequest) (*BookingCreated, error) {
+	var out BookingCreated
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/v1/bookings", body: in, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// MyBookings — брони текущего пользователя
+func (c *Client) MyBookings(ctx context.Context) ([]Booking, error) {
+	var out []Booking
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/bookings/my", auth: true}, &out)
+	return out, err
+}
+
+// PendingBookings — брони на ресурсы пользователя, ожидающие решения
+func (c *Client) PendingBookings(ctx context.Context) ([]Booking, error) {
+	var out []Booking
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/bookings/pending", auth: true}, &out)
+	return out, err
+}
+
+// SetBookingStatus подтверждает (BookingApproved) или отклоняет (BookingRejected) бронь;
+// comment — необязательный комментарий арендатору
+func (c *Client) SetBookingStatus(ctx context.Context, id uint64, status BookingStatus, comment *string) error {
+	body := struct {
+		Status         BookingStatus `json:"status"`
+		ManagerComment *string       `json:"managerComment,omitempty"`
+	}{status, comment}
+	return c.do(ctx, request{method: http.MethodPatch, path: idPath("/api/v1/bookings/%d/status", id), body: body, auth: true}, nil)
+}
+
+// CancelBooking отменяет бронь
+func (c *Client) CancelBooking(ctx context.Context, id uint64) error {
+	return c.do(ctx, request{method: http.MethodPost, path: idPath("/api/v1/bookings/%d/cancel", id), auth: true}, nil)
+}
+
+// BookingHistory — смены статуса брони по порядку
+func (c *Client) BookingHistory(ctx context.Context, id uint64) ([]BookingStatusChange, error) {
+	var out []BookingStatusChange
+	err := c.do(ctx, request{method: http.MethodGet, path: idPath("/api/v1/bookings/%d/history", id), auth: true}, &out)
+	return out, err
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+func archivedQuery(includeArchived bool) url.Values {
+	if includeArchived {
+		return url.Values{"includeArchived": {"true"}}
+	}
+	return nil
+}
+
+// ListCategories — плоский список категорий; архивные — только с includeArchived
+func (c *Client) ListCategories(ctx context.Context, includeArchived bool) ([]Category, error) {
+	var out []Category
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/categories", query: archivedQuery(includeArchived)}, &out)
+	return out, err
+}
+
+// CategoryTree — дерево категорий
+func (c *Client) CategoryTree(ctx context.Context, includeArchived bool) ([]CategoryNode, error) {
+	var out []CategoryNode
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/categories/tree", query: archivedQuery(includeArchived)}, &out)
+	return out, err
+}
+
+// GetCategory — категория с хлебными крошками, подкатегориями и атрибутами
+func (c *Client) GetCategory(ctx context.Context, id uint64) (*CategoryDetails, error) {
+	var out CategoryDetails
+	if err := c.do(ctx, request{method: http.MethodGet, path: idPath("/api/v1/categories/%d", id)}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CategoryAttributes — собственные атрибуты категории
+func (c *Client) CategoryAttributes(ctx context.Context, id uint64) ([]CategoryAttribute, error) {
+	var out []CategoryAttribute
+	err := c.do(ctx, request{method: http.MethodGet, path: idPath("/api/v1/categories/%d/attributes", id)}, &out)
+	return out, err
+}
+
+// CreateCategory создаёт категорию (parentID nil — в корне) и возвращает её ID; нужно право category.manage
+func (c *Client) CreateCategory(ctx context.Context, name string, parentID *uint64) (uint64, error) {
+	body := struct {
+		Name     string  `json:"name"`
+		ParentID *uint64 `json:"parentId"`
+	}{name, parentID}
+	var out struct {
+		ID uint64 `json:"id"`
+	}
+	err := c.do(ctx, request{method: http.MethodPost, path: "/api/v1/categories", body: body, auth: true}, &out)
+	return out.ID, err
+}
+
+// RenameCategory меняет название категории, не трогая родителя
+func (c *Client) RenameCategory(ctx context.Context, id uint64, name string) error {
+	body := map[string]string{"name": name}
+	return c.do(ctx, request{method: http.MethodPatch, path: idPath("/api/v1/categories/%d", id), body: body, auth: true}, nil)
+}
+
+// DeleteCategory удаляет пустую категорию. Непустая — ошибка 409 без кода: ресурсы переносят
+// в другую категорию или архивируют категорию.
+func (c *Client) DeleteCategory(ctx context.Context, id uint64) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: idPath("/api/v1/categories/%d", id), auth: true}, nil)
+}
@@ -0,0 +1,249 @@
+// Package client — типизированный Go-клиент API BookingHub для внутренних сервисов.
+//
+//	c := client.New("https://bookinghub.example.com", client.WithCredentials("svc@example.com", "secret"))
+//	items, err := c.ListResources(ctx, client.ResourceQuery{CategoryID: 1})
+//	var apiErr *client.Error
+//	if errors.Is(err, client.ErrBookingConflict) { ... }
+//
+// Клиент работает с API v1 (/api/v1). Ответы разбираются в типы v1 (Resource, Booking, Category —
+// псевдонимы типов из internal/apiv1), ошибки API — в *Error с кодом из того же списка, что у сервера.
+// Все методы принимают context и прерываются при его отмене.
+//
+// Обновление токена. Refresh-токенов у API нет, access-токен живёт JWT_ACCESS_TTL_MIN минут.
+// Клиент с учётными данными (WithCredentials или после Login) сам входит заново, когда токен
+// истекает или сервер отвечает 401, и повторяет запрос один раз.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// refreshBefore — за сколько до истечения токен считается устаревшим: запрос не должен
+// прийти на сервер с токеном, который истечёт по дороге
+const refreshBefore = 30 * time.Second
+
+// Client — клиент API. Безопасен для одновременного использования из нескольких горутин.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	lang    string
+
+	mu       sync.Mutex
+	token    string
+	expires  time.Time
+	email    string
+	password string
+}
+
+// Option настраивает клиент в New
+type Option func(*Client)
+
+// WithHTTPClient — свой http.Client (таймауты, транспорт); по умолчанию http.DefaultClient
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *Client) { c.http = h }
+}
+
+// WithToken — готовый access-токен. Без учётных данных клиент не сможет его обновить.
+func WithToken(token string) Option {
+	return func(c *Client) { c.setToken(token) }
+}
+
+// WithCredentials — email и пароль для входа: клиент войдёт перед первым запросом с авторизацией
+// и будет входить заново, когда токен истекает
+func WithCredentials(email, password string) Option {
+	return func(c *Client) { c.email, c.password = email, password }
+}
+
+// WithLanguage — язык сообщений об ошибках (заголовок Accept-Language): ru или en
+func WithLanguage(lang string) Option {
+	return func(c *Client) { c.lang = lang }
+}
+
+// New создаёт клиент для сервера baseURL, например https://bookinghub.example.com
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{baseURL: strings.TrimSuffix(baseURL, "/"), http: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Token — текущий access-токен; пусто, если клиент ещё не входил
+func (c *Client) Token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+// request — описание вызова API. auth — нужен токен: клиент получит или обновит его перед запросом.
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   any
+	auth   bool
+}
+
+// do выполняет запрос и разбирает JSON-ответ в out (nil — тело не нужно). Ответ не 2xx
+// возвращается как *Error. Запрос с авторизацией, отклонённый с 401, повторяется один раз
+// после повторного входа, если у клиента есть учётные данные.
+func (c *Client) do(ctx context.Context, req request, out any) error {
+	var body []byte
+	if req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return fmt.Errorf("bookinghub: кодирование запроса: %w", err)
+		}
+	}
+
+	token := ""
+	if req.auth {
+		var err error
+		if token, err = c.validToken(ctx); err != nil {
+			return err
+		}
+	}
+	err := c.send(ctx, req, body, token, out)
+
+	var apiErr *Error
+	if req.auth && errors.As(err, &apiErr) && apiErr.Status == http.StatusUnauthorized && c.hasCredentials() {
+		if token, err = c.relogin(ctx, token); err != nil {
+			return err
+		}
+		err = c.send(ctx, req, body, token, out)
+	}
+	return err
+}
+
+func (c *Client) send(ctx context.Context, req request, body []byte, token string, out any) error {
+	u := c.baseURL + req.path
+	if len(req.query) > 0 {
+		u += "?" + req.query.Encode()
+	}
+	var rdr io.Reader
+	if body != nil {
+		rdr = bytes.NewReader(body)
+	}
+	hr, err := http.NewRequestWithContext(ctx, req.method, u, rdr)
+	if err != nil {
+		return fmt.Errorf("bookinghub: %w", err)
+	}
+	hr.Header.Set("Accept", "application/json")
+	if body != nil {
+		hr.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		hr.Header.Set("Authorization", "Bearer "+token)
+	}
+	if c.lang != "" {
+		hr.Header.Set("Accept-Language", c.lang)
+	}
+
+	resp, err := c.http.Do(hr)
+	if err != nil {
+		return fmt.Errorf("bookinghub: %s %s: %w", req.method, req.path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return decodeError(resp)
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("bookinghub: %s %s: разбор ответа: %w", req.method, req.path, err)
+	}
+	return nil
+}
+
+// validToken — действующий токен; при учётных данных входит заново, если токена нет или он истекает
+func (c *Client) validToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	token, expires := c.token, c.expires
+	c.mu.Unlock()
+
+	if token != "" && (expires.IsZero() || time.Until(expires) > refreshBefore) {
+		return token, nil
+	}
+	if !c.hasCredentials() {
+		if token == "" {
+			return "", ErrNoToken
+		}
+		return token, nil // обновить нечем — пусть решит сервер
+	}
+	return c.relogin(ctx, token)
+}
+
+// relogin входит по сохранённым учётным данным. stale — токен, с которым не получилось:
+// если другая горутина уже успела его заменить, повторный вход не нужен.
+func (c *Client) relogin(ctx context.Context, stale string) (string, error) {
+	c.mu.Lock()
+	if c.token != stale && c.token != "" {
+		token := c.token
+		c.mu.Unlock()
+		return token, nil
+	}
+	email, password := c.email, c.password
+	c.mu.Unlock()
+
+	res, err := c.login(ctx, email, password)
+	if err != nil {
+		return "", err
+	}
+	return res.AccessToken, nil
+}
+
+func (c *Client) hasCredentials() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.email != ""
+}
+
+// setToken запоминает токен и срок его действия из поля exp JWT. Подпись не проверяется:
+// срок нужен только чтобы вовремя обновить токен, проверяет его сервер.
+func (c *Client) setToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+	c.expires = tokenExpiry(token)
+}
+
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if json.Unmarshal(payload, &claims) != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
+
+// idPath — путь с числовым идентификатором: "/api/v1/resources/%d"
+func idPath(format string, ids ...uint64) string {
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,243 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeJWT — токен с нужным exp; подпись клиент не проверяет
+func fakeJWT(exp time.Time) string {
+	payload, _ := json.Marshal(map[string]int64{"exp": exp.Unix()})
+	return "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// authServer принимает только последний выданный токен и считает входы
+type authServer struct {
+	logins atomic.Int32
+	valid  atomic.Value
+	ttl    time.Duration
+}
+
+func (s *authServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/api/v1/auth/login":
+		n := s.logins.Add(1)
+		tok := fakeJWT(time.Now().Add(s.ttl)) + strconv.Itoa(int(n))
+		s.valid.Store(tok)
+		writeJSON(w, http.StatusOK, map[string]any{"accessToken": tok, "user": map[string]any{"id": 1, "email": "a@example.com"}})
+	case "/api/v1/auth/me":
+		if r.Header.Get("Authorization") != "Bearer "+s.valid.Load().(string) {
+			writeJSON(w, http.StatusUnauthorized, map[string]any{"error": map[string]any{"code": CodeUnauthorized, "message": "нужен вход"}})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"id": 1, "email": "a@example.com", "role": RoleIndividual})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestClient_LogsInWithCredentials(t *testing.T) {
+	srv := &authServer{ttl: time.Hour}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	c := New(ts.URL, WithCredentials("a@example.com", "secret"))
+	for i := 0; i < 2; i++ {
+		if _, err := c.Me(context.Background()); err != nil {
+			t.Fatalf("Me: %v", err)
+		}
+	}
+	if got := srv.logins.Load(); got != 1 {
+		t.Fatalf("expected one login, got %d", got)
+	}
+}
+
+func TestClient_RefreshesExpiringToken(t *testing.T) {
+	// сервер выдаёт токены, которые истекают раньше refreshBefore: каждый запрос — новый вход
+	srv := &authServer{ttl: 10 * time.Second}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	c := New(ts.URL)
+	if _, err := c.Login(context.Background(), "a@example.com", "secret"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if _, err := c.Me(context.Background()); err != nil {
+		t.Fatalf("Me: %v", err)
+	}
+	if got := srv.logins.Load(); got != 2 {
+		t.Fatalf("expected re-login before request, got %d logins", got)
+	}
+}
+
+func TestClient_RetriesOnceAfter401(t *testing.T) {
+	srv := &authServer{ttl: time.Hour}
+	srv.valid.Store("")
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	// токен ещё не истёк, но сервер его уже не принимает (например, после выхода со всех устройств)
+	c := New(ts.URL, WithToken(fakeJWT(time.Now().Add(time.Hour))), WithCredentials("a@example.com", "secret"))
+	if _, err := c.Me(context.Background()); err != nil {
+		t.Fatalf("Me: %v", err)
+	}
+	if got := srv.logins.Load(); got != 1 {
+		t.Fatalf("expected one re-login, got %d", got)
+	}
+
+	// без учётных данных 401 возвращается как есть
+	c = New(ts.URL, WithToken("stale"))
+	_, err := c.Me(context.Background())
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestClient_NoToken(t *testing.T) {
+	c := New("http://127.0.0.1:1")
+	if _, err := c.MyBookings(context.Background()); !errors.Is(err, ErrNoToken) {
+		t.Fatalf("expected ErrNoToken, got %v", err)
+	}
+}
+
+func TestClient_DecodesErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/bookings":
+			w.Header().Set("X-Request-ID", "req-1")
+			writeJSON(w, http.StatusConflict, map[string]any{"error": map[string]any{
+				"code": CodeBookingConflict, "message": "занято", "requestId": "req-1",
+			}})
+		case "/api/v1/categories/5":
+			writeJSON(w, http.StatusConflict, map[string]any{"error": "в категории есть ресурсы"})
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprint(w, "bad gateway")
+		}
+	}))
+	defer ts.Close()
+	c := New(ts.URL, WithToken("t"))
+	ctx := context.Background()
+
+	_, err := c.CreateBooking(ctx, BookingRequest{ResourceID: 1, StartAt: "2026-05-01T10:00", EndAt: "2026-05-01T11:00"})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusConflict || apiErr.RequestID != "req-1" {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+	if !errors.Is(err, ErrBookingConflict) || errors.Is(err, ErrConflict) {
+		t.Fatalf("errors.Is must match by code: %v", err)
+	}
+
+	err = c.DeleteCategory(ctx, 5)
+	if !errors.As(err, &apiErr) || apiErr.Code != "" || apiErr.Message != "в категории есть ресурсы" {
+		t.Fatalf("legacy error body: %#v", err)
+	}
+
+	_, err = c.GetResource(ctx, 1)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadGateway || apiErr.Message != "bad gateway" {
+		t.Fatalf("non-API error: %#v", err)
+	}
+}
+
+func TestClient_ContextCancel(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := New(ts.URL).ListResources(ctx, ResourceQuery{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestClient_ResourceQuery(t *testing.T) {
+	var got string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.RawQuery
+		writeJSON(w, http.StatusOK, []any{})
+	}))
+	defer ts.Close()
+
+	_, err := New(ts.URL).ListResources(context.Background(), ResourceQuery{
+		CategoryID: 3,
+		Attributes: map[string][]string{"lens_mount": {"EF", "RF"}},
+		Near:       "55.75,37.62",
+		RadiusKm:   2.5,
+		Sort:       "distance",
+	})
+	if err != nil {
+		t.Fatalf("ListResources: %v", err)
+	}
+	want := "attr.lens_mount=EF&attr.lens_mount=RF&categoryId=3&near=55.75%2C37.62&radiusKm=2.5&sort=distance"
+	if got != want {
+		t.Fatalf("query = %s, want %s", got, want)
+	}
+}
+
+// Коды клиента совпадают с кодами сервера: новый код в handler/errors.go без копии здесь — ошибка
+func TestCodes_MatchServer(t *testing.T) {
+	server := codeConstants(t, "../../internal/handler/errors.go")
+	client := codeConstants(t, "errors.go")
+	if len(server) == 0 {
+		t.Fatal("no codes found in handler/errors.go")
+	}
+	for name, value := range server {
+		if client[name] != value {
+			t.Errorf("%s = %q на сервере, %q в клиенте", name, value, client[name])
+		}
+	}
+	for name := range client {
+		if _, ok := server[name]; !ok {
+			t.Errorf("%s есть в клиенте, но не на сервере", name)
+		}
+	}
+}
+
+func codeConstants(t *testing.T, path string) map[string]string {
+	t.Helper()
+	f, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		t.Fatalf("parse %s: %v", path, err)
+	}
+	out := map[string]string{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		for i, name := range spec.Names {
+			if !strings.HasPrefix(name.Name, "Code") || i >= len(spec.Values) {
+				continue
+			}
+			if lit, ok := spec.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				out[name.Name], _ = strconv.Unquote(lit.Value)
+			}
+		}
+		return false
+	})
+	return out
+}
@@ -0,0 +1,183 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Коды ошибок API — те же, что отдаёт сервер (internal/handler/errors.go); совпадение проверяет тест
+const (
+	CodeValidationFailed     = "VALIDATION_FAILED"
+	CodeInvalidJSON          = "INVALID_JSON"
+	CodeUnauthorized         = "UNAUTHORIZED"
+	CodeInvalidCredentials   = "INVALID_CREDENTIALS"
+	CodeForbidden            = "FORBIDDEN"
+	CodeAccountSuspended     = "ACCOUNT_SUSPENDED"
+	CodeNotFound             = "NOT_FOUND"
+	CodeResourceNotFound     = "RESOURCE_NOT_FOUND"
+	CodeBookingNotFound      = "BOOKING_NOT_FOUND"
+	CodeMethodNotAllowed     = "METHOD_NOT_ALLOWED"
+	CodeConflict             = "CONFLICT"
+	CodeBookingConflict      = "BOOKING_CONFLICT"
+	CodeEmailTaken           = "EMAIL_TAKEN"
+	CodeHoldNotFound         = "HOLD_NOT_FOUND"
+	CodeHoldLimit            = "HOLD_LIMIT_REACHED"
+	CodeSlotAvailable        = "SLOT_AVAILABLE"
+	CodeAlreadyInQueue       = "ALREADY_IN_QUEUE"
+	CodeClaimNotOffered      = "CLAIM_NOT_OFFERED"
+	CodeInviteExpired        = "INVITE_EXPIRED"
+	CodeCancelNotAllowed     = "CANCEL_NOT_ALLOWED"
+	CodeInvalidBookingStatus = "INVALID_BOOKING_STATUS"
+	CodePayloadTooLarge      = "PAYLOAD_TOO_LARGE"
+	CodeUnsupportedMedia     = "UNSUPPORTED_MEDIA_TYPE"
+	CodeImageLimit           = "IMAGE_LIMIT_REACHED"
+	CodeInternal             = "INTERNAL_ERROR"
+	CodeTimeDoesNotExist     = "LOCAL_TIME_DOES_NOT_EXIST"
+	CodeInvalidTimezone      = "INVALID_TIMEZONE"
+	CodeInvalidTimeInterval  = "INVALID_TIME_INTERVAL"
+	CodeBookingInPast        = "BOOKING_IN_PAST"
+	CodeTooManyRequests      = "TOO_MANY_REQUESTS"
+	CodeLoginLocked          = "LOGIN_LOCKED"
+	CodeInvalidTwoFactorCode = "INVALID_TWO_FACTOR_CODE"
+	CodeTwoFactorNotEnabled  = "TWO_FACTOR_NOT_ENABLED"
+	CodeTwoFactorEnabled     = "TWO_FACTOR_ENABLED"
+	CodeTwoFactorRequired    = "TWO_FACTOR_REQUIRED"
+	CodeSSOFailed            = "SSO_FAILED"
+	CodeSSOUnavailable       = "SSO_UNAVAILABLE"
+	CodeSSOEmailNotVerified  = "SSO_EMAIL_NOT_VERIFIED"
+)
+
+// Образцы ошибок для errors.Is: совпадение по коду, статус и текст не важны
+var (
+	ErrValidationFailed     = &Error{Code: CodeValidationFailed}
+	ErrInvalidJSON          = &Error{Code: CodeInvalidJSON}
+	ErrUnauthorized         = &Error{Code: CodeUnauthorized}
+	ErrInvalidCredentials   = &Error{Code: CodeInvalidCredentials}
+	ErrForbidden            = &Error{Code: CodeForbidden}
+	ErrAccountSuspended     = &Error{Code: CodeAccountSuspended}
+	ErrNotFound             = &Error{Code: CodeNotFound}
+	ErrResourceNotFound     = &Error{Code: CodeResourceNotFound}
+	ErrBookingNotFound      = &Error{Code: CodeBookingNotFound}
+	ErrMethodNotAllowed     = &Error{Code: CodeMethodNotAllowed}
+	ErrConflict             = &Error{Code: CodeConflict}
+	ErrBookingConflict      = &Error{Code: CodeBookingConflict}
+	ErrEmailTaken           = &Error{Code: CodeEmailTaken}
+	ErrHoldNotFound         = &Error{Code: CodeHoldNotFound}
+	ErrHoldLimit            = &Error{Code: CodeHoldLimit}
+	ErrSlotAvailable        = &Error{Code: CodeSlotAvailable}
+	ErrAlreadyInQueue       = &Error{Code: CodeAlreadyInQueue}
+	ErrClaimNotOffered      = &Error{Code: CodeClaimNotOffered}
+	ErrInviteExpired        = &Error{Code: CodeInviteExpired}
+	ErrCancelNotAllowed     = &Error{Code: CodeCancelNotAllowed}
+	ErrInvalidBookingStatus = &Error{Code: CodeInvalidBookingStatus}
+	ErrPayloadTooLarge      = &Error{Code: CodePayloadTooLarge}
+	ErrUnsupportedMedia     = &Error{Code: CodeUnsupportedMedia}
+	ErrImageLimit           = &Error{Code: CodeImageLimit}
+	ErrInternal             = &Error{Code: CodeInternal}
+	ErrTimeDoesNotExist     = &Error{Code: CodeTimeDoesNotExist}
+	ErrInvalidTimezone      = &Error{Code: CodeInvalidTimezone}
+	ErrInvalidTimeInterval  = &Error{Code: CodeInvalidTimeInterval}
+	ErrBookingInPast        = &Error{Code: CodeBookingInPast}
+	ErrTooManyRequests      = &Error{Code: CodeTooManyRequests}
+	ErrLoginLocked          = &Error{Code: CodeLoginLocked}
+	ErrInvalidTwoFactorCode = &Error{Code: CodeInvalidTwoFactorCode}
+	ErrTwoFactorNotEnabled  = &Error{Code: CodeTwoFactorNotEnabled}
+	ErrTwoFactorEnabled     = &Error{Code: CodeTwoFactorEnabled}
+	ErrTwoFactorRequired    = &Error{Code: CodeTwoFactorRequired}
+	ErrSSOFailed            = &Error{Code: CodeSSOFailed}
+	ErrSSOUnavailable       = &Error{Code: CodeSSOUnavailable}
+	ErrSSOEmailNotVerified  = &Error{Code: CodeSSOEmailNotVerified}
+)
+
+// ErrNoToken — запрос требует авторизации, а у клиента нет ни токена, ни учётных данных
+var ErrNoToken = errors.New("bookinghub: нет токена: вызовите Login или задайте WithCredentials")
+
+// ChallengeError — пароль верный, но нужен второй фактор: Challenge.ChallengeToken вместе с кодом
+// передаётся в VerifyTwoFactor. Если Challenge.EnrollmentRequired, 2FA для роли обязательна:
+// секрет из Challenge.Enrollment добавляется в аутентификатор, и первый код подтверждает подключение.
+type ChallengeError struct {
+	Challenge LoginChallenge
+}
+
+func (e *ChallengeError) Error() string {
+	if e.Challenge.EnrollmentRequired {
+		return "bookinghub: вход требует подключить двухфакторную аутентификацию"
+	}
+	return "bookinghub: вход требует код двухфакторной аутентификации"
+}
+
+// FieldError — ошибка в конкретном поле запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error — ответ API с ошибкой. Code пуст, если ответ пришёл не от API (прокси, балансировщик).
+type Error struct {
+	Status    int          `json:"-"`
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"requestId,omitempty"`
+	// RetryAfter — через сколько можно повторить запрос (заголовок Retry-After у ответов 429)
+	RetryAfter time.Duration `json:"-"`
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "bookinghub: %d", e.Status)
+	if e.Code != "" {
+		b.WriteString(" " + e.Code)
+	}
+	if e.Message != "" {
+		b.WriteString(": " + e.Message)
+	}
+	for _, d := range e.Details {
+		b.WriteString("; " + d.Field + " — " + d.Message)
+	}
+	if e.RequestID != "" {
+		b.WriteString(" (request " + e.RequestID + ")")
+	}
+	return b.String()
+}
+
+// Is сравнивает по коду: errors.Is(err, client.ErrNotFound)
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code != "" && t.Code == e.Code
+}
+
+// decodeError разбирает тело ошибки {"error": {...}}. Старый формат {"error": "текст"}
+// (удаление непустой категории) и ответы не от API превращаются в Error без кода.
+func decodeError(resp *http.Response) error {
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	e := &Error{Status: resp.StatusCode, RequestID: resp.Header.Get("X-Request-ID")}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		e.RetryAfter = time.Duration(secs) * time.Second
+	}
+
+	var body struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(raw, &body) == nil && len(body.Error) > 0 {
+		if json.Unmarshal(body.Error, e) == nil {
+			return e
+		}
+		var text string
+		if json.Unmarshal(body.Error, &text) == nil {
+			e.Message = text
+			return e
+		}
+	}
+	e.Message = strings.TrimSpace(string(raw))
+	if e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+	return e
+}
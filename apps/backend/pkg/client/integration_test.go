@@ -0,0 +1,256 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	"bookinghub-backend/internal/oidc/oidctest"
+	"bookinghub-backend/internal/openapi"
+	"bookinghub-backend/internal/server"
+	"bookinghub-backend/internal/service"
+	"bookinghub-backend/internal/totp"
+	"bookinghub-backend/pkg/client"
+)
+
+const testSecret = "test-secret"
+
+// newRouterServer поднимает настоящий роутер на sqlmock. Каждый обмен проверяется по
+// спецификации OpenAPI: клиент и сервер не должны расходиться в форме запросов и ответов.
+func newRouterServer(t *testing.T) (*httptest.Server, sqlmock.Sqlmock) {
+	t.Helper()
+	t.Setenv("STORAGE_DRIVER", "")
+	t.Setenv("UPLOADS_DIR", t.TempDir())
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	var mu sync.Mutex
+	var problems []string
+	validator := openapi.Validator(server.APIDoc(true), func(r *http.Request, err error) {
+		mu.Lock()
+		problems = append(problems, err.Error())
+		mu.Unlock()
+	})
+
+	srv := server.New(&server.App{DB: sqlx.NewDb(db, "sqlmock")}, service.NewAuthService(testSecret, 15), validator)
+	ts := httptest.NewServer(srv.Router)
+	t.Cleanup(func() {
+		ts.Close()
+		if len(problems) > 0 {
+			t.Errorf("responses do not match the spec:\n%s", strings.Join(problems, "\n"))
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("sql expectations: %v", err)
+		}
+	})
+	return ts, mock
+}
+
+func expectLogin(t *testing.T, mock sqlmock.Sqlmock, email, password string) {
+	t.Helper()
+	hash, err := service.NewAuthService(testSecret, 15).HashPassword(password)
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	mock.ExpectQuery(`FROM users WHERE email = \?`).WithArgs(email).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at", "locale"}).
+			AddRow(7, email, "Сервис", "INDIVIDUAL", hash, time.Now(), nil))
+	expectAuthState(mock)
+	mock.ExpectQuery(`FROM user_totp`).WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+}
+
+func expectAuthState(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`SELECT role, suspended_at, suspend_reason, sessions_revoked_at, locale`).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"role", "suspended_at", "suspend_reason", "sessions_revoked_at", "locale"}).
+			AddRow("INDIVIDUAL", nil, nil, nil, nil))
+}
+
+func TestClient_AgainstRouter(t *testing.T) {
+	ts, mock := newRouterServer(t)
+	ctx := context.Background()
+	c := client.New(ts.URL, client.WithLanguage("en"))
+
+	expectLogin(t, mock, "svc@example.com", "secret1")
+	res, err := c.Login(ctx, "svc@example.com", "secret1")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if res.User.ID != 7 || res.User.Role != client.RoleIndividual || c.Token() == "" {
+		t.Fatalf("unexpected login result: %+v", res)
+	}
+
+	expectAuthState(mock)
+	mock.ExpectQuery(`FROM users WHERE id = \?`).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at", "locale"}).
+			AddRow(7, "svc@example.com", "Сервис", "INDIVIDUAL", "x", time.Now(), "en"))
+	me, err := c.Me(ctx)
+	if err != nil {
+		t.Fatalf("Me: %v", err)
+	}
+	if me.Email != "svc@example.com" || me.Locale == nil || *me.Locale != "en" {
+		t.Fatalf("unexpected profile: %+v", me)
+	}
+
+	now := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`FROM resource_categories`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id", "name", "archived_at", "created_at"}).
+			AddRow(1, nil, "Залы", nil, now).
+			AddRow(2, 1, "Переговорные", nil, now))
+	tree, err := c.CategoryTree(ctx, false)
+	if err != nil {
+		t.Fatalf("CategoryTree: %v", err)
+	}
+	if len(tree) != 1 || len(tree[0].Children) != 1 || tree[0].Children[0].Name != "Переговорные" {
+		t.Fatalf("unexpected tree: %+v", tree)
+	}
+
+	mock.ExpectQuery(`FROM resources`).WithArgs(42).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = c.GetResource(ctx, 42)
+	var apiErr *client.Error
+	if !errors.Is(err, client.ErrResourceNotFound) || !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+		t.Fatalf("expected RESOURCE_NOT_FOUND, got %v", err)
+	}
+	if apiErr.RequestID == "" || apiErr.Message != "Resource not found" {
+		t.Fatalf("expected English message with request id: %+v", apiErr)
+	}
+
+	expectAuthState(mock)
+	_, err = c.CreateBooking(ctx, client.BookingRequest{ResourceID: 1})
+	if !errors.Is(err, client.ErrValidationFailed) || !errors.As(err, &apiErr) || len(apiErr.Details) != 2 {
+		t.Fatalf("expected field errors for startAt and endAt, got %v", err)
+	}
+}
+
+func TestClient_RelogsInAfterRejectedToken(t *testing.T) {
+	ts, mock := newRouterServer(t)
+	ctx := context.Background()
+
+	// токен подписан чужим ключом: по сроку он действителен, но сервер его отклонит
+	foreign, err := service.NewAuthService("other-secret", 15).CreateAccessToken(7, client.RoleIndividual)
+	if err != nil {
+		t.Fatalf("CreateAccessToken: %v", err)
+	}
+	c := client.New(ts.URL, client.WithToken(foreign), client.WithCredentials("svc@example.com", "secret1"))
+
+	expectLogin(t, mock, "svc@example.com", "secret1")
+	expectAuthState(mock)
+	mock.ExpectQuery(`FROM bookings`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	items, err := c.MyBookings(ctx)
+	if err != nil {
+		t.Fatalf("MyBookings: %v", err)
+	}
+	if len(items) != 0 || c.Token() == foreign {
+		t.Fatalf("expected fresh token and empty list, got %d items", len(items))
+	}
+}
+
+func TestClient_TwoFactorLogin(t *testing.T) {
+	ts, mock := newRouterServer(t)
+	ctx := context.Background()
+	c := client.New(ts.URL)
+
+	secret, _ := totp.GenerateSecret()
+	hash, _ := service.NewAuthService(testSecret, 15).HashPassword("secret1")
+	enabled := time.Now().Add(-time.Hour)
+	mock.ExpectQuery(`FROM users WHERE email = \?`).WithArgs("svc@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at", "locale"}).
+			AddRow(7, "svc@example.com", "Сервис", "INDIVIDUAL", hash, time.Now(), nil))
+	expectAuthState(mock)
+	mock.ExpectQuery(`FROM user_totp`).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "secret", "enabled_at", "last_step"}).AddRow(7, secret, enabled, nil))
+
+	_, err := c.Login(ctx, "svc@example.com", "secret1")
+	var challenge *client.ChallengeError
+	if !errors.As(err, &challenge) || challenge.Challenge.ChallengeToken == "" || challenge.Challenge.EnrollmentRequired {
+		t.Fatalf("expected 2FA challenge, got %v", err)
+	}
+	if c.Token() != "" {
+		t.Fatal("no access token before the second step")
+	}
+
+	code, _ := totp.Code(secret, time.Now())
+	mock.ExpectQuery(`FROM users WHERE id = \?`).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at", "locale"}).
+			AddRow(7, "svc@example.com", "Сервис", "INDIVIDUAL", hash, time.Now(), nil))
+	mock.ExpectQuery(`FROM user_totp`).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "secret", "enabled_at", "last_step"}).AddRow(7, secret, enabled, nil))
+	mock.ExpectExec(`UPDATE user_totp\s+SET last_step`).WillReturnResult(sqlmock.NewResult(0, 1))
+	res, err := c.VerifyTwoFactor(ctx, client.TwoFactorVerifyRequest{ChallengeToken: challenge.Challenge.ChallengeToken, Code: code})
+	if err != nil {
+		t.Fatalf("VerifyTwoFactor: %v", err)
+	}
+	if res.User.ID != 7 || c.Token() != res.AccessToken || res.RecoveryCodes != nil {
+		t.Fatalf("unexpected verify result: %+v", res)
+	}
+
+	// тот же токен второго шага с использованным кодом не пройдёт
+	mock.ExpectQuery(`FROM users WHERE id = \?`).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at", "locale"}).
+			AddRow(7, "svc@example.com", "Сервис", "INDIVIDUAL", hash, time.Now(), nil))
+	mock.ExpectQuery(`FROM user_totp`).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "secret", "enabled_at", "last_step"}).AddRow(7, secret, enabled, totp.Step(time.Now())))
+	mock.ExpectExec(`UPDATE user_totp\s+SET last_step`).WillReturnResult(sqlmock.NewResult(0, 0))
+	_, err = c.VerifyTwoFactor(ctx, client.TwoFactorVerifyRequest{ChallengeToken: challenge.Challenge.ChallengeToken, Code: code})
+	if !errors.Is(err, client.ErrInvalidTwoFactorCode) {
+		t.Fatalf("expected INVALID_TWO_FACTOR_CODE, got %v", err)
+	}
+}
+
+func TestClient_SSOLogin(t *testing.T) {
+	idp := oidctest.NewServer("bookinghub", "s3cret")
+	defer idp.Close()
+	t.Setenv("OIDC_ISSUER", idp.Issuer())
+	t.Setenv("OIDC_CLIENT_ID", "bookinghub")
+	t.Setenv("OIDC_CLIENT_SECRET", "s3cret")
+	t.Setenv("OIDC_REDIRECT_URL", "http://app.local/sso/callback")
+	t.Setenv("OIDC_ROLE_MAP", "partners=COMPANY")
+	ts, mock := newRouterServer(t)
+	ctx := context.Background()
+	c := client.New(ts.URL)
+
+	start, err := c.SSOAuthorize(ctx)
+	if err != nil {
+		t.Fatalf("SSOAuthorize: %v", err)
+	}
+	idp.SetUser(&oidctest.User{Subject: "corp-7", Email: "svc@example.com", EmailVerified: true, Groups: []string{"partners"}})
+	code, state, err := idp.Login(start.AuthorizationURL)
+	if err != nil || state != start.State {
+		t.Fatalf("provider login: %v", err)
+	}
+
+	// учётная запись уже привязана к пользователю 7; группа partners делает его компанией
+	mock.ExpectQuery(`FROM user_identities`).WithArgs(idp.Issuer(), "corp-7").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(7))
+	mock.ExpectQuery(`FROM users\s+WHERE id = \?`).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "role", "password_hash", "created_at", "locale"}).
+			AddRow(7, "svc@example.com", "Сервис", "INDIVIDUAL", "!sso", time.Now(), nil))
+	mock.ExpectExec(`UPDATE users\s+SET role = \?`).WithArgs("COMPANY", 7).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO user_identities`).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectAuthState(mock)
+	mock.ExpectQuery(`FROM user_totp`).WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+	res, err := c.SSOCallback(ctx, code, state)
+	if err != nil {
+		t.Fatalf("SSOCallback: %v", err)
+	}
+	if res.User.ID != 7 || res.User.Role != "COMPANY" || c.Token() != res.AccessToken {
+		t.Fatalf("unexpected SSO result: %+v", res)
+	}
+
+	// код одноразовый: повторный обмен — отказ провайдера
+	if _, err := c.SSOCallback(ctx, code, state); !errors.Is(err, client.ErrSSOFailed) {
+		t.Fatalf("expected SSO_FAILED, got %v", err)
+	}
+}
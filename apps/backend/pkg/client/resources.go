@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ListResources ищет активные ресурсы по фильтрам
+func (c *Client) ListResources(ctx context.Context, q ResourceQuery) ([]Resource, error) {
+	v := url.Values{}
+	if q.CategoryID != 0 {
+		v.Set("categoryId", strconv.FormatUint(q.CategoryID, 10))
+	}
+	for code, values := range q.Attributes {
+		for _, val := range values {
+			v.Add("attr."+code, val)
+		}
+	}
+	if q.Near != "" {
+		v.Set("near", q.Near)
+	}
+	if q.RadiusKm != 0 {
+		v.Set("radiusKm", strconv.FormatFloat(q.RadiusKm, 'f', -1, 64))
+	}
+	if q.BBox != "" {
+		v.Set("bbox", q.BBox)
+	}
+	if q.Sort != "" {
+		v.Set("sort", q.Sort)
+	}
+
+	var out []Resource
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/resources", query: v}, &out)
+	return out, err
+}
+
+// GetResource — ресурс по ID
+func (c *Client) GetResource(ctx context.Context, id uint64) (*Resource, error) {
+	var out Resource
+	if err := c.do(ctx, request{method: http.MethodGet, path: idPath("/api/v1/resources/%d", id)}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// MyResources — ресурсы текущего пользователя, включая неактивные
+func (c *Client) MyResources(ctx context.Context) ([]Resource, error) {
+	var out []Resource
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/resources/my", auth: true}, &out)
+	return out, err
+}
+
+// CreateResource создаёт ресурс и возвращает его ID
+func (c *Client) CreateResource(ctx context.Context, in ResourceInput) (uint64, error) {
+	var out struct {
+		ID uint64 `json:"id"`
+	}
+	err := c.do(ctx, request{method: http.MethodPost, path: "/api/v1/resources", body: in, auth: true}, &out)
+	return out.ID, err
+}
+
+// UpdateResource изменяет ресурс; nil-поля ResourceInput не меняются
+func (c *Client) UpdateResource(ctx context.Context, id uint64, in ResourceInput) error {
+	return c.do(ctx, request{method: http.MethodPatch, path: idPath("/api/v1/resources/%d", id), body: in, auth: true}, nil)
+}
+
+// Availability — сколько единиц ресурса свободно на весь интервал [startAt, endAt).
+// Время без смещения трактуется в поясе ресурса.
+func (c *Client) Availability(ctx context.Context, resourceID uint64, startAt, endAt string) (*Availability, error) {
+	v := url.Values{"startAt": {startAt}, "endAt": {endAt}}
+	var out Availability
+	if err := c.do(ctx, request{method: http.MethodGet, path: idPath("/api/v1/resources/%d/availability", resourceID), query: v}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ResourceBookings — брони ресурса с from по to включительно (YYYY-MM-DD в поясе ресурса)
+func (c *Client) ResourceBookings(ctx context.Context, resourceID uint64, from, to string) ([]Booking, error) {
+	v := url.Values{"from": {from}, "to": {to}}
+	var out []Booking
+	err := c.do(ctx, request{method: http.MethodGet, path: idPath("/api/v1/resources/%d/bookings", resourceID), query: v}, &out)
+	return out, err
+}
+
+// OpeningHours — часы работы ресурса; пустой список — круглосуточно
+func (c *Client) OpeningHours(ctx context.Context, resourceID uint64) ([]OpeningHours, error) {
+	var out []OpeningHours
+	err := c.do(ctx, request{method: http.MethodGet, path: idPath("/api/v1/resources/%d/opening-hours", resourceID)}, &out)
+	return out, err
+}
@@ -0,0 +1,24 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// SSOAuthorize начинает вход через корпоративного провайдера: пользователя нужно отправить
+// на AuthorizationURL, а State запомнить и сверить с тем, что провайдер вернёт вместе с кодом
+func (c *Client) SSOAuthorize(ctx context.Context) (*SSOAuthorization, error) {
+	var out SSOAuthorization
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/auth/oidc/authorize"}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SSOCallback завершает вход через провайдера кодом и state с адреса возврата.
+// Как и Login, при включённой 2FA возвращает *ChallengeError. Учётных данных для
+// повторного входа у клиента нет: по истечении токена вход нужно пройти заново.
+func (c *Client) SSOCallback(ctx context.Context, code, state string) (*AuthResult, error) {
+	body := map[string]string{"code": code, "state": state}
+	return c.signIn(ctx, "/api/v1/auth/oidc/callback", body)
+}
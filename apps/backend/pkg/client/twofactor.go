@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// VerifyTwoFactor завершает вход вторым фактором после *ChallengeError из Login.
+// При обязательном подключении в ответе — коды восстановления (показываются один раз).
+func (c *Client) VerifyTwoFactor(ctx context.Context, in TwoFactorVerifyRequest) (*AuthResult, error) {
+	var out AuthResult
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/v1/auth/2fa/verify", body: in}, &out); err != nil {
+		return nil, err
+	}
+	c.setToken(out.AccessToken)
+	return &out, nil
+}
+
+// TwoFactorStatus — включена ли 2FA, обязательна ли она и сколько осталось кодов восстановления
+func (c *Client) TwoFactorStatus(ctx context.Context) (*TwoFactorStatus, error) {
+	var out TwoFactorStatus
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/auth/2fa", auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EnrollTwoFactor начинает подключение 2FA: секрет и ссылка otpauth:// для аутентификатора
+func (c *Client) EnrollTwoFactor(ctx context.Context) (*TwoFactorEnrollment, error) {
+	var out TwoFactorEnrollment
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/v1/auth/2fa/enroll", auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EnableTwoFactor включает 2FA первым кодом из аутентификатора и возвращает коды восстановления
+func (c *Client) EnableTwoFactor(ctx context.Context, code string) ([]string, error) {
+	return c.recoveryCodes(ctx, "/api/v1/auth/2fa/enable", code)
+}
+
+// RegenerateRecoveryCodes выдаёт новые коды восстановления взамен прежних
+func (c *Client) RegenerateRecoveryCodes(ctx context.Context, code string) ([]string, error) {
+	return c.recoveryCodes(ctx, "/api/v1/auth/2fa/recovery-codes", code)
+}
+
+func (c *Client) recoveryCodes(ctx context.Context, path, code string) ([]string, error) {
+	var out struct {
+		RecoveryCodes []string `json:"recoveryCodes"`
+	}
+	body := map[string]string{"code": code}
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out.RecoveryCodes, nil
+}
+
+// DisableTwoFactor выключает 2FA кодом из аутентификатора или, если code пуст, кодом восстановления
+func (c *Client) DisableTwoFactor(ctx context.Context, code, recoveryCode string) error {
+	body := map[string]string{"code": code, "recoveryCode": recoveryCode}
+	return c.do(ctx, request{method: http.MethodPost, path: "/api/v1/auth/2fa/disable", body: body, auth: true}, nil)
+}
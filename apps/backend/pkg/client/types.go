@@ -0,0 +1,119 @@
+package client
+
+import (
+	"bookinghub-backend/internal/apiv1"
+	"bookinghub-backend/internal/domain"
+)
+
+// Типы ответов API v1. Псевдонимы, а не копии: клиент и сервер не могут разойтись в полях,
+// а пакеты вне модуля получают к ним доступ через этот пакет.
+type (
+	AuthUser            = apiv1.AuthUser
+	AuthResult          = apiv1.Auth
+	Profile             = apiv1.Profile
+	Resource            = apiv1.Resource
+	Image               = apiv1.Image
+	OpeningHours        = apiv1.OpeningHours
+	Address             = apiv1.Address
+	Approval            = apiv1.Approval
+	Availability        = apiv1.Availability
+	Category            = apiv1.Category
+	CategoryNode        = apiv1.CategoryNode
+	CategoryDetails     = apiv1.CategoryDetails
+	CategoryAttribute   = apiv1.CategoryAttribute
+	Booking             = apiv1.Booking
+	BookingCreated      = apiv1.BookingCreated
+	BookingStatusChange = apiv1.BookingStatusChange
+	LoginChallenge      = apiv1.LoginChallenge
+	TwoFactorEnrollment = apiv1.TwoFactorEnrollment
+	TwoFactorStatus     = apiv1.TwoFactorStatus
+	SSOAuthorization    = apiv1.SSOAuthorization
+)
+
+// Перечисления — из домена, значения те же, что в JSON
+type (
+	UserRole      = domain.UserRole
+	ApprovalMode  = domain.ApprovalMode
+	AttributeType = domain.AttributeType
+	BookingStatus = domain.BookingStatus
+)
+
+const (
+	RoleIndividual = domain.RoleIndividual
+	RoleCompany    = domain.RoleCompany
+	RoleAdmin      = domain.RoleAdmin
+
+	BookingPending  = domain.BookingPending
+	BookingApproved = domain.BookingApproved
+	BookingRejected = domain.BookingRejected
+	BookingCanceled = domain.BookingCanceled
+
+	ApprovalManual      = domain.ApprovalManual
+	ApprovalAuto        = domain.ApprovalAuto
+	ApprovalConditional = domain.ApprovalConditional
+)
+
+// RegisterRequest — регистрация; AccountType — INDIVIDUAL (по умолчанию) или COMPANY
+type RegisterRequest struct {
+	Email       string `json:"email"`
+	Name        string `json:"name"`
+	Password    string `json:"password"`
+	AccountType string `json:"accountType,omitempty"`
+}
+
+// TwoFactorVerifyRequest — второй шаг входа: Code из аутентификатора или RecoveryCode
+type TwoFactorVerifyRequest struct {
+	ChallengeToken string `json:"challengeToken"`
+	Code           string `json:"code,omitempty"`
+	RecoveryCode   string `json:"recoveryCode,omitempty"`
+}
+
+// UpdateProfileRequest — изменение профиля; Locale nil — язык не меняется, "" — по Accept-Language
+type UpdateProfileRequest struct {
+	Email  string  `json:"email"`
+	Name   string  `json:"name"`
+	Locale *string `json:"locale,omitempty"`
+}
+
+// ResourceQuery — фильтры GET /api/v1/resources; нулевые поля не передаются
+type ResourceQuery struct {
+	CategoryID uint64
+	// Attributes — фильтры по атрибутам категории: {"lens_mount": {"EF", "RF"}, "capacity.min": {"10"}}
+	Attributes map[string][]string
+	// Near — точка "lat,lng" для поиска в радиусе RadiusKm
+	Near     string
+	RadiusKm float64
+	// BBox — видимая область карты "west,south,east,north"
+	BBox string
+	// Sort — distance или newest
+	Sort string
+}
+
+// ResourceInput — тело создания и изменения ресурса. Необязательные поля nil — значение по
+// умолчанию при создании и «не менять» при изменении.
+type ResourceInput struct {
+	CategoryID          uint64         `json:"categoryId"`
+	OrganizationID      *uint64        `json:"organizationId,omitempty"` // только при создании
+	Title               string         `json:"title"`
+	Description         *string        `json:"description,omitempty"`
+	Location            *string        `json:"location,omitempty"`
+	PricePerHour        int            `json:"pricePerHour"`
+	IsActive            *bool          `json:"isActive,omitempty"` // только при изменении
+	Capacity            *int           `json:"capacity,omitempty"`
+	BufferBeforeMinutes *int           `json:"bufferBeforeMinutes,omitempty"`
+	BufferAfterMinutes  *int           `json:"bufferAfterMinutes,omitempty"`
+	Approval            *Approval      `json:"approval,omitempty"`
+	Timezone            *string        `json:"timezone,omitempty"`
+	Address             *Address       `json:"address,omitempty"`
+	Attributes          map[string]any `json:"attributes,omitempty"`
+}
+
+// BookingRequest — новая бронь: ресурс и интервал или HoldToken удержания.
+// Время без смещения трактуется в поясе ресурса.
+type BookingRequest struct {
+	ResourceID uint64 `json:"resourceId,omitempty"`
+	StartAt    string `json:"startAt,omitempty"`
+	EndAt      string `json:"endAt,omitempty"`
+	Quantity   *int   `json:"quantity,omitempty"`
+	HoldToken  string `json:"holdToken,omitempty"`
+}
@@ -57,8 +57,8 @@ export default function App() {
   // ---- loaders ----
   const loadPublic = async () => {
     const [cats, res] = await Promise.all([
-      apiJson('/api/categories', {}, token),
-      apiJson('/api/resources', {}, token),
+      apiJson('/api/v1/categories', {}, token),
+      apiJson('/api/v1/resources', {}, token),
     ])
 
     setCategories(Array.isArray(cats) ? cats : [])
@@ -71,7 +71,7 @@ export default function App() {
       return
     }
     try {
-      const u = await apiJson('/api/auth/me', {}, t)
+      const u = await apiJson('/api/v1/auth/me', {}, t)
       setMe(u)
     } catch {
       setMe(null)
@@ -82,12 +82,13 @@ export default function App() {
 
   // ---- effects ----
   useEffect(() => {
-    apiText('/api/health')
+    apiText('/api/v1/health')
       .then(() => setServerStatus('ок'))
       .catch(() => setServerStatus('ошибка'))
 
     loadPublic().catch((e) => setError(String(e.message || e)))
     loadMe(token).catch(() => {})
+    finishSSOLogin().catch((e) => setError(String(e.message || e)))
     // eslint-disable-next-line react-hooks/exhaustive-deps
   }, [])
 
@@ -98,7 +99,7 @@ export default function App() {
 
     try {
       const data = await apiJson(
-        '/api/auth/login',
+        '/api/v1/auth/login',
         {
           method: 'POST',
           headers: { 'Content-Type': 'application/json' },
@@ -110,11 +111,14 @@ export default function App() {
         ''
       )
 
-      const t = data.accessToken
+      const auth = data.challengeToken ? await verifyTwoFactor(data) : data
+      if (!auth) return
+
+      const t = auth.accessToken
       saveToken(t)
       setTokenState(t)
 
-      if (data.user) setMe(data.user)
+      if (auth.user) setMe(auth.user)
 
       await loadMe(t)
       await loadPublic(t)
@@ -123,6 +127,83 @@ export default function App() {
     }
   }
 
+  // второй шаг входа: код из приложения-аутентификатора или код восстановления.
+  // Если 2FA обязательна и ещё не подключена, сервер прислал секрет — его нужно добавить в приложение.
+  const verifyTwoFactor = async (challenge) => {
+    const setup = challenge.enrollment
+      ? `Для вашего аккаунта обязательна двухфакторная аутентификация.\nДобавьте в приложение-аутентификатор ключ ${challenge.enrollment.secret}\n(или ссылку ${challenge.enrollment.otpauthUri}).\n\n`
+      : ''
+    const code = window.prompt(`${setup}Введите 6-значный код из приложения${challenge.enrollment ? '' : ' или код восстановления'}:`)
+    if (!code) return null
+
+    const value = code.trim()
+    const isRecovery = !challenge.enrollment && value.replace(/\s/g, '').length > 6
+    const auth = await apiJson(
+      '/api/v1/auth/2fa/verify',
+      {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(
+          isRecovery
+            ? { challengeToken: challenge.challengeToken, recoveryCode: value }
+            : { challengeToken: challenge.challengeToken, code: value }
+        ),
+      },
+      ''
+    )
+    if (auth.recoveryCodes?.length) {
+      window.alert(`Сохраните коды восстановления — они показываются один раз:\n\n${auth.recoveryCodes.join('\n')}`)
+    }
+    return auth
+  }
+
+  // вход через корпоративный аккаунт (OIDC): сервер выдаёт адрес страницы входа провайдера и state.
+  // state запоминаем — провайдер вернёт его вместе с кодом, и чужой код так не подсунуть.
+  const onSSOLogin = async () => {
+    setError('')
+    try {
+      const { authorizationUrl, state } = await apiJson('/api/v1/auth/oidc/authorize', {}, '')
+      sessionStorage.setItem('ssoState', state)
+      window.location.assign(authorizationUrl)
+    } catch (e) {
+      setError(String(e.message || e))
+    }
+  }
+
+  // возврат от провайдера: ?code=...&state=... (или ?error=...) на адресе OIDC_REDIRECT_URL
+  const finishSSOLogin = async () => {
+    const params = new URLSearchParams(window.location.search)
+    const expected = sessionStorage.getItem('ssoState')
+    if (!expected || !params.get('state')) return
+
+    sessionStorage.removeItem('ssoState')
+    window.history.replaceState(null, '', window.location.pathname)
+    if (params.get('error')) throw new Error(`Провайдер отказал во входе: ${params.get('error')}`)
+    if (params.get('state') !== expected) throw new Error('Вход через корпоративный аккаунт не совпал с начатым, попробуйте ещё раз')
+
+    const data = await apiJson(
+      '/api/v1/auth/oidc/callback',
+      {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ code: params.get('code'), state: params.get('state') }),
+      },
+      ''
+    )
+
+    const auth = data.challengeToken ? await verifyTwoFactor(data) : data
+    if (!auth) return
+
+    const t = auth.accessToken
+    saveToken(t)
+    setTokenState(t)
+
+    if (auth.user) setMe(auth.user)
+
+    await loadMe(t)
+    await loadPublic(t)
+  }
+
   const onRegister = async (e) => {
     e.preventDefault()
     setError('')
@@ -140,7 +221,7 @@ export default function App() {
     try {
       // 1) регистрация
       await apiJson(
-        '/api/auth/register',
+        '/api/v1/auth/register',
         {
           method: 'POST',
           headers: { 'Content-Type': 'application/json' },
@@ -151,7 +232,7 @@ export default function App() {
 
       // 2) авто-логин после регистрации
       const data = await apiJson(
-        '/api/auth/login',
+        '/api/v1/auth/login',
         {
           method: 'POST',
           headers: { 'Content-Type': 'application/json' },
@@ -160,11 +241,14 @@ export default function App() {
         ''
       )
 
-      const t = data.accessToken
+      const auth = data.challengeToken ? await verifyTwoFactor(data) : data
+      if (!auth) return
+
+      const t = auth.accessToken
       saveToken(t)
       setTokenState(t)
 
-      if (data.user) setMe(data.user)
+      if (auth.user) setMe(auth.user)
 
       await loadMe(t)
       await loadPublic()
@@ -192,6 +276,7 @@ export default function App() {
         loginForm={loginForm}
         setLoginForm={setLoginForm}
         onLogin={onLogin}
+        onSSOLogin={import.meta.env.VITE_SSO_ENABLED === 'true' ? onSSOLogin : undefined}
         registerForm={registerForm}
         setRegisterForm={setRegisterForm}
         onRegister={onRegister}
@@ -263,7 +348,7 @@ export default function App() {
                   token={token}
                   categories={categories}
                   onCreated={async () => {
-                    const fresh = await apiJson('/api/resources', {}, token)
+                    const fresh = await apiJson('/api/v1/resources', {}, token)
                     setResources(Array.isArray(fresh) ? fresh : [])
                   }}
                 />
@@ -13,12 +13,37 @@ export function saveToken(token) {
   else localStorage.removeItem('accessToken')
 }
 
+// Ошибки API приходят как { error: { code, message, details, requestId } }.
+// В Error кладём человекочитаемое сообщение, код и детали — отдельными полями.
+async function apiError(r) {
+  const text = await r.text()
+  try {
+    const { error } = JSON.parse(text)
+    if (error && error.message) {
+      const details = error.details || []
+      // при ошибках в полях сервер перечисляет их все — показываем вместе с общим сообщением
+      const message = details.length
+        ? `${error.message}: ${details.map((d) => `${d.field} — ${d.message}`).join('; ')}`
+        : error.message
+      const e = new Error(message)
+      e.code = error.code
+      e.details = details
+      e.requestId = error.requestId
+      e.status = r.status
+      return e
+    }
+  } catch {
+    // не JSON — отдаём текст как есть
+  }
+  return new Error(text)
+}
+
 export async function apiText(path, token = '') {
   // Добавляем BASE_URL перед путем
   const r = await fetch(`${BASE_URL}${path}`, {
     headers: token ? { Authorization: `Bearer ${token}` } : undefined,
   })
-  if (!r.ok) throw new Error(await r.text())
+  if (!r.ok) throw await apiError(r)
   return r.text()
 }
 
@@ -28,6 +53,6 @@ export async function apiJson(path, opts = {}, token = '') {
 
   // Добавляем BASE_URL перед путем
   const r = await fetch(`${BASE_URL}${path}`, { ...opts, headers })
-  if (!r.ok) throw new Error(await r.text())
+  if (!r.ok) throw await apiError(r)
   return r.json()
 }
\ No newline at end of file
@@ -10,6 +10,7 @@ export default function AuthPage({
   loginForm,
   setLoginForm,
   onLogin,
+  onSSOLogin, // вход через корпоративный аккаунт; без него кнопки нет
 
   registerForm,
   setRegisterForm,
@@ -85,6 +86,12 @@ export default function AuthPage({
                 Войти
               </button>
 
+              {onSSOLogin ? (
+                <button className="btn btn-secondary" type="button" onClick={onSSOLogin}>
+                  Войти через корпоративный аккаунт
+                </button>
+              ) : null}
+
               <div className="auth-hint">
                 <div className="hint-title">Тестовые аккаунты</div>
                 <div className="hint-list">
@@ -14,7 +14,7 @@ export default function AdminCategoriesPage({ token, me }) {
     setError('')
     setOk('')
     try {
-      const cats = await apiJson('/api/categories', {}, token)
+      const cats = await apiJson('/api/v1/categories', {}, token)
       setItems(Array.isArray(cats) ? cats : [])
     } catch (e) {
       setError(String(e.message || e))
@@ -36,7 +36,7 @@ export default function AdminCategoriesPage({ token, me }) {
 
     try {
       await apiJson(
-        '/api/categories',
+        '/api/v1/categories',
         {
           method: 'POST',
           headers: { 'Content-Type': 'application/json' },
@@ -61,7 +61,7 @@ export default function AdminCategoriesPage({ token, me }) {
 
     try {
       await apiJson(
-        `/api/categories/${id}`,
+        `/api/v1/categories/${id}`,
         {
           method: 'PATCH',
           headers: { 'Content-Type': 'application/json' },
@@ -88,7 +88,7 @@ export default function AdminCategoriesPage({ token, me }) {
     if (!confirm('Удалить категорию? Если к ней привязаны объявления — сервер не даст удалить.')) return
 
     try {
-      await apiJson(`/api/categories/${id}`, { method: 'DELETE' }, token)
+      await apiJson(`/api/v1/categories/${id}`, { method: 'DELETE' }, token)
       setOk('Категория удалена')
       await load()
     } catch (e) {
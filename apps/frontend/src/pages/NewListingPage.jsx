@@ -45,7 +45,7 @@ export default function NewListingPage({ token, categories, onCreated }) {
 
     try {
       await apiJson(
-        '/api/resources',
+        '/api/v1/resources',
         {
           method: 'POST',
           headers: { 'Content-Type': 'application/json' },
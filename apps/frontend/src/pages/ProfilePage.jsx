@@ -38,7 +38,7 @@ export default function ProfilePage({ token, me, onMeUpdated }) {
 
     try {
       const updated = await apiJson(
-        '/api/auth/me',
+        '/api/v1/auth/me',
         {
           method: 'PATCH',
           headers: { 'Content-Type': 'application/json' },
@@ -65,7 +65,7 @@ export default function ProfilePage({ token, me, onMeUpdated }) {
 
     try {
       await apiJson(
-        '/api/auth/password',
+        '/api/v1/auth/password',
         {
           method: 'POST',
           headers: { 'Content-Type': 'application/json' },
@@ -95,7 +95,7 @@ export default function ProfilePage({ token, me, onMeUpdated }) {
     }
 
     try {
-      await apiJson('/api/auth/me', { method: 'DELETE' }, token)
+      await apiJson('/api/v1/auth/me', { method: 'DELETE' }, token)
       // после удаления просто разлогиниваемся
       saveToken('')
       window.location.reload()
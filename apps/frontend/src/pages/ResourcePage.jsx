@@ -25,7 +25,7 @@ export default function ResourcePage({ id, token, me, resources, onBack, onRefre
       setResourceBookings([])
       return
     }
-    const items = await apiJson(`/api/resources/${id}/bookings?from=${date}&to=${date}`, {}, token)
+    const items = await apiJson(`/api/v1/resources/${id}/bookings?from=${date}&to=${date}`, {}, token)
     setResourceBookings(Array.isArray(items) ? items : [])
   }
 
@@ -41,7 +41,7 @@ export default function ResourcePage({ id, token, me, resources, onBack, onRefre
 
     try {
       await apiJson(
-        '/api/bookings',
+        '/api/v1/bookings',
         {
           method: 'POST',
           headers: { 'Content-Type': 'application/json' },
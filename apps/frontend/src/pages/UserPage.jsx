@@ -13,7 +13,7 @@ export default function UserPage({ token }) {
 
     ;(async () => {
       try {
-        const data = await apiJson(`/api/users/${id}`, {}, token)
+        const data = await apiJson(`/api/v1/users/${id}`, {}, token)
         if (!alive) return
         setU(data)
         setError('') // ✅ очищаем ошибку тут, а не синхронно в начале эффекта
@@ -36,7 +36,7 @@ export default function ProfileMyBookings({ token, resources }) {
   setError('')
   try {
     await apiJson(
-      `/api/bookings/${id}/cancel`,
+      `/api/v1/bookings/${id}/cancel`,
       { method: 'POST' },
       token
     )
@@ -58,7 +58,7 @@ export default function ProfileMyBookings({ token, resources }) {
     ;(async () => {
       setError('')
       try {
-        const items = await apiJson('/api/bookings/my', {}, token)
+        const items = await apiJson('/api/v1/bookings/my', {}, token)
         if (!alive) return
         setMyBookings(Array.isArray(items) ? items : [])
       } catch {
@@ -93,7 +93,7 @@ export default function ProfileMyBookings({ token, resources }) {
         const results = await Promise.all(
           missing.map(async (uid) => {
             try {
-              const u = await apiJson(`/api/users/${uid}`, {}, token)
+              const u = await apiJson(`/api/v1/users/${uid}`, {}, token)
               return [uid, u]
             } catch {
               return [uid, null]
@@ -22,7 +22,7 @@ export default function ProfileMyListings({ token, categories }) {
     ;(async () => {
       setError('')
       try {
-        const res = await apiJson('/api/resources/my', {}, token)
+        const res = await apiJson('/api/v1/resources/my', {}, token)
         if (!alive) return
         setMyResources(Array.isArray(res) ? res : [])
       } catch {
@@ -31,7 +31,7 @@ export default function ProfilePending({ token, resources }) {
   const reload = async () => {
     setError('')
     try {
-      const data = await apiJson('/api/bookings/pending', {}, token)
+      const data = await apiJson('/api/v1/bookings/pending', {}, token)
       setItems(Array.isArray(data) ? data : [])
     } catch (e) {
       setError(String(e.message || e))
@@ -59,7 +59,7 @@ export default function ProfilePending({ token, resources }) {
         const results = await Promise.all(
           missing.map(async (uid) => {
             try {
-              const u = await apiJson(`/api/users/${uid}`, {}, token)
+              const u = await apiJson(`/api/v1/users/${uid}`, {}, token)
               return [uid, u]
             } catch {
               return [uid, null]
@@ -91,7 +91,7 @@ export default function ProfilePending({ token, resources }) {
       const comment = (commentById[bookingId] || '').trim()
 
       await apiJson(
-        `/api/bookings/${bookingId}/status`,
+        `/api/v1/bookings/${bookingId}/status`,
         {
           method: 'PATCH',
           headers: { 'Content-Type': 'application/json' },